@@ -2,20 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	_ "go-shisha-backend/docs" // Swagger docs
 	"go-shisha-backend/internal/handlers"
 	"go-shisha-backend/internal/middleware"
+	"go-shisha-backend/internal/models"
 	"go-shisha-backend/internal/repositories/postgres"
 	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/auth"
 	"go-shisha-backend/pkg/db"
 	"go-shisha-backend/pkg/logging"
+	"go-shisha-backend/pkg/mailer"
+	"go-shisha-backend/pkg/oidc"
 	"go-shisha-backend/pkg/validation"
+	"go-shisha-backend/pkg/webauthn"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -111,6 +120,20 @@ func main() {
 	r.Static("/images", "./public/images")
 	logging.L.Info("static images served", "path", "/images", "url", "http://localhost:8080/images/")
 
+	// トークンの署名鍵（JWT_SIGNING_KEY・JWT_SIGNING_KEY_FILE）
+	// 本番環境以外で未設定の場合は起動ごとに鍵を生成する（再起動すると発行済みのトークンは無効になる）
+	jwtKeys, err := auth.LoadKeySetFromEnv()
+	if errors.Is(err, auth.ErrSigningKeyNotConfigured) && os.Getenv("APP_ENV") != "production" {
+		logging.L.Warn("jwt signing key is not configured, using an ephemeral key")
+		jwtKeys, err = auth.GenerateKeySet()
+	}
+	if err != nil {
+		logging.L.Error("failed to configure jwt signing key", "error", err)
+		return
+	}
+	auth.SetKeySet(jwtKeys)
+	logging.L.Info("jwt signing key configured", "kid", jwtKeys.KeyID())
+
 	// Dependency Injection
 	// DB接続と Repository（GORM）
 	gormDB, err := db.NewDBFromEnv()
@@ -135,15 +158,103 @@ func main() {
 	postRepo := postgres.NewPostRepository(gormDB)
 	userRepo := postgres.NewUserRepository(gormDB)
 	refreshTokenRepo := postgres.NewRefreshTokenRepository(gormDB)
+	tokenRevocationRepo := postgres.NewTokenRevocationRepository(gormDB)
 	flavorRepo := postgres.NewFlavorRepository(gormDB)
 	uploadRepo := postgres.NewUploadRepository(gormDB)
+	userStatsRepo := postgres.NewUserStatsRepository(gormDB)
+	badgeRepo := postgres.NewBadgeRepository(gormDB)
+	collectionRepo := postgres.NewCollectionRepository(gormDB)
+	notificationRepo := postgres.NewNotificationRepository(gormDB)
+	webhookRepo := postgres.NewWebhookRepository(gormDB)
+	outboxRepo := postgres.NewOutboxRepository(gormDB)
+	moderationRepo := postgres.NewModerationRepository(gormDB)
+	userRelationRepo := postgres.NewUserRelationRepository(gormDB)
+	muteRuleRepo := postgres.NewMuteRuleRepository(gormDB)
+	passwordResetRepo := postgres.NewPasswordResetRepository(gormDB)
+	mfaRepo := postgres.NewMFARepository(gormDB)
+	passkeyRepo := postgres.NewPasskeyRepository(gormDB)
+	userIdentityRepo := postgres.NewUserIdentityRepository(gormDB)
+	loginFailureRepo := postgres.NewLoginFailureRepository(gormDB)
+
+	// メール送信（MAILER=smtp で SMTP サーバーから送信し、未設定の場合はログに出力する）
+	mail, err := mailer.NewFromEnv()
+	if err != nil {
+		logging.L.Error("failed to configure mailer", "error", err)
+		return
+	}
+
+	// OpenID Connect のプロバイダー（OIDC_PROVIDERS に列挙し、認可コードはフロントエンドの /auth/oidc/<識別子> で受け取る）
+	oidcConfigs, err := oidc.ConfigsFromEnv(frontendURL + "/auth/oidc")
+	if err != nil {
+		logging.L.Error("failed to configure oidc providers", "error", err)
+		return
+	}
+	oidcProviders := make([]*oidc.Provider, 0, len(oidcConfigs))
+	for _, config := range oidcConfigs {
+		oidcProviders = append(oidcProviders, oidc.NewProvider(config, nil))
+	}
 
 	// Service層
 	userService := services.NewUserService(userRepo, postRepo)
-	postService := services.NewPostService(postRepo, userRepo, flavorRepo, uploadRepo)
-	authService := services.NewAuthService(userRepo, refreshTokenRepo)
+	tokenRevocationStore := services.NewTokenRevocationStore(tokenRevocationRepo)
 	uploadService := services.NewUploadService(uploadRepo, logging.L)
 	flavorService := services.NewFlavorService(flavorRepo)
+	userStatsService := services.NewUserStatsService(userStatsRepo, userRepo)
+	badgeService := services.NewBadgeService(badgeRepo, userStatsRepo, userRepo)
+	collectionService := services.NewCollectionService(collectionRepo, postRepo, userRepo)
+	// 新規投稿・いいね数の変化・通知をストリーム接続に配信する
+	eventBroker := services.NewEventBroker(0)
+	notificationService := services.NewNotificationService(notificationRepo, eventBroker)
+	// 投稿・いいね時にユーザー統計のキャッシュ破棄・通知を行う
+	postService := services.NewPostService(postRepo, userRepo, flavorRepo, uploadRepo, userStatsService, notificationService, eventBroker)
+	// Webhook の配信キューに追加した配信はすぐに送信を促す
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepo, nil)
+	webhookService := services.NewWebhookService(webhookRepo, webhookDispatcher)
+	domainEventBus := services.NewDomainEventBus()
+	outboxDispatcher := services.NewOutboxDispatcher(outboxRepo, domainEventBus)
+	moderationService := services.NewModerationService(moderationRepo, postRepo, userRepo, tokenRevocationStore)
+	userRelationService := services.NewUserRelationService(userRelationRepo, userRepo)
+	muteRuleService := services.NewMuteRuleService(muteRuleRepo, flavorRepo)
+	roleService := services.NewRoleService(userRepo)
+	emailVerificationService := services.NewEmailVerificationService(userRepo, mail, frontendURL+"/verify-email")
+	accountLockoutService := services.NewAccountLockoutService(userRepo, loginFailureRepo, mail, frontendURL+"/unlock-account", services.DefaultLockoutPolicy)
+	// 認証アプリに表示するサービス名
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = "Go Shisha" // デフォルト値
+	}
+	mfaService := services.NewMFAService(userRepo, mfaRepo, mfaIssuer)
+	// 登録したユーザーにメールアドレスの確認用のリンクを送り、2段階認証が有効なユーザーはログイン時にコードの入力を求める
+	// ログインの失敗が続いたアカウントは、IP によらずログインを遅らせ・一時的にロックする
+	authService := services.NewAuthService(userRepo, refreshTokenRepo, tokenRevocationStore, emailVerificationService, mfaService, accountLockoutService)
+	passwordResetService := services.NewPasswordResetService(userRepo, passwordResetRepo, loginFailureRepo, mail, authService, frontendURL+"/password-reset")
+	// パスキーの RP ID・オリジンは既定でフロントエンドの URL に合わせる
+	relyingParty := &webauthn.RelyingParty{
+		ID:      os.Getenv("WEBAUTHN_RP_ID"),
+		Name:    os.Getenv("WEBAUTHN_RP_NAME"),
+		Origins: []string{frontendURL},
+	}
+	if relyingParty.ID == "" {
+		if u, err := url.Parse(frontendURL); err == nil {
+			relyingParty.ID = u.Hostname()
+		}
+	}
+	if relyingParty.Name == "" {
+		relyingParty.Name = mfaIssuer
+	}
+	if origins := os.Getenv("WEBAUTHN_ORIGINS"); origins != "" {
+		relyingParty.Origins = strings.Split(origins, ",")
+	}
+	passkeyService := services.NewPasskeyService(userRepo, passkeyRepo, relyingParty, authService, tokenRevocationStore)
+	oidcService := services.NewOIDCService(userRepo, userIdentityRepo, authService, oidcProviders, tokenRevocationStore)
+	// アウトボックスに書き込まれたドメインイベントの購読者を登録する
+	// 画像ステータスの更新・旧プロフィール画像の削除、Webhook の配信キューへの追加、バッジの獲得判定
+	uploadService.RegisterEventHandlers(domainEventBus)
+	webhookService.RegisterEventHandlers(domainEventBus)
+	badgeService.RegisterEventHandlers(domainEventBus)
+	// 利用停止・トークンの無効化を有効期限内のアクセストークンにもすぐに反映する
+	authMiddleware := middleware.AuthMiddleware(authService)
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(authService)
 
 	// Handler層
 	userHandler := handlers.NewUserHandler(userService)
@@ -151,22 +262,67 @@ func main() {
 	authHandler := handlers.NewAuthHandler(authService)
 	uploadHandler := handlers.NewUploadHandler(uploadService, logging.L)
 	flavorHandler := handlers.NewFlavorHandler(flavorService)
+	userStatsHandler := handlers.NewUserStatsHandler(userStatsService)
+	badgeHandler := handlers.NewBadgeHandler(badgeService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	streamHandler := handlers.NewStreamHandler(eventBroker, authService, postService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	userRelationHandler := handlers.NewUserRelationHandler(userRelationService)
+	muteRuleHandler := handlers.NewMuteRuleHandler(muteRuleService)
+	roleHandler := handlers.NewRoleHandler(roleService)
+	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
+	emailVerificationHandler := handlers.NewEmailVerificationHandler(emailVerificationService)
+	accountLockoutHandler := handlers.NewAccountLockoutHandler(accountLockoutService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	passkeyHandler := handlers.NewPasskeyHandler(passkeyService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+	jwksHandler := handlers.NewJWKSHandler(jwtKeys)
+
+	// REQUIRE_EMAIL_VERIFICATION=true の場合、メールアドレスが未確認のユーザーは投稿・画像のアップロードができない
+	requireVerifiedEmail := func(c *gin.Context) { c.Next() }
+	if os.Getenv("REQUIRE_EMAIL_VERIFICATION") == "true" {
+		requireVerifiedEmail = middleware.RequireVerifiedEmail(emailVerificationService)
+		logging.L.Info("email verification required for posting and uploading")
+	}
+
+	// 管理者が1人もいない場合、BOOTSTRAP_ADMIN_EMAIL のユーザーを最初の管理者にする
+	if email := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); email != "" {
+		if err := roleService.BootstrapAdmin(email); err != nil {
+			logging.L.Error("failed to bootstrap admin", "error", err)
+		}
+	}
 
 	// レート制限ミドルウェア（認証エンドポイント用）
 	// 1分間に5リクエストまで（12秒 × 5 = 60秒）、バースト5リクエスト
 	authRateLimiter := middleware.NewIPRateLimiter(rate.Every(12*time.Second), 5)
 	// 1時間ごとに古いIPエントリをクリーンアップ
+	// バックグラウンド処理は終了時に ctx をキャンセルし、DB接続を閉じる前に終了を待つ（defer は登録と逆順に実行される）
+	var background sync.WaitGroup
+	defer background.Wait()
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	authRateLimiter.CleanupOldIPs(ctx, 1*time.Hour)
 	logging.L.Info("rate limiter initialized", "rate", "5 req/min", "burst", 5)
 
+	// アウトボックスのドメインイベントと Webhook の配信キューをバックグラウンドで処理する（失敗時は再試行する）
+	background.Go(func() { outboxDispatcher.Run(ctx) })
+	background.Go(func() { webhookDispatcher.Run(ctx) })
+	// 有効期限切れのアクセストークンの無効化の記録を定期的に削除する
+	background.Go(func() { tokenRevocationStore.Run(ctx) })
+	// 有効期限切れのパスワードリセットのトークンを定期的に削除する
+	background.Go(func() { passwordResetService.Run(ctx) })
+
 	// Swagger UI
 	// Note: gin-swaggerは/swagger/index.htmlでのアクセスのみサポート
 	// /swagger/でのリダイレクトは未サポート (関連Issue: https://github.com/swaggo/gin-swagger/issues/323)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/swagger/doc.json")))
 	logging.L.Info("swagger UI available", "url", "http://localhost:8080/swagger/index.html")
 
+	// トークンの検証鍵の公開（他のサービスがアクセストークンを検証する）
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	// API routes
 	api := r.Group("/api/v1")
 	{
@@ -184,25 +340,103 @@ func main() {
 			// レート制限を適用（ブルートフォース攻撃対策）
 			auth.POST("/register", middleware.RateLimitMiddleware(authRateLimiter), authHandler.Register)
 			auth.POST("/login", middleware.RateLimitMiddleware(authRateLimiter), authHandler.Login)
+			auth.POST("/login/mfa", middleware.RateLimitMiddleware(authRateLimiter), authHandler.LoginMFA)
 			auth.POST("/refresh", middleware.RateLimitMiddleware(authRateLimiter), authHandler.Refresh)
-			auth.POST("/logout", middleware.AuthMiddleware(), authHandler.Logout)
-			auth.GET("/me", middleware.AuthMiddleware(), authHandler.Me)
+			auth.POST("/logout", authMiddleware, authHandler.Logout)
+			auth.GET("/me", authMiddleware, authHandler.Me)
+			auth.POST("/password", middleware.RateLimitMiddleware(authRateLimiter), authMiddleware, authHandler.ChangePassword)
+			auth.POST("/password-reset/request", middleware.RateLimitMiddleware(authRateLimiter), passwordResetHandler.RequestPasswordReset)
+			auth.POST("/password-reset/confirm", middleware.RateLimitMiddleware(authRateLimiter), passwordResetHandler.ConfirmPasswordReset)
+			auth.POST("/verify-email", middleware.RateLimitMiddleware(authRateLimiter), emailVerificationHandler.VerifyEmail)
+			auth.POST("/verify-email/resend", middleware.RateLimitMiddleware(authRateLimiter), authMiddleware, emailVerificationHandler.ResendVerificationEmail)
+			auth.POST("/unlock", middleware.RateLimitMiddleware(authRateLimiter), accountLockoutHandler.UnlockAccount)
+			auth.GET("/sessions", authMiddleware, authHandler.ListSessions)
+			auth.DELETE("/sessions", authMiddleware, authHandler.RevokeOtherSessions)
+			auth.DELETE("/sessions/:id", authMiddleware, authHandler.RevokeSession)
+			auth.GET("/mfa", authMiddleware, mfaHandler.GetStatus)
+			auth.POST("/mfa/totp", authMiddleware, mfaHandler.BeginTOTPEnrollment)
+			auth.POST("/mfa/totp/confirm", middleware.RateLimitMiddleware(authRateLimiter), authMiddleware, mfaHandler.ConfirmTOTPEnrollment)
+			auth.POST("/mfa/disable", middleware.RateLimitMiddleware(authRateLimiter), authMiddleware, mfaHandler.Disable)
+			auth.POST("/mfa/recovery-codes", middleware.RateLimitMiddleware(authRateLimiter), authMiddleware, mfaHandler.RegenerateRecoveryCodes)
+			auth.POST("/passkeys/registration/options", authMiddleware, passkeyHandler.BeginRegistration)
+			auth.POST("/passkeys/registration", middleware.RateLimitMiddleware(authRateLimiter), authMiddleware, passkeyHandler.FinishRegistration)
+			auth.POST("/passkeys/login/options", middleware.RateLimitMiddleware(authRateLimiter), passkeyHandler.BeginLogin)
+			auth.POST("/passkeys/login", middleware.RateLimitMiddleware(authRateLimiter), passkeyHandler.FinishLogin)
+			auth.GET("/passkeys", authMiddleware, passkeyHandler.List)
+			auth.PATCH("/passkeys/:id", authMiddleware, passkeyHandler.Rename)
+			auth.DELETE("/passkeys/:id", authMiddleware, passkeyHandler.Delete)
+			auth.GET("/oidc/providers", oidcHandler.ListProviders)
+			auth.POST("/oidc/:provider/authorize", middleware.RateLimitMiddleware(authRateLimiter), oidcHandler.Authorize)
+			auth.POST("/oidc/:provider/callback", middleware.RateLimitMiddleware(authRateLimiter), oidcHandler.Callback)
 		}
 
 		// Posts endpoints
-		api.GET("/posts", middleware.OptionalAuthMiddleware(), postHandler.GetAllPosts)
-		api.GET("/posts/:id", middleware.OptionalAuthMiddleware(), postHandler.GetPost)
-		api.POST("/posts", middleware.AuthMiddleware(), postHandler.CreatePost)
-		api.POST("/posts/:id/like", middleware.AuthMiddleware(), postHandler.LikePost)
-		api.POST("/posts/:id/unlike", middleware.AuthMiddleware(), postHandler.UnlikePost)
-		api.DELETE("/posts/:id", middleware.AuthMiddleware(), postHandler.DeletePost)
-		api.PATCH("/posts/:id", middleware.AuthMiddleware(), postHandler.UpdatePost)
+		api.GET("/posts", optionalAuthMiddleware, postHandler.GetAllPosts)
+		api.GET("/posts/:id", optionalAuthMiddleware, postHandler.GetPost)
+		api.POST("/posts", authMiddleware, requireVerifiedEmail, postHandler.CreatePost)
+		api.POST("/posts/:id/like", authMiddleware, postHandler.LikePost)
+		api.POST("/posts/:id/unlike", authMiddleware, postHandler.UnlikePost)
+		api.DELETE("/posts/:id", authMiddleware, postHandler.DeletePost)
+		api.PATCH("/posts/:id", authMiddleware, postHandler.UpdatePost)
+		api.POST("/posts/:id/remix", authMiddleware, requireVerifiedEmail, postHandler.RemixPost)
+		api.POST("/posts/:id/report", authMiddleware, moderationHandler.ReportPost)
 
 		// Users endpoints
 		api.GET("/users", userHandler.GetAllUsers)
 		api.GET("/users/:id", userHandler.GetUser)
-		api.GET("/users/:id/posts", middleware.OptionalAuthMiddleware(), userHandler.GetUserPosts)
-		api.PATCH("/users/me", middleware.AuthMiddleware(), userHandler.UpdateMe)
+		api.GET("/users/:id/posts", optionalAuthMiddleware, userHandler.GetUserPosts)
+		api.GET("/users/:id/stats", userStatsHandler.GetUserStats)
+		api.GET("/users/:id/badges", badgeHandler.GetUserBadges)
+		api.GET("/users/:id/collections", optionalAuthMiddleware, collectionHandler.GetUserCollections)
+		api.POST("/users/:id/report", authMiddleware, moderationHandler.ReportUser)
+		api.POST("/users/:id/block", authMiddleware, userRelationHandler.BlockUser)
+		api.DELETE("/users/:id/block", authMiddleware, userRelationHandler.UnblockUser)
+		api.POST("/users/:id/mute", authMiddleware, userRelationHandler.MuteUser)
+		api.DELETE("/users/:id/mute", authMiddleware, userRelationHandler.UnmuteUser)
+		api.PATCH("/users/me", authMiddleware, userHandler.UpdateMe)
+		api.GET("/users/me/badges/unseen", authMiddleware, badgeHandler.GetMyUnseenBadges)
+		api.POST("/users/me/badges/seen", authMiddleware, badgeHandler.MarkMyBadgesSeen)
+		api.GET("/users/me/blocks", authMiddleware, userRelationHandler.GetMyBlocks)
+		api.GET("/users/me/mutes", authMiddleware, userRelationHandler.GetMyMutes)
+		api.GET("/users/me/mute-rules", authMiddleware, muteRuleHandler.GetMyMuteRules)
+		api.POST("/users/me/mute-rules", authMiddleware, muteRuleHandler.CreateMyMuteRule)
+		api.PUT("/users/me/mute-rules/:id", authMiddleware, muteRuleHandler.UpdateMyMuteRule)
+		api.DELETE("/users/me/mute-rules/:id", authMiddleware, muteRuleHandler.DeleteMyMuteRule)
+		api.GET("/users/me/notifications", authMiddleware, notificationHandler.GetMyNotifications)
+		api.GET("/users/me/notifications/unread-count", authMiddleware, notificationHandler.GetMyUnreadCount)
+		api.POST("/users/me/notifications/read-all", authMiddleware, notificationHandler.MarkAllRead)
+		api.POST("/users/me/notifications/:id/read", authMiddleware, notificationHandler.MarkRead)
+
+		// Collections endpoints
+		api.POST("/collections", authMiddleware, collectionHandler.CreateCollection)
+		api.GET("/collections/:id", optionalAuthMiddleware, collectionHandler.GetCollection)
+		api.PATCH("/collections/:id", authMiddleware, collectionHandler.UpdateCollection)
+		api.DELETE("/collections/:id", authMiddleware, collectionHandler.DeleteCollection)
+		api.POST("/collections/:id/items", authMiddleware, collectionHandler.AddItem)
+		api.DELETE("/collections/:id/items/:post_id", authMiddleware, collectionHandler.RemoveItem)
+		api.PUT("/collections/:id/items/order", authMiddleware, collectionHandler.ReorderItems)
+
+		// Webhooks endpoints (認証必須)
+		api.POST("/webhooks", authMiddleware, webhookHandler.CreateWebhook)
+		api.GET("/webhooks", authMiddleware, webhookHandler.ListWebhooks)
+		api.DELETE("/webhooks/:id", authMiddleware, webhookHandler.DeleteWebhook)
+		api.GET("/webhooks/:id/deliveries", authMiddleware, webhookHandler.GetWebhookDeliveries)
+
+		// Admin endpoints (管理者・モデレーターのみ)
+		// 通報の対応・ロールの変更は、トークン発行後のロールの剥奪を反映するため DB 上の現在のロールで再確認する
+		admin := api.Group("/admin", authMiddleware, middleware.RequireRole(models.RoleModerator, models.RoleAdmin))
+		{
+			admin.GET("/reports", moderationHandler.ListReports)
+			admin.POST("/reports/:id/resolve", middleware.RequireCurrentRole(roleService, models.RoleModerator, models.RoleAdmin), moderationHandler.ResolveReport)
+			admin.POST("/users/:id/suspension", middleware.RequireCurrentRole(roleService, models.RoleModerator, models.RoleAdmin), moderationHandler.SuspendUser)
+			admin.DELETE("/users/:id/suspension", middleware.RequireCurrentRole(roleService, models.RoleModerator, models.RoleAdmin), moderationHandler.UnsuspendUser)
+			admin.DELETE("/users/:id/lock", middleware.RequireCurrentRole(roleService, models.RoleModerator, models.RoleAdmin), accountLockoutHandler.AdminUnlockAccount)
+			admin.GET("/moderation-actions", moderationHandler.ListModerationActions)
+			admin.PUT("/users/:id/role", middleware.RequireCurrentRole(roleService, models.RoleAdmin), roleHandler.UpdateUserRole)
+		}
+
+		// Stream endpoint (Server-Sent Events、認証必須)
+		api.GET("/stream", authMiddleware, streamHandler.Stream)
 
 		// Flavors endpoints
 		api.GET("/flavors", flavorHandler.GetAllFlavors)
@@ -210,8 +444,8 @@ func main() {
 		// Uploads endpoints (認証必須)
 		uploads := api.Group("/uploads")
 		{
-			uploads.POST("/images", middleware.AuthMiddleware(), uploadHandler.UploadImages)
-			uploads.POST("/profile-images", middleware.AuthMiddleware(), uploadHandler.UploadProfileImage)
+			uploads.POST("/images", authMiddleware, requireVerifiedEmail, uploadHandler.UploadImages)
+			uploads.POST("/profile-images", authMiddleware, requireVerifiedEmail, uploadHandler.UploadProfileImage)
 		}
 	}
 
@@ -220,6 +454,8 @@ func main() {
 		Addr:    ":8080",
 		Handler: r,
 	}
+	// Shutdown 開始時にストリーム接続を終了させ、接続がアイドルになるのを待ち続けないようにする
+	srv.RegisterOnShutdown(eventBroker.Close)
 
 	logging.L.Info("server starting", "addr", srv.Addr)
 	go func() {
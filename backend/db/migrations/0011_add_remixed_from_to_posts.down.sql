@@ -0,0 +1,5 @@
+-- 0011_add_remixed_from_to_posts.down.sql
+-- remixed_from カラムを削除する
+
+DROP INDEX IF EXISTS idx_posts_remixed_from;
+ALTER TABLE posts DROP COLUMN IF EXISTS remixed_from;
@@ -0,0 +1,8 @@
+-- 0011_add_remixed_from_to_posts.up.sql
+-- リミックス元の投稿を参照する remixed_from カラムを追加する
+
+-- 元投稿が物理削除された場合は参照のみ外す（リミックス投稿自体は残す）
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS remixed_from BIGINT REFERENCES posts(id) ON DELETE SET NULL;
+
+-- リミックス数の集計用インデックス
+CREATE INDEX IF NOT EXISTS idx_posts_remixed_from ON posts(remixed_from) WHERE remixed_from IS NOT NULL;
@@ -0,0 +1,3 @@
+-- 0012_add_post_sessions.down.sql
+DROP INDEX IF EXISTS idx_post_sessions_rating;
+DROP TABLE IF EXISTS post_sessions;
@@ -0,0 +1,17 @@
+-- 0012_add_post_sessions.up.sql
+-- 投稿ごとのセッション詳細（ボウル・HMD・炭の数・吸った時間・総合評価）を保持するテーブル
+-- 全項目任意のため、詳細が入力された投稿にのみ行が存在する
+
+CREATE TABLE IF NOT EXISTS post_sessions (
+  post_id          BIGINT PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+  bowl_type        TEXT,                                                   -- ボウルの種類（例: ファンネル）
+  heat_management  TEXT,                                                   -- ヒートマネジメント（例: ロータス、アルミホイル）
+  coal_count       INT CHECK (coal_count >= 1 AND coal_count <= 10),       -- 炭の数
+  duration_minutes INT CHECK (duration_minutes >= 1 AND duration_minutes <= 600), -- セッション時間（分）
+  rating           SMALLINT CHECK (rating >= 1 AND rating <= 5),           -- 総合評価（1〜5）
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- タイムラインの評価フィルタ用インデックス
+CREATE INDEX IF NOT EXISTS idx_post_sessions_rating ON post_sessions(rating);
@@ -0,0 +1,5 @@
+-- 0013_add_category_to_flavors.down.sql
+-- フレーバーのカテゴリを削除する
+
+DROP INDEX IF EXISTS idx_flavors_category;
+ALTER TABLE flavors DROP COLUMN IF EXISTS category;
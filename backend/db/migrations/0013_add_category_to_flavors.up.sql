@@ -0,0 +1,11 @@
+-- 0013_add_category_to_flavors.up.sql
+-- フレーバーのカテゴリ（統計のカテゴリ別集計用）を追加する
+
+ALTER TABLE flavors ADD COLUMN IF NOT EXISTS category TEXT;
+
+-- 既存の初期フレーバーにカテゴリを設定する
+UPDATE flavors SET category = 'ミント' WHERE name = 'ミント' AND category IS NULL;
+UPDATE flavors SET category = 'フルーツ' WHERE name IN ('アップル', 'ベリー', 'マンゴー', 'オレンジ', 'グレープ') AND category IS NULL;
+
+-- カテゴリ別集計用インデックス
+CREATE INDEX IF NOT EXISTS idx_flavors_category ON flavors(category);
@@ -0,0 +1,5 @@
+-- 0014_add_user_badges.down.sql
+-- user_badges テーブルを削除する
+
+DROP INDEX IF EXISTS idx_user_badges_unseen;
+DROP TABLE IF EXISTS user_badges;
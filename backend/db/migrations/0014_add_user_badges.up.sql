@@ -0,0 +1,14 @@
+-- 0014_add_user_badges.up.sql
+-- ユーザーが獲得したバッジ（実績）を管理するテーブル
+-- バッジの種類と獲得条件はアプリケーション側のルールで定義する
+
+CREATE TABLE IF NOT EXISTS user_badges (
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  badge_code TEXT NOT NULL,          -- バッジ識別子（例: first_post）
+  awarded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  seen_at    TIMESTAMPTZ,            -- ユーザーが獲得通知を確認した日時（未確認の場合は NULL）
+  PRIMARY KEY (user_id, badge_code)
+);
+
+-- 未確認バッジの取得用インデックス
+CREATE INDEX IF NOT EXISTS idx_user_badges_unseen ON user_badges(user_id) WHERE seen_at IS NULL;
@@ -0,0 +1,5 @@
+-- 0015_add_collections.down.sql
+-- コレクション関連のテーブルを削除する
+
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
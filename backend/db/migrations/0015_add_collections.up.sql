@@ -0,0 +1,28 @@
+-- 0015_add_collections.up.sql
+-- ユーザーが作成する投稿のコレクション（アルバム）と、その収録投稿を管理するテーブル
+
+CREATE TABLE IF NOT EXISTS collections (
+  id          BIGSERIAL PRIMARY KEY,
+  user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name        TEXT NOT NULL,                   -- コレクション名（例: ミント系ベストミックス）
+  description TEXT NOT NULL DEFAULT '',
+  is_public   BOOLEAN NOT NULL DEFAULT TRUE,   -- FALSE の場合は作成者本人のみ閲覧可能
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 自分以外の投稿も収録できる。論理削除された投稿は行を残したまま取得時に除外する
+CREATE TABLE IF NOT EXISTS collection_items (
+  collection_id BIGINT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+  post_id       BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+  position      INT NOT NULL,                  -- コレクション内の並び順（昇順）
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (collection_id, post_id)
+);
+
+-- ユーザーのコレクション一覧取得用インデックス
+CREATE INDEX IF NOT EXISTS idx_collections_user_id ON collections(user_id);
+-- コレクション内の並び順での取得用インデックス
+CREATE INDEX IF NOT EXISTS idx_collection_items_position ON collection_items(collection_id, position);
+-- 投稿の物理削除時の連鎖削除用インデックス
+CREATE INDEX IF NOT EXISTS idx_collection_items_post_id ON collection_items(post_id);
@@ -0,0 +1,8 @@
+-- 0016_add_notifications.down.sql
+-- notifications / notification_actors テーブルを削除する
+
+DROP TABLE IF EXISTS notification_actors;
+DROP INDEX IF EXISTS idx_notifications_unread_group;
+DROP INDEX IF EXISTS idx_notifications_unread;
+DROP INDEX IF EXISTS idx_notifications_user_updated;
+DROP TABLE IF EXISTS notifications;
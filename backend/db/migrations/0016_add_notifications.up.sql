@@ -0,0 +1,30 @@
+-- 0016_add_notifications.up.sql
+-- アプリ内通知を管理するテーブル
+-- 同じ投稿への繰り返しのいいね等は、未読の通知1件にまとめて actor_count を加算する
+
+CREATE TABLE IF NOT EXISTS notifications (
+  id          BIGSERIAL PRIMARY KEY,
+  user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- 通知の受信者
+  type        TEXT NOT NULL,                                           -- 通知の種類（例: post_liked）
+  post_id     BIGINT REFERENCES posts(id) ON DELETE CASCADE,           -- 関連する投稿（投稿に紐づかない通知の場合は NULL）
+  actor_id    BIGINT REFERENCES users(id) ON DELETE SET NULL,          -- 直近に操作を行ったユーザー
+  actor_count INTEGER NOT NULL DEFAULT 1,                              -- まとめられた操作ユーザーの人数
+  read_at     TIMESTAMPTZ,                                             -- 既読にした日時（未読の場合は NULL）
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()                       -- 最後に操作がまとめられた日時（一覧の並び順に使用）
+);
+
+-- まとめられた通知に含まれる操作ユーザー（同じユーザーの重複加算を防ぐ）
+CREATE TABLE IF NOT EXISTS notification_actors (
+  notification_id BIGINT NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+  actor_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (notification_id, actor_id)
+);
+
+-- 通知一覧の取得用インデックス
+CREATE INDEX IF NOT EXISTS idx_notifications_user_updated ON notifications(user_id, updated_at DESC);
+-- 未読件数の取得用インデックス
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+-- 未読のまとめ先通知は (受信者, 種類, 投稿) ごとに1件まで
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_unread_group ON notifications(user_id, type, post_id) WHERE read_at IS NULL;
@@ -0,0 +1,8 @@
+-- 0017_add_webhooks.down.sql
+-- webhook_endpoints / webhook_deliveries テーブルを削除する
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_endpoint;
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP INDEX IF EXISTS idx_webhook_endpoints_user_id;
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -0,0 +1,37 @@
+-- 0017_add_webhooks.up.sql
+-- 外部サービスへ投稿・いいねイベントを通知する Webhook の登録先と配信キューを管理するテーブル
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id         BIGSERIAL PRIMARY KEY,
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- 登録したユーザー
+  url        TEXT NOT NULL,                                           -- 配信先URL（http/https）
+  secret     TEXT NOT NULL,                                           -- HMAC-SHA256 署名の鍵（署名の生成に必要なため平文で保持する）
+  events     TEXT NOT NULL,                                           -- 購読するイベント種別（カンマ区切り。例: post.created,like.added）
+  is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_user_id ON webhook_endpoints(user_id);
+
+-- 配信キュー兼配信ログ（1イベント×1登録先につき1行）
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id              BIGSERIAL PRIMARY KEY,
+  endpoint_id     BIGINT NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+  event_id        TEXT NOT NULL,                   -- イベントの一意なID（受信側の重複排除用）
+  event_type      TEXT NOT NULL,
+  payload         TEXT NOT NULL,                   -- 送信する JSON 本文
+  status          TEXT NOT NULL DEFAULT 'pending', -- pending / succeeded / failed
+  attempts        INTEGER NOT NULL DEFAULT 0,      -- 送信を試みた回数
+  next_attempt_at TIMESTAMPTZ,                     -- 次回の送信予定日時（pending の場合のみ）
+  last_attempt_at TIMESTAMPTZ,
+  response_status INTEGER,                         -- 直近の送信で受け取ったHTTPステータス
+  last_error      TEXT NOT NULL DEFAULT '',        -- 直近の送信失敗の理由
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 送信予定の配信を取り出すためのインデックス
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+-- 配信ログの取得用インデックス
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at DESC);
@@ -0,0 +1,5 @@
+-- 0018_add_outbox_events.down.sql
+-- outbox_events テーブルを削除する
+
+DROP INDEX IF EXISTS idx_outbox_events_due;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,20 @@
+-- 0018_add_outbox_events.up.sql
+-- ドメインイベントのトランザクショナルアウトボックス
+-- 投稿・いいね等の変更と同じトランザクションでイベントを書き込み、ディスパッチャーが購読者へ配送する
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id                 BIGSERIAL PRIMARY KEY,
+  event_type         TEXT NOT NULL,                   -- PostCreated / PostDeleted / LikeAdded / ProfileImageReplaced
+  payload            TEXT NOT NULL,                   -- イベント内容（JSON）
+  status             TEXT NOT NULL DEFAULT 'pending', -- pending / processed / failed
+  attempts           INTEGER NOT NULL DEFAULT 0,      -- 配送を試みた回数
+  completed_handlers TEXT NOT NULL DEFAULT '',        -- 処理に成功した購読者名（カンマ区切り。再試行時はこれ以外の購読者にのみ配送する）
+  next_attempt_at    TIMESTAMPTZ,                     -- 次回の配送予定日時（pending の場合のみ）
+  last_error         TEXT NOT NULL DEFAULT '',        -- 直近の配送失敗の理由
+  processed_at       TIMESTAMPTZ,
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 配送予定のイベントを取り出すためのインデックス
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events(next_attempt_at) WHERE status = 'pending';
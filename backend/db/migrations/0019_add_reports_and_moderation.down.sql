@@ -0,0 +1,11 @@
+-- 0019_add_reports_and_moderation.down.sql
+-- reports / moderation_actions テーブルと、posts.hidden_at / users.suspended_at を削除する
+
+DROP INDEX IF EXISTS idx_moderation_actions_created_at;
+DROP TABLE IF EXISTS moderation_actions;
+DROP INDEX IF EXISTS idx_reports_status;
+DROP INDEX IF EXISTS idx_reports_open_user;
+DROP INDEX IF EXISTS idx_reports_open_post;
+DROP TABLE IF EXISTS reports;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS hidden_at;
@@ -0,0 +1,43 @@
+-- 0019_add_reports_and_moderation.up.sql
+-- 投稿・ユーザーの通報と、管理者によるモデレーション（通報の却下・投稿の非表示・ユーザーの利用停止）を管理する
+
+-- モデレーションで非表示にされた投稿（非表示の投稿は公開の読み取りから除外される）
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+-- モデレーションで利用停止にされたユーザー
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS reports (
+  id             BIGSERIAL PRIMARY KEY,
+  reporter_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- 通報したユーザー
+  target_type    TEXT NOT NULL,                                           -- post / user
+  post_id        BIGINT REFERENCES posts(id) ON DELETE CASCADE,           -- 通報対象の投稿（target_type = post の場合のみ）
+  target_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- 通報対象のユーザー（投稿の通報では投稿者）
+  reason         TEXT NOT NULL,                                           -- 理由コード（spam / harassment / ...）
+  comment        TEXT NOT NULL DEFAULT '',                                -- 補足（任意）
+  status         TEXT NOT NULL DEFAULT 'open',                            -- open / dismissed / actioned
+  resolution     TEXT NOT NULL DEFAULT '',                                -- 対応内容（dismiss / hide_post / suspend_user）
+  resolved_by    BIGINT REFERENCES users(id) ON DELETE SET NULL,          -- 対応した管理者
+  resolved_at    TIMESTAMPTZ,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 同じユーザーが同じ対象を未対応のまま重複して通報できないようにする
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_post ON reports(reporter_id, post_id) WHERE status = 'open' AND target_type = 'post';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_user ON reports(reporter_id, target_user_id) WHERE status = 'open' AND target_type = 'user';
+-- モデレーションキュー（未対応の通報を古い順に取得）用インデックス
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status, created_at);
+
+-- モデレーション操作の記録（すべての操作を1行ずつ残す）
+CREATE TABLE IF NOT EXISTS moderation_actions (
+  id             BIGSERIAL PRIMARY KEY,
+  moderator_id   BIGINT REFERENCES users(id) ON DELETE SET NULL,         -- 操作した管理者
+  action         TEXT NOT NULL,                                          -- dismiss / hide_post / suspend_user
+  report_id      BIGINT REFERENCES reports(id) ON DELETE SET NULL,       -- 対応した通報
+  post_id        BIGINT REFERENCES posts(id) ON DELETE SET NULL,         -- 操作対象の投稿
+  target_user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,         -- 操作対象のユーザー
+  note           TEXT NOT NULL DEFAULT '',                               -- 管理者のメモ
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_created_at ON moderation_actions(created_at DESC);
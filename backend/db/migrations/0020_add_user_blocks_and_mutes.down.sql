@@ -0,0 +1,6 @@
+-- 0020_add_user_blocks_and_mutes.down.sql
+-- user_blocks / user_mutes テーブルを削除する
+
+DROP TABLE IF EXISTS user_mutes;
+DROP INDEX IF EXISTS idx_user_blocks_blocked_id;
+DROP TABLE IF EXISTS user_blocks;
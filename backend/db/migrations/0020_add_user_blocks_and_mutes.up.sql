@@ -0,0 +1,22 @@
+-- 0020_add_user_blocks_and_mutes.up.sql
+-- ユーザーのブロック・ミュートを管理する
+-- ミュートしたユーザーの投稿はタイムラインに表示されず、ブロックしたユーザーは自分の投稿にいいね・リミックスできなくなる
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+  blocker_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- ブロックしたユーザー
+  blocked_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- ブロックされたユーザー
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (blocker_id, blocked_id),
+  CHECK (blocker_id <> blocked_id)
+);
+
+-- いいね時の「投稿者にブロックされているか」の確認用インデックス
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_id ON user_blocks(blocked_id);
+
+CREATE TABLE IF NOT EXISTS user_mutes (
+  muter_id   BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- ミュートしたユーザー
+  muted_id   BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- ミュートされたユーザー
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (muter_id, muted_id),
+  CHECK (muter_id <> muted_id)
+);
@@ -0,0 +1,5 @@
+-- 0021_add_mute_rules.down.sql
+-- mute_rules テーブルを削除する
+
+DROP INDEX IF EXISTS idx_mute_rules_user_id;
+DROP TABLE IF EXISTS mute_rules;
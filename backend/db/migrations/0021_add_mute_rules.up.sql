@@ -0,0 +1,17 @@
+-- 0021_add_mute_rules.up.sql
+-- ユーザーごとのミュートルール（キーワード・フレーバー）を管理する
+-- 有効なルールに一致する投稿は、そのユーザーのタイムライン・検索結果から除外される
+
+CREATE TABLE IF NOT EXISTS mute_rules (
+  id         BIGSERIAL PRIMARY KEY,
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind       TEXT NOT NULL,                                        -- keyword / flavor
+  keyword    TEXT NOT NULL DEFAULT '',                             -- スライドの本文と部分一致させる語（kind = keyword の場合）
+  flavor_id  BIGINT REFERENCES flavors(id) ON DELETE CASCADE,      -- スライドのフレーバー（kind = flavor の場合）
+  expires_at TIMESTAMPTZ,                                          -- 有効期限（NULL の場合は無期限）
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK ((kind = 'keyword' AND keyword <> '' AND flavor_id IS NULL) OR (kind = 'flavor' AND flavor_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_mute_rules_user_id ON mute_rules(user_id);
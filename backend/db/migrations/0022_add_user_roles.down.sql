@@ -0,0 +1,5 @@
+-- 0022_add_user_roles.down.sql
+-- users.role を削除する
+
+DROP INDEX IF EXISTS idx_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
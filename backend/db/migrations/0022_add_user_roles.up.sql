@@ -0,0 +1,9 @@
+-- 0022_add_user_roles.up.sql
+-- ユーザーのロール（user / moderator / admin）を管理する
+-- moderator は通報の対応、admin は通報の対応に加えてロールの変更ができる
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user'
+  CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- 最初の管理者の作成時に管理者の有無を確認するためのインデックス（一般ユーザーは含めない）
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role) WHERE role <> 'user';
@@ -0,0 +1,6 @@
+-- 0023_add_user_suspension_details.down.sql
+-- users.suspension_reason / suspended_until / token_version を削除する
+
+ALTER TABLE users DROP COLUMN IF EXISTS token_version;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_until;
+ALTER TABLE users DROP COLUMN IF EXISTS suspension_reason;
@@ -0,0 +1,9 @@
+-- 0023_add_user_suspension_details.up.sql
+-- 利用停止の理由・終了日時と、発行済みトークンを一括で無効化するためのトークンバージョンを追加する
+
+-- 利用停止の理由（利用停止中のログイン・トークン更新の拒否時に本人へ返す）
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason TEXT NOT NULL DEFAULT '';
+-- 利用停止の終了日時（NULL の場合は解除されるまで無期限）
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMPTZ;
+-- トークンバージョン（アクセストークンに含め、DB の値より古いトークンは拒否する。利用停止時に1つ進める）
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- 0024_add_revoked_access_tokens.down.sql
+-- revoked_access_tokens テーブルを削除する
+
+DROP INDEX IF EXISTS idx_revoked_access_tokens_expires_at;
+DROP TABLE IF EXISTS revoked_access_tokens;
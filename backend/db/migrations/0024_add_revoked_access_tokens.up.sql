@@ -0,0 +1,12 @@
+-- 0024_add_revoked_access_tokens.up.sql
+-- ログアウトなどで有効期限前に無効化したアクセストークンの jti を記録する
+-- 有効期限を過ぎた行は不要になるため、定期的に削除する
+
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+  jti        TEXT PRIMARY KEY,                                     -- アクセストークンの jti クレーム
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL,                                 -- アクセストークンの有効期限（過ぎたら削除してよい）
+  revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
@@ -0,0 +1,6 @@
+-- 0025_add_refresh_token_families.down.sql
+-- Refresh Token のファミリーとローテーションの記録を削除する
+
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS rotated_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,13 @@
+-- 0025_add_refresh_token_families.up.sql
+-- Refresh Token をリフレッシュのたびにローテーションし、同じログインから続くトークンをファミリーとしてまとめる
+-- ローテーション済みのトークンは再利用の検知のため有効期限まで残し、再利用されたらファミリーごと削除する
+
+-- ファミリーID（ログイン時に発行し、ローテーションしても引き継ぐ）
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id TEXT NOT NULL DEFAULT '';
+-- ローテーションした日時（NULL の場合は現在有効なトークン）
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+-- 既存のトークンはそれぞれ独立したファミリーとして扱う
+UPDATE refresh_tokens SET family_id = 'legacy-' || id::text WHERE family_id = '';
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
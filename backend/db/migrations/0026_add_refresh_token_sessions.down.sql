@@ -0,0 +1,6 @@
+-- 0026_add_refresh_token_sessions.down.sql
+-- Refresh Token の端末の情報を削除する
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_started_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS user_agent;
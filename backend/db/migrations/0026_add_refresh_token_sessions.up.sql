@@ -0,0 +1,11 @@
+-- 0026_add_refresh_token_sessions.up.sql
+-- Refresh Token のファミリーをログイン中の端末（セッション）として一覧・個別にログアウトできるよう、端末の情報を記録する
+
+-- 最後にログイン・トークン更新を行った端末の User-Agent と IP アドレス
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip_address TEXT NOT NULL DEFAULT '';
+-- セッションを開始した（ログインした）日時（ローテーションしても引き継ぐ）
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMPTZ;
+UPDATE refresh_tokens SET session_started_at = created_at WHERE session_started_at IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN session_started_at SET DEFAULT NOW();
+ALTER TABLE refresh_tokens ALTER COLUMN session_started_at SET NOT NULL;
@@ -0,0 +1,6 @@
+-- 0027_add_password_reset_tokens.down.sql
+-- password_reset_tokens テーブルを削除する
+
+DROP INDEX IF EXISTS idx_password_reset_tokens_expires_at;
+DROP INDEX IF EXISTS idx_password_reset_tokens_user_id;
+DROP TABLE IF EXISTS password_reset_tokens;
@@ -0,0 +1,15 @@
+-- 0027_add_password_reset_tokens.up.sql
+-- パスワードリセットのトークンを記録する（refresh_tokens と同じく SHA256 のハッシュだけを保存する）
+-- トークンは1回だけ使え、有効期限を過ぎた行は定期的に削除する
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+  id         BIGSERIAL PRIMARY KEY,
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash TEXT NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at    TIMESTAMPTZ,                                          -- パスワードのリセットに使った日時（使用済みのトークンは使えない）
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_expires_at ON password_reset_tokens(expires_at);
@@ -0,0 +1,4 @@
+-- 0028_add_users_email_verified_at.down.sql
+-- メールアドレスの確認日時を削除する
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,7 @@
+-- 0028_add_users_email_verified_at.up.sql
+-- メールアドレスの確認日時を追加する（NULL の場合は未確認）
+-- 既存のユーザーは確認済みとして扱い、確認を必須にしても投稿できなくならないようにする
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+UPDATE users SET email_verified_at = NOW() WHERE email_verified_at IS NULL;
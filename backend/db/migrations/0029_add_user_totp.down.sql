@@ -0,0 +1,5 @@
+-- 0029_add_user_totp.down.sql
+-- 2段階認証のテーブルを削除する
+
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
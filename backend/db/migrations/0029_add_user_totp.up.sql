@@ -0,0 +1,20 @@
+-- 0029_add_user_totp.up.sql
+-- TOTP による2段階認証の設定とリカバリーコードを記録する
+
+CREATE TABLE IF NOT EXISTS user_totp (
+  user_id           BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  secret            TEXT NOT NULL,                                 -- Base32 のシークレット
+  enabled_at        TIMESTAMPTZ,                                   -- 最初のコードで確認した日時（NULL の場合は登録中で、ログインには使わない）
+  last_used_counter BIGINT NOT NULL DEFAULT 0,                     -- 最後に受け付けたコードの時間ステップ（同じコードの再利用を防ぐ）
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- リカバリーコードは refresh_tokens と同じく SHA256 のハッシュだけを保存し、1回だけ使える
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+  id         BIGSERIAL PRIMARY KEY,
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash  TEXT NOT NULL,
+  used_at    TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, code_hash)
+);
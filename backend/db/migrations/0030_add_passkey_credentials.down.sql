@@ -0,0 +1,4 @@
+-- 0030_add_passkey_credentials.down.sql
+-- パスキーのテーブルを削除する
+
+DROP TABLE IF EXISTS passkey_credentials;
@@ -0,0 +1,16 @@
+-- 0030_add_passkey_credentials.up.sql
+-- パスワードの代わりにログインに使う WebAuthn のクレデンシャル（パスキー）を記録する
+
+CREATE TABLE IF NOT EXISTS passkey_credentials (
+  id            BIGSERIAL PRIMARY KEY,
+  user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  credential_id BYTEA NOT NULL UNIQUE,                        -- 認証器が発行したクレデンシャルID
+  public_key    BYTEA NOT NULL,                               -- COSE_Key 形式の公開鍵
+  sign_count    BIGINT NOT NULL DEFAULT 0,                    -- 最後に受け付けた署名カウンター（複製された認証器の検出に使う）
+  transports    TEXT NOT NULL DEFAULT '',                     -- 認証器の接続方法（カンマ区切り、例: internal,hybrid）
+  name          TEXT NOT NULL DEFAULT '',                     -- ユーザーが付けた名前
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_used_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_passkey_credentials_user_id ON passkey_credentials(user_id);
@@ -0,0 +1,4 @@
+-- 0031_add_user_identities.down.sql
+-- プロバイダーのアカウントとの紐付けのテーブルを削除する
+
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,14 @@
+-- 0031_add_user_identities.up.sql
+-- OpenID Connect のプロバイダー（Google など）のアカウントとユーザーの紐付けを記録する
+
+CREATE TABLE IF NOT EXISTS user_identities (
+  id         BIGSERIAL PRIMARY KEY,
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider   TEXT NOT NULL,                         -- プロバイダーの識別子（OIDC_PROVIDERS の値）
+  subject    TEXT NOT NULL,                         -- プロバイダーのユーザーID（ID トークンの sub）
+  email      TEXT NOT NULL DEFAULT '',              -- 紐付けた時点のプロバイダーのメールアドレス
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
@@ -0,0 +1,4 @@
+-- 0032_add_login_failures.down.sql
+-- ログインの失敗の記録のテーブルを削除する
+
+DROP TABLE IF EXISTS login_failures;
@@ -0,0 +1,10 @@
+-- 0032_add_login_failures.up.sql
+-- アカウントごとのログインの失敗の回数とロックを記録する（IP ごとの制限をすり抜ける分散した総当たりへの対策）
+
+CREATE TABLE IF NOT EXISTS login_failures (
+  user_id        BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  failed_count    INTEGER NOT NULL DEFAULT 0,   -- 連続したログインの試行の回数（パスワードの検証前に数え、ログインに成功すると記録ごと削除する）
+  last_failed_at  TIMESTAMPTZ NOT NULL,         -- 最後に試行した日時（古くなったら数え直す）
+  next_attempt_at TIMESTAMPTZ NOT NULL,         -- 次の試行を受け付ける日時（失敗が続くと延びる待ち時間）
+  locked_until    TIMESTAMPTZ                   -- ロックの終了日時（NULL の場合はロックしていない）
+);
@@ -0,0 +1,5 @@
+-- 0033_fix_notifications_unread_group_index.down.sql
+-- 未読のまとめ先通知の一意インデックスを元に戻す
+
+DROP INDEX IF EXISTS idx_notifications_unread_group;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_unread_group ON notifications(user_id, type, post_id) WHERE read_at IS NULL;
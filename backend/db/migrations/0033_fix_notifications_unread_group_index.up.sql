@@ -0,0 +1,9 @@
+-- 0033_fix_notifications_unread_group_index.up.sql
+-- 未読のまとめ先通知の一意インデックスを、まとめる種類の通知だけに限定する
+-- まとめない種類の通知は同じ投稿に未読が複数あってよく、投稿に紐づかない通知（post_id が NULL）も NULL 同士が区別されないよう 0 に置き換えてまとめる
+-- まとめる種類の通知を追加する場合は、type IN (...) にも追加する（services.notificationTypes の grouped）
+
+DROP INDEX IF EXISTS idx_notifications_unread_group;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_unread_group
+  ON notifications(user_id, type, COALESCE(post_id, 0))
+  WHERE read_at IS NULL AND type IN ('post_liked');
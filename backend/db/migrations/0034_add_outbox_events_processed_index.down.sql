@@ -0,0 +1,4 @@
+-- 0034_add_outbox_events_processed_index.down.sql
+-- 処理済みのイベントの削除用インデックスを削除する
+
+DROP INDEX IF EXISTS idx_outbox_events_processed;
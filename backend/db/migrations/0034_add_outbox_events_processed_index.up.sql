@@ -0,0 +1,4 @@
+-- 0034_add_outbox_events_processed_index.up.sql
+-- 保持期間を過ぎた処理済みのイベントを削除するためのインデックス（services.OutboxDispatcher の Cleanup）
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed ON outbox_events(processed_at) WHERE status = 'processed';
@@ -0,0 +1,9 @@
+-- 0035_add_notifications_grouped.down.sql
+-- 未読のまとめ先通知の一意インデックスを種類の一覧による条件に戻し、grouped カラムを削除する
+
+DROP INDEX IF EXISTS idx_notifications_unread_group;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_unread_group
+  ON notifications(user_id, type, COALESCE(post_id, 0))
+  WHERE read_at IS NULL AND type IN ('post_liked');
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS grouped;
@@ -0,0 +1,13 @@
+-- 0035_add_notifications_grouped.up.sql
+-- まとめる種類の通知かどうかを通知ごとに記録し、未読のまとめ先通知の一意インデックスを種類の一覧に依存しないようにする
+-- grouped は作成時にサービス層の定義（services.notificationTypes の grouped）から設定するため、まとめる種類を追加してもマイグレーションは不要
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS grouped BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- 既存の通知は、このマイグレーションの時点でまとめる種類（いいね）をまとめ先とする
+UPDATE notifications SET grouped = TRUE WHERE type = 'post_liked';
+
+DROP INDEX IF EXISTS idx_notifications_unread_group;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_unread_group
+  ON notifications(user_id, type, COALESCE(post_id, 0))
+  WHERE read_at IS NULL AND grouped;
@@ -18,9 +18,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "メールアドレスとパスワードでログインし、JWT（Cookie）を発行する",
+        "/admin/moderation-actions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理者・モデレーターによる通報の対応と、管理者によるロールの変更（change_role）を新しい順に取得します",
                 "consumes": [
                     "application/json"
                 ],
@@ -28,39 +33,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "moderation"
                 ],
-                "summary": "ログイン",
+                "summary": "モデレーション操作の記録取得（管理者・モデレーター）",
                 "parameters": [
                     {
-                        "description": "ログイン情報",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.LoginInput"
-                        }
+                        "type": "integer",
+                        "description": "取得件数（1〜100、省略時は20）",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "読み飛ばす件数",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ログイン成功",
+                        "description": "操作の記録",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ModerationActionsResponse"
                         }
                     },
                     "400": {
-                        "description": "バリデーションエラー",
+                        "description": "無効なパラメータ",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
                     "401": {
-                        "description": "認証失敗",
+                        "description": "認証エラー",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
+                    "403": {
+                        "description": "管理者・モデレーターではありません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
                     "500": {
                         "description": "サーバーエラー",
                         "schema": {
@@ -70,107 +84,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
+        "/admin/reports": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cookieを削除し、Refresh Tokenを無効化する",
+                "description": "通報を古い順に取得します。status を省略した場合は未対応の通報を返します",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "moderation"
                 ],
-                "summary": "ログアウト",
-                "responses": {
-                    "200": {
-                        "description": "ログアウト成功",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                "summary": "モデレーションキュー取得（管理者・モデレーター）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "状態（open / dismissed / actioned、省略時は open）",
+                        "name": "status",
+                        "in": "query"
                     },
-                    "401": {
-                        "description": "認証失敗",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "取得件数（1〜100、省略時は20）",
+                        "name": "limit",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "サーバーエラー",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/me": {
-            "get": {
-                "security": [
                     {
-                        "BearerAuth": []
+                        "type": "integer",
+                        "description": "読み飛ばす件数",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
-                "description": "認証されたユーザーの情報を取得する",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "現在のユーザー情報取得",
                 "responses": {
                     "200": {
-                        "description": "ユーザー情報",
+                        "description": "通報一覧",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ReportsResponse"
                         }
                     },
-                    "401": {
-                        "description": "認証失敗",
+                    "400": {
+                        "description": "無効なパラメータ",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
-                    "500": {
-                        "description": "サーバーエラー",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/refresh": {
-            "post": {
-                "description": "Refresh Tokenを使って新しいAccess Tokenを発行する",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "アクセストークンのリフレッシュ",
-                "responses": {
-                    "200": {
-                        "description": "リフレッシュ成功",
+                    "401": {
+                        "description": "認証エラー",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
-                    "401": {
-                        "description": "認証失敗",
+                    "403": {
+                        "description": "管理者・モデレーターではありません",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
                         }
                     },
                     "500": {
@@ -182,9 +156,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
+        "/admin/reports/{id}/resolve": {
             "post": {
-                "description": "新しいユーザーを登録する",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "通報を却下（dismiss）するか、投稿の非表示（hide_post）・ユーザーの利用停止（suspend_user）で対応します\nhide_post・suspend_user では同じ対象への未対応の通報もまとめて対応済みになり、操作はモデレーションの記録に残ります\nsuspend_user では suspend_until で終了日時を指定でき（省略時は無期限）、note が利用停止の理由になります（省略時は通報の理由）\nロールはトークンではなく現在のロールで確認するため、ロールを外されたユーザーはすぐに対応できなくなります",
                 "consumes": [
                     "application/json"
                 ],
@@ -192,98 +171,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "moderation"
                 ],
-                "summary": "ユーザー登録",
+                "summary": "通報の対応（管理者・モデレーター）",
                 "parameters": [
                     {
-                        "description": "ユーザー登録情報",
+                        "type": "integer",
+                        "description": "通報ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "対応内容",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreateUserInput"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ResolveReportInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "登録成功",
+                    "200": {
+                        "description": "対応した通報",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Report"
                         }
                     },
                     "400": {
-                        "description": "バリデーションエラー",
+                        "description": "バリデーションエラー（対象に適用できない操作・過去の終了日時・管理者の利用停止を含む）",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
-                    "409": {
-                        "description": "メールアドレス重複",
+                    "401": {
+                        "description": "認証エラー",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
-                    "500": {
-                        "description": "サーバーエラー",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
-                        }
-                    }
-                }
-            }
-        },
-        "/flavors": {
-            "get": {
-                "description": "全てのフレーバーの一覧を取得します",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "flavors"
-                ],
-                "summary": "フレーバー一覧取得",
-                "responses": {
-                    "200": {
-                        "description": "フレーバー一覧",
+                    "403": {
+                        "description": "管理者・モデレーターではありません",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/go-shisha-backend_internal_models.Flavor"
-                            }
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
                         }
                     },
-                    "500": {
-                        "description": "サーバーエラー",
+                    "404": {
+                        "description": "通報が見つかりません",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
                         }
-                    }
-                }
-            }
-        },
-        "/posts": {
-            "get": {
-                "description": "全ての投稿の一覧を取得します（総数付き）。認証済みの場合、各投稿のいいね状態（is_liked）を含みます",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "posts"
-                ],
-                "summary": "投稿一覧取得",
-                "responses": {
-                    "200": {
-                        "description": "投稿一覧と総数",
+                    },
+                    "409": {
+                        "description": "対応済みの通報",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.PostsResponse"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
                         }
                     },
                     "500": {
@@ -293,14 +236,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/users/{id}/lock": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "新しい投稿を作成します（認証必須）。注意: slides内のflavor_idが無効な場合、そのスライドはFlavorなしで作成されます（エラーにはなりません）",
+                "description": "ログインの失敗が続いてロックされたアカウントのロックを解除し、失敗の回数も数え直す",
                 "consumes": [
                     "application/json"
                 ],
@@ -308,29 +253,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "moderation"
                 ],
-                "summary": "投稿作成",
+                "summary": "アカウントのロック解除（管理者・モデレーター）",
                 "parameters": [
                     {
-                        "description": "投稿情報",
-                        "name": "post",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreatePostInput"
-                        }
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "作成された投稿",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
-                        }
+                    "204": {
+                        "description": "ロックを解除しました"
                     },
                     "400": {
-                        "description": "バリデーションエラー",
+                        "description": "無効なパラメータ",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
@@ -342,17 +282,23 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "権限エラー",
+                        "description": "管理者・モデレーターではありません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
                         }
                     },
                     "404": {
-                        "description": "リソースが見つからない",
+                        "description": "ユーザーが見つかりません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
                         }
                     },
+                    "409": {
+                        "description": "ロックされていません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
                     "500": {
                         "description": "サーバーエラー",
                         "schema": {
@@ -362,43 +308,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{id}": {
-            "get": {
-                "description": "指定されたIDの投稿情報を取得します。認証済みの場合、いいね状態（is_liked）を含みます",
-                "consumes": [
-                    "application/json"
+        "/admin/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
-                "produces": [
+                "description": "ユーザーのロールを user / moderator / admin のいずれかに変更します。変更はモデレーションの記録に残ります\n変更後のロールは対象ユーザーの次回のトークン再発行から反映されます。自分自身のロールは変更できません",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "moderation"
                 ],
-                "summary": "投稿詳細取得",
+                "summary": "ユーザーのロール変更（管理者）",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "投稿ID",
+                        "description": "ユーザーID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "変更後のロール",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UpdateRoleInput"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "投稿情報",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
-                        }
+                    "204": {
+                        "description": "変更しました"
                     },
                     "400": {
-                        "description": "無効な投稿ID",
+                        "description": "バリデーションエラー（自分自身のロールの変更を含む）",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "管理者ではありません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
                     "404": {
-                        "description": "投稿が見つかりません",
+                        "description": "ユーザーが見つかりません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
                         }
@@ -410,14 +376,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/users/{id}/suspension": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "指定された投稿を論理削除します（認証必須・投稿所有者のみ）",
+                "description": "通報によらずユーザーを利用停止にします。until を省略すると無期限になり、利用停止中に再度実行すると理由と終了日時を上書きします\n利用停止中のユーザーはログイン・トークン更新ができず、発行済みのアクセストークン・リフレッシュトークンもすぐに無効になります\n管理者は利用停止にできません",
                 "consumes": [
                     "application/json"
                 ],
@@ -425,24 +393,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "moderation"
                 ],
-                "summary": "投稿削除",
+                "summary": "ユーザーの利用停止（管理者・モデレーター）",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "投稿ID",
+                        "description": "ユーザーID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "利用停止の理由と終了日時",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.SuspendUserInput"
+                        }
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "削除成功"
+                        "description": "利用停止しました"
                     },
                     "400": {
-                        "description": "無効な投稿ID",
+                        "description": "バリデーションエラー（自分自身・管理者・過去の終了日時を含む）",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
@@ -454,13 +431,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "権限エラー（投稿所有者でない）",
+                        "description": "管理者・モデレーターではありません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
                         }
                     },
                     "404": {
-                        "description": "投稿が見つかりません",
+                        "description": "ユーザーが見つかりません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
                         }
@@ -473,13 +450,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "指定された投稿のスライドのテキスト・フレーバーを更新します（認証必須・投稿所有者のみ）。各スライドは id で更新対象を指定します。全上書き型のため、全スライドの全フィールドを送信してください。text を省略すると空文字、flavor_id を省略または null で渡すとフレーバーが解除されます。",
+                "description": "ユーザーの利用停止を解除します。利用停止前に発行されたトークンは無効のままのため、再度ログインが必要です",
                 "consumes": [
                     "application/json"
                 ],
@@ -487,36 +464,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "moderation"
                 ],
-                "summary": "投稿編集",
+                "summary": "ユーザーの利用停止解除（管理者・モデレーター）",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "投稿ID",
+                        "description": "ユーザーID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "更新内容",
-                        "name": "post",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UpdatePostInput"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "更新された投稿",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
-                        }
+                    "204": {
+                        "description": "利用停止を解除しました"
                     },
                     "400": {
-                        "description": "バリデーションエラー",
+                        "description": "無効なパラメータ",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
@@ -528,17 +493,23 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "権限エラー（投稿所有者でない）",
+                        "description": "管理者・モデレーターではありません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
                         }
                     },
                     "404": {
-                        "description": "投稿が見つかりません",
+                        "description": "ユーザーが見つかりません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
                         }
                     },
+                    "409": {
+                        "description": "利用停止されていません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
                     "500": {
                         "description": "サーバーエラー",
                         "schema": {
@@ -548,14 +519,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{id}/like": {
+        "/auth/login": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "指定された投稿にいいねを追加します（認証必須）",
+                "description": "メールアドレスとパスワードでログインし、JWT（Cookie）を発行する\n2段階認証が有効なユーザーの場合は Cookie を発行せずに 202 とチャレンジトークンを返す。チャレンジトークンと2段階認証のコードを POST /auth/login/mfa に送るとログインが完了する\n失敗が続いたアカウントは、失敗のたびに次のログインまでの待ち時間が延び、さらに続くと一時的にロックされる。待ち時間中・ロック中はパスワードを検証せずに 429 を返し、Retry-After ヘッダーで次のログインを受け付けるまでの秒数を返す（ロックしたときは解除用のリンクをメールで送る）",
                 "consumes": [
                     "application/json"
                 ],
@@ -563,47 +529,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "auth"
                 ],
-                "summary": "投稿にいいね",
+                "summary": "ログイン",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "投稿ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ログイン情報",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.LoginInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "いいねが追加された投稿",
+                        "description": "ログイン成功",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "2段階認証のコードが必要",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MFAChallengeResponse"
                         }
                     },
                     "400": {
-                        "description": "無効な投稿ID",
+                        "description": "バリデーションエラー",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
                     "401": {
-                        "description": "認証エラー",
+                        "description": "認証失敗",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
-                    "404": {
-                        "description": "投稿が見つかりません",
+                    "403": {
+                        "description": "利用停止中",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AccountSuspendedError"
                         }
                     },
-                    "409": {
-                        "description": "既にいいね済み",
+                    "429": {
+                        "description": "ログインの失敗が続いたため待ち時間中・ロック中",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.TooManyRequestsError"
                         }
                     },
                     "500": {
@@ -615,14 +589,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{id}/unlike": {
+        "/auth/login/mfa": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "指定された投稿のいいねを取り消します（認証必須）",
+                "description": "ログインで返したチャレンジトークンと、認証アプリに表示された6桁のコードまたはリカバリーコードでログインを完了し、JWT（Cookie）を発行する\nチャレンジトークンは5分間有効で、一度しか使えない（コードが誤っている場合もログインからやり直す）。コードが誤っている場合は invalid_mfa_code、チャレンジトークンが無効・使用済みの場合は unauthorized を返す",
                 "consumes": [
                     "application/json"
                 ],
@@ -630,47 +599,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "auth"
                 ],
-                "summary": "投稿のいいねを取り消す",
+                "summary": "2段階認証のコードによるログイン",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "投稿ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "チャレンジトークンと2段階認証のコード",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MFALoginInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "いいねが取り消された投稿",
+                        "description": "ログイン成功",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
                         }
                     },
                     "400": {
-                        "description": "無効な投稿ID",
+                        "description": "バリデーションエラー",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
                     "401": {
-                        "description": "認証エラー",
+                        "description": "認証失敗",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
-                    "404": {
-                        "description": "投稿が見つかりません",
+                    "403": {
+                        "description": "利用停止中",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AccountSuspendedError"
                         }
                     },
-                    "409": {
-                        "description": "いいねしていない投稿",
+                    "429": {
+                        "description": "ログインの失敗が続いたため待ち時間中・ロック中",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.TooManyRequestsError"
                         }
                     },
                     "500": {
@@ -682,58 +653,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/uploads/images": {
+        "/auth/logout": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "複数の画像を一括アップロードし、保存されたURLの配列を返却します",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Cookieを削除し、この端末のセッションのRefresh Tokenとログアウトに使ったAccess Tokenを無効化する（他の端末はログアウトしない）",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "uploads"
-                ],
-                "summary": "画像アップロード",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "アップロードする画像（複数可）",
-                        "name": "images",
-                        "in": "formData",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "ログアウト",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UploadImagesResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "バリデーションエラー",
+                        "description": "ログアウト成功",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
-                        "description": "認証エラー",
+                        "description": "認証失敗",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
-                    "413": {
-                        "description": "ファイルサイズ超過",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.PayloadTooLargeError"
-                        }
-                    },
                     "500": {
                         "description": "サーバーエラー",
                         "schema": {
@@ -743,58 +693,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/uploads/profile-images": {
-            "post": {
+        "/auth/me": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "プロフィール画像を1枚アップロードし、保存されたURLを返却します",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "認証されたユーザーの情報を取得する",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "uploads"
-                ],
-                "summary": "プロフィール画像アップロード",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "アップロードするプロフィール画像（1枚のみ）",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "現在のユーザー情報取得",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UploadProfileImageResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "バリデーションエラー",
+                        "description": "ユーザー情報",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
                         }
                     },
                     "401": {
-                        "description": "認証エラー",
+                        "description": "認証失敗",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
-                    "413": {
-                        "description": "ファイルサイズ超過",
-                        "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.PayloadTooLargeError"
-                        }
-                    },
                     "500": {
                         "description": "サーバーエラー",
                         "schema": {
@@ -804,24 +730,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/auth/mfa": {
             "get": {
-                "description": "全てのユーザーの一覧を取得します（総数付き）",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "ログイン中のユーザーの2段階認証が有効かどうかと、未使用のリカバリーコードの数を取得する",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "ユーザー一覧取得",
+                "summary": "2段階認証の状態",
                 "responses": {
                     "200": {
-                        "description": "ユーザー一覧と総数",
+                        "description": "2段階認証の状態",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UsersResponse"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MFAStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
                     "500": {
@@ -833,14 +767,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
-            "patch": {
+        "/auth/mfa/disable": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "認証ユーザー自身のプロフィール情報を更新します",
+                "description": "パスワードを確認して2段階認証を無効にし、リカバリーコードを削除する",
                 "consumes": [
                     "application/json"
                 ],
@@ -848,25 +782,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "自分のプロフィール更新",
+                "summary": "2段階認証の無効化",
                 "parameters": [
                     {
-                        "description": "更新するフィールド（省略可能）",
-                        "name": "request",
+                        "description": "現在のパスワード",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.UpdateUserInput"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MFAPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "無効にしました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "パスワードが誤っています",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "409": {
+                        "description": "2段階認証が有効でない",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "パスワードを確認してリカバリーコードを発行し直す。それまでのリカバリーコードは使えなくなる",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "リカバリーコードの再発行",
+                "parameters": [
+                    {
+                        "description": "現在のパスワード",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MFAPasswordInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新後のユーザー情報",
+                        "description": "リカバリーコード",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.RecoveryCodesResponse"
                         }
                     },
                     "400": {
@@ -881,6 +881,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
                         }
                     },
+                    "403": {
+                        "description": "パスワードが誤っています",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "409": {
+                        "description": "2段階認証が有効でない",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
                     "500": {
                         "description": "サーバーエラー",
                         "schema": {
@@ -890,9 +902,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
-                "description": "指定されたIDのユーザー情報を取得します",
+        "/auth/mfa/totp": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証アプリに登録するシークレットと、QR コードにする otpauth URI を発行する\nPOST /auth/mfa/totp/confirm で認証アプリに表示されたコードを確認するまで2段階認証は有効にならない。登録をやり直すと以前のシークレットは使えなくなる",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "TOTP の登録開始",
+                "responses": {
+                    "200": {
+                        "description": "シークレットと otpauth URI",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.TOTPEnrollment"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "409": {
+                        "description": "2段階認証が有効",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/totp/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証アプリに表示されたコードを確認して2段階認証を有効にし、リカバリーコードを発行する\nリカバリーコードはこのレスポンスでのみ返すため、ユーザーに保管してもらう",
                 "consumes": [
                     "application/json"
                 ],
@@ -900,35 +960,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "ユーザー詳細取得",
+                "summary": "TOTP の登録確認",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ユーザーID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "認証アプリに表示されたコード",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConfirmTOTPInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ユーザー情報",
+                        "description": "リカバリーコード",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.RecoveryCodesResponse"
                         }
                     },
                     "400": {
-                        "description": "無効なユーザーID",
+                        "description": "バリデーションエラー・コードが誤っている",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
-                    "404": {
-                        "description": "ユーザーが見つかりません",
+                    "401": {
+                        "description": "認証エラー",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "409": {
+                        "description": "登録を開始していない・2段階認証が有効",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
                         }
                     },
                     "500": {
@@ -940,9 +1008,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/posts": {
+        "/auth/oidc/providers": {
             "get": {
-                "description": "指定されたユーザーの全ての投稿を取得します（総数付き）",
+                "description": "OpenID Connect でログインできるプロバイダーの識別子を返す（環境変数 OIDC_PROVIDERS の順）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "ログインに使えるプロバイダーの一覧",
+                "responses": {
+                    "200": {
+                        "description": "プロバイダーの一覧",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.OIDCProvidersResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/authorize": {
+            "post": {
+                "description": "プロバイダーの認可 URL を発行し、state・nonce・PKCE の code_verifier を含む state トークンを Cookie（10分有効）に設定する\nブラウザーを authorization_url に移動し、プロバイダーから戻った URL の code と state を POST /auth/oidc/{provider}/callback に送る",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "プロバイダーによるログインの開始",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "プロバイダーの識別子",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "認可 URL",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.OIDCAuthorizationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "プロバイダーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー・プロバイダーに接続できない",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "post": {
+                "description": "プロバイダーが返した認可コードを交換して ID トークンを検証し、パスワードでのログインと同じ JWT（Cookie）を発行する\nプロバイダーのアカウントが紐付いていない場合は確認済みのメールアドレスで既存のユーザーに紐付け、該当するユーザーがいない場合は作成する\nプロバイダーのメールアドレスが未確認の場合は oidc_email_unverified、同じメールアドレスの既存のユーザーがメールアドレスを確認していない場合は email_not_verified を返す\n2段階認証が有効なユーザーの場合は Cookie を発行せず、パスワードでのログインと同じくチャレンジトークンを返す",
                 "consumes": [
                     "application/json"
                 ],
@@ -950,33 +1079,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "ユーザーの投稿一覧取得",
+                "summary": "プロバイダーによるログインの完了",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ユーザーID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "プロバイダーの識別子",
+                        "name": "provider",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "プロバイダーが返した code と state",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.OIDCCallbackInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "投稿一覧と総数",
+                        "description": "ログイン成功",
                         "schema": {
-                            "$ref": "#/definitions/go-shisha-backend_internal_models.PostsResponse"
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "2段階認証のコードが必要（POST /auth/login/mfa でログインを完了する）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MFAChallengeResponse"
                         }
                     },
                     "400": {
-                        "description": "無効なユーザーID",
+                        "description": "バリデーションエラー",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
                         }
                     },
+                    "401": {
+                        "description": "認証失敗（state の不一致・期限切れを含む）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "メールアドレスが未確認",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
                     "404": {
-                        "description": "ユーザーが見つかりません",
+                        "description": "プロバイダーが見つかりません",
                         "schema": {
                             "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
                         }
@@ -989,119 +1145,5185 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "go-shisha-backend_internal_models.AuthResponse": {
-            "type": "object",
-            "properties": {
-                "user": {
-                    "$ref": "#/definitions/go-shisha-backend_internal_models.User"
-                }
-            }
         },
-        "go-shisha-backend_internal_models.ConflictError": {
-            "description": "リソース競合エラーレスポンス（メール重複・いいね重複・いいね未実施など）",
-            "type": "object",
-            "required": [
-                "error"
-            ],
-            "properties": {
-                "error": {
-                    "description": "エラー種別の識別子",
-                    "type": "string",
-                    "enum": [
-                        "email_already_exists",
-                        "already_liked",
-                        "not_liked"
+        "/auth/passkeys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中のユーザーが登録したパスキーを登録順に返す",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "登録済みのパスキー一覧",
+                "responses": {
+                    "200": {
+                        "description": "パスキー一覧",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PasskeysResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/login": {
+            "post": {
+                "description": "navigator.credentials.get() の結果を検証してログインし、パスワードでのログインと同じ JWT（Cookie）を発行する\nパスキーは本人確認（生体認証・PIN）を含むため、2段階認証のコードは求めない",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスキーによるログイン",
+                "parameters": [
+                    {
+                        "description": "セッショントークンと認証器のレスポンス",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PasskeyLoginInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ログイン成功",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証失敗",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "利用停止中",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AccountSuspendedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/login/options": {
+            "post": {
+                "description": "navigator.credentials.get() に渡すオプションと、ログインの完了に使うセッショントークン（5分間有効）を発行する\n端末に保存されたパスキーから選んでもらうため、メールアドレスは不要",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスキーによるログインの開始",
+                "responses": {
+                    "200": {
+                        "description": "ログインのオプション",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PasskeyLoginOptionsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/registration": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "navigator.credentials.create() の結果を検証してパスキーを登録する\nセッショントークンが無効・使用済みの場合は invalid_token、認証器のレスポンスを検証できない場合は invalid_passkey を返す",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスキーの登録",
+                "parameters": [
+                    {
+                        "description": "セッショントークンと認証器のレスポンス",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.RegisterPasskeyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "登録したパスキー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Passkey"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー・検証に失敗",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "409": {
+                        "description": "登録済みのパスキー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/registration/options": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "navigator.credentials.create() に渡すオプションと、登録の完了に使うセッショントークン（5分間有効）を発行する",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスキーの登録開始",
+                "responses": {
+                    "200": {
+                        "description": "登録のオプション",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PasskeyRegistrationOptionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "パスキーを削除する。削除したパスキーではログインできなくなる（認証器に残ったパスキーは端末側で削除してもらう）",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスキーの削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "パスキーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除しました"
+                    },
+                    "400": {
+                        "description": "無効なパスキーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "パスキーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスキーの名前の変更",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "パスキーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新しい名前",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.RenamePasskeyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "変更しました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "パスキーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "現在のパスワードを確認してパスワードを変更する。新しいパスワードは12文字以上\n変更後はすべての端末（セッション）をログアウトさせ、発行済みのAccess Tokenもすべて無効にする。この端末には新しいトークンの Cookie を設定する",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスワード変更",
+                "parameters": [
+                    {
+                        "description": "現在のパスワードと新しいパスワード",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ChangePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "パスワードを変更しました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "現在のパスワードが誤っています",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/confirm": {
+            "post": {
+                "description": "メールで送ったリンクのトークンを使ってパスワードを再設定する。新しいパスワードは12文字以上\n再設定後はすべての端末をログアウトさせ、ログインの失敗によるアカウントのロックも解除する",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスワードリセットの確定",
+                "parameters": [
+                    {
+                        "description": "トークンと新しいパスワード",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PasswordResetConfirmInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "パスワードを再設定しました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー・トークンが無効",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/request": {
+            "post": {
+                "description": "登録したメールアドレスにパスワードを再設定するためのリンクを送る。リンクは30分間、1回だけ使える\n登録されているメールアドレスかどうかに関わらず同じレスポンスを返す",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "パスワードリセットの申請",
+                "parameters": [
+                    {
+                        "description": "メールアドレス",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PasswordResetRequestInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "受け付けました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Refresh Tokenを使って新しいAccess Tokenを発行し、Refresh Tokenをローテーションする（新しいトークンは Cookie に設定）\nローテーション済みのRefresh Tokenが再度使われた場合は漏洩とみなし、同じログインから続くRefresh Tokenをすべて無効化する",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "アクセストークンのリフレッシュ",
+                "responses": {
+                    "200": {
+                        "description": "リフレッシュ成功",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "認証失敗",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "利用停止中",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AccountSuspendedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "新しいユーザーを登録する",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "ユーザー登録",
+                "parameters": [
+                    {
+                        "description": "ユーザー登録情報",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "登録成功",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "409": {
+                        "description": "メールアドレス重複",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中の端末（セッション）を最後に使われた順に取得する。このリクエストを送った端末は current が true になる",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "ログイン中の端末一覧",
+                "responses": {
+                    "200": {
+                        "description": "ログイン中の端末",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.SessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "この端末以外のすべての端末（セッション）をログアウトさせる。それらの端末のAccess Tokenも有効期限を待たずに無効になる",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "他の端末からすべてログアウト",
+                "responses": {
+                    "204": {
+                        "description": "ログアウトさせました"
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定した端末（セッション）をログアウトさせる。その端末のAccess Tokenも有効期限を待たずに無効になる\nこの端末のセッションを指定した場合は Cookie も削除する",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "端末のログアウト",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "セッションID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "ログアウトさせました"
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "セッションが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/unlock": {
+            "post": {
+                "description": "ログインの失敗が続いてロックしたときにメールで送ったリンクのトークンで、アカウントのロックを解除する。リンクはロックが終わるまで有効\nロックが既に終わっている・解除済みの場合も 204 を返す",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "アカウントのロック解除",
+                "parameters": [
+                    {
+                        "description": "ロック解除用トークン",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnlockAccountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "ロックを解除しました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー・トークンが無効",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "登録時・再送時にメールで送ったリンクのトークンでメールアドレスを確認済みにする。リンクは24時間有効\nログインしていない端末からも確認できる",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "メールアドレスの確認",
+                "parameters": [
+                    {
+                        "description": "確認用トークン",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.VerifyEmailInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "確認しました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー・トークンが無効",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email/resend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中のユーザーのメールアドレスに確認用のリンクを送り直す",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "確認メールの再送",
+                "responses": {
+                    "202": {
+                        "description": "受け付けました"
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "409": {
+                        "description": "確認済み",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーのコレクションを作成します。is_public を省略した場合は公開コレクションになります",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "コレクション作成",
+                "parameters": [
+                    {
+                        "description": "コレクション情報",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreateCollectionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成されたコレクション",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}": {
+            "get": {
+                "description": "指定されたコレクションと収録投稿を並び順で取得します。非公開コレクションは作成者のみ取得できます。削除された投稿は含まれません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "コレクション詳細取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "コレクションID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "コレクションと収録投稿",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なコレクションID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "コレクションが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "コレクションを削除します（作成者のみ）。収録されていた投稿自体は削除されません",
+                "tags": [
+                    "collections"
+                ],
+                "summary": "コレクション削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "コレクションID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "400": {
+                        "description": "無効なコレクションID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "作成者ではありません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "コレクションが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "コレクションの名前・説明・公開設定を更新します（作成者のみ）。省略した項目は変更されません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "コレクション更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "コレクションID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新するフィールド（省略可能）",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UpdateCollectionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新後のコレクション",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "作成者ではありません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "コレクションが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/items": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "コレクションの末尾に投稿を追加します（作成者のみ）。他ユーザーの投稿も追加できます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "コレクションに投稿を追加",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "コレクションID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "追加する投稿",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.AddCollectionItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "追加成功"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "作成者ではありません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "コレクションまたは投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "すでに収録されています",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/items/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定した投稿IDの順序でコレクション内の並び順を更新します（作成者のみ）。現在収録されている投稿IDをすべて過不足なく指定してください",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "コレクション内の並び替え",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "コレクションID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新しい並び順",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ReorderCollectionItemsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "並び替え成功"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー（収録投稿と一致しない場合を含む）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "作成者ではありません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "コレクションが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/items/{post_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "コレクションから投稿を取り除きます（作成者のみ）",
+                "tags": [
+                    "collections"
+                ],
+                "summary": "コレクションから投稿を削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "コレクションID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "投稿ID",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "400": {
+                        "description": "無効なID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "作成者ではありません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "コレクションが見つからない、または投稿が収録されていません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/flavors": {
+            "get": {
+                "description": "全てのフレーバーの一覧を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "flavors"
+                ],
+                "summary": "フレーバー一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "フレーバー一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/go-shisha-backend_internal_models.Flavor"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts": {
+            "get": {
+                "description": "全ての投稿の一覧を取得します（総数付き）。認証済みの場合、各投稿のいいね状態（is_liked）を含みます\nセッション詳細による絞り込み条件を指定した場合、セッション詳細が未入力の投稿は含まれません\n認証済みの場合、ミュート・ブロックしたユーザーと、自分をブロックしたユーザーの投稿は含まれません\n認証済みの場合、有効なミュートルール（キーワード・フレーバー）に一致するスライドを含む投稿も含まれません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿一覧取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "総合評価の下限（1〜5）",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ボウルの種類（完全一致）",
+                        "name": "bowl_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ヒートマネジメント（完全一致）",
+                        "name": "heat_management",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "投稿一覧と総数",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PostsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "絞り込み条件が不正",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "新しい投稿を作成します（認証必須）。注意: slides内のflavor_idが無効な場合、そのスライドはFlavorなしで作成されます（エラーにはなりません）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿作成",
+                "parameters": [
+                    {
+                        "description": "投稿情報",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreatePostInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成された投稿",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "権限エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "リソースが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}": {
+            "get": {
+                "description": "指定されたIDの投稿情報を取得します。認証済みの場合、いいね状態（is_liked）を含みます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿詳細取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "投稿ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "投稿情報",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "無効な投稿ID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定された投稿を論理削除します（認証必須・投稿所有者のみ）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "投稿ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "400": {
+                        "description": "無効な投稿ID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "権限エラー（投稿所有者でない）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定された投稿のスライドのテキスト・フレーバーを更新します（認証必須・投稿所有者のみ）。各スライドは id で更新対象を指定します。全上書き型のため、全スライドの全フィールドを送信してください。text を省略すると空文字、flavor_id を省略または null で渡すとフレーバーが解除されます。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿編集",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "投稿ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新内容",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UpdatePostInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新された投稿",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "権限エラー（投稿所有者でない）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/like": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定された投稿にいいねを追加します（認証必須）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿にいいね",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "投稿ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "いいねが追加された投稿",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "無効な投稿ID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "投稿者にブロックされています",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "既にいいね済み",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/remix": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定された投稿のスライドのテキスト・フレーバーをコピーした新しい投稿を作成します（認証必須）。画像はコピーされません。作成された投稿の remixed_from には元投稿のIDが入ります",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿をリミックス",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "リミックス元の投稿ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成されたリミックス投稿",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "無効な投稿ID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "403": {
+                        "description": "リミックス元の投稿者にブロックされています",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ForbiddenError"
+                        }
+                    },
+                    "404": {
+                        "description": "投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/report": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "投稿を理由コードとともに通報します。通報はモデレーションキューに追加され、管理者が対応します\n同じ投稿への未対応の通報がすでにある場合は 409 を返します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "投稿の通報",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "投稿ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "通報の理由",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreateReportInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成した通報",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー（自分の投稿の通報を含む）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "通報済み",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/unlike": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定された投稿のいいねを取り消します（認証必須）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "投稿のいいねを取り消す",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "投稿ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "いいねが取り消された投稿",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "無効な投稿ID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "投稿が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "いいねしていない投稿",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events で新規投稿（post_created）・いいね数の変化（like_count_changed）・自分宛ての通知（notification）を配信します\n投稿のイベントはタイムラインと同じく、ブロック・ミュートの関係にあるユーザーの投稿とミュートルールに一致する投稿のものを配信しません\n接続維持のため一定間隔でコメント行（\": heartbeat\"）を送信します。切断された場合はクライアント側で再接続してください\nアクセストークンの有効期限が切れた場合、ログアウト・利用停止などで無効になった場合はサーバー側から切断します",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "リアルタイムイベントストリーム",
+                "responses": {
+                    "200": {
+                        "description": "イベントストリーム",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "503": {
+                        "description": "サーバー停止中",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "複数の画像を一括アップロードし、保存されたURLの配列を返却します",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "画像アップロード",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "アップロードする画像（複数可）",
+                        "name": "images",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UploadImagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "413": {
+                        "description": "ファイルサイズ超過",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PayloadTooLargeError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/profile-images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "プロフィール画像を1枚アップロードし、保存されたURLを返却します",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "プロフィール画像アップロード",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "アップロードするプロフィール画像（1枚のみ）",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UploadProfileImageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "413": {
+                        "description": "ファイルサイズ超過",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PayloadTooLargeError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "全てのユーザーの一覧を取得します（総数付き）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザー一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "ユーザー一覧と総数",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UsersResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザー自身のプロフィール情報を更新します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "自分のプロフィール更新",
+                "parameters": [
+                    {
+                        "description": "更新するフィールド（省略可能）",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UpdateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新後のユーザー情報",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/badges/seen": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーの指定されたバッジを確認済みにし、以降の未確認バッジ取得では返されないようにします\n獲得していない・確認済みのバッジは無視されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "badges"
+                ],
+                "summary": "バッジを確認済みにする",
+                "parameters": [
+                    {
+                        "description": "確認済みにするバッジ",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MarkBadgesSeenInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "確認済みにしました"
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/badges/unseen": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーが新たに獲得し、まだ確認していないバッジを取得します\n取得しただけでは確認済みにならないため、表示した後に POST /users/me/badges/seen で確認済みにしてください",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "badges"
+                ],
+                "summary": "未確認バッジ取得",
+                "responses": {
+                    "200": {
+                        "description": "未確認バッジ一覧と総数",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UserBadgesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/blocks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーがブロックしたユーザーを新しい順に取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ブロックしたユーザー一覧",
+                "responses": {
+                    "200": {
+                        "description": "ブロックしたユーザー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.RelatedUsersResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mute-rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーのキーワード・フレーバーのミュートルールを新しい順に取得します（期限切れのルールを含みます）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ミュートルール一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "ミュートルール一覧",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MuteRulesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "キーワード（スライド本文の部分一致、大文字小文字を区別しない）またはフレーバーのミュートルールを作成します\n有効なルールに一致するスライドを含む投稿は、投稿一覧・ユーザーの投稿一覧に表示されなくなります。expires_at を指定すると、その日時以降は適用されません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ミュートルール作成",
+                "parameters": [
+                    {
+                        "description": "ミュートルール",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MuteRuleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成したミュートルール",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MuteRule"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー（内容の不正、存在しないフレーバー、過去の有効期限、登録数の上限）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mute-rules/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーのミュートルールの内容を置き換えます（有効期限を省略すると無期限になります）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ミュートルール更新",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ミュートルールID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ミュートルール",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MuteRuleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新後のミュートルール",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.MuteRule"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "ミュートルールが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーのミュートルールを削除します",
+                "tags": [
+                    "users"
+                ],
+                "summary": "ミュートルール削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ミュートルールID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除しました"
+                    },
+                    "400": {
+                        "description": "無効なミュートルールID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "ミュートルールが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mutes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーがミュートしたユーザーを新しい順に取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ミュートしたユーザー一覧",
+                "responses": {
+                    "200": {
+                        "description": "ミュートしたユーザー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.RelatedUsersResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーの通知を新しい順に取得します（総数・未読数付き）\n同じ投稿への未読のいいねは1件にまとめられ、others_count にまとめられた他のユーザー数が入ります",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "通知一覧取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "取得件数（1〜100、省略時は20）",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "読み飛ばす件数",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "通知一覧",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotificationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なページングパラメータ",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/read-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーの未読通知をすべて既読にします",
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "すべての通知を既読にする",
+                "responses": {
+                    "204": {
+                        "description": "既読にしました"
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーの未読通知数を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "未読通知数取得",
+                "responses": {
+                    "200": {
+                        "description": "未読通知数",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnreadNotificationCountResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーの通知を既読にします。既読の通知を指定した場合も成功します",
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "通知を既読にする",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "通知ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "既読にしました"
+                    },
+                    "400": {
+                        "description": "無効な通知ID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "通知が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "指定されたIDのユーザー情報を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザー詳細取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ユーザー情報",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なユーザーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/badges": {
+            "get": {
+                "description": "指定されたユーザーが獲得したバッジを獲得日時の古い順に取得します（総数付き）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "badges"
+                ],
+                "summary": "ユーザーのバッジ一覧取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "バッジ一覧と総数",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UserBadgesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なユーザーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/block": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定したユーザーをブロックします。ブロックしたユーザーの投稿はタイムラインに表示されず、相手は自分の投稿にいいね・リミックスできなくなります",
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザーをブロック",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "ブロックしました"
+                    },
+                    "400": {
+                        "description": "無効なユーザーID（自分自身を含む）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "ブロック済み",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定したユーザーのブロックを解除します",
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザーのブロックを解除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "ブロックを解除しました"
+                    },
+                    "400": {
+                        "description": "無効なユーザーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "409": {
+                        "description": "ブロックしていません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/collections": {
+            "get": {
+                "description": "指定されたユーザーのコレクション一覧を取得します（総数付き）。本人の場合のみ非公開コレクションを含みます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "ユーザーのコレクション一覧取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "コレクション一覧と総数",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.CollectionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なユーザーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/mute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定したユーザーをミュートします。ミュートしたユーザーの投稿はタイムラインに表示されなくなります（相手には通知されません）",
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザーをミュート",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "ミュートしました"
+                    },
+                    "400": {
+                        "description": "無効なユーザーID（自分自身を含む）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "ミュート済み",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定したユーザーのミュートを解除します",
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザーのミュートを解除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "ミュートを解除しました"
+                    },
+                    "400": {
+                        "description": "無効なユーザーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "409": {
+                        "description": "ミュートしていません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/posts": {
+            "get": {
+                "description": "指定されたユーザーの全ての投稿を取得します（総数付き）\n認証済みの場合、有効なミュートルール（キーワード・フレーバー）に一致するスライドを含む投稿は含まれません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザーの投稿一覧取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "投稿一覧と総数",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.PostsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なユーザーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/report": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ユーザーを理由コードとともに通報します。通報はモデレーションキューに追加され、管理者が対応します\n同じユーザーへの未対応の通報がすでにある場合は 409 を返します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "ユーザーの通報",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "通報の理由",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreateReportInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成した通報",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー（自分自身の通報を含む）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "409": {
+                        "description": "通報済み",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ConflictError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/stats": {
+            "get": {
+                "description": "指定されたユーザーの月別投稿数、よく使うフレーバー・カテゴリ、使用フレーバー数、受け取ったいいね数の推移、最長連続投稿日数を取得します\n統計は一定時間キャッシュされ、投稿・いいね時に再集計されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "ユーザー統計取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ユーザーID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ユーザー統計",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UserStats"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なユーザーID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "ユーザーが見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーが登録した Webhook を新しい順に取得します（secret は含まれません）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "Webhook 一覧",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.WebhookEndpointsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "自分の投稿の作成・削除、自分の投稿へのいいねのイベントを受け取る配信先URLを登録します（他のユーザーの投稿のイベントは配信されません）\nレスポンスの secret は登録時にのみ返されます。各配信の X-Webhook-Signature ヘッダーは \"sha256=\" + HMAC-SHA256(secret, \"\u003cX-Webhook-Timestamp\u003e.\u003c本文\u003e\") の16進数です\n2xx 以外の応答や接続エラーの場合は指数バックオフで再送されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 登録",
+                "parameters": [
+                    {
+                        "description": "配信先URLと購読するイベント",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.CreateWebhookInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "登録した Webhook（secret を含む）",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "バリデーションエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーが登録した Webhook を配信ログとともに削除します",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 削除",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除しました"
+                    },
+                    "400": {
+                        "description": "無効な Webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "認証ユーザーが登録した Webhook の配信ログを新しい順に取得します（状態・試行回数・直近の応答を含む）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 配信ログ取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "取得件数（1〜100、省略時は20）",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "読み飛ばす件数",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "配信ログ",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.WebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無効なパラメータ",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ValidationError"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.UnauthorizedError"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook が見つかりません",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotFoundError"
+                        }
+                    },
+                    "500": {
+                        "description": "サーバーエラー",
+                        "schema": {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ServerError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "go-shisha-backend_internal_models.AccountSuspendedError": {
+            "description": "利用停止中のユーザーがログイン・トークン更新・認証が必要な操作を行った場合のエラーレスポンス",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "account_suspended"
+                    ],
+                    "example": "account_suspended"
+                },
+                "reason": {
+                    "description": "利用停止の理由",
+                    "type": "string",
+                    "example": "スパム行為のため"
+                },
+                "suspended_until": {
+                    "description": "利用停止の終了日時（無期限の場合は含まれない）",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.AddCollectionItemInput": {
+            "type": "object",
+            "required": [
+                "post_id"
+            ],
+            "properties": {
+                "post_id": {
+                    "description": "追加する投稿ID（コレクションの末尾に追加される）",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 10
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "email_verified": {
+                    "description": "メールアドレスを確認済みかどうか",
+                    "type": "boolean",
+                    "example": true
+                },
+                "role": {
+                    "description": "認証ユーザーのロール（user / moderator / admin）",
+                    "type": "string",
+                    "example": "user"
+                },
+                "user": {
+                    "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.CategoryUsage": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "フレーバーカテゴリ",
+                    "type": "string",
+                    "example": "フルーツ"
+                },
+                "count": {
+                    "description": "そのカテゴリのフレーバーを使用した投稿数",
+                    "type": "integer",
+                    "example": 15
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ChangePasswordInput": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "description": "現在のパスワード",
+                    "type": "string"
+                },
+                "new_password": {
+                    "description": "新しいパスワード",
+                    "type": "string",
+                    "minLength": 12
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Collection": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "夏に吸いたいミント系の組み合わせ"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_public": {
+                    "description": "公開コレクションかどうか（非公開の場合は作成者本人のみ閲覧可能）",
+                    "type": "boolean",
+                    "example": true
+                },
+                "item_count": {
+                    "description": "収録されている投稿のうち閲覧者に表示される（削除・非表示・ブロック・ミュートされていない）投稿の数",
+                    "type": "integer",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ミント系ベストミックス"
+                },
+                "posts": {
+                    "description": "収録投稿（並び順）。コレクション詳細の取得時のみ設定される",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.CollectionsResponse": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Collection"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ConfirmTOTPInput": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "認証アプリに表示された6桁のコード",
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ConflictError": {
+            "description": "リソース競合エラーレスポンス（メール重複・いいね重複・いいね未実施・コレクション収録済み・通報済み・対応済みの通報・ブロック/ミュートの重複や未実施・利用停止されていないユーザーの利用停止解除・ロックされていないアカウントのロック解除・確認済みのメールアドレスの確認メールの再送・2段階認証の状態と合わない操作・登録済みのパスキーの登録など）",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "email_already_exists",
+                        "already_liked",
+                        "not_liked",
+                        "already_in_collection",
+                        "already_reported",
+                        "report_already_resolved",
+                        "already_blocked",
+                        "not_blocked",
+                        "already_muted",
+                        "not_muted",
+                        "not_suspended",
+                        "not_locked",
+                        "already_verified",
+                        "mfa_already_enabled",
+                        "mfa_not_enabled",
+                        "passkey_already_registered"
+                    ],
+                    "example": "already_liked"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.CreateCollectionInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "description": "説明（200文字以内）",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "夏に吸いたいミント系の組み合わせ"
+                },
+                "is_public": {
+                    "description": "公開するかどうか（省略時は公開）",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "description": "コレクション名（1〜50文字）",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "ミント系ベストミックス"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.CreatePostInput": {
+            "type": "object",
+            "required": [
+                "slides"
+            ],
+            "properties": {
+                "session": {
+                    "description": "セッション詳細（省略可）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ShishaSessionInput"
+                        }
+                    ]
+                },
+                "slides": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.SlideInput"
+                    }
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.CreateReportInput": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "補足（任意、1000文字以内）",
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "同じ内容の投稿を繰り返しています"
+                },
+                "reason": {
+                    "description": "理由コード（spam / harassment / hate_speech / sexual_content / violence / impersonation / other）",
+                    "type": "string",
+                    "enum": [
+                        "spam",
+                        "harassment",
+                        "hate_speech",
+                        "sexual_content",
+                        "violence",
+                        "impersonation",
+                        "other"
+                    ],
+                    "example": "spam"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.CreateUserInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 12
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.CreateWebhookInput": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "購読するイベントの種類（post.created / post.deleted / like.added）",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "post.created",
+                        "like.added"
+                    ]
+                },
+                "url": {
+                    "description": "配信先URL（http/https）",
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://example.com/webhooks/shisha"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Flavor": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "フレーバーのカテゴリ（未分類の場合は省略）",
+                    "type": "string",
+                    "example": "フルーツ"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.FlavorUsage": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "そのフレーバーを使用した投稿数",
+                    "type": "integer",
+                    "example": 8
+                },
+                "flavor": {
+                    "$ref": "#/definitions/go-shisha-backend_internal_models.Flavor"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ForbiddenError": {
+            "description": "権限がない操作を実行した場合のエラーレスポンス（投稿者にブロックされている場合は blocked、確認のためのパスワードが誤っている場合は incorrect_password、メールアドレスが未確認の場合は email_not_verified、ログインに使ったプロバイダーのメールアドレスが未確認の場合は oidc_email_unverified）",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "forbidden",
+                        "blocked",
+                        "incorrect_password",
+                        "email_not_verified",
+                        "oidc_email_unverified"
+                    ],
+                    "example": "forbidden"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.LoginInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MFAChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MFALoginInput": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "description": "ログインのレスポンスの challenge_token",
+                    "type": "string"
+                },
+                "code": {
+                    "description": "認証アプリに表示された6桁のコード、またはリカバリーコード",
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MFAPasswordInput": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "description": "現在のパスワード",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MFAStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "2段階認証が有効かどうか",
+                    "type": "boolean",
+                    "example": true
+                },
+                "enabled_at": {
+                    "description": "2段階認証を有効にした日時",
+                    "type": "string"
+                },
+                "remaining_recovery_codes": {
+                    "description": "未使用のリカバリーコードの数",
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MarkBadgesSeenInput": {
+            "type": "object",
+            "required": [
+                "codes"
+            ],
+            "properties": {
+                "codes": {
+                    "description": "確認済みにするバッジ識別子（未確認バッジ取得で受け取ったもの）",
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "first_post"
+                    ]
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ModerationAction": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "dismiss / hide_post / suspend_user / unsuspend_user / change_role",
+                    "type": "string",
+                    "example": "hide_post"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "moderator_id": {
+                    "description": "操作した管理者（退会済みの場合は含まれない）",
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "type": "string",
+                    "example": "スパム投稿のため非表示"
+                },
+                "post_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "report_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "target_user_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ModerationActionsResponse": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.ModerationAction"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MonthlyCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "件数",
+                    "type": "integer",
+                    "example": 5
+                },
+                "month": {
+                    "description": "対象月（YYYY-MM）",
+                    "type": "string",
+                    "example": "2025-01"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MuteRule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "有効期限（省略時は無期限。期限を過ぎたルールは適用されない）",
+                    "type": "string"
+                },
+                "flavor_id": {
+                    "description": "ミュートするフレーバーのID（kind が flavor の場合のみ）",
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "keyword": {
+                    "description": "ミュートする語（kind が keyword の場合のみ。大文字・小文字を区別せず部分一致）",
+                    "type": "string",
+                    "example": "ミント"
+                },
+                "kind": {
+                    "description": "keyword / flavor",
+                    "type": "string",
+                    "example": "keyword"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MuteRuleInput": {
+            "type": "object",
+            "required": [
+                "kind"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "有効期限（省略時は無期限、未来の日時のみ指定可能）",
+                    "type": "string",
+                    "example": "2026-12-31T23:59:59+09:00"
+                },
+                "flavor_id": {
+                    "description": "ミュートするフレーバーのID（kind が flavor の場合に必須）",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                },
+                "keyword": {
+                    "description": "ミュートする語（kind が keyword の場合に必須、100文字以内）",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "ミント"
+                },
+                "kind": {
+                    "description": "keyword / flavor",
+                    "type": "string",
+                    "enum": [
+                        "keyword",
+                        "flavor"
+                    ],
+                    "example": "keyword"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.MuteRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.MuteRule"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.NotFoundError": {
+            "description": "リソースが見つからない場合のエラーレスポンス",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "not_found"
+                    ],
+                    "example": "not_found"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Notification": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "直近に操作を行ったユーザー（退会済みの場合は省略）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.NotificationActor"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_read": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "message": {
+                    "description": "表示用メッセージ",
+                    "type": "string",
+                    "example": "シーシャ太郎さんと他4人があなたの投稿にいいねしました"
+                },
+                "others_count": {
+                    "description": "Actor 以外にまとめられた操作ユーザーの人数",
+                    "type": "integer",
+                    "example": 4
+                },
+                "post_id": {
+                    "description": "関連する投稿ID（投稿に紐づかない通知の場合は省略）",
+                    "type": "integer",
+                    "example": 10
+                },
+                "type": {
+                    "type": "string",
+                    "example": "post_liked"
+                },
+                "updated_at": {
+                    "description": "最後に操作がまとめられた日時（一覧はこの日時の新しい順）",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.NotificationActor": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "シーシャ太郎"
+                },
+                "icon_url": {
+                    "type": "string",
+                    "example": "/images/icon.jpg"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.NotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Notification"
+                    }
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "description": "通知の総数",
+                    "type": "integer",
+                    "example": 42
+                },
+                "unread_count": {
+                    "description": "未読の通知数",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.OIDCAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string",
+                    "example": "https://accounts.google.com/o/oauth2/v2/auth?client_id=..."
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.OIDCCallbackInput": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "description": "プロバイダーが返した認可コード",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "プロバイダーが返した state",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.OIDCProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "description": "プロバイダーの識別子（POST /auth/oidc/{provider}/authorize で指定する）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "google",
+                        "line"
+                    ]
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Passkey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "登録した日時",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "パスキーのID（PATCH・DELETE /auth/passkeys/{id} で指定する）",
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "description": "最後にログインに使った日時",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "ユーザーが付けた名前",
+                    "type": "string",
+                    "example": "iPhone"
+                },
+                "transports": {
+                    "description": "認証器の接続方法",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "internal",
+                        "hybrid"
+                    ]
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PasskeyLoginInput": {
+            "type": "object",
+            "required": [
+                "session_token"
+            ],
+            "properties": {
+                "credential": {
+                    "description": "navigator.credentials.get() の結果（PublicKeyCredential.toJSON() の形式）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.AssertionResponse"
+                        }
+                    ]
+                },
+                "session_token": {
+                    "description": "ログイン開始のレスポンスの session_token",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PasskeyLoginOptionsResponse": {
+            "type": "object",
+            "properties": {
+                "public_key": {
+                    "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.RequestOptions"
+                },
+                "session_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PasskeyRegistrationOptionsResponse": {
+            "type": "object",
+            "properties": {
+                "public_key": {
+                    "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.CreationOptions"
+                },
+                "session_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PasskeysResponse": {
+            "type": "object",
+            "properties": {
+                "passkeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Passkey"
+                    }
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PasswordResetConfirmInput": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "description": "新しいパスワード",
+                    "type": "string",
+                    "minLength": 12
+                },
+                "token": {
+                    "description": "メールで送ったリンクに含まれるトークン",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PasswordResetRequestInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "登録したメールアドレス",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PayloadTooLargeError": {
+            "description": "ファイルサイズが上限を超えた場合のエラーレスポンス",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "payload_too_large"
+                    ],
+                    "example": "payload_too_large"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Post": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_liked": {
+                    "type": "boolean"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "remix_count": {
+                    "description": "この投稿をリミックスした（削除されていない）投稿の数",
+                    "type": "integer"
+                },
+                "remixed_from": {
+                    "description": "リミックス元の投稿ID。元投稿が削除された場合は参照が外れて省略される",
+                    "type": "integer",
+                    "example": 1
+                },
+                "session": {
+                    "description": "セッション詳細。未入力の投稿では省略される",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ShishaSession"
+                        }
+                    ]
+                },
+                "slides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Slide"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.PostsResponse": {
+            "type": "object",
+            "properties": {
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.RegisterPasskeyInput": {
+            "type": "object",
+            "required": [
+                "session_token"
+            ],
+            "properties": {
+                "credential": {
+                    "description": "navigator.credentials.create() の結果（PublicKeyCredential.toJSON() の形式）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.RegistrationResponse"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "パスキーの名前（省略した場合は「パスキー」）",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "iPhone"
+                },
+                "session_token": {
+                    "description": "登録開始のレスポンスの session_token",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.RelatedUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "ブロック・ミュートした日時",
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.RelatedUsersResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.RelatedUser"
+                    }
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.RenamePasskeyInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "仕事用の MacBook"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ReorderCollectionItemsInput": {
+            "type": "object",
+            "required": [
+                "post_ids"
+            ],
+            "properties": {
+                "post_ids": {
+                    "description": "新しい並び順の投稿ID。現在収録されている投稿IDをすべて過不足なく指定する",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Report": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": ""
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "post_id": {
+                    "description": "通報対象の投稿ID（target_type が post の場合のみ）",
+                    "type": "integer",
+                    "example": 10
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "spam"
+                },
+                "reporter_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "resolution": {
+                    "description": "対応内容（dismiss / hide_post / suspend_user、未対応の場合は空）",
+                    "type": "string",
+                    "example": ""
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "open / dismissed / actioned",
+                    "type": "string",
+                    "example": "open"
+                },
+                "target_type": {
+                    "description": "post / user",
+                    "type": "string",
+                    "example": "post"
+                },
+                "target_user_id": {
+                    "description": "通報対象のユーザーID（投稿の通報では投稿者）",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ReportsResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Report"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ResolveReportInput": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "description": "対応内容（dismiss / hide_post / suspend_user。hide_post は投稿の通報のみ）",
+                    "type": "string",
+                    "enum": [
+                        "dismiss",
+                        "hide_post",
+                        "suspend_user"
+                    ],
+                    "example": "hide_post"
+                },
+                "note": {
+                    "description": "管理者のメモ（任意、1000文字以内）。suspend_user では利用停止の理由として本人に返す（省略時は通報の理由コード）",
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "スパム投稿のため非表示"
+                },
+                "suspend_until": {
+                    "description": "利用停止の終了日時（suspend_user のみ、省略時は解除されるまで無期限）",
+                    "type": "string",
+                    "example": "2026-12-31T00:00:00Z"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ServerError": {
+            "description": "サーバー内部でエラーが発生した場合のエラーレスポンス",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "internal_server_error"
+                    ],
+                    "example": "internal_server_error"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Session": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "ログインした日時",
+                    "type": "string"
+                },
+                "current": {
+                    "description": "このリクエストを送った端末のセッションか",
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "description": "セッションID（DELETE /auth/sessions/{id} で指定する）",
+                    "type": "string",
+                    "example": "0b6f7c1e-4d2a-4f3b-9c1d-2e5a8b7c6d40"
+                },
+                "ip_address": {
+                    "description": "最後にログイン・トークン更新を行った端末の IP アドレス",
+                    "type": "string",
+                    "example": "203.0.113.10"
+                },
+                "last_used_at": {
+                    "description": "最後にトークンを更新した日時（ログイン後に更新していない場合はログインした日時）",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "最後にログイン・トークン更新を行った端末の User-Agent",
+                    "type": "string",
+                    "example": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.SessionsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.Session"
+                    }
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ShishaSession": {
+            "type": "object",
+            "properties": {
+                "bowl_type": {
+                    "description": "ボウルの種類",
+                    "type": "string",
+                    "example": "ファンネル"
+                },
+                "coal_count": {
+                    "description": "炭の数",
+                    "type": "integer",
+                    "example": 3
+                },
+                "duration_minutes": {
+                    "description": "セッション時間（分）",
+                    "type": "integer",
+                    "example": 60
+                },
+                "heat_management": {
+                    "description": "ヒートマネジメント（HMD・アルミホイル等）",
+                    "type": "string",
+                    "example": "ロータス"
+                },
+                "rating": {
+                    "description": "総合評価（1〜5）",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ShishaSessionInput": {
+            "type": "object",
+            "properties": {
+                "bowl_type": {
+                    "description": "ボウルの種類（50文字以内）",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "ファンネル"
+                },
+                "coal_count": {
+                    "description": "炭の数（1〜10）",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1,
+                    "example": 3
+                },
+                "duration_minutes": {
+                    "description": "セッション時間（分、1〜600）",
+                    "type": "integer",
+                    "maximum": 600,
+                    "minimum": 1,
+                    "example": 60
+                },
+                "heat_management": {
+                    "description": "ヒートマネジメント（50文字以内）",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "ロータス"
+                },
+                "rating": {
+                    "description": "総合評価（1〜5）",
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 4
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.Slide": {
+            "type": "object",
+            "required": [
+                "image_url"
+            ],
+            "properties": {
+                "flavor": {
+                    "$ref": "#/definitions/go-shisha-backend_internal_models.Flavor"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.SlideInput": {
+            "type": "object",
+            "required": [
+                "image_url"
+            ],
+            "properties": {
+                "flavor_id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.SuspendUserInput": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "利用停止の理由（本人のログイン・トークン更新の拒否時に返す）",
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "スパム行為のため"
+                },
+                "until": {
+                    "description": "利用停止の終了日時（省略時は解除されるまで無期限）",
+                    "type": "string",
+                    "example": "2026-12-31T00:00:00Z"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.TOTPEnrollment": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "description": "認証アプリに QR コードで読み込ませる otpauth URI",
+                    "type": "string",
+                    "example": "otpauth://totp/Go%20Shisha:user@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=Go+Shisha"
+                },
+                "secret": {
+                    "description": "認証アプリに手入力する Base32 のシークレット",
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.TooManyRequestsError": {
+            "description": "ログインの失敗が続いたアカウントで、待ち時間中・ロック中のためログインを受け付けない場合のエラーレスポンス（Retry-After ヘッダーで次の試行を受け付けるまでの秒数を返す）",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "login_throttled"
                     ],
-                    "example": "already_liked"
+                    "example": "login_throttled"
                 }
             }
         },
-        "go-shisha-backend_internal_models.CreatePostInput": {
+        "go-shisha-backend_internal_models.UnauthorizedError": {
+            "description": "認証に失敗した場合のエラーレスポンス（2段階認証のログインでコードが誤っている場合は invalid_mfa_code）",
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "description": "エラー種別の識別子",
+                    "type": "string",
+                    "enum": [
+                        "unauthorized",
+                        "invalid_mfa_code"
+                    ],
+                    "example": "unauthorized"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UnlockAccountInput": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "description": "ロックしたときにメールで送ったリンクのトークン",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UnreadNotificationCountResponse": {
+            "type": "object",
+            "properties": {
+                "unread_count": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UpdateCollectionInput": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "説明（200文字以内）",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "夏に吸いたいミント系の組み合わせ"
+                },
+                "is_public": {
+                    "description": "公開するかどうか",
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "description": "コレクション名（1〜50文字）",
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1,
+                    "example": "ミント系ベストミックス"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UpdatePostInput": {
             "type": "object",
             "required": [
                 "slides"
             ],
             "properties": {
+                "session": {
+                    "description": "セッション詳細。省略または null の場合は既存の値を変更しない。\n指定した場合は全項目を上書きし、全項目を省略した空オブジェクトを渡すとセッション詳細が削除される",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-shisha-backend_internal_models.ShishaSessionInput"
+                        }
+                    ]
+                },
                 "slides": {
                     "type": "array",
                     "maxItems": 10,
                     "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/go-shisha-backend_internal_models.SlideInput"
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.UpdateSlideInput"
                     }
                 }
             }
         },
-        "go-shisha-backend_internal_models.CreateUserInput": {
+        "go-shisha-backend_internal_models.UpdateRoleInput": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "moderator",
+                        "admin"
+                    ],
+                    "example": "moderator"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UpdateSlideInput": {
+            "type": "object",
+            "required": [
+                "id"
             ],
             "properties": {
+                "flavor_id": {
+                    "description": "フレーバーID。省略または null を指定するとフレーバーが解除される",
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "description": "更新対象のスライドID",
+                    "type": "integer",
+                    "example": 12
+                },
+                "text": {
+                    "description": "スライドのテキスト。省略すると空文字で上書きされる",
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UpdateUserInput": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "自己紹介文（省略可）",
+                    "type": "string"
+                },
                 "display_name": {
+                    "description": "表示名（省略可）",
                     "type": "string"
                 },
-                "email": {
+                "external_url": {
+                    "description": "外部URL（省略可、http(s)://...のみ許可）",
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 12
+                "icon_url": {
+                    "description": "アイコン画像URL（省略可、/images/... または http(s)://...）",
+                    "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.Flavor": {
+        "go-shisha-backend_internal_models.UploadImagesResponse": {
             "type": "object",
             "properties": {
-                "color": {
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UploadProfileImageResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.User": {
+            "type": "object",
+            "properties": {
+                "description": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "display_name": {
+                    "type": "string"
                 },
-                "name": {
+                "email": {
                     "type": "string"
+                },
+                "external_url": {
+                    "type": "string"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
                 }
             }
         },
-        "go-shisha-backend_internal_models.ForbiddenError": {
-            "description": "権限がない操作を実行した場合のエラーレスポンス",
+        "go-shisha-backend_internal_models.UserBadge": {
             "type": "object",
-            "required": [
-                "error"
-            ],
             "properties": {
-                "error": {
-                    "description": "エラー種別の識別子",
+                "awarded_at": {
+                    "description": "獲得日時",
+                    "type": "string"
+                },
+                "code": {
+                    "description": "バッジ識別子",
                     "type": "string",
                     "enum": [
-                        "forbidden"
+                        "first_post",
+                        "flavor_explorer",
+                        "popular",
+                        "weekly_streak"
                     ],
-                    "example": "forbidden"
+                    "example": "first_post"
+                },
+                "description": {
+                    "description": "獲得条件の説明",
+                    "type": "string",
+                    "example": "はじめて投稿した"
+                },
+                "name": {
+                    "description": "バッジ名",
+                    "type": "string",
+                    "example": "はじめての一服"
                 }
             }
         },
-        "go-shisha-backend_internal_models.LoginInput": {
+        "go-shisha-backend_internal_models.UserBadgesResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "badges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.UserBadge"
+                    }
                 },
-                "password": {
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.UserStats": {
+            "type": "object",
+            "properties": {
+                "distinct_flavors": {
+                    "description": "これまでに使用したフレーバーの種類数",
+                    "type": "integer",
+                    "example": 12
+                },
+                "generated_at": {
+                    "description": "統計の集計日時（キャッシュされた統計の場合は集計時点の日時）",
                     "type": "string"
+                },
+                "likes_received_per_month": {
+                    "description": "月ごとに受け取ったいいね数（いいねされた日時で集計、古い月から順）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.MonthlyCount"
+                    }
+                },
+                "longest_streak_days": {
+                    "description": "連続投稿日数の最長記録",
+                    "type": "integer",
+                    "example": 7
+                },
+                "posts_per_month": {
+                    "description": "月ごとの投稿数（古い月から順、投稿のない月は含まない）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.MonthlyCount"
+                    }
+                },
+                "top_flavor_categories": {
+                    "description": "よく使うフレーバーカテゴリ（使用した投稿数の多い順、最大5件）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.CategoryUsage"
+                    }
+                },
+                "top_flavors": {
+                    "description": "よく使うフレーバー（使用した投稿数の多い順、最大5件）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.FlavorUsage"
+                    }
+                },
+                "total_likes_received": {
+                    "description": "受け取ったいいね数の合計",
+                    "type": "integer",
+                    "example": 128
+                },
+                "total_posts": {
+                    "description": "投稿数の合計（削除済みを除く）",
+                    "type": "integer",
+                    "example": 42
+                },
+                "user_id": {
+                    "description": "対象ユーザーID",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "go-shisha-backend_internal_models.NotFoundError": {
-            "description": "リソースが見つからない場合のエラーレスポンス",
+        "go-shisha-backend_internal_models.UsersResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                    }
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.ValidationError": {
+            "description": "入力値のバリデーションに失敗した場合のエラーレスポンス（メールで送ったリンクのトークンが無効・使用済み・有効期限切れの場合は invalid_token、2段階認証のコードが誤っている場合は invalid_mfa_code、パスキーを検証できない場合は invalid_passkey）",
             "type": "object",
             "required": [
                 "error"
@@ -1111,272 +6333,347 @@ const docTemplate = `{
                     "description": "エラー種別の識別子",
                     "type": "string",
                     "enum": [
-                        "not_found"
+                        "validation_failed",
+                        "invalid_token",
+                        "invalid_mfa_code",
+                        "invalid_passkey"
                     ],
-                    "example": "not_found"
+                    "example": "validation_failed"
                 }
             }
         },
-        "go-shisha-backend_internal_models.PayloadTooLargeError": {
-            "description": "ファイルサイズが上限を超えた場合のエラーレスポンス",
+        "go-shisha-backend_internal_models.VerifyEmailInput": {
             "type": "object",
             "required": [
-                "error"
+                "token"
             ],
             "properties": {
-                "error": {
-                    "description": "エラー種別の識別子",
-                    "type": "string",
-                    "enum": [
-                        "payload_too_large"
-                    ],
-                    "example": "payload_too_large"
+                "token": {
+                    "description": "メールで送ったリンクに含まれるトークン",
+                    "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.Post": {
+        "go-shisha-backend_internal_models.WebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.WebhookDelivery"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "go-shisha-backend_internal_models.WebhookDelivery": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "endpoint_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "event_id": {
+                    "type": "string",
+                    "example": "8f14e45f-ceea-467f-a2c5-3f0d0a1b2c3d"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "post.created"
+                },
                 "id": {
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 1
                 },
-                "is_liked": {
-                    "type": "boolean"
+                "last_attempt_at": {
+                    "type": "string"
                 },
-                "likes": {
-                    "type": "integer"
+                "last_error": {
+                    "description": "直近の送信失敗の理由",
+                    "type": "string",
+                    "example": ""
                 },
-                "slides": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-shisha-backend_internal_models.Slide"
-                    }
+                "next_attempt_at": {
+                    "description": "次回の送信予定日時（pending の場合のみ）",
+                    "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                "payload": {
+                    "description": "送信する JSON 本文",
+                    "type": "object"
                 },
-                "user_id": {
-                    "type": "integer"
+                "response_status": {
+                    "description": "直近の送信で受け取ったHTTPステータス",
+                    "type": "integer",
+                    "example": 200
+                },
+                "status": {
+                    "description": "pending / succeeded / failed",
+                    "type": "string",
+                    "example": "succeeded"
                 }
             }
         },
-        "go-shisha-backend_internal_models.PostsResponse": {
+        "go-shisha-backend_internal_models.WebhookEndpoint": {
             "type": "object",
             "properties": {
-                "posts": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "購読するイベントの種類",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/go-shisha-backend_internal_models.Post"
-                    }
+                        "type": "string"
+                    },
+                    "example": [
+                        "post.created",
+                        "like.added"
+                    ]
                 },
-                "total": {
-                    "type": "integer"
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "secret": {
+                    "description": "署名の検証に使う鍵（登録時のレスポンスにのみ含まれる）",
+                    "type": "string",
+                    "example": "5f2b..."
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/webhooks/shisha"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "go-shisha-backend_internal_models.ServerError": {
-            "description": "サーバー内部でエラーが発生した場合のエラーレスポンス",
+        "go-shisha-backend_internal_models.WebhookEndpointsResponse": {
             "type": "object",
-            "required": [
-                "error"
-            ],
             "properties": {
-                "error": {
-                    "description": "エラー種別の識別子",
-                    "type": "string",
-                    "enum": [
-                        "internal_server_error"
-                    ],
-                    "example": "internal_server_error"
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_internal_models.WebhookEndpoint"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "go-shisha-backend_internal_models.Slide": {
+        "go-shisha-backend_pkg_webauthn.AssertionResponse": {
             "type": "object",
-            "required": [
-                "image_url"
-            ],
             "properties": {
-                "flavor": {
-                    "$ref": "#/definitions/go-shisha-backend_internal_models.Flavor"
-                },
                 "id": {
-                    "type": "integer"
+                    "type": "string"
                 },
-                "image_url": {
+                "rawId": {
                     "type": "string"
                 },
-                "text": {
+                "response": {
+                    "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.AuthenticatorAssertionData"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.SlideInput": {
+        "go-shisha-backend_pkg_webauthn.AuthenticatorAssertionData": {
             "type": "object",
-            "required": [
-                "image_url"
-            ],
             "properties": {
-                "flavor_id": {
-                    "type": "integer"
+                "authenticatorData": {
+                    "type": "string"
                 },
-                "image_url": {
+                "clientDataJSON": {
                     "type": "string"
                 },
-                "text": {
+                "signature": {
+                    "type": "string"
+                },
+                "userHandle": {
                     "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.UnauthorizedError": {
-            "description": "認証に失敗した場合のエラーレスポンス",
-            "type": "object",
-            "required": [
-                "error"
-            ],
-            "properties": {
-                "error": {
-                    "description": "エラー種別の識別子",
-                    "type": "string",
-                    "enum": [
-                        "unauthorized"
-                    ],
-                    "example": "unauthorized"
-                }
-            }
-        },
-        "go-shisha-backend_internal_models.UpdatePostInput": {
+        "go-shisha-backend_pkg_webauthn.AuthenticatorAttestationData": {
             "type": "object",
-            "required": [
-                "slides"
-            ],
             "properties": {
-                "slides": {
+                "attestationObject": {
+                    "type": "string"
+                },
+                "clientDataJSON": {
+                    "type": "string"
+                },
+                "transports": {
                     "type": "array",
-                    "maxItems": 10,
-                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/go-shisha-backend_internal_models.UpdateSlideInput"
+                        "type": "string"
                     }
                 }
             }
         },
-        "go-shisha-backend_internal_models.UpdateSlideInput": {
+        "go-shisha-backend_pkg_webauthn.AuthenticatorSelection": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "flavor_id": {
-                    "description": "フレーバーID。省略または null を指定するとフレーバーが解除される",
-                    "type": "integer",
-                    "example": 1
-                },
-                "id": {
-                    "description": "更新対象のスライドID",
-                    "type": "integer",
-                    "example": 12
+                "residentKey": {
+                    "type": "string"
                 },
-                "text": {
-                    "description": "スライドのテキスト。省略すると空文字で上書きされる",
+                "userVerification": {
                     "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.UpdateUserInput": {
+        "go-shisha-backend_pkg_webauthn.CreationOptions": {
             "type": "object",
             "properties": {
-                "description": {
-                    "description": "自己紹介文（省略可）",
+                "attestation": {
                     "type": "string"
                 },
-                "display_name": {
-                    "description": "表示名（省略可）",
-                    "type": "string"
+                "authenticatorSelection": {
+                    "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.AuthenticatorSelection"
                 },
-                "external_url": {
-                    "description": "外部URL（省略可、http(s)://...のみ許可）",
+                "challenge": {
                     "type": "string"
                 },
-                "icon_url": {
-                    "description": "アイコン画像URL（省略可、/images/... または http(s)://...）",
-                    "type": "string"
+                "excludeCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.CredentialDescriptor"
+                    }
+                },
+                "pubKeyCredParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.CredentialParameter"
+                    }
+                },
+                "rp": {
+                    "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.RPEntity"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.UserEntity"
                 }
             }
         },
-        "go-shisha-backend_internal_models.UploadImagesResponse": {
+        "go-shisha-backend_pkg_webauthn.CredentialDescriptor": {
             "type": "object",
             "properties": {
-                "urls": {
+                "id": {
+                    "type": "string"
+                },
+                "transports": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.UploadProfileImageResponse": {
+        "go-shisha-backend_pkg_webauthn.CredentialParameter": {
             "type": "object",
             "properties": {
-                "url": {
+                "alg": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.User": {
+        "go-shisha-backend_pkg_webauthn.RPEntity": {
             "type": "object",
             "properties": {
-                "description": {
+                "id": {
                     "type": "string"
                 },
-                "display_name": {
+                "name": {
                     "type": "string"
-                },
-                "email": {
+                }
+            }
+        },
+        "go-shisha-backend_pkg_webauthn.RegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 },
-                "external_url": {
+                "rawId": {
                     "type": "string"
                 },
-                "icon_url": {
-                    "type": "string"
+                "response": {
+                    "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.AuthenticatorAttestationData"
                 },
-                "id": {
-                    "type": "integer"
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.UsersResponse": {
+        "go-shisha-backend_pkg_webauthn.RequestOptions": {
             "type": "object",
             "properties": {
-                "total": {
-                    "type": "integer"
-                },
-                "users": {
+                "allowCredentials": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/go-shisha-backend_internal_models.User"
+                        "$ref": "#/definitions/go-shisha-backend_pkg_webauthn.CredentialDescriptor"
                     }
+                },
+                "challenge": {
+                    "type": "string"
+                },
+                "rpId": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "userVerification": {
+                    "type": "string"
                 }
             }
         },
-        "go-shisha-backend_internal_models.ValidationError": {
-            "description": "入力値のバリデーションに失敗した場合のエラーレスポンス",
+        "go-shisha-backend_pkg_webauthn.UserEntity": {
             "type": "object",
-            "required": [
-                "error"
-            ],
             "properties": {
-                "error": {
-                    "description": "エラー種別の識別子",
-                    "type": "string",
-                    "enum": [
-                        "validation_failed"
-                    ],
-                    "example": "validation_failed"
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID はユーザーハンドル（個人情報を含めない）",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         }
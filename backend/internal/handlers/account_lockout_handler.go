@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountLockoutServiceInterface は AccountLockoutService のインターフェース（テスト用）
+type AccountLockoutServiceInterface interface {
+	Unlock(token string) error
+	AdminUnlock(moderatorID, userID int) error
+}
+
+// AccountLockoutHandler はログインの失敗が続いてロックされたアカウントの解除を処理する
+type AccountLockoutHandler struct {
+	accountLockoutService AccountLockoutServiceInterface
+}
+
+// NewAccountLockoutHandler は新しい AccountLockoutHandler を作成する
+func NewAccountLockoutHandler(accountLockoutService AccountLockoutServiceInterface) *AccountLockoutHandler {
+	return &AccountLockoutHandler{
+		accountLockoutService: accountLockoutService,
+	}
+}
+
+// UnlockAccount godoc
+// @Summary アカウントのロック解除
+// @Description ログインの失敗が続いてロックしたときにメールで送ったリンクのトークンで、アカウントのロックを解除する。リンクはロックが終わるまで有効
+// @Description ロックが既に終わっている・解除済みの場合も 204 を返す
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.UnlockAccountInput true "ロック解除用トークン"
+// @Success 204 "ロックを解除しました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー・トークンが無効"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/unlock [post]
+func (h *AccountLockoutHandler) UnlockAccount(c *gin.Context) {
+	var input models.UnlockAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "AccountLockoutHandler", "method", "UnlockAccount", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.accountLockoutService.Unlock(input.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidUnlockToken) {
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeInvalidToken})
+			return
+		}
+		logging.L.Error("failed to unlock account", "handler", "AccountLockoutHandler", "method", "UnlockAccount", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AdminUnlockAccount は DELETE /api/v1/admin/users/:id/lock を処理する
+// @Summary アカウントのロック解除（管理者・モデレーター）
+// @Description ログインの失敗が続いてロックされたアカウントのロックを解除し、失敗の回数も数え直す
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Success 204 "ロックを解除しました"
+// @Failure 400 {object} models.ValidationError "無効なパラメータ"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "管理者・モデレーターではありません"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 409 {object} models.ConflictError "ロックされていません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /admin/users/{id}/lock [delete]
+func (h *AccountLockoutHandler) AdminUnlockAccount(c *gin.Context) {
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "AccountLockoutHandler", "method", "AdminUnlockAccount")
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	if err := h.accountLockoutService.AdminUnlock(userID, targetID); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+		case errors.Is(err, services.ErrAccountNotLocked):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeNotLocked})
+		default:
+			logging.L.Error("failed to unlock account", "handler", "AccountLockoutHandler", "method", "AdminUnlockAccount", "user_id", userID, "target_user_id", targetID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
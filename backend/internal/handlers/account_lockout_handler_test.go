@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAccountLockoutService はテスト用の AccountLockoutService モック
+type mockAccountLockoutService struct {
+	unlockFunc      func(token string) error
+	adminUnlockFunc func(moderatorID, userID int) error
+}
+
+func (m *mockAccountLockoutService) Unlock(token string) error {
+	if m.unlockFunc != nil {
+		return m.unlockFunc(token)
+	}
+	return nil
+}
+
+func (m *mockAccountLockoutService) AdminUnlock(moderatorID, userID int) error {
+	if m.adminUnlockFunc != nil {
+		return m.adminUnlockFunc(moderatorID, userID)
+	}
+	return nil
+}
+
+func TestUnlockAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/auth/unlock", NewAccountLockoutHandler(&mockAccountLockoutService{
+		unlockFunc: func(token string) error {
+			switch token {
+			case "valid":
+				return nil
+			case "broken":
+				return errors.New("db error")
+			}
+			return services.ErrInvalidUnlockToken
+		},
+	}).UnlockAccount)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "正常系: ロックを解除する", body: `{"token":"valid"}`, wantStatus: http.StatusNoContent},
+		{name: "異常系: 無効なトークン", body: `{"token":"expired"}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeInvalidToken},
+		{name: "異常系: トークンが未入力", body: `{}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "異常系: サーバーエラー", body: `{"token":"broken"}`, wantStatus: http.StatusInternalServerError, wantError: models.ErrCodeInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/auth/unlock", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var resp map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp["error"])
+			}
+		})
+	}
+}
+
+func TestAdminUnlockAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.DELETE("/admin/users/:id/lock", withUserID(1), NewAccountLockoutHandler(&mockAccountLockoutService{
+		adminUnlockFunc: func(moderatorID, userID int) error {
+			assert.Equal(t, 1, moderatorID)
+			switch userID {
+			case 2:
+				return nil
+			case 3:
+				return services.ErrAccountNotLocked
+			}
+			return repositories.ErrUserNotFound
+		},
+	}).AdminUnlockAccount)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "正常系: ロックを解除する", path: "/admin/users/2/lock", wantStatus: http.StatusNoContent},
+		{name: "異常系: ロックされていない", path: "/admin/users/3/lock", wantStatus: http.StatusConflict, wantError: models.ErrCodeNotLocked},
+		{name: "異常系: ユーザーが存在しない", path: "/admin/users/99/lock", wantStatus: http.StatusNotFound, wantError: models.ErrCodeNotFound},
+		{name: "異常系: 無効なID", path: "/admin/users/abc/lock", wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var resp map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp["error"])
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"errors"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 
 	"go-shisha-backend/internal/models"
 	"go-shisha-backend/internal/repositories"
 	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/auth"
 	"go-shisha-backend/pkg/logging"
 
 	"github.com/gin-gonic/gin"
@@ -29,6 +32,114 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 	}
 }
 
+// setTokenCookies は Access Token（15分有効）と Refresh Token（7日有効）を Cookie に設定する
+func (h *AuthHandler) setTokenCookies(c *gin.Context, accessToken, refreshToken string) {
+	writeTokenCookies(c, h.isSecure, accessToken, refreshToken)
+}
+
+// writeTokenCookies は Access Token（15分有効）と Refresh Token（7日有効）を Cookie に設定する
+// ログインの方法（パスワード・パスキーなど）によらず同じ Cookie を発行するため、ハンドラー間で共有する
+func writeTokenCookies(c *gin.Context, secure bool, accessToken, refreshToken string) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     "access_token",
+		Value:    accessToken,
+		Path:     "/",
+		MaxAge:   15 * 60,
+		Secure:   secure, // 本番環境（APP_ENV=production）でtrue
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode, // CSRF対策
+	})
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    refreshToken,
+		Path:     "/",
+		MaxAge:   7 * 24 * 60 * 60,
+		Secure:   secure, // 本番環境（APP_ENV=production）でtrue
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode, // CSRF対策
+	})
+}
+
+// clearTokenCookies は Access Token と Refresh Token の Cookie を削除する（MaxAge=-1で即座に削除）
+func (h *AuthHandler) clearTokenCookies(c *gin.Context) {
+	for _, name := range []string{"access_token", "refresh_token"} {
+		http.SetCookie(c.Writer, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			MaxAge:   -1,
+			Secure:   h.isSecure,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+}
+
+// sessionDevice はリクエストを送った端末の情報を返す
+func sessionDevice(c *gin.Context) models.SessionDevice {
+	return models.SessionDevice{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// tokenClaims は AuthMiddleware がセットしたAccess Tokenのクレームを返す（セットされていない場合は nil）
+func tokenClaims(c *gin.Context) *auth.Claims {
+	value, _ := c.Get("token_claims")
+	claims, _ := value.(*auth.Claims)
+	return claims
+}
+
+// currentSessionID はリクエストに使われたAccess TokenのセッションIDを返す
+func currentSessionID(c *gin.Context) string {
+	if claims := tokenClaims(c); claims != nil {
+		return claims.SessionID
+	}
+	return ""
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *AuthHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "AuthHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeLoginThrottled は err が待ち時間中・ロック中によるものであれば 429 と Retry-After ヘッダー（秒）を書き込み true を返す
+func writeLoginThrottled(c *gin.Context, err error) bool {
+	var throttled *services.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	retryAfter := max(int(math.Ceil(throttled.RetryAfter.Seconds())), 1)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, models.TooManyRequestsError{Error: models.ErrCodeLoginThrottled})
+	return true
+}
+
+// writeAccountSuspended は err が利用停止によるものであれば 403 と理由・終了日時を書き込み true を返す
+func writeAccountSuspended(c *gin.Context, err error) bool {
+	var suspension *auth.SuspensionError
+	if !errors.As(err, &suspension) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, models.AccountSuspendedError{
+		Error:          models.ErrCodeAccountSuspended,
+		Reason:         suspension.Reason,
+		SuspendedUntil: suspension.Until,
+	})
+	return true
+}
+
 // Register godoc
 // @Summary ユーザー登録
 // @Description 新しいユーザーを登録する
@@ -78,19 +189,24 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.AuthResponse{User: *user})
+	c.JSON(http.StatusCreated, models.NewAuthResponse(user))
 }
 
 // Login godoc
 // @Summary ログイン
 // @Description メールアドレスとパスワードでログインし、JWT（Cookie）を発行する
+// @Description 2段階認証が有効なユーザーの場合は Cookie を発行せずに 202 とチャレンジトークンを返す。チャレンジトークンと2段階認証のコードを POST /auth/login/mfa に送るとログインが完了する
+// @Description 失敗が続いたアカウントは、失敗のたびに次のログインまでの待ち時間が延び、さらに続くと一時的にロックされる。待ち時間中・ロック中はパスワードを検証せずに 429 を返し、Retry-After ヘッダーで次のログインを受け付けるまでの秒数を返す（ロックしたときは解除用のリンクをメールで送る）
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param input body models.LoginInput true "ログイン情報"
 // @Success 200 {object} models.AuthResponse "ログイン成功"
+// @Success 202 {object} models.MFAChallengeResponse "2段階認証のコードが必要"
 // @Failure 400 {object} models.ValidationError "バリデーションエラー"
 // @Failure 401 {object} models.UnauthorizedError "認証失敗"
+// @Failure 403 {object} models.AccountSuspendedError "利用停止中"
+// @Failure 429 {object} models.TooManyRequestsError "ログインの失敗が続いたため待ち時間中・ロック中"
 // @Failure 500 {object} models.ServerError "サーバーエラー"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -106,7 +222,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, accessToken, refreshToken, err := h.authService.Login(&input)
+	user, accessToken, refreshToken, err := h.authService.Login(&input, sessionDevice(c))
 	if err != nil {
 		if errors.Is(err, services.ErrInvalidCredentials) {
 			logging.L.Warn("login failed",
@@ -119,6 +235,21 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			})
 			return
 		}
+		var challenge *services.MFAChallengeError
+		if errors.As(err, &challenge) {
+			c.JSON(http.StatusAccepted, models.MFAChallengeResponse{
+				MFARequired:    true,
+				ChallengeToken: challenge.ChallengeToken,
+				ExpiresAt:      challenge.ExpiresAt,
+			})
+			return
+		}
+		if writeLoginThrottled(c, err) {
+			return
+		}
+		if writeAccountSuspended(c, err) {
+			return
+		}
 		logging.L.Error("login internal error",
 			"handler", "AuthHandler",
 			"method", "Login",
@@ -130,43 +261,74 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Access Token を Cookie に設定（15分有効）
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "access_token",
-		Value:    accessToken,
-		Path:     "/",
-		MaxAge:   15 * 60,
-		Secure:   h.isSecure, // 本番環境（APP_ENV=production）でtrue
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode, // CSRF対策
-	})
-
-	// Refresh Token を Cookie に設定（7日有効）
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    refreshToken,
-		Path:     "/",
-		MaxAge:   7 * 24 * 60 * 60,
-		Secure:   h.isSecure, // 本番環境（APP_ENV=production）でtrue
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode, // CSRF対策
-	})
+	h.setTokenCookies(c, accessToken, refreshToken)
 
 	logging.L.Info("user logged in",
 		"handler", "AuthHandler",
 		"method", "Login",
 		"user_id", user.ID)
 
-	c.JSON(http.StatusOK, models.AuthResponse{User: *user})
+	c.JSON(http.StatusOK, models.NewAuthResponse(user))
+}
+
+// LoginMFA godoc
+// @Summary 2段階認証のコードによるログイン
+// @Description ログインで返したチャレンジトークンと、認証アプリに表示された6桁のコードまたはリカバリーコードでログインを完了し、JWT（Cookie）を発行する
+// @Description チャレンジトークンは5分間有効で、一度しか使えない（コードが誤っている場合もログインからやり直す）。コードが誤っている場合は invalid_mfa_code、チャレンジトークンが無効・使用済みの場合は unauthorized を返す
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.MFALoginInput true "チャレンジトークンと2段階認証のコード"
+// @Success 200 {object} models.AuthResponse "ログイン成功"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証失敗"
+// @Failure 403 {object} models.AccountSuspendedError "利用停止中"
+// @Failure 429 {object} models.TooManyRequestsError "ログインの失敗が続いたため待ち時間中・ロック中"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/login/mfa [post]
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var input models.MFALoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "AuthHandler", "method", "LoginMFA", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	user, accessToken, refreshToken, err := h.authService.CompleteMFALogin(input.ChallengeToken, input.Code, sessionDevice(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMFACode) {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeInvalidMFACode})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidMFAChallenge) {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+			return
+		}
+		if writeLoginThrottled(c, err) {
+			return
+		}
+		if writeAccountSuspended(c, err) {
+			return
+		}
+		logging.L.Error("mfa login internal error", "handler", "AuthHandler", "method", "LoginMFA", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	h.setTokenCookies(c, accessToken, refreshToken)
+	logging.L.Info("user logged in", "handler", "AuthHandler", "method", "LoginMFA", "user_id", user.ID)
+	c.JSON(http.StatusOK, models.NewAuthResponse(user))
 }
 
 // Refresh godoc
 // @Summary アクセストークンのリフレッシュ
-// @Description Refresh Tokenを使って新しいAccess Tokenを発行する
+// @Description Refresh Tokenを使って新しいAccess Tokenを発行し、Refresh Tokenをローテーションする（新しいトークンは Cookie に設定）
+// @Description ローテーション済みのRefresh Tokenが再度使われた場合は漏洩とみなし、同じログインから続くRefresh Tokenをすべて無効化する
 // @Tags auth
 // @Produce json
 // @Success 200 {object} map[string]string "リフレッシュ成功"
 // @Failure 401 {object} models.UnauthorizedError "認証失敗"
+// @Failure 403 {object} models.AccountSuspendedError "利用停止中"
 // @Failure 500 {object} models.ServerError "サーバーエラー"
 // @Router /auth/refresh [post]
 func (h *AuthHandler) Refresh(c *gin.Context) {
@@ -182,18 +344,23 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, err := h.authService.Refresh(refreshToken)
+	newAccessToken, newRefreshToken, err := h.authService.Refresh(refreshToken, sessionDevice(c))
 	if err != nil {
-		if errors.Is(err, services.ErrInvalidRefreshToken) {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
 			logging.L.Warn("refresh failed",
 				"handler", "AuthHandler",
 				"method", "Refresh",
 				"error", err)
+			// 使えなくなったトークンを送り続けないよう Cookie を削除する
+			h.clearTokenCookies(c)
 			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{
 				Error: models.ErrCodeUnauthorized,
 			})
 			return
 		}
+		if writeAccountSuspended(c, err) {
+			return
+		}
 		logging.L.Error("refresh internal error",
 			"handler", "AuthHandler",
 			"method", "Refresh",
@@ -204,16 +371,8 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	// 新しい Access Token を Cookie に設定
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "access_token",
-		Value:    newAccessToken,
-		Path:     "/",
-		MaxAge:   15 * 60,
-		Secure:   h.isSecure, // 本番環境（APP_ENV=production）でtrue
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode, // CSRF対策
-	})
+	// 新しい Access Token とローテーションした Refresh Token を Cookie に設定
+	h.setTokenCookies(c, newAccessToken, newRefreshToken)
 
 	logging.L.Info("access token refreshed",
 		"handler", "AuthHandler",
@@ -224,7 +383,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 
 // Logout godoc
 // @Summary ログアウト
-// @Description Cookieを削除し、Refresh Tokenを無効化する
+// @Description Cookieを削除し、この端末のセッションのRefresh Tokenとログアウトに使ったAccess Tokenを無効化する（他の端末はログアウトしない）
 // @Tags auth
 // @Produce json
 // @Success 200 {object} map[string]string "ログアウト成功"
@@ -253,7 +412,8 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		})
 		return
 	}
-	if err := h.authService.Logout(int64(uid)); err != nil {
+	// ログアウトに使ったAccess Tokenは有効期限を待たずに無効化する
+	if err := h.authService.Logout(int64(uid), tokenClaims(c)); err != nil {
 		logging.L.Error("logout failed",
 			"handler", "AuthHandler",
 			"method", "Logout",
@@ -265,25 +425,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	// Cookieを削除（MaxAge=-1で即座に削除）
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "access_token",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		Secure:   h.isSecure,
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-	})
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		Secure:   h.isSecure,
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-	})
+	h.clearTokenCookies(c)
 
 	logging.L.Info("user logged out",
 		"handler", "AuthHandler",
@@ -337,5 +479,133 @@ func (h *AuthHandler) Me(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.AuthResponse{User: *user})
+	c.JSON(http.StatusOK, models.NewAuthResponse(user))
+}
+
+// ChangePassword godoc
+// @Summary パスワード変更
+// @Description 現在のパスワードを確認してパスワードを変更する。新しいパスワードは12文字以上
+// @Description 変更後はすべての端末（セッション）をログアウトさせ、発行済みのAccess Tokenもすべて無効にする。この端末には新しいトークンの Cookie を設定する
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.ChangePasswordInput true "現在のパスワードと新しいパスワード"
+// @Success 204 "パスワードを変更しました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "現在のパスワードが誤っています"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "ChangePassword")
+	if !ok {
+		return
+	}
+
+	var input models.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "AuthHandler", "method", "ChangePassword", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	accessToken, refreshToken, err := h.authService.ChangePassword(int64(userID), sessionDevice(c), &input)
+	if err != nil {
+		if errors.Is(err, services.ErrIncorrectPassword) {
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeIncorrectPassword})
+			return
+		}
+		logging.L.Error("failed to change password", "handler", "AuthHandler", "method", "ChangePassword", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	h.setTokenCookies(c, accessToken, refreshToken)
+	c.Status(http.StatusNoContent)
+}
+
+// ListSessions godoc
+// @Summary ログイン中の端末一覧
+// @Description ログイン中の端末（セッション）を最後に使われた順に取得する。このリクエストを送った端末は current が true になる
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SessionsResponse "ログイン中の端末"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "ListSessions")
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(int64(userID), currentSessionID(c))
+	if err != nil {
+		logging.L.Error("failed to list sessions", "handler", "AuthHandler", "method", "ListSessions", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.SessionsResponse{Sessions: sessions})
+}
+
+// RevokeSession godoc
+// @Summary 端末のログアウト
+// @Description 指定した端末（セッション）をログアウトさせる。その端末のAccess Tokenも有効期限を待たずに無効になる
+// @Description この端末のセッションを指定した場合は Cookie も削除する
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "セッションID"
+// @Success 204 "ログアウトさせました"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "セッションが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "RevokeSession")
+	if !ok {
+		return
+	}
+	sessionID := c.Param("id")
+
+	if err := h.authService.RevokeSession(int64(userID), sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to revoke session", "handler", "AuthHandler", "method", "RevokeSession", "user_id", userID, "session_id", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	if sessionID == currentSessionID(c) {
+		h.clearTokenCookies(c)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions godoc
+// @Summary 他の端末からすべてログアウト
+// @Description この端末以外のすべての端末（セッション）をログアウトさせる。それらの端末のAccess Tokenも有効期限を待たずに無効になる
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "ログアウトさせました"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/sessions [delete]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "RevokeOtherSessions")
+	if !ok {
+		return
+	}
+
+	revoked, err := h.authService.RevokeOtherSessions(int64(userID), currentSessionID(c))
+	if err != nil {
+		logging.L.Error("failed to revoke other sessions", "handler", "AuthHandler", "method", "RevokeOtherSessions", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	logging.L.Info("other sessions revoked", "handler", "AuthHandler", "method", "RevokeOtherSessions", "user_id", userID, "count", revoked)
+	c.Status(http.StatusNoContent)
 }
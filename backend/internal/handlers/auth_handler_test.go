@@ -6,11 +6,12 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
+	"time"
 
 	"go-shisha-backend/internal/models"
 	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/repositories/postgres"
 	"go-shisha-backend/internal/services"
 	"go-shisha-backend/pkg/auth"
 
@@ -19,7 +20,12 @@ import (
 )
 
 func init() {
-	_ = os.Setenv("JWT_SECRET", "test-secret-key-for-testing-purpose-at-least-64-characters-long")
+	// テスト用の署名鍵を設定
+	keys, err := auth.GenerateKeySet()
+	if err != nil {
+		panic(err)
+	}
+	auth.SetKeySet(keys)
 	gin.SetMode(gin.TestMode)
 }
 
@@ -59,19 +65,43 @@ func (m *mockAuthUserRepoForHandler) GetByID(id int) (*models.User, error) {
 	return nil, errors.New("user not found")
 }
 
+func (m *mockAuthUserRepoForHandler) UpdatePassword(userID int, passwordHash string) error {
+	for _, user := range m.users {
+		if user.ID == userID {
+			user.PasswordHash = passwordHash
+			return nil
+		}
+	}
+	return repositories.ErrUserNotFound
+}
+
+func (m *mockAuthUserRepoForHandler) MarkEmailVerified(userID int, email string) error {
+	for _, user := range m.users {
+		if user.ID == userID && user.Email == email {
+			if user.EmailVerifiedAt == nil {
+				now := time.Now()
+				user.EmailVerifiedAt = &now
+			}
+			return nil
+		}
+	}
+	return repositories.ErrUserNotFound
+}
+
 type mockRefreshTokenRepoForHandler struct {
-	tokens  map[int64]string
+	tokens  map[string]*models.RefreshToken
 	findErr error // FindByTokenHash に注入するエラー
 }
 
 func newMockRefreshTokenRepoForHandler() *mockRefreshTokenRepoForHandler {
 	return &mockRefreshTokenRepoForHandler{
-		tokens: make(map[int64]string),
+		tokens: make(map[string]*models.RefreshToken),
 	}
 }
 
 func (m *mockRefreshTokenRepoForHandler) Create(token *models.RefreshToken, rawToken string) error {
-	m.tokens[token.UserID] = rawToken
+	token.ID = int64(len(m.tokens) + 1)
+	m.tokens[rawToken] = token
 	return nil
 }
 
@@ -79,32 +109,128 @@ func (m *mockRefreshTokenRepoForHandler) FindByTokenHash(rawToken string) (*mode
 	if m.findErr != nil {
 		return nil, m.findErr
 	}
-	for userID, storedToken := range m.tokens {
-		if storedToken == rawToken {
-			return &models.RefreshToken{UserID: userID}, nil
-		}
+	if token, exists := m.tokens[rawToken]; exists {
+		return token, nil
 	}
 	return nil, gorm.ErrRecordNotFound
 }
 
+func (m *mockRefreshTokenRepoForHandler) Rotate(current *models.RefreshToken, next *models.RefreshToken, rawNext string) error {
+	if current.RotatedAt != nil {
+		return postgres.ErrRefreshTokenRotated
+	}
+	now := time.Now()
+	current.RotatedAt = &now
+	next.FamilyID = current.FamilyID
+	next.SessionStartedAt = current.SessionStartedAt
+	return m.Create(next, rawNext)
+}
+
 func (m *mockRefreshTokenRepoForHandler) UpdateLastUsed(id int64) error {
 	return nil
 }
 
 func (m *mockRefreshTokenRepoForHandler) DeleteByUserID(userID int64) error {
-	delete(m.tokens, userID)
+	for key, token := range m.tokens {
+		if token.UserID == userID {
+			delete(m.tokens, key)
+		}
+	}
+	return nil
+}
+
+func (m *mockRefreshTokenRepoForHandler) DeleteByFamilyID(familyID string) error {
+	for key, token := range m.tokens {
+		if token.FamilyID == familyID {
+			delete(m.tokens, key)
+		}
+	}
 	return nil
 }
 
+func (m *mockRefreshTokenRepoForHandler) ListActiveByUserID(userID int64) ([]models.RefreshToken, error) {
+	tokens := []models.RefreshToken{}
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.RotatedAt == nil {
+			tokens = append(tokens, *token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *mockRefreshTokenRepoForHandler) DeleteByUserIDAndFamilyID(userID int64, familyID string) (int64, error) {
+	var deleted int64
+	for key, token := range m.tokens {
+		if token.UserID == userID && token.FamilyID == familyID {
+			delete(m.tokens, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func (m *mockRefreshTokenRepoForHandler) DeleteExpired() error {
 	// テスト用の実装は不要（何もしない）
 	return nil
 }
 
+// memoryTokenRevocationRepoForHandler はテスト用のインメモリ TokenRevocationRepository
+type memoryTokenRevocationRepoForHandler struct {
+	revoked map[string]time.Time
+}
+
+func (m *memoryTokenRevocationRepoForHandler) RevokeAccessToken(jti string, userID int, expiresAt time.Time) error {
+	m.revoked[jti] = expiresAt
+	return nil
+}
+
+func (m *memoryTokenRevocationRepoForHandler) IsAccessTokenRevoked(jti string) (bool, error) {
+	_, ok := m.revoked[jti]
+	return ok, nil
+}
+
+func (m *memoryTokenRevocationRepoForHandler) ConsumeToken(jti string, userID int, expiresAt time.Time) (bool, error) {
+	if _, ok := m.revoked[jti]; ok {
+		return false, nil
+	}
+	m.revoked[jti] = expiresAt
+	return true, nil
+}
+
+func (m *memoryTokenRevocationRepoForHandler) IncrementTokenVersion(userID int) error {
+	return nil
+}
+
+func (m *memoryTokenRevocationRepoForHandler) DeleteExpiredRevocations(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTokenRevocationStoreForHandler() *services.TokenRevocationStore {
+	return services.NewTokenRevocationStore(&memoryTokenRevocationRepoForHandler{revoked: make(map[string]time.Time)})
+}
+
+// nopVerificationSenderForHandler・nopLoginLimiterForHandler は、テストで確認しない AuthService の依存に渡す何もしない実装
+type nopVerificationSenderForHandler struct{}
+
+func (nopVerificationSenderForHandler) SendVerification(user *models.User) error { return nil }
+
+type nopLoginLimiterForHandler struct{}
+
+func (nopLoginLimiterForHandler) Reserve(userID int) error { return nil }
+
+func (nopLoginLimiterForHandler) RecordFailure(user *models.User) error { return nil }
+
+func (nopLoginLimiterForHandler) RecordSuccess(userID int) error { return nil }
+
+// newAuthServiceForHandler は2段階認証が誰にも有効でない AuthService を作成する
+func newAuthServiceForHandler(userRepo *mockAuthUserRepoForHandler, tokenRepo *mockRefreshTokenRepoForHandler) *services.AuthService {
+	return services.NewAuthService(userRepo, tokenRepo, newTokenRevocationStoreForHandler(), nopVerificationSenderForHandler{}, &stubMFAVerifier{}, nopLoginLimiterForHandler{})
+}
+
 func TestAuthHandler_Register_Success(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	r := gin.New()
@@ -135,12 +261,15 @@ func TestAuthHandler_Register_Success(t *testing.T) {
 	if response.User.Email != input.Email {
 		t.Errorf("expected email %s, got %s", input.Email, response.User.Email)
 	}
+	if response.Role != models.RoleUser {
+		t.Errorf("expected role %s, got %s", models.RoleUser, response.Role)
+	}
 }
 
 func TestAuthHandler_Register_InvalidJSON(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	r := gin.New()
@@ -169,7 +298,7 @@ func TestAuthHandler_Register_InvalidJSON(t *testing.T) {
 func TestAuthHandler_Register_EmailAlreadyExists(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	// 事前にユーザーを登録
@@ -210,7 +339,7 @@ func TestAuthHandler_Register_EmailAlreadyExists(t *testing.T) {
 func TestAuthHandler_Login_Success(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	// 事前にユーザーを登録
@@ -269,10 +398,98 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Login_Suspended(t *testing.T) {
+	userRepo := newMockAuthUserRepoForHandler()
+	tokenRepo := newMockRefreshTokenRepoForHandler()
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
+	handler := NewAuthHandler(authService)
+
+	// 利用停止中のユーザーを登録
+	suspendedAt := time.Now()
+	until := suspendedAt.Add(24 * time.Hour)
+	user := &models.User{Email: "suspended@example.com", DisplayName: "Suspended User", SuspendedAt: &suspendedAt, SuspendedUntil: &until, SuspensionReason: "スパム行為のため"}
+	_ = user.HashPassword("password123456")
+	_ = userRepo.Create(user)
+
+	r := gin.New()
+	r.POST("/login", handler.Login)
+
+	body, _ := json.Marshal(models.LoginInput{Email: "suspended@example.com", Password: "password123456"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", w.Code)
+	}
+	var resp models.AccountSuspendedError
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error != models.ErrCodeAccountSuspended || resp.Reason != "スパム行為のため" || resp.SuspendedUntil == nil {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("expected no cookies to be set for suspended user")
+	}
+}
+
+// throttledLoginLimiterForHandler はすべての試行を retryAfter の待ち時間中として拒否する loginLimiter
+type throttledLoginLimiterForHandler struct {
+	nopLoginLimiterForHandler
+	retryAfter time.Duration
+}
+
+func (l throttledLoginLimiterForHandler) Reserve(userID int) error {
+	return &services.LoginThrottledError{RetryAfter: l.retryAfter}
+}
+
+func TestAuthHandler_Login_Throttled(t *testing.T) {
+	userRepo := newMockAuthUserRepoForHandler()
+	tokenRepo := newMockRefreshTokenRepoForHandler()
+	limiter := throttledLoginLimiterForHandler{retryAfter: 1500 * time.Millisecond}
+	authService := services.NewAuthService(userRepo, tokenRepo, newTokenRevocationStoreForHandler(), nopVerificationSenderForHandler{}, &stubMFAVerifier{}, limiter)
+	handler := NewAuthHandler(authService)
+
+	user := &models.User{Email: "throttled@example.com", DisplayName: "Throttled User"}
+	_ = user.HashPassword("password123456")
+	_ = userRepo.Create(user)
+
+	r := gin.New()
+	r.POST("/login", handler.Login)
+
+	// パスワードが正しくても、待ち時間中は 401 ではなく 429 と次の試行までの秒数を返す
+	body, _ := json.Marshal(models.LoginInput{Email: "throttled@example.com", Password: "password123456"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("expected Retry-After 2, got %q", got)
+	}
+	var resp models.TooManyRequestsError
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error != models.ErrCodeLoginThrottled {
+		t.Errorf("expected error %q, got %q", models.ErrCodeLoginThrottled, resp.Error)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("expected no cookies to be set for throttled login")
+	}
+}
+
 func TestAuthHandler_Login_InvalidPassword(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	// 事前にユーザーを登録
@@ -312,7 +529,7 @@ func TestAuthHandler_Login_InvalidPassword(t *testing.T) {
 func TestAuthHandler_Login_UserNotFound(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	r := gin.New()
@@ -347,7 +564,7 @@ func TestAuthHandler_Login_UserNotFound(t *testing.T) {
 func TestAuthHandler_Login_ValidationError(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	r := gin.New()
@@ -391,7 +608,7 @@ func TestAuthHandler_Login_ValidationError(t *testing.T) {
 func TestAuthHandler_Refresh_NoCookie(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	r := gin.New()
@@ -417,7 +634,7 @@ func TestAuthHandler_Refresh_NoCookie(t *testing.T) {
 func TestAuthHandler_Refresh_InvalidToken(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	r := gin.New()
@@ -441,12 +658,76 @@ func TestAuthHandler_Refresh_InvalidToken(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Refresh_RotatesCookie(t *testing.T) {
+	userRepo := newMockAuthUserRepoForHandler()
+	tokenRepo := newMockRefreshTokenRepoForHandler()
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
+	handler := NewAuthHandler(authService)
+
+	user := &models.User{Email: "refresh@example.com", DisplayName: "Refresh User"}
+	_ = user.HashPassword("password123456")
+	_ = userRepo.Create(user)
+	_, _, refreshToken, err := authService.Login(&models.LoginInput{Email: "refresh@example.com", Password: "password123456"}, models.SessionDevice{})
+	if err != nil {
+		t.Fatalf("failed to login: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/refresh", handler.Refresh)
+	refresh := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/refresh", nil)
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: token})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := refresh(refreshToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var rotated string
+	var hasAccessToken bool
+	for _, cookie := range w.Result().Cookies() {
+		switch cookie.Name {
+		case "access_token":
+			hasAccessToken = cookie.Value != ""
+		case "refresh_token":
+			rotated = cookie.Value
+			if !cookie.HttpOnly || cookie.MaxAge != 7*24*60*60 {
+				t.Errorf("unexpected refresh_token cookie: %+v", cookie)
+			}
+		}
+	}
+	if !hasAccessToken {
+		t.Error("access_token cookie not set")
+	}
+	if rotated == "" || rotated == refreshToken {
+		t.Fatalf("expected rotated refresh token cookie, got %q", rotated)
+	}
+
+	// ローテーション済みのトークンの再利用は 401 とし、Cookie を削除する
+	w = refresh(refreshToken)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 on reuse, got %d", w.Code)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			t.Errorf("expected cookie %s to be cleared, got %+v", cookie.Name, cookie)
+		}
+	}
+	// ファミリーごと無効化されるため、ローテーション後のトークンも使えない
+	if w := refresh(rotated); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 for revoked family, got %d", w.Code)
+	}
+}
+
 func TestAuthHandler_Refresh_InternalError(t *testing.T) {
 	userRepo := newMockAuthUserRepoForHandler()
 	tokenRepo := newMockRefreshTokenRepoForHandler()
 	// FindByTokenHash が内部エラーを返すよう設定
 	tokenRepo.findErr = errors.New("db connection error")
-	authService := services.NewAuthService(userRepo, tokenRepo)
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
 	handler := NewAuthHandler(authService)
 
 	r := gin.New()
@@ -475,3 +756,318 @@ func TestAuthHandler_Refresh_InternalError(t *testing.T) {
 		t.Errorf("expected error '%s', got '%s'", models.ErrCodeInternalServer, response.Error)
 	}
 }
+
+func TestAuthHandler_Sessions(t *testing.T) {
+	userRepo := newMockAuthUserRepoForHandler()
+	tokenRepo := newMockRefreshTokenRepoForHandler()
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
+	handler := NewAuthHandler(authService)
+
+	user := &models.User{Email: "sessions@example.com", DisplayName: "Sessions User"}
+	_ = user.HashPassword("password123456")
+	_ = userRepo.Create(user)
+	login := func(userAgent string) *auth.Claims {
+		t.Helper()
+		_, accessToken, _, err := authService.Login(&models.LoginInput{Email: "sessions@example.com", Password: "password123456"}, models.SessionDevice{UserAgent: userAgent})
+		if err != nil {
+			t.Fatalf("failed to login: %v", err)
+		}
+		claims, err := auth.ValidateToken(accessToken)
+		if err != nil {
+			t.Fatalf("failed to validate token: %v", err)
+		}
+		return claims
+	}
+	current := login("Firefox")
+	other := login("iPhone")
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", user.ID)
+		c.Set("token_claims", current)
+		c.Next()
+	})
+	r.GET("/auth/sessions", handler.ListSessions)
+	r.DELETE("/auth/sessions/:id", handler.RevokeSession)
+	r.DELETE("/auth/sessions", handler.RevokeOtherSessions)
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+	listSessions := func() []models.Session {
+		t.Helper()
+		w := serve(http.MethodGet, "/auth/sessions")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		var response models.SessionsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return response.Sessions
+	}
+
+	sessions := listSessions()
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.Current != (session.ID == current.SessionID) {
+			t.Errorf("unexpected current flag: %+v", session)
+		}
+	}
+
+	if w := serve(http.MethodDelete, "/auth/sessions/unknown"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+	w := serve(http.MethodDelete, "/auth/sessions/"+other.SessionID)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	// 他の端末をログアウトさせた場合は Cookie を削除しない
+	if cookies := w.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("expected no cookies to be cleared, got %+v", cookies)
+	}
+
+	login("iPad")
+	if w := serve(http.MethodDelete, "/auth/sessions"); w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	if sessions := listSessions(); len(sessions) != 1 || sessions[0].ID != current.SessionID {
+		t.Fatalf("expected only current session to remain, got %+v", sessions)
+	}
+
+	// この端末をログアウトさせた場合は Cookie も削除する
+	w = serve(http.MethodDelete, "/auth/sessions/"+current.SessionID)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			t.Errorf("expected cookie %s to be cleared, got %+v", cookie.Name, cookie)
+		}
+	}
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	userRepo := newMockAuthUserRepoForHandler()
+	tokenRepo := newMockRefreshTokenRepoForHandler()
+	authService := newAuthServiceForHandler(userRepo, tokenRepo)
+	handler := NewAuthHandler(authService)
+
+	user := &models.User{Email: "change@example.com", DisplayName: "Change User"}
+	_ = user.HashPassword("password123456")
+	_ = userRepo.Create(user)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", user.ID)
+		c.Next()
+	})
+	r.POST("/auth/password", handler.ChangePassword)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "異常系: 新しいパスワードが12文字未満",
+			body:       `{"current_password":"password123456","new_password":"short"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  models.ErrCodeValidationFailed,
+		},
+		{
+			name:       "異常系: 現在のパスワードが未入力",
+			body:       `{"new_password":"new-password-123"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  models.ErrCodeValidationFailed,
+		},
+		{
+			name:       "異常系: 現在のパスワードが誤っている",
+			body:       `{"current_password":"wrong-password","new_password":"new-password-123"}`,
+			wantStatus: http.StatusForbidden,
+			wantError:  models.ErrCodeIncorrectPassword,
+		},
+		{
+			name:       "正常系: パスワードを変更",
+			body:       `{"current_password":"password123456","new_password":"new-password-123"}`,
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/auth/password", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantError != "" {
+				var response map[string]string
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if response["error"] != tt.wantError {
+					t.Errorf("expected error %q, got %q", tt.wantError, response["error"])
+				}
+				return
+			}
+			// トークンバージョンを進めたため、この端末には新しいトークンを設定する
+			names := map[string]bool{}
+			for _, cookie := range w.Result().Cookies() {
+				names[cookie.Name] = cookie.Value != ""
+			}
+			if !names["access_token"] || !names["refresh_token"] {
+				t.Errorf("expected reissued token cookies, got %v", names)
+			}
+		})
+	}
+
+	if err := user.CheckPassword("new-password-123"); err != nil {
+		t.Errorf("expected password to be changed, got %v", err)
+	}
+}
+
+// stubMFAVerifier はテスト用の2段階認証（userID のユーザーだけ有効で、code だけを受け付ける）
+type stubMFAVerifier struct {
+	userID int
+	code   string
+}
+
+func (s *stubMFAVerifier) IsEnabled(userID int) (bool, error) {
+	return userID == s.userID, nil
+}
+
+func (s *stubMFAVerifier) VerifyLoginCode(userID int, code string) error {
+	if userID != s.userID || code != s.code {
+		return services.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func TestAuthHandler_LoginMFA(t *testing.T) {
+	userRepo := newMockAuthUserRepoForHandler()
+	tokenRepo := newMockRefreshTokenRepoForHandler()
+	user := &models.User{Email: "mfa@example.com", DisplayName: "MFA User"}
+	_ = user.HashPassword("password123456")
+	_ = userRepo.Create(user)
+	authService := services.NewAuthService(userRepo, tokenRepo, newTokenRevocationStoreForHandler(), nopVerificationSenderForHandler{}, &stubMFAVerifier{userID: user.ID, code: "123456"}, nopLoginLimiterForHandler{})
+	handler := NewAuthHandler(authService)
+
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	r.POST("/login/mfa", handler.LoginMFA)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// パスワードが正しい場合は Cookie を発行せずにチャレンジトークンを返す
+	login := func(t *testing.T) string {
+		t.Helper()
+		w := post("/login", models.LoginInput{Email: "mfa@example.com", Password: "password123456"})
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("expected status 202, got %d", w.Code)
+		}
+		if len(w.Result().Cookies()) != 0 {
+			t.Fatal("expected no cookies before two-factor authentication")
+		}
+		var challenge models.MFAChallengeResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &challenge); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if !challenge.MFARequired || challenge.ChallengeToken == "" {
+			t.Fatalf("unexpected challenge response: %+v", challenge)
+		}
+		return challenge.ChallengeToken
+	}
+
+	// チャレンジトークンは一度しか使えないため、ChallengeToken が空のケースはログインし直したトークンを使う
+	tests := []struct {
+		name       string
+		input      models.MFALoginInput
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "異常系: コードが誤っている",
+			input:      models.MFALoginInput{Code: "000000"},
+			wantStatus: http.StatusUnauthorized,
+			wantError:  models.ErrCodeInvalidMFACode,
+		},
+		{
+			name:       "異常系: チャレンジトークンが不正",
+			input:      models.MFALoginInput{ChallengeToken: "invalid", Code: "123456"},
+			wantStatus: http.StatusUnauthorized,
+			wantError:  models.ErrCodeUnauthorized,
+		},
+		{
+			name:       "正常系: コードでログインが完了する",
+			input:      models.MFALoginInput{Code: "123456"},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.input.ChallengeToken == "" {
+				tt.input.ChallengeToken = login(t)
+			}
+			w := post("/login/mfa", tt.input)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantError != "" {
+				var response map[string]string
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if response["error"] != tt.wantError {
+					t.Errorf("expected error %q, got %q", tt.wantError, response["error"])
+				}
+				return
+			}
+			names := map[string]bool{}
+			for _, cookie := range w.Result().Cookies() {
+				names[cookie.Name] = true
+			}
+			if !names["access_token"] || !names["refresh_token"] {
+				t.Errorf("expected token cookies, got %v", names)
+			}
+		})
+	}
+
+	t.Run("異常系: コードが未入力", func(t *testing.T) {
+		w := post("/login/mfa", models.MFALoginInput{ChallengeToken: login(t)})
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("異常系: コードを誤ったチャレンジトークンは再び使えない", func(t *testing.T) {
+		token := login(t)
+		if w := post("/login/mfa", models.MFALoginInput{ChallengeToken: token, Code: "000000"}); w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", w.Code)
+		}
+		w := post("/login/mfa", models.MFALoginInput{ChallengeToken: token, Code: "123456"})
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d", w.Code)
+		}
+		var response map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if response["error"] != models.ErrCodeUnauthorized {
+			t.Errorf("expected error %q, got %q", models.ErrCodeUnauthorized, response["error"])
+		}
+	})
+}
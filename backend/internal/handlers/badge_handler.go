@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BadgeServiceInterface は BadgeService のインターフェース（テスト用）
+type BadgeServiceInterface interface {
+	GetUserBadges(userID int) ([]models.UserBadge, error)
+	GetUnseenBadges(userID int) ([]models.UserBadge, error)
+	MarkBadgesSeen(userID int, codes []string) error
+}
+
+// BadgeHandler はバッジ関連のHTTPリクエストを処理する
+type BadgeHandler struct {
+	badgeService BadgeServiceInterface
+}
+
+// NewBadgeHandler は新しい BadgeHandler を作成する
+func NewBadgeHandler(badgeService BadgeServiceInterface) *BadgeHandler {
+	return &BadgeHandler{
+		badgeService: badgeService,
+	}
+}
+
+// GetUserBadges は GET /api/v1/users/:id/badges を処理する
+// @Summary ユーザーのバッジ一覧取得
+// @Description 指定されたユーザーが獲得したバッジを獲得日時の古い順に取得します（総数付き）
+// @Tags badges
+// @Accept json
+// @Produce json
+// @Param id path int true "ユーザーID"
+// @Success 200 {object} models.UserBadgesResponse "バッジ一覧と総数"
+// @Failure 400 {object} models.ValidationError "無効なユーザーID"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/badges [get]
+func (h *BadgeHandler) GetUserBadges(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	badges, err := h.badgeService.GetUserBadges(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to get user badges", "handler", "BadgeHandler", "method", "GetUserBadges", "user_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.UserBadgesResponse{
+		Badges: badges,
+		Total:  len(badges),
+	})
+}
+
+// GetMyUnseenBadges は GET /api/v1/users/me/badges/unseen を処理する
+// @Summary 未確認バッジ取得
+// @Description 認証ユーザーが新たに獲得し、まだ確認していないバッジを取得します
+// @Description 取得しただけでは確認済みにならないため、表示した後に POST /users/me/badges/seen で確認済みにしてください
+// @Tags badges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UserBadgesResponse "未確認バッジ一覧と総数"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/badges/unseen [get]
+func (h *BadgeHandler) GetMyUnseenBadges(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "BadgeHandler", "method", "GetMyUnseenBadges")
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	badges, err := h.badgeService.GetUnseenBadges(userID)
+	if err != nil {
+		logging.L.Error("failed to get unseen badges", "handler", "BadgeHandler", "method", "GetMyUnseenBadges", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.UserBadgesResponse{
+		Badges: badges,
+		Total:  len(badges),
+	})
+}
+
+// MarkMyBadgesSeen は POST /api/v1/users/me/badges/seen を処理する
+// @Summary バッジを確認済みにする
+// @Description 認証ユーザーの指定されたバッジを確認済みにし、以降の未確認バッジ取得では返されないようにします
+// @Description 獲得していない・確認済みのバッジは無視されます
+// @Tags badges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.MarkBadgesSeenInput true "確認済みにするバッジ"
+// @Success 204 "確認済みにしました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/badges/seen [post]
+func (h *BadgeHandler) MarkMyBadgesSeen(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "BadgeHandler", "method", "MarkMyBadgesSeen")
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	var input models.MarkBadgesSeenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "BadgeHandler", "method", "MarkMyBadgesSeen", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.badgeService.MarkBadgesSeen(userID, input.Codes); err != nil {
+		logging.L.Error("failed to mark badges as seen", "handler", "BadgeHandler", "method", "MarkMyBadgesSeen", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
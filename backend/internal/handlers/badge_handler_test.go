@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBadgeService はテスト用の BadgeService モック
+type mockBadgeService struct {
+	getUserBadgesFunc   func(userID int) ([]models.UserBadge, error)
+	getUnseenBadgesFunc func(userID int) ([]models.UserBadge, error)
+	markBadgesSeenFunc  func(userID int, codes []string) error
+}
+
+func (m *mockBadgeService) GetUserBadges(userID int) ([]models.UserBadge, error) {
+	if m.getUserBadgesFunc != nil {
+		return m.getUserBadgesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockBadgeService) GetUnseenBadges(userID int) ([]models.UserBadge, error) {
+	if m.getUnseenBadgesFunc != nil {
+		return m.getUnseenBadgesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockBadgeService) MarkBadgesSeen(userID int, codes []string) error {
+	if m.markBadgesSeenFunc != nil {
+		return m.markBadgesSeenFunc(userID, codes)
+	}
+	return nil
+}
+
+func TestGetUserBadges_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBadgeHandler(&mockBadgeService{
+		getUserBadgesFunc: func(userID int) ([]models.UserBadge, error) {
+			return []models.UserBadge{{Code: models.BadgeFirstPost, Name: "はじめての一服", AwardedAt: time.Now()}}, nil
+		},
+	})
+	router := gin.New()
+	router.GET("/users/:id/badges", handler.GetUserBadges)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1/badges", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response models.UserBadgesResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, models.BadgeFirstPost, response.Badges[0].Code)
+}
+
+func TestGetUserBadges_InvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBadgeHandler(&mockBadgeService{})
+	router := gin.New()
+	router.GET("/users/:id/badges", handler.GetUserBadges)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/abc/badges", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetUserBadges_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBadgeHandler(&mockBadgeService{
+		getUserBadgesFunc: func(userID int) ([]models.UserBadge, error) {
+			return nil, repositories.ErrUserNotFound
+		},
+	})
+	router := gin.New()
+	router.GET("/users/:id/badges", handler.GetUserBadges)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/999/badges", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetMyUnseenBadges_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var calledWith int
+	handler := NewBadgeHandler(&mockBadgeService{
+		getUnseenBadgesFunc: func(userID int) ([]models.UserBadge, error) {
+			calledWith = userID
+			return []models.UserBadge{}, nil
+		},
+	})
+	router := gin.New()
+	router.GET("/users/me/badges/unseen", func(c *gin.Context) {
+		c.Set("user_id", 7)
+		handler.GetMyUnseenBadges(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/badges/unseen", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 7, calledWith)
+	var response models.UserBadgesResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, response.Total)
+	assert.NotNil(t, response.Badges)
+}
+
+func TestGetMyUnseenBadges_NoAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBadgeHandler(&mockBadgeService{})
+	router := gin.New()
+	router.GET("/users/me/badges/unseen", handler.GetMyUnseenBadges)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/badges/unseen", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestGetMyUnseenBadges_ServerError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBadgeHandler(&mockBadgeService{
+		getUnseenBadgesFunc: func(userID int) ([]models.UserBadge, error) {
+			return nil, assert.AnError
+		},
+	})
+	router := gin.New()
+	router.GET("/users/me/badges/unseen", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		handler.GetMyUnseenBadges(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/badges/unseen", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestMarkMyBadgesSeen_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var calledWith int
+	var calledCodes []string
+	handler := NewBadgeHandler(&mockBadgeService{
+		markBadgesSeenFunc: func(userID int, codes []string) error {
+			calledWith = userID
+			calledCodes = codes
+			return nil
+		},
+	})
+	router := gin.New()
+	router.POST("/users/me/badges/seen", func(c *gin.Context) {
+		c.Set("user_id", 7)
+		handler.MarkMyBadgesSeen(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users/me/badges/seen", strings.NewReader(`{"codes":["first_post","popular"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 7, calledWith)
+	assert.Equal(t, []string{models.BadgeFirstPost, models.BadgePopular}, calledCodes)
+}
+
+func TestMarkMyBadgesSeen_InvalidBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBadgeHandler(&mockBadgeService{
+		markBadgesSeenFunc: func(userID int, codes []string) error {
+			t.Fatal("MarkBadgesSeen should not be called")
+			return nil
+		},
+	})
+	router := gin.New()
+	router.POST("/users/me/badges/seen", func(c *gin.Context) {
+		c.Set("user_id", 7)
+		handler.MarkMyBadgesSeen(c)
+	})
+
+	for _, body := range []string{`{}`, `{"codes":[]}`, `{"codes":[""]}`} {
+		req := httptest.NewRequest(http.MethodPost, "/users/me/badges/seen", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestMarkMyBadgesSeen_NoAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBadgeHandler(&mockBadgeService{})
+	router := gin.New()
+	router.POST("/users/me/badges/seen", handler.MarkMyBadgesSeen)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/me/badges/seen", strings.NewReader(`{"codes":["first_post"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollectionServiceInterface は CollectionService のインターフェース（テスト用）
+type CollectionServiceInterface interface {
+	CreateCollection(userID int, input *models.CreateCollectionInput) (*models.Collection, error)
+	GetCollection(collectionID int, viewerID *int) (*models.Collection, error)
+	GetUserCollections(userID int, viewerID *int) ([]models.Collection, error)
+	UpdateCollection(userID, collectionID int, input *models.UpdateCollectionInput) (*models.Collection, error)
+	DeleteCollection(userID, collectionID int) error
+	AddItem(userID, collectionID, postID int) error
+	RemoveItem(userID, collectionID, postID int) error
+	ReorderItems(userID, collectionID int, postIDs []int) error
+}
+
+// CollectionHandler はコレクション関連のHTTPリクエストを処理する
+type CollectionHandler struct {
+	collectionService CollectionServiceInterface
+}
+
+// NewCollectionHandler は新しい CollectionHandler を作成する
+func NewCollectionHandler(collectionService CollectionServiceInterface) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *CollectionHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "CollectionHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// optionalUserID は認証済みの場合のみユーザーIDを返す。コンテキストの型が不正な場合はエラーレスポンスを書き込み false を返す
+func (h *CollectionHandler) optionalUserID(c *gin.Context, method string) (*int, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		return nil, true
+	}
+	uid, ok := v.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "CollectionHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return nil, false
+	}
+	return &uid, true
+}
+
+// respondError はサービス層のエラーをHTTPレスポンスに変換する
+func (h *CollectionHandler) respondError(c *gin.Context, method string, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrCollectionNotFound),
+		errors.Is(err, repositories.ErrPostNotFound),
+		errors.Is(err, repositories.ErrNotInCollection),
+		errors.Is(err, repositories.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+	case errors.Is(err, repositories.ErrForbidden):
+		c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeForbidden})
+	case errors.Is(err, repositories.ErrAlreadyInCollection):
+		c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeAlreadyInCollection})
+	case errors.Is(err, repositories.ErrCollectionItemsMismatch):
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+	default:
+		logging.L.Error("collection operation failed", "handler", "CollectionHandler", "method", method, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+	}
+}
+
+// CreateCollection は POST /api/v1/collections を処理する
+// @Summary コレクション作成
+// @Description 認証ユーザーのコレクションを作成します。is_public を省略した場合は公開コレクションになります
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateCollectionInput true "コレクション情報"
+// @Success 201 {object} models.Collection "作成されたコレクション"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /collections [post]
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "CreateCollection")
+	if !ok {
+		return
+	}
+
+	var input models.CreateCollectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "CollectionHandler", "method", "CreateCollection", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(userID, &input)
+	if err != nil {
+		h.respondError(c, "CreateCollection", err)
+		return
+	}
+	c.JSON(http.StatusCreated, collection)
+}
+
+// GetCollection は GET /api/v1/collections/:id を処理する
+// @Summary コレクション詳細取得
+// @Description 指定されたコレクションと収録投稿を並び順で取得します。非公開コレクションは作成者のみ取得できます。削除された投稿は含まれません
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "コレクションID"
+// @Success 200 {object} models.Collection "コレクションと収録投稿"
+// @Failure 400 {object} models.ValidationError "無効なコレクションID"
+// @Failure 404 {object} models.NotFoundError "コレクションが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /collections/{id} [get]
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	viewerID, ok := h.optionalUserID(c, "GetCollection")
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(id, viewerID)
+	if err != nil {
+		h.respondError(c, "GetCollection", err)
+		return
+	}
+	c.JSON(http.StatusOK, collection)
+}
+
+// GetUserCollections は GET /api/v1/users/:id/collections を処理する
+// @Summary ユーザーのコレクション一覧取得
+// @Description 指定されたユーザーのコレクション一覧を取得します（総数付き）。本人の場合のみ非公開コレクションを含みます
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path int true "ユーザーID"
+// @Success 200 {object} models.CollectionsResponse "コレクション一覧と総数"
+// @Failure 400 {object} models.ValidationError "無効なユーザーID"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/collections [get]
+func (h *CollectionHandler) GetUserCollections(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	viewerID, ok := h.optionalUserID(c, "GetUserCollections")
+	if !ok {
+		return
+	}
+
+	collections, err := h.collectionService.GetUserCollections(id, viewerID)
+	if err != nil {
+		h.respondError(c, "GetUserCollections", err)
+		return
+	}
+	c.JSON(http.StatusOK, models.CollectionsResponse{
+		Collections: collections,
+		Total:       len(collections),
+	})
+}
+
+// UpdateCollection は PATCH /api/v1/collections/:id を処理する
+// @Summary コレクション更新
+// @Description コレクションの名前・説明・公開設定を更新します（作成者のみ）。省略した項目は変更されません
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "コレクションID"
+// @Param request body models.UpdateCollectionInput true "更新するフィールド（省略可能）"
+// @Success 200 {object} models.Collection "更新後のコレクション"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "作成者ではありません"
+// @Failure 404 {object} models.NotFoundError "コレクションが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /collections/{id} [patch]
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "UpdateCollection")
+	if !ok {
+		return
+	}
+
+	var input models.UpdateCollectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "CollectionHandler", "method", "UpdateCollection", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	collection, err := h.collectionService.UpdateCollection(userID, id, &input)
+	if err != nil {
+		h.respondError(c, "UpdateCollection", err)
+		return
+	}
+	c.JSON(http.StatusOK, collection)
+}
+
+// DeleteCollection は DELETE /api/v1/collections/:id を処理する
+// @Summary コレクション削除
+// @Description コレクションを削除します（作成者のみ）。収録されていた投稿自体は削除されません
+// @Tags collections
+// @Security BearerAuth
+// @Param id path int true "コレクションID"
+// @Success 204 "削除成功"
+// @Failure 400 {object} models.ValidationError "無効なコレクションID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "作成者ではありません"
+// @Failure 404 {object} models.NotFoundError "コレクションが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /collections/{id} [delete]
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "DeleteCollection")
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(userID, id); err != nil {
+		h.respondError(c, "DeleteCollection", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddItem は POST /api/v1/collections/:id/items を処理する
+// @Summary コレクションに投稿を追加
+// @Description コレクションの末尾に投稿を追加します（作成者のみ）。他ユーザーの投稿も追加できます
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "コレクションID"
+// @Param request body models.AddCollectionItemInput true "追加する投稿"
+// @Success 204 "追加成功"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "作成者ではありません"
+// @Failure 404 {object} models.NotFoundError "コレクションまたは投稿が見つかりません"
+// @Failure 409 {object} models.ConflictError "すでに収録されています"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /collections/{id}/items [post]
+func (h *CollectionHandler) AddItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "AddItem")
+	if !ok {
+		return
+	}
+
+	var input models.AddCollectionItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "CollectionHandler", "method", "AddItem", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.collectionService.AddItem(userID, id, input.PostID); err != nil {
+		h.respondError(c, "AddItem", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveItem は DELETE /api/v1/collections/:id/items/:post_id を処理する
+// @Summary コレクションから投稿を削除
+// @Description コレクションから投稿を取り除きます（作成者のみ）
+// @Tags collections
+// @Security BearerAuth
+// @Param id path int true "コレクションID"
+// @Param post_id path int true "投稿ID"
+// @Success 204 "削除成功"
+// @Failure 400 {object} models.ValidationError "無効なID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "作成者ではありません"
+// @Failure 404 {object} models.NotFoundError "コレクションが見つからない、または投稿が収録されていません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /collections/{id}/items/{post_id} [delete]
+func (h *CollectionHandler) RemoveItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	postID, err := strconv.Atoi(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "RemoveItem")
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.RemoveItem(userID, id, postID); err != nil {
+		h.respondError(c, "RemoveItem", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderItems は PUT /api/v1/collections/:id/items/order を処理する
+// @Summary コレクション内の並び替え
+// @Description 指定した投稿IDの順序でコレクション内の並び順を更新します（作成者のみ）。現在収録されている投稿IDをすべて過不足なく指定してください
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "コレクションID"
+// @Param request body models.ReorderCollectionItemsInput true "新しい並び順"
+// @Success 204 "並び替え成功"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー（収録投稿と一致しない場合を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "作成者ではありません"
+// @Failure 404 {object} models.NotFoundError "コレクションが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /collections/{id}/items/order [put]
+func (h *CollectionHandler) ReorderItems(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "ReorderItems")
+	if !ok {
+		return
+	}
+
+	var input models.ReorderCollectionItemsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "CollectionHandler", "method", "ReorderItems", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.collectionService.ReorderItems(userID, id, input.PostIDs); err != nil {
+		h.respondError(c, "ReorderItems", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
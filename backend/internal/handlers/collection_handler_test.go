@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCollectionService はテスト用の CollectionService モック
+type mockCollectionService struct {
+	createCollectionFunc   func(userID int, input *models.CreateCollectionInput) (*models.Collection, error)
+	getCollectionFunc      func(collectionID int, viewerID *int) (*models.Collection, error)
+	getUserCollectionsFunc func(userID int, viewerID *int) ([]models.Collection, error)
+	updateCollectionFunc   func(userID, collectionID int, input *models.UpdateCollectionInput) (*models.Collection, error)
+	deleteCollectionFunc   func(userID, collectionID int) error
+	addItemFunc            func(userID, collectionID, postID int) error
+	removeItemFunc         func(userID, collectionID, postID int) error
+	reorderItemsFunc       func(userID, collectionID int, postIDs []int) error
+}
+
+func (m *mockCollectionService) CreateCollection(userID int, input *models.CreateCollectionInput) (*models.Collection, error) {
+	if m.createCollectionFunc != nil {
+		return m.createCollectionFunc(userID, input)
+	}
+	return &models.Collection{ID: 1, UserID: userID, Name: input.Name}, nil
+}
+
+func (m *mockCollectionService) GetCollection(collectionID int, viewerID *int) (*models.Collection, error) {
+	if m.getCollectionFunc != nil {
+		return m.getCollectionFunc(collectionID, viewerID)
+	}
+	return &models.Collection{ID: collectionID}, nil
+}
+
+func (m *mockCollectionService) GetUserCollections(userID int, viewerID *int) ([]models.Collection, error) {
+	if m.getUserCollectionsFunc != nil {
+		return m.getUserCollectionsFunc(userID, viewerID)
+	}
+	return nil, nil
+}
+
+func (m *mockCollectionService) UpdateCollection(userID, collectionID int, input *models.UpdateCollectionInput) (*models.Collection, error) {
+	if m.updateCollectionFunc != nil {
+		return m.updateCollectionFunc(userID, collectionID, input)
+	}
+	return &models.Collection{ID: collectionID, UserID: userID}, nil
+}
+
+func (m *mockCollectionService) DeleteCollection(userID, collectionID int) error {
+	if m.deleteCollectionFunc != nil {
+		return m.deleteCollectionFunc(userID, collectionID)
+	}
+	return nil
+}
+
+func (m *mockCollectionService) AddItem(userID, collectionID, postID int) error {
+	if m.addItemFunc != nil {
+		return m.addItemFunc(userID, collectionID, postID)
+	}
+	return nil
+}
+
+func (m *mockCollectionService) RemoveItem(userID, collectionID, postID int) error {
+	if m.removeItemFunc != nil {
+		return m.removeItemFunc(userID, collectionID, postID)
+	}
+	return nil
+}
+
+func (m *mockCollectionService) ReorderItems(userID, collectionID int, postIDs []int) error {
+	if m.reorderItemsFunc != nil {
+		return m.reorderItemsFunc(userID, collectionID, postIDs)
+	}
+	return nil
+}
+
+// withUserID は認証済みユーザーとしてコンテキストに user_id を設定するミドルウェア
+func withUserID(userID int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
+
+func TestCreateCollection_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{})
+	router := gin.New()
+	router.POST("/collections", withUserID(1), handler.CreateCollection)
+
+	req := httptest.NewRequest(http.MethodPost, "/collections", bytes.NewBufferString(`{"name":"お気に入り"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response models.Collection
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "お気に入り", response.Name)
+	assert.Equal(t, 1, response.UserID)
+}
+
+func TestCreateCollection_ValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{})
+	router := gin.New()
+	router.POST("/collections", withUserID(1), handler.CreateCollection)
+
+	req := httptest.NewRequest(http.MethodPost, "/collections", bytes.NewBufferString(`{"name":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCreateCollection_Unauthorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{})
+	router := gin.New()
+	router.POST("/collections", handler.CreateCollection)
+
+	req := httptest.NewRequest(http.MethodPost, "/collections", bytes.NewBufferString(`{"name":"お気に入り"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestGetCollection_PassesViewer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotViewer *int
+	handler := NewCollectionHandler(&mockCollectionService{
+		getCollectionFunc: func(collectionID int, viewerID *int) (*models.Collection, error) {
+			gotViewer = viewerID
+			return &models.Collection{ID: collectionID, Posts: []models.Post{{ID: 3}, {ID: 1}}}, nil
+		},
+	})
+	router := gin.New()
+	router.GET("/collections/:id", withUserID(7), handler.GetCollection)
+
+	req := httptest.NewRequest(http.MethodGet, "/collections/5", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.NotNil(t, gotViewer) {
+		assert.Equal(t, 7, *gotViewer)
+	}
+	var response models.Collection
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, response.Posts[0].ID)
+}
+
+func TestGetCollection_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{
+		getCollectionFunc: func(collectionID int, viewerID *int) (*models.Collection, error) {
+			return nil, repositories.ErrCollectionNotFound
+		},
+	})
+	router := gin.New()
+	router.GET("/collections/:id", handler.GetCollection)
+
+	req := httptest.NewRequest(http.MethodGet, "/collections/5", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetUserCollections_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{
+		getUserCollectionsFunc: func(userID int, viewerID *int) ([]models.Collection, error) {
+			return []models.Collection{{ID: 1, UserID: userID}, {ID: 2, UserID: userID}}, nil
+		},
+	})
+	router := gin.New()
+	router.GET("/users/:id/collections", handler.GetUserCollections)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1/collections", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response models.CollectionsResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Total)
+}
+
+func TestDeleteCollection_Forbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{
+		deleteCollectionFunc: func(userID, collectionID int) error {
+			return repositories.ErrForbidden
+		},
+	})
+	router := gin.New()
+	router.DELETE("/collections/:id", withUserID(2), handler.DeleteCollection)
+
+	req := httptest.NewRequest(http.MethodDelete, "/collections/1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAddCollectionItem_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{
+		addItemFunc: func(userID, collectionID, postID int) error {
+			return repositories.ErrAlreadyInCollection
+		},
+	})
+	router := gin.New()
+	router.POST("/collections/:id/items", withUserID(1), handler.AddItem)
+
+	req := httptest.NewRequest(http.MethodPost, "/collections/1/items", bytes.NewBufferString(`{"post_id":10}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var response models.ConflictError
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ErrCodeAlreadyInCollection, response.Error)
+}
+
+func TestRemoveCollectionItem_NotInCollection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCollectionHandler(&mockCollectionService{
+		removeItemFunc: func(userID, collectionID, postID int) error {
+			return repositories.ErrNotInCollection
+		},
+	})
+	router := gin.New()
+	router.DELETE("/collections/:id/items/:post_id", withUserID(1), handler.RemoveItem)
+
+	req := httptest.NewRequest(http.MethodDelete, "/collections/1/items/10", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestReorderCollectionItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotIDs []int
+	handler := NewCollectionHandler(&mockCollectionService{
+		reorderItemsFunc: func(userID, collectionID int, postIDs []int) error {
+			gotIDs = postIDs
+			if len(postIDs) != 3 {
+				return repositories.ErrCollectionItemsMismatch
+			}
+			return nil
+		},
+	})
+	router := gin.New()
+	router.PUT("/collections/:id/items/order", withUserID(1), handler.ReorderItems)
+
+	req := httptest.NewRequest(http.MethodPut, "/collections/1/items/order", bytes.NewBufferString(`{"post_ids":[3,1,2]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []int{3, 1, 2}, gotIDs)
+
+	req = httptest.NewRequest(http.MethodPut, "/collections/1/items/order", bytes.NewBufferString(`{"post_ids":[3,1]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
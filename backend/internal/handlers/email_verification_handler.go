@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationServiceInterface は EmailVerificationService のインターフェース（テスト用）
+type EmailVerificationServiceInterface interface {
+	Verify(token string) error
+	ResendVerification(userID int) error
+}
+
+// EmailVerificationHandler はメールアドレスの確認関連のHTTPリクエストを処理する
+type EmailVerificationHandler struct {
+	emailVerificationService EmailVerificationServiceInterface
+}
+
+// NewEmailVerificationHandler は新しい EmailVerificationHandler を作成する
+func NewEmailVerificationHandler(emailVerificationService EmailVerificationServiceInterface) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		emailVerificationService: emailVerificationService,
+	}
+}
+
+// VerifyEmail godoc
+// @Summary メールアドレスの確認
+// @Description 登録時・再送時にメールで送ったリンクのトークンでメールアドレスを確認済みにする。リンクは24時間有効
+// @Description ログインしていない端末からも確認できる
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.VerifyEmailInput true "確認用トークン"
+// @Success 204 "確認しました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー・トークンが無効"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/verify-email [post]
+func (h *EmailVerificationHandler) VerifyEmail(c *gin.Context) {
+	var input models.VerifyEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "EmailVerificationHandler", "method", "VerifyEmail", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.emailVerificationService.Verify(input.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeInvalidToken})
+			return
+		}
+		logging.L.Error("failed to verify email", "handler", "EmailVerificationHandler", "method", "VerifyEmail", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerificationEmail godoc
+// @Summary 確認メールの再送
+// @Description ログイン中のユーザーのメールアドレスに確認用のリンクを送り直す
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 202 "受け付けました"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 409 {object} models.ConflictError "確認済み"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/verify-email/resend [post]
+func (h *EmailVerificationHandler) ResendVerificationEmail(c *gin.Context) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "EmailVerificationHandler", "method", "ResendVerificationEmail")
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	if err := h.emailVerificationService.ResendVerification(userID); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeAlreadyVerified})
+			return
+		}
+		logging.L.Error("failed to resend verification mail", "handler", "EmailVerificationHandler", "method", "ResendVerificationEmail", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockEmailVerificationService はテスト用の EmailVerificationService モック
+type mockEmailVerificationService struct {
+	verifyFunc             func(token string) error
+	resendVerificationFunc func(userID int) error
+}
+
+func (m *mockEmailVerificationService) Verify(token string) error {
+	if m.verifyFunc != nil {
+		return m.verifyFunc(token)
+	}
+	return nil
+}
+
+func (m *mockEmailVerificationService) ResendVerification(userID int) error {
+	if m.resendVerificationFunc != nil {
+		return m.resendVerificationFunc(userID)
+	}
+	return nil
+}
+
+func newEmailVerificationRouter(service *mockEmailVerificationService, userID interface{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewEmailVerificationHandler(service)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	r.POST("/auth/verify-email", handler.VerifyEmail)
+	r.POST("/auth/verify-email/resend", handler.ResendVerificationEmail)
+	return r
+}
+
+func TestVerifyEmail(t *testing.T) {
+	r := newEmailVerificationRouter(&mockEmailVerificationService{
+		verifyFunc: func(token string) error {
+			switch token {
+			case "valid":
+				return nil
+			case "broken":
+				return errors.New("db error")
+			}
+			return services.ErrInvalidVerificationToken
+		},
+	}, nil)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "正常系: 確認済みにする", body: `{"token":"valid"}`, wantStatus: http.StatusNoContent},
+		{name: "異常系: 無効なトークン", body: `{"token":"expired"}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeInvalidToken},
+		{name: "異常系: トークンが未入力", body: `{}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "異常系: サーバーエラー", body: `{"token":"broken"}`, wantStatus: http.StatusInternalServerError, wantError: models.ErrCodeInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/auth/verify-email", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var resp map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp["error"])
+			}
+		})
+	}
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	service := &mockEmailVerificationService{
+		resendVerificationFunc: func(userID int) error {
+			if userID == 2 {
+				return services.ErrEmailAlreadyVerified
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		userID     interface{}
+		wantStatus int
+		wantError  string
+	}{
+		{name: "正常系: 再送を受け付ける", userID: 1, wantStatus: http.StatusAccepted},
+		{name: "異常系: 確認済み", userID: 2, wantStatus: http.StatusConflict, wantError: models.ErrCodeAlreadyVerified},
+		{name: "異常系: 未認証", userID: nil, wantStatus: http.StatusUnauthorized, wantError: models.ErrCodeUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newEmailVerificationRouter(service, tt.userID).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/verify-email/resend", nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var resp map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp["error"])
+			}
+		})
+	}
+}
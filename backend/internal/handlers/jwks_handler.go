@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-shisha-backend/pkg/jwk"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSProvider はトークンの検証鍵の公開鍵を返すインターフェース（テスト用）
+type JWKSProvider interface {
+	JWKS() jwk.Set
+}
+
+// JWKSHandler はトークンの検証鍵の公開（/.well-known/jwks.json）を処理する
+// 他のサービスは共有シークレットなしに、ヘッダーの kid に一致する鍵でアクセストークンを検証できる
+type JWKSHandler struct {
+	keys JWKSProvider
+}
+
+// NewJWKSHandler は新しい JWKSHandler を作成する
+func NewJWKSHandler(keys JWKSProvider) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS はトークンの検証鍵の公開鍵を JSON Web Key Set で返す（API のベースパスの外に置くため Swagger には含めない）
+// 鍵のローテーションを早く反映するよう、キャッシュは5分までにする
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
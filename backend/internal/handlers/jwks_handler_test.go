@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/jwk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKSHandler_GetJWKS(t *testing.T) {
+	keys, err := auth.GenerateKeySet()
+	if err != nil {
+		t.Fatalf("GenerateKeySet failed: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", NewJWKSHandler(keys).GetJWKS)
+
+	t.Run("正常系: 署名鍵の公開鍵を kid 付きで返す", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+		var set jwk.Set
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		if assert.Len(t, set.Keys, 1) {
+			assert.Equal(t, keys.KeyID(), set.Keys[0].Kid)
+			assert.Equal(t, "OKP", set.Keys[0].Kty)
+			assert.Equal(t, "EdDSA", set.Keys[0].Alg)
+			_, err := set.Keys[0].PublicKey()
+			assert.NoError(t, err)
+		}
+	})
+}
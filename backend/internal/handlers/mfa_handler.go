@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAServiceInterface は MFAService のインターフェース（テスト用）
+type MFAServiceInterface interface {
+	Status(userID int) (*models.MFAStatus, error)
+	BeginEnrollment(userID int) (*models.TOTPEnrollment, error)
+	ConfirmEnrollment(userID int, code string) ([]string, error)
+	Disable(userID int, password string) error
+	RegenerateRecoveryCodes(userID int, password string) ([]string, error)
+}
+
+// MFAHandler は2段階認証の設定関連のHTTPリクエストを処理する
+type MFAHandler struct {
+	mfaService MFAServiceInterface
+}
+
+// NewMFAHandler は新しい MFAHandler を作成する
+func NewMFAHandler(mfaService MFAServiceInterface) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// GetStatus godoc
+// @Summary 2段階認証の状態
+// @Description ログイン中のユーザーの2段階認証が有効かどうかと、未使用のリカバリーコードの数を取得する
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.MFAStatus "2段階認証の状態"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/mfa [get]
+func (h *MFAHandler) GetStatus(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "GetStatus")
+	if !ok {
+		return
+	}
+	status, err := h.mfaService.Status(userID)
+	if err != nil {
+		logging.L.Error("failed to get mfa status", "handler", "MFAHandler", "method", "GetStatus", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// BeginTOTPEnrollment godoc
+// @Summary TOTP の登録開始
+// @Description 認証アプリに登録するシークレットと、QR コードにする otpauth URI を発行する
+// @Description POST /auth/mfa/totp/confirm で認証アプリに表示されたコードを確認するまで2段階認証は有効にならない。登録をやり直すと以前のシークレットは使えなくなる
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TOTPEnrollment "シークレットと otpauth URI"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 409 {object} models.ConflictError "2段階認証が有効"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/mfa/totp [post]
+func (h *MFAHandler) BeginTOTPEnrollment(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "BeginTOTPEnrollment")
+	if !ok {
+		return
+	}
+	enrollment, err := h.mfaService.BeginEnrollment(userID)
+	if err != nil {
+		if h.writeMFAStateError(c, err) {
+			return
+		}
+		logging.L.Error("failed to begin totp enrollment", "handler", "MFAHandler", "method", "BeginTOTPEnrollment", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTOTPEnrollment godoc
+// @Summary TOTP の登録確認
+// @Description 認証アプリに表示されたコードを確認して2段階認証を有効にし、リカバリーコードを発行する
+// @Description リカバリーコードはこのレスポンスでのみ返すため、ユーザーに保管してもらう
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.ConfirmTOTPInput true "認証アプリに表示されたコード"
+// @Success 200 {object} models.RecoveryCodesResponse "リカバリーコード"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー・コードが誤っている"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 409 {object} models.ConflictError "登録を開始していない・2段階認証が有効"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTPEnrollment(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "ConfirmTOTPEnrollment")
+	if !ok {
+		return
+	}
+	var input models.ConfirmTOTPInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "MFAHandler", "method", "ConfirmTOTPEnrollment", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(userID, input.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMFACode) {
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeInvalidMFACode})
+			return
+		}
+		if h.writeMFAStateError(c, err) {
+			return
+		}
+		logging.L.Error("failed to confirm totp enrollment", "handler", "MFAHandler", "method", "ConfirmTOTPEnrollment", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary 2段階認証の無効化
+// @Description パスワードを確認して2段階認証を無効にし、リカバリーコードを削除する
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.MFAPasswordInput true "現在のパスワード"
+// @Success 204 "無効にしました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "パスワードが誤っています"
+// @Failure 409 {object} models.ConflictError "2段階認証が有効でない"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "Disable")
+	if !ok {
+		return
+	}
+	var input models.MFAPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "MFAHandler", "method", "Disable", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.mfaService.Disable(userID, input.Password); err != nil {
+		if h.writeMFAStateError(c, err) {
+			return
+		}
+		logging.L.Error("failed to disable mfa", "handler", "MFAHandler", "method", "Disable", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary リカバリーコードの再発行
+// @Description パスワードを確認してリカバリーコードを発行し直す。それまでのリカバリーコードは使えなくなる
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.MFAPasswordInput true "現在のパスワード"
+// @Success 200 {object} models.RecoveryCodesResponse "リカバリーコード"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "パスワードが誤っています"
+// @Failure 409 {object} models.ConflictError "2段階認証が有効でない"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "RegenerateRecoveryCodes")
+	if !ok {
+		return
+	}
+	var input models.MFAPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "MFAHandler", "method", "RegenerateRecoveryCodes", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(userID, input.Password)
+	if err != nil {
+		if h.writeMFAStateError(c, err) {
+			return
+		}
+		logging.L.Error("failed to regenerate recovery codes", "handler", "MFAHandler", "method", "RegenerateRecoveryCodes", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *MFAHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "MFAHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeMFAStateError は2段階認証の状態・パスワードの確認によるエラーであれば対応するレスポンスを書き込み true を返す
+func (h *MFAHandler) writeMFAStateError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeMFAAlreadyEnabled})
+	case errors.Is(err, services.ErrMFANotEnabled):
+		c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeMFANotEnabled})
+	case errors.Is(err, services.ErrIncorrectPassword):
+		c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeIncorrectPassword})
+	default:
+		return false
+	}
+	return true
+}
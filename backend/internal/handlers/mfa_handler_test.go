@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockMFAService はテスト用の MFAService モック
+type mockMFAService struct {
+	statusFunc                  func(userID int) (*models.MFAStatus, error)
+	beginEnrollmentFunc         func(userID int) (*models.TOTPEnrollment, error)
+	confirmEnrollmentFunc       func(userID int, code string) ([]string, error)
+	disableFunc                 func(userID int, password string) error
+	regenerateRecoveryCodesFunc func(userID int, password string) ([]string, error)
+}
+
+func (m *mockMFAService) Status(userID int) (*models.MFAStatus, error) {
+	if m.statusFunc != nil {
+		return m.statusFunc(userID)
+	}
+	return &models.MFAStatus{}, nil
+}
+
+func (m *mockMFAService) BeginEnrollment(userID int) (*models.TOTPEnrollment, error) {
+	if m.beginEnrollmentFunc != nil {
+		return m.beginEnrollmentFunc(userID)
+	}
+	return &models.TOTPEnrollment{}, nil
+}
+
+func (m *mockMFAService) ConfirmEnrollment(userID int, code string) ([]string, error) {
+	if m.confirmEnrollmentFunc != nil {
+		return m.confirmEnrollmentFunc(userID, code)
+	}
+	return nil, nil
+}
+
+func (m *mockMFAService) Disable(userID int, password string) error {
+	if m.disableFunc != nil {
+		return m.disableFunc(userID, password)
+	}
+	return nil
+}
+
+func (m *mockMFAService) RegenerateRecoveryCodes(userID int, password string) ([]string, error) {
+	if m.regenerateRecoveryCodesFunc != nil {
+		return m.regenerateRecoveryCodesFunc(userID, password)
+	}
+	return nil, nil
+}
+
+func newMFARouter(service *mockMFAService, userID interface{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewMFAHandler(service)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	r.GET("/auth/mfa", handler.GetStatus)
+	r.POST("/auth/mfa/totp", handler.BeginTOTPEnrollment)
+	r.POST("/auth/mfa/totp/confirm", handler.ConfirmTOTPEnrollment)
+	r.POST("/auth/mfa/disable", handler.Disable)
+	r.POST("/auth/mfa/recovery-codes", handler.RegenerateRecoveryCodes)
+	return r
+}
+
+func TestMFAHandler(t *testing.T) {
+	service := &mockMFAService{
+		statusFunc: func(userID int) (*models.MFAStatus, error) {
+			return &models.MFAStatus{Enabled: true, RemainingRecoveryCodes: 8}, nil
+		},
+		beginEnrollmentFunc: func(userID int) (*models.TOTPEnrollment, error) {
+			if userID == 2 {
+				return nil, services.ErrMFAAlreadyEnabled
+			}
+			return &models.TOTPEnrollment{Secret: "JBSWY3DPEHPK3PXP", OTPAuthURI: "otpauth://totp/test"}, nil
+		},
+		confirmEnrollmentFunc: func(userID int, code string) ([]string, error) {
+			switch {
+			case userID == 3:
+				return nil, services.ErrMFANotEnabled
+			case code != "123456":
+				return nil, services.ErrInvalidMFACode
+			}
+			return []string{"AAAA-BBBB-CCCC"}, nil
+		},
+		disableFunc: func(userID int, password string) error {
+			switch {
+			case password != "password12345":
+				return services.ErrIncorrectPassword
+			case userID == 3:
+				return services.ErrMFANotEnabled
+			case userID == 4:
+				return errors.New("db error")
+			}
+			return nil
+		},
+		regenerateRecoveryCodesFunc: func(userID int, password string) ([]string, error) {
+			if password != "password12345" {
+				return nil, services.ErrIncorrectPassword
+			}
+			return []string{"DDDD-EEEE-FFFF"}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		userID     interface{}
+		wantStatus int
+		wantError  string
+		wantBody   string
+	}{
+		{name: "正常系: 状態を取得する", method: http.MethodGet, path: "/auth/mfa", userID: 1, wantStatus: http.StatusOK, wantBody: `"remaining_recovery_codes":8`},
+		{name: "異常系: 未認証", method: http.MethodGet, path: "/auth/mfa", userID: nil, wantStatus: http.StatusUnauthorized, wantError: models.ErrCodeUnauthorized},
+		{name: "正常系: 登録を開始する", method: http.MethodPost, path: "/auth/mfa/totp", userID: 1, wantStatus: http.StatusOK, wantBody: `"otpauth_uri":"otpauth://totp/test"`},
+		{name: "異常系: 2段階認証が有効", method: http.MethodPost, path: "/auth/mfa/totp", userID: 2, wantStatus: http.StatusConflict, wantError: models.ErrCodeMFAAlreadyEnabled},
+		{name: "正常系: 登録を確認する", method: http.MethodPost, path: "/auth/mfa/totp/confirm", body: `{"code":"123456"}`, userID: 1, wantStatus: http.StatusOK, wantBody: `"recovery_codes":["AAAA-BBBB-CCCC"]`},
+		{name: "異常系: コードが誤っている", method: http.MethodPost, path: "/auth/mfa/totp/confirm", body: `{"code":"000000"}`, userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeInvalidMFACode},
+		{name: "異常系: 登録を開始していない", method: http.MethodPost, path: "/auth/mfa/totp/confirm", body: `{"code":"123456"}`, userID: 3, wantStatus: http.StatusConflict, wantError: models.ErrCodeMFANotEnabled},
+		{name: "異常系: コードが未入力", method: http.MethodPost, path: "/auth/mfa/totp/confirm", body: `{}`, userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "正常系: 無効にする", method: http.MethodPost, path: "/auth/mfa/disable", body: `{"password":"password12345"}`, userID: 1, wantStatus: http.StatusNoContent},
+		{name: "異常系: 無効化のパスワードが誤っている", method: http.MethodPost, path: "/auth/mfa/disable", body: `{"password":"wrong"}`, userID: 1, wantStatus: http.StatusForbidden, wantError: models.ErrCodeIncorrectPassword},
+		{name: "異常系: 2段階認証が有効でない", method: http.MethodPost, path: "/auth/mfa/disable", body: `{"password":"password12345"}`, userID: 3, wantStatus: http.StatusConflict, wantError: models.ErrCodeMFANotEnabled},
+		{name: "異常系: サーバーエラー", method: http.MethodPost, path: "/auth/mfa/disable", body: `{"password":"password12345"}`, userID: 4, wantStatus: http.StatusInternalServerError, wantError: models.ErrCodeInternalServer},
+		{name: "正常系: リカバリーコードを再発行する", method: http.MethodPost, path: "/auth/mfa/recovery-codes", body: `{"password":"password12345"}`, userID: 1, wantStatus: http.StatusOK, wantBody: `"recovery_codes":["DDDD-EEEE-FFFF"]`},
+		{name: "異常系: 再発行のパスワードが誤っている", method: http.MethodPost, path: "/auth/mfa/recovery-codes", body: `{"password":"wrong"}`, userID: 1, wantStatus: http.StatusForbidden, wantError: models.ErrCodeIncorrectPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			newMFARouter(service, tt.userID).ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var resp map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp["error"])
+			}
+			if tt.wantBody != "" {
+				assert.Contains(t, w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
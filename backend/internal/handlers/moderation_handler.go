@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationServiceInterface は ModerationService のインターフェース（テスト用）
+type ModerationServiceInterface interface {
+	ReportPost(reporterID, postID int, input *models.CreateReportInput) (*models.Report, error)
+	ReportUser(reporterID, userID int, input *models.CreateReportInput) (*models.Report, error)
+	ListReports(query models.ReportListQuery) (*models.ReportsResponse, error)
+	ResolveReport(moderatorID, reportID int, input *models.ResolveReportInput) (*models.Report, error)
+	SuspendUser(moderatorID, userID int, input *models.SuspendUserInput) error
+	UnsuspendUser(moderatorID, userID int) error
+	ListActions(query models.ModerationActionListQuery) (*models.ModerationActionsResponse, error)
+}
+
+// ModerationHandler は通報とモデレーション関連のHTTPリクエストを処理する
+type ModerationHandler struct {
+	moderationService ModerationServiceInterface
+}
+
+// NewModerationHandler は新しい ModerationHandler を作成する
+func NewModerationHandler(moderationService ModerationServiceInterface) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *ModerationHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "ModerationHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeReportError は通報作成時のエラーをレスポンスに変換する
+func (h *ModerationHandler) writeReportError(c *gin.Context, method string, reporterID, targetID int, err error) {
+	switch {
+	case errors.Is(err, services.ErrCannotReportSelf):
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+	case errors.Is(err, repositories.ErrPostNotFound), errors.Is(err, repositories.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+	case errors.Is(err, repositories.ErrAlreadyReported):
+		c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeAlreadyReported})
+	default:
+		logging.L.Error("failed to create report", "handler", "ModerationHandler", "method", method, "reporter_id", reporterID, "target_id", targetID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+	}
+}
+
+// ReportPost は POST /api/v1/posts/:id/report を処理する
+// @Summary 投稿の通報
+// @Description 投稿を理由コードとともに通報します。通報はモデレーションキューに追加され、管理者が対応します
+// @Description 同じ投稿への未対応の通報がすでにある場合は 409 を返します
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "投稿ID"
+// @Param input body models.CreateReportInput true "通報の理由"
+// @Success 201 {object} models.Report "作成した通報"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー（自分の投稿の通報を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "投稿が見つかりません"
+// @Failure 409 {object} models.ConflictError "通報済み"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /posts/{id}/report [post]
+func (h *ModerationHandler) ReportPost(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "ReportPost")
+	if !ok {
+		return
+	}
+
+	var input models.CreateReportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "ModerationHandler", "method", "ReportPost", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	report, err := h.moderationService.ReportPost(userID, postID, &input)
+	if err != nil {
+		h.writeReportError(c, "ReportPost", userID, postID, err)
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// ReportUser は POST /api/v1/users/:id/report を処理する
+// @Summary ユーザーの通報
+// @Description ユーザーを理由コードとともに通報します。通報はモデレーションキューに追加され、管理者が対応します
+// @Description 同じユーザーへの未対応の通報がすでにある場合は 409 を返します
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Param input body models.CreateReportInput true "通報の理由"
+// @Success 201 {object} models.Report "作成した通報"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー（自分自身の通報を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 409 {object} models.ConflictError "通報済み"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/report [post]
+func (h *ModerationHandler) ReportUser(c *gin.Context) {
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "ReportUser")
+	if !ok {
+		return
+	}
+
+	var input models.CreateReportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "ModerationHandler", "method", "ReportUser", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	report, err := h.moderationService.ReportUser(userID, targetID, &input)
+	if err != nil {
+		h.writeReportError(c, "ReportUser", userID, targetID, err)
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// ListReports は GET /api/v1/admin/reports を処理する
+// @Summary モデレーションキュー取得（管理者・モデレーター）
+// @Description 通報を古い順に取得します。status を省略した場合は未対応の通報を返します
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "状態（open / dismissed / actioned、省略時は open）"
+// @Param limit query int false "取得件数（1〜100、省略時は20）"
+// @Param offset query int false "読み飛ばす件数"
+// @Success 200 {object} models.ReportsResponse "通報一覧"
+// @Failure 400 {object} models.ValidationError "無効なパラメータ"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "管理者・モデレーターではありません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /admin/reports [get]
+func (h *ModerationHandler) ListReports(c *gin.Context) {
+	var query models.ReportListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logging.L.Warn("invalid query parameters", "handler", "ModerationHandler", "method", "ListReports", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	response, err := h.moderationService.ListReports(query)
+	if err != nil {
+		logging.L.Error("failed to list reports", "handler", "ModerationHandler", "method", "ListReports", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ResolveReport は POST /api/v1/admin/reports/:id/resolve を処理する
+// @Summary 通報の対応（管理者・モデレーター）
+// @Description 通報を却下（dismiss）するか、投稿の非表示（hide_post）・ユーザーの利用停止（suspend_user）で対応します
+// @Description hide_post・suspend_user では同じ対象への未対応の通報もまとめて対応済みになり、操作はモデレーションの記録に残ります
+// @Description suspend_user では suspend_until で終了日時を指定でき（省略時は無期限）、note が利用停止の理由になります（省略時は通報の理由）
+// @Description ロールはトークンではなく現在のロールで確認するため、ロールを外されたユーザーはすぐに対応できなくなります
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "通報ID"
+// @Param input body models.ResolveReportInput true "対応内容"
+// @Success 200 {object} models.Report "対応した通報"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー（対象に適用できない操作・過去の終了日時・管理者の利用停止を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "管理者・モデレーターではありません"
+// @Failure 404 {object} models.NotFoundError "通報が見つかりません"
+// @Failure 409 {object} models.ConflictError "対応済みの通報"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /admin/reports/{id}/resolve [post]
+func (h *ModerationHandler) ResolveReport(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "ResolveReport")
+	if !ok {
+		return
+	}
+
+	var input models.ResolveReportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "ModerationHandler", "method", "ResolveReport", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	report, err := h.moderationService.ResolveReport(userID, reportID, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInvalidModerationAction),
+			errors.Is(err, repositories.ErrCannotSuspendAdmin),
+			errors.Is(err, services.ErrInvalidSuspensionEnd):
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		case errors.Is(err, repositories.ErrReportNotFound):
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+		case errors.Is(err, repositories.ErrReportAlreadyResolved):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeReportResolved})
+		default:
+			logging.L.Error("failed to resolve report", "handler", "ModerationHandler", "method", "ResolveReport", "user_id", userID, "report_id", reportID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// SuspendUser は POST /api/v1/admin/users/:id/suspension を処理する
+// @Summary ユーザーの利用停止（管理者・モデレーター）
+// @Description 通報によらずユーザーを利用停止にします。until を省略すると無期限になり、利用停止中に再度実行すると理由と終了日時を上書きします
+// @Description 利用停止中のユーザーはログイン・トークン更新ができず、発行済みのアクセストークン・リフレッシュトークンもすぐに無効になります
+// @Description 管理者は利用停止にできません
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Param input body models.SuspendUserInput true "利用停止の理由と終了日時"
+// @Success 204 "利用停止しました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー（自分自身・管理者・過去の終了日時を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "管理者・モデレーターではありません"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /admin/users/{id}/suspension [post]
+func (h *ModerationHandler) SuspendUser(c *gin.Context) {
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "SuspendUser")
+	if !ok {
+		return
+	}
+
+	var input models.SuspendUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "ModerationHandler", "method", "SuspendUser", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.moderationService.SuspendUser(userID, targetID, &input); err != nil {
+		switch {
+		case errors.Is(err, services.ErrCannotSuspendSelf),
+			errors.Is(err, services.ErrInvalidSuspensionEnd),
+			errors.Is(err, repositories.ErrCannotSuspendAdmin):
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		case errors.Is(err, repositories.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+		default:
+			logging.L.Error("failed to suspend user", "handler", "ModerationHandler", "method", "SuspendUser", "user_id", userID, "target_user_id", targetID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UnsuspendUser は DELETE /api/v1/admin/users/:id/suspension を処理する
+// @Summary ユーザーの利用停止解除（管理者・モデレーター）
+// @Description ユーザーの利用停止を解除します。利用停止前に発行されたトークンは無効のままのため、再度ログインが必要です
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Success 204 "利用停止を解除しました"
+// @Failure 400 {object} models.ValidationError "無効なパラメータ"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "管理者・モデレーターではありません"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 409 {object} models.ConflictError "利用停止されていません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /admin/users/{id}/suspension [delete]
+func (h *ModerationHandler) UnsuspendUser(c *gin.Context) {
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "UnsuspendUser")
+	if !ok {
+		return
+	}
+
+	if err := h.moderationService.UnsuspendUser(userID, targetID); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+		case errors.Is(err, repositories.ErrNotSuspended):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeNotSuspended})
+		default:
+			logging.L.Error("failed to unsuspend user", "handler", "ModerationHandler", "method", "UnsuspendUser", "user_id", userID, "target_user_id", targetID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListModerationActions は GET /api/v1/admin/moderation-actions を処理する
+// @Summary モデレーション操作の記録取得（管理者・モデレーター）
+// @Description 管理者・モデレーターによる通報の対応と、管理者によるロールの変更（change_role）を新しい順に取得します
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "取得件数（1〜100、省略時は20）"
+// @Param offset query int false "読み飛ばす件数"
+// @Success 200 {object} models.ModerationActionsResponse "操作の記録"
+// @Failure 400 {object} models.ValidationError "無効なパラメータ"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "管理者・モデレーターではありません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /admin/moderation-actions [get]
+func (h *ModerationHandler) ListModerationActions(c *gin.Context) {
+	var query models.ModerationActionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logging.L.Warn("invalid query parameters", "handler", "ModerationHandler", "method", "ListModerationActions", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	response, err := h.moderationService.ListActions(query)
+	if err != nil {
+		logging.L.Error("failed to list moderation actions", "handler", "ModerationHandler", "method", "ListModerationActions", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
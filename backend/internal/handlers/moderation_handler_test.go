@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockModerationService はテスト用の ModerationService モック
+type mockModerationService struct {
+	reportPostFunc    func(reporterID, postID int, input *models.CreateReportInput) (*models.Report, error)
+	reportUserFunc    func(reporterID, userID int, input *models.CreateReportInput) (*models.Report, error)
+	listReportsFunc   func(query models.ReportListQuery) (*models.ReportsResponse, error)
+	resolveReportFunc func(moderatorID, reportID int, input *models.ResolveReportInput) (*models.Report, error)
+	suspendUserFunc   func(moderatorID, userID int, input *models.SuspendUserInput) error
+	unsuspendUserFunc func(moderatorID, userID int) error
+	listActionsFunc   func(query models.ModerationActionListQuery) (*models.ModerationActionsResponse, error)
+}
+
+func (m *mockModerationService) ReportPost(reporterID, postID int, input *models.CreateReportInput) (*models.Report, error) {
+	if m.reportPostFunc != nil {
+		return m.reportPostFunc(reporterID, postID, input)
+	}
+	return &models.Report{}, nil
+}
+
+func (m *mockModerationService) ReportUser(reporterID, userID int, input *models.CreateReportInput) (*models.Report, error) {
+	if m.reportUserFunc != nil {
+		return m.reportUserFunc(reporterID, userID, input)
+	}
+	return &models.Report{}, nil
+}
+
+func (m *mockModerationService) ListReports(query models.ReportListQuery) (*models.ReportsResponse, error) {
+	if m.listReportsFunc != nil {
+		return m.listReportsFunc(query)
+	}
+	return &models.ReportsResponse{}, nil
+}
+
+func (m *mockModerationService) ResolveReport(moderatorID, reportID int, input *models.ResolveReportInput) (*models.Report, error) {
+	if m.resolveReportFunc != nil {
+		return m.resolveReportFunc(moderatorID, reportID, input)
+	}
+	return &models.Report{}, nil
+}
+
+func (m *mockModerationService) SuspendUser(moderatorID, userID int, input *models.SuspendUserInput) error {
+	if m.suspendUserFunc != nil {
+		return m.suspendUserFunc(moderatorID, userID, input)
+	}
+	return nil
+}
+
+func (m *mockModerationService) UnsuspendUser(moderatorID, userID int) error {
+	if m.unsuspendUserFunc != nil {
+		return m.unsuspendUserFunc(moderatorID, userID)
+	}
+	return nil
+}
+
+func (m *mockModerationService) ListActions(query models.ModerationActionListQuery) (*models.ModerationActionsResponse, error) {
+	if m.listActionsFunc != nil {
+		return m.listActionsFunc(query)
+	}
+	return &models.ModerationActionsResponse{}, nil
+}
+
+// newModerationRouter は本番と同じパス構成で通報・モデレーションのエンドポイントを登録したルーターを返す
+// 管理者の判定は RequireAdmin ミドルウェアで行うため、ここでは登録しない
+func newModerationRouter(handler *ModerationHandler, userID int) *gin.Engine {
+	router := gin.New()
+	auth := withUserID(userID)
+	router.POST("/posts/:id/report", auth, handler.ReportPost)
+	router.POST("/users/:id/report", auth, handler.ReportUser)
+	router.GET("/admin/reports", auth, handler.ListReports)
+	router.POST("/admin/reports/:id/resolve", auth, handler.ResolveReport)
+	router.POST("/admin/users/:id/suspension", auth, handler.SuspendUser)
+	router.DELETE("/admin/users/:id/suspension", auth, handler.UnsuspendUser)
+	router.GET("/admin/moderation-actions", auth, handler.ListModerationActions)
+	return router
+}
+
+func TestReportPost_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewModerationHandler(&mockModerationService{
+		reportPostFunc: func(reporterID, postID int, input *models.CreateReportInput) (*models.Report, error) {
+			assert.Equal(t, 2, reporterID)
+			return &models.Report{ID: 1, ReporterID: reporterID, TargetType: models.ReportTargetPost, PostID: &postID, TargetUserID: 1, Reason: input.Reason, Status: models.ReportStatusOpen}, nil
+		},
+	})
+	router := newModerationRouter(handler, 2)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/posts/10/report", strings.NewReader(`{"reason":"spam","comment":"宣伝"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var res models.Report
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 10, *res.PostID)
+	assert.Equal(t, models.ReportReasonSpam, res.Reason)
+}
+
+func TestReportPost_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{"invalid reason", "/posts/10/report", `{"reason":"boring"}`, nil, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"invalid id", "/posts/abc/report", `{"reason":"spam"}`, nil, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"own post", "/posts/10/report", `{"reason":"spam"}`, services.ErrCannotReportSelf, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"post not found", "/posts/10/report", `{"reason":"spam"}`, repositories.ErrPostNotFound, http.StatusNotFound, models.ErrCodeNotFound},
+		{"already reported", "/posts/10/report", `{"reason":"spam"}`, repositories.ErrAlreadyReported, http.StatusConflict, models.ErrCodeAlreadyReported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewModerationHandler(&mockModerationService{
+				reportPostFunc: func(reporterID, postID int, input *models.CreateReportInput) (*models.Report, error) {
+					return nil, tt.err
+				},
+			})
+			router := newModerationRouter(handler, 2)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantErr)
+		})
+	}
+}
+
+func TestReportUser_UserNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewModerationHandler(&mockModerationService{
+		reportUserFunc: func(reporterID, userID int, input *models.CreateReportInput) (*models.Report, error) {
+			return nil, repositories.ErrUserNotFound
+		},
+	})
+	router := newModerationRouter(handler, 2)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/99/report", strings.NewReader(`{"reason":"impersonation"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListReports_PassesQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewModerationHandler(&mockModerationService{
+		listReportsFunc: func(query models.ReportListQuery) (*models.ReportsResponse, error) {
+			assert.Equal(t, models.ReportStatusDismissed, query.Status)
+			assert.Equal(t, 5, query.Limit)
+			return &models.ReportsResponse{Reports: []models.Report{{ID: 1}}, Total: 1, Limit: 5}, nil
+		},
+	})
+	router := newModerationRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/reports?status=dismissed&limit=5", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/reports?status=unknown", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestResolveReport_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{"unknown action", `{"action":"ban"}`, nil, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"invalid for target", `{"action":"hide_post"}`, repositories.ErrInvalidModerationAction, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"not found", `{"action":"dismiss"}`, repositories.ErrReportNotFound, http.StatusNotFound, models.ErrCodeNotFound},
+		{"already resolved", `{"action":"dismiss"}`, repositories.ErrReportAlreadyResolved, http.StatusConflict, models.ErrCodeReportResolved},
+		{"suspend admin", `{"action":"suspend_user"}`, repositories.ErrCannotSuspendAdmin, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"past suspension end", `{"action":"suspend_user","suspend_until":"2020-01-01T00:00:00Z"}`, services.ErrInvalidSuspensionEnd, http.StatusBadRequest, models.ErrCodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewModerationHandler(&mockModerationService{
+				resolveReportFunc: func(moderatorID, reportID int, input *models.ResolveReportInput) (*models.Report, error) {
+					return nil, tt.err
+				},
+			})
+			router := newModerationRouter(handler, 1)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/reports/3/resolve", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantErr)
+		})
+	}
+}
+
+func TestResolveReport_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewModerationHandler(&mockModerationService{
+		resolveReportFunc: func(moderatorID, reportID int, input *models.ResolveReportInput) (*models.Report, error) {
+			assert.Equal(t, 1, moderatorID)
+			assert.Equal(t, 3, reportID)
+			return &models.Report{ID: reportID, Status: models.ReportStatusActioned, Resolution: input.Action, ResolvedBy: &moderatorID}, nil
+		},
+	})
+	router := newModerationRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/reports/3/resolve", strings.NewReader(`{"action":"suspend_user","note":"繰り返しのスパム"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.Report
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, models.ModerationActionSuspendUser, res.Resolution)
+}
+
+func TestSuspendUser_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewModerationHandler(&mockModerationService{
+		suspendUserFunc: func(moderatorID, userID int, input *models.SuspendUserInput) error {
+			assert.Equal(t, 1, moderatorID)
+			assert.Equal(t, 5, userID)
+			assert.Equal(t, "スパム行為のため", input.Reason)
+			if assert.NotNil(t, input.Until) {
+				assert.Equal(t, 2030, input.Until.Year())
+			}
+			return nil
+		},
+	})
+	router := newModerationRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/5/suspension", strings.NewReader(`{"reason":"スパム行為のため","until":"2030-01-01T00:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestSuspendUser_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{"missing reason", `{}`, nil, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"self", `{"reason":"x"}`, services.ErrCannotSuspendSelf, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"admin", `{"reason":"x"}`, repositories.ErrCannotSuspendAdmin, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"user not found", `{"reason":"x"}`, repositories.ErrUserNotFound, http.StatusNotFound, models.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewModerationHandler(&mockModerationService{
+				suspendUserFunc: func(moderatorID, userID int, input *models.SuspendUserInput) error {
+					return tt.err
+				},
+			})
+			router := newModerationRouter(handler, 1)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/5/suspension", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantErr)
+		})
+	}
+}
+
+func TestUnsuspendUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"user not found", repositories.ErrUserNotFound, http.StatusNotFound},
+		{"not suspended", repositories.ErrNotSuspended, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewModerationHandler(&mockModerationService{
+				unsuspendUserFunc: func(moderatorID, userID int) error {
+					assert.Equal(t, 5, userID)
+					return tt.err
+				},
+			})
+			router := newModerationRouter(handler, 1)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/users/5/suspension", nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
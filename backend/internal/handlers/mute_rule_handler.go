@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MuteRuleServiceInterface は MuteRuleService のインターフェース（テスト用）
+type MuteRuleServiceInterface interface {
+	ListRules(userID int) ([]models.MuteRule, error)
+	CreateRule(userID int, input *models.MuteRuleInput) (*models.MuteRule, error)
+	UpdateRule(userID, ruleID int, input *models.MuteRuleInput) (*models.MuteRule, error)
+	DeleteRule(userID, ruleID int) error
+}
+
+// MuteRuleHandler はミュートルール関連のHTTPリクエストを処理する
+type MuteRuleHandler struct {
+	muteRuleService MuteRuleServiceInterface
+}
+
+// NewMuteRuleHandler は新しい MuteRuleHandler を作成する
+func NewMuteRuleHandler(muteRuleService MuteRuleServiceInterface) *MuteRuleHandler {
+	return &MuteRuleHandler{
+		muteRuleService: muteRuleService,
+	}
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *MuteRuleHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "MuteRuleHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeRuleError はミュートルールの作成・更新・削除で発生したエラーをレスポンスに変換する
+func (h *MuteRuleHandler) writeRuleError(c *gin.Context, method string, userID int, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMuteRule),
+		errors.Is(err, services.ErrTooManyMuteRules),
+		errors.Is(err, repositories.ErrFlavorNotFound):
+		logging.L.Warn("invalid mute rule", "handler", "MuteRuleHandler", "method", method, "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+	case errors.Is(err, repositories.ErrMuteRuleNotFound):
+		c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+	default:
+		logging.L.Error("failed to process mute rule", "handler", "MuteRuleHandler", "method", method, "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+	}
+}
+
+// GetMyMuteRules は GET /api/v1/users/me/mute-rules を処理する
+// @Summary ミュートルール一覧取得
+// @Description 認証ユーザーのキーワード・フレーバーのミュートルールを新しい順に取得します（期限切れのルールを含みます）
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.MuteRulesResponse "ミュートルール一覧"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/mute-rules [get]
+func (h *MuteRuleHandler) GetMyMuteRules(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "GetMyMuteRules")
+	if !ok {
+		return
+	}
+
+	rules, err := h.muteRuleService.ListRules(userID)
+	if err != nil {
+		logging.L.Error("failed to list mute rules", "handler", "MuteRuleHandler", "method", "GetMyMuteRules", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.MuteRulesResponse{Rules: rules, Total: len(rules)})
+}
+
+// CreateMyMuteRule は POST /api/v1/users/me/mute-rules を処理する
+// @Summary ミュートルール作成
+// @Description キーワード（スライド本文の部分一致、大文字小文字を区別しない）またはフレーバーのミュートルールを作成します
+// @Description 有効なルールに一致するスライドを含む投稿は、投稿一覧・ユーザーの投稿一覧に表示されなくなります。expires_at を指定すると、その日時以降は適用されません
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.MuteRuleInput true "ミュートルール"
+// @Success 201 {object} models.MuteRule "作成したミュートルール"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー（内容の不正、存在しないフレーバー、過去の有効期限、登録数の上限）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/mute-rules [post]
+func (h *MuteRuleHandler) CreateMyMuteRule(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "CreateMyMuteRule")
+	if !ok {
+		return
+	}
+
+	var input models.MuteRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "MuteRuleHandler", "method", "CreateMyMuteRule", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	rule, err := h.muteRuleService.CreateRule(userID, &input)
+	if err != nil {
+		h.writeRuleError(c, "CreateMyMuteRule", userID, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateMyMuteRule は PUT /api/v1/users/me/mute-rules/:id を処理する
+// @Summary ミュートルール更新
+// @Description 認証ユーザーのミュートルールの内容を置き換えます（有効期限を省略すると無期限になります）
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ミュートルールID"
+// @Param input body models.MuteRuleInput true "ミュートルール"
+// @Success 200 {object} models.MuteRule "更新後のミュートルール"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "ミュートルールが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/mute-rules/{id} [put]
+func (h *MuteRuleHandler) UpdateMyMuteRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "UpdateMyMuteRule")
+	if !ok {
+		return
+	}
+
+	var input models.MuteRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "MuteRuleHandler", "method", "UpdateMyMuteRule", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	rule, err := h.muteRuleService.UpdateRule(userID, id, &input)
+	if err != nil {
+		h.writeRuleError(c, "UpdateMyMuteRule", userID, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteMyMuteRule は DELETE /api/v1/users/me/mute-rules/:id を処理する
+// @Summary ミュートルール削除
+// @Description 認証ユーザーのミュートルールを削除します
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "ミュートルールID"
+// @Success 204 "削除しました"
+// @Failure 400 {object} models.ValidationError "無効なミュートルールID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "ミュートルールが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/mute-rules/{id} [delete]
+func (h *MuteRuleHandler) DeleteMyMuteRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "DeleteMyMuteRule")
+	if !ok {
+		return
+	}
+
+	if err := h.muteRuleService.DeleteRule(userID, id); err != nil {
+		h.writeRuleError(c, "DeleteMyMuteRule", userID, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
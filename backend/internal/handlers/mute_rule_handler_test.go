@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockMuteRuleService はテスト用の MuteRuleService モック
+type mockMuteRuleService struct {
+	listRulesFunc  func(userID int) ([]models.MuteRule, error)
+	createRuleFunc func(userID int, input *models.MuteRuleInput) (*models.MuteRule, error)
+	updateRuleFunc func(userID, ruleID int, input *models.MuteRuleInput) (*models.MuteRule, error)
+	deleteRuleFunc func(userID, ruleID int) error
+}
+
+func (m *mockMuteRuleService) ListRules(userID int) ([]models.MuteRule, error) {
+	if m.listRulesFunc != nil {
+		return m.listRulesFunc(userID)
+	}
+	return []models.MuteRule{}, nil
+}
+
+func (m *mockMuteRuleService) CreateRule(userID int, input *models.MuteRuleInput) (*models.MuteRule, error) {
+	if m.createRuleFunc != nil {
+		return m.createRuleFunc(userID, input)
+	}
+	return &models.MuteRule{}, nil
+}
+
+func (m *mockMuteRuleService) UpdateRule(userID, ruleID int, input *models.MuteRuleInput) (*models.MuteRule, error) {
+	if m.updateRuleFunc != nil {
+		return m.updateRuleFunc(userID, ruleID, input)
+	}
+	return &models.MuteRule{}, nil
+}
+
+func (m *mockMuteRuleService) DeleteRule(userID, ruleID int) error {
+	if m.deleteRuleFunc != nil {
+		return m.deleteRuleFunc(userID, ruleID)
+	}
+	return nil
+}
+
+// newMuteRuleRouter は本番と同じパス構成でミュートルールのエンドポイントを登録したルーターを返す
+func newMuteRuleRouter(handler *MuteRuleHandler, userID int) *gin.Engine {
+	router := gin.New()
+	auth := withUserID(userID)
+	router.GET("/users/me/mute-rules", auth, handler.GetMyMuteRules)
+	router.POST("/users/me/mute-rules", auth, handler.CreateMyMuteRule)
+	router.PUT("/users/me/mute-rules/:id", auth, handler.UpdateMyMuteRule)
+	router.DELETE("/users/me/mute-rules/:id", auth, handler.DeleteMyMuteRule)
+	return router
+}
+
+func TestGetMyMuteRules_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewMuteRuleHandler(&mockMuteRuleService{
+		listRulesFunc: func(userID int) ([]models.MuteRule, error) {
+			return []models.MuteRule{{ID: 1, UserID: userID, Kind: models.MuteRuleKeyword, Keyword: "spoiler"}}, nil
+		},
+	})
+	router := newMuteRuleRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/me/mute-rules", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.MuteRulesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "spoiler", res.Rules[0].Keyword)
+}
+
+func TestCreateMyMuteRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewMuteRuleHandler(&mockMuteRuleService{
+		createRuleFunc: func(userID int, input *models.MuteRuleInput) (*models.MuteRule, error) {
+			switch input.Keyword {
+			case "invalid":
+				return nil, services.ErrInvalidMuteRule
+			case "full":
+				return nil, services.ErrTooManyMuteRules
+			}
+			if input.FlavorID != nil && *input.FlavorID == 999 {
+				return nil, repositories.ErrFlavorNotFound
+			}
+			return &models.MuteRule{ID: 1, UserID: userID, Kind: input.Kind, Keyword: input.Keyword, FlavorID: input.FlavorID}, nil
+		},
+	})
+	router := newMuteRuleRouter(handler, 1)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"keyword", `{"kind":"keyword","keyword":"spoiler"}`, http.StatusCreated},
+		{"flavor", `{"kind":"flavor","flavor_id":1,"expires_at":"2030-01-01T00:00:00Z"}`, http.StatusCreated},
+		{"unknown kind", `{"kind":"user","keyword":"x"}`, http.StatusBadRequest},
+		{"keyword too long", `{"kind":"keyword","keyword":"` + strings.Repeat("a", 101) + `"}`, http.StatusBadRequest},
+		{"service validation", `{"kind":"keyword","keyword":"invalid"}`, http.StatusBadRequest},
+		{"limit reached", `{"kind":"keyword","keyword":"full"}`, http.StatusBadRequest},
+		{"unknown flavor", `{"kind":"flavor","flavor_id":999}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/users/me/mute-rules", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestUpdateMyMuteRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewMuteRuleHandler(&mockMuteRuleService{
+		updateRuleFunc: func(userID, ruleID int, input *models.MuteRuleInput) (*models.MuteRule, error) {
+			if ruleID == 99 {
+				return nil, repositories.ErrMuteRuleNotFound
+			}
+			return &models.MuteRule{ID: ruleID, UserID: userID, Kind: input.Kind, Keyword: input.Keyword}, nil
+		},
+	})
+	router := newMuteRuleRouter(handler, 1)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"success", "/users/me/mute-rules/1", http.StatusOK},
+		{"not found", "/users/me/mute-rules/99", http.StatusNotFound},
+		{"invalid id", "/users/me/mute-rules/abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(`{"kind":"keyword","keyword":"apple"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestDeleteMyMuteRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewMuteRuleHandler(&mockMuteRuleService{
+		deleteRuleFunc: func(userID, ruleID int) error {
+			if ruleID == 99 {
+				return repositories.ErrMuteRuleNotFound
+			}
+			return nil
+		},
+	})
+	router := newMuteRuleRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/me/mute-rules/1", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/me/mute-rules/99", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationServiceInterface は NotificationService のインターフェース（テスト用）
+type NotificationServiceInterface interface {
+	GetNotifications(userID int, query models.NotificationListQuery) (*models.NotificationsResponse, error)
+	GetUnreadCount(userID int) (int, error)
+	MarkRead(userID, notificationID int) error
+	MarkAllRead(userID int) error
+}
+
+// NotificationHandler は通知関連のHTTPリクエストを処理する
+type NotificationHandler struct {
+	notificationService NotificationServiceInterface
+}
+
+// NewNotificationHandler は新しい NotificationHandler を作成する
+func NewNotificationHandler(notificationService NotificationServiceInterface) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *NotificationHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "NotificationHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// GetMyNotifications は GET /api/v1/users/me/notifications を処理する
+// @Summary 通知一覧取得
+// @Description 認証ユーザーの通知を新しい順に取得します（総数・未読数付き）
+// @Description 同じ投稿への未読のいいねは1件にまとめられ、others_count にまとめられた他のユーザー数が入ります
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "取得件数（1〜100、省略時は20）"
+// @Param offset query int false "読み飛ばす件数"
+// @Success 200 {object} models.NotificationsResponse "通知一覧"
+// @Failure 400 {object} models.ValidationError "無効なページングパラメータ"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/notifications [get]
+func (h *NotificationHandler) GetMyNotifications(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "GetMyNotifications")
+	if !ok {
+		return
+	}
+
+	var query models.NotificationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logging.L.Warn("invalid query parameters", "handler", "NotificationHandler", "method", "GetMyNotifications", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	response, err := h.notificationService.GetNotifications(userID, query)
+	if err != nil {
+		logging.L.Error("failed to get notifications", "handler", "NotificationHandler", "method", "GetMyNotifications", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMyUnreadCount は GET /api/v1/users/me/notifications/unread-count を処理する
+// @Summary 未読通知数取得
+// @Description 認証ユーザーの未読通知数を取得します
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UnreadNotificationCountResponse "未読通知数"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/notifications/unread-count [get]
+func (h *NotificationHandler) GetMyUnreadCount(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "GetMyUnreadCount")
+	if !ok {
+		return
+	}
+
+	count, err := h.notificationService.GetUnreadCount(userID)
+	if err != nil {
+		logging.L.Error("failed to count unread notifications", "handler", "NotificationHandler", "method", "GetMyUnreadCount", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.UnreadNotificationCountResponse{UnreadCount: count})
+}
+
+// MarkRead は POST /api/v1/users/me/notifications/:id/read を処理する
+// @Summary 通知を既読にする
+// @Description 認証ユーザーの通知を既読にします。既読の通知を指定した場合も成功します
+// @Tags notifications
+// @Security BearerAuth
+// @Param id path int true "通知ID"
+// @Success 204 "既読にしました"
+// @Failure 400 {object} models.ValidationError "無効な通知ID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "通知が見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "MarkRead")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(userID, id); err != nil {
+		if errors.Is(err, repositories.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to mark notification as read", "handler", "NotificationHandler", "method", "MarkRead", "user_id", userID, "notification_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllRead は POST /api/v1/users/me/notifications/read-all を処理する
+// @Summary すべての通知を既読にする
+// @Description 認証ユーザーの未読通知をすべて既読にします
+// @Tags notifications
+// @Security BearerAuth
+// @Success 204 "既読にしました"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "MarkAllRead")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkAllRead(userID); err != nil {
+		logging.L.Error("failed to mark all notifications as read", "handler", "NotificationHandler", "method", "MarkAllRead", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockNotificationService はテスト用の NotificationService モック
+type mockNotificationService struct {
+	getNotificationsFunc func(userID int, query models.NotificationListQuery) (*models.NotificationsResponse, error)
+	getUnreadCountFunc   func(userID int) (int, error)
+	markReadFunc         func(userID, notificationID int) error
+	markAllReadFunc      func(userID int) error
+}
+
+func (m *mockNotificationService) GetNotifications(userID int, query models.NotificationListQuery) (*models.NotificationsResponse, error) {
+	if m.getNotificationsFunc != nil {
+		return m.getNotificationsFunc(userID, query)
+	}
+	return &models.NotificationsResponse{}, nil
+}
+
+func (m *mockNotificationService) GetUnreadCount(userID int) (int, error) {
+	if m.getUnreadCountFunc != nil {
+		return m.getUnreadCountFunc(userID)
+	}
+	return 0, nil
+}
+
+func (m *mockNotificationService) MarkRead(userID, notificationID int) error {
+	if m.markReadFunc != nil {
+		return m.markReadFunc(userID, notificationID)
+	}
+	return nil
+}
+
+func (m *mockNotificationService) MarkAllRead(userID int) error {
+	if m.markAllReadFunc != nil {
+		return m.markAllReadFunc(userID)
+	}
+	return nil
+}
+
+// newNotificationRouter は本番と同じパス構成で通知エンドポイントを登録したルーターを返す
+func newNotificationRouter(handler *NotificationHandler, userID int) *gin.Engine {
+	router := gin.New()
+	auth := withUserID(userID)
+	router.GET("/users/me/notifications", auth, handler.GetMyNotifications)
+	router.GET("/users/me/notifications/unread-count", auth, handler.GetMyUnreadCount)
+	router.POST("/users/me/notifications/read-all", auth, handler.MarkAllRead)
+	router.POST("/users/me/notifications/:id/read", auth, handler.MarkRead)
+	return router
+}
+
+func TestGetMyNotifications_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotQuery models.NotificationListQuery
+	handler := NewNotificationHandler(&mockNotificationService{
+		getNotificationsFunc: func(userID int, query models.NotificationListQuery) (*models.NotificationsResponse, error) {
+			gotQuery = query
+			return &models.NotificationsResponse{
+				Notifications: []models.Notification{{ID: 1, Type: models.NotificationTypePostLiked, OthersCount: 4}},
+				Total:         1,
+				UnreadCount:   1,
+				Limit:         query.Limit,
+				Offset:        query.Offset,
+			}, nil
+		},
+	})
+	router := newNotificationRouter(handler, 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/notifications?limit=10&offset=20", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 10, gotQuery.Limit)
+	assert.Equal(t, 20, gotQuery.Offset)
+	var response models.NotificationsResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, response.Notifications[0].OthersCount)
+}
+
+func TestGetMyNotifications_InvalidLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newNotificationRouter(NewNotificationHandler(&mockNotificationService{}), 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/notifications?limit=1000", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetMyUnreadCount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewNotificationHandler(&mockNotificationService{
+		getUnreadCountFunc: func(userID int) (int, error) { return 3, nil },
+	})
+	router := newNotificationRouter(handler, 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/notifications/unread-count", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response models.UnreadNotificationCountResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, response.UnreadCount)
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotID int
+	handler := NewNotificationHandler(&mockNotificationService{
+		markReadFunc: func(userID, notificationID int) error {
+			gotID = notificationID
+			if notificationID == 404 {
+				return repositories.ErrNotificationNotFound
+			}
+			return nil
+		},
+	})
+	router := newNotificationRouter(handler, 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/me/notifications/7/read", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 7, gotID)
+
+	req = httptest.NewRequest(http.MethodPost, "/users/me/notifications/404/read", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	called := false
+	handler := NewNotificationHandler(&mockNotificationService{
+		markAllReadFunc: func(userID int) error {
+			called = true
+			return nil
+		},
+	})
+	router := newNotificationRouter(handler, 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/me/notifications/read-all", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.True(t, called)
+}
+
+func TestGetMyNotifications_Unauthorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/users/me/notifications", NewNotificationHandler(&mockNotificationService{}).GetMyNotifications)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/notifications", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
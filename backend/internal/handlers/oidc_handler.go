@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie は OpenID Connect のログインの state トークンを預ける Cookie
+// 開始と完了の API だけに送られるようパスを限定する
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/api/v1/auth/oidc"
+)
+
+// OIDCServiceInterface は OIDCService のインターフェース（テスト用）
+type OIDCServiceInterface interface {
+	Providers() []string
+	BeginLogin(ctx context.Context, providerName string) (string, string, error)
+	FinishLogin(ctx context.Context, providerName, stateToken string, input *models.OIDCCallbackInput, device models.SessionDevice) (*models.User, string, string, error)
+}
+
+// OIDCHandler は OpenID Connect のプロバイダー（Google・LINE など）によるログインのHTTPリクエストを処理する
+type OIDCHandler struct {
+	oidcService OIDCServiceInterface
+	isSecure    bool // Cookie Secureフラグ（本番環境でtrue）
+}
+
+// NewOIDCHandler は新しい OIDCHandler を作成する
+func NewOIDCHandler(oidcService OIDCServiceInterface) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+		isSecure:    os.Getenv("APP_ENV") == "production",
+	}
+}
+
+// ListProviders godoc
+// @Summary ログインに使えるプロバイダーの一覧
+// @Description OpenID Connect でログインできるプロバイダーの識別子を返す（環境変数 OIDC_PROVIDERS の順）
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.OIDCProvidersResponse "プロバイダーの一覧"
+// @Router /auth/oidc/providers [get]
+func (h *OIDCHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, models.OIDCProvidersResponse{Providers: h.oidcService.Providers()})
+}
+
+// Authorize godoc
+// @Summary プロバイダーによるログインの開始
+// @Description プロバイダーの認可 URL を発行し、state・nonce・PKCE の code_verifier を含む state トークンを Cookie（10分有効）に設定する
+// @Description ブラウザーを authorization_url に移動し、プロバイダーから戻った URL の code と state を POST /auth/oidc/{provider}/callback に送る
+// @Tags auth
+// @Produce json
+// @Param provider path string true "プロバイダーの識別子"
+// @Success 200 {object} models.OIDCAuthorizationResponse "認可 URL"
+// @Failure 404 {object} models.NotFoundError "プロバイダーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー・プロバイダーに接続できない"
+// @Router /auth/oidc/{provider}/authorize [post]
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	providerName := c.Param("provider")
+	authURL, stateToken, err := h.oidcService.BeginLogin(c.Request.Context(), providerName)
+	if err != nil {
+		if errors.Is(err, services.ErrOIDCProviderNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to begin oidc login", "handler", "OIDCHandler", "method", "Authorize", "provider", providerName, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	h.setStateCookie(c, stateToken, int(auth.OIDCStateTokenTTL.Seconds()))
+	c.JSON(http.StatusOK, models.OIDCAuthorizationResponse{AuthorizationURL: authURL})
+}
+
+// Callback godoc
+// @Summary プロバイダーによるログインの完了
+// @Description プロバイダーが返した認可コードを交換して ID トークンを検証し、パスワードでのログインと同じ JWT（Cookie）を発行する
+// @Description プロバイダーのアカウントが紐付いていない場合は確認済みのメールアドレスで既存のユーザーに紐付け、該当するユーザーがいない場合は作成する
+// @Description プロバイダーのメールアドレスが未確認の場合は oidc_email_unverified、同じメールアドレスの既存のユーザーがメールアドレスを確認していない場合は email_not_verified を返す
+// @Description 2段階認証が有効なユーザーの場合は Cookie を発行せず、パスワードでのログインと同じくチャレンジトークンを返す
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "プロバイダーの識別子"
+// @Param input body models.OIDCCallbackInput true "プロバイダーが返した code と state"
+// @Success 200 {object} models.AuthResponse "ログイン成功"
+// @Success 202 {object} models.MFAChallengeResponse "2段階認証のコードが必要（POST /auth/login/mfa でログインを完了する）"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証失敗（state の不一致・期限切れを含む）"
+// @Failure 403 {object} models.ForbiddenError "メールアドレスが未確認"
+// @Failure 404 {object} models.NotFoundError "プロバイダーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/oidc/{provider}/callback [post]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	providerName := c.Param("provider")
+	var input models.OIDCCallbackInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "OIDCHandler", "method", "Callback", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	stateToken, err := c.Cookie(oidcStateCookie)
+	if err != nil || stateToken == "" {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	// state トークンは成否にかかわらず一度だけ使う
+	h.setStateCookie(c, "", -1)
+
+	user, accessToken, refreshToken, err := h.oidcService.FinishLogin(c.Request.Context(), providerName, stateToken, &input, sessionDevice(c))
+	if err != nil {
+		var challenge *services.MFAChallengeError
+		switch {
+		case errors.As(err, &challenge):
+			c.JSON(http.StatusAccepted, models.MFAChallengeResponse{
+				MFARequired:    true,
+				ChallengeToken: challenge.ChallengeToken,
+				ExpiresAt:      challenge.ExpiresAt,
+			})
+		case errors.Is(err, services.ErrOIDCProviderNotFound):
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+		case errors.Is(err, services.ErrInvalidOIDCState), errors.Is(err, services.ErrOIDCLoginFailed):
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		case errors.Is(err, services.ErrOIDCEmailUnverified):
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeOIDCEmailUnverified})
+		case errors.Is(err, services.ErrOIDCAccountNotVerified):
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeEmailNotVerified})
+		default:
+			if writeAccountSuspended(c, err) {
+				return
+			}
+			logging.L.Error("oidc login internal error", "handler", "OIDCHandler", "method", "Callback", "provider", providerName, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+
+	writeTokenCookies(c, h.isSecure, accessToken, refreshToken)
+	logging.L.Info("user logged in", "handler", "OIDCHandler", "method", "Callback", "provider", providerName, "user_id", user.ID)
+	c.JSON(http.StatusOK, models.NewAuthResponse(user))
+}
+
+// setStateCookie は state トークンの Cookie を設定する（maxAge が負の場合は削除する）
+func (h *OIDCHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     oidcStateCookiePath,
+		MaxAge:   maxAge,
+		Secure:   h.isSecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
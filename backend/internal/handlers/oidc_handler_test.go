@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockOIDCService はテスト用の OIDCService モック
+type mockOIDCService struct {
+	beginLoginFunc  func(ctx context.Context, providerName string) (string, string, error)
+	finishLoginFunc func(ctx context.Context, providerName, stateToken string, input *models.OIDCCallbackInput, device models.SessionDevice) (*models.User, string, string, error)
+}
+
+func (m *mockOIDCService) Providers() []string {
+	return []string{"google", "line"}
+}
+
+func (m *mockOIDCService) BeginLogin(ctx context.Context, providerName string) (string, string, error) {
+	if m.beginLoginFunc != nil {
+		return m.beginLoginFunc(ctx, providerName)
+	}
+	return "", "", errors.New("not implemented")
+}
+
+func (m *mockOIDCService) FinishLogin(ctx context.Context, providerName, stateToken string, input *models.OIDCCallbackInput, device models.SessionDevice) (*models.User, string, string, error) {
+	if m.finishLoginFunc != nil {
+		return m.finishLoginFunc(ctx, providerName, stateToken, input, device)
+	}
+	return nil, "", "", errors.New("not implemented")
+}
+
+func newOIDCRouter(service *mockOIDCService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewOIDCHandler(service)
+	r := gin.New()
+	r.GET("/auth/oidc/providers", handler.ListProviders)
+	r.POST("/auth/oidc/:provider/authorize", handler.Authorize)
+	r.POST("/auth/oidc/:provider/callback", handler.Callback)
+	return r
+}
+
+// responseCookies はレスポンスの Cookie を名前で引けるようにする
+func responseCookies(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func TestOIDCHandler_Authorize(t *testing.T) {
+	service := &mockOIDCService{
+		beginLoginFunc: func(ctx context.Context, providerName string) (string, string, error) {
+			switch providerName {
+			case "google":
+				return "https://accounts.google.com/o/oauth2/v2/auth?state=abc", "state-token", nil
+			case "down":
+				return "", "", errors.New("discovery failed")
+			}
+			return "", "", services.ErrOIDCProviderNotFound
+		},
+	}
+
+	t.Run("正常系: プロバイダーの一覧を返す", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newOIDCRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/providers", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"providers":["google","line"]}`, w.Body.String())
+	})
+
+	t.Run("正常系: 認可 URL を返し、state トークンを Cookie に設定する", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newOIDCRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/oidc/google/authorize", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"authorization_url":"https://accounts.google.com/o/oauth2/v2/auth?state=abc"`)
+		cookie := responseCookies(w)[oidcStateCookie]
+		if assert.NotNil(t, cookie) {
+			assert.Equal(t, "state-token", cookie.Value)
+			assert.Equal(t, oidcStateCookiePath, cookie.Path)
+			assert.Equal(t, int(auth.OIDCStateTokenTTL.Seconds()), cookie.MaxAge)
+			assert.True(t, cookie.HttpOnly)
+			assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		provider   string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "異常系: 設定されていないプロバイダー", provider: "unknown", wantStatus: http.StatusNotFound, wantError: models.ErrCodeNotFound},
+		{name: "異常系: プロバイダーに接続できない", provider: "down", wantStatus: http.StatusInternalServerError, wantError: models.ErrCodeInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newOIDCRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/oidc/"+tt.provider+"/authorize", nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var resp map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantError, resp["error"])
+			assert.Empty(t, w.Result().Cookies())
+		})
+	}
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+	service := &mockOIDCService{
+		finishLoginFunc: func(ctx context.Context, providerName, stateToken string, input *models.OIDCCallbackInput, device models.SessionDevice) (*models.User, string, string, error) {
+			if providerName != "google" {
+				return nil, "", "", services.ErrOIDCProviderNotFound
+			}
+			switch input.Code {
+			case "bad-state":
+				return nil, "", "", services.ErrInvalidOIDCState
+			case "bad-code":
+				return nil, "", "", services.ErrOIDCLoginFailed
+			case "unverified-provider":
+				return nil, "", "", services.ErrOIDCEmailUnverified
+			case "unverified-account":
+				return nil, "", "", services.ErrOIDCAccountNotVerified
+			case "suspended":
+				return nil, "", "", &auth.SuspensionError{Reason: "スパム行為のため"}
+			case "mfa":
+				return nil, "", "", &services.MFAChallengeError{ChallengeToken: "challenge-token"}
+			case "db-error":
+				return nil, "", "", errors.New("db error")
+			}
+			assert.Equal(t, "state-token", stateToken)
+			assert.Equal(t, "TestAgent", device.UserAgent)
+			return &models.User{ID: 1, Email: "oidc@example.com", DisplayName: "OIDC User"}, "access", "refresh", nil
+		},
+	}
+
+	t.Run("正常系: パスワードでのログインと同じ Cookie を発行し、state トークンの Cookie を削除する", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/oidc/google/callback", strings.NewReader(`{"code":"ok","state":"abc"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "TestAgent")
+		req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: "state-token"})
+		newOIDCRouter(service).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"email":"oidc@example.com"`)
+		cookies := responseCookies(w)
+		if assert.Contains(t, cookies, "access_token") && assert.Contains(t, cookies, "refresh_token") {
+			assert.Equal(t, "access", cookies["access_token"].Value)
+			assert.Equal(t, "refresh", cookies["refresh_token"].Value)
+		}
+		if assert.Contains(t, cookies, oidcStateCookie) {
+			assert.Equal(t, -1, cookies[oidcStateCookie].MaxAge)
+		}
+	})
+
+	t.Run("正常系: 2段階認証が有効なユーザーには Cookie を発行せずチャレンジトークンを返す", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/oidc/google/callback", strings.NewReader(`{"code":"mfa","state":"abc"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: "state-token"})
+		newOIDCRouter(service).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var resp models.MFAChallengeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.MFARequired)
+		assert.Equal(t, "challenge-token", resp.ChallengeToken)
+		assert.NotContains(t, responseCookies(w), "access_token")
+	})
+
+	t.Run("異常系: state トークンの Cookie がない", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/oidc/google/callback", strings.NewReader(`{"code":"ok","state":"abc"}`))
+		req.Header.Set("Content-Type", "application/json")
+		newOIDCRouter(service).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	tests := []struct {
+		name       string
+		provider   string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "異常系: code が未入力", provider: "google", body: `{"state":"abc"}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "異常系: 設定されていないプロバイダー", provider: "unknown", body: `{"code":"ok","state":"abc"}`, wantStatus: http.StatusNotFound, wantError: models.ErrCodeNotFound},
+		{name: "異常系: state が一致しない", provider: "google", body: `{"code":"bad-state","state":"abc"}`, wantStatus: http.StatusUnauthorized, wantError: models.ErrCodeUnauthorized},
+		{name: "異常系: 認可コードを交換できない", provider: "google", body: `{"code":"bad-code","state":"abc"}`, wantStatus: http.StatusUnauthorized, wantError: models.ErrCodeUnauthorized},
+		{name: "異常系: プロバイダーのメールアドレスが未確認", provider: "google", body: `{"code":"unverified-provider","state":"abc"}`, wantStatus: http.StatusForbidden, wantError: models.ErrCodeOIDCEmailUnverified},
+		{name: "異常系: 既存のユーザーのメールアドレスが未確認", provider: "google", body: `{"code":"unverified-account","state":"abc"}`, wantStatus: http.StatusForbidden, wantError: models.ErrCodeEmailNotVerified},
+		{name: "異常系: 利用停止中", provider: "google", body: `{"code":"suspended","state":"abc"}`, wantStatus: http.StatusForbidden, wantError: models.ErrCodeAccountSuspended},
+		{name: "異常系: サーバーエラー", provider: "google", body: `{"code":"db-error","state":"abc"}`, wantStatus: http.StatusInternalServerError, wantError: models.ErrCodeInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/auth/oidc/"+tt.provider+"/callback", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: "state-token"})
+			newOIDCRouter(service).ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var resp map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantError, resp["error"])
+			assert.NotContains(t, responseCookies(w), "access_token")
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyServiceInterface は PasskeyService のインターフェース（テスト用）
+type PasskeyServiceInterface interface {
+	BeginRegistration(userID int) (*models.PasskeyRegistrationOptionsResponse, error)
+	FinishRegistration(userID int, input *models.RegisterPasskeyInput) (*models.Passkey, error)
+	BeginLogin() (*models.PasskeyLoginOptionsResponse, error)
+	FinishLogin(input *models.PasskeyLoginInput, device models.SessionDevice) (*models.User, string, string, error)
+	List(userID int) ([]models.Passkey, error)
+	Rename(userID int, id int64, name string) error
+	Delete(userID int, id int64) error
+}
+
+// PasskeyHandler はパスキー（WebAuthn）の登録・管理とパスキーによるログインのHTTPリクエストを処理する
+type PasskeyHandler struct {
+	passkeyService PasskeyServiceInterface
+	isSecure       bool // Cookie Secureフラグ（本番環境でtrue）
+}
+
+// NewPasskeyHandler は新しい PasskeyHandler を作成する
+func NewPasskeyHandler(passkeyService PasskeyServiceInterface) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+		isSecure:       os.Getenv("APP_ENV") == "production",
+	}
+}
+
+// BeginRegistration godoc
+// @Summary パスキーの登録開始
+// @Description navigator.credentials.create() に渡すオプションと、登録の完了に使うセッショントークン（5分間有効）を発行する
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PasskeyRegistrationOptionsResponse "登録のオプション"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/passkeys/registration/options [post]
+func (h *PasskeyHandler) BeginRegistration(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "BeginRegistration")
+	if !ok {
+		return
+	}
+	options, err := h.passkeyService.BeginRegistration(userID)
+	if err != nil {
+		logging.L.Error("failed to begin passkey registration", "handler", "PasskeyHandler", "method", "BeginRegistration", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishRegistration godoc
+// @Summary パスキーの登録
+// @Description navigator.credentials.create() の結果を検証してパスキーを登録する
+// @Description セッショントークンが無効・使用済みの場合は invalid_token、認証器のレスポンスを検証できない場合は invalid_passkey を返す
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.RegisterPasskeyInput true "セッショントークンと認証器のレスポンス"
+// @Success 201 {object} models.Passkey "登録したパスキー"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー・検証に失敗"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 409 {object} models.ConflictError "登録済みのパスキー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/passkeys/registration [post]
+func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "FinishRegistration")
+	if !ok {
+		return
+	}
+	var input models.RegisterPasskeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "PasskeyHandler", "method", "FinishRegistration", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(userID, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPasskeySession):
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeInvalidToken})
+		case errors.Is(err, services.ErrPasskeyVerificationFailed):
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeInvalidPasskey})
+		case errors.Is(err, repositories.ErrPasskeyAlreadyExists):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodePasskeyRegistered})
+		default:
+			logging.L.Error("failed to register passkey", "handler", "PasskeyHandler", "method", "FinishRegistration", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, passkey)
+}
+
+// BeginLogin godoc
+// @Summary パスキーによるログインの開始
+// @Description navigator.credentials.get() に渡すオプションと、ログインの完了に使うセッショントークン（5分間有効）を発行する
+// @Description 端末に保存されたパスキーから選んでもらうため、メールアドレスは不要
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.PasskeyLoginOptionsResponse "ログインのオプション"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/passkeys/login/options [post]
+func (h *PasskeyHandler) BeginLogin(c *gin.Context) {
+	options, err := h.passkeyService.BeginLogin()
+	if err != nil {
+		logging.L.Error("failed to begin passkey login", "handler", "PasskeyHandler", "method", "BeginLogin", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishLogin godoc
+// @Summary パスキーによるログイン
+// @Description navigator.credentials.get() の結果を検証してログインし、パスワードでのログインと同じ JWT（Cookie）を発行する
+// @Description パスキーは本人確認（生体認証・PIN）を含むため、2段階認証のコードは求めない
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.PasskeyLoginInput true "セッショントークンと認証器のレスポンス"
+// @Success 200 {object} models.AuthResponse "ログイン成功"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証失敗"
+// @Failure 403 {object} models.AccountSuspendedError "利用停止中"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/passkeys/login [post]
+func (h *PasskeyHandler) FinishLogin(c *gin.Context) {
+	var input models.PasskeyLoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "PasskeyHandler", "method", "FinishLogin", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	user, accessToken, refreshToken, err := h.passkeyService.FinishLogin(&input, sessionDevice(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPasskeySession) || errors.Is(err, services.ErrPasskeyVerificationFailed) {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+			return
+		}
+		if writeAccountSuspended(c, err) {
+			return
+		}
+		logging.L.Error("passkey login internal error", "handler", "PasskeyHandler", "method", "FinishLogin", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	writeTokenCookies(c, h.isSecure, accessToken, refreshToken)
+	logging.L.Info("user logged in", "handler", "PasskeyHandler", "method", "FinishLogin", "user_id", user.ID)
+	c.JSON(http.StatusOK, models.NewAuthResponse(user))
+}
+
+// List godoc
+// @Summary 登録済みのパスキー一覧
+// @Description ログイン中のユーザーが登録したパスキーを登録順に返す
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PasskeysResponse "パスキー一覧"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/passkeys [get]
+func (h *PasskeyHandler) List(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "List")
+	if !ok {
+		return
+	}
+	passkeys, err := h.passkeyService.List(userID)
+	if err != nil {
+		logging.L.Error("failed to list passkeys", "handler", "PasskeyHandler", "method", "List", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.PasskeysResponse{Passkeys: passkeys})
+}
+
+// Rename godoc
+// @Summary パスキーの名前の変更
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Param id path int true "パスキーID"
+// @Param input body models.RenamePasskeyInput true "新しい名前"
+// @Success 204 "変更しました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "パスキーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/passkeys/{id} [patch]
+func (h *PasskeyHandler) Rename(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "Rename")
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	var input models.RenamePasskeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "PasskeyHandler", "method", "Rename", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.passkeyService.Rename(userID, id, input.Name); err != nil {
+		if errors.Is(err, repositories.ErrPasskeyNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to rename passkey", "handler", "PasskeyHandler", "method", "Rename", "user_id", userID, "passkey_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Delete godoc
+// @Summary パスキーの削除
+// @Description パスキーを削除する。削除したパスキーではログインできなくなる（認証器に残ったパスキーは端末側で削除してもらう）
+// @Tags auth
+// @Security BearerAuth
+// @Param id path int true "パスキーID"
+// @Success 204 "削除しました"
+// @Failure 400 {object} models.ValidationError "無効なパスキーID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "パスキーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/passkeys/{id} [delete]
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "Delete")
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.passkeyService.Delete(userID, id); err != nil {
+		if errors.Is(err, repositories.ErrPasskeyNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to delete passkey", "handler", "PasskeyHandler", "method", "Delete", "user_id", userID, "passkey_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *PasskeyHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "PasskeyHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
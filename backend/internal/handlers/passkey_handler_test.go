@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPasskeyService はテスト用の PasskeyService モック
+type mockPasskeyService struct {
+	beginRegistrationFunc  func(userID int) (*models.PasskeyRegistrationOptionsResponse, error)
+	finishRegistrationFunc func(userID int, input *models.RegisterPasskeyInput) (*models.Passkey, error)
+	beginLoginFunc         func() (*models.PasskeyLoginOptionsResponse, error)
+	finishLoginFunc        func(input *models.PasskeyLoginInput, device models.SessionDevice) (*models.User, string, string, error)
+	listFunc               func(userID int) ([]models.Passkey, error)
+	renameFunc             func(userID int, id int64, name string) error
+	deleteFunc             func(userID int, id int64) error
+}
+
+func (m *mockPasskeyService) BeginRegistration(userID int) (*models.PasskeyRegistrationOptionsResponse, error) {
+	if m.beginRegistrationFunc != nil {
+		return m.beginRegistrationFunc(userID)
+	}
+	return &models.PasskeyRegistrationOptionsResponse{}, nil
+}
+
+func (m *mockPasskeyService) FinishRegistration(userID int, input *models.RegisterPasskeyInput) (*models.Passkey, error) {
+	if m.finishRegistrationFunc != nil {
+		return m.finishRegistrationFunc(userID, input)
+	}
+	return &models.Passkey{}, nil
+}
+
+func (m *mockPasskeyService) BeginLogin() (*models.PasskeyLoginOptionsResponse, error) {
+	if m.beginLoginFunc != nil {
+		return m.beginLoginFunc()
+	}
+	return &models.PasskeyLoginOptionsResponse{}, nil
+}
+
+func (m *mockPasskeyService) FinishLogin(input *models.PasskeyLoginInput, device models.SessionDevice) (*models.User, string, string, error) {
+	if m.finishLoginFunc != nil {
+		return m.finishLoginFunc(input, device)
+	}
+	return nil, "", "", errors.New("not implemented")
+}
+
+func (m *mockPasskeyService) List(userID int) ([]models.Passkey, error) {
+	if m.listFunc != nil {
+		return m.listFunc(userID)
+	}
+	return []models.Passkey{}, nil
+}
+
+func (m *mockPasskeyService) Rename(userID int, id int64, name string) error {
+	if m.renameFunc != nil {
+		return m.renameFunc(userID, id, name)
+	}
+	return nil
+}
+
+func (m *mockPasskeyService) Delete(userID int, id int64) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(userID, id)
+	}
+	return nil
+}
+
+func newPasskeyRouter(service *mockPasskeyService, userID interface{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewPasskeyHandler(service)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	r.POST("/auth/passkeys/registration/options", handler.BeginRegistration)
+	r.POST("/auth/passkeys/registration", handler.FinishRegistration)
+	r.POST("/auth/passkeys/login/options", handler.BeginLogin)
+	r.POST("/auth/passkeys/login", handler.FinishLogin)
+	r.GET("/auth/passkeys", handler.List)
+	r.PATCH("/auth/passkeys/:id", handler.Rename)
+	r.DELETE("/auth/passkeys/:id", handler.Delete)
+	return r
+}
+
+func TestPasskeyHandler(t *testing.T) {
+	service := &mockPasskeyService{
+		beginRegistrationFunc: func(userID int) (*models.PasskeyRegistrationOptionsResponse, error) {
+			return &models.PasskeyRegistrationOptionsResponse{SessionToken: "registration-token"}, nil
+		},
+		finishRegistrationFunc: func(userID int, input *models.RegisterPasskeyInput) (*models.Passkey, error) {
+			switch input.SessionToken {
+			case "expired":
+				return nil, services.ErrInvalidPasskeySession
+			case "tampered":
+				return nil, services.ErrPasskeyVerificationFailed
+			case "duplicate":
+				return nil, repositories.ErrPasskeyAlreadyExists
+			}
+			return &models.Passkey{ID: 1, Name: input.Name, Transports: []string{"internal"}}, nil
+		},
+		beginLoginFunc: func() (*models.PasskeyLoginOptionsResponse, error) {
+			return &models.PasskeyLoginOptionsResponse{SessionToken: "login-token"}, nil
+		},
+		listFunc: func(userID int) ([]models.Passkey, error) {
+			if userID == 4 {
+				return nil, errors.New("db error")
+			}
+			return []models.Passkey{{ID: 1, Name: "iPhone", Transports: []string{"internal"}}}, nil
+		},
+		renameFunc: func(userID int, id int64, name string) error {
+			if id != 1 {
+				return repositories.ErrPasskeyNotFound
+			}
+			return nil
+		},
+		deleteFunc: func(userID int, id int64) error {
+			if id != 1 {
+				return repositories.ErrPasskeyNotFound
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		userID     interface{}
+		wantStatus int
+		wantError  string
+		wantBody   string
+	}{
+		{name: "正常系: 登録を開始する", method: http.MethodPost, path: "/auth/passkeys/registration/options", userID: 1, wantStatus: http.StatusOK, wantBody: `"session_token":"registration-token"`},
+		{name: "異常系: 未認証では登録を開始できない", method: http.MethodPost, path: "/auth/passkeys/registration/options", userID: nil, wantStatus: http.StatusUnauthorized, wantError: models.ErrCodeUnauthorized},
+		{name: "正常系: パスキーを登録する", method: http.MethodPost, path: "/auth/passkeys/registration", body: `{"session_token":"ok","name":"iPhone","credential":{}}`, userID: 1, wantStatus: http.StatusCreated, wantBody: `"name":"iPhone"`},
+		{name: "異常系: セッショントークンが未入力", method: http.MethodPost, path: "/auth/passkeys/registration", body: `{"credential":{}}`, userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "異常系: セッショントークンが無効", method: http.MethodPost, path: "/auth/passkeys/registration", body: `{"session_token":"expired"}`, userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeInvalidToken},
+		{name: "異常系: 認証器のレスポンスを検証できない", method: http.MethodPost, path: "/auth/passkeys/registration", body: `{"session_token":"tampered"}`, userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeInvalidPasskey},
+		{name: "異常系: 登録済みのパスキー", method: http.MethodPost, path: "/auth/passkeys/registration", body: `{"session_token":"duplicate"}`, userID: 1, wantStatus: http.StatusConflict, wantError: models.ErrCodePasskeyRegistered},
+		{name: "異常系: 名前が長すぎる", method: http.MethodPost, path: "/auth/passkeys/registration", body: `{"session_token":"ok","name":"` + strings.Repeat("a", 65) + `"}`, userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "正常系: 未認証でもログインを開始できる", method: http.MethodPost, path: "/auth/passkeys/login/options", userID: nil, wantStatus: http.StatusOK, wantBody: `"session_token":"login-token"`},
+		{name: "正常系: 一覧を取得する", method: http.MethodGet, path: "/auth/passkeys", userID: 1, wantStatus: http.StatusOK, wantBody: `"passkeys":[{"id":1,"transports":["internal"],"name":"iPhone"`},
+		{name: "異常系: 一覧のサーバーエラー", method: http.MethodGet, path: "/auth/passkeys", userID: 4, wantStatus: http.StatusInternalServerError, wantError: models.ErrCodeInternalServer},
+		{name: "正常系: 名前を変更する", method: http.MethodPatch, path: "/auth/passkeys/1", body: `{"name":"MacBook"}`, userID: 1, wantStatus: http.StatusNoContent},
+		{name: "異常系: 名前が空", method: http.MethodPatch, path: "/auth/passkeys/1", body: `{"name":""}`, userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "異常系: 変更するパスキーが見つからない", method: http.MethodPatch, path: "/auth/passkeys/2", body: `{"name":"MacBook"}`, userID: 1, wantStatus: http.StatusNotFound, wantError: models.ErrCodeNotFound},
+		{name: "正常系: 削除する", method: http.MethodDelete, path: "/auth/passkeys/1", userID: 1, wantStatus: http.StatusNoContent},
+		{name: "異常系: 削除するパスキーが見つからない", method: http.MethodDelete, path: "/auth/passkeys/2", userID: 1, wantStatus: http.StatusNotFound, wantError: models.ErrCodeNotFound},
+		{name: "異常系: 無効なパスキーID", method: http.MethodDelete, path: "/auth/passkeys/abc", userID: 1, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			newPasskeyRouter(service, tt.userID).ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var resp map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp["error"])
+			}
+			if tt.wantBody != "" {
+				assert.Contains(t, w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestPasskeyHandler_FinishLogin(t *testing.T) {
+	service := &mockPasskeyService{
+		finishLoginFunc: func(input *models.PasskeyLoginInput, device models.SessionDevice) (*models.User, string, string, error) {
+			switch input.SessionToken {
+			case "expired":
+				return nil, "", "", services.ErrInvalidPasskeySession
+			case "unknown":
+				return nil, "", "", services.ErrPasskeyVerificationFailed
+			case "suspended":
+				return nil, "", "", &auth.SuspensionError{Reason: "スパム行為のため"}
+			}
+			assert.Equal(t, "TestAgent", device.UserAgent)
+			return &models.User{ID: 1, Email: "passkey@example.com", DisplayName: "Passkey User"}, "access", "refresh", nil
+		},
+	}
+
+	t.Run("正常系: パスワードでのログインと同じ Cookie を発行する", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/passkeys/login", strings.NewReader(`{"session_token":"ok","credential":{}}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "TestAgent")
+		newPasskeyRouter(service, nil).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"email":"passkey@example.com"`)
+		cookies := map[string]*http.Cookie{}
+		for _, cookie := range w.Result().Cookies() {
+			cookies[cookie.Name] = cookie
+		}
+		if assert.Contains(t, cookies, "access_token") && assert.Contains(t, cookies, "refresh_token") {
+			assert.Equal(t, "access", cookies["access_token"].Value)
+			assert.Equal(t, "refresh", cookies["refresh_token"].Value)
+			assert.True(t, cookies["access_token"].HttpOnly)
+			assert.Equal(t, http.SameSiteLaxMode, cookies["refresh_token"].SameSite)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "異常系: セッショントークンが無効", body: `{"session_token":"expired"}`, wantStatus: http.StatusUnauthorized, wantError: models.ErrCodeUnauthorized},
+		{name: "異常系: 登録されていないパスキー", body: `{"session_token":"unknown"}`, wantStatus: http.StatusUnauthorized, wantError: models.ErrCodeUnauthorized},
+		{name: "異常系: 利用停止中", body: `{"session_token":"suspended"}`, wantStatus: http.StatusForbidden, wantError: models.ErrCodeAccountSuspended},
+		{name: "異常系: セッショントークンが未入力", body: `{}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/auth/passkeys/login", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			newPasskeyRouter(service, nil).ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var resp map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantError, resp["error"])
+			assert.Empty(t, w.Result().Cookies())
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordResetServiceInterface は PasswordResetService のインターフェース（テスト用）
+type PasswordResetServiceInterface interface {
+	RequestReset(email string) error
+	ConfirmReset(rawToken, newPassword string) error
+}
+
+// PasswordResetHandler はパスワードリセット関連のHTTPリクエストを処理する
+type PasswordResetHandler struct {
+	passwordResetService PasswordResetServiceInterface
+}
+
+// NewPasswordResetHandler は新しい PasswordResetHandler を作成する
+func NewPasswordResetHandler(passwordResetService PasswordResetServiceInterface) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+	}
+}
+
+// RequestPasswordReset godoc
+// @Summary パスワードリセットの申請
+// @Description 登録したメールアドレスにパスワードを再設定するためのリンクを送る。リンクは30分間、1回だけ使える
+// @Description 登録されているメールアドレスかどうかに関わらず同じレスポンスを返す
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.PasswordResetRequestInput true "メールアドレス"
+// @Success 202 "受け付けました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/password-reset/request [post]
+func (h *PasswordResetHandler) RequestPasswordReset(c *gin.Context) {
+	var input models.PasswordResetRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "PasswordResetHandler", "method", "RequestPasswordReset", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.passwordResetService.RequestReset(input.Email); err != nil {
+		logging.L.Error("failed to request password reset", "handler", "PasswordResetHandler", "method", "RequestPasswordReset", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// ConfirmPasswordReset godoc
+// @Summary パスワードリセットの確定
+// @Description メールで送ったリンクのトークンを使ってパスワードを再設定する。新しいパスワードは12文字以上
+// @Description 再設定後はすべての端末をログアウトさせ、ログインの失敗によるアカウントのロックも解除する
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body models.PasswordResetConfirmInput true "トークンと新しいパスワード"
+// @Success 204 "パスワードを再設定しました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー・トークンが無効"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /auth/password-reset/confirm [post]
+func (h *PasswordResetHandler) ConfirmPasswordReset(c *gin.Context) {
+	var input models.PasswordResetConfirmInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "PasswordResetHandler", "method", "ConfirmPasswordReset", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.passwordResetService.ConfirmReset(input.Token, input.NewPassword); err != nil {
+		if errors.Is(err, repositories.ErrInvalidPasswordResetToken) {
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeInvalidToken})
+			return
+		}
+		logging.L.Error("failed to confirm password reset", "handler", "PasswordResetHandler", "method", "ConfirmPasswordReset", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
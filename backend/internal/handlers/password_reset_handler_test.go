@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPasswordResetService はテスト用の PasswordResetService モック
+type mockPasswordResetService struct {
+	requestResetFunc func(email string) error
+	confirmResetFunc func(rawToken, newPassword string) error
+}
+
+func (m *mockPasswordResetService) RequestReset(email string) error {
+	if m.requestResetFunc != nil {
+		return m.requestResetFunc(email)
+	}
+	return nil
+}
+
+func (m *mockPasswordResetService) ConfirmReset(rawToken, newPassword string) error {
+	if m.confirmResetFunc != nil {
+		return m.confirmResetFunc(rawToken, newPassword)
+	}
+	return nil
+}
+
+func newPasswordResetRouter(service *mockPasswordResetService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewPasswordResetHandler(service)
+	r := gin.New()
+	r.POST("/auth/password-reset/request", handler.RequestPasswordReset)
+	r.POST("/auth/password-reset/confirm", handler.ConfirmPasswordReset)
+	return r
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	r := newPasswordResetRouter(&mockPasswordResetService{
+		requestResetFunc: func(email string) error {
+			if email == "broken@example.com" {
+				return errors.New("db error")
+			}
+			return nil
+		},
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "正常系: 登録済みのメールアドレス", body: `{"email":"user@example.com"}`, wantStatus: http.StatusAccepted},
+		{name: "正常系: 未登録のメールアドレスでも同じレスポンス", body: `{"email":"unknown@example.com"}`, wantStatus: http.StatusAccepted},
+		{name: "異常系: メールアドレスの形式が不正", body: `{"email":"not-an-email"}`, wantStatus: http.StatusBadRequest},
+		{name: "異常系: サーバーエラー", body: `{"email":"broken@example.com"}`, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/auth/password-reset/request", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusAccepted {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestConfirmPasswordReset(t *testing.T) {
+	var gotToken, gotPassword string
+	r := newPasswordResetRouter(&mockPasswordResetService{
+		confirmResetFunc: func(rawToken, newPassword string) error {
+			if rawToken == "used" {
+				return repositories.ErrInvalidPasswordResetToken
+			}
+			gotToken, gotPassword = rawToken, newPassword
+			return nil
+		},
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "正常系: パスワードを再設定", body: `{"token":"valid","new_password":"new-password-123"}`, wantStatus: http.StatusNoContent},
+		{name: "異常系: 無効なトークン", body: `{"token":"used","new_password":"new-password-123"}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeInvalidToken},
+		{name: "異常系: 新しいパスワードが12文字未満", body: `{"token":"valid","new_password":"short"}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+		{name: "異常系: トークンが未入力", body: `{"new_password":"new-password-123"}`, wantStatus: http.StatusBadRequest, wantError: models.ErrCodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/auth/password-reset/confirm", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var resp map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantError, resp["error"])
+			}
+		})
+	}
+	assert.Equal(t, "valid", gotToken)
+	assert.Equal(t, "new-password-123", gotPassword)
+}
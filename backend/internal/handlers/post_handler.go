@@ -15,13 +15,14 @@ import (
 
 // PostServiceInterface はPostServiceのインターフェース（テスト用）
 type PostServiceInterface interface {
-	GetAllPosts(userID *int) ([]models.Post, error)
+	GetAllPosts(userID *int, filter models.PostFilter) ([]models.Post, error)
 	GetPostByID(id int, userID *int) (*models.Post, error)
 	CreatePost(userID int, input *models.CreatePostInput) (*models.Post, error)
 	LikePost(userID, postID int) (*models.Post, error)
 	UnlikePost(userID, postID int) (*models.Post, error)
 	DeletePost(userID, postID int) error
 	UpdatePost(userID, postID int, input *models.UpdatePostInput) (*models.Post, error)
+	RemixPost(userID, sourcePostID int) (*models.Post, error)
 }
 
 // PostHandler は投稿関連のHTTPリクエストを処理する
@@ -39,10 +40,17 @@ func NewPostHandler(postService PostServiceInterface) *PostHandler {
 // GetAllPosts は GET /api/v1/posts を処理する
 // @Summary 投稿一覧取得
 // @Description 全ての投稿の一覧を取得します（総数付き）。認証済みの場合、各投稿のいいね状態（is_liked）を含みます
+// @Description セッション詳細による絞り込み条件を指定した場合、セッション詳細が未入力の投稿は含まれません
+// @Description 認証済みの場合、ミュート・ブロックしたユーザーと、自分をブロックしたユーザーの投稿は含まれません
+// @Description 認証済みの場合、有効なミュートルール（キーワード・フレーバー）に一致するスライドを含む投稿も含まれません
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Param min_rating query int false "総合評価の下限（1〜5）"
+// @Param bowl_type query string false "ボウルの種類（完全一致）"
+// @Param heat_management query string false "ヒートマネジメント（完全一致）"
 // @Success 200 {object} models.PostsResponse "投稿一覧と総数"
+// @Failure 400 {object} models.ValidationError "絞り込み条件が不正"
 // @Failure 500 {object} models.ServerError "サーバーエラー"
 // @Router /posts [get]
 func (h *PostHandler) GetAllPosts(c *gin.Context) {
@@ -57,7 +65,14 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 		userID = &uid
 	}
 
-	posts, err := h.postService.GetAllPosts(userID)
+	var filter models.PostFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		logging.L.Warn("invalid query parameters", "handler", "PostHandler", "method", "GetAllPosts", "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	posts, err := h.postService.GetAllPosts(userID, filter)
 	if err != nil {
 		logging.L.Error("failed to get all posts", "handler", "PostHandler", "method", "GetAllPosts", "error", err)
 		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
@@ -198,6 +213,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 // @Success 200 {object} models.Post "いいねが追加された投稿"
 // @Failure 400 {object} models.ValidationError "無効な投稿ID"
 // @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "投稿者にブロックされています"
 // @Failure 404 {object} models.NotFoundError "投稿が見つかりません"
 // @Failure 409 {object} models.ConflictError "既にいいね済み"
 // @Failure 500 {object} models.ServerError "サーバーエラー"
@@ -236,6 +252,10 @@ func (h *PostHandler) LikePost(c *gin.Context) {
 			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
 			return
 		}
+		if errors.Is(err, repositories.ErrBlocked) {
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeBlocked})
+			return
+		}
 		logging.L.Error("failed to like post", "handler", "PostHandler", "method", "LikePost", "user_id", userID, "post_id", id, "error", err)
 		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
 		return
@@ -424,3 +444,60 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	logging.L.Info("post updated", "handler", "PostHandler", "method", "UpdatePost", "user_id", userID, "post_id", id)
 	c.JSON(http.StatusOK, post)
 }
+
+// RemixPost は POST /api/v1/posts/:id/remix を処理する
+// @Summary 投稿をリミックス
+// @Description 指定された投稿のスライドのテキスト・フレーバーをコピーした新しい投稿を作成します（認証必須）。画像はコピーされません。作成された投稿の remixed_from には元投稿のIDが入ります
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "リミックス元の投稿ID"
+// @Success 201 {object} models.Post "作成されたリミックス投稿"
+// @Failure 400 {object} models.ValidationError "無効な投稿ID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "リミックス元の投稿者にブロックされています"
+// @Failure 404 {object} models.NotFoundError "投稿が見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Security BearerAuth
+// @Router /posts/{id}/remix [post]
+func (h *PostHandler) RemixPost(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "PostHandler", "method", "RemixPost")
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	post, err := h.postService.RemixPost(userID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+			return
+		}
+		if errors.Is(err, repositories.ErrBlocked) {
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeBlocked})
+			return
+		}
+		logging.L.Error("failed to remix post", "handler", "PostHandler", "method", "RemixPost", "user_id", userID, "post_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	logging.L.Info("post remixed", "handler", "PostHandler", "method", "RemixPost", "user_id", userID, "source_post_id", id, "post_id", post.ID)
+	c.JSON(http.StatusCreated, post)
+}
@@ -30,18 +30,19 @@ func init() {
 
 // mockPostService はテスト用のPostServiceモック
 type mockPostService struct {
-	getAllPostsFunc func(userID *int) ([]models.Post, error)
+	getAllPostsFunc func(userID *int, filter models.PostFilter) ([]models.Post, error)
 	getPostByIDFunc func(id int, userID *int) (*models.Post, error)
 	createPostFunc  func(userID int, input *models.CreatePostInput) (*models.Post, error)
 	likePostFunc    func(userID, postID int) (*models.Post, error)
 	unlikePostFunc  func(userID, postID int) (*models.Post, error)
 	deletePostFunc  func(userID, postID int) error
 	updatePostFunc  func(userID, postID int, input *models.UpdatePostInput) (*models.Post, error)
+	remixPostFunc   func(userID, sourcePostID int) (*models.Post, error)
 }
 
-func (m *mockPostService) GetAllPosts(userID *int) ([]models.Post, error) {
+func (m *mockPostService) GetAllPosts(userID *int, filter models.PostFilter) ([]models.Post, error) {
 	if m.getAllPostsFunc != nil {
-		return m.getAllPostsFunc(userID)
+		return m.getAllPostsFunc(userID, filter)
 	}
 	return nil, nil
 }
@@ -88,6 +89,13 @@ func (m *mockPostService) UpdatePost(userID, postID int, input *models.UpdatePos
 	return nil, nil
 }
 
+func (m *mockPostService) RemixPost(userID, sourcePostID int) (*models.Post, error) {
+	if m.remixPostFunc != nil {
+		return m.remixPostFunc(userID, sourcePostID)
+	}
+	return nil, nil
+}
+
 func TestCreatePost_NoAuth(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -573,6 +581,33 @@ func TestLikePost_UserNotFound_401(t *testing.T) {
 	assert.Equal(t, models.ErrCodeUnauthorized, response.Error)
 }
 
+func TestLikePost_Blocked_403(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockPostService{
+		likePostFunc: func(userID, postID int) (*models.Post, error) {
+			return nil, repositories.ErrBlocked
+		},
+	}
+	handler := NewPostHandler(mockService)
+
+	router := gin.New()
+	router.POST("/posts/:id/like", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		handler.LikePost(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/1/like", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	var response models.ForbiddenError
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ErrCodeBlocked, response.Error)
+}
+
 func TestUnlikePost_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -711,7 +746,7 @@ func TestGetAllPosts_WithOptionalAuth(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockService := &mockPostService{
-		getAllPostsFunc: func(userID *int) ([]models.Post, error) {
+		getAllPostsFunc: func(userID *int, filter models.PostFilter) ([]models.Post, error) {
 			posts := []models.Post{{ID: 1, Likes: 3}}
 			if userID != nil {
 				posts[0].IsLiked = true
@@ -879,7 +914,7 @@ func TestGetAllPosts_InternalError_500(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockService := &mockPostService{
-		getAllPostsFunc: func(userID *int) ([]models.Post, error) {
+		getAllPostsFunc: func(userID *int, filter models.PostFilter) ([]models.Post, error) {
 			return nil, errors.New("db connection failed")
 		},
 	}
@@ -899,6 +934,88 @@ func TestGetAllPosts_InternalError_500(t *testing.T) {
 	assert.Equal(t, models.ErrCodeInternalServer, response.Error)
 }
 
+func TestGetAllPosts_SessionFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var captured models.PostFilter
+	mockService := &mockPostService{
+		getAllPostsFunc: func(userID *int, filter models.PostFilter) ([]models.Post, error) {
+			captured = filter
+			return []models.Post{}, nil
+		},
+	}
+	handler := NewPostHandler(mockService)
+
+	router := gin.New()
+	router.GET("/posts", handler.GetAllPosts)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts?min_rating=4&bowl_type=%E3%83%95%E3%82%A1%E3%83%B3%E3%83%8D%E3%83%AB", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.NotNil(t, captured.MinRating) {
+		assert.Equal(t, 4, *captured.MinRating)
+	}
+	if assert.NotNil(t, captured.BowlType) {
+		assert.Equal(t, "ファンネル", *captured.BowlType)
+	}
+	assert.Nil(t, captured.HeatManagement)
+}
+
+func TestGetAllPosts_InvalidMinRating_400(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	called := false
+	mockService := &mockPostService{
+		getAllPostsFunc: func(userID *int, filter models.PostFilter) ([]models.Post, error) {
+			called = true
+			return nil, nil
+		},
+	}
+	handler := NewPostHandler(mockService)
+
+	router := gin.New()
+	router.GET("/posts", handler.GetAllPosts)
+
+	for _, q := range []string{"min_rating=6", "min_rating=0", "min_rating=abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/posts?"+q, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, q)
+	}
+	assert.False(t, called)
+}
+
+func TestCreatePost_InvalidSession_400(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockPostService{
+		createPostFunc: func(userID int, input *models.CreatePostInput) (*models.Post, error) {
+			t.Fatal("service should not be called")
+			return nil, nil
+		},
+	}
+	handler := NewPostHandler(mockService)
+
+	router := gin.New()
+	router.POST("/posts", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		handler.CreatePost(c)
+	})
+
+	for _, session := range []string{`{"rating":6}`, `{"coal_count":0}`, `{"duration_minutes":601}`} {
+		body := `{"slides":[{"image_url":"/images/a.jpg","text":"t"}],"session":` + session + `}`
+		req := httptest.NewRequest(http.MethodPost, "/posts", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, session)
+	}
+}
+
 func TestCreatePost_ImageNotFound_404(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -1455,3 +1572,127 @@ func TestUpdatePost_ServerError(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, models.ErrCodeInternalServer, response.Error)
 }
+
+func TestRemixPost_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var capturedUserID, capturedSourceID int
+	mockService := &mockPostService{
+		remixPostFunc: func(userID, sourcePostID int) (*models.Post, error) {
+			capturedUserID = userID
+			capturedSourceID = sourcePostID
+			return &models.Post{
+				ID:          20,
+				UserID:      userID,
+				Slides:      []models.Slide{{Text: "ミント多め"}},
+				RemixedFrom: &sourcePostID,
+			}, nil
+		},
+	}
+	handler := NewPostHandler(mockService)
+
+	router := gin.New()
+	router.POST("/posts/:id/remix", func(c *gin.Context) {
+		c.Set("user_id", 2)
+		handler.RemixPost(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/5/remix", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 2, capturedUserID)
+	assert.Equal(t, 5, capturedSourceID)
+	var response models.Post
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, response.ID)
+	if assert.NotNil(t, response.RemixedFrom) {
+		assert.Equal(t, 5, *response.RemixedFrom)
+	}
+	assert.Equal(t, "", response.Slides[0].ImageURL)
+}
+
+func TestRemixPost_NoAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewPostHandler(&mockPostService{})
+
+	router := gin.New()
+	router.POST("/posts/:id/remix", handler.RemixPost)
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/5/remix", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRemixPost_InvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewPostHandler(&mockPostService{})
+
+	router := gin.New()
+	router.POST("/posts/:id/remix", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		handler.RemixPost(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/abc/remix", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRemixPost_SourceNotFound_404(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockPostService{
+		remixPostFunc: func(userID, sourcePostID int) (*models.Post, error) {
+			return nil, repositories.ErrPostNotFound
+		},
+	}
+	handler := NewPostHandler(mockService)
+
+	router := gin.New()
+	router.POST("/posts/:id/remix", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		handler.RemixPost(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/99/remix", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var response models.NotFoundError
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ErrCodeNotFound, response.Error)
+}
+
+func TestRemixPost_InternalError_500(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockPostService{
+		remixPostFunc: func(userID, sourcePostID int) (*models.Post, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	handler := NewPostHandler(mockService)
+
+	router := gin.New()
+	router.POST("/posts/:id/remix", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		handler.RemixPost(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/1/remix", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleServiceInterface は RoleService のインターフェース（テスト用）
+type RoleServiceInterface interface {
+	ChangeRole(actorID, userID int, role string) error
+}
+
+// RoleHandler はロール関連のHTTPリクエストを処理する
+type RoleHandler struct {
+	roleService RoleServiceInterface
+}
+
+// NewRoleHandler は新しい RoleHandler を作成する
+func NewRoleHandler(roleService RoleServiceInterface) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// UpdateUserRole は PUT /api/v1/admin/users/:id/role を処理する
+// @Summary ユーザーのロール変更（管理者）
+// @Description ユーザーのロールを user / moderator / admin のいずれかに変更します。変更はモデレーションの記録に残ります
+// @Description 変更後のロールは対象ユーザーの次回のトークン再発行から反映されます。自分自身のロールは変更できません
+// @Tags moderation
+// @Accept json
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Param input body models.UpdateRoleInput true "変更後のロール"
+// @Success 204 "変更しました"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー（自分自身のロールの変更を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 403 {object} models.ForbiddenError "管理者ではありません"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /admin/users/{id}/role [put]
+func (h *RoleHandler) UpdateUserRole(c *gin.Context) {
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	actorID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	userID, ok := actorID.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "RoleHandler", "method", "UpdateUserRole")
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	var input models.UpdateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "RoleHandler", "method", "UpdateUserRole", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	if err := h.roleService.ChangeRole(userID, targetID, input.Role); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrCannotChangeOwnRole):
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		case errors.Is(err, repositories.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+		default:
+			logging.L.Error("failed to change user role", "handler", "RoleHandler", "method", "UpdateUserRole", "user_id", userID, "target_user_id", targetID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRoleService はテスト用の RoleService モック
+type mockRoleService struct {
+	changeRoleFunc func(actorID, userID int, role string) error
+}
+
+func (m *mockRoleService) ChangeRole(actorID, userID int, role string) error {
+	if m.changeRoleFunc != nil {
+		return m.changeRoleFunc(actorID, userID, role)
+	}
+	return nil
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotActor, gotUser int
+	var gotRole string
+	handler := NewRoleHandler(&mockRoleService{
+		changeRoleFunc: func(actorID, userID int, role string) error {
+			switch userID {
+			case 1:
+				return services.ErrCannotChangeOwnRole
+			case 99:
+				return repositories.ErrUserNotFound
+			}
+			gotActor, gotUser, gotRole = actorID, userID, role
+			return nil
+		},
+	})
+	router := gin.New()
+	router.PUT("/admin/users/:id/role", withUserID(1), handler.UpdateUserRole)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{"success", "/admin/users/2/role", `{"role":"moderator"}`, http.StatusNoContent, ""},
+		{"unknown role", "/admin/users/2/role", `{"role":"owner"}`, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"own role", "/admin/users/1/role", `{"role":"user"}`, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"user not found", "/admin/users/99/role", `{"role":"admin"}`, http.StatusNotFound, models.ErrCodeNotFound},
+		{"invalid id", "/admin/users/abc/role", `{"role":"admin"}`, http.StatusBadRequest, models.ErrCodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var res map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, tt.wantError, res["error"])
+			}
+		})
+	}
+	assert.Equal(t, 1, gotActor)
+	assert.Equal(t, 2, gotUser)
+	assert.Equal(t, models.RoleModerator, gotRole)
+}
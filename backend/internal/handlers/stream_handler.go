@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultStreamHeartbeatInterval はストリームのハートビート送信間隔の既定値
+// プロキシ・ロードバランサーのアイドルタイムアウトより短くする
+const defaultStreamHeartbeatInterval = 25 * time.Second
+
+// StreamBrokerInterface は EventBroker のインターフェース（テスト用）
+type StreamBrokerInterface interface {
+	Subscribe(userID int) (*services.Subscription, error)
+	Unsubscribe(sub *services.Subscription)
+}
+
+// StreamTokenVerifierInterface は AuthService のアクセストークンの確認のインターフェース（テスト用）
+type StreamTokenVerifierInterface interface {
+	VerifyAccessToken(claims *auth.Claims) error
+}
+
+// StreamPostVisibilityInterface は PostService の投稿の表示可否の判定のインターフェース（テスト用）
+type StreamPostVisibilityInterface interface {
+	IsPostVisibleTo(viewerID, postID int) (bool, error)
+}
+
+// StreamHandler はServer-Sent Eventsによるリアルタイム配信を処理する
+type StreamHandler struct {
+	broker StreamBrokerInterface
+	// verifier は接続中のアクセストークンがログアウト・利用停止などで無効になっていないかをハートビートごとに確認する
+	verifier StreamTokenVerifierInterface
+	// posts は全員に配信される投稿のイベントを、タイムラインと同じ条件（ブロック・ミュート・ミュートルール）で接続ごとに絞り込む
+	posts             StreamPostVisibilityInterface
+	heartbeatInterval time.Duration
+}
+
+// NewStreamHandler は新しい StreamHandler を作成する
+func NewStreamHandler(broker StreamBrokerInterface, verifier StreamTokenVerifierInterface, posts StreamPostVisibilityInterface) *StreamHandler {
+	return &StreamHandler{
+		broker:            broker,
+		verifier:          verifier,
+		posts:             posts,
+		heartbeatInterval: defaultStreamHeartbeatInterval,
+	}
+}
+
+// streamEventPostID は投稿に関するイベントの投稿IDを返す（投稿に関するイベントでなければ false）
+func streamEventPostID(event models.StreamEvent) (int, bool) {
+	switch data := event.Data.(type) {
+	case models.PostCreatedStreamData:
+		return data.PostID, true
+	case models.LikeCountStreamData:
+		return data.PostID, true
+	}
+	return 0, false
+}
+
+// visibleTo は event を userID の接続に配信してよいかを返す
+// 投稿ごとの判定は visible に記録し、同じ投稿のイベントでは問い合わせない。判定に失敗したイベントは配信しない
+func (h *StreamHandler) visibleTo(userID int, event models.StreamEvent, visible map[int]bool) bool {
+	postID, ok := streamEventPostID(event)
+	if !ok {
+		return true
+	}
+	if v, ok := visible[postID]; ok {
+		return v
+	}
+	v, err := h.posts.IsPostVisibleTo(userID, postID)
+	if err != nil {
+		logging.L.Error("failed to check stream post visibility", "handler", "StreamHandler", "method", "Stream", "user_id", userID, "post_id", postID, "error", err)
+		return false
+	}
+	visible[postID] = v
+	return v
+}
+
+// Stream は GET /api/v1/stream を処理する
+// @Summary リアルタイムイベントストリーム
+// @Description Server-Sent Events で新規投稿（post_created）・いいね数の変化（like_count_changed）・自分宛ての通知（notification）を配信します
+// @Description 投稿のイベントはタイムラインと同じく、ブロック・ミュートの関係にあるユーザーの投稿とミュートルールに一致する投稿のものを配信しません
+// @Description 接続維持のため一定間隔でコメント行（": heartbeat"）を送信します。切断された場合はクライアント側で再接続してください
+// @Description アクセストークンの有効期限が切れた場合、ログアウト・利用停止などで無効になった場合はサーバー側から切断します
+// @Tags stream
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Success 200 {string} string "イベントストリーム"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 503 {object} models.ServerError "サーバー停止中"
+// @Router /stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "StreamHandler", "method", "Stream")
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	// 接続中もトークンが有効かを確認し続けるため、AuthMiddleware がセットしたクレームを使う
+	claims := tokenClaims(c)
+	if claims == nil || claims.ExpiresAt == nil {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return
+	}
+
+	sub, err := h.broker.Subscribe(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrBrokerClosed) {
+			c.JSON(http.StatusServiceUnavailable, models.ServerError{Error: models.ErrCodeInternalServer})
+			return
+		}
+		logging.L.Error("failed to subscribe stream", "handler", "StreamHandler", "method", "Stream", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	defer h.broker.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// nginx 等のリバースプロキシによるバッファリングを無効化する
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+	// 有効期限が切れたらハートビートを待たずに切断する
+	expiry := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+	defer expiry.Stop()
+
+	// 投稿ごとの表示可否（ブロック・ミュートの変更を反映するため、ハートビートごとに破棄する）
+	visible := map[int]bool{}
+
+	logging.L.Debug("stream connected", "handler", "StreamHandler", "method", "Stream", "user_id", userID)
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			logging.L.Debug("stream disconnected by client", "handler", "StreamHandler", "method", "Stream", "user_id", userID)
+			return
+		case <-sub.Done():
+			// サーバーのシャットダウン、またはバッファあふれによる切断
+			logging.L.Debug("stream closed by server", "handler", "StreamHandler", "method", "Stream", "user_id", userID)
+			return
+		case event := <-sub.Events():
+			if !h.visibleTo(userID, event, visible) {
+				continue
+			}
+			c.SSEvent(event.Type, event.Data)
+			c.Writer.Flush()
+		case <-expiry.C:
+			logging.L.Debug("stream closed on access token expiry", "handler", "StreamHandler", "method", "Stream", "user_id", userID)
+			return
+		case <-heartbeat.C:
+			// ログアウト・利用停止などで無効になったトークンの接続は切断する（確認に失敗した場合も、再接続時の認証に任せて切断する）
+			if err := h.verifier.VerifyAccessToken(claims); err != nil {
+				var suspension *auth.SuspensionError
+				if errors.Is(err, auth.ErrRevokedToken) || errors.As(err, &suspension) {
+					logging.L.Debug("stream closed on revoked access token", "handler", "StreamHandler", "method", "Stream", "user_id", userID)
+				} else {
+					logging.L.Error("failed to verify stream access token", "handler", "StreamHandler", "method", "Stream", "user_id", userID, "error", err)
+				}
+				return
+			}
+			clear(visible)
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
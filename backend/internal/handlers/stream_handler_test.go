@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubStreamVerifier は err を返すアクセストークンの確認（err は接続中に差し替えられる）
+type stubStreamVerifier struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (v *stubStreamVerifier) VerifyAccessToken(claims *auth.Claims) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.err
+}
+
+func (v *stubStreamVerifier) setErr(err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.err = err
+}
+
+// stubStreamPosts は hidden に含まれる投稿を閲覧者に表示できないものとして扱う投稿の表示可否の判定
+type stubStreamPosts struct {
+	hidden map[int]bool
+}
+
+func (p stubStreamPosts) IsPostVisibleTo(viewerID, postID int) (bool, error) {
+	return !p.hidden[postID], nil
+}
+
+// withStreamToken は AuthMiddleware と同じく、ユーザーIDと expiresAt に期限切れになるアクセストークンのクレームをセットする
+func withStreamToken(userID int, expiresAt time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("token_claims", &auth.Claims{
+			UserID:           int64(userID),
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expiresAt)},
+		})
+		c.Next()
+	}
+}
+
+// waitStreamClosed はサーバー側からストリームが切断されるまで待つ
+func waitStreamClosed(t *testing.T, lines <-chan string) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-lines:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("stream was not closed by server")
+		}
+	}
+}
+
+// readStreamLine はストリームから空行以外の1行をタイムアウト付きで読み取る
+func readStreamLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			t.Fatalf("stream closed unexpectedly")
+		}
+		return line
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for stream data")
+	}
+	return ""
+}
+
+// openStream はストリームに接続し、空行以外の行を流すチャネルを返す
+func openStream(t *testing.T, url string) (<-chan string, *http.Response) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("failed to connect stream: %v", err)
+	}
+	lines := make(chan string, 64)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				lines <- line
+			}
+		}
+	}()
+	return lines, resp
+}
+
+func TestStream_DeliversEventsAndHeartbeats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	broker := services.NewEventBroker(0)
+	handler := NewStreamHandler(broker, &stubStreamVerifier{}, stubStreamPosts{})
+	handler.heartbeatInterval = 50 * time.Millisecond
+	router := gin.New()
+	router.GET("/stream", withStreamToken(1, time.Now().Add(time.Hour)), handler.Stream)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	lines, resp := openStream(t, server.URL+"/stream")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"))
+
+	// レスポンスヘッダー受信時点で購読は開始している
+	broker.Publish(models.StreamEvent{
+		Type: models.StreamEventLikeCountChanged,
+		Data: models.LikeCountStreamData{PostID: 10, Likes: 3},
+	})
+	other := 2
+	broker.Publish(models.StreamEvent{Type: models.StreamEventNotification, UserID: &other})
+
+	line := readStreamLine(t, lines)
+	for strings.HasPrefix(line, ":") {
+		line = readStreamLine(t, lines)
+	}
+	assert.Equal(t, "event:like_count_changed", line)
+	assert.Equal(t, `data:{"post_id":10,"likes":3}`, readStreamLine(t, lines))
+
+	// 他ユーザー宛ての通知は届かず、ハートビートのみ届く
+	assert.Equal(t, ": heartbeat", readStreamLine(t, lines))
+}
+
+func TestStream_FiltersHiddenPostEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	broker := services.NewEventBroker(0)
+	handler := NewStreamHandler(broker, &stubStreamVerifier{}, stubStreamPosts{hidden: map[int]bool{10: true}})
+	handler.heartbeatInterval = time.Hour
+	router := gin.New()
+	router.GET("/stream", withStreamToken(1, time.Now().Add(time.Hour)), handler.Stream)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	lines, resp := openStream(t, server.URL+"/stream")
+	defer resp.Body.Close()
+
+	// ブロック・ミュートなどで閲覧者に表示できない投稿のイベントは届かない
+	broker.Publish(models.StreamEvent{Type: models.StreamEventPostCreated, Data: models.PostCreatedStreamData{PostID: 10, UserID: 2}})
+	broker.Publish(models.StreamEvent{Type: models.StreamEventLikeCountChanged, Data: models.LikeCountStreamData{PostID: 10, Likes: 1}})
+	broker.Publish(models.StreamEvent{Type: models.StreamEventPostCreated, Data: models.PostCreatedStreamData{PostID: 11, UserID: 3}})
+
+	assert.Equal(t, "event:post_created", readStreamLine(t, lines))
+	assert.Equal(t, `data:{"post_id":11,"user_id":3}`, readStreamLine(t, lines))
+}
+
+func TestStream_Unauthorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/stream", NewStreamHandler(services.NewEventBroker(0), &stubStreamVerifier{}, stubStreamPosts{}).Stream)
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestStream_ClosesOnRevokedOrExpiredToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		verifyErr error
+	}{
+		{name: "異常系: ログアウト・トークンの無効化でハートビート時に切断する", expiresAt: time.Now().Add(time.Hour), verifyErr: auth.ErrRevokedToken},
+		{name: "異常系: 利用停止でハートビート時に切断する", expiresAt: time.Now().Add(time.Hour), verifyErr: &auth.SuspensionError{Reason: "スパム行為のため"}},
+		{name: "異常系: 有効期限が切れたら切断する", expiresAt: time.Now().Add(100 * time.Millisecond)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := &stubStreamVerifier{}
+			handler := NewStreamHandler(services.NewEventBroker(0), verifier, stubStreamPosts{})
+			handler.heartbeatInterval = 50 * time.Millisecond
+			if tt.verifyErr == nil {
+				// 有効期限の確認をハートビートに頼らないことを確かめる
+				handler.heartbeatInterval = time.Hour
+			}
+			router := gin.New()
+			router.GET("/stream", withStreamToken(1, tt.expiresAt), handler.Stream)
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			lines, resp := openStream(t, server.URL+"/stream")
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			if tt.verifyErr != nil {
+				assert.Equal(t, ": heartbeat", readStreamLine(t, lines))
+				verifier.setErr(tt.verifyErr)
+			}
+			waitStreamClosed(t, lines)
+		})
+	}
+}
+
+func TestStream_ShutdownClosesConnections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	broker := services.NewEventBroker(0)
+	router := gin.New()
+	router.GET("/stream", withStreamToken(1, time.Now().Add(time.Hour)), NewStreamHandler(broker, &stubStreamVerifier{}, stubStreamPosts{}).Stream)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := &http.Server{Handler: router}
+	srv.RegisterOnShutdown(broker.Close)
+	go func() { _ = srv.Serve(listener) }()
+
+	lines, resp := openStream(t, "http://"+listener.Addr().String()+"/stream")
+	defer resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	assert.NoError(t, srv.Shutdown(ctx), "Shutdown should not wait for the stream connection to time out")
+
+	// 接続はサーバー側から終了される
+	select {
+	case _, ok := <-lines:
+		for ok {
+			_, ok = <-lines
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("stream was not closed by shutdown")
+	}
+
+	// シャットダウン後の新規接続は受け付けない
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -92,6 +92,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // GetUserPosts は GET /api/v1/users/:id/posts を処理する
 // @Summary ユーザーの投稿一覧取得
 // @Description 指定されたユーザーの全ての投稿を取得します（総数付き）
+// @Description 認証済みの場合、有効なミュートルール（キーワード・フレーバー）に一致するスライドを含む投稿は含まれません
 // @Tags users
 // @Accept json
 // @Produce json
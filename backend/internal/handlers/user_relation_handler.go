@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserRelationServiceInterface は UserRelationService のインターフェース（テスト用）
+type UserRelationServiceInterface interface {
+	Block(userID, targetID int) error
+	Unblock(userID, targetID int) error
+	ListBlocked(userID int) ([]models.RelatedUser, error)
+	Mute(userID, targetID int) error
+	Unmute(userID, targetID int) error
+	ListMuted(userID int) ([]models.RelatedUser, error)
+}
+
+// UserRelationHandler はユーザーのブロック・ミュート関連のHTTPリクエストを処理する
+type UserRelationHandler struct {
+	relationService UserRelationServiceInterface
+}
+
+// NewUserRelationHandler は新しい UserRelationHandler を作成する
+func NewUserRelationHandler(relationService UserRelationServiceInterface) *UserRelationHandler {
+	return &UserRelationHandler{
+		relationService: relationService,
+	}
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *UserRelationHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "UserRelationHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// handleRelationChange はパスのユーザーIDを対象にブロック・ミュートの追加・解除を行い、204 を返す
+func (h *UserRelationHandler) handleRelationChange(c *gin.Context, method string, change func(userID, targetID int) error) {
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, method)
+	if !ok {
+		return
+	}
+
+	if err := change(userID, targetID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrCannotRelateToSelf):
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		case errors.Is(err, repositories.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+		case errors.Is(err, repositories.ErrAlreadyBlocked):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeAlreadyBlocked})
+		case errors.Is(err, repositories.ErrNotBlocked):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeNotBlocked})
+		case errors.Is(err, repositories.ErrAlreadyMuted):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeAlreadyMuted})
+		case errors.Is(err, repositories.ErrNotMuted):
+			c.JSON(http.StatusConflict, models.ConflictError{Error: models.ErrCodeNotMuted})
+		default:
+			logging.L.Error("failed to change user relation", "handler", "UserRelationHandler", "method", method, "user_id", userID, "target_id", targetID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// handleRelationList は認証ユーザーがブロック・ミュートしたユーザーの一覧を返す
+func (h *UserRelationHandler) handleRelationList(c *gin.Context, method string, list func(userID int) ([]models.RelatedUser, error)) {
+	userID, ok := h.requireUserID(c, method)
+	if !ok {
+		return
+	}
+	users, err := list(userID)
+	if err != nil {
+		logging.L.Error("failed to list related users", "handler", "UserRelationHandler", "method", method, "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.RelatedUsersResponse{Users: users, Total: len(users)})
+}
+
+// BlockUser は POST /api/v1/users/:id/block を処理する
+// @Summary ユーザーをブロック
+// @Description 指定したユーザーをブロックします。ブロックしたユーザーの投稿はタイムラインに表示されず、相手は自分の投稿にいいね・リミックスできなくなります
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Success 204 "ブロックしました"
+// @Failure 400 {object} models.ValidationError "無効なユーザーID（自分自身を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 409 {object} models.ConflictError "ブロック済み"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/block [post]
+func (h *UserRelationHandler) BlockUser(c *gin.Context) {
+	h.handleRelationChange(c, "BlockUser", h.relationService.Block)
+}
+
+// UnblockUser は DELETE /api/v1/users/:id/block を処理する
+// @Summary ユーザーのブロックを解除
+// @Description 指定したユーザーのブロックを解除します
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Success 204 "ブロックを解除しました"
+// @Failure 400 {object} models.ValidationError "無効なユーザーID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 409 {object} models.ConflictError "ブロックしていません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/block [delete]
+func (h *UserRelationHandler) UnblockUser(c *gin.Context) {
+	h.handleRelationChange(c, "UnblockUser", h.relationService.Unblock)
+}
+
+// MuteUser は POST /api/v1/users/:id/mute を処理する
+// @Summary ユーザーをミュート
+// @Description 指定したユーザーをミュートします。ミュートしたユーザーの投稿はタイムラインに表示されなくなります（相手には通知されません）
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Success 204 "ミュートしました"
+// @Failure 400 {object} models.ValidationError "無効なユーザーID（自分自身を含む）"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 409 {object} models.ConflictError "ミュート済み"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/mute [post]
+func (h *UserRelationHandler) MuteUser(c *gin.Context) {
+	h.handleRelationChange(c, "MuteUser", h.relationService.Mute)
+}
+
+// UnmuteUser は DELETE /api/v1/users/:id/mute を処理する
+// @Summary ユーザーのミュートを解除
+// @Description 指定したユーザーのミュートを解除します
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "ユーザーID"
+// @Success 204 "ミュートを解除しました"
+// @Failure 400 {object} models.ValidationError "無効なユーザーID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 409 {object} models.ConflictError "ミュートしていません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/mute [delete]
+func (h *UserRelationHandler) UnmuteUser(c *gin.Context) {
+	h.handleRelationChange(c, "UnmuteUser", h.relationService.Unmute)
+}
+
+// GetMyBlocks は GET /api/v1/users/me/blocks を処理する
+// @Summary ブロックしたユーザー一覧
+// @Description 認証ユーザーがブロックしたユーザーを新しい順に取得します
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RelatedUsersResponse "ブロックしたユーザー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/blocks [get]
+func (h *UserRelationHandler) GetMyBlocks(c *gin.Context) {
+	h.handleRelationList(c, "GetMyBlocks", h.relationService.ListBlocked)
+}
+
+// GetMyMutes は GET /api/v1/users/me/mutes を処理する
+// @Summary ミュートしたユーザー一覧
+// @Description 認証ユーザーがミュートしたユーザーを新しい順に取得します
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RelatedUsersResponse "ミュートしたユーザー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/me/mutes [get]
+func (h *UserRelationHandler) GetMyMutes(c *gin.Context) {
+	h.handleRelationList(c, "GetMyMutes", h.relationService.ListMuted)
+}
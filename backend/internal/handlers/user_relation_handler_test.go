@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockUserRelationService はテスト用の UserRelationService モック
+type mockUserRelationService struct {
+	changeErr    error
+	gotUserID    int
+	gotTargetID  int
+	relatedUsers []models.RelatedUser
+}
+
+func (m *mockUserRelationService) change(userID, targetID int) error {
+	m.gotUserID = userID
+	m.gotTargetID = targetID
+	return m.changeErr
+}
+
+func (m *mockUserRelationService) Block(userID, targetID int) error {
+	return m.change(userID, targetID)
+}
+
+func (m *mockUserRelationService) Unblock(userID, targetID int) error {
+	return m.change(userID, targetID)
+}
+
+func (m *mockUserRelationService) Mute(userID, targetID int) error {
+	return m.change(userID, targetID)
+}
+
+func (m *mockUserRelationService) Unmute(userID, targetID int) error {
+	return m.change(userID, targetID)
+}
+
+func (m *mockUserRelationService) ListBlocked(userID int) ([]models.RelatedUser, error) {
+	return m.relatedUsers, nil
+}
+
+func (m *mockUserRelationService) ListMuted(userID int) ([]models.RelatedUser, error) {
+	return m.relatedUsers, nil
+}
+
+// newUserRelationRouter は本番と同じパス構成でブロック・ミュートのエンドポイントを登録したルーターを返す
+func newUserRelationRouter(handler *UserRelationHandler, userID int) *gin.Engine {
+	router := gin.New()
+	auth := withUserID(userID)
+	router.POST("/users/:id/block", auth, handler.BlockUser)
+	router.DELETE("/users/:id/block", auth, handler.UnblockUser)
+	router.POST("/users/:id/mute", auth, handler.MuteUser)
+	router.DELETE("/users/:id/mute", auth, handler.UnmuteUser)
+	router.GET("/users/me/blocks", auth, handler.GetMyBlocks)
+	router.GET("/users/me/mutes", auth, handler.GetMyMutes)
+	return router
+}
+
+func TestBlockUser_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &mockUserRelationService{}
+	router := newUserRelationRouter(NewUserRelationHandler(svc), 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/2/block", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 1, svc.gotUserID)
+	assert.Equal(t, 2, svc.gotTargetID)
+}
+
+func TestUserRelationChange_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{"invalid id", http.MethodPost, "/users/abc/block", nil, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"self", http.MethodPost, "/users/1/mute", services.ErrCannotRelateToSelf, http.StatusBadRequest, models.ErrCodeValidationFailed},
+		{"user not found", http.MethodPost, "/users/99/block", repositories.ErrUserNotFound, http.StatusNotFound, models.ErrCodeNotFound},
+		{"already blocked", http.MethodPost, "/users/2/block", repositories.ErrAlreadyBlocked, http.StatusConflict, models.ErrCodeAlreadyBlocked},
+		{"not blocked", http.MethodDelete, "/users/2/block", repositories.ErrNotBlocked, http.StatusConflict, models.ErrCodeNotBlocked},
+		{"already muted", http.MethodPost, "/users/2/mute", repositories.ErrAlreadyMuted, http.StatusConflict, models.ErrCodeAlreadyMuted},
+		{"not muted", http.MethodDelete, "/users/2/mute", repositories.ErrNotMuted, http.StatusConflict, models.ErrCodeNotMuted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newUserRelationRouter(NewUserRelationHandler(&mockUserRelationService{changeErr: tt.err}), 1)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantErr)
+		})
+	}
+}
+
+func TestGetMyMutes_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &mockUserRelationService{relatedUsers: []models.RelatedUser{{User: models.User{ID: 3, DisplayName: "u3"}}}}
+	router := newUserRelationRouter(NewUserRelationHandler(svc), 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/me/mutes", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.RelatedUsersResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, 3, res.Users[0].User.ID)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserStatsServiceInterface は UserStatsService のインターフェース（テスト用）
+type UserStatsServiceInterface interface {
+	GetUserStats(userID int) (*models.UserStats, error)
+}
+
+// UserStatsHandler はユーザー統計関連のHTTPリクエストを処理する
+type UserStatsHandler struct {
+	statsService UserStatsServiceInterface
+}
+
+// NewUserStatsHandler は新しい UserStatsHandler を作成する
+func NewUserStatsHandler(statsService UserStatsServiceInterface) *UserStatsHandler {
+	return &UserStatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetUserStats は GET /api/v1/users/:id/stats を処理する
+// @Summary ユーザー統計取得
+// @Description 指定されたユーザーの月別投稿数、よく使うフレーバー・カテゴリ、使用フレーバー数、受け取ったいいね数の推移、最長連続投稿日数を取得します
+// @Description 統計は一定時間キャッシュされ、投稿・いいね時に再集計されます
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ユーザーID"
+// @Success 200 {object} models.UserStats "ユーザー統計"
+// @Failure 400 {object} models.ValidationError "無効なユーザーID"
+// @Failure 404 {object} models.NotFoundError "ユーザーが見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /users/{id}/stats [get]
+func (h *UserStatsHandler) GetUserStats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	stats, err := h.statsService.GetUserStats(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to get user stats", "handler", "UserStatsHandler", "method", "GetUserStats", "user_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
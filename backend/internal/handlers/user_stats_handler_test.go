@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockUserStatsService はテスト用の UserStatsService モック
+type mockUserStatsService struct {
+	getUserStatsFunc func(userID int) (*models.UserStats, error)
+}
+
+func (m *mockUserStatsService) GetUserStats(userID int) (*models.UserStats, error) {
+	if m.getUserStatsFunc != nil {
+		return m.getUserStatsFunc(userID)
+	}
+	return nil, nil
+}
+
+func newUserStatsRouter(service UserStatsServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewUserStatsHandler(service)
+	router := gin.New()
+	router.GET("/users/:id/stats", handler.GetUserStats)
+	return router
+}
+
+func TestGetUserStats_Success(t *testing.T) {
+	router := newUserStatsRouter(&mockUserStatsService{
+		getUserStatsFunc: func(userID int) (*models.UserStats, error) {
+			return &models.UserStats{
+				UserID:            userID,
+				TotalPosts:        3,
+				PostsPerMonth:     []models.MonthlyCount{{Month: "2025-01", Count: 3}},
+				TopFlavors:        []models.FlavorUsage{{Flavor: models.Flavor{ID: 1, Name: "ミント"}, Count: 2}},
+				LongestStreakDays: 2,
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1/stats", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response models.UserStats
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.UserID)
+	assert.Equal(t, 3, response.TotalPosts)
+	assert.Equal(t, "2025-01", response.PostsPerMonth[0].Month)
+	assert.Equal(t, "ミント", response.TopFlavors[0].Flavor.Name)
+	assert.Equal(t, 2, response.LongestStreakDays)
+}
+
+func TestGetUserStats_InvalidID(t *testing.T) {
+	router := newUserStatsRouter(&mockUserStatsService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/abc/stats", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetUserStats_NotFound(t *testing.T) {
+	router := newUserStatsRouter(&mockUserStatsService{
+		getUserStatsFunc: func(userID int) (*models.UserStats, error) {
+			return nil, repositories.ErrUserNotFound
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/999/stats", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var response models.NotFoundError
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ErrCodeNotFound, response.Error)
+}
+
+func TestGetUserStats_ServerError(t *testing.T) {
+	router := newUserStatsRouter(&mockUserStatsService{
+		getUserStatsFunc: func(userID int) (*models.UserStats, error) {
+			return nil, assert.AnError
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1/stats", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
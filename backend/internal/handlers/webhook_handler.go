@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookServiceInterface は WebhookService のインターフェース（テスト用）
+type WebhookServiceInterface interface {
+	CreateEndpoint(userID int, input *models.CreateWebhookInput) (*models.WebhookEndpoint, error)
+	ListEndpoints(userID int) ([]models.WebhookEndpoint, error)
+	DeleteEndpoint(userID, endpointID int) error
+	GetDeliveries(userID, endpointID int, query models.WebhookDeliveryListQuery) (*models.WebhookDeliveriesResponse, error)
+}
+
+// WebhookHandler は Webhook 関連のHTTPリクエストを処理する
+type WebhookHandler struct {
+	webhookService WebhookServiceInterface
+}
+
+// NewWebhookHandler は新しい WebhookHandler を作成する
+func NewWebhookHandler(webhookService WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// requireUserID は認証済みユーザーIDを取得する。取得できない場合はエラーレスポンスを書き込み false を返す
+func (h *WebhookHandler) requireUserID(c *gin.Context, method string) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+		return 0, false
+	}
+	userID, ok := userIDValue.(int)
+	if !ok {
+		logging.L.Error("invalid user_id type in context", "handler", "WebhookHandler", "method", method)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return 0, false
+	}
+	return userID, true
+}
+
+// CreateWebhook は POST /api/v1/webhooks を処理する
+// @Summary Webhook 登録
+// @Description 自分の投稿の作成・削除、自分の投稿へのいいねのイベントを受け取る配信先URLを登録します（他のユーザーの投稿のイベントは配信されません）
+// @Description レスポンスの secret は登録時にのみ返されます。各配信の X-Webhook-Signature ヘッダーは "sha256=" + HMAC-SHA256(secret, "<X-Webhook-Timestamp>.<本文>") の16進数です
+// @Description 2xx 以外の応答や接続エラーの場合は指数バックオフで再送されます
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.CreateWebhookInput true "配信先URLと購読するイベント"
+// @Success 201 {object} models.WebhookEndpoint "登録した Webhook（secret を含む）"
+// @Failure 400 {object} models.ValidationError "バリデーションエラー"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "CreateWebhook")
+	if !ok {
+		return
+	}
+
+	var input models.CreateWebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logging.L.Warn("invalid request body", "handler", "WebhookHandler", "method", "CreateWebhook", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(userID, &input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookURL) {
+			c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+			return
+		}
+		logging.L.Error("failed to create webhook", "handler", "WebhookHandler", "method", "CreateWebhook", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// ListWebhooks は GET /api/v1/webhooks を処理する
+// @Summary Webhook 一覧取得
+// @Description 認証ユーザーが登録した Webhook を新しい順に取得します（secret は含まれません）
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.WebhookEndpointsResponse "Webhook 一覧"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	userID, ok := h.requireUserID(c, "ListWebhooks")
+	if !ok {
+		return
+	}
+
+	endpoints, err := h.webhookService.ListEndpoints(userID)
+	if err != nil {
+		logging.L.Error("failed to list webhooks", "handler", "WebhookHandler", "method", "ListWebhooks", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, models.WebhookEndpointsResponse{Endpoints: endpoints, Total: len(endpoints)})
+}
+
+// DeleteWebhook は DELETE /api/v1/webhooks/:id を処理する
+// @Summary Webhook 削除
+// @Description 認証ユーザーが登録した Webhook を配信ログとともに削除します
+// @Tags webhooks
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 204 "削除しました"
+// @Failure 400 {object} models.ValidationError "無効な Webhook ID"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "Webhook が見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "DeleteWebhook")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(userID, id); err != nil {
+		if errors.Is(err, repositories.ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to delete webhook", "handler", "WebhookHandler", "method", "DeleteWebhook", "user_id", userID, "endpoint_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries は GET /api/v1/webhooks/:id/deliveries を処理する
+// @Summary Webhook 配信ログ取得
+// @Description 認証ユーザーが登録した Webhook の配信ログを新しい順に取得します（状態・試行回数・直近の応答を含む）
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param limit query int false "取得件数（1〜100、省略時は20）"
+// @Param offset query int false "読み飛ばす件数"
+// @Success 200 {object} models.WebhookDeliveriesResponse "配信ログ"
+// @Failure 400 {object} models.ValidationError "無効なパラメータ"
+// @Failure 401 {object} models.UnauthorizedError "認証エラー"
+// @Failure 404 {object} models.NotFoundError "Webhook が見つかりません"
+// @Failure 500 {object} models.ServerError "サーバーエラー"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+	userID, ok := h.requireUserID(c, "GetWebhookDeliveries")
+	if !ok {
+		return
+	}
+
+	var query models.WebhookDeliveryListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logging.L.Warn("invalid query parameters", "handler", "WebhookHandler", "method", "GetWebhookDeliveries", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, models.ValidationError{Error: models.ErrCodeValidationFailed})
+		return
+	}
+
+	response, err := h.webhookService.GetDeliveries(userID, id, query)
+	if err != nil {
+		if errors.Is(err, repositories.ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, models.NotFoundError{Error: models.ErrCodeNotFound})
+			return
+		}
+		logging.L.Error("failed to get webhook deliveries", "handler", "WebhookHandler", "method", "GetWebhookDeliveries", "user_id", userID, "endpoint_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
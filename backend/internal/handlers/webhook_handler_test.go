@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockWebhookService はテスト用の WebhookService モック
+type mockWebhookService struct {
+	createEndpointFunc func(userID int, input *models.CreateWebhookInput) (*models.WebhookEndpoint, error)
+	listEndpointsFunc  func(userID int) ([]models.WebhookEndpoint, error)
+	deleteEndpointFunc func(userID, endpointID int) error
+	getDeliveriesFunc  func(userID, endpointID int, query models.WebhookDeliveryListQuery) (*models.WebhookDeliveriesResponse, error)
+}
+
+func (m *mockWebhookService) CreateEndpoint(userID int, input *models.CreateWebhookInput) (*models.WebhookEndpoint, error) {
+	if m.createEndpointFunc != nil {
+		return m.createEndpointFunc(userID, input)
+	}
+	return &models.WebhookEndpoint{}, nil
+}
+
+func (m *mockWebhookService) ListEndpoints(userID int) ([]models.WebhookEndpoint, error) {
+	if m.listEndpointsFunc != nil {
+		return m.listEndpointsFunc(userID)
+	}
+	return []models.WebhookEndpoint{}, nil
+}
+
+func (m *mockWebhookService) DeleteEndpoint(userID, endpointID int) error {
+	if m.deleteEndpointFunc != nil {
+		return m.deleteEndpointFunc(userID, endpointID)
+	}
+	return nil
+}
+
+func (m *mockWebhookService) GetDeliveries(userID, endpointID int, query models.WebhookDeliveryListQuery) (*models.WebhookDeliveriesResponse, error) {
+	if m.getDeliveriesFunc != nil {
+		return m.getDeliveriesFunc(userID, endpointID, query)
+	}
+	return &models.WebhookDeliveriesResponse{}, nil
+}
+
+// newWebhookRouter は本番と同じパス構成で Webhook エンドポイントを登録したルーターを返す
+func newWebhookRouter(handler *WebhookHandler, userID int) *gin.Engine {
+	router := gin.New()
+	auth := withUserID(userID)
+	router.POST("/webhooks", auth, handler.CreateWebhook)
+	router.GET("/webhooks", auth, handler.ListWebhooks)
+	router.DELETE("/webhooks/:id", auth, handler.DeleteWebhook)
+	router.GET("/webhooks/:id/deliveries", auth, handler.GetWebhookDeliveries)
+	return router
+}
+
+func TestCreateWebhook_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewWebhookHandler(&mockWebhookService{
+		createEndpointFunc: func(userID int, input *models.CreateWebhookInput) (*models.WebhookEndpoint, error) {
+			return &models.WebhookEndpoint{ID: 1, UserID: userID, URL: input.URL, Events: input.Events, IsActive: true, Secret: "s3cret"}, nil
+		},
+	})
+	router := newWebhookRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"https://example.com/hook","events":["post.created"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var res models.WebhookEndpoint
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "s3cret", res.Secret)
+	assert.Equal(t, []string{models.WebhookEventPostCreated}, res.Events)
+}
+
+func TestCreateWebhook_ValidationErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewWebhookHandler(&mockWebhookService{
+		createEndpointFunc: func(userID int, input *models.CreateWebhookInput) (*models.WebhookEndpoint, error) {
+			return nil, services.ErrInvalidWebhookURL
+		},
+	})
+	router := newWebhookRouter(handler, 1)
+
+	for _, body := range []string{
+		`{"url":"https://example.com/hook","events":["unknown.event"]}`,
+		`{"url":"https://example.com/hook","events":[]}`,
+		`{"url":"ftp://example.com/hook","events":["post.created"]}`,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestListWebhooks_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewWebhookHandler(&mockWebhookService{
+		listEndpointsFunc: func(userID int) ([]models.WebhookEndpoint, error) {
+			return []models.WebhookEndpoint{{ID: 1, UserID: userID}, {ID: 2, UserID: userID}}, nil
+		},
+	})
+	router := newWebhookRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.WebhookEndpointsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Total)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewWebhookHandler(&mockWebhookService{
+		deleteEndpointFunc: func(userID, endpointID int) error {
+			if endpointID == 99 {
+				return repositories.ErrWebhookNotFound
+			}
+			return nil
+		},
+	})
+	router := newWebhookRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/webhooks/1", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/webhooks/99", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/webhooks/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotQuery models.WebhookDeliveryListQuery
+	handler := NewWebhookHandler(&mockWebhookService{
+		getDeliveriesFunc: func(userID, endpointID int, query models.WebhookDeliveryListQuery) (*models.WebhookDeliveriesResponse, error) {
+			if endpointID == 99 {
+				return nil, repositories.ErrWebhookNotFound
+			}
+			gotQuery = query
+			return &models.WebhookDeliveriesResponse{
+				Deliveries: []models.WebhookDelivery{{ID: 1, EndpointID: endpointID, Status: models.WebhookDeliverySucceeded, Payload: json.RawMessage(`{"id":"e1"}`)}},
+				Total:      1,
+				Limit:      query.Limit,
+				Offset:     query.Offset,
+			}, nil
+		},
+	})
+	router := newWebhookRouter(handler, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries?limit=5&offset=10", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 5, gotQuery.Limit)
+	assert.Equal(t, 10, gotQuery.Offset)
+	assert.Contains(t, w.Body.String(), `"payload":{"id":"e1"}`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks/99/deliveries", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries?limit=1000", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
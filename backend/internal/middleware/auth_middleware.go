@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -27,9 +28,24 @@ func extractAccessTokenFromRequest(c *gin.Context) string {
 	return ""
 }
 
+// AccessTokenVerifier は署名・有効期限を検証済みのアクセストークンが現在も有効か（利用停止・無効化されていないか）を確認するインターフェース
+type AccessTokenVerifier interface {
+	VerifyAccessToken(claims *auth.Claims) error
+}
+
+// roleFromClaims はトークンのロールを返す（ロール導入前に発行されたトークンは一般ユーザーとして扱う）
+func roleFromClaims(claims *auth.Claims) string {
+	if claims.Role == "" {
+		return models.RoleUser
+	}
+	return claims.Role
+}
+
 // AuthMiddleware はJWT認証を行うミドルウェア
 // Cookie または Authorization Headerから Access Tokenを取得して検証
-func AuthMiddleware() gin.HandlerFunc {
+// 検証したクレームは "token_claims"（*auth.Claims）としてコンテキストにセットし、ログアウト時の無効化に使う
+// 署名・有効期限の検証後、verifier でトークンが現在も有効かを確認する
+func AuthMiddleware(verifier AccessTokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := extractAccessTokenFromRequest(c)
 		if tokenString == "" {
@@ -52,7 +68,40 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 利用停止中のユーザーと無効化されたトークンは、有効期限内でもすぐに拒否する
+		if err := verifier.VerifyAccessToken(claims); err != nil {
+			var suspension *auth.SuspensionError
+			switch {
+			case errors.As(err, &suspension):
+				logging.L.Warn("suspended user rejected",
+					"middleware", "AuthMiddleware",
+					"user_id", claims.UserID,
+					"path", c.Request.URL.Path)
+				c.JSON(http.StatusForbidden, models.AccountSuspendedError{
+					Error:          models.ErrCodeAccountSuspended,
+					Reason:         suspension.Reason,
+					SuspendedUntil: suspension.Until,
+				})
+			case errors.Is(err, auth.ErrRevokedToken):
+				logging.L.Warn("revoked access token",
+					"middleware", "AuthMiddleware",
+					"user_id", claims.UserID,
+					"path", c.Request.URL.Path)
+				c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+			default:
+				logging.L.Error("failed to verify access token",
+					"middleware", "AuthMiddleware",
+					"user_id", claims.UserID,
+					"error", err)
+				c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+			}
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", int(claims.UserID))
+		c.Set("role", roleFromClaims(claims))
+		c.Set("token_claims", claims)
 		logging.L.Debug("user authenticated",
 			"middleware", "AuthMiddleware",
 			"user_id", claims.UserID,
@@ -64,7 +113,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 // OptionalAuthMiddleware はJWTがあれば検証してuser_idをコンテキストにセットする。
 // トークンがない・無効でもリクエストを通過させる（認証必須ではない）。
-func OptionalAuthMiddleware() gin.HandlerFunc {
+func OptionalAuthMiddleware(verifier AccessTokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := extractAccessTokenFromRequest(c)
 
@@ -84,7 +133,20 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 利用停止中・無効化されたトークンは未ログインとして扱う
+		if err := verifier.VerifyAccessToken(claims); err != nil {
+			logging.L.Debug("optional auth: token rejected, proceeding without auth",
+				"middleware", "OptionalAuthMiddleware",
+				"user_id", claims.UserID,
+				"path", c.Request.URL.Path,
+				"error", err)
+			c.Next()
+			return
+		}
+
 		c.Set("user_id", int(claims.UserID))
+		c.Set("role", roleFromClaims(claims))
+		c.Set("token_claims", claims)
 		logging.L.Debug("optional auth: user authenticated",
 			"middleware", "OptionalAuthMiddleware",
 			"user_id", claims.UserID,
@@ -2,32 +2,47 @@ package middleware
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
+	"time"
 
+	"go-shisha-backend/internal/models"
 	"go-shisha-backend/pkg/auth"
 
 	"github.com/gin-gonic/gin"
 )
 
+// stubAccessTokenVerifier は常に err を返す AccessTokenVerifier（err が nil の場合はすべてのトークンを有効とする）
+type stubAccessTokenVerifier struct {
+	err error
+}
+
+func (v *stubAccessTokenVerifier) VerifyAccessToken(claims *auth.Claims) error {
+	return v.err
+}
+
 func init() {
-	// テスト用のJWT_SECRETを設定
-	_ = os.Setenv("JWT_SECRET", "test-secret-key-for-testing-purpose-at-least-64-characters-long")
+	// テスト用の署名鍵を設定
+	keys, err := auth.GenerateKeySet()
+	if err != nil {
+		panic(err)
+	}
+	auth.SetKeySet(keys)
 	gin.SetMode(gin.TestMode)
 }
 
 func TestAuthMiddleware_ValidCookie(t *testing.T) {
 	// テスト用のトークンを生成
-	token, err := auth.GenerateAccessToken(123)
+	token, err := auth.GenerateAccessToken(123, models.RoleUser, 0, "")
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
 
 	// Ginルーターをセットアップ
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
 		if !exists {
@@ -58,14 +73,14 @@ func TestAuthMiddleware_ValidCookie(t *testing.T) {
 
 func TestAuthMiddleware_ValidBearerToken(t *testing.T) {
 	// テスト用のトークンを生成
-	token, err := auth.GenerateAccessToken(456)
+	token, err := auth.GenerateAccessToken(456, models.RoleUser, 0, "")
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
 
 	// Ginルーターをセットアップ
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
 		if !exists {
@@ -94,7 +109,7 @@ func TestAuthMiddleware_ValidBearerToken(t *testing.T) {
 func TestAuthMiddleware_NoToken(t *testing.T) {
 	// Ginルーターをセットアップ
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -117,7 +132,7 @@ func TestAuthMiddleware_NoToken(t *testing.T) {
 func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	// Ginルーターをセットアップ
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -138,13 +153,32 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	}
 }
 
+func TestAuthMiddleware_RefreshTokenRejected(t *testing.T) {
+	// Refresh Token は Access Token として使えない
+	refreshToken, err := auth.GenerateRefreshToken(42)
+	if err != nil {
+		t.Fatalf("failed to generate refresh token: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
+	r.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+refreshToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", w.Code)
+	}
+}
+
 func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 	// 期限切れトークンを生成するために、過去の時刻で生成
 	// JWTライブラリは署名時に有効期限をチェックしないので、手動で作成
-	oldSecret := os.Getenv("JWT_SECRET")
-	_ = os.Setenv("JWT_SECRET", "test-secret-key-for-testing-purpose-at-least-64-characters-long")
-	defer func() { _ = os.Setenv("JWT_SECRET", oldSecret) }()
-
 	// 既に期限切れのトークンを作成（-1時間）
 	// Note: auth.GenerateAccessTokenは内部で時刻を設定するため、
 	// ここでは直接JWTを組み立てる必要がある
@@ -152,7 +186,7 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 
 	// Ginルーターをセットアップ
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -181,7 +215,7 @@ func TestAuthMiddleware_UserIDSetInContext(t *testing.T) {
 	expectedUserID := int64(789)
 
 	// テスト用のトークンを生成
-	token, err := auth.GenerateAccessToken(expectedUserID)
+	token, err := auth.GenerateAccessToken(expectedUserID, models.RoleUser, 0, "")
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
@@ -189,7 +223,7 @@ func TestAuthMiddleware_UserIDSetInContext(t *testing.T) {
 	// Ginルーターをセットアップ
 	var actualUserID int
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
 		if !exists {
@@ -225,13 +259,13 @@ func TestAuthMiddleware_UserIDSetInContext(t *testing.T) {
 
 func TestAuthMiddleware_CookiePriorityOverBearer(t *testing.T) {
 	// 2つの異なるuser_idでトークンを生成
-	cookieToken, _ := auth.GenerateAccessToken(111)
-	bearerToken, _ := auth.GenerateAccessToken(222)
+	cookieToken, _ := auth.GenerateAccessToken(111, models.RoleUser, 0, "")
+	bearerToken, _ := auth.GenerateAccessToken(222, models.RoleUser, 0, "")
 
 	// Ginルーターをセットアップ
 	var actualUserID int
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		userID, _ := c.Get("user_id")
 		actualUserID = userID.(int)
@@ -261,7 +295,7 @@ func TestAuthMiddleware_CookiePriorityOverBearer(t *testing.T) {
 func TestAuthMiddleware_MalformedBearerHeader(t *testing.T) {
 	// Ginルーターをセットアップ
 	r := gin.New()
-	r.Use(AuthMiddleware())
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -296,7 +330,7 @@ func TestAuthMiddleware_MalformedBearerHeader(t *testing.T) {
 func TestOptionalAuthMiddleware_NoToken(t *testing.T) {
 	// Ginルーターをセットアップ
 	r := gin.New()
-	r.Use(OptionalAuthMiddleware())
+	r.Use(OptionalAuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		_, exists := c.Get("user_id")
 		c.JSON(http.StatusOK, gin.H{"has_user_id": exists})
@@ -321,7 +355,7 @@ func TestOptionalAuthMiddleware_NoToken(t *testing.T) {
 }
 
 func TestOptionalAuthMiddleware_ValidCookie(t *testing.T) {
-	token, err := auth.GenerateAccessToken(123)
+	token, err := auth.GenerateAccessToken(123, models.RoleUser, 0, "")
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
@@ -330,7 +364,7 @@ func TestOptionalAuthMiddleware_ValidCookie(t *testing.T) {
 	var hasUserID bool
 
 	r := gin.New()
-	r.Use(OptionalAuthMiddleware())
+	r.Use(OptionalAuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		if v, exists := c.Get("user_id"); exists {
 			actualUserID = v.(int)
@@ -360,7 +394,7 @@ func TestOptionalAuthMiddleware_ValidCookie(t *testing.T) {
 }
 
 func TestOptionalAuthMiddleware_ValidBearerToken(t *testing.T) {
-	token, err := auth.GenerateAccessToken(456)
+	token, err := auth.GenerateAccessToken(456, models.RoleUser, 0, "")
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
@@ -369,7 +403,7 @@ func TestOptionalAuthMiddleware_ValidBearerToken(t *testing.T) {
 	var hasUserID bool
 
 	r := gin.New()
-	r.Use(OptionalAuthMiddleware())
+	r.Use(OptionalAuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		if v, exists := c.Get("user_id"); exists {
 			actualUserID = v.(int)
@@ -397,7 +431,7 @@ func TestOptionalAuthMiddleware_ValidBearerToken(t *testing.T) {
 
 func TestOptionalAuthMiddleware_InvalidToken(t *testing.T) {
 	r := gin.New()
-	r.Use(OptionalAuthMiddleware())
+	r.Use(OptionalAuthMiddleware(&stubAccessTokenVerifier{}))
 	r.GET("/test", func(c *gin.Context) {
 		_, exists := c.Get("user_id")
 		c.JSON(http.StatusOK, gin.H{"has_user_id": exists})
@@ -422,3 +456,113 @@ func TestOptionalAuthMiddleware_InvalidToken(t *testing.T) {
 		t.Errorf("expected has_user_id to be false, got %v", resp["has_user_id"])
 	}
 }
+
+func TestAuthMiddleware_VerifierRejectsToken(t *testing.T) {
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{"suspended", &auth.SuspensionError{Reason: "スパム行為のため", Until: &until}, http.StatusForbidden, models.ErrCodeAccountSuspended},
+		{"revoked", auth.ErrRevokedToken, http.StatusUnauthorized, models.ErrCodeUnauthorized},
+		{"lookup failed", errors.New("db down"), http.StatusInternalServerError, models.ErrCodeInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := auth.GenerateAccessToken(123, models.RoleUser, 0, "")
+			if err != nil {
+				t.Fatalf("failed to generate token: %v", err)
+			}
+
+			r := gin.New()
+			r.Use(AuthMiddleware(&stubAccessTokenVerifier{err: tt.err}))
+			r.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{})
+			})
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp["error"] != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, resp["error"])
+			}
+			if tt.wantCode == http.StatusForbidden && resp["reason"] != "スパム行為のため" {
+				t.Errorf("expected suspension reason, got %v", resp["reason"])
+			}
+		})
+	}
+}
+
+func TestOptionalAuthMiddleware_VerifierRejectsToken(t *testing.T) {
+	token, err := auth.GenerateAccessToken(123, models.RoleUser, 0, "")
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(OptionalAuthMiddleware(&stubAccessTokenVerifier{err: auth.ErrRevokedToken}))
+	r.GET("/test", func(c *gin.Context) {
+		_, exists := c.Get("user_id")
+		c.JSON(http.StatusOK, gin.H{"has_user_id": exists})
+	})
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// 無効化されたトークンは未ログインとして扱う
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["has_user_id"] != false {
+		t.Errorf("expected has_user_id to be false, got %v", resp["has_user_id"])
+	}
+}
+
+func TestAuthMiddleware_SetsTokenClaims(t *testing.T) {
+	token, err := auth.GenerateAccessToken(123, models.RoleUser, 3, "")
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(AuthMiddleware(&stubAccessTokenVerifier{}))
+	r.GET("/test", func(c *gin.Context) {
+		value, _ := c.Get("token_claims")
+		claims, ok := value.(*auth.Claims)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"jti": claims.ID, "token_version": claims.TokenVersion})
+	})
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["jti"] == "" || resp["token_version"] != float64(3) {
+		t.Errorf("unexpected claims: %v", resp)
+	}
+}
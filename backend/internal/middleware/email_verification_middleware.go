@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationChecker はユーザーのメールアドレスが確認済みかどうかを取得するインターフェース
+type EmailVerificationChecker interface {
+	IsEmailVerified(userID int) (bool, error)
+}
+
+// RequireVerifiedEmail はメールアドレスを確認済みのユーザーのみリクエストを通過させるミドルウェア
+// AuthMiddleware の後に使用し、未認証の場合は 401、未確認の場合は 403 を返す
+func RequireVerifiedEmail(checker EmailVerificationChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDValue, ok := c.Get("user_id")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+			c.Abort()
+			return
+		}
+		userID, ok := userIDValue.(int)
+		if !ok {
+			logging.L.Error("invalid user_id type in context", "middleware", "RequireVerifiedEmail")
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+			c.Abort()
+			return
+		}
+		verified, err := checker.IsEmailVerified(userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrUserNotFound) {
+				c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+				c.Abort()
+				return
+			}
+			logging.L.Error("failed to check email verification", "middleware", "RequireVerifiedEmail", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+			c.Abort()
+			return
+		}
+		if !verified {
+			logging.L.Info("email not verified", "middleware", "RequireVerifiedEmail", "user_id", userID, "path", c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeEmailNotVerified})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
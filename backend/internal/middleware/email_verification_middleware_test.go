@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-shisha-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubEmailVerificationChecker は確認済みかどうかを返すモック
+type stubEmailVerificationChecker map[int]bool
+
+func (s stubEmailVerificationChecker) IsEmailVerified(userID int) (bool, error) {
+	if userID == 500 {
+		return false, errors.New("db error")
+	}
+	verified, ok := s[userID]
+	if !ok {
+		return false, repositories.ErrUserNotFound
+	}
+	return verified, nil
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	checker := stubEmailVerificationChecker{1: true, 2: false}
+	tests := []struct {
+		name     string
+		userID   interface{}
+		wantCode int
+	}{
+		{"verified", 1, http.StatusOK},
+		{"unverified", 2, http.StatusForbidden},
+		{"deleted user", 99, http.StatusUnauthorized},
+		{"unauthenticated", nil, http.StatusUnauthorized},
+		{"lookup error", 500, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.userID != nil {
+					c.Set("user_id", tt.userID)
+				}
+				c.Next()
+			})
+			r.POST("/posts", RequireVerifiedEmail(checker), func(c *gin.Context) { c.Status(http.StatusOK) })
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/posts", nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleLookup はユーザーの現在のロールを取得するインターフェース
+type RoleLookup interface {
+	GetRole(userID int) (string, error)
+}
+
+// hasRole は role が roles のいずれかに一致するかを返す
+func hasRole(role string, roles []string) bool {
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole はトークンのロールが roles のいずれかの場合のみリクエストを通過させるミドルウェア
+// AuthMiddleware の後に使用し、未認証の場合は 401、ロールが一致しない場合は 403 を返す
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("user_id"); !ok {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+			c.Abort()
+			return
+		}
+		role := c.GetString("role")
+		if !hasRole(role, roles) {
+			logging.L.Warn("insufficient role",
+				"middleware", "RequireRole",
+				"user_id", c.GetInt("user_id"),
+				"role", role,
+				"path", c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeForbidden})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireCurrentRole は DB 上の現在のロールが roles のいずれかの場合のみリクエストを通過させるミドルウェア
+// トークンのロールは有効期限まで変わらないため、ロールの剥奪をすぐに反映したい重要な操作で RequireRole の代わりに使用する
+func RequireCurrentRole(lookup RoleLookup, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDValue, ok := c.Get("user_id")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedError{Error: models.ErrCodeUnauthorized})
+			c.Abort()
+			return
+		}
+		userID, ok := userIDValue.(int)
+		if !ok {
+			logging.L.Error("invalid user_id type in context", "middleware", "RequireCurrentRole")
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+			c.Abort()
+			return
+		}
+		role, err := lookup.GetRole(userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrUserNotFound) {
+				logging.L.Warn("user for role check not found", "middleware", "RequireCurrentRole", "user_id", userID)
+				c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeForbidden})
+				c.Abort()
+				return
+			}
+			logging.L.Error("failed to verify current role", "middleware", "RequireCurrentRole", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ServerError{Error: models.ErrCodeInternalServer})
+			c.Abort()
+			return
+		}
+		if !hasRole(role, roles) {
+			logging.L.Warn("insufficient current role",
+				"middleware", "RequireCurrentRole",
+				"user_id", userID,
+				"token_role", c.GetString("role"),
+				"role", role,
+				"path", c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, models.ForbiddenError{Error: models.ErrCodeForbidden})
+			c.Abort()
+			return
+		}
+		c.Set("role", role)
+		c.Next()
+	}
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubRoleLookup は DB 上のロールを返すモック
+type stubRoleLookup map[int]string
+
+func (s stubRoleLookup) GetRole(userID int) (string, error) {
+	if userID == 500 {
+		return "", errors.New("db error")
+	}
+	role, ok := s[userID]
+	if !ok {
+		return "", repositories.ErrUserNotFound
+	}
+	return role, nil
+}
+
+// newRoleRouter は user_id・role をコンテキストに設定してから middleware を通すルーターを返す
+func newRoleRouter(userID interface{}, role string, middleware gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("user_id", userID)
+			c.Set("role", role)
+		}
+		c.Next()
+	})
+	r.GET("/admin", middleware, func(c *gin.Context) { c.String(http.StatusOK, c.GetString("role")) })
+	return r
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   interface{}
+		role     string
+		wantCode int
+	}{
+		{"admin", 1, models.RoleAdmin, http.StatusOK},
+		{"moderator", 2, models.RoleModerator, http.StatusOK},
+		{"user", 3, models.RoleUser, http.StatusForbidden},
+		{"unauthenticated", nil, "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router := newRoleRouter(tt.userID, tt.role, RequireRole(models.RoleModerator, models.RoleAdmin))
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireCurrentRole(t *testing.T) {
+	lookup := stubRoleLookup{1: models.RoleAdmin, 2: models.RoleUser}
+
+	tests := []struct {
+		name      string
+		userID    interface{}
+		tokenRole string
+		wantCode  int
+	}{
+		{"current admin", 1, models.RoleAdmin, http.StatusOK},
+		// トークン発行後に管理者でなくなったユーザーは通過させない
+		{"demoted after token issued", 2, models.RoleAdmin, http.StatusForbidden},
+		{"deleted user", 3, models.RoleAdmin, http.StatusForbidden},
+		{"lookup error", 500, models.RoleAdmin, http.StatusInternalServerError},
+		{"unauthenticated", nil, "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router := newRoleRouter(tt.userID, tt.tokenRole, RequireCurrentRole(lookup, models.RoleAdmin))
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_SetsRoleFromToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		wantRole string
+	}{
+		{"moderator", models.RoleModerator, models.RoleModerator},
+		// ロール導入前に発行されたトークンは一般ユーザーとして扱う
+		{"legacy token without role", "", models.RoleUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := auth.GenerateAccessToken(1, tt.role, 0, "")
+			if err != nil {
+				t.Fatalf("failed to generate token: %v", err)
+			}
+			r := gin.New()
+			r.GET("/test", AuthMiddleware(&stubAccessTokenVerifier{}), func(c *gin.Context) { c.String(http.StatusOK, c.GetString("role")) })
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK || w.Body.String() != tt.wantRole {
+				t.Errorf("expected 200 with role %q, got %d %q", tt.wantRole, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// バッジ識別子
+const (
+	// BadgeFirstPost は初めて投稿したときに獲得するバッジ
+	BadgeFirstPost = "first_post"
+	// BadgeFlavorExplorer は10種類のフレーバーを試したときに獲得するバッジ
+	BadgeFlavorExplorer = "flavor_explorer"
+	// BadgePopular は受け取ったいいねが合計100件に達したときに獲得するバッジ
+	BadgePopular = "popular"
+	// BadgeWeeklyStreak は7日連続で投稿したときに獲得するバッジ
+	BadgeWeeklyStreak = "weekly_streak"
+)
+
+// UserBadge はユーザーが獲得したバッジ
+type UserBadge struct {
+	// バッジ識別子
+	Code string `json:"code" example:"first_post" enums:"first_post,flavor_explorer,popular,weekly_streak"`
+	// バッジ名
+	Name string `json:"name" example:"はじめての一服"`
+	// 獲得条件の説明
+	Description string `json:"description" example:"はじめて投稿した"`
+	// 獲得日時
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
+// UserBadgesResponse はバッジ一覧のレスポンス
+type UserBadgesResponse struct {
+	Badges []UserBadge `json:"badges"`
+	Total  int         `json:"total" example:"2"`
+}
+
+// MarkBadgesSeenInput はバッジを確認済みにするリクエスト
+type MarkBadgesSeenInput struct {
+	// 確認済みにするバッジ識別子（未確認バッジ取得で受け取ったもの）
+	Codes []string `json:"codes" binding:"required,min=1,max=20,dive,required" example:"first_post"`
+}
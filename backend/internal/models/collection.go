@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Collection はユーザーが作成した投稿のコレクション（アルバム）
+type Collection struct {
+	ID          int    `json:"id" example:"1"`
+	UserID      int    `json:"user_id" example:"1"`
+	Name        string `json:"name" example:"ミント系ベストミックス"`
+	Description string `json:"description" example:"夏に吸いたいミント系の組み合わせ"`
+	// 公開コレクションかどうか（非公開の場合は作成者本人のみ閲覧可能）
+	IsPublic bool `json:"is_public" example:"true"`
+	// 収録されている投稿のうち閲覧者に表示される（削除・非表示・ブロック・ミュートされていない）投稿の数
+	ItemCount int       `json:"item_count" example:"5"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// 収録投稿（並び順）。コレクション詳細の取得時のみ設定される
+	Posts []Post `json:"posts,omitempty"`
+}
+
+// CollectionsResponse はコレクション一覧のレスポンス
+type CollectionsResponse struct {
+	Collections []Collection `json:"collections"`
+	Total       int          `json:"total" example:"3"`
+}
+
+// CreateCollectionInput はコレクション作成時の入力
+type CreateCollectionInput struct {
+	// コレクション名（1〜50文字）
+	Name string `json:"name" binding:"required,max=50" example:"ミント系ベストミックス"`
+	// 説明（200文字以内）
+	Description string `json:"description" binding:"max=200" example:"夏に吸いたいミント系の組み合わせ"`
+	// 公開するかどうか（省略時は公開）
+	IsPublic *bool `json:"is_public" example:"true"`
+}
+
+// UpdateCollectionInput はコレクション更新時の入力（省略した項目は変更しない）
+type UpdateCollectionInput struct {
+	// コレクション名（1〜50文字）
+	Name *string `json:"name" binding:"omitempty,min=1,max=50" example:"ミント系ベストミックス"`
+	// 説明（200文字以内）
+	Description *string `json:"description" binding:"omitempty,max=200" example:"夏に吸いたいミント系の組み合わせ"`
+	// 公開するかどうか
+	IsPublic *bool `json:"is_public" example:"false"`
+}
+
+// AddCollectionItemInput はコレクションへの投稿追加時の入力
+type AddCollectionItemInput struct {
+	// 追加する投稿ID（コレクションの末尾に追加される）
+	PostID int `json:"post_id" binding:"required,min=1" example:"10"`
+}
+
+// ReorderCollectionItemsInput はコレクション内の並び替え時の入力
+type ReorderCollectionItemsInput struct {
+	// 新しい並び順の投稿ID。現在収録されている投稿IDをすべて過不足なく指定する
+	PostIDs []int `json:"post_ids" binding:"required,dive,min=1" example:"3,1,2"`
+}
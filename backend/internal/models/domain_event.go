@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ドメインイベントの種類
+const (
+	// DomainEventPostCreated は投稿の作成（リミックスを含む）
+	DomainEventPostCreated = "PostCreated"
+	// DomainEventPostDeleted は投稿の論理削除
+	DomainEventPostDeleted = "PostDeleted"
+	// DomainEventLikeAdded は投稿へのいいね
+	DomainEventLikeAdded = "LikeAdded"
+	// DomainEventProfileImageReplaced はプロフィール画像の置き換え
+	DomainEventProfileImageReplaced = "ProfileImageReplaced"
+)
+
+// アウトボックスのイベントの状態
+const (
+	// OutboxEventPending は配送待ち（再試行待ちを含む）
+	OutboxEventPending = "pending"
+	// OutboxEventProcessed はすべての購読者が処理に成功した
+	OutboxEventProcessed = "processed"
+	// OutboxEventFailed は再試行上限に達して配送を諦めた
+	OutboxEventFailed = "failed"
+)
+
+// DomainEvent はアウトボックスに書き込まれたドメインイベント
+// Payload は Type に応じた *Event 構造体の JSON
+type DomainEvent struct {
+	ID      int
+	Type    string
+	Payload json.RawMessage
+	// Status は pending / processed / failed
+	Status   string
+	Attempts int
+	// CompletedHandlers は処理に成功した購読者名（再試行時はこれ以外の購読者にのみ配送する）
+	CompletedHandlers []string
+	NextAttemptAt     *time.Time
+	LastError         string
+	ProcessedAt       *time.Time
+	CreatedAt         time.Time
+}
+
+// Decode は Payload を v に復元する
+func (e *DomainEvent) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// PostCreatedEvent は PostCreated イベントの内容
+type PostCreatedEvent struct {
+	PostID int `json:"post_id"`
+	UserID int `json:"user_id"`
+	// リミックス元の投稿ID（リミックスの場合のみ）
+	RemixedFrom *int `json:"remixed_from,omitempty"`
+	// スライドで使用した画像のURL（画像なしのスライドは含まない）
+	ImageURLs []string `json:"image_urls"`
+}
+
+// PostDeletedEvent は PostDeleted イベントの内容
+type PostDeletedEvent struct {
+	PostID int `json:"post_id"`
+	UserID int `json:"user_id"`
+}
+
+// LikeAddedEvent は LikeAdded イベントの内容
+type LikeAddedEvent struct {
+	PostID int `json:"post_id"`
+	// いいねしたユーザー
+	UserID int `json:"user_id"`
+	// 投稿者
+	PostOwnerID int `json:"post_owner_id"`
+	// いいね後のいいね数
+	Likes int `json:"likes"`
+}
+
+// ProfileImageReplacedEvent は ProfileImageReplaced イベントの内容
+type ProfileImageReplacedEvent struct {
+	UserID int `json:"user_id"`
+	// 新しいプロフィール画像のURL
+	FilePath string `json:"file_path"`
+	// deleted にした旧プロフィール画像のURL
+	OldFilePaths []string `json:"old_file_paths"`
+}
@@ -1,44 +1,79 @@
 package models
 
+import "time"
+
 // エラーコード定数 - ハンドラーと enums タグの単一ソース
 const (
-	ErrCodeValidationFailed   = "validation_failed"
-	ErrCodeEmailAlreadyExists = "email_already_exists"
-	ErrCodeAlreadyLiked       = "already_liked"
-	ErrCodeNotLiked           = "not_liked"
-	ErrCodeForbidden          = "forbidden"
-	ErrCodeUnauthorized       = "unauthorized"
-	ErrCodeNotFound           = "not_found"
-	ErrCodePayloadTooLarge    = "payload_too_large"
-	ErrCodeInternalServer     = "internal_server_error"
+	ErrCodeValidationFailed    = "validation_failed"
+	ErrCodeInvalidToken        = "invalid_token"
+	ErrCodeEmailAlreadyExists  = "email_already_exists"
+	ErrCodeAlreadyLiked        = "already_liked"
+	ErrCodeNotLiked            = "not_liked"
+	ErrCodeAlreadyInCollection = "already_in_collection"
+	ErrCodeAlreadyReported     = "already_reported"
+	ErrCodeReportResolved      = "report_already_resolved"
+	ErrCodeAlreadyBlocked      = "already_blocked"
+	ErrCodeNotBlocked          = "not_blocked"
+	ErrCodeAlreadyMuted        = "already_muted"
+	ErrCodeNotMuted            = "not_muted"
+	ErrCodeNotSuspended        = "not_suspended"
+	ErrCodeNotLocked           = "not_locked"
+	ErrCodeBlocked             = "blocked"
+	ErrCodeAccountSuspended    = "account_suspended"
+	ErrCodeForbidden           = "forbidden"
+	ErrCodeIncorrectPassword   = "incorrect_password"
+	ErrCodeEmailNotVerified    = "email_not_verified"
+	ErrCodeOIDCEmailUnverified = "oidc_email_unverified"
+	ErrCodeAlreadyVerified     = "already_verified"
+	ErrCodeInvalidMFACode      = "invalid_mfa_code"
+	ErrCodeMFAAlreadyEnabled   = "mfa_already_enabled"
+	ErrCodeMFANotEnabled       = "mfa_not_enabled"
+	ErrCodeInvalidPasskey      = "invalid_passkey"
+	ErrCodePasskeyRegistered   = "passkey_already_registered"
+	ErrCodeUnauthorized        = "unauthorized"
+	ErrCodeNotFound            = "not_found"
+	ErrCodePayloadTooLarge     = "payload_too_large"
+	ErrCodeLoginThrottled      = "login_throttled"
+	ErrCodeInternalServer      = "internal_server_error"
 )
 
 // ValidationError はバリデーションエラーを表す（400 Bad Request）
-// @Description 入力値のバリデーションに失敗した場合のエラーレスポンス
+// @Description 入力値のバリデーションに失敗した場合のエラーレスポンス（メールで送ったリンクのトークンが無効・使用済み・有効期限切れの場合は invalid_token、2段階認証のコードが誤っている場合は invalid_mfa_code、パスキーを検証できない場合は invalid_passkey）
 type ValidationError struct {
 	// エラー種別の識別子
-	Error string `json:"error" enums:"validation_failed" example:"validation_failed" binding:"required"`
+	Error string `json:"error" enums:"validation_failed,invalid_token,invalid_mfa_code,invalid_passkey" example:"validation_failed" binding:"required"`
 }
 
 // ConflictError はリソース競合エラーを表す（409 Conflict）
-// @Description リソース競合エラーレスポンス（メール重複・いいね重複・いいね未実施など）
+// @Description リソース競合エラーレスポンス（メール重複・いいね重複・いいね未実施・コレクション収録済み・通報済み・対応済みの通報・ブロック/ミュートの重複や未実施・利用停止されていないユーザーの利用停止解除・ロックされていないアカウントのロック解除・確認済みのメールアドレスの確認メールの再送・2段階認証の状態と合わない操作・登録済みのパスキーの登録など）
 type ConflictError struct {
 	// エラー種別の識別子
-	Error string `json:"error" enums:"email_already_exists,already_liked,not_liked" example:"already_liked" binding:"required"`
+	Error string `json:"error" enums:"email_already_exists,already_liked,not_liked,already_in_collection,already_reported,report_already_resolved,already_blocked,not_blocked,already_muted,not_muted,not_suspended,not_locked,already_verified,mfa_already_enabled,mfa_not_enabled,passkey_already_registered" example:"already_liked" binding:"required"`
 }
 
 // UnauthorizedError は認証エラーを表す（401 Unauthorized）
-// @Description 認証に失敗した場合のエラーレスポンス
+// @Description 認証に失敗した場合のエラーレスポンス（2段階認証のログインでコードが誤っている場合は invalid_mfa_code）
 type UnauthorizedError struct {
 	// エラー種別の識別子
-	Error string `json:"error" enums:"unauthorized" example:"unauthorized" binding:"required"`
+	Error string `json:"error" enums:"unauthorized,invalid_mfa_code" example:"unauthorized" binding:"required"`
 }
 
 // ForbiddenError は権限エラーを表す（403 Forbidden）
-// @Description 権限がない操作を実行した場合のエラーレスポンス
+// @Description 権限がない操作を実行した場合のエラーレスポンス（投稿者にブロックされている場合は blocked、確認のためのパスワードが誤っている場合は incorrect_password、メールアドレスが未確認の場合は email_not_verified、ログインに使ったプロバイダーのメールアドレスが未確認の場合は oidc_email_unverified）
 type ForbiddenError struct {
 	// エラー種別の識別子
-	Error string `json:"error" enums:"forbidden" example:"forbidden" binding:"required"`
+	Error string `json:"error" enums:"forbidden,blocked,incorrect_password,email_not_verified,oidc_email_unverified" example:"forbidden" binding:"required"`
+}
+
+// AccountSuspendedError は利用停止中のエラーを表す（403 Forbidden）
+// @Description 利用停止中のユーザーがログイン・トークン更新・認証が必要な操作を行った場合のエラーレスポンス
+type AccountSuspendedError struct {
+	// エラー種別の識別子
+	Error string `json:"error" enums:"account_suspended" example:"account_suspended" binding:"required"`
+	// 利用停止の理由
+	Reason string `json:"reason" example:"スパム行為のため"`
+	// 利用停止の終了日時（無期限の場合は含まれない）
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
 }
 
 // NotFoundError はリソースが見つからないエラーを表す（404 Not Found）
@@ -55,6 +90,13 @@ type PayloadTooLargeError struct {
 	Error string `json:"error" enums:"payload_too_large" example:"payload_too_large" binding:"required"`
 }
 
+// TooManyRequestsError はログインの試行の制限を表す（429 Too Many Requests）
+// @Description ログインの失敗が続いたアカウントで、待ち時間中・ロック中のためログインを受け付けない場合のエラーレスポンス（Retry-After ヘッダーで次の試行を受け付けるまでの秒数を返す）
+type TooManyRequestsError struct {
+	// エラー種別の識別子
+	Error string `json:"error" enums:"login_throttled" example:"login_throttled" binding:"required"`
+}
+
 // ServerError はサーバー内部エラーを表す（500 Internal Server Error）
 // @Description サーバー内部でエラーが発生した場合のエラーレスポンス
 type ServerError struct {
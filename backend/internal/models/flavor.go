@@ -5,4 +5,6 @@ type Flavor struct {
 	ID    int    `json:"id"`
 	Name  string `json:"name"`
 	Color string `json:"color"`
+	// フレーバーのカテゴリ（未分類の場合は省略）
+	Category string `json:"category,omitempty" example:"フルーツ"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// LoginFailure はアカウントごとのログインの失敗の記録
+type LoginFailure struct {
+	UserID int
+	// FailedCount は連続したログインの試行の回数（パスワードの検証前に数え、ログインに成功すると記録ごと削除する）
+	FailedCount int
+	// LastFailedAt は最後に試行した日時
+	LastFailedAt time.Time
+	// NextAttemptAt は次の試行を受け付ける日時
+	NextAttemptAt time.Time
+	// LockedUntil はロックの終了日時（nil の場合はロックしていない）
+	LockedUntil *time.Time
+}
+
+// UnlockAccountInput はアカウントのロックの解除のリクエスト
+type UnlockAccountInput struct {
+	// ロックしたときにメールで送ったリンクのトークン
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// TOTP はユーザーの TOTP による2段階認証の設定
+type TOTP struct {
+	UserID int
+	// Secret は Base32 のシークレット
+	Secret string
+	// EnabledAt は最初のコードで確認した日時（nil の場合は登録中）
+	EnabledAt *time.Time
+	// LastUsedCounter は最後に受け付けたコードの時間ステップ（これ以前のコードは受け付けない）
+	LastUsedCounter int64
+}
+
+// MFAStatus は2段階認証の状態のレスポンス
+type MFAStatus struct {
+	// 2段階認証が有効かどうか
+	Enabled bool `json:"enabled" example:"true"`
+	// 2段階認証を有効にした日時
+	EnabledAt *time.Time `json:"enabled_at,omitempty"`
+	// 未使用のリカバリーコードの数
+	RemainingRecoveryCodes int `json:"remaining_recovery_codes" example:"10"`
+}
+
+// TOTPEnrollment は TOTP の登録開始のレスポンス
+type TOTPEnrollment struct {
+	// 認証アプリに手入力する Base32 のシークレット
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	// 認証アプリに QR コードで読み込ませる otpauth URI
+	OTPAuthURI string `json:"otpauth_uri" example:"otpauth://totp/Go%20Shisha:user@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Go+Shisha"`
+}
+
+// ConfirmTOTPInput は TOTP の登録確認のリクエストボディ
+type ConfirmTOTPInput struct {
+	// 認証アプリに表示された6桁のコード
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// MFAPasswordInput は2段階認証の無効化・リカバリーコードの再発行のリクエストボディ
+type MFAPasswordInput struct {
+	// 現在のパスワード
+	Password string `json:"password" binding:"required"`
+}
+
+// RecoveryCodesResponse はリカバリーコードのレスポンス（コードはこのレスポンスでのみ返す）
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallengeResponse は2段階認証が有効なユーザーのログインのレスポンス
+// challenge_token とコードを POST /auth/login/mfa に送るとログインが完了する
+type MFAChallengeResponse struct {
+	MFARequired    bool      `json:"mfa_required" example:"true"`
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// MFALoginInput は2段階認証のコードによるログインのリクエストボディ
+type MFALoginInput struct {
+	// ログインのレスポンスの challenge_token
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// 認証アプリに表示された6桁のコード、またはリカバリーコード
+	Code string `json:"code" binding:"required" example:"123456"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ミュートルールの種類
+const (
+	// MuteRuleKeyword はスライドの本文に指定した語を含む投稿をミュートする
+	MuteRuleKeyword = "keyword"
+	// MuteRuleFlavor は指定したフレーバーを使ったスライドを含む投稿をミュートする
+	MuteRuleFlavor = "flavor"
+)
+
+// MuteRule はタイムライン・検索結果から投稿を除外するユーザーごとのルール
+type MuteRule struct {
+	ID     int `json:"id" example:"1"`
+	UserID int `json:"user_id" example:"1"`
+	// keyword / flavor
+	Kind string `json:"kind" example:"keyword"`
+	// ミュートする語（kind が keyword の場合のみ。大文字・小文字を区別せず部分一致）
+	Keyword string `json:"keyword,omitempty" example:"ミント"`
+	// ミュートするフレーバーのID（kind が flavor の場合のみ）
+	FlavorID *int `json:"flavor_id,omitempty" example:"3"`
+	// 有効期限（省略時は無期限。期限を過ぎたルールは適用されない）
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MuteRulesResponse はミュートルール一覧のレスポンス
+type MuteRulesResponse struct {
+	Rules []MuteRule `json:"rules"`
+	Total int        `json:"total" example:"2"`
+}
+
+// MuteRuleInput はミュートルールの作成・更新時の入力
+type MuteRuleInput struct {
+	// keyword / flavor
+	Kind string `json:"kind" binding:"required,oneof=keyword flavor" example:"keyword"`
+	// ミュートする語（kind が keyword の場合に必須、100文字以内）
+	Keyword string `json:"keyword" binding:"max=100" example:"ミント"`
+	// ミュートするフレーバーのID（kind が flavor の場合に必須）
+	FlavorID *int `json:"flavor_id" binding:"omitempty,min=1" example:"3"`
+	// 有効期限（省略時は無期限、未来の日時のみ指定可能）
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-31T23:59:59+09:00"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// 通知の種類
+const (
+	// NotificationTypePostLiked は投稿へのいいね通知（同じ投稿への未読のいいねは1件にまとめられる）
+	NotificationTypePostLiked = "post_liked"
+)
+
+// NotificationEvent は通知を発生させるイベント（通知の生成元が NotificationService に渡す）
+type NotificationEvent struct {
+	// 通知の受信者
+	UserID int
+	// 操作を行ったユーザー
+	ActorID int
+	// 通知の種類（NotificationType* 定数）
+	Type string
+	// 関連する投稿（投稿に紐づかない通知の場合は nil）
+	PostID *int
+}
+
+// NotificationActor は通知に表示する操作ユーザーの情報
+type NotificationActor struct {
+	ID          int    `json:"id" example:"2"`
+	DisplayName string `json:"display_name" example:"シーシャ太郎"`
+	IconURL     string `json:"icon_url" example:"/images/icon.jpg"`
+}
+
+// Notification はユーザーへのアプリ内通知
+type Notification struct {
+	ID   int    `json:"id" example:"1"`
+	Type string `json:"type" example:"post_liked"`
+	// 関連する投稿ID（投稿に紐づかない通知の場合は省略）
+	PostID *int `json:"post_id,omitempty" example:"10"`
+	// 直近に操作を行ったユーザー（退会済みの場合は省略）
+	Actor *NotificationActor `json:"actor,omitempty"`
+	// Actor 以外にまとめられた操作ユーザーの人数
+	OthersCount int `json:"others_count" example:"4"`
+	// 表示用メッセージ
+	Message string `json:"message" example:"シーシャ太郎さんと他4人があなたの投稿にいいねしました"`
+	IsRead  bool   `json:"is_read" example:"false"`
+	// 作成日時
+	CreatedAt time.Time `json:"created_at"`
+	// 最後に操作がまとめられた日時（一覧はこの日時の新しい順）
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationListQuery は通知一覧取得時のページングパラメータ
+type NotificationListQuery struct {
+	// 取得件数（1〜100、省略時は20）
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	// 読み飛ばす件数
+	Offset int `form:"offset" binding:"omitempty,min=0" example:"0"`
+}
+
+// NotificationsResponse は通知一覧のレスポンス
+type NotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	// 通知の総数
+	Total int `json:"total" example:"42"`
+	// 未読の通知数
+	UnreadCount int `json:"unread_count" example:"3"`
+	Limit       int `json:"limit" example:"20"`
+	Offset      int `json:"offset" example:"0"`
+}
+
+// UnreadNotificationCountResponse は未読通知数のレスポンス
+type UnreadNotificationCountResponse struct {
+	UnreadCount int `json:"unread_count" example:"3"`
+}
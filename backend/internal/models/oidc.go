@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// UserIdentity は OpenID Connect のプロバイダーのアカウントとユーザーの紐付け
+type UserIdentity struct {
+	ID     int64
+	UserID int
+	// Provider はプロバイダーの識別子（google など）
+	Provider string
+	// Subject はプロバイダーのユーザーID（ID トークンの sub）
+	Subject string
+	// Email は紐付けた時点のプロバイダーのメールアドレス
+	Email     string
+	CreatedAt time.Time
+}
+
+// OIDCProvidersResponse はログインに使える OpenID Connect のプロバイダーの一覧のレスポンス
+type OIDCProvidersResponse struct {
+	// プロバイダーの識別子（POST /auth/oidc/{provider}/authorize で指定する）
+	Providers []string `json:"providers" example:"google,line"`
+}
+
+// OIDCAuthorizationResponse はプロバイダーによるログインの開始のレスポンス
+// ブラウザーを authorization_url に移動し、プロバイダーから戻った URL の code と state を POST /auth/oidc/{provider}/callback に送る
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url" example:"https://accounts.google.com/o/oauth2/v2/auth?client_id=..."`
+}
+
+// OIDCCallbackInput はプロバイダーによるログインの完了のリクエストボディ
+type OIDCCallbackInput struct {
+	// プロバイダーが返した認可コード
+	Code string `json:"code" binding:"required"`
+	// プロバイダーが返した state
+	State string `json:"state" binding:"required"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"go-shisha-backend/pkg/webauthn"
+)
+
+// Passkey はユーザーが登録したパスキー（WebAuthn のクレデンシャル）
+type Passkey struct {
+	// パスキーのID（PATCH・DELETE /auth/passkeys/{id} で指定する）
+	ID     int64 `json:"id" example:"1"`
+	UserID int   `json:"-"`
+	// CredentialID は認証器が発行したクレデンシャルID
+	CredentialID []byte `json:"-"`
+	// PublicKey は COSE_Key 形式の公開鍵
+	PublicKey []byte `json:"-"`
+	// SignCount は最後に受け付けた署名カウンター
+	SignCount uint32 `json:"-"`
+	// 認証器の接続方法
+	Transports []string `json:"transports" example:"internal,hybrid"`
+	// ユーザーが付けた名前
+	Name string `json:"name" example:"iPhone"`
+	// 登録した日時
+	CreatedAt time.Time `json:"created_at"`
+	// 最後にログインに使った日時
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// PasskeysResponse は登録済みのパスキー一覧のレスポンス
+type PasskeysResponse struct {
+	Passkeys []Passkey `json:"passkeys"`
+}
+
+// PasskeyRegistrationOptionsResponse はパスキーの登録開始のレスポンス
+// public_key を navigator.credentials.create() に渡し、結果と session_token を POST /auth/passkeys/registration に送る
+type PasskeyRegistrationOptionsResponse struct {
+	SessionToken string                   `json:"session_token"`
+	PublicKey    webauthn.CreationOptions `json:"public_key"`
+}
+
+// RegisterPasskeyInput はパスキーの登録のリクエストボディ
+type RegisterPasskeyInput struct {
+	// 登録開始のレスポンスの session_token
+	SessionToken string `json:"session_token" binding:"required"`
+	// パスキーの名前（省略した場合は「パスキー」）
+	Name string `json:"name" binding:"max=64" example:"iPhone"`
+	// navigator.credentials.create() の結果（PublicKeyCredential.toJSON() の形式）
+	Credential webauthn.RegistrationResponse `json:"credential"`
+}
+
+// PasskeyLoginOptionsResponse はパスキーによるログイン開始のレスポンス
+// public_key を navigator.credentials.get() に渡し、結果と session_token を POST /auth/passkeys/login に送る
+type PasskeyLoginOptionsResponse struct {
+	SessionToken string                  `json:"session_token"`
+	PublicKey    webauthn.RequestOptions `json:"public_key"`
+}
+
+// PasskeyLoginInput はパスキーによるログインのリクエストボディ
+type PasskeyLoginInput struct {
+	// ログイン開始のレスポンスの session_token
+	SessionToken string `json:"session_token" binding:"required"`
+	// navigator.credentials.get() の結果（PublicKeyCredential.toJSON() の形式）
+	Credential webauthn.AssertionResponse `json:"credential"`
+}
+
+// RenamePasskeyInput はパスキーの名前の変更のリクエストボディ
+type RenamePasskeyInput struct {
+	Name string `json:"name" binding:"required,max=64" example:"仕事用の MacBook"`
+}
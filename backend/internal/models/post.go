@@ -35,6 +35,56 @@ type Post struct {
 	User      User      `json:"user"`
 	CreatedAt time.Time `json:"created_at"`
 	IsLiked   bool      `json:"is_liked,omitempty"`
+	// リミックス元の投稿ID。元投稿が削除された場合は参照が外れて省略される
+	RemixedFrom *int `json:"remixed_from,omitempty" example:"1"`
+	// この投稿をリミックスした（削除されていない）投稿の数
+	RemixCount int `json:"remix_count"`
+	// セッション詳細。未入力の投稿では省略される
+	Session *ShishaSession `json:"session,omitempty"`
+}
+
+// ShishaSession は投稿に紐づくシーシャセッションの詳細（全項目任意）
+type ShishaSession struct {
+	// ボウルの種類
+	BowlType *string `json:"bowl_type,omitempty" example:"ファンネル"`
+	// ヒートマネジメント（HMD・アルミホイル等）
+	HeatManagement *string `json:"heat_management,omitempty" example:"ロータス"`
+	// 炭の数
+	CoalCount *int `json:"coal_count,omitempty" example:"3"`
+	// セッション時間（分）
+	DurationMinutes *int `json:"duration_minutes,omitempty" example:"60"`
+	// 総合評価（1〜5）
+	Rating *int `json:"rating,omitempty" example:"4"`
+}
+
+// IsEmpty はすべての項目が未入力かどうかを返す
+func (s *ShishaSession) IsEmpty() bool {
+	return s == nil ||
+		(s.BowlType == nil && s.HeatManagement == nil && s.CoalCount == nil && s.DurationMinutes == nil && s.Rating == nil)
+}
+
+// ShishaSessionInput はセッション詳細の入力（全項目任意）
+type ShishaSessionInput struct {
+	// ボウルの種類（50文字以内）
+	BowlType *string `json:"bowl_type" binding:"omitempty,max=50" example:"ファンネル"`
+	// ヒートマネジメント（50文字以内）
+	HeatManagement *string `json:"heat_management" binding:"omitempty,max=50" example:"ロータス"`
+	// 炭の数（1〜10）
+	CoalCount *int `json:"coal_count" binding:"omitempty,min=1,max=10" example:"3"`
+	// セッション時間（分、1〜600）
+	DurationMinutes *int `json:"duration_minutes" binding:"omitempty,min=1,max=600" example:"60"`
+	// 総合評価（1〜5）
+	Rating *int `json:"rating" binding:"omitempty,min=1,max=5" example:"4"`
+}
+
+// PostFilter はタイムライン取得時の絞り込み条件（すべて任意）
+type PostFilter struct {
+	// 総合評価の下限（例: 4 を指定すると評価4以上の投稿のみ）
+	MinRating *int `form:"min_rating" binding:"omitempty,min=1,max=5"`
+	// ボウルの種類（完全一致）
+	BowlType *string `form:"bowl_type" binding:"omitempty,max=50"`
+	// ヒートマネジメント（完全一致）
+	HeatManagement *string `form:"heat_management" binding:"omitempty,max=50"`
 }
 
 // PostDB represents a post record in the database
@@ -60,6 +110,8 @@ type SlideInput struct {
 // CreatePostInput represents the input for creating a post
 type CreatePostInput struct {
 	Slides []SlideInput `json:"slides" binding:"required,min=1,max=10,dive"`
+	// セッション詳細（省略可）
+	Session *ShishaSessionInput `json:"session"`
 }
 
 // UpdateSlideInput はスライド更新時の入力
@@ -78,6 +130,9 @@ type UpdateSlideInput struct {
 // UpdatePostInput は投稿更新時の入力
 type UpdatePostInput struct {
 	Slides []UpdateSlideInput `json:"slides" binding:"required,min=1,max=10,dive"`
+	// セッション詳細。省略または null の場合は既存の値を変更しない。
+	// 指定した場合は全項目を上書きし、全項目を省略した空オブジェクトを渡すとセッション詳細が削除される
+	Session *ShishaSessionInput `json:"session"`
 }
 
 // PostsResponse represents the response for post list
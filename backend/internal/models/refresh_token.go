@@ -10,6 +10,15 @@ type RefreshToken struct {
 	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null;index"`
 	CreatedAt  time.Time  `json:"created_at" gorm:"not null;default:now()"`
 	LastUsedAt *time.Time `json:"last_used_at"`
+	// FamilyID はログイン時に発行し、ローテーションしても引き継ぐID（再利用を検知したらファミリーごと無効化する）
+	FamilyID string `json:"-" gorm:"not null;index"`
+	// RotatedAt はローテーションして新しいトークンに置き換えた日時（nil の場合は現在有効なトークン）
+	RotatedAt *time.Time `json:"-"`
+	// UserAgent・IPAddress は最後にログイン・トークン更新を行った端末の情報
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-" gorm:"column:ip_address"`
+	// SessionStartedAt はセッションを開始した（ログインした）日時（ローテーションしても引き継ぐ）
+	SessionStartedAt time.Time `json:"-"`
 }
 
 // TableName はテーブル名を指定
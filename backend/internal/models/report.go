@@ -0,0 +1,139 @@
+package models
+
+import "time"
+
+// 通報の理由コード
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonHateSpeech    = "hate_speech"
+	ReportReasonSexualContent = "sexual_content"
+	ReportReasonViolence      = "violence"
+	ReportReasonImpersonation = "impersonation"
+	ReportReasonOther         = "other"
+)
+
+// 通報の対象の種類
+const (
+	ReportTargetPost = "post"
+	ReportTargetUser = "user"
+)
+
+// 通報の状態
+const (
+	// ReportStatusOpen は未対応
+	ReportStatusOpen = "open"
+	// ReportStatusDismissed は問題なしとして却下された
+	ReportStatusDismissed = "dismissed"
+	// ReportStatusActioned は投稿の非表示・ユーザーの利用停止で対応された
+	ReportStatusActioned = "actioned"
+)
+
+// モデレーション操作の種類
+const (
+	// ModerationActionDismiss は通報の却下
+	ModerationActionDismiss = "dismiss"
+	// ModerationActionHidePost は通報された投稿の非表示
+	ModerationActionHidePost = "hide_post"
+	// ModerationActionSuspendUser は通報されたユーザー（投稿の通報では投稿者）、または管理者が指定したユーザーの利用停止
+	ModerationActionSuspendUser = "suspend_user"
+	// ModerationActionUnsuspendUser は利用停止の解除（通報によらない操作）
+	ModerationActionUnsuspendUser = "unsuspend_user"
+	// ModerationActionChangeRole はユーザーのロールの変更（通報によらない操作。Note に変更後のロールを記録する）
+	ModerationActionChangeRole = "change_role"
+)
+
+// CreateReportInput は通報時の入力
+type CreateReportInput struct {
+	// 理由コード（spam / harassment / hate_speech / sexual_content / violence / impersonation / other）
+	Reason string `json:"reason" binding:"required,oneof=spam harassment hate_speech sexual_content violence impersonation other" example:"spam"`
+	// 補足（任意、1000文字以内）
+	Comment string `json:"comment" binding:"max=1000" example:"同じ内容の投稿を繰り返しています"`
+}
+
+// Report は投稿またはユーザーへの通報
+type Report struct {
+	ID         int `json:"id" example:"1"`
+	ReporterID int `json:"reporter_id" example:"2"`
+	// post / user
+	TargetType string `json:"target_type" example:"post"`
+	// 通報対象の投稿ID（target_type が post の場合のみ）
+	PostID *int `json:"post_id,omitempty" example:"10"`
+	// 通報対象のユーザーID（投稿の通報では投稿者）
+	TargetUserID int    `json:"target_user_id" example:"1"`
+	Reason       string `json:"reason" example:"spam"`
+	Comment      string `json:"comment" example:""`
+	// open / dismissed / actioned
+	Status string `json:"status" example:"open"`
+	// 対応内容（dismiss / hide_post / suspend_user、未対応の場合は空）
+	Resolution string     `json:"resolution,omitempty" example:""`
+	ResolvedBy *int       `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ReportListQuery はモデレーションキュー取得時のパラメータ
+type ReportListQuery struct {
+	// 状態で絞り込む（open / dismissed / actioned、省略時は open）
+	Status string `form:"status" binding:"omitempty,oneof=open dismissed actioned" example:"open"`
+	// 取得件数（1〜100、省略時は20）
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	// 読み飛ばす件数
+	Offset int `form:"offset" binding:"omitempty,min=0" example:"0"`
+}
+
+// ReportsResponse はモデレーションキューのレスポンス
+type ReportsResponse struct {
+	Reports []Report `json:"reports"`
+	Total   int      `json:"total" example:"3"`
+	Limit   int      `json:"limit" example:"20"`
+	Offset  int      `json:"offset" example:"0"`
+}
+
+// ResolveReportInput は通報の対応時の入力
+type ResolveReportInput struct {
+	// 対応内容（dismiss / hide_post / suspend_user。hide_post は投稿の通報のみ）
+	Action string `json:"action" binding:"required,oneof=dismiss hide_post suspend_user" example:"hide_post"`
+	// 管理者のメモ（任意、1000文字以内）。suspend_user では利用停止の理由として本人に返す（省略時は通報の理由コード）
+	Note string `json:"note" binding:"max=1000" example:"スパム投稿のため非表示"`
+	// 利用停止の終了日時（suspend_user のみ、省略時は解除されるまで無期限）
+	SuspendUntil *time.Time `json:"suspend_until,omitempty" example:"2026-12-31T00:00:00Z"`
+}
+
+// SuspendUserInput は管理者によるユーザーの利用停止時の入力
+type SuspendUserInput struct {
+	// 利用停止の理由（本人のログイン・トークン更新の拒否時に返す）
+	Reason string `json:"reason" binding:"required,max=1000" example:"スパム行為のため"`
+	// 利用停止の終了日時（省略時は解除されるまで無期限）
+	Until *time.Time `json:"until,omitempty" example:"2026-12-31T00:00:00Z"`
+}
+
+// ModerationAction はモデレーション操作の記録
+type ModerationAction struct {
+	ID int `json:"id" example:"1"`
+	// 操作した管理者（退会済みの場合は含まれない）
+	ModeratorID *int `json:"moderator_id,omitempty" example:"1"`
+	// dismiss / hide_post / suspend_user / unsuspend_user / change_role
+	Action       string    `json:"action" example:"hide_post"`
+	ReportID     *int      `json:"report_id,omitempty" example:"1"`
+	PostID       *int      `json:"post_id,omitempty" example:"10"`
+	TargetUserID *int      `json:"target_user_id,omitempty" example:"3"`
+	Note         string    `json:"note" example:"スパム投稿のため非表示"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ModerationActionListQuery はモデレーション操作の記録取得時のページングパラメータ
+type ModerationActionListQuery struct {
+	// 取得件数（1〜100、省略時は20）
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	// 読み飛ばす件数
+	Offset int `form:"offset" binding:"omitempty,min=0" example:"0"`
+}
+
+// ModerationActionsResponse はモデレーション操作の記録のレスポンス
+type ModerationActionsResponse struct {
+	Actions []ModerationAction `json:"actions"`
+	Total   int                `json:"total" example:"5"`
+	Limit   int                `json:"limit" example:"20"`
+	Offset  int                `json:"offset" example:"0"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// SessionDevice はログイン・トークン更新を行った端末の情報
+type SessionDevice struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session はログイン中の端末（1回のログインから続く Refresh Token のファミリー）
+type Session struct {
+	// セッションID（DELETE /auth/sessions/{id} で指定する）
+	ID string `json:"id" example:"0b6f7c1e-4d2a-4f3b-9c1d-2e5a8b7c6d40"`
+	// 最後にログイン・トークン更新を行った端末の User-Agent
+	UserAgent string `json:"user_agent" example:"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"`
+	// 最後にログイン・トークン更新を行った端末の IP アドレス
+	IPAddress string `json:"ip_address" example:"203.0.113.10"`
+	// ログインした日時
+	CreatedAt time.Time `json:"created_at"`
+	// 最後にトークンを更新した日時（ログイン後に更新していない場合はログインした日時）
+	LastUsedAt time.Time `json:"last_used_at"`
+	// このリクエストを送った端末のセッションか
+	Current bool `json:"current" example:"true"`
+}
+
+// SessionsResponse はログイン中の端末一覧のレスポンス
+type SessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
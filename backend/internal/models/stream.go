@@ -0,0 +1,41 @@
+package models
+
+// ストリーム（Server-Sent Events）で配信するイベントの種類
+const (
+	// StreamEventPostCreated は新しい投稿の作成（全接続に配信）
+	StreamEventPostCreated = "post_created"
+	// StreamEventLikeCountChanged は投稿のいいね数の変化（全接続に配信）
+	StreamEventLikeCountChanged = "like_count_changed"
+	// StreamEventNotification は個人宛ての通知（受信者の接続にのみ配信）
+	StreamEventNotification = "notification"
+)
+
+// StreamEvent はストリームで配信するイベント
+type StreamEvent struct {
+	// イベントの種類（StreamEvent* 定数）。SSE の event フィールドになる
+	Type string
+	// 配信先のユーザー（nil の場合は全接続に配信）
+	UserID *int
+	// SSE の data フィールドに JSON として書き出すペイロード
+	Data interface{}
+}
+
+// PostCreatedStreamData は post_created イベントのペイロード
+type PostCreatedStreamData struct {
+	PostID int `json:"post_id" example:"10"`
+	UserID int `json:"user_id" example:"1"`
+}
+
+// LikeCountStreamData は like_count_changed イベントのペイロード
+type LikeCountStreamData struct {
+	PostID int `json:"post_id" example:"10"`
+	Likes  int `json:"likes" example:"5"`
+}
+
+// NotificationStreamData は notification イベントのペイロード
+// 受信したクライアントは必要に応じて通知一覧・未読数を再取得する
+type NotificationStreamData struct {
+	Type    string `json:"type" example:"post_liked"`
+	PostID  *int   `json:"post_id,omitempty" example:"10"`
+	ActorID int    `json:"actor_id" example:"2"`
+}
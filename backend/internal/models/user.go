@@ -1,9 +1,21 @@
 package models
 
 import (
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ユーザーのロール
+const (
+	// RoleUser は一般ユーザー
+	RoleUser = "user"
+	// RoleModerator は通報の対応ができるユーザー
+	RoleModerator = "moderator"
+	// RoleAdmin は通報の対応とロールの変更ができるユーザー
+	RoleAdmin = "admin"
+)
+
 // User represents a user in the system
 type User struct {
 	ID           int    `json:"id"`
@@ -13,6 +25,24 @@ type User struct {
 	Description  string `json:"description"`
 	IconURL      string `json:"icon_url"`
 	ExternalURL  string `json:"external_url"`
+	// ロール（公開のユーザー情報には含めず、AuthResponse でのみ返す）
+	Role string `json:"-"`
+	// 利用停止の開始日時・終了日時（終了日時が nil の場合は無期限）と理由
+	SuspendedAt      *time.Time `json:"-"`
+	SuspendedUntil   *time.Time `json:"-"`
+	SuspensionReason string     `json:"-"`
+	// トークンバージョン（これより古いバージョンのアクセストークンは無効）
+	TokenVersion int `json:"-"`
+	// メールアドレスを確認した日時（nil の場合は未確認）
+	EmailVerifiedAt *time.Time `json:"-"`
+}
+
+// IsSuspended は now の時点で利用停止中かどうかを返す
+func (u *User) IsSuspended(now time.Time) bool {
+	if u.SuspendedAt == nil {
+		return false
+	}
+	return u.SuspendedUntil == nil || u.SuspendedUntil.After(now)
 }
 
 // HashPassword はパスワードをbcryptでハッシュ化する
@@ -63,9 +93,53 @@ type LoginInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// ChangePasswordInput はパスワード変更のリクエストボディ
+// 新しいパスワードには CreateUserInput と同じく12文字以上を必須とする
+type ChangePasswordInput struct {
+	// 現在のパスワード
+	CurrentPassword string `json:"current_password" binding:"required"`
+	// 新しいパスワード
+	NewPassword string `json:"new_password" binding:"required,min=12"`
+}
+
+// PasswordResetRequestInput はパスワードリセットの申請のリクエストボディ
+type PasswordResetRequestInput struct {
+	// 登録したメールアドレス
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetConfirmInput はパスワードリセットの確定のリクエストボディ
+// 新しいパスワードには CreateUserInput と同じく12文字以上を必須とする
+type PasswordResetConfirmInput struct {
+	// メールで送ったリンクに含まれるトークン
+	Token string `json:"token" binding:"required"`
+	// 新しいパスワード
+	NewPassword string `json:"new_password" binding:"required,min=12"`
+}
+
+// VerifyEmailInput はメールアドレスの確認のリクエストボディ
+type VerifyEmailInput struct {
+	// メールで送ったリンクに含まれるトークン
+	Token string `json:"token" binding:"required"`
+}
+
 // AuthResponse represents the response for authentication
 type AuthResponse struct {
 	User User `json:"user"`
+	// 認証ユーザーのロール（user / moderator / admin）
+	Role string `json:"role" example:"user"`
+	// メールアドレスを確認済みかどうか
+	EmailVerified bool `json:"email_verified" example:"true"`
+}
+
+// NewAuthResponse は認証ユーザーの AuthResponse を作成する
+func NewAuthResponse(user *User) AuthResponse {
+	return AuthResponse{User: *user, Role: user.Role, EmailVerified: user.EmailVerifiedAt != nil}
+}
+
+// UpdateRoleInput はロール変更のリクエストボディ
+type UpdateRoleInput struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin" example:"moderator"`
 }
 
 // ErrorResponse represents an error response
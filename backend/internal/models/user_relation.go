@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// RelatedUser はブロック・ミュートしたユーザーの一覧の1件
+type RelatedUser struct {
+	User User `json:"user"`
+	// ブロック・ミュートした日時
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RelatedUsersResponse はブロック・ミュートしたユーザーの一覧のレスポンス
+type RelatedUsersResponse struct {
+	Users []RelatedUser `json:"users"`
+	Total int           `json:"total" example:"1"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// UserStats はユーザーの喫煙（投稿）統計
+// 月・日の区切りはサーバーのタイムゾーン（TZ）を基準に集計する
+type UserStats struct {
+	// 対象ユーザーID
+	UserID int `json:"user_id" example:"1"`
+	// 投稿数の合計（削除済みを除く）
+	TotalPosts int `json:"total_posts" example:"42"`
+	// 月ごとの投稿数（古い月から順、投稿のない月は含まない）
+	PostsPerMonth []MonthlyCount `json:"posts_per_month"`
+	// よく使うフレーバー（使用した投稿数の多い順、最大5件）
+	TopFlavors []FlavorUsage `json:"top_flavors"`
+	// よく使うフレーバーカテゴリ（使用した投稿数の多い順、最大5件）
+	TopFlavorCategories []CategoryUsage `json:"top_flavor_categories"`
+	// これまでに使用したフレーバーの種類数
+	DistinctFlavors int `json:"distinct_flavors" example:"12"`
+	// 受け取ったいいね数の合計
+	TotalLikesReceived int `json:"total_likes_received" example:"128"`
+	// 月ごとに受け取ったいいね数（いいねされた日時で集計、古い月から順）
+	LikesReceivedPerMonth []MonthlyCount `json:"likes_received_per_month"`
+	// 連続投稿日数の最長記録
+	LongestStreakDays int `json:"longest_streak_days" example:"7"`
+	// 統計の集計日時（キャッシュされた統計の場合は集計時点の日時）
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// MonthlyCount は月ごとの件数
+type MonthlyCount struct {
+	// 対象月（YYYY-MM）
+	Month string `json:"month" example:"2025-01"`
+	// 件数
+	Count int `json:"count" example:"5"`
+}
+
+// FlavorUsage はフレーバーごとの使用数
+type FlavorUsage struct {
+	Flavor Flavor `json:"flavor"`
+	// そのフレーバーを使用した投稿数
+	Count int `json:"count" example:"8"`
+}
+
+// CategoryUsage はフレーバーカテゴリごとの使用数
+type CategoryUsage struct {
+	// フレーバーカテゴリ
+	Category string `json:"category" example:"フルーツ"`
+	// そのカテゴリのフレーバーを使用した投稿数
+	Count int `json:"count" example:"15"`
+}
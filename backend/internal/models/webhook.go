@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook で配信するイベントの種類
+const (
+	// WebhookEventPostCreated は投稿の作成（リミックスを含む）
+	WebhookEventPostCreated = "post.created"
+	// WebhookEventPostDeleted は投稿の削除
+	WebhookEventPostDeleted = "post.deleted"
+	// WebhookEventLikeAdded は投稿へのいいね
+	WebhookEventLikeAdded = "like.added"
+)
+
+// WebhookEventTypes は購読可能なイベントの種類
+var WebhookEventTypes = []string{WebhookEventPostCreated, WebhookEventPostDeleted, WebhookEventLikeAdded}
+
+// Webhook 配信の状態
+const (
+	// WebhookDeliveryPending は送信待ち（再送待ちを含む）
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliverySucceeded は送信成功（2xx を受信）
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed は再送上限に達して送信を諦めた
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookEndpoint は Webhook の登録先
+type WebhookEndpoint struct {
+	ID     int    `json:"id" example:"1"`
+	UserID int    `json:"user_id" example:"1"`
+	URL    string `json:"url" example:"https://example.com/webhooks/shisha"`
+	// 購読するイベントの種類
+	Events   []string `json:"events" example:"post.created,like.added"`
+	IsActive bool     `json:"is_active" example:"true"`
+	// 署名の検証に使う鍵（登録時のレスポンスにのみ含まれる）
+	Secret    string    `json:"secret,omitempty" example:"5f2b..."`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookEndpointsResponse は Webhook 登録先一覧のレスポンス
+type WebhookEndpointsResponse struct {
+	Endpoints []WebhookEndpoint `json:"endpoints"`
+	Total     int               `json:"total" example:"1"`
+}
+
+// CreateWebhookInput は Webhook 登録時の入力
+type CreateWebhookInput struct {
+	// 配信先URL（http/https）
+	URL string `json:"url" binding:"required,max=2048" example:"https://example.com/webhooks/shisha"`
+	// 購読するイベントの種類（post.created / post.deleted / like.added）
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=post.created post.deleted like.added" example:"post.created,like.added"`
+}
+
+// WebhookPayload は Webhook で送信する JSON 本文
+type WebhookPayload struct {
+	// イベントの一意なID（X-Webhook-Delivery ヘッダーにも含まれる。受信側の重複排除に使う）
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// PostWebhookData は post.created / post.deleted イベントのデータ
+type PostWebhookData struct {
+	PostID int `json:"post_id"`
+	UserID int `json:"user_id"`
+	// リミックス元の投稿ID（post.created でリミックスの場合のみ）
+	RemixedFrom *int `json:"remixed_from,omitempty"`
+}
+
+// LikeWebhookData は like.added イベントのデータ
+type LikeWebhookData struct {
+	PostID int `json:"post_id"`
+	// いいねしたユーザー
+	UserID int `json:"user_id"`
+	// 投稿者
+	PostOwnerID int `json:"post_owner_id"`
+	// いいね後のいいね数
+	Likes int `json:"likes"`
+}
+
+// WebhookDelivery は Webhook の配信（配信キューの1件、配信ログとしても使う）
+type WebhookDelivery struct {
+	ID         int    `json:"id" example:"1"`
+	EndpointID int    `json:"endpoint_id" example:"1"`
+	EventID    string `json:"event_id" example:"8f14e45f-ceea-467f-a2c5-3f0d0a1b2c3d"`
+	EventType  string `json:"event_type" example:"post.created"`
+	// 送信する JSON 本文
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+	// pending / succeeded / failed
+	Status   string `json:"status" example:"succeeded"`
+	Attempts int    `json:"attempts" example:"1"`
+	// 次回の送信予定日時（pending の場合のみ）
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+	// 直近の送信で受け取ったHTTPステータス
+	ResponseStatus *int `json:"response_status,omitempty" example:"200"`
+	// 直近の送信失敗の理由
+	LastError string    `json:"last_error,omitempty" example:""`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryListQuery は配信ログ取得時のページングパラメータ
+type WebhookDeliveryListQuery struct {
+	// 取得件数（1〜100、省略時は20）
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	// 読み飛ばす件数
+	Offset int `form:"offset" binding:"omitempty,min=0" example:"0"`
+}
+
+// WebhookDeliveriesResponse は配信ログのレスポンス
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int               `json:"total" example:"42"`
+	Limit      int               `json:"limit" example:"20"`
+	Offset     int               `json:"offset" example:"0"`
+}
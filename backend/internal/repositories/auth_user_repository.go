@@ -9,4 +9,9 @@ type AuthUserRepository interface {
 	GetByEmail(email string) (*models.User, error)
 	Create(user *models.User) error
 	GetByID(id int) (*models.User, error)
+	// UpdatePassword はパスワードハッシュを更新する（ユーザーが存在しない場合は ErrUserNotFound）
+	UpdatePassword(userID int, passwordHash string) error
+	// MarkEmailVerified はメールアドレスが email のままであればユーザーを確認済みにする
+	// ユーザーが存在しないか、メールアドレスが変わっている場合は ErrUserNotFound
+	MarkEmailVerified(userID int, email string) error
 }
@@ -0,0 +1,21 @@
+package repositories
+
+import "go-shisha-backend/internal/models"
+
+// BadgeRepository はユーザーバッジのデータアクセスのインターフェース
+// 返却される UserBadge には Code と AwardedAt のみが設定される（名称・説明はサービス層で付与する）
+type BadgeRepository interface {
+	// Award は、指定されたバッジをユーザーに付与し、今回新たに付与されたバッジのみを返す
+	// すでに獲得済みのバッジは無視される
+	Award(userID int, codes []string) ([]models.UserBadge, error)
+
+	// ListByUserID は、ユーザーが獲得したバッジを獲得日時の古い順に返す
+	ListByUserID(userID int) ([]models.UserBadge, error)
+
+	// ListUnseen は、ユーザーが未確認のバッジを獲得日時の古い順に返す（確認済みにはしない）
+	ListUnseen(userID int) ([]models.UserBadge, error)
+
+	// MarkSeen は、ユーザーの指定されたバッジを確認済みにする
+	// 獲得していない・確認済みのバッジは無視される
+	MarkSeen(userID int, codes []string) error
+}
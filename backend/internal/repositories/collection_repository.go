@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+)
+
+var (
+	// ErrCollectionNotFound は、対象のコレクションが存在しない場合に返されるエラー
+	ErrCollectionNotFound = errors.New("collection not found")
+	// ErrAlreadyInCollection は、コレクションにすでに収録されている投稿を追加しようとしたときに返されるエラー
+	ErrAlreadyInCollection = errors.New("post already in collection")
+	// ErrNotInCollection は、コレクションに収録されていない投稿を削除しようとしたときに返されるエラー
+	ErrNotInCollection = errors.New("post not in collection")
+	// ErrCollectionItemsMismatch は、並び替えで指定した投稿IDが収録されている投稿と一致しない場合に返されるエラー
+	ErrCollectionItemsMismatch = errors.New("collection items mismatch")
+)
+
+// CollectionRepository はコレクションデータアクセスのインターフェースを定義する
+// 収録投稿のうち論理削除された投稿は、件数・一覧・並び替えのいずれでも存在しないものとして扱う
+// 収録投稿の数（ItemCount）は viewerID の閲覧者に表示される投稿の数で、閲覧者がブロック・ミュートした投稿とミュートルールに一致する投稿を数えない（nil の場合は未ログインの閲覧者から見た数）
+type CollectionRepository interface {
+	// Create は、新しいコレクションを作成する
+	Create(collection *models.Collection) error
+
+	// GetByID は、指定された ID のコレクションを返す（Posts は設定しない）
+	// 存在しない場合は ErrCollectionNotFound を返す
+	GetByID(id int, viewerID *int) (*models.Collection, error)
+
+	// ListByUserID は、指定されたユーザーのコレクションを作成日時の新しい順に返す
+	// includePrivate が false の場合は公開コレクションのみを返す
+	ListByUserID(userID int, includePrivate bool, viewerID *int) ([]models.Collection, error)
+
+	// Update は、指定されたコレクションの名前・説明・公開設定を更新する（nil の項目は変更しない）
+	// 存在しない場合は ErrCollectionNotFound を返す
+	Update(id int, input models.UpdateCollectionInput, viewerID *int) (*models.Collection, error)
+
+	// Delete は、指定されたコレクションと収録情報を削除する
+	// 存在しない場合は ErrCollectionNotFound を返す
+	Delete(id int) error
+
+	// ListPostIDs は、コレクションに収録されている投稿IDを並び順で返す
+	ListPostIDs(collectionID int) ([]int, error)
+
+	// AddItem は、コレクションの末尾に投稿を追加する（同時の追加・並び替えとは順に処理し、同じ並び順を割り当てない）
+	// すでに収録されている場合は ErrAlreadyInCollection、投稿が存在しない場合は ErrPostNotFound、コレクションが存在しない場合は ErrCollectionNotFound を返す
+	AddItem(collectionID, postID int) error
+
+	// RemoveItem は、コレクションから投稿を取り除く
+	// 収録されていない場合は ErrNotInCollection を返す
+	RemoveItem(collectionID, postID int) error
+
+	// ReorderItems は、postIDs の順序でコレクション内の並び順を更新する
+	// postIDs が収録されている投稿IDと過不足なく一致しない場合は ErrCollectionItemsMismatch、コレクションが存在しない場合は ErrCollectionNotFound を返す
+	ReorderItems(collectionID int, postIDs []int) error
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// ログインの失敗の記録のセンチネルエラー
+var (
+	// ErrLoginFailureNotFound はユーザーのログインの失敗の記録が存在しない場合のエラー
+	ErrLoginFailureNotFound = errors.New("login failure not found")
+	// ErrLoginAttemptThrottled は待ち時間中・ロック中のため、ログインの試行を確保できなかった場合のエラー
+	ErrLoginAttemptThrottled = errors.New("login attempt throttled")
+)
+
+// LoginFailureRepository はアカウントごとのログインの失敗の記録のデータアクセスのインターフェースを定義する
+type LoginFailureRepository interface {
+	// Get は、ユーザーのログインの失敗の記録を返す（存在しない場合は ErrLoginFailureNotFound）
+	Get(userID int) (*models.LoginFailure, error)
+
+	// Reserve は、パスワードを検証する前にユーザーのログインの試行を at に1回数え、次の試行を受け付ける日時を at + delay(数えた後の回数) にする
+	// 次の試行を受け付ける日時・ロックの終了日時が at より後の場合は数えずに ErrLoginAttemptThrottled を返す
+	// 同じアカウントへの同時の試行が判定をすり抜けないよう、判定と数えることを DB 上で1つの更新として行う
+	// 最後の試行が since より前の場合と、ロックの期限が過ぎた場合は（ロックを解除して）1回目から数え直す
+	Reserve(userID int, at, since time.Time, delay func(failedCount int) time.Duration) (*models.LoginFailure, error)
+
+	// Lock は、ユーザーのログインを until までロックする
+	Lock(userID int, until time.Time) error
+
+	// Delete は、ユーザーのログインの失敗の記録を削除する（ロックも解除される、記録がない場合も成功とする）
+	Delete(userID int) error
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+)
+
+// 2段階認証のセンチネルエラー
+var (
+	// ErrTOTPNotFound は TOTP の設定（確認済みの設定を操作する場合は確認済みの設定）が存在しない場合のエラー
+	ErrTOTPNotFound = errors.New("totp not found")
+	// ErrTOTPCodeReused は受け付け済みの時間ステップ以前のコードが使われた場合のエラー
+	ErrTOTPCodeReused = errors.New("totp code already used")
+	// ErrRecoveryCodeNotFound はリカバリーコードが存在しないか使用済みの場合のエラー
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+)
+
+// MFARepository は2段階認証の設定とリカバリーコードのデータアクセスのインターフェースを定義する
+// リカバリーコードはハッシュだけを保存し、生のコードは保存しない
+type MFARepository interface {
+	// GetTOTP は、ユーザーの TOTP の設定を返す（存在しない場合は ErrTOTPNotFound）
+	GetTOTP(userID int) (*models.TOTP, error)
+
+	// SaveTOTPSecret は、登録中の TOTP のシークレットを保存する（登録中のシークレットがあれば置き換える）
+	SaveTOTPSecret(userID int, secret string) error
+
+	// EnableTOTP は、登録中の TOTP を有効にし、counter を受け付け済みとしてリカバリーコードを保存する
+	// 登録中の設定が存在しない場合は ErrTOTPNotFound を返す
+	EnableTOTP(userID int, counter int64, recoveryCodes []string) error
+
+	// UseTOTPCounter は、有効な TOTP で counter のコードを受け付け済みにする
+	// counter が受け付け済みの時間ステップ以前の場合は ErrTOTPCodeReused を返す
+	UseTOTPCounter(userID int, counter int64) error
+
+	// ReplaceRecoveryCodes は、ユーザーのリカバリーコードをすべて置き換える
+	ReplaceRecoveryCodes(userID int, recoveryCodes []string) error
+
+	// ConsumeRecoveryCode は、未使用のリカバリーコードを使用済みにする（存在しない場合は ErrRecoveryCodeNotFound）
+	ConsumeRecoveryCode(userID int, recoveryCode string) error
+
+	// CountRecoveryCodes は、未使用のリカバリーコードの数を返す
+	CountRecoveryCodes(userID int) (int, error)
+
+	// DeleteTOTP は、ユーザーの TOTP の設定とリカバリーコードを削除する
+	DeleteTOTP(userID int) error
+}
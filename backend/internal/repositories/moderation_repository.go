@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+var (
+	// ErrReportNotFound は、対象の通報が存在しない場合に返されるエラー
+	ErrReportNotFound = errors.New("report not found")
+	// ErrAlreadyReported は、同じ対象への未対応の通報がすでに存在する場合に返されるエラー
+	ErrAlreadyReported = errors.New("already reported")
+	// ErrReportAlreadyResolved は、対応済みの通報を再度対応しようとした場合に返されるエラー
+	ErrReportAlreadyResolved = errors.New("report already resolved")
+	// ErrInvalidModerationAction は、通報の対象に適用できない操作（ユーザーの通報への hide_post 等）の場合に返されるエラー
+	ErrInvalidModerationAction = errors.New("invalid moderation action for report target")
+	// ErrCannotSuspendAdmin は、管理者を利用停止にしようとした場合に返されるエラー（先にロールを変更する）
+	ErrCannotSuspendAdmin = errors.New("cannot suspend an admin")
+	// ErrNotSuspended は、利用停止中でないユーザーの利用停止を解除しようとした場合に返されるエラー
+	ErrNotSuspended = errors.New("user is not suspended")
+)
+
+// ModerationRepository は通報とモデレーション操作のデータアクセスのインターフェースを定義する
+type ModerationRepository interface {
+	// CreateReport は、通報を未対応として作成する
+	// 同じ通報者による同じ対象への未対応の通報がある場合は ErrAlreadyReported を返す
+	CreateReport(report *models.Report) error
+
+	// GetReport は、指定された ID の通報を返す
+	// 存在しない場合は ErrReportNotFound を返す
+	GetReport(id int) (*models.Report, error)
+
+	// ListReports は、指定された状態の通報を古い順に返し、あわせて総数を返す
+	ListReports(status string, limit, offset int) ([]models.Report, int, error)
+
+	// ResolveReport は、通報を1つのトランザクションで対応し、操作を記録する
+	//   - dismiss: 通報を却下する
+	//   - hide_post: 投稿を非表示にし、同じ投稿への未対応の通報をまとめて対応済みにする
+	//   - suspend_user: 通報対象のユーザーを suspendUntil まで（nil の場合は無期限）利用停止にし、同じユーザーへの未対応の通報をまとめて対応済みにする
+	//     利用停止の理由は note（空の場合は通報の理由コード）とする
+	// 存在しない場合は ErrReportNotFound、対応済みの場合は ErrReportAlreadyResolved、
+	// 対象に適用できない操作の場合は ErrInvalidModerationAction、管理者を利用停止にしようとした場合は ErrCannotSuspendAdmin を返す
+	ResolveReport(reportID, moderatorID int, action, note string, suspendUntil *time.Time) (*models.Report, error)
+
+	// SuspendUser は、ユーザーを until まで（nil の場合は無期限）利用停止にし、操作を記録する
+	// トークンバージョンを進めて発行済みのアクセストークンを無効化し、リフレッシュトークンを削除する
+	// 存在しない場合は ErrUserNotFound、管理者の場合は ErrCannotSuspendAdmin を返す
+	SuspendUser(moderatorID, userID int, reason string, until *time.Time) error
+
+	// UnsuspendUser は、ユーザーの利用停止を解除し、操作を記録する
+	// 存在しない場合は ErrUserNotFound、利用停止中でない場合は ErrNotSuspended を返す
+	UnsuspendUser(moderatorID, userID int) error
+
+	// ListActions は、モデレーション操作の記録を新しい順に返し、あわせて総数を返す
+	ListActions(limit, offset int) ([]models.ModerationAction, int, error)
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+)
+
+// ErrMuteRuleNotFound は、対象のミュートルールが存在しない場合に返されるエラー
+var ErrMuteRuleNotFound = errors.New("mute rule not found")
+
+// MuteRuleRepository はミュートルールのデータアクセスのインターフェースを定義する
+// ルールの適用（投稿の除外）は PostRepository が行う
+type MuteRuleRepository interface {
+	// Create は、ミュートルールを作成する
+	Create(rule *models.MuteRule) error
+
+	// GetByID は、指定された ID のミュートルールを返す
+	// 存在しない場合は ErrMuteRuleNotFound を返す
+	GetByID(id int) (*models.MuteRule, error)
+
+	// ListByUserID は、ユーザーのミュートルールを作成日時の新しい順に返す（期限切れのルールを含む）
+	ListByUserID(userID int) ([]models.MuteRule, error)
+
+	// Update は、ミュートルールの種類・語・フレーバー・有効期限を rule の内容で置き換える
+	// 存在しない場合は ErrMuteRuleNotFound を返す
+	Update(rule *models.MuteRule) error
+
+	// Delete は、ミュートルールを削除する
+	// 存在しない場合は ErrMuteRuleNotFound を返す
+	Delete(id int) error
+}
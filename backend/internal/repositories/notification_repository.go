@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+)
+
+// ErrNotificationNotFound は、対象の通知が存在しない（または他ユーザーの通知である）場合に返されるエラー
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationRepository は通知データアクセスのインターフェースを定義する
+// 返却される Notification の Message はサービス層で付与する
+type NotificationRepository interface {
+	// Create は、イベントから新しい通知を1件作成する（まとめ先にはならない）
+	Create(event models.NotificationEvent) error
+
+	// CreateOrGroup は、同じ受信者・種類・投稿の未読のまとめ先通知があればそこに操作ユーザーを加えてまとめ、なければまとめ先として新しく作成する
+	// 同じ操作ユーザーはまとめ先の通知で重複して数えない
+	CreateOrGroup(event models.NotificationEvent) error
+
+	// ListByUserID は、ユーザーの通知を更新日時の新しい順に返し、あわせて通知の総数を返す
+	ListByUserID(userID, limit, offset int) ([]models.Notification, int, error)
+
+	// CountUnread は、ユーザーの未読通知数を返す
+	CountUnread(userID int) (int, error)
+
+	// MarkRead は、ユーザーの通知を既読にする（既読の場合は何もしない）
+	// 存在しない、または他ユーザーの通知の場合は ErrNotificationNotFound を返す
+	MarkRead(userID, notificationID int) error
+
+	// MarkAllRead は、ユーザーの未読通知をすべて既読にし、既読にした件数を返す
+	MarkAllRead(userID int) (int, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// OutboxRepository はアウトボックスに書き込まれたドメインイベントのデータアクセスのインターフェースを定義する
+// イベントの書き込みは各リポジトリが変更と同じトランザクション内で行うため、ここでは配送側の操作のみを扱う
+type OutboxRepository interface {
+	// ClaimDueEvents は、配送予定日時を過ぎた配送待ちのイベントを古い順に最大 limit 件取り出す
+	// 取り出したイベントは次回配送予定を now+lease に延ばし、他のディスパッチャーが同時に配送しないようにする
+	ClaimDueEvents(now time.Time, lease time.Duration, limit int) ([]models.DomainEvent, error)
+
+	// RecordAttempt は、配送結果を記録する
+	// event の Status・Attempts・CompletedHandlers・NextAttemptAt・LastError・ProcessedAt を保存する
+	RecordAttempt(event *models.DomainEvent) error
+
+	// DeleteProcessedBefore は、処理日時が before より前の処理済みのイベントを削除し、削除した件数を返す
+	// 失敗したイベントは原因の調査のため削除しない
+	DeleteProcessedBefore(before time.Time) (int64, error)
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// パスキーのセンチネルエラー
+var (
+	// ErrPasskeyNotFound はパスキーが存在しないか、他のユーザーのものである場合のエラー
+	ErrPasskeyNotFound = errors.New("passkey not found")
+	// ErrPasskeyAlreadyExists は同じクレデンシャルIDのパスキーが登録済みの場合のエラー
+	ErrPasskeyAlreadyExists = errors.New("passkey already exists")
+	// ErrPasskeySignCountStale は署名カウンターを更新する時点で、保存済みのカウンターが新しい値以上になっていた場合のエラー
+	// 同じカウンターのレスポンスが並行して使われた（認証器が複製された可能性がある）ことを示す
+	ErrPasskeySignCountStale = errors.New("passkey sign count is stale")
+)
+
+// PasskeyRepository はパスキー（WebAuthn のクレデンシャル）のデータアクセスのインターフェースを定義する
+type PasskeyRepository interface {
+	// Create は、パスキーを登録する（クレデンシャルIDが重複する場合は ErrPasskeyAlreadyExists）
+	Create(passkey *models.Passkey) error
+
+	// GetByCredentialID は、クレデンシャルIDのパスキーを返す（存在しない場合は ErrPasskeyNotFound）
+	GetByCredentialID(credentialID []byte) (*models.Passkey, error)
+
+	// ListByUserID は、ユーザーのパスキーを登録順に返す
+	ListByUserID(userID int) ([]models.Passkey, error)
+
+	// UpdateSignCount は、ログインに使ったパスキーの署名カウンターと最終使用日時を更新する
+	// 保存済みのカウンターが signCount より小さい場合（カウンターを使わない認証器ではともに 0 の場合）だけ更新し、
+	// それ以外は ErrPasskeySignCountStale を返す
+	UpdateSignCount(id int64, signCount uint32, usedAt time.Time) error
+
+	// Rename は、ユーザーのパスキーの名前を変更する（存在しない場合は ErrPasskeyNotFound）
+	Rename(userID int, id int64, name string) error
+
+	// Delete は、ユーザーのパスキーを削除する（存在しない場合は ErrPasskeyNotFound）
+	Delete(userID int, id int64) error
+}
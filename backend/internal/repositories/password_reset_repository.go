@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidPasswordResetToken はパスワードリセットのトークンが存在しない・使用済み・有効期限切れの場合のエラー
+var ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+
+// PasswordResetRepository はパスワードリセットのトークンのデータアクセスのインターフェースを定義する
+// トークンはハッシュだけを保存し、生のトークンは保存しない
+type PasswordResetRepository interface {
+	// CreateToken は、ユーザーのパスワードリセットのトークンを有効期限 expiresAt で保存する
+	// ユーザーの未使用のトークンは無効にし、最後に発行したトークンだけを使えるようにする
+	CreateToken(userID int, rawToken string, expiresAt time.Time) error
+
+	// ConsumeToken は、有効なトークンを使用済みにしてユーザーIDを返す
+	// トークンが存在しない・使用済み・有効期限切れの場合は ErrInvalidPasswordResetToken を返す
+	ConsumeToken(rawToken string, now time.Time) (int, error)
+
+	// DeleteExpiredTokens は、有効期限が before より前のトークンを削除し、削除した件数を返す
+	DeleteExpiredTokens(before time.Time) (int64, error)
+}
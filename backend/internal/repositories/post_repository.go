@@ -24,17 +24,33 @@ var (
 )
 
 // PostRepository は投稿データアクセスのインターフェースを定義する
+// 管理者によって非表示にされた投稿は、取得系のメソッドでは存在しないものとして扱う
 type PostRepository interface {
 	// GetAll は、指定されたユーザーのいいね状態（userID が nil の場合は未ログインとして扱う）を含めて、すべての投稿を取得する
-	GetAll(userID *int) ([]models.Post, error)
+	// filter に指定したセッション詳細の条件に一致する投稿のみを返す
+	// userID がミュート・ブロックしたユーザーと、userID をブロックしたユーザーの投稿は除外する
+	// userID の有効なミュートルール（キーワード・フレーバー）に一致するスライドを含む投稿も除外する
+	GetAll(userID *int, filter models.PostFilter) ([]models.Post, error)
 
 	// GetByID は、指定された ID の投稿を取得し、指定されたユーザーのいいね状態（userID が nil の場合は未ログインとして扱う）を含めて返す
 	GetByID(id int, userID *int) (*models.Post, error)
 
+	// GetByIDs は、指定された ID の投稿を ids の順序で返す
+	// 存在しない・論理削除された・非表示の投稿は結果から除外される（エラーにはならない）
+	// userID を指定した場合は GetAll と同じく、ミュート・ブロックの関係にあるユーザーの投稿とミュートルールに一致する投稿も除外する
+	GetByIDs(ids []int, userID *int) ([]models.Post, error)
+
 	// GetByUserID は、指定されたユーザーの投稿一覧を取得し、カレントユーザーのいいね状態（currentUserID が nil の場合は未ログインとして扱う）を含めて返す
+	// currentUserID を指定した場合は GetAll と同じく、ミュート・ブロックの関係にあるユーザーの投稿とミュートルールに一致する投稿を除外する
 	GetByUserID(userID int, currentUserID *int) ([]models.Post, error)
 
+	// IsVisibleTo は、postID の投稿を viewerID のユーザーのタイムラインに表示できるかを真偽値で返す
+	// GetAll と同じく、存在しない・論理削除された・非表示の投稿、ミュート・ブロックの関係にあるユーザーの投稿、ミュートルールに一致する投稿は false を返す
+	IsVisibleTo(postID, viewerID int) (bool, error)
+
 	// Create は、新しい投稿を作成する
+	// 同一トランザクション内で PostCreated イベントをアウトボックスに書き込む
+	// リミックスの場合、リミックス元の投稿者にブロックされていれば ErrBlocked を返す
 	Create(post *models.Post) error
 
 	// IncrementLikes は、指定された投稿のいいね数をインクリメントする（#162 で削除予定）
@@ -44,7 +60,9 @@ type PostRepository interface {
 	DecrementLikes(id int) (*models.Post, error)
 
 	// AddLike は、userID による postID へのいいねを記録する
-	// すでにいいね済みの場合は ErrAlreadyLiked を返す
+	// 同一トランザクション内で LikeAdded イベントをアウトボックスに書き込む
+	// すでにいいね済みの場合は ErrAlreadyLiked、投稿が存在しないか非表示の場合は ErrPostNotFound を返す
+	// 投稿者にブロックされている場合は ErrBlocked を返す
 	AddLike(userID, postID int) error
 
 	// RemoveLike は、userID による postID へのいいねを削除する
@@ -55,15 +73,18 @@ type PostRepository interface {
 	HasLiked(userID, postID int) (bool, error)
 
 	// DeletePost は、指定された postID の投稿をソフトデリートする
+	// 削除した投稿をリミックス元とする投稿は remixed_from の参照が外される
+	// 同一トランザクション内で PostDeleted イベントをアウトボックスに書き込む
 	// 投稿が存在しない、またはすでに削除されている場合は ErrPostNotFound を返す
 	// 投稿が userID に紐づかない場合は ErrForbidden を返す
 	DeletePost(userID, postID int) error
 
-	// UpdatePost は、指定された postID のスライドの text/flavor_id を更新する
-	// 投稿が存在しない場合は ErrPostNotFound を返す
+	// UpdatePost は、指定された postID のスライドの text/flavor_id とセッション詳細を更新する
+	// session が nil の場合はセッション詳細を変更せず、全項目が空の場合はセッション詳細を削除する
+	// 投稿が存在しないか非表示の場合は ErrPostNotFound を返す
 	// 投稿が userID に紐づかない場合は ErrForbidden を返す
 	// スライド枚数が既存と一致しない場合は ErrSlideCountMismatch を返す
 	// 入力スライドIDが重複している場合は ErrDuplicateSlideID を返す
 	// 入力スライドIDが対象投稿に属さない場合は ErrSlideNotBelongToPost を返す
-	UpdatePost(userID, postID int, slides []models.UpdateSlideInput) (*models.Post, error)
+	UpdatePost(userID, postID int, slides []models.UpdateSlideInput, session *models.ShishaSession) (*models.Post, error)
 }
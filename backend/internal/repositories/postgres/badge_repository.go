@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/pkg/logging"
+)
+
+type BadgeRepository struct {
+	db *gorm.DB
+}
+
+func NewBadgeRepository(db *gorm.DB) *BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+func (r *BadgeRepository) toDomain(bm *userBadgeModel) models.UserBadge {
+	return models.UserBadge{
+		Code:      bm.BadgeCode,
+		AwardedAt: bm.AwardedAt,
+	}
+}
+
+func (r *BadgeRepository) Award(userID int, codes []string) ([]models.UserBadge, error) {
+	awarded := []models.UserBadge{}
+	if len(codes) == 0 {
+		return awarded, nil
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := tx.NowFunc()
+		for _, code := range codes {
+			bm := userBadgeModel{UserID: int64(userID), BadgeCode: code, AwardedAt: now}
+			// 獲得済みのバッジは主キー重複となるため何もしない
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bm)
+			if result.Error != nil {
+				return fmt.Errorf("failed to award badge %s: %w", code, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				awarded = append(awarded, r.toDomain(&bm))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logging.L.Error("failed to award badges", "repository", "BadgeRepository", "method", "Award", "user_id", userID, "error", err)
+		return nil, err
+	}
+	for _, b := range awarded {
+		logging.L.Info("badge awarded", "repository", "BadgeRepository", "method", "Award", "user_id", userID, "badge", b.Code)
+	}
+	return awarded, nil
+}
+
+func (r *BadgeRepository) ListByUserID(userID int) ([]models.UserBadge, error) {
+	logging.L.Debug("querying badges by user ID", "repository", "BadgeRepository", "method", "ListByUserID", "user_id", userID)
+	var bms []userBadgeModel
+	if err := r.db.Where("user_id = ?", userID).Order("awarded_at ASC, badge_code ASC").Find(&bms).Error; err != nil {
+		logging.L.Error("failed to query badges", "repository", "BadgeRepository", "method", "ListByUserID", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query badges by user_id=%d: %w", userID, err)
+	}
+	badges := make([]models.UserBadge, 0, len(bms))
+	for i := range bms {
+		badges = append(badges, r.toDomain(&bms[i]))
+	}
+	return badges, nil
+}
+
+func (r *BadgeRepository) ListUnseen(userID int) ([]models.UserBadge, error) {
+	logging.L.Debug("querying unseen badges", "repository", "BadgeRepository", "method", "ListUnseen", "user_id", userID)
+	var bms []userBadgeModel
+	if err := r.db.Where("user_id = ? AND seen_at IS NULL", userID).Order("awarded_at ASC, badge_code ASC").Find(&bms).Error; err != nil {
+		logging.L.Error("failed to query unseen badges", "repository", "BadgeRepository", "method", "ListUnseen", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query unseen badges by user_id=%d: %w", userID, err)
+	}
+	badges := make([]models.UserBadge, 0, len(bms))
+	for i := range bms {
+		badges = append(badges, r.toDomain(&bms[i]))
+	}
+	return badges, nil
+}
+
+func (r *BadgeRepository) MarkSeen(userID int, codes []string) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	logging.L.Debug("marking badges as seen", "repository", "BadgeRepository", "method", "MarkSeen", "user_id", userID, "badges", codes)
+	if err := r.db.Model(&userBadgeModel{}).
+		Where("user_id = ? AND badge_code IN ? AND seen_at IS NULL", userID, codes).
+		Update("seen_at", r.db.NowFunc()).Error; err != nil {
+		logging.L.Error("failed to mark badges as seen", "repository", "BadgeRepository", "method", "MarkSeen", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to mark badges as seen for user_id=%d: %w", userID, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"testing"
+)
+
+func TestBadge_AwardIsIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBadgeRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	awarded, err := repo.Award(1, []string{"first_post"})
+	if err != nil {
+		t.Fatalf("Award failed: %v", err)
+	}
+	if len(awarded) != 1 || awarded[0].Code != "first_post" || awarded[0].AwardedAt.IsZero() {
+		t.Fatalf("unexpected awarded badges: %+v", awarded)
+	}
+
+	// 獲得済みのバッジは新規獲得として返さない
+	awarded, err = repo.Award(1, []string{"first_post", "weekly_streak"})
+	if err != nil {
+		t.Fatalf("second Award failed: %v", err)
+	}
+	if len(awarded) != 1 || awarded[0].Code != "weekly_streak" {
+		t.Fatalf("expected only weekly_streak to be newly awarded, got %+v", awarded)
+	}
+
+	badges, err := repo.ListByUserID(1)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if len(badges) != 2 {
+		t.Fatalf("expected 2 badges, got %+v", badges)
+	}
+}
+
+func TestBadge_ListUnseenAndMarkSeen(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewBadgeRepository(db)
+	for _, u := range []userModel{{ID: 1, Email: "u1@example.com"}, {ID: 2, Email: "u2@example.com"}} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	if _, err := repo.Award(1, []string{"first_post", "popular"}); err != nil {
+		t.Fatalf("Award failed: %v", err)
+	}
+	if _, err := repo.Award(2, []string{"first_post"}); err != nil {
+		t.Fatalf("Award failed: %v", err)
+	}
+
+	// 取得しただけでは確認済みにならない
+	for i := 0; i < 2; i++ {
+		unseen, err := repo.ListUnseen(1)
+		if err != nil {
+			t.Fatalf("ListUnseen failed: %v", err)
+		}
+		if len(unseen) != 2 {
+			t.Fatalf("expected 2 unseen badges, got %+v", unseen)
+		}
+	}
+
+	if err := repo.MarkSeen(1, []string{"first_post", "popular"}); err != nil {
+		t.Fatalf("MarkSeen failed: %v", err)
+	}
+	unseen, err := repo.ListUnseen(1)
+	if err != nil {
+		t.Fatalf("ListUnseen failed: %v", err)
+	}
+	if len(unseen) != 0 {
+		t.Fatalf("expected no unseen badges after marking, got %+v", unseen)
+	}
+
+	// 他ユーザーの未確認バッジには影響しない
+	unseen, err = repo.ListUnseen(2)
+	if err != nil {
+		t.Fatalf("ListUnseen user2 failed: %v", err)
+	}
+	if len(unseen) != 1 {
+		t.Fatalf("expected user2 to still have 1 unseen badge, got %+v", unseen)
+	}
+
+	// 確認済みにしたバッジ以外は未確認のまま残る
+	if _, err := repo.Award(1, []string{"first_post", "weekly_streak", "flavor_explorer"}); err != nil {
+		t.Fatalf("Award failed: %v", err)
+	}
+	if err := repo.MarkSeen(1, []string{"weekly_streak"}); err != nil {
+		t.Fatalf("MarkSeen failed: %v", err)
+	}
+	unseen, err = repo.ListUnseen(1)
+	if err != nil {
+		t.Fatalf("ListUnseen failed: %v", err)
+	}
+	if len(unseen) != 1 || unseen[0].Code != "flavor_explorer" {
+		t.Fatalf("expected only flavor_explorer to be unseen, got %+v", unseen)
+	}
+}
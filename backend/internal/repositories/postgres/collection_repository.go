@@ -0,0 +1,336 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type CollectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+func (r *CollectionRepository) toDomain(cm *collectionModel) models.Collection {
+	return models.Collection{
+		ID:          int(cm.ID),
+		UserID:      int(cm.UserID),
+		Name:        cm.Name,
+		Description: cm.Description,
+		IsPublic:    cm.IsPublic,
+		CreatedAt:   cm.CreatedAt,
+		UpdatedAt:   cm.UpdatedAt,
+	}
+}
+
+// visibleItems は論理削除・管理者による非表示のいずれでもない投稿の収録情報に絞り込むクエリを返す
+func (r *CollectionRepository) visibleItems(db *gorm.DB) *gorm.DB {
+	return db.Model(&collectionItemModel{}).
+		Joins("JOIN posts ON posts.id = collection_items.post_id AND posts.deleted_at IS NULL AND posts.hidden_at IS NULL")
+}
+
+// itemCountsByCollectionID は指定コレクションごとの収録投稿数を1クエリでまとめて取得する
+// viewerID が指定されている場合は、収録投稿の取得（PostRepository.GetByIDs）と同じく閲覧者がブロック・ミュートした投稿を数えない
+func (r *CollectionRepository) itemCountsByCollectionID(collectionIDs []int64, viewerID *int) (map[int64]int, error) {
+	counts := map[int64]int{}
+	if len(collectionIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		CollectionID int64
+		Count        int
+	}
+	query := r.visibleItems(r.db)
+	if viewerID != nil {
+		query = query.Scopes(excludeAuthorsHiddenFrom(*viewerID), excludeMutedContent(*viewerID, r.db.NowFunc()))
+	}
+	if err := query.
+		Select("collection_items.collection_id, COUNT(*) AS count").
+		Where("collection_items.collection_id IN ?", collectionIDs).
+		Group("collection_items.collection_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count collection items: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *CollectionRepository) Create(collection *models.Collection) error {
+	logging.L.Debug("creating collection", "repository", "CollectionRepository", "method", "Create", "user_id", collection.UserID)
+	cm := collectionModel{
+		UserID:      int64(collection.UserID),
+		Name:        collection.Name,
+		Description: collection.Description,
+		IsPublic:    collection.IsPublic,
+	}
+	if err := r.db.Create(&cm).Error; err != nil {
+		logging.L.Error("failed to create collection", "repository", "CollectionRepository", "method", "Create", "user_id", collection.UserID, "error", err)
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	collection.ID = int(cm.ID)
+	collection.CreatedAt = cm.CreatedAt
+	collection.UpdatedAt = cm.UpdatedAt
+	logging.L.Info("collection created", "repository", "CollectionRepository", "method", "Create", "collection_id", collection.ID, "user_id", collection.UserID)
+	return nil
+}
+
+// lockCollection は収録投稿の並び順を変更するトランザクションの間、コレクションの行をロックする
+// 同じコレクションへの同時の追加・並び替えが同じ並び順を割り当てないよう、並び順の読み取りより前に呼ぶ
+func lockCollection(tx *gorm.DB, collectionID int) error {
+	query := tx.Select("id")
+	if tx.Dialector.Name() == "postgres" {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	var cm collectionModel
+	if err := query.First(&cm, "id = ?", collectionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ErrCollectionNotFound
+		}
+		return fmt.Errorf("failed to lock collection: %w", err)
+	}
+	return nil
+}
+
+func (r *CollectionRepository) GetByID(id int, viewerID *int) (*models.Collection, error) {
+	logging.L.Debug("querying collection by ID", "repository", "CollectionRepository", "method", "GetByID", "collection_id", id)
+	var cm collectionModel
+	if err := r.db.First(&cm, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrCollectionNotFound
+		}
+		logging.L.Error("failed to query collection", "repository", "CollectionRepository", "method", "GetByID", "collection_id", id, "error", err)
+		return nil, fmt.Errorf("failed to query collection by id=%d: %w", id, err)
+	}
+	collection := r.toDomain(&cm)
+	counts, err := r.itemCountsByCollectionID([]int64{cm.ID}, viewerID)
+	if err != nil {
+		logging.L.Error("failed to fetch collection item count", "repository", "CollectionRepository", "method", "GetByID", "collection_id", id, "error", err)
+		return nil, err
+	}
+	collection.ItemCount = counts[cm.ID]
+	return &collection, nil
+}
+
+func (r *CollectionRepository) ListByUserID(userID int, includePrivate bool, viewerID *int) ([]models.Collection, error) {
+	logging.L.Debug("querying collections by user ID", "repository", "CollectionRepository", "method", "ListByUserID", "user_id", userID, "include_private", includePrivate)
+	query := r.db.Where("user_id = ?", userID)
+	if !includePrivate {
+		query = query.Where("is_public = ?", true)
+	}
+	var cms []collectionModel
+	if err := query.Order("created_at DESC, id DESC").Find(&cms).Error; err != nil {
+		logging.L.Error("failed to query collections", "repository", "CollectionRepository", "method", "ListByUserID", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query collections by user_id=%d: %w", userID, err)
+	}
+
+	ids := make([]int64, 0, len(cms))
+	for i := range cms {
+		ids = append(ids, cms[i].ID)
+	}
+	counts, err := r.itemCountsByCollectionID(ids, viewerID)
+	if err != nil {
+		logging.L.Error("failed to fetch collection item counts", "repository", "CollectionRepository", "method", "ListByUserID", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	collections := make([]models.Collection, 0, len(cms))
+	for i := range cms {
+		collection := r.toDomain(&cms[i])
+		collection.ItemCount = counts[cms[i].ID]
+		collections = append(collections, collection)
+	}
+	return collections, nil
+}
+
+func (r *CollectionRepository) Update(id int, input models.UpdateCollectionInput, viewerID *int) (*models.Collection, error) {
+	logging.L.Debug("updating collection", "repository", "CollectionRepository", "method", "Update", "collection_id", id)
+	updates := map[string]interface{}{}
+	if input.Name != nil {
+		updates["name"] = *input.Name
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.IsPublic != nil {
+		updates["is_public"] = *input.IsPublic
+	}
+	if len(updates) > 0 {
+		updates["updated_at"] = r.db.NowFunc()
+		result := r.db.Model(&collectionModel{}).Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			logging.L.Error("failed to update collection", "repository", "CollectionRepository", "method", "Update", "collection_id", id, "error", result.Error)
+			return nil, fmt.Errorf("failed to update collection id=%d: %w", id, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil, repositories.ErrCollectionNotFound
+		}
+		logging.L.Info("collection updated", "repository", "CollectionRepository", "method", "Update", "collection_id", id)
+	}
+	return r.GetByID(id, viewerID)
+}
+
+func (r *CollectionRepository) Delete(id int) error {
+	logging.L.Debug("deleting collection", "repository", "CollectionRepository", "method", "Delete", "collection_id", id)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// ON DELETE CASCADE に頼らず収録情報も明示的に削除する（外部キーが無効な環境でも孤立行を残さない）
+		if err := tx.Where("collection_id = ?", id).Delete(&collectionItemModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete collection items: %w", err)
+		}
+		result := tx.Where("id = ?", id).Delete(&collectionModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete collection id=%d: %w", id, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrCollectionNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrCollectionNotFound) {
+			return repositories.ErrCollectionNotFound
+		}
+		logging.L.Error("failed to delete collection", "repository", "CollectionRepository", "method", "Delete", "collection_id", id, "error", err)
+		return err
+	}
+	logging.L.Info("collection deleted", "repository", "CollectionRepository", "method", "Delete", "collection_id", id)
+	return nil
+}
+
+func (r *CollectionRepository) ListPostIDs(collectionID int) ([]int, error) {
+	var postIDs []int
+	if err := r.visibleItems(r.db).
+		Where("collection_items.collection_id = ?", collectionID).
+		Order("collection_items.position ASC, collection_items.created_at ASC").
+		Pluck("collection_items.post_id", &postIDs).Error; err != nil {
+		logging.L.Error("failed to query collection items", "repository", "CollectionRepository", "method", "ListPostIDs", "collection_id", collectionID, "error", err)
+		return nil, fmt.Errorf("failed to query items of collection id=%d: %w", collectionID, err)
+	}
+	if postIDs == nil {
+		postIDs = []int{}
+	}
+	return postIDs, nil
+}
+
+func (r *CollectionRepository) AddItem(collectionID, postID int) error {
+	logging.L.Debug("adding collection item", "repository", "CollectionRepository", "method", "AddItem", "collection_id", collectionID, "post_id", postID)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockCollection(tx, collectionID); err != nil {
+			return err
+		}
+		var maxPosition *int
+		if err := tx.Model(&collectionItemModel{}).
+			Where("collection_id = ?", collectionID).
+			Select("MAX(position)").
+			Scan(&maxPosition).Error; err != nil {
+			return fmt.Errorf("failed to query max position: %w", err)
+		}
+		position := 0
+		if maxPosition != nil {
+			position = *maxPosition + 1
+		}
+		item := collectionItemModel{CollectionID: int64(collectionID), PostID: int64(postID), Position: position}
+		if err := tx.Create(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return repositories.ErrAlreadyInCollection
+			}
+			if errors.Is(err, gorm.ErrForeignKeyViolated) {
+				return repositories.ErrPostNotFound
+			}
+			return fmt.Errorf("failed to insert collection item: %w", err)
+		}
+		return tx.Model(&collectionModel{}).Where("id = ?", collectionID).
+			UpdateColumn("updated_at", tx.NowFunc()).Error
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrAlreadyInCollection) || errors.Is(err, repositories.ErrPostNotFound) || errors.Is(err, repositories.ErrCollectionNotFound) {
+			return err
+		}
+		logging.L.Error("failed to add collection item", "repository", "CollectionRepository", "method", "AddItem", "collection_id", collectionID, "post_id", postID, "error", err)
+		return err
+	}
+	logging.L.Info("collection item added", "repository", "CollectionRepository", "method", "AddItem", "collection_id", collectionID, "post_id", postID)
+	return nil
+}
+
+func (r *CollectionRepository) RemoveItem(collectionID, postID int) error {
+	logging.L.Debug("removing collection item", "repository", "CollectionRepository", "method", "RemoveItem", "collection_id", collectionID, "post_id", postID)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("collection_id = ? AND post_id = ?", collectionID, postID).Delete(&collectionItemModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete collection item: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrNotInCollection
+		}
+		return tx.Model(&collectionModel{}).Where("id = ?", collectionID).
+			UpdateColumn("updated_at", tx.NowFunc()).Error
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotInCollection) {
+			return repositories.ErrNotInCollection
+		}
+		logging.L.Error("failed to remove collection item", "repository", "CollectionRepository", "method", "RemoveItem", "collection_id", collectionID, "post_id", postID, "error", err)
+		return err
+	}
+	logging.L.Info("collection item removed", "repository", "CollectionRepository", "method", "RemoveItem", "collection_id", collectionID, "post_id", postID)
+	return nil
+}
+
+func (r *CollectionRepository) ReorderItems(collectionID int, postIDs []int) error {
+	logging.L.Debug("reordering collection items", "repository", "CollectionRepository", "method", "ReorderItems", "collection_id", collectionID, "count", len(postIDs))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockCollection(tx, collectionID); err != nil {
+			return err
+		}
+		var current []int
+		if err := r.visibleItems(tx).
+			Where("collection_items.collection_id = ?", collectionID).
+			Pluck("collection_items.post_id", &current).Error; err != nil {
+			return fmt.Errorf("failed to query collection items: %w", err)
+		}
+		if len(current) != len(postIDs) {
+			return repositories.ErrCollectionItemsMismatch
+		}
+		remaining := make(map[int]struct{}, len(current))
+		for _, id := range current {
+			remaining[id] = struct{}{}
+		}
+		for _, id := range postIDs {
+			// 未収録の投稿ID・重複指定はいずれも不一致として扱う
+			if _, ok := remaining[id]; !ok {
+				return repositories.ErrCollectionItemsMismatch
+			}
+			delete(remaining, id)
+		}
+
+		for i, id := range postIDs {
+			if err := tx.Model(&collectionItemModel{}).
+				Where("collection_id = ? AND post_id = ?", collectionID, id).
+				UpdateColumn("position", i).Error; err != nil {
+				return fmt.Errorf("failed to update position of post id=%d: %w", id, err)
+			}
+		}
+		return tx.Model(&collectionModel{}).Where("id = ?", collectionID).
+			UpdateColumn("updated_at", tx.NowFunc()).Error
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrCollectionItemsMismatch) || errors.Is(err, repositories.ErrCollectionNotFound) {
+			return err
+		}
+		logging.L.Error("failed to reorder collection items", "repository", "CollectionRepository", "method", "ReorderItems", "collection_id", collectionID, "error", err)
+		return err
+	}
+	logging.L.Info("collection items reordered", "repository", "CollectionRepository", "method", "ReorderItems", "collection_id", collectionID)
+	return nil
+}
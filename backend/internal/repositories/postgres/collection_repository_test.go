@@ -0,0 +1,343 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// setupCollectionWithPosts はユーザー1・投稿3件・空のコレクションを作成し、コレクションIDと投稿IDを返す
+func setupCollectionWithPosts(t *testing.T, db *gorm.DB) (collectionID int, postIDs []int) {
+	t.Helper()
+	_, firstID := setupPostAndUser(t, db)
+	postIDs = []int{firstID}
+	postRepo := NewPostRepository(db)
+	for i := 0; i < 2; i++ {
+		p := &models.Post{UserID: 1, Slides: []models.Slide{{ImageURL: "/img.jpg", Text: "t"}}}
+		if err := postRepo.Create(p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		postIDs = append(postIDs, p.ID)
+	}
+	collection := &models.Collection{UserID: 1, Name: "お気に入り", IsPublic: true}
+	if err := NewCollectionRepository(db).Create(collection); err != nil {
+		t.Fatalf("failed to create collection: %v", err)
+	}
+	return collection.ID, postIDs
+}
+
+func TestCollection_AddItemAppendsInOrder(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+
+	for _, id := range []int{postIDs[2], postIDs[0], postIDs[1]} {
+		if err := repo.AddItem(collectionID, id); err != nil {
+			t.Fatalf("AddItem failed: %v", err)
+		}
+	}
+
+	got, err := repo.ListPostIDs(collectionID)
+	if err != nil {
+		t.Fatalf("ListPostIDs failed: %v", err)
+	}
+	want := []int{postIDs[2], postIDs[0], postIDs[1]}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	collection, err := repo.GetByID(collectionID, nil)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if collection.ItemCount != 3 {
+		t.Fatalf("expected item_count=3, got %d", collection.ItemCount)
+	}
+}
+
+func TestCollection_AddItemDuplicate(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+
+	if err := repo.AddItem(collectionID, postIDs[0]); err != nil {
+		t.Fatalf("AddItem failed: %v", err)
+	}
+	if err := repo.AddItem(collectionID, postIDs[0]); !errors.Is(err, repositories.ErrAlreadyInCollection) {
+		t.Fatalf("expected ErrAlreadyInCollection, got %v", err)
+	}
+}
+
+func TestCollection_RemoveItem(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+
+	if err := repo.AddItem(collectionID, postIDs[0]); err != nil {
+		t.Fatalf("AddItem failed: %v", err)
+	}
+	if err := repo.RemoveItem(collectionID, postIDs[0]); err != nil {
+		t.Fatalf("RemoveItem failed: %v", err)
+	}
+	if err := repo.RemoveItem(collectionID, postIDs[0]); !errors.Is(err, repositories.ErrNotInCollection) {
+		t.Fatalf("expected ErrNotInCollection, got %v", err)
+	}
+}
+
+func TestCollection_ReorderItems(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+	for _, id := range postIDs {
+		if err := repo.AddItem(collectionID, id); err != nil {
+			t.Fatalf("AddItem failed: %v", err)
+		}
+	}
+
+	want := []int{postIDs[1], postIDs[2], postIDs[0]}
+	if err := repo.ReorderItems(collectionID, want); err != nil {
+		t.Fatalf("ReorderItems failed: %v", err)
+	}
+	got, err := repo.ListPostIDs(collectionID)
+	if err != nil {
+		t.Fatalf("ListPostIDs failed: %v", err)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	// 過不足・重複のある指定は不一致として拒否される
+	invalid := [][]int{
+		{postIDs[0], postIDs[1]},
+		{postIDs[0], postIDs[1], postIDs[1]},
+		{postIDs[0], postIDs[1], 9999},
+	}
+	for _, ids := range invalid {
+		if err := repo.ReorderItems(collectionID, ids); !errors.Is(err, repositories.ErrCollectionItemsMismatch) {
+			t.Fatalf("expected ErrCollectionItemsMismatch for %v, got %v", ids, err)
+		}
+	}
+}
+
+func TestCollection_DeletedPostsAreHidden(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+	for _, id := range postIDs {
+		if err := repo.AddItem(collectionID, id); err != nil {
+			t.Fatalf("AddItem failed: %v", err)
+		}
+	}
+
+	if err := NewPostRepository(db).DeletePost(1, postIDs[1]); err != nil {
+		t.Fatalf("DeletePost failed: %v", err)
+	}
+
+	got, err := repo.ListPostIDs(collectionID)
+	if err != nil {
+		t.Fatalf("ListPostIDs failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != postIDs[0] || got[1] != postIDs[2] {
+		t.Fatalf("expected deleted post to be excluded, got %v", got)
+	}
+	collection, err := repo.GetByID(collectionID, nil)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if collection.ItemCount != 2 {
+		t.Fatalf("expected item_count=2, got %d", collection.ItemCount)
+	}
+
+	// 削除済み投稿は並び替えの対象に含めない
+	if err := repo.ReorderItems(collectionID, []int{postIDs[2], postIDs[0]}); err != nil {
+		t.Fatalf("ReorderItems failed: %v", err)
+	}
+}
+
+func TestCollection_HiddenPostsAreExcluded(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+	for _, id := range postIDs {
+		if err := repo.AddItem(collectionID, id); err != nil {
+			t.Fatalf("AddItem failed: %v", err)
+		}
+	}
+
+	// 管理者が非表示にした投稿
+	if err := db.Model(&postModel{}).Where("id = ?", postIDs[0]).UpdateColumn("hidden_at", time.Now()).Error; err != nil {
+		t.Fatalf("failed to hide post: %v", err)
+	}
+
+	got, err := repo.ListPostIDs(collectionID)
+	if err != nil {
+		t.Fatalf("ListPostIDs failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != postIDs[1] || got[1] != postIDs[2] {
+		t.Fatalf("expected hidden post to be excluded, got %v", got)
+	}
+	collection, err := repo.GetByID(collectionID, nil)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if collection.ItemCount != 2 {
+		t.Fatalf("expected item_count=2, got %d", collection.ItemCount)
+	}
+}
+
+func TestCollection_ItemCountExcludesPostsHiddenFromViewer(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+	for _, id := range postIDs {
+		if err := repo.AddItem(collectionID, id); err != nil {
+			t.Fatalf("AddItem failed: %v", err)
+		}
+	}
+	// 閲覧者2は投稿者1をブロックし、閲覧者3はキーワードのミュートルールで1件だけ除外する
+	for _, u := range []userModel{{ID: 2, Email: "viewer2@example.com"}, {ID: 3, Email: "viewer3@example.com"}} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	if err := NewUserRelationRepository(db).Block(2, 1); err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+	if err := db.Model(&slideModel{}).Where("post_id = ?", postIDs[0]).UpdateColumn("text", "ミント強め").Error; err != nil {
+		t.Fatalf("failed to update slide: %v", err)
+	}
+	if err := db.Create(&muteRuleModel{UserID: 3, Kind: "keyword", Keyword: "ミント"}).Error; err != nil {
+		t.Fatalf("failed to create mute rule: %v", err)
+	}
+
+	viewer2, viewer3 := 2, 3
+	for _, tt := range []struct {
+		name     string
+		viewerID *int
+		want     int
+	}{
+		{"未ログイン", nil, 3},
+		{"投稿者をブロックした閲覧者", &viewer2, 0},
+		{"ミュートルールに一致する投稿がある閲覧者", &viewer3, 2},
+	} {
+		collection, err := repo.GetByID(collectionID, tt.viewerID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		collections, err := repo.ListByUserID(1, false, tt.viewerID)
+		if err != nil {
+			t.Fatalf("ListByUserID failed: %v", err)
+		}
+		posts, err := NewPostRepository(db).GetByIDs(postIDs, tt.viewerID)
+		if err != nil {
+			t.Fatalf("GetByIDs failed: %v", err)
+		}
+		// 件数は収録投稿の取得と同じ閲覧者の条件で数える
+		if collection.ItemCount != tt.want || collections[0].ItemCount != tt.want || len(posts) != tt.want {
+			t.Errorf("%s: expected %d items, got GetByID=%d ListByUserID=%d GetByIDs=%d", tt.name, tt.want, collection.ItemCount, collections[0].ItemCount, len(posts))
+		}
+	}
+}
+
+func TestCollection_AddItemToMissingCollection(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	_, postIDs := setupCollectionWithPosts(t, db)
+
+	if err := repo.AddItem(999, postIDs[0]); !errors.Is(err, repositories.ErrCollectionNotFound) {
+		t.Fatalf("expected ErrCollectionNotFound, got %v", err)
+	}
+}
+
+func TestCollection_ListByUserIDVisibility(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	for _, c := range []*models.Collection{
+		{UserID: 1, Name: "公開", IsPublic: true},
+		{UserID: 1, Name: "非公開", IsPublic: false},
+	} {
+		if err := repo.Create(c); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	public, err := repo.ListByUserID(1, false, nil)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if len(public) != 1 || public[0].Name != "公開" {
+		t.Fatalf("expected only public collection, got %+v", public)
+	}
+	all, err := repo.ListByUserID(1, true, nil)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 collections, got %+v", all)
+	}
+}
+
+func TestCollection_UpdateAndDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCollectionRepository(db)
+	collectionID, postIDs := setupCollectionWithPosts(t, db)
+	if err := repo.AddItem(collectionID, postIDs[0]); err != nil {
+		t.Fatalf("AddItem failed: %v", err)
+	}
+
+	name := "名前変更"
+	isPublic := false
+	updated, err := repo.Update(collectionID, models.UpdateCollectionInput{Name: &name, IsPublic: &isPublic}, nil)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.Name != name || updated.IsPublic || updated.ItemCount != 1 {
+		t.Fatalf("unexpected updated collection: %+v", updated)
+	}
+
+	if err := repo.Delete(collectionID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.GetByID(collectionID, nil); !errors.Is(err, repositories.ErrCollectionNotFound) {
+		t.Fatalf("expected ErrCollectionNotFound, got %v", err)
+	}
+	if err := repo.Delete(collectionID); !errors.Is(err, repositories.ErrCollectionNotFound) {
+		t.Fatalf("expected ErrCollectionNotFound on second delete, got %v", err)
+	}
+	// 投稿自体は削除されない
+	if _, err := NewPostRepository(db).GetByID(postIDs[0], nil); err != nil {
+		t.Fatalf("expected post to remain, got %v", err)
+	}
+}
+
+func TestGetByIDs_PreservesOrderAndSkipsDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	_, postIDs := setupCollectionWithPosts(t, db)
+	postRepo := NewPostRepository(db)
+	if err := postRepo.DeletePost(1, postIDs[1]); err != nil {
+		t.Fatalf("DeletePost failed: %v", err)
+	}
+
+	posts, err := postRepo.GetByIDs([]int{postIDs[2], postIDs[1], postIDs[0], 9999}, nil)
+	if err != nil {
+		t.Fatalf("GetByIDs failed: %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != postIDs[2] || posts[1].ID != postIDs[0] {
+		t.Fatalf("unexpected posts: %+v", posts)
+	}
+}
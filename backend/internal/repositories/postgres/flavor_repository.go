@@ -22,11 +22,15 @@ func (r *FlavorRepository) toDomain(fm *flavorModel) models.Flavor {
 	if fm == nil {
 		return models.Flavor{}
 	}
-	return models.Flavor{
+	flavor := models.Flavor{
 		ID:    int(fm.ID),
 		Name:  fm.Name,
 		Color: fm.Color,
 	}
+	if fm.Category != nil {
+		flavor.Category = *fm.Category
+	}
+	return flavor
 }
 
 func (r *FlavorRepository) GetByID(id int) (*models.Flavor, error) {
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type LoginFailureRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginFailureRepository(db *gorm.DB) *LoginFailureRepository {
+	return &LoginFailureRepository{db: db}
+}
+
+func (r *LoginFailureRepository) Get(userID int) (*models.LoginFailure, error) {
+	var fm loginFailureModel
+	if err := r.db.First(&fm, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrLoginFailureNotFound
+		}
+		logging.L.Error("failed to query login failure", "repository", "LoginFailureRepository", "method", "Get", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query login failure of user id=%d: %w", userID, err)
+	}
+	return toLoginFailure(&fm), nil
+}
+
+func (r *LoginFailureRepository) Reserve(userID int, at, since time.Time, delay func(failedCount int) time.Duration) (*models.LoginFailure, error) {
+	var fm loginFailureModel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 待ち時間中・ロック中は更新しない。更新した行はコミットまでロックされるため、同時の試行は次の試行を受け付ける日時の更新後に判定される
+		// 期限が過ぎたロックが残っている場合は数え直し、1回の失敗ですぐにロックし直さないようにする
+		result := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failed_count":   gorm.Expr("CASE WHEN login_failures.last_failed_at < ? OR login_failures.locked_until IS NOT NULL THEN 1 ELSE login_failures.failed_count + 1 END", since),
+				"last_failed_at": at,
+				"locked_until":   nil,
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				gorm.Expr("login_failures.next_attempt_at <= ? AND (login_failures.locked_until IS NULL OR login_failures.locked_until <= ?)", at, at),
+			}},
+		}).Create(&loginFailureModel{UserID: int64(userID), FailedCount: 1, LastFailedAt: at, NextAttemptAt: at})
+		if result.Error != nil {
+			return fmt.Errorf("failed to upsert login failure: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrLoginAttemptThrottled
+		}
+		if err := tx.First(&fm, "user_id = ?", userID).Error; err != nil {
+			return fmt.Errorf("failed to query login failure: %w", err)
+		}
+		fm.NextAttemptAt = at.Add(delay(fm.FailedCount))
+		return tx.Model(&loginFailureModel{}).Where("user_id = ?", userID).Update("next_attempt_at", fm.NextAttemptAt).Error
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrLoginAttemptThrottled) {
+			return nil, err
+		}
+		logging.L.Error("failed to reserve login attempt", "repository", "LoginFailureRepository", "method", "Reserve", "user_id", userID, "error", err)
+		return nil, err
+	}
+	return toLoginFailure(&fm), nil
+}
+
+func (r *LoginFailureRepository) Lock(userID int, until time.Time) error {
+	if err := r.db.Model(&loginFailureModel{}).Where("user_id = ?", userID).Update("locked_until", until).Error; err != nil {
+		logging.L.Error("failed to lock account", "repository", "LoginFailureRepository", "method", "Lock", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to lock account of user id=%d: %w", userID, err)
+	}
+	logging.L.Info("account locked", "repository", "LoginFailureRepository", "method", "Lock", "user_id", userID, "locked_until", until)
+	return nil
+}
+
+func (r *LoginFailureRepository) Delete(userID int) error {
+	if err := r.db.Where("user_id = ?", userID).Delete(&loginFailureModel{}).Error; err != nil {
+		logging.L.Error("failed to delete login failure", "repository", "LoginFailureRepository", "method", "Delete", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to delete login failure of user id=%d: %w", userID, err)
+	}
+	return nil
+}
+
+func toLoginFailure(fm *loginFailureModel) *models.LoginFailure {
+	return &models.LoginFailure{
+		UserID:        int(fm.UserID),
+		FailedCount:   fm.FailedCount,
+		LastFailedAt:  fm.LastFailedAt,
+		NextAttemptAt: fm.NextAttemptAt,
+		LockedUntil:   fm.LockedUntil,
+	}
+}
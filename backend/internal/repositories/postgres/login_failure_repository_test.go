@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestLoginFailureRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewLoginFailureRepository(db)
+	for _, u := range []userModel{{ID: 1, Email: "lock1@example.com"}, {ID: 2, Email: "lock2@example.com"}} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := repo.Get(1); !errors.Is(err, repositories.ErrLoginFailureNotFound) {
+		t.Fatalf("expected ErrLoginFailureNotFound, got %v", err)
+	}
+
+	t.Run("正常系: 試行を数え、待ち時間中は数えずに拒否し、期間より前の試行は数え直す", func(t *testing.T) {
+		delay := func(failedCount int) time.Duration { return time.Duration(failedCount) * time.Minute }
+		for i := 1; i <= 3; i++ {
+			at := now.Add(time.Duration(i*i) * time.Minute)
+			failure, err := repo.Reserve(1, at, now.Add(-time.Hour), delay)
+			if err != nil {
+				t.Fatalf("Reserve failed: %v", err)
+			}
+			if failure.FailedCount != i || !failure.LastFailedAt.Equal(at) || !failure.NextAttemptAt.Equal(at.Add(time.Duration(i)*time.Minute)) {
+				t.Fatalf("unexpected failure: %+v", failure)
+			}
+		}
+		// 3回目（9分）の後は12分まで受け付けない
+		if _, err := repo.Reserve(1, now.Add(11*time.Minute), now.Add(-time.Hour), delay); !errors.Is(err, repositories.ErrLoginAttemptThrottled) {
+			t.Fatalf("expected ErrLoginAttemptThrottled, got %v", err)
+		}
+		if failure, err := repo.Get(1); err != nil || failure.FailedCount != 3 {
+			t.Fatalf("expected throttled attempt not to be counted, got %+v (err=%v)", failure, err)
+		}
+		failure, err := repo.Reserve(1, now.Add(3*time.Hour), now.Add(2*time.Hour), delay)
+		if err != nil || failure.FailedCount != 1 {
+			t.Errorf("expected count to restart, got %+v (err=%v)", failure, err)
+		}
+		if other, err := repo.Reserve(2, now, now.Add(-time.Hour), delay); err != nil || other.FailedCount != 1 {
+			t.Errorf("expected independent count, got %+v (err=%v)", other, err)
+		}
+	})
+
+	t.Run("正常系: ロックし、削除するとロックも解除される", func(t *testing.T) {
+		until := now.Add(5 * time.Hour)
+		if err := repo.Lock(1, until); err != nil {
+			t.Fatalf("Lock failed: %v", err)
+		}
+		failure, err := repo.Get(1)
+		if err != nil || failure.LockedUntil == nil || !failure.LockedUntil.Equal(until) {
+			t.Fatalf("expected locked, got %+v (err=%v)", failure, err)
+		}
+		if _, err := repo.Reserve(1, until.Add(-time.Second), now, func(int) time.Duration { return 0 }); !errors.Is(err, repositories.ErrLoginAttemptThrottled) {
+			t.Fatalf("expected ErrLoginAttemptThrottled while locked, got %v", err)
+		}
+		// 期限が過ぎたロックは解除し、1回目から数え直す
+		failure, err = repo.Reserve(1, until, now, func(int) time.Duration { return 0 })
+		if err != nil || failure.FailedCount != 1 || failure.LockedUntil != nil {
+			t.Fatalf("expected count to restart after the lock expired, got %+v (err=%v)", failure, err)
+		}
+
+		if err := repo.Delete(1); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := repo.Get(1); !errors.Is(err, repositories.ErrLoginFailureNotFound) {
+			t.Errorf("expected ErrLoginFailureNotFound, got %v", err)
+		}
+		if err := repo.Delete(1); err != nil {
+			t.Errorf("expected no error for missing record, got %v", err)
+		}
+		if _, err := repo.Get(2); err != nil {
+			t.Errorf("expected other user's record to remain, got %v", err)
+		}
+	})
+}
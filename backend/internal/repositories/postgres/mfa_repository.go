@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type MFARepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+func (r *MFARepository) GetTOTP(userID int) (*models.TOTP, error) {
+	var tm userTOTPModel
+	if err := r.db.First(&tm, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrTOTPNotFound
+		}
+		logging.L.Error("failed to query totp", "repository", "MFARepository", "method", "GetTOTP", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query totp of user id=%d: %w", userID, err)
+	}
+	return &models.TOTP{
+		UserID:          int(tm.UserID),
+		Secret:          tm.Secret,
+		EnabledAt:       tm.EnabledAt,
+		LastUsedCounter: tm.LastUsedCounter,
+	}, nil
+}
+
+func (r *MFARepository) SaveTOTPSecret(userID int, secret string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND enabled_at IS NULL", userID).Delete(&userTOTPModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete pending totp: %w", err)
+		}
+		if err := tx.Create(&userTOTPModel{UserID: int64(userID), Secret: secret, CreatedAt: tx.NowFunc()}).Error; err != nil {
+			return fmt.Errorf("failed to save totp secret: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logging.L.Error("failed to save totp secret", "repository", "MFARepository", "method", "SaveTOTPSecret", "user_id", userID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MFARepository) EnableTOTP(userID int, counter int64, recoveryCodes []string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&userTOTPModel{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Updates(map[string]interface{}{"enabled_at": tx.NowFunc(), "last_used_counter": counter})
+		if result.Error != nil {
+			return fmt.Errorf("failed to enable totp: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrTOTPNotFound
+		}
+		return replaceRecoveryCodes(tx, userID, recoveryCodes)
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrTOTPNotFound) {
+			return err
+		}
+		logging.L.Error("failed to enable totp", "repository", "MFARepository", "method", "EnableTOTP", "user_id", userID, "error", err)
+		return err
+	}
+	logging.L.Info("totp enabled", "repository", "MFARepository", "method", "EnableTOTP", "user_id", userID)
+	return nil
+}
+
+func (r *MFARepository) UseTOTPCounter(userID int, counter int64) error {
+	// 同じコードでの同時リクエストは、先に受け付けた方だけを成功させる
+	result := r.db.Model(&userTOTPModel{}).
+		Where("user_id = ? AND enabled_at IS NOT NULL AND last_used_counter < ?", userID, counter).
+		Update("last_used_counter", counter)
+	if result.Error != nil {
+		logging.L.Error("failed to update totp counter", "repository", "MFARepository", "method", "UseTOTPCounter", "user_id", userID, "error", result.Error)
+		return fmt.Errorf("failed to update totp counter of user id=%d: %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrTOTPCodeReused
+	}
+	return nil
+}
+
+func (r *MFARepository) ReplaceRecoveryCodes(userID int, recoveryCodes []string) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, recoveryCodes)
+	}); err != nil {
+		logging.L.Error("failed to replace recovery codes", "repository", "MFARepository", "method", "ReplaceRecoveryCodes", "user_id", userID, "error", err)
+		return err
+	}
+	logging.L.Info("recovery codes replaced", "repository", "MFARepository", "method", "ReplaceRecoveryCodes", "user_id", userID)
+	return nil
+}
+
+func (r *MFARepository) ConsumeRecoveryCode(userID int, recoveryCode string) error {
+	result := r.db.Model(&mfaRecoveryCodeModel{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(recoveryCode)).
+		Update("used_at", r.db.NowFunc())
+	if result.Error != nil {
+		logging.L.Error("failed to consume recovery code", "repository", "MFARepository", "method", "ConsumeRecoveryCode", "user_id", userID, "error", result.Error)
+		return fmt.Errorf("failed to consume recovery code of user id=%d: %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrRecoveryCodeNotFound
+	}
+	logging.L.Info("recovery code consumed", "repository", "MFARepository", "method", "ConsumeRecoveryCode", "user_id", userID)
+	return nil
+}
+
+func (r *MFARepository) CountRecoveryCodes(userID int) (int, error) {
+	var count int64
+	if err := r.db.Model(&mfaRecoveryCodeModel{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error; err != nil {
+		logging.L.Error("failed to count recovery codes", "repository", "MFARepository", "method", "CountRecoveryCodes", "user_id", userID, "error", err)
+		return 0, fmt.Errorf("failed to count recovery codes of user id=%d: %w", userID, err)
+	}
+	return int(count), nil
+}
+
+func (r *MFARepository) DeleteTOTP(userID int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&mfaRecoveryCodeModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&userTOTPModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete totp: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logging.L.Error("failed to delete totp", "repository", "MFARepository", "method", "DeleteTOTP", "user_id", userID, "error", err)
+		return err
+	}
+	logging.L.Info("totp deleted", "repository", "MFARepository", "method", "DeleteTOTP", "user_id", userID)
+	return nil
+}
+
+// replaceRecoveryCodes はトランザクション内でユーザーのリカバリーコードをハッシュにして置き換える
+func replaceRecoveryCodes(tx *gorm.DB, userID int, recoveryCodes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&mfaRecoveryCodeModel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if len(recoveryCodes) == 0 {
+		return nil
+	}
+	now := tx.NowFunc()
+	rows := make([]mfaRecoveryCodeModel, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		rows = append(rows, mfaRecoveryCodeModel{UserID: int64(userID), CodeHash: hashToken(code), CreatedAt: now})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return fmt.Errorf("failed to create recovery codes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestMFA_TOTPLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMFARepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "mfa@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	if _, err := repo.GetTOTP(1); !errors.Is(err, repositories.ErrTOTPNotFound) {
+		t.Fatalf("expected ErrTOTPNotFound, got %v", err)
+	}
+	if err := repo.EnableTOTP(1, 10, nil); !errors.Is(err, repositories.ErrTOTPNotFound) {
+		t.Fatalf("expected ErrTOTPNotFound before enrollment, got %v", err)
+	}
+
+	// 登録中のシークレットは登録をやり直すと置き換わる
+	for _, secret := range []string{"FIRSTSECRET", "SECONDSECRET"} {
+		if err := repo.SaveTOTPSecret(1, secret); err != nil {
+			t.Fatalf("SaveTOTPSecret failed: %v", err)
+		}
+	}
+	pending, err := repo.GetTOTP(1)
+	if err != nil || pending.Secret != "SECONDSECRET" || pending.EnabledAt != nil {
+		t.Fatalf("unexpected pending totp: %+v (err=%v)", pending, err)
+	}
+
+	if err := repo.EnableTOTP(1, 100, []string{"CODEA", "CODEB"}); err != nil {
+		t.Fatalf("EnableTOTP failed: %v", err)
+	}
+	enabled, err := repo.GetTOTP(1)
+	if err != nil || enabled.EnabledAt == nil || enabled.LastUsedCounter != 100 {
+		t.Fatalf("unexpected enabled totp: %+v (err=%v)", enabled, err)
+	}
+	// 有効な設定は登録中のシークレットで上書きできない
+	if err := repo.SaveTOTPSecret(1, "THIRDSECRET"); err == nil {
+		t.Fatal("expected error when overwriting enabled totp")
+	}
+
+	t.Run("時間ステップは前に進む場合だけ受け付ける", func(t *testing.T) {
+		for _, tt := range []struct {
+			counter int64
+			want    error
+		}{
+			{100, repositories.ErrTOTPCodeReused},
+			{99, repositories.ErrTOTPCodeReused},
+			{101, nil},
+			{101, repositories.ErrTOTPCodeReused},
+		} {
+			if err := repo.UseTOTPCounter(1, tt.counter); !errors.Is(err, tt.want) {
+				t.Errorf("counter %d: expected %v, got %v", tt.counter, tt.want, err)
+			}
+		}
+	})
+
+	t.Run("リカバリーコードはハッシュで保存し一度だけ使える", func(t *testing.T) {
+		var stored []mfaRecoveryCodeModel
+		if err := db.Where("user_id = ?", 1).Order("id").Find(&stored).Error; err != nil {
+			t.Fatalf("failed to query recovery codes: %v", err)
+		}
+		if len(stored) != 2 || stored[0].CodeHash != hashToken("CODEA") {
+			t.Fatalf("expected hashed recovery codes, got %+v", stored)
+		}
+		if err := repo.ConsumeRecoveryCode(1, "CODEA"); err != nil {
+			t.Fatalf("ConsumeRecoveryCode failed: %v", err)
+		}
+		if err := repo.ConsumeRecoveryCode(1, "CODEA"); !errors.Is(err, repositories.ErrRecoveryCodeNotFound) {
+			t.Fatalf("expected ErrRecoveryCodeNotFound, got %v", err)
+		}
+		if count, err := repo.CountRecoveryCodes(1); err != nil || count != 1 {
+			t.Fatalf("expected 1 remaining code, got %d (err=%v)", count, err)
+		}
+
+		if err := repo.ReplaceRecoveryCodes(1, []string{"CODEC"}); err != nil {
+			t.Fatalf("ReplaceRecoveryCodes failed: %v", err)
+		}
+		if err := repo.ConsumeRecoveryCode(1, "CODEB"); !errors.Is(err, repositories.ErrRecoveryCodeNotFound) {
+			t.Fatalf("expected replaced code to be rejected, got %v", err)
+		}
+		if count, _ := repo.CountRecoveryCodes(1); count != 1 {
+			t.Fatalf("expected 1 remaining code after replace, got %d", count)
+		}
+	})
+
+	if err := repo.DeleteTOTP(1); err != nil {
+		t.Fatalf("DeleteTOTP failed: %v", err)
+	}
+	if _, err := repo.GetTOTP(1); !errors.Is(err, repositories.ErrTOTPNotFound) {
+		t.Fatalf("expected ErrTOTPNotFound after delete, got %v", err)
+	}
+	if count, _ := repo.CountRecoveryCodes(1); count != 0 {
+		t.Fatalf("expected recovery codes to be deleted, got %d", count)
+	}
+}
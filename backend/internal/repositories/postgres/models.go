@@ -8,13 +8,20 @@ import (
 
 // userModel represents the users table
 type userModel struct {
-	ID           int64  `gorm:"primaryKey;column:id"`
-	Email        string `gorm:"column:email"`
-	PasswordHash string `gorm:"column:password_hash"`
-	DisplayName  string `gorm:"column:display_name"`
-	Description  string `gorm:"column:description"`
-	IconURL      string `gorm:"column:icon_url"`
-	ExternalURL  string `gorm:"column:external_url"`
+	ID           int64      `gorm:"primaryKey;column:id"`
+	Email        string     `gorm:"column:email"`
+	PasswordHash string     `gorm:"column:password_hash"`
+	DisplayName  string     `gorm:"column:display_name"`
+	Description  string     `gorm:"column:description"`
+	IconURL      string     `gorm:"column:icon_url"`
+	ExternalURL  string     `gorm:"column:external_url"`
+	SuspendedAt  *time.Time `gorm:"column:suspended_at"`
+	Role         string     `gorm:"column:role;default:user"`
+	// SuspensionReason・SuspendedUntil は利用停止の理由と終了日時（SuspendedUntil が nil の場合は無期限）
+	SuspensionReason string     `gorm:"column:suspension_reason"`
+	SuspendedUntil   *time.Time `gorm:"column:suspended_until"`
+	TokenVersion     int        `gorm:"column:token_version"`
+	EmailVerifiedAt  *time.Time `gorm:"column:email_verified_at"`
 }
 
 // TableName ensures GORM uses the existing `users` table
@@ -24,13 +31,16 @@ func (userModel) TableName() string {
 
 // postModel represents the posts table
 type postModel struct {
-	ID        int64          `gorm:"primaryKey;column:id"`
-	UserID    int64          `gorm:"column:user_id"`
-	Likes     int            `gorm:"column:likes"`
-	CreatedAt time.Time      `gorm:"column:created_at"`
-	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
-	User      *userModel     `gorm:"foreignKey:UserID"`
-	Slides    []slideModel   `gorm:"foreignKey:PostID"`
+	ID          int64             `gorm:"primaryKey;column:id"`
+	UserID      int64             `gorm:"column:user_id"`
+	Likes       int               `gorm:"column:likes"`
+	RemixedFrom *int64            `gorm:"column:remixed_from;index"`
+	CreatedAt   time.Time         `gorm:"column:created_at"`
+	DeletedAt   gorm.DeletedAt    `gorm:"column:deleted_at;index"`
+	HiddenAt    *time.Time        `gorm:"column:hidden_at"`
+	User        *userModel        `gorm:"foreignKey:UserID"`
+	Slides      []slideModel      `gorm:"foreignKey:PostID"`
+	Session     *postSessionModel `gorm:"foreignKey:PostID"`
 }
 
 // TableName ensures GORM uses the existing `posts` table
@@ -38,6 +48,23 @@ func (postModel) TableName() string {
 	return "posts"
 }
 
+// postSessionModel represents the post_sessions table (1 post : 0..1 session)
+type postSessionModel struct {
+	PostID          int64     `gorm:"primaryKey;column:post_id;autoIncrement:false"`
+	BowlType        *string   `gorm:"column:bowl_type"`
+	HeatManagement  *string   `gorm:"column:heat_management"`
+	CoalCount       *int      `gorm:"column:coal_count"`
+	DurationMinutes *int      `gorm:"column:duration_minutes"`
+	Rating          *int      `gorm:"column:rating"`
+	CreatedAt       time.Time `gorm:"column:created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at"`
+}
+
+// TableName ensures GORM uses the post_sessions table
+func (postSessionModel) TableName() string {
+	return "post_sessions"
+}
+
 // slideModel represents the slides table
 type slideModel struct {
 	ID         int64        `gorm:"primaryKey;column:id"`
@@ -57,9 +84,10 @@ func (slideModel) TableName() string {
 
 // flavorModel represents the flavors table
 type flavorModel struct {
-	ID    int64  `gorm:"primaryKey;column:id"`
-	Name  string `gorm:"column:name"`
-	Color string `gorm:"column:color"`
+	ID       int64   `gorm:"primaryKey;column:id"`
+	Name     string  `gorm:"column:name"`
+	Color    string  `gorm:"column:color"`
+	Category *string `gorm:"column:category"`
 }
 
 // TableName ensures GORM uses the existing `flavors` table
@@ -78,3 +106,319 @@ type postLikeModel struct {
 func (postLikeModel) TableName() string {
 	return "post_likes"
 }
+
+// userBadgeModel represents the user_badges table
+type userBadgeModel struct {
+	UserID    int64      `gorm:"primaryKey;column:user_id;autoIncrement:false"`
+	BadgeCode string     `gorm:"primaryKey;column:badge_code"`
+	AwardedAt time.Time  `gorm:"column:awarded_at"`
+	SeenAt    *time.Time `gorm:"column:seen_at"`
+}
+
+// TableName ensures GORM uses the user_badges table
+func (userBadgeModel) TableName() string {
+	return "user_badges"
+}
+
+// collectionModel represents the collections table
+type collectionModel struct {
+	ID          int64     `gorm:"primaryKey;column:id"`
+	UserID      int64     `gorm:"column:user_id"`
+	Name        string    `gorm:"column:name"`
+	Description string    `gorm:"column:description"`
+	IsPublic    bool      `gorm:"column:is_public"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+// TableName ensures GORM uses the collections table
+func (collectionModel) TableName() string {
+	return "collections"
+}
+
+// collectionItemModel represents the collection_items table
+type collectionItemModel struct {
+	CollectionID int64     `gorm:"primaryKey;column:collection_id;autoIncrement:false"`
+	PostID       int64     `gorm:"primaryKey;column:post_id;autoIncrement:false"`
+	Position     int       `gorm:"column:position"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the collection_items table
+func (collectionItemModel) TableName() string {
+	return "collection_items"
+}
+
+// notificationModel represents the notifications table
+type notificationModel struct {
+	ID         int64      `gorm:"primaryKey;column:id"`
+	UserID     int64      `gorm:"column:user_id"`
+	Type       string     `gorm:"column:type"`
+	PostID     *int64     `gorm:"column:post_id"`
+	ActorID    *int64     `gorm:"column:actor_id"`
+	ActorCount int        `gorm:"column:actor_count"`
+	Grouped    bool       `gorm:"column:grouped"`
+	ReadAt     *time.Time `gorm:"column:read_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+	Actor      *userModel `gorm:"foreignKey:ActorID"`
+}
+
+// TableName ensures GORM uses the notifications table
+func (notificationModel) TableName() string {
+	return "notifications"
+}
+
+// notificationActorModel represents the notification_actors table
+type notificationActorModel struct {
+	NotificationID int64     `gorm:"primaryKey;column:notification_id;autoIncrement:false"`
+	ActorID        int64     `gorm:"primaryKey;column:actor_id;autoIncrement:false"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the notification_actors table
+func (notificationActorModel) TableName() string {
+	return "notification_actors"
+}
+
+// webhookEndpointModel represents the webhook_endpoints table
+type webhookEndpointModel struct {
+	ID        int64     `gorm:"primaryKey;column:id"`
+	UserID    int64     `gorm:"column:user_id"`
+	URL       string    `gorm:"column:url"`
+	Secret    string    `gorm:"column:secret"`
+	Events    string    `gorm:"column:events"`
+	IsActive  bool      `gorm:"column:is_active"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// TableName ensures GORM uses the webhook_endpoints table
+func (webhookEndpointModel) TableName() string {
+	return "webhook_endpoints"
+}
+
+// webhookDeliveryModel represents the webhook_deliveries table
+type webhookDeliveryModel struct {
+	ID             int64      `gorm:"primaryKey;column:id"`
+	EndpointID     int64      `gorm:"column:endpoint_id"`
+	EventID        string     `gorm:"column:event_id"`
+	EventType      string     `gorm:"column:event_type"`
+	Payload        string     `gorm:"column:payload"`
+	Status         string     `gorm:"column:status"`
+	Attempts       int        `gorm:"column:attempts"`
+	NextAttemptAt  *time.Time `gorm:"column:next_attempt_at"`
+	LastAttemptAt  *time.Time `gorm:"column:last_attempt_at"`
+	ResponseStatus *int       `gorm:"column:response_status"`
+	LastError      string     `gorm:"column:last_error"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName ensures GORM uses the webhook_deliveries table
+func (webhookDeliveryModel) TableName() string {
+	return "webhook_deliveries"
+}
+
+// outboxEventModel represents the outbox_events table
+type outboxEventModel struct {
+	ID                int64      `gorm:"primaryKey;column:id"`
+	EventType         string     `gorm:"column:event_type"`
+	Payload           string     `gorm:"column:payload"`
+	Status            string     `gorm:"column:status"`
+	Attempts          int        `gorm:"column:attempts"`
+	CompletedHandlers string     `gorm:"column:completed_handlers"`
+	NextAttemptAt     *time.Time `gorm:"column:next_attempt_at"`
+	LastError         string     `gorm:"column:last_error"`
+	ProcessedAt       *time.Time `gorm:"column:processed_at"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName ensures GORM uses the outbox_events table
+func (outboxEventModel) TableName() string {
+	return "outbox_events"
+}
+
+// reportModel represents the reports table
+type reportModel struct {
+	ID           int64      `gorm:"primaryKey;column:id"`
+	ReporterID   int64      `gorm:"column:reporter_id"`
+	TargetType   string     `gorm:"column:target_type"`
+	PostID       *int64     `gorm:"column:post_id"`
+	TargetUserID int64      `gorm:"column:target_user_id"`
+	Reason       string     `gorm:"column:reason"`
+	Comment      string     `gorm:"column:comment"`
+	Status       string     `gorm:"column:status"`
+	Resolution   string     `gorm:"column:resolution"`
+	ResolvedBy   *int64     `gorm:"column:resolved_by"`
+	ResolvedAt   *time.Time `gorm:"column:resolved_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName ensures GORM uses the reports table
+func (reportModel) TableName() string {
+	return "reports"
+}
+
+// moderationActionModel represents the moderation_actions table
+type moderationActionModel struct {
+	ID           int64     `gorm:"primaryKey;column:id"`
+	ModeratorID  *int64    `gorm:"column:moderator_id"`
+	Action       string    `gorm:"column:action"`
+	ReportID     *int64    `gorm:"column:report_id"`
+	PostID       *int64    `gorm:"column:post_id"`
+	TargetUserID *int64    `gorm:"column:target_user_id"`
+	Note         string    `gorm:"column:note"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the moderation_actions table
+func (moderationActionModel) TableName() string {
+	return "moderation_actions"
+}
+
+// userBlockModel represents the user_blocks table
+type userBlockModel struct {
+	BlockerID int64     `gorm:"primaryKey;column:blocker_id"`
+	BlockedID int64     `gorm:"primaryKey;column:blocked_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the user_blocks table
+func (userBlockModel) TableName() string {
+	return "user_blocks"
+}
+
+// userMuteModel represents the user_mutes table
+type userMuteModel struct {
+	MuterID   int64     `gorm:"primaryKey;column:muter_id"`
+	MutedID   int64     `gorm:"primaryKey;column:muted_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the user_mutes table
+func (userMuteModel) TableName() string {
+	return "user_mutes"
+}
+
+// muteRuleModel represents the mute_rules table
+type muteRuleModel struct {
+	ID        int64      `gorm:"primaryKey;column:id"`
+	UserID    int64      `gorm:"column:user_id"`
+	Kind      string     `gorm:"column:kind"`
+	Keyword   string     `gorm:"column:keyword"`
+	FlavorID  *int64     `gorm:"column:flavor_id"`
+	ExpiresAt *time.Time `gorm:"column:expires_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	UpdatedAt time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName ensures GORM uses the mute_rules table
+func (muteRuleModel) TableName() string {
+	return "mute_rules"
+}
+
+// revokedAccessTokenModel represents the revoked_access_tokens table
+type revokedAccessTokenModel struct {
+	JTI       string    `gorm:"primaryKey;column:jti"`
+	UserID    int64     `gorm:"column:user_id"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+	RevokedAt time.Time `gorm:"column:revoked_at"`
+}
+
+// TableName ensures GORM uses the revoked_access_tokens table
+func (revokedAccessTokenModel) TableName() string {
+	return "revoked_access_tokens"
+}
+
+// passwordResetTokenModel represents the password_reset_tokens table
+type passwordResetTokenModel struct {
+	ID        int64      `gorm:"primaryKey;column:id"`
+	UserID    int64      `gorm:"column:user_id"`
+	TokenHash string     `gorm:"column:token_hash"`
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the password_reset_tokens table
+func (passwordResetTokenModel) TableName() string {
+	return "password_reset_tokens"
+}
+
+// userTOTPModel represents the user_totp table
+type userTOTPModel struct {
+	UserID          int64      `gorm:"primaryKey;autoIncrement:false;column:user_id"`
+	Secret          string     `gorm:"column:secret"`
+	EnabledAt       *time.Time `gorm:"column:enabled_at"`
+	LastUsedCounter int64      `gorm:"column:last_used_counter"`
+	CreatedAt       time.Time  `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the user_totp table
+func (userTOTPModel) TableName() string {
+	return "user_totp"
+}
+
+// mfaRecoveryCodeModel represents the mfa_recovery_codes table
+type mfaRecoveryCodeModel struct {
+	ID        int64      `gorm:"primaryKey;column:id"`
+	UserID    int64      `gorm:"column:user_id"`
+	CodeHash  string     `gorm:"column:code_hash"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the mfa_recovery_codes table
+func (mfaRecoveryCodeModel) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// passkeyCredentialModel represents the passkey_credentials table
+type passkeyCredentialModel struct {
+	ID           int64      `gorm:"primaryKey;column:id"`
+	UserID       int64      `gorm:"column:user_id;index"`
+	CredentialID []byte     `gorm:"column:credential_id;uniqueIndex"`
+	PublicKey    []byte     `gorm:"column:public_key"`
+	SignCount    int64      `gorm:"column:sign_count"`
+	Transports   string     `gorm:"column:transports"`
+	Name         string     `gorm:"column:name"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	LastUsedAt   *time.Time `gorm:"column:last_used_at"`
+}
+
+// TableName ensures GORM uses the passkey_credentials table
+func (passkeyCredentialModel) TableName() string {
+	return "passkey_credentials"
+}
+
+// userIdentityModel represents the user_identities table
+type userIdentityModel struct {
+	ID        int64     `gorm:"primaryKey;column:id"`
+	UserID    int64     `gorm:"column:user_id;index"`
+	Provider  string    `gorm:"column:provider;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string    `gorm:"column:subject;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string    `gorm:"column:email"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// TableName ensures GORM uses the user_identities table
+func (userIdentityModel) TableName() string {
+	return "user_identities"
+}
+
+// loginFailureModel represents the login_failures table
+type loginFailureModel struct {
+	UserID        int64      `gorm:"primaryKey;autoIncrement:false;column:user_id"`
+	FailedCount   int        `gorm:"column:failed_count"`
+	LastFailedAt  time.Time  `gorm:"column:last_failed_at"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+}
+
+// TableName ensures GORM uses the login_failures table
+func (loginFailureModel) TableName() string {
+	return "login_failures"
+}
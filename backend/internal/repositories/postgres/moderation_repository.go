@@ -0,0 +1,362 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type ModerationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+func intPtrFrom64(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func int64PtrFrom(v *int) *int64 {
+	if v == nil {
+		return nil
+	}
+	i := int64(*v)
+	return &i
+}
+
+func (r *ModerationRepository) toReport(rm *reportModel) models.Report {
+	return models.Report{
+		ID:           int(rm.ID),
+		ReporterID:   int(rm.ReporterID),
+		TargetType:   rm.TargetType,
+		PostID:       intPtrFrom64(rm.PostID),
+		TargetUserID: int(rm.TargetUserID),
+		Reason:       rm.Reason,
+		Comment:      rm.Comment,
+		Status:       rm.Status,
+		Resolution:   rm.Resolution,
+		ResolvedBy:   intPtrFrom64(rm.ResolvedBy),
+		ResolvedAt:   rm.ResolvedAt,
+		CreatedAt:    rm.CreatedAt,
+	}
+}
+
+func (r *ModerationRepository) CreateReport(report *models.Report) error {
+	logging.L.Debug("creating report", "repository", "ModerationRepository", "method", "CreateReport", "reporter_id", report.ReporterID, "target_type", report.TargetType)
+	rm := reportModel{
+		ReporterID:   int64(report.ReporterID),
+		TargetType:   report.TargetType,
+		PostID:       int64PtrFrom(report.PostID),
+		TargetUserID: int64(report.TargetUserID),
+		Reason:       report.Reason,
+		Comment:      report.Comment,
+		Status:       models.ReportStatusOpen,
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 部分ユニークインデックスで重複は防がれるが、インデックスのない環境でも同じ結果になるよう事前に確認する
+		query := tx.Model(&reportModel{}).Where("reporter_id = ? AND target_type = ? AND status = ?", rm.ReporterID, rm.TargetType, models.ReportStatusOpen)
+		if rm.TargetType == models.ReportTargetPost {
+			query = query.Where("post_id = ?", rm.PostID)
+		} else {
+			query = query.Where("target_user_id = ?", rm.TargetUserID)
+		}
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check existing report: %w", err)
+		}
+		if count > 0 {
+			return repositories.ErrAlreadyReported
+		}
+		if err := tx.Create(&rm).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return repositories.ErrAlreadyReported
+			}
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrAlreadyReported) {
+			logging.L.Debug("already reported", "repository", "ModerationRepository", "method", "CreateReport", "reporter_id", report.ReporterID)
+			return repositories.ErrAlreadyReported
+		}
+		logging.L.Error("failed to create report", "repository", "ModerationRepository", "method", "CreateReport", "reporter_id", report.ReporterID, "error", err)
+		return err
+	}
+	*report = r.toReport(&rm)
+	logging.L.Info("report created", "repository", "ModerationRepository", "method", "CreateReport", "report_id", report.ID, "reporter_id", report.ReporterID, "target_type", report.TargetType, "reason", report.Reason)
+	return nil
+}
+
+func (r *ModerationRepository) GetReport(id int) (*models.Report, error) {
+	var rm reportModel
+	if err := r.db.First(&rm, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrReportNotFound
+		}
+		logging.L.Error("failed to query report", "repository", "ModerationRepository", "method", "GetReport", "report_id", id, "error", err)
+		return nil, fmt.Errorf("failed to query report id=%d: %w", id, err)
+	}
+	report := r.toReport(&rm)
+	return &report, nil
+}
+
+func (r *ModerationRepository) ListReports(status string, limit, offset int) ([]models.Report, int, error) {
+	var total int64
+	if err := r.db.Model(&reportModel{}).Where("status = ?", status).Count(&total).Error; err != nil {
+		logging.L.Error("failed to count reports", "repository", "ModerationRepository", "method", "ListReports", "status", status, "error", err)
+		return nil, 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+	var rms []reportModel
+	if err := r.db.Where("status = ?", status).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rms).Error; err != nil {
+		logging.L.Error("failed to query reports", "repository", "ModerationRepository", "method", "ListReports", "status", status, "error", err)
+		return nil, 0, fmt.Errorf("failed to query reports: %w", err)
+	}
+	reports := make([]models.Report, 0, len(rms))
+	for i := range rms {
+		reports = append(reports, r.toReport(&rms[i]))
+	}
+	return reports, int(total), nil
+}
+
+func (r *ModerationRepository) ResolveReport(reportID, moderatorID int, action, note string, suspendUntil *time.Time) (*models.Report, error) {
+	logging.L.Debug("resolving report", "repository", "ModerationRepository", "method", "ResolveReport", "report_id", reportID, "moderator_id", moderatorID, "action", action)
+	var rm reportModel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx
+		// 複数の管理者が同時に同じ通報を対応しないよう行ロックを取得する（SQLite では無視される）
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&rm, "id = ?", reportID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repositories.ErrReportNotFound
+			}
+			return fmt.Errorf("failed to query report id=%d: %w", reportID, err)
+		}
+		if rm.Status != models.ReportStatusOpen {
+			return repositories.ErrReportAlreadyResolved
+		}
+
+		now := tx.NowFunc()
+		moderator := int64(moderatorID)
+		resolve := map[string]interface{}{
+			"resolution":  action,
+			"resolved_by": moderator,
+			"resolved_at": now,
+			"updated_at":  now,
+		}
+		logged := moderationActionModel{
+			ModeratorID: &moderator,
+			Action:      action,
+			ReportID:    &rm.ID,
+			Note:        note,
+		}
+
+		// 対応する通報の範囲（dismiss はこの通報のみ、それ以外は同じ対象への未対応の通報すべて）
+		reports := tx.Model(&reportModel{}).Where("status = ?", models.ReportStatusOpen)
+		switch action {
+		case models.ModerationActionDismiss:
+			reports = reports.Where("id = ?", rm.ID)
+			resolve["status"] = models.ReportStatusDismissed
+			logged.PostID = rm.PostID
+			logged.TargetUserID = &rm.TargetUserID
+		case models.ModerationActionHidePost:
+			if rm.TargetType != models.ReportTargetPost || rm.PostID == nil {
+				return repositories.ErrInvalidModerationAction
+			}
+			// 論理削除済みの投稿でも非表示の記録は残す
+			if err := tx.Unscoped().Model(&postModel{}).Where("id = ? AND hidden_at IS NULL", *rm.PostID).
+				UpdateColumn("hidden_at", now).Error; err != nil {
+				return fmt.Errorf("failed to hide post id=%d: %w", *rm.PostID, err)
+			}
+			reports = reports.Where("target_type = ? AND post_id = ?", models.ReportTargetPost, *rm.PostID)
+			resolve["status"] = models.ReportStatusActioned
+			logged.PostID = rm.PostID
+			logged.TargetUserID = &rm.TargetUserID
+		case models.ModerationActionSuspendUser:
+			reason := note
+			if reason == "" {
+				reason = rm.Reason
+			}
+			if err := suspendUser(tx, rm.TargetUserID, reason, suspendUntil, now); err != nil {
+				return err
+			}
+			// 投稿への通報を含め、同じユーザーへの未対応の通報をまとめて対応済みにする
+			reports = reports.Where("target_user_id = ?", rm.TargetUserID)
+			resolve["status"] = models.ReportStatusActioned
+			logged.PostID = rm.PostID
+			logged.TargetUserID = &rm.TargetUserID
+		default:
+			return repositories.ErrInvalidModerationAction
+		}
+
+		if err := reports.Updates(resolve).Error; err != nil {
+			return fmt.Errorf("failed to resolve reports: %w", err)
+		}
+		if err := tx.Create(&logged).Error; err != nil {
+			return fmt.Errorf("failed to record moderation action: %w", err)
+		}
+		return tx.First(&rm, "id = ?", rm.ID).Error
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrReportNotFound):
+			return nil, repositories.ErrReportNotFound
+		case errors.Is(err, repositories.ErrReportAlreadyResolved):
+			logging.L.Debug("report already resolved", "repository", "ModerationRepository", "method", "ResolveReport", "report_id", reportID)
+			return nil, repositories.ErrReportAlreadyResolved
+		case errors.Is(err, repositories.ErrInvalidModerationAction):
+			return nil, repositories.ErrInvalidModerationAction
+		case errors.Is(err, repositories.ErrCannotSuspendAdmin):
+			return nil, repositories.ErrCannotSuspendAdmin
+		}
+		logging.L.Error("failed to resolve report", "repository", "ModerationRepository", "method", "ResolveReport", "report_id", reportID, "error", err)
+		return nil, err
+	}
+	report := r.toReport(&rm)
+	logging.L.Info("report resolved", "repository", "ModerationRepository", "method", "ResolveReport", "report_id", reportID, "moderator_id", moderatorID, "action", action)
+	return &report, nil
+}
+
+// suspendUser はユーザーを利用停止にする
+// トークンバージョンを進めて発行済みのアクセストークンを無効化し、再ログイン・トークン更新をできなくするためリフレッシュトークンを削除する
+func suspendUser(tx *gorm.DB, userID int64, reason string, until *time.Time, now time.Time) error {
+	var um userModel
+	if err := tx.Select("id", "role").First(&um, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to query user id=%d: %w", userID, err)
+	}
+	if um.Role == models.RoleAdmin {
+		return repositories.ErrCannotSuspendAdmin
+	}
+	if err := tx.Model(&userModel{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"suspended_at":      now,
+		"suspended_until":   until,
+		"suspension_reason": reason,
+		"token_version":     gorm.Expr("token_version + 1"),
+	}).Error; err != nil {
+		return fmt.Errorf("failed to suspend user id=%d: %w", userID, err)
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RefreshToken{}).Error; err != nil {
+		return fmt.Errorf("failed to delete refresh tokens of user id=%d: %w", userID, err)
+	}
+	return nil
+}
+
+func (r *ModerationRepository) SuspendUser(moderatorID, userID int, reason string, until *time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := suspendUser(tx, int64(userID), reason, until, tx.NowFunc()); err != nil {
+			return err
+		}
+		moderator := int64(moderatorID)
+		target := int64(userID)
+		if err := tx.Create(&moderationActionModel{
+			ModeratorID:  &moderator,
+			Action:       models.ModerationActionSuspendUser,
+			TargetUserID: &target,
+			Note:         reason,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to record moderation action: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) || errors.Is(err, repositories.ErrCannotSuspendAdmin) {
+			return err
+		}
+		logging.L.Error("failed to suspend user", "repository", "ModerationRepository", "method", "SuspendUser", "user_id", userID, "error", err)
+		return err
+	}
+	logging.L.Info("user suspended", "repository", "ModerationRepository", "method", "SuspendUser", "user_id", userID, "moderator_id", moderatorID)
+	return nil
+}
+
+func (r *ModerationRepository) UnsuspendUser(moderatorID, userID int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&userModel{}).Where("id = ? AND suspended_at IS NOT NULL", userID).Updates(map[string]interface{}{
+			"suspended_at":      nil,
+			"suspended_until":   nil,
+			"suspension_reason": "",
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to unsuspend user id=%d: %w", userID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&userModel{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to query user id=%d: %w", userID, err)
+			}
+			if count == 0 {
+				return repositories.ErrUserNotFound
+			}
+			return repositories.ErrNotSuspended
+		}
+		moderator := int64(moderatorID)
+		target := int64(userID)
+		if err := tx.Create(&moderationActionModel{
+			ModeratorID:  &moderator,
+			Action:       models.ModerationActionUnsuspendUser,
+			TargetUserID: &target,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to record moderation action: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) || errors.Is(err, repositories.ErrNotSuspended) {
+			return err
+		}
+		logging.L.Error("failed to unsuspend user", "repository", "ModerationRepository", "method", "UnsuspendUser", "user_id", userID, "error", err)
+		return err
+	}
+	logging.L.Info("user unsuspended", "repository", "ModerationRepository", "method", "UnsuspendUser", "user_id", userID, "moderator_id", moderatorID)
+	return nil
+}
+
+func (r *ModerationRepository) ListActions(limit, offset int) ([]models.ModerationAction, int, error) {
+	var total int64
+	if err := r.db.Model(&moderationActionModel{}).Count(&total).Error; err != nil {
+		logging.L.Error("failed to count moderation actions", "repository", "ModerationRepository", "method", "ListActions", "error", err)
+		return nil, 0, fmt.Errorf("failed to count moderation actions: %w", err)
+	}
+	var ams []moderationActionModel
+	if err := r.db.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&ams).Error; err != nil {
+		logging.L.Error("failed to query moderation actions", "repository", "ModerationRepository", "method", "ListActions", "error", err)
+		return nil, 0, fmt.Errorf("failed to query moderation actions: %w", err)
+	}
+	actions := make([]models.ModerationAction, 0, len(ams))
+	for i := range ams {
+		am := &ams[i]
+		actions = append(actions, models.ModerationAction{
+			ID:           int(am.ID),
+			ModeratorID:  intPtrFrom64(am.ModeratorID),
+			Action:       am.Action,
+			ReportID:     intPtrFrom64(am.ReportID),
+			PostID:       intPtrFrom64(am.PostID),
+			TargetUserID: intPtrFrom64(am.TargetUserID),
+			Note:         am.Note,
+			CreatedAt:    am.CreatedAt,
+		})
+	}
+	return actions, int(total), nil
+}
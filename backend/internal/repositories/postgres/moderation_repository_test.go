@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// setupReportedPost は投稿者（ユーザー1）の投稿と通報者（ユーザー2）を作成し、投稿IDを返す
+func setupReportedPost(t *testing.T, db *gorm.DB) int {
+	t.Helper()
+	_, postID := setupPostAndUser(t, db)
+	if err := db.Create(&userModel{ID: 2, Email: "u2@example.com", DisplayName: "u2"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return postID
+}
+
+func createPostReport(t *testing.T, repo *ModerationRepository, reporterID, postID int) *models.Report {
+	t.Helper()
+	report := &models.Report{
+		ReporterID:   reporterID,
+		TargetType:   models.ReportTargetPost,
+		PostID:       &postID,
+		TargetUserID: 1,
+		Reason:       models.ReportReasonSpam,
+	}
+	if err := repo.CreateReport(report); err != nil {
+		t.Fatalf("CreateReport failed: %v", err)
+	}
+	return report
+}
+
+func TestModeration_CreateReportRejectsDuplicateOpenReport(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewModerationRepository(db)
+	postID := setupReportedPost(t, db)
+
+	report := createPostReport(t, repo, 2, postID)
+	if report.ID == 0 || report.Status != models.ReportStatusOpen {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	dup := &models.Report{ReporterID: 2, TargetType: models.ReportTargetPost, PostID: &postID, TargetUserID: 1, Reason: models.ReportReasonOther}
+	if err := repo.CreateReport(dup); !errors.Is(err, repositories.ErrAlreadyReported) {
+		t.Fatalf("expected ErrAlreadyReported, got %v", err)
+	}
+
+	// 対応済みになれば同じ対象を再度通報できる
+	if _, err := repo.ResolveReport(report.ID, 1, models.ModerationActionDismiss, "", nil); err != nil {
+		t.Fatalf("ResolveReport failed: %v", err)
+	}
+	if err := repo.CreateReport(dup); err != nil {
+		t.Fatalf("expected report after dismissal to succeed, got %v", err)
+	}
+}
+
+func TestModeration_ListReportsOldestFirst(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewModerationRepository(db)
+	postID := setupReportedPost(t, db)
+
+	first := createPostReport(t, repo, 2, postID)
+	second := &models.Report{ReporterID: 2, TargetType: models.ReportTargetUser, TargetUserID: 1, Reason: models.ReportReasonHarassment}
+	if err := repo.CreateReport(second); err != nil {
+		t.Fatalf("CreateReport failed: %v", err)
+	}
+
+	reports, total, err := repo.ListReports(models.ReportStatusOpen, 20, 0)
+	if err != nil {
+		t.Fatalf("ListReports failed: %v", err)
+	}
+	if total != 2 || len(reports) != 2 || reports[0].ID != first.ID || reports[1].ID != second.ID {
+		t.Fatalf("unexpected reports: total=%d %+v", total, reports)
+	}
+	if reports[0].PostID == nil || *reports[0].PostID != postID || reports[1].PostID != nil {
+		t.Fatalf("unexpected post ids: %+v", reports)
+	}
+}
+
+func TestModeration_HidePostHidesFromReadsAndLikes(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewModerationRepository(db)
+	postRepo := NewPostRepository(db)
+	postID := setupReportedPost(t, db)
+	if err := db.Create(&userModel{ID: 3, Email: "u3@example.com", DisplayName: "u3"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	report := createPostReport(t, repo, 2, postID)
+	other := createPostReport(t, repo, 3, postID)
+
+	resolved, err := repo.ResolveReport(report.ID, 3, models.ModerationActionHidePost, "spam", nil)
+	if err != nil {
+		t.Fatalf("ResolveReport failed: %v", err)
+	}
+	if resolved.Status != models.ReportStatusActioned || resolved.Resolution != models.ModerationActionHidePost ||
+		resolved.ResolvedBy == nil || *resolved.ResolvedBy != 3 || resolved.ResolvedAt == nil {
+		t.Fatalf("unexpected resolved report: %+v", resolved)
+	}
+	// 同じ投稿への未対応の通報もまとめて対応済みになる
+	if got, err := repo.GetReport(other.ID); err != nil || got.Status != models.ReportStatusActioned {
+		t.Fatalf("expected other report to be actioned, got %+v err=%v", got, err)
+	}
+
+	if _, err := postRepo.GetByID(postID, nil); !errors.Is(err, repositories.ErrPostNotFound) {
+		t.Fatalf("expected hidden post to be not found, got %v", err)
+	}
+	if posts, err := postRepo.GetAll(nil, models.PostFilter{}); err != nil || len(posts) != 0 {
+		t.Fatalf("expected no visible posts, got %d err=%v", len(posts), err)
+	}
+	if posts, err := postRepo.GetByUserID(1, nil); err != nil || len(posts) != 0 {
+		t.Fatalf("expected no visible posts of user, got %d err=%v", len(posts), err)
+	}
+	if err := postRepo.AddLike(2, postID); !errors.Is(err, repositories.ErrPostNotFound) {
+		t.Fatalf("expected ErrPostNotFound when liking hidden post, got %v", err)
+	}
+	var likes int64
+	db.Model(&postLikeModel{}).Where("post_id = ?", postID).Count(&likes)
+	if likes != 0 {
+		t.Fatalf("expected like insert to be rolled back, got %d likes", likes)
+	}
+
+	if _, err := repo.ResolveReport(report.ID, 3, models.ModerationActionDismiss, "", nil); !errors.Is(err, repositories.ErrReportAlreadyResolved) {
+		t.Fatalf("expected ErrReportAlreadyResolved, got %v", err)
+	}
+
+	actions, total, err := repo.ListActions(20, 0)
+	if err != nil {
+		t.Fatalf("ListActions failed: %v", err)
+	}
+	if total != 1 || actions[0].Action != models.ModerationActionHidePost || *actions[0].PostID != postID || *actions[0].ReportID != report.ID || actions[0].Note != "spam" {
+		t.Fatalf("unexpected actions: total=%d %+v", total, actions)
+	}
+}
+
+func TestModeration_HidePostRejectedForUserReport(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewModerationRepository(db)
+	setupReportedPost(t, db)
+
+	report := &models.Report{ReporterID: 2, TargetType: models.ReportTargetUser, TargetUserID: 1, Reason: models.ReportReasonImpersonation}
+	if err := repo.CreateReport(report); err != nil {
+		t.Fatalf("CreateReport failed: %v", err)
+	}
+	if _, err := repo.ResolveReport(report.ID, 3, models.ModerationActionHidePost, "", nil); !errors.Is(err, repositories.ErrInvalidModerationAction) {
+		t.Fatalf("expected ErrInvalidModerationAction, got %v", err)
+	}
+	got, err := repo.GetReport(report.ID)
+	if err != nil || got.Status != models.ReportStatusOpen {
+		t.Fatalf("expected report to stay open, got %+v err=%v", got, err)
+	}
+	if _, err := repo.ResolveReport(9999, 3, models.ModerationActionDismiss, "", nil); !errors.Is(err, repositories.ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound, got %v", err)
+	}
+}
+
+func TestModeration_SuspendUserRevokesRefreshTokens(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewModerationRepository(db)
+	postID := setupReportedPost(t, db)
+
+	postReport := createPostReport(t, repo, 2, postID)
+	userReport := &models.Report{ReporterID: 2, TargetType: models.ReportTargetUser, TargetUserID: 1, Reason: models.ReportReasonSpam}
+	if err := repo.CreateReport(userReport); err != nil {
+		t.Fatalf("CreateReport failed: %v", err)
+	}
+	for _, userID := range []int64{1, 2} {
+		token := models.RefreshToken{UserID: userID, TokenHash: "hash" + string(rune('0'+userID)), ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()}
+		if err := db.Create(&token).Error; err != nil {
+			t.Fatalf("failed to create refresh token: %v", err)
+		}
+	}
+
+	if _, err := repo.ResolveReport(userReport.ID, 2, models.ModerationActionSuspendUser, "", nil); err != nil {
+		t.Fatalf("ResolveReport failed: %v", err)
+	}
+
+	var um userModel
+	if err := db.First(&um, 1).Error; err != nil || um.SuspendedAt == nil {
+		t.Fatalf("expected user to be suspended, got %+v err=%v", um, err)
+	}
+	var remaining []models.RefreshToken
+	db.Find(&remaining)
+	if len(remaining) != 1 || remaining[0].UserID != 2 {
+		t.Fatalf("expected only other user's refresh token to remain, got %+v", remaining)
+	}
+	// 投稿への通報も投稿者への通報としてまとめて対応済みになる
+	if got, err := repo.GetReport(postReport.ID); err != nil || got.Status != models.ReportStatusActioned || got.Resolution != models.ModerationActionSuspendUser {
+		t.Fatalf("expected post report to be actioned, got %+v err=%v", got, err)
+	}
+}
+
+func TestModeration_SuspendAndUnsuspendUser(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewModerationRepository(db)
+	for _, um := range []userModel{
+		{ID: 1, Email: "u1@example.com", Role: models.RoleUser},
+		{ID: 2, Email: "u2@example.com", Role: models.RoleModerator},
+		{ID: 3, Email: "u3@example.com", Role: models.RoleAdmin},
+	} {
+		if err := db.Create(&um).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	until := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	if err := repo.SuspendUser(2, 1, "スパム行為のため", &until); err != nil {
+		t.Fatalf("SuspendUser failed: %v", err)
+	}
+	var um userModel
+	if err := db.First(&um, 1).Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if um.SuspendedAt == nil || um.SuspendedUntil == nil || !um.SuspendedUntil.Equal(until) || um.SuspensionReason != "スパム行為のため" || um.TokenVersion != 1 {
+		t.Fatalf("unexpected suspended user: %+v", um)
+	}
+
+	if err := repo.SuspendUser(2, 3, "x", nil); !errors.Is(err, repositories.ErrCannotSuspendAdmin) {
+		t.Fatalf("expected ErrCannotSuspendAdmin, got %v", err)
+	}
+	if err := repo.SuspendUser(2, 999, "x", nil); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	if err := repo.UnsuspendUser(2, 1); err != nil {
+		t.Fatalf("UnsuspendUser failed: %v", err)
+	}
+	um = userModel{}
+	db.First(&um, 1)
+	// 解除しても利用停止前のトークンは無効のまま
+	if um.SuspendedAt != nil || um.SuspendedUntil != nil || um.SuspensionReason != "" || um.TokenVersion != 1 {
+		t.Fatalf("unexpected unsuspended user: %+v", um)
+	}
+	if err := repo.UnsuspendUser(2, 1); !errors.Is(err, repositories.ErrNotSuspended) {
+		t.Fatalf("expected ErrNotSuspended, got %v", err)
+	}
+
+	actions, total, err := repo.ListActions(10, 0)
+	if err != nil {
+		t.Fatalf("ListActions failed: %v", err)
+	}
+	if total != 2 || actions[0].Action != models.ModerationActionUnsuspendUser || actions[1].Action != models.ModerationActionSuspendUser {
+		t.Fatalf("unexpected moderation actions: %+v", actions)
+	}
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type MuteRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewMuteRuleRepository(db *gorm.DB) *MuteRuleRepository {
+	return &MuteRuleRepository{db: db}
+}
+
+func (r *MuteRuleRepository) toDomain(mm *muteRuleModel) models.MuteRule {
+	return models.MuteRule{
+		ID:        int(mm.ID),
+		UserID:    int(mm.UserID),
+		Kind:      mm.Kind,
+		Keyword:   mm.Keyword,
+		FlavorID:  intPtrFrom64(mm.FlavorID),
+		ExpiresAt: mm.ExpiresAt,
+		CreatedAt: mm.CreatedAt,
+	}
+}
+
+func (r *MuteRuleRepository) Create(rule *models.MuteRule) error {
+	mm := muteRuleModel{
+		UserID:    int64(rule.UserID),
+		Kind:      rule.Kind,
+		Keyword:   rule.Keyword,
+		FlavorID:  int64PtrFrom(rule.FlavorID),
+		ExpiresAt: rule.ExpiresAt,
+	}
+	if err := r.db.Create(&mm).Error; err != nil {
+		logging.L.Error("failed to create mute rule", "repository", "MuteRuleRepository", "method", "Create", "user_id", rule.UserID, "error", err)
+		return fmt.Errorf("failed to create mute rule: %w", err)
+	}
+	*rule = r.toDomain(&mm)
+	logging.L.Info("mute rule created", "repository", "MuteRuleRepository", "method", "Create", "rule_id", rule.ID, "user_id", rule.UserID, "kind", rule.Kind)
+	return nil
+}
+
+func (r *MuteRuleRepository) GetByID(id int) (*models.MuteRule, error) {
+	var mm muteRuleModel
+	if err := r.db.First(&mm, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrMuteRuleNotFound
+		}
+		logging.L.Error("failed to query mute rule", "repository", "MuteRuleRepository", "method", "GetByID", "rule_id", id, "error", err)
+		return nil, fmt.Errorf("failed to query mute rule id=%d: %w", id, err)
+	}
+	rule := r.toDomain(&mm)
+	return &rule, nil
+}
+
+func (r *MuteRuleRepository) ListByUserID(userID int) ([]models.MuteRule, error) {
+	var mms []muteRuleModel
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&mms).Error; err != nil {
+		logging.L.Error("failed to query mute rules", "repository", "MuteRuleRepository", "method", "ListByUserID", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query mute rules by user_id=%d: %w", userID, err)
+	}
+	rules := make([]models.MuteRule, 0, len(mms))
+	for i := range mms {
+		rules = append(rules, r.toDomain(&mms[i]))
+	}
+	return rules, nil
+}
+
+func (r *MuteRuleRepository) Update(rule *models.MuteRule) error {
+	result := r.db.Model(&muteRuleModel{}).Where("id = ?", rule.ID).Updates(map[string]interface{}{
+		"kind":       rule.Kind,
+		"keyword":    rule.Keyword,
+		"flavor_id":  int64PtrFrom(rule.FlavorID),
+		"expires_at": rule.ExpiresAt,
+		"updated_at": r.db.NowFunc(),
+	})
+	if result.Error != nil {
+		logging.L.Error("failed to update mute rule", "repository", "MuteRuleRepository", "method", "Update", "rule_id", rule.ID, "error", result.Error)
+		return fmt.Errorf("failed to update mute rule id=%d: %w", rule.ID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrMuteRuleNotFound
+	}
+	logging.L.Info("mute rule updated", "repository", "MuteRuleRepository", "method", "Update", "rule_id", rule.ID, "kind", rule.Kind)
+	return nil
+}
+
+func (r *MuteRuleRepository) Delete(id int) error {
+	result := r.db.Where("id = ?", id).Delete(&muteRuleModel{})
+	if result.Error != nil {
+		logging.L.Error("failed to delete mute rule", "repository", "MuteRuleRepository", "method", "Delete", "rule_id", id, "error", result.Error)
+		return fmt.Errorf("failed to delete mute rule id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrMuteRuleNotFound
+	}
+	logging.L.Info("mute rule deleted", "repository", "MuteRuleRepository", "method", "Delete", "rule_id", id)
+	return nil
+}
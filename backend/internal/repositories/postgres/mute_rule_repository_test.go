@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// setupMuteRulePosts はユーザー1・2と、ユーザー2の投稿（本文・フレーバーの異なる3件）を作成し、名前ごとの投稿IDを返す
+func setupMuteRulePosts(t *testing.T, db *gorm.DB) map[string]int {
+	t.Helper()
+	for id := 1; id <= 2; id++ {
+		if err := db.Create(&userModel{ID: int64(id), Email: "u" + string(rune('0'+id)) + "@example.com", DisplayName: "u"}).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	if err := db.Create(&flavorModel{ID: 10, Name: "mint", Color: "#00ff00"}).Error; err != nil {
+		t.Fatalf("failed to create flavor: %v", err)
+	}
+	postRepo := NewPostRepository(db)
+	posts := map[string][]models.Slide{
+		"spoiler": {{Text: "first"}, {Text: "Big SPOILER ahead"}},
+		"percent": {{Text: "100% double apple"}},
+		"mint":    {{Text: "cool", Flavor: &models.Flavor{ID: 10}}},
+	}
+	ids := map[string]int{}
+	for name, slides := range posts {
+		p := &models.Post{UserID: 2, Slides: slides}
+		if err := postRepo.Create(p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		ids[name] = p.ID
+	}
+	return ids
+}
+
+// postIDSet は投稿IDの集合を返す
+func postIDSet(posts []models.Post) map[int]bool {
+	set := map[int]bool{}
+	for _, p := range posts {
+		set[p.ID] = true
+	}
+	return set
+}
+
+func TestMuteRuleRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMuteRuleRepository(db)
+	setupMuteRulePosts(t, db)
+
+	flavorID := 10
+	first := &models.MuteRule{UserID: 1, Kind: models.MuteRuleKeyword, Keyword: "spoiler"}
+	if err := repo.Create(first); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second := &models.MuteRule{UserID: 1, Kind: models.MuteRuleFlavor, FlavorID: &flavorID}
+	if err := repo.Create(second); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if first.ID == 0 || first.CreatedAt.IsZero() {
+		t.Fatalf("expected ID and CreatedAt to be set, got %+v", first)
+	}
+
+	rules, err := repo.ListByUserID(1)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if len(rules) != 2 || rules[0].ID != second.ID {
+		t.Fatalf("expected 2 rules newest first, got %+v", rules)
+	}
+	if rules[0].FlavorID == nil || *rules[0].FlavorID != flavorID {
+		t.Fatalf("expected flavor_id=%d, got %+v", flavorID, rules[0].FlavorID)
+	}
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	first.Keyword = "apple"
+	first.ExpiresAt = &expiresAt
+	if err := repo.Update(first); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err := repo.GetByID(first.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.Keyword != "apple" || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("expected updated rule, got %+v", got)
+	}
+
+	if err := repo.Delete(first.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.GetByID(first.ID); !errors.Is(err, repositories.ErrMuteRuleNotFound) {
+		t.Fatalf("expected ErrMuteRuleNotFound, got %v", err)
+	}
+	if err := repo.Delete(first.ID); !errors.Is(err, repositories.ErrMuteRuleNotFound) {
+		t.Fatalf("expected ErrMuteRuleNotFound on second delete, got %v", err)
+	}
+	if err := repo.Update(first); !errors.Is(err, repositories.ErrMuteRuleNotFound) {
+		t.Fatalf("expected ErrMuteRuleNotFound on update, got %v", err)
+	}
+}
+
+func TestPostRepository_MuteRulesFilterTimelines(t *testing.T) {
+	db := setupTestDB(t)
+	ruleRepo := NewMuteRuleRepository(db)
+	postRepo := NewPostRepository(db)
+	ids := setupMuteRulePosts(t, db)
+
+	viewerID := 1
+	flavorID := 10
+	past := time.Now().Add(-time.Hour)
+	rules := []*models.MuteRule{
+		// 大文字小文字を区別せず一致する
+		{UserID: 1, Kind: models.MuteRuleKeyword, Keyword: "spoiler"},
+		// % はワイルドカードではなく文字として扱う（"%apple" は "100% double apple" に一致しない）
+		{UserID: 1, Kind: models.MuteRuleKeyword, Keyword: "%apple"},
+		{UserID: 1, Kind: models.MuteRuleFlavor, FlavorID: &flavorID},
+		// 期限切れのルールは適用されない
+		{UserID: 1, Kind: models.MuteRuleKeyword, Keyword: "double", ExpiresAt: &past},
+		// 他ユーザーのルールは適用されない
+		{UserID: 2, Kind: models.MuteRuleKeyword, Keyword: "apple"},
+	}
+	for _, rule := range rules {
+		if err := ruleRepo.Create(rule); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	posts, err := postRepo.GetAll(&viewerID, models.PostFilter{})
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	visible := postIDSet(posts)
+	if visible[ids["spoiler"]] || visible[ids["mint"]] || !visible[ids["percent"]] || len(visible) != 1 {
+		t.Fatalf("expected only the percent post in timeline, got %v (ids=%v)", visible, ids)
+	}
+
+	posts, err = postRepo.GetByUserID(2, &viewerID)
+	if err != nil {
+		t.Fatalf("GetByUserID failed: %v", err)
+	}
+	if got := postIDSet(posts); len(got) != 1 || !got[ids["percent"]] {
+		t.Fatalf("expected only the percent post in user posts, got %v", got)
+	}
+
+	posts, err = postRepo.GetByIDs([]int{ids["spoiler"], ids["mint"], ids["percent"]}, &viewerID)
+	if err != nil {
+		t.Fatalf("GetByIDs failed: %v", err)
+	}
+	if got := postIDSet(posts); len(got) != 1 || !got[ids["percent"]] {
+		t.Fatalf("expected only the percent post from GetByIDs, got %v", got)
+	}
+
+	for name, want := range map[string]bool{"spoiler": false, "mint": false, "percent": true} {
+		visible, err := postRepo.IsVisibleTo(ids[name], viewerID)
+		if err != nil || visible != want {
+			t.Fatalf("expected IsVisibleTo(%s)=%v, got %v (err=%v)", name, want, visible, err)
+		}
+	}
+	// 閲覧者をブロックしたユーザーの投稿は表示できない
+	if err := db.Create(&userBlockModel{BlockerID: 2, BlockedID: 1}).Error; err != nil {
+		t.Fatalf("failed to create block: %v", err)
+	}
+	if visible, err := postRepo.IsVisibleTo(ids["percent"], viewerID); err != nil || visible {
+		t.Fatalf("expected post of blocking user not to be visible, got %v (err=%v)", visible, err)
+	}
+
+	// 未ログインの場合はミュートルールを適用しない
+	posts, err = postRepo.GetAll(nil, models.PostFilter{})
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("expected 3 posts for anonymous viewer, got %d", len(posts))
+	}
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+// errNotificationGroupRace は未読のまとめ先通知を同時に作成しようとして一意制約に違反したことを表す（内部でリトライする）
+var errNotificationGroupRace = errors.New("notification group created concurrently")
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) toDomain(nm *notificationModel) models.Notification {
+	n := models.Notification{
+		ID:          int(nm.ID),
+		Type:        nm.Type,
+		OthersCount: nm.ActorCount - 1,
+		IsRead:      nm.ReadAt != nil,
+		CreatedAt:   nm.CreatedAt,
+		UpdatedAt:   nm.UpdatedAt,
+	}
+	if n.OthersCount < 0 {
+		n.OthersCount = 0
+	}
+	if nm.PostID != nil {
+		postID := int(*nm.PostID)
+		n.PostID = &postID
+	}
+	if nm.Actor != nil {
+		n.Actor = &models.NotificationActor{
+			ID:          int(nm.Actor.ID),
+			DisplayName: nm.Actor.DisplayName,
+			IconURL:     nm.Actor.IconURL,
+		}
+	}
+	return n
+}
+
+// visible は削除済み投稿に紐づく通知を除外するクエリを返す
+func (r *NotificationRepository) visible(db *gorm.DB, userID int) *gorm.DB {
+	return db.Model(&notificationModel{}).
+		Joins("LEFT JOIN posts ON posts.id = notifications.post_id").
+		Where("notifications.user_id = ?", userID).
+		Where("notifications.post_id IS NULL OR posts.deleted_at IS NULL")
+}
+
+// insert は通知と最初の操作ユーザーを作成する（grouped が true の通知は未読のまとめ先として一意にする）
+func (r *NotificationRepository) insert(tx *gorm.DB, event models.NotificationEvent, grouped bool) (*notificationModel, error) {
+	actorID := int64(event.ActorID)
+	nm := notificationModel{
+		UserID:     int64(event.UserID),
+		Type:       event.Type,
+		ActorID:    &actorID,
+		ActorCount: 1,
+		Grouped:    grouped,
+	}
+	if event.PostID != nil {
+		postID := int64(*event.PostID)
+		nm.PostID = &postID
+	}
+	if err := tx.Omit("Actor").Create(&nm).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Create(&notificationActorModel{NotificationID: nm.ID, ActorID: actorID}).Error; err != nil {
+		return nil, fmt.Errorf("failed to insert notification actor: %w", err)
+	}
+	return &nm, nil
+}
+
+func (r *NotificationRepository) Create(event models.NotificationEvent) error {
+	logging.L.Debug("creating notification", "repository", "NotificationRepository", "method", "Create", "user_id", event.UserID, "type", event.Type)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		_, err := r.insert(tx, event, false)
+		return err
+	})
+	if err != nil {
+		logging.L.Error("failed to create notification", "repository", "NotificationRepository", "method", "Create", "user_id", event.UserID, "type", event.Type, "error", err)
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) CreateOrGroup(event models.NotificationEvent) error {
+	logging.L.Debug("creating or grouping notification", "repository", "NotificationRepository", "method", "CreateOrGroup", "user_id", event.UserID, "type", event.Type, "actor_id", event.ActorID)
+	err := r.createOrGroup(event)
+	if errors.Is(err, errNotificationGroupRace) {
+		// 他のリクエストが先にまとめ先を作成したため、作成された通知にまとめ直す
+		err = r.createOrGroup(event)
+	}
+	if err != nil {
+		logging.L.Error("failed to create or group notification", "repository", "NotificationRepository", "method", "CreateOrGroup", "user_id", event.UserID, "type", event.Type, "error", err)
+		return fmt.Errorf("failed to create or group notification: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) createOrGroup(event models.NotificationEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("user_id = ? AND type = ? AND read_at IS NULL AND grouped", event.UserID, event.Type)
+		if event.PostID != nil {
+			query = query.Where("post_id = ?", *event.PostID)
+		} else {
+			query = query.Where("post_id IS NULL")
+		}
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		var nm notificationModel
+		err := query.First(&nm).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if _, err := r.insert(tx, event, true); err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return errNotificationGroupRace
+				}
+				return err
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query unread notification group: %w", err)
+		}
+
+		// いいねの取り消し→再いいね等で同じユーザーが再度操作した場合は人数を加算しない
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&notificationActorModel{NotificationID: nm.ID, ActorID: int64(event.ActorID)})
+		if result.Error != nil {
+			return fmt.Errorf("failed to insert notification actor: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&notificationModel{}).Where("id = ?", nm.ID).Updates(map[string]interface{}{
+			"actor_id":    event.ActorID,
+			"actor_count": gorm.Expr("actor_count + 1"),
+			"updated_at":  tx.NowFunc(),
+		}).Error
+	})
+}
+
+func (r *NotificationRepository) ListByUserID(userID, limit, offset int) ([]models.Notification, int, error) {
+	logging.L.Debug("querying notifications", "repository", "NotificationRepository", "method", "ListByUserID", "user_id", userID, "limit", limit, "offset", offset)
+	var total int64
+	if err := r.visible(r.db, userID).Count(&total).Error; err != nil {
+		logging.L.Error("failed to count notifications", "repository", "NotificationRepository", "method", "ListByUserID", "user_id", userID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count notifications of user_id=%d: %w", userID, err)
+	}
+
+	var nms []notificationModel
+	if err := r.visible(r.db, userID).
+		Select("notifications.*").
+		Preload("Actor").
+		Order("notifications.updated_at DESC, notifications.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&nms).Error; err != nil {
+		logging.L.Error("failed to query notifications", "repository", "NotificationRepository", "method", "ListByUserID", "user_id", userID, "error", err)
+		return nil, 0, fmt.Errorf("failed to query notifications of user_id=%d: %w", userID, err)
+	}
+
+	notifications := make([]models.Notification, 0, len(nms))
+	for i := range nms {
+		notifications = append(notifications, r.toDomain(&nms[i]))
+	}
+	return notifications, int(total), nil
+}
+
+func (r *NotificationRepository) CountUnread(userID int) (int, error) {
+	var count int64
+	if err := r.visible(r.db, userID).Where("notifications.read_at IS NULL").Count(&count).Error; err != nil {
+		logging.L.Error("failed to count unread notifications", "repository", "NotificationRepository", "method", "CountUnread", "user_id", userID, "error", err)
+		return 0, fmt.Errorf("failed to count unread notifications of user_id=%d: %w", userID, err)
+	}
+	return int(count), nil
+}
+
+func (r *NotificationRepository) MarkRead(userID, notificationID int) error {
+	logging.L.Debug("marking notification as read", "repository", "NotificationRepository", "method", "MarkRead", "user_id", userID, "notification_id", notificationID)
+	var nm notificationModel
+	if err := r.db.Select("id", "read_at").Where("id = ? AND user_id = ?", notificationID, userID).First(&nm).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ErrNotificationNotFound
+		}
+		logging.L.Error("failed to query notification", "repository", "NotificationRepository", "method", "MarkRead", "notification_id", notificationID, "error", err)
+		return fmt.Errorf("failed to query notification id=%d: %w", notificationID, err)
+	}
+	if nm.ReadAt != nil {
+		return nil
+	}
+	if err := r.db.Model(&notificationModel{}).
+		Where("id = ? AND read_at IS NULL", notificationID).
+		Update("read_at", r.db.NowFunc()).Error; err != nil {
+		logging.L.Error("failed to mark notification as read", "repository", "NotificationRepository", "method", "MarkRead", "notification_id", notificationID, "error", err)
+		return fmt.Errorf("failed to mark notification id=%d as read: %w", notificationID, err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(userID int) (int, error) {
+	logging.L.Debug("marking all notifications as read", "repository", "NotificationRepository", "method", "MarkAllRead", "user_id", userID)
+	result := r.db.Model(&notificationModel{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", r.db.NowFunc())
+	if result.Error != nil {
+		logging.L.Error("failed to mark all notifications as read", "repository", "NotificationRepository", "method", "MarkAllRead", "user_id", userID, "error", result.Error)
+		return 0, fmt.Errorf("failed to mark notifications of user_id=%d as read: %w", userID, result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
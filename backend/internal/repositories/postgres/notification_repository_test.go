@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// createNotificationActors は操作ユーザーとなるユーザー（ID 2 以降）を作成する
+func createNotificationActors(t *testing.T, db *gorm.DB, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		id := int64(i + 2)
+		if err := db.Create(&userModel{ID: id, Email: fmt.Sprintf("u%d@example.com", id), DisplayName: fmt.Sprintf("u%d", id)}).Error; err != nil {
+			t.Fatalf("failed to create actor: %v", err)
+		}
+	}
+}
+
+func likeEvent(actorID, postID int) models.NotificationEvent {
+	return models.NotificationEvent{UserID: 1, ActorID: actorID, Type: models.NotificationTypePostLiked, PostID: &postID}
+}
+
+func TestNotification_GroupsUnreadLikes(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNotificationRepository(db)
+	_, postID := setupPostAndUser(t, db)
+	createNotificationActors(t, db, 5)
+
+	for actorID := 2; actorID <= 6; actorID++ {
+		if err := repo.CreateOrGroup(likeEvent(actorID, postID)); err != nil {
+			t.Fatalf("CreateOrGroup failed: %v", err)
+		}
+	}
+	// 同じユーザーの再度のいいねは人数に加算しない
+	if err := repo.CreateOrGroup(likeEvent(3, postID)); err != nil {
+		t.Fatalf("CreateOrGroup failed: %v", err)
+	}
+
+	notifications, total, err := repo.ListByUserID(1, 20, 0)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if total != 1 || len(notifications) != 1 {
+		t.Fatalf("expected 1 grouped notification, got total=%d %+v", total, notifications)
+	}
+	n := notifications[0]
+	if n.OthersCount != 4 {
+		t.Fatalf("expected others_count=4, got %d", n.OthersCount)
+	}
+	if n.Actor == nil || n.Actor.ID != 6 {
+		t.Fatalf("expected latest actor id=6, got %+v", n.Actor)
+	}
+	if n.PostID == nil || *n.PostID != postID || n.IsRead {
+		t.Fatalf("unexpected notification: %+v", n)
+	}
+}
+
+func TestNotification_UngroupedNotificationsAreNotUnique(t *testing.T) {
+	db := setupTestDB(t)
+	// 0035_add_notifications_grouped の未読のまとめ先通知の一意インデックス
+	if err := db.Exec(`CREATE UNIQUE INDEX idx_notifications_unread_group
+		ON notifications(user_id, type, COALESCE(post_id, 0))
+		WHERE read_at IS NULL AND grouped`).Error; err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+	repo := NewNotificationRepository(db)
+	_, postID := setupPostAndUser(t, db)
+	createNotificationActors(t, db, 3)
+
+	// まとめない通知は、同じ受信者・種類・投稿の未読があっても作成でき、まとめ先にもならない
+	for actorID := 2; actorID <= 3; actorID++ {
+		if err := repo.Create(likeEvent(actorID, postID)); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	for actorID := 3; actorID <= 4; actorID++ {
+		if err := repo.CreateOrGroup(likeEvent(actorID, postID)); err != nil {
+			t.Fatalf("CreateOrGroup failed: %v", err)
+		}
+	}
+
+	var grouped []notificationModel
+	if err := db.Where("grouped").Find(&grouped).Error; err != nil {
+		t.Fatalf("failed to query grouped notifications: %v", err)
+	}
+	if len(grouped) != 1 || grouped[0].ActorCount != 2 {
+		t.Fatalf("expected a single grouped notification of 2 actors, got %+v", grouped)
+	}
+	_, total, err := repo.ListByUserID(1, 20, 0)
+	if err != nil || total != 3 {
+		t.Fatalf("expected 2 ungrouped and 1 grouped notifications, got total=%d err=%v", total, err)
+	}
+}
+
+func TestNotification_ReadGroupStartsNewNotification(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNotificationRepository(db)
+	_, postID := setupPostAndUser(t, db)
+	createNotificationActors(t, db, 2)
+
+	if err := repo.CreateOrGroup(likeEvent(2, postID)); err != nil {
+		t.Fatalf("CreateOrGroup failed: %v", err)
+	}
+	count, err := repo.MarkAllRead(1)
+	if err != nil || count != 1 {
+		t.Fatalf("MarkAllRead: count=%d err=%v", count, err)
+	}
+	if err := repo.CreateOrGroup(likeEvent(3, postID)); err != nil {
+		t.Fatalf("CreateOrGroup failed: %v", err)
+	}
+
+	notifications, total, err := repo.ListByUserID(1, 20, 0)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected a new notification after the group was read, got %d", total)
+	}
+	if notifications[0].IsRead || notifications[0].Actor.ID != 3 || !notifications[1].IsRead {
+		t.Fatalf("unexpected order or read state: %+v", notifications)
+	}
+	unread, err := repo.CountUnread(1)
+	if err != nil || unread != 1 {
+		t.Fatalf("CountUnread: unread=%d err=%v", unread, err)
+	}
+}
+
+func TestNotification_MarkRead(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNotificationRepository(db)
+	_, postID := setupPostAndUser(t, db)
+	createNotificationActors(t, db, 1)
+
+	if err := repo.Create(likeEvent(2, postID)); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	notifications, _, err := repo.ListByUserID(1, 20, 0)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	id := notifications[0].ID
+
+	// 他ユーザーの通知は存在しないものとして扱う
+	if err := repo.MarkRead(2, id); !errors.Is(err, repositories.ErrNotificationNotFound) {
+		t.Fatalf("expected ErrNotificationNotFound, got %v", err)
+	}
+	if err := repo.MarkRead(1, id); err != nil {
+		t.Fatalf("MarkRead failed: %v", err)
+	}
+	// 既読の通知を再度既読にしても成功する
+	if err := repo.MarkRead(1, id); err != nil {
+		t.Fatalf("second MarkRead failed: %v", err)
+	}
+	unread, err := repo.CountUnread(1)
+	if err != nil || unread != 0 {
+		t.Fatalf("CountUnread: unread=%d err=%v", unread, err)
+	}
+}
+
+func TestNotification_PaginationAndDeletedPosts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNotificationRepository(db)
+	postRepo := NewPostRepository(db)
+	_, firstID := setupPostAndUser(t, db)
+	createNotificationActors(t, db, 1)
+	postIDs := []int{firstID}
+	for i := 0; i < 2; i++ {
+		p := &models.Post{UserID: 1, Slides: []models.Slide{{ImageURL: "/img.jpg", Text: "t"}}}
+		if err := postRepo.Create(p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		postIDs = append(postIDs, p.ID)
+	}
+	for _, id := range postIDs {
+		if err := repo.CreateOrGroup(likeEvent(2, id)); err != nil {
+			t.Fatalf("CreateOrGroup failed: %v", err)
+		}
+	}
+
+	page, total, err := repo.ListByUserID(1, 2, 0)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if total != 3 || len(page) != 2 {
+		t.Fatalf("expected 2 of 3 notifications, got total=%d len=%d", total, len(page))
+	}
+	page, _, err = repo.ListByUserID(1, 2, 2)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	if len(page) != 1 {
+		t.Fatalf("expected 1 notification on the second page, got %d", len(page))
+	}
+
+	// 削除された投稿への通知は一覧・未読数に含めない
+	if err := postRepo.DeletePost(1, postIDs[0]); err != nil {
+		t.Fatalf("DeletePost failed: %v", err)
+	}
+	_, total, err = repo.ListByUserID(1, 20, 0)
+	if err != nil {
+		t.Fatalf("ListByUserID failed: %v", err)
+	}
+	unread, err := repo.CountUnread(1)
+	if err != nil {
+		t.Fatalf("CountUnread failed: %v", err)
+	}
+	if total != 2 || unread != 2 {
+		t.Fatalf("expected deleted post notification to be hidden, got total=%d unread=%d", total, unread)
+	}
+}
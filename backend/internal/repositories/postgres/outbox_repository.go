@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/pkg/logging"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// writeOutboxEvent はドメインイベントをアウトボックスに書き込む
+// 変更と同じトランザクション tx で呼び出し、変更がロールバックされた場合はイベントも残らないようにする
+func writeOutboxEvent(tx *gorm.DB, eventType string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	now := tx.NowFunc()
+	if err := tx.Create(&outboxEventModel{
+		EventType:     eventType,
+		Payload:       string(b),
+		Status:        models.OutboxEventPending,
+		NextAttemptAt: &now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to write %s event to outbox: %w", eventType, err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) toEvent(om *outboxEventModel) models.DomainEvent {
+	completed := []string{}
+	if om.CompletedHandlers != "" {
+		completed = strings.Split(om.CompletedHandlers, ",")
+	}
+	return models.DomainEvent{
+		ID:                int(om.ID),
+		Type:              om.EventType,
+		Payload:           []byte(om.Payload),
+		Status:            om.Status,
+		Attempts:          om.Attempts,
+		CompletedHandlers: completed,
+		NextAttemptAt:     om.NextAttemptAt,
+		LastError:         om.LastError,
+		ProcessedAt:       om.ProcessedAt,
+		CreatedAt:         om.CreatedAt,
+	}
+}
+
+func (r *OutboxRepository) ClaimDueEvents(now time.Time, lease time.Duration, limit int) ([]models.DomainEvent, error) {
+	var oms []outboxEventModel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("status = ? AND next_attempt_at <= ?", models.OutboxEventPending, now).
+			Order("id ASC").
+			Limit(limit)
+		// 複数インスタンスで同じイベントを取り合わないよう、ロック済みの行は読み飛ばす（SQLite では無視される）
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := query.Find(&oms).Error; err != nil {
+			return fmt.Errorf("failed to query due outbox events: %w", err)
+		}
+		if len(oms) == 0 {
+			return nil
+		}
+		ids := make([]int64, 0, len(oms))
+		for i := range oms {
+			ids = append(ids, oms[i].ID)
+		}
+		leaseUntil := now.Add(lease)
+		if err := tx.Model(&outboxEventModel{}).Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error; err != nil {
+			return fmt.Errorf("failed to lease outbox events: %w", err)
+		}
+		for i := range oms {
+			oms[i].NextAttemptAt = &leaseUntil
+		}
+		return nil
+	})
+	if err != nil {
+		logging.L.Error("failed to claim outbox events", "repository", "OutboxRepository", "method", "ClaimDueEvents", "error", err)
+		return nil, err
+	}
+	events := make([]models.DomainEvent, 0, len(oms))
+	for i := range oms {
+		events = append(events, r.toEvent(&oms[i]))
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) RecordAttempt(event *models.DomainEvent) error {
+	if err := r.db.Model(&outboxEventModel{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+		"status":             event.Status,
+		"attempts":           event.Attempts,
+		"completed_handlers": strings.Join(event.CompletedHandlers, ","),
+		"next_attempt_at":    event.NextAttemptAt,
+		"last_error":         event.LastError,
+		"processed_at":       event.ProcessedAt,
+		"updated_at":         r.db.NowFunc(),
+	}).Error; err != nil {
+		logging.L.Error("failed to record outbox attempt", "repository", "OutboxRepository", "method", "RecordAttempt", "event_id", event.ID, "error", err)
+		return fmt.Errorf("failed to record attempt of outbox event id=%d: %w", event.ID, err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) DeleteProcessedBefore(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND processed_at < ?", models.OutboxEventProcessed, before).Delete(&outboxEventModel{})
+	if result.Error != nil {
+		logging.L.Error("failed to delete processed outbox events", "repository", "OutboxRepository", "method", "DeleteProcessedBefore", "error", result.Error)
+		return 0, fmt.Errorf("failed to delete processed outbox events: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logging.L.Debug("processed outbox events deleted", "repository", "OutboxRepository", "method", "DeleteProcessedBefore", "count", result.RowsAffected)
+	}
+	return result.RowsAffected, nil
+}
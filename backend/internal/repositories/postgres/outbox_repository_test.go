@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestOutbox_PostChangesWriteEvents(t *testing.T) {
+	db := setupTestDB(t)
+	postRepo := NewPostRepository(db)
+	outboxRepo := NewOutboxRepository(db)
+	userID, postID := setupPostAndUser(t, db)
+
+	if err := postRepo.AddLike(userID, postID); err != nil {
+		t.Fatalf("AddLike failed: %v", err)
+	}
+	// ロールバックされた変更のイベントは残らない
+	if err := postRepo.AddLike(userID, postID); !errors.Is(err, repositories.ErrAlreadyLiked) {
+		t.Fatalf("expected ErrAlreadyLiked, got %v", err)
+	}
+	if err := postRepo.DeletePost(userID, postID); err != nil {
+		t.Fatalf("DeletePost failed: %v", err)
+	}
+
+	events, err := outboxRepo.ClaimDueEvents(time.Now().Add(time.Second), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueEvents failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+
+	var created models.PostCreatedEvent
+	if events[0].Type != models.DomainEventPostCreated || events[0].Decode(&created) != nil {
+		t.Fatalf("unexpected first event: %+v", events[0])
+	}
+	if created.PostID != postID || created.UserID != userID || len(created.ImageURLs) != 1 || created.ImageURLs[0] != "/img.jpg" {
+		t.Fatalf("unexpected PostCreated payload: %+v", created)
+	}
+
+	var liked models.LikeAddedEvent
+	if events[1].Type != models.DomainEventLikeAdded || events[1].Decode(&liked) != nil {
+		t.Fatalf("unexpected second event: %+v", events[1])
+	}
+	if liked.PostID != postID || liked.UserID != userID || liked.PostOwnerID != userID || liked.Likes != 1 {
+		t.Fatalf("unexpected LikeAdded payload: %+v", liked)
+	}
+
+	var deleted models.PostDeletedEvent
+	if events[2].Type != models.DomainEventPostDeleted || events[2].Decode(&deleted) != nil || deleted.PostID != postID {
+		t.Fatalf("unexpected third event: %+v", events[2])
+	}
+}
+
+func TestOutbox_ClaimLeaseAndRecordAttempt(t *testing.T) {
+	db := setupTestDB(t)
+	outboxRepo := NewOutboxRepository(db)
+	setupPostAndUser(t, db)
+
+	now := time.Now().Add(time.Second)
+	events, err := outboxRepo.ClaimDueEvents(now, time.Minute, 10)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected 1 event, got %+v (err=%v)", events, err)
+	}
+
+	// リース期間中は再度取り出されない
+	again, err := outboxRepo.ClaimDueEvents(now.Add(time.Second), time.Minute, 10)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("expected leased event to be skipped, got %+v (err=%v)", again, err)
+	}
+
+	// 一部の購読者だけ成功した状態で再試行待ちにする
+	event := events[0]
+	next := now.Add(5 * time.Second)
+	event.Attempts = 1
+	event.CompletedHandlers = []string{"a", "b"}
+	event.NextAttemptAt = &next
+	event.LastError = "c: temporary error"
+	if err := outboxRepo.RecordAttempt(&event); err != nil {
+		t.Fatalf("RecordAttempt failed: %v", err)
+	}
+
+	retried, err := outboxRepo.ClaimDueEvents(next, time.Minute, 10)
+	if err != nil || len(retried) != 1 {
+		t.Fatalf("expected event to be retried, got %+v (err=%v)", retried, err)
+	}
+	got := retried[0]
+	if got.Attempts != 1 || len(got.CompletedHandlers) != 2 || got.CompletedHandlers[1] != "b" || got.LastError != "c: temporary error" {
+		t.Fatalf("unexpected retried event: %+v", got)
+	}
+
+	processedAt := next
+	got.Status = models.OutboxEventProcessed
+	got.Attempts = 2
+	got.NextAttemptAt = nil
+	got.ProcessedAt = &processedAt
+	if err := outboxRepo.RecordAttempt(&got); err != nil {
+		t.Fatalf("RecordAttempt failed: %v", err)
+	}
+	done, err := outboxRepo.ClaimDueEvents(next.Add(time.Hour), time.Minute, 10)
+	if err != nil || len(done) != 0 {
+		t.Fatalf("expected processed event not to be claimed, got %+v (err=%v)", done, err)
+	}
+
+	// 処理日時が before より前の処理済みのイベントだけを削除する
+	if deleted, err := outboxRepo.DeleteProcessedBefore(processedAt); err != nil || deleted != 0 {
+		t.Fatalf("expected nothing to be deleted, got %d (err=%v)", deleted, err)
+	}
+	if deleted, err := outboxRepo.DeleteProcessedBefore(processedAt.Add(time.Second)); err != nil || deleted != 1 {
+		t.Fatalf("expected processed event to be deleted, got %d (err=%v)", deleted, err)
+	}
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type PasskeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPasskeyRepository(db *gorm.DB) *PasskeyRepository {
+	return &PasskeyRepository{db: db}
+}
+
+func (r *PasskeyRepository) Create(passkey *models.Passkey) error {
+	pm := passkeyCredentialModel{
+		UserID:       int64(passkey.UserID),
+		CredentialID: passkey.CredentialID,
+		PublicKey:    passkey.PublicKey,
+		SignCount:    int64(passkey.SignCount),
+		Transports:   strings.Join(passkey.Transports, ","),
+		Name:         passkey.Name,
+		CreatedAt:    r.db.NowFunc(),
+	}
+	if err := r.db.Create(&pm).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repositories.ErrPasskeyAlreadyExists
+		}
+		logging.L.Error("failed to create passkey", "repository", "PasskeyRepository", "method", "Create", "user_id", passkey.UserID, "error", err)
+		return fmt.Errorf("failed to create passkey: %w", err)
+	}
+	passkey.ID = pm.ID
+	passkey.CreatedAt = pm.CreatedAt
+	logging.L.Info("passkey created", "repository", "PasskeyRepository", "method", "Create", "user_id", passkey.UserID, "passkey_id", pm.ID)
+	return nil
+}
+
+func (r *PasskeyRepository) GetByCredentialID(credentialID []byte) (*models.Passkey, error) {
+	var pm passkeyCredentialModel
+	if err := r.db.Where("credential_id = ?", credentialID).First(&pm).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrPasskeyNotFound
+		}
+		logging.L.Error("failed to query passkey", "repository", "PasskeyRepository", "method", "GetByCredentialID", "error", err)
+		return nil, fmt.Errorf("failed to query passkey: %w", err)
+	}
+	passkey := toPasskey(pm)
+	return &passkey, nil
+}
+
+func (r *PasskeyRepository) ListByUserID(userID int) ([]models.Passkey, error) {
+	var rows []passkeyCredentialModel
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&rows).Error; err != nil {
+		logging.L.Error("failed to list passkeys", "repository", "PasskeyRepository", "method", "ListByUserID", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list passkeys of user id=%d: %w", userID, err)
+	}
+	passkeys := make([]models.Passkey, 0, len(rows))
+	for _, pm := range rows {
+		passkeys = append(passkeys, toPasskey(pm))
+	}
+	return passkeys, nil
+}
+
+func (r *PasskeyRepository) UpdateSignCount(id int64, signCount uint32, usedAt time.Time) error {
+	result := r.db.Model(&passkeyCredentialModel{}).
+		Where("id = ? AND (sign_count < ? OR (sign_count = 0 AND ? = 0))", id, int64(signCount), int64(signCount)).
+		Updates(map[string]interface{}{"sign_count": int64(signCount), "last_used_at": usedAt})
+	if result.Error != nil {
+		logging.L.Error("failed to update passkey sign count", "repository", "PasskeyRepository", "method", "UpdateSignCount", "passkey_id", id, "error", result.Error)
+		return fmt.Errorf("failed to update passkey id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrPasskeySignCountStale
+	}
+	return nil
+}
+
+func (r *PasskeyRepository) Rename(userID int, id int64, name string) error {
+	result := r.db.Model(&passkeyCredentialModel{}).Where("id = ? AND user_id = ?", id, userID).Update("name", name)
+	if result.Error != nil {
+		logging.L.Error("failed to rename passkey", "repository", "PasskeyRepository", "method", "Rename", "user_id", userID, "passkey_id", id, "error", result.Error)
+		return fmt.Errorf("failed to rename passkey id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrPasskeyNotFound
+	}
+	return nil
+}
+
+func (r *PasskeyRepository) Delete(userID int, id int64) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&passkeyCredentialModel{})
+	if result.Error != nil {
+		logging.L.Error("failed to delete passkey", "repository", "PasskeyRepository", "method", "Delete", "user_id", userID, "passkey_id", id, "error", result.Error)
+		return fmt.Errorf("failed to delete passkey id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrPasskeyNotFound
+	}
+	logging.L.Info("passkey deleted", "repository", "PasskeyRepository", "method", "Delete", "user_id", userID, "passkey_id", id)
+	return nil
+}
+
+// toPasskey は passkeyCredentialModel をドメインモデルに変換する
+func toPasskey(pm passkeyCredentialModel) models.Passkey {
+	transports := []string{}
+	if pm.Transports != "" {
+		transports = strings.Split(pm.Transports, ",")
+	}
+	return models.Passkey{
+		ID:           pm.ID,
+		UserID:       int(pm.UserID),
+		CredentialID: pm.CredentialID,
+		PublicKey:    pm.PublicKey,
+		SignCount:    uint32(pm.SignCount),
+		Transports:   transports,
+		Name:         pm.Name,
+		CreatedAt:    pm.CreatedAt,
+		LastUsedAt:   pm.LastUsedAt,
+	}
+}
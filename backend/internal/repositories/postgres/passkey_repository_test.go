@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestPasskeyRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPasskeyRepository(db)
+	for _, u := range []userModel{{ID: 1, Email: "passkey1@example.com"}, {ID: 2, Email: "passkey2@example.com"}} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	first := &models.Passkey{UserID: 1, CredentialID: []byte{1, 2, 3}, PublicKey: []byte("key1"), SignCount: 5, Transports: []string{"internal", "hybrid"}, Name: "iPhone"}
+	second := &models.Passkey{UserID: 1, CredentialID: []byte{4, 5, 6}, PublicKey: []byte("key2"), Transports: []string{}, Name: "YubiKey"}
+	for _, passkey := range []*models.Passkey{first, second} {
+		if err := repo.Create(passkey); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if passkey.ID == 0 {
+			t.Fatal("expected ID to be set")
+		}
+	}
+
+	t.Run("異常系: 同じクレデンシャルIDは登録できない", func(t *testing.T) {
+		err := repo.Create(&models.Passkey{UserID: 2, CredentialID: []byte{1, 2, 3}, PublicKey: []byte("other"), Name: "dup"})
+		if !errors.Is(err, repositories.ErrPasskeyAlreadyExists) {
+			t.Errorf("expected ErrPasskeyAlreadyExists, got %v", err)
+		}
+	})
+
+	t.Run("正常系: クレデンシャルIDで取得できる", func(t *testing.T) {
+		got, err := repo.GetByCredentialID([]byte{1, 2, 3})
+		if err != nil {
+			t.Fatalf("GetByCredentialID failed: %v", err)
+		}
+		if got.ID != first.ID || got.UserID != 1 || string(got.PublicKey) != "key1" || got.SignCount != 5 || len(got.Transports) != 2 || got.LastUsedAt != nil {
+			t.Errorf("unexpected passkey: %+v", got)
+		}
+		if _, err := repo.GetByCredentialID([]byte{9}); !errors.Is(err, repositories.ErrPasskeyNotFound) {
+			t.Errorf("expected ErrPasskeyNotFound, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 署名カウンターと最終利用日時を更新する", func(t *testing.T) {
+		usedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		if err := repo.UpdateSignCount(first.ID, 6, usedAt); err != nil {
+			t.Fatalf("UpdateSignCount failed: %v", err)
+		}
+		got, _ := repo.GetByCredentialID([]byte{1, 2, 3})
+		if got.SignCount != 6 || got.LastUsedAt == nil || !got.LastUsedAt.Equal(usedAt) {
+			t.Errorf("unexpected passkey after update: %+v", got)
+		}
+	})
+
+	t.Run("異常系: 保存済みのカウンター以下には更新しない", func(t *testing.T) {
+		if err := repo.UpdateSignCount(first.ID, 6, time.Now()); !errors.Is(err, repositories.ErrPasskeySignCountStale) {
+			t.Fatalf("expected ErrPasskeySignCountStale, got %v", err)
+		}
+		got, _ := repo.GetByCredentialID([]byte{1, 2, 3})
+		if got.SignCount != 6 {
+			t.Errorf("expected sign count to stay 6, got %d", got.SignCount)
+		}
+	})
+
+	t.Run("正常系: カウンターを使わない認証器は 0 のまま最終利用日時を更新する", func(t *testing.T) {
+		if err := repo.UpdateSignCount(second.ID, 0, time.Now()); err != nil {
+			t.Fatalf("UpdateSignCount failed: %v", err)
+		}
+	})
+
+	t.Run("正常系: 一覧は登録順で本人のパスキーだけを返す", func(t *testing.T) {
+		passkeys, err := repo.ListByUserID(1)
+		if err != nil {
+			t.Fatalf("ListByUserID failed: %v", err)
+		}
+		if len(passkeys) != 2 || passkeys[0].ID != first.ID || passkeys[1].ID != second.ID {
+			t.Fatalf("unexpected passkeys: %+v", passkeys)
+		}
+		if passkeys[1].Transports == nil || len(passkeys[1].Transports) != 0 {
+			t.Errorf("expected empty transports, got %v", passkeys[1].Transports)
+		}
+		others, err := repo.ListByUserID(2)
+		if err != nil || len(others) != 0 {
+			t.Errorf("expected no passkeys for user 2, got %v (err=%v)", others, err)
+		}
+	})
+
+	t.Run("名前の変更と削除は本人のパスキーだけ", func(t *testing.T) {
+		if err := repo.Rename(2, first.ID, "盗む"); !errors.Is(err, repositories.ErrPasskeyNotFound) {
+			t.Errorf("expected ErrPasskeyNotFound for other user, got %v", err)
+		}
+		if err := repo.Rename(1, first.ID, "新しい iPhone"); err != nil {
+			t.Fatalf("Rename failed: %v", err)
+		}
+		if got, _ := repo.GetByCredentialID([]byte{1, 2, 3}); got.Name != "新しい iPhone" {
+			t.Errorf("expected renamed passkey, got %q", got.Name)
+		}
+
+		if err := repo.Delete(2, first.ID); !errors.Is(err, repositories.ErrPasskeyNotFound) {
+			t.Errorf("expected ErrPasskeyNotFound for other user, got %v", err)
+		}
+		if err := repo.Delete(1, first.ID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if err := repo.Delete(1, first.ID); !errors.Is(err, repositories.ErrPasskeyNotFound) {
+			t.Errorf("expected ErrPasskeyNotFound after delete, got %v", err)
+		}
+		if _, err := repo.GetByCredentialID([]byte{1, 2, 3}); !errors.Is(err, repositories.ErrPasskeyNotFound) {
+			t.Errorf("expected deleted passkey to be gone, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+func (r *PasswordResetRepository) CreateToken(userID int, rawToken string, expiresAt time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", userID).Delete(&passwordResetTokenModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete previous password reset tokens: %w", err)
+		}
+		if err := tx.Create(&passwordResetTokenModel{
+			UserID:    int64(userID),
+			TokenHash: hashToken(rawToken),
+			ExpiresAt: expiresAt,
+			CreatedAt: tx.NowFunc(),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to create password reset token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logging.L.Error("failed to create password reset token", "repository", "PasswordResetRepository", "method", "CreateToken", "user_id", userID, "error", err)
+		return err
+	}
+	logging.L.Info("password reset token created", "repository", "PasswordResetRepository", "method", "CreateToken", "user_id", userID)
+	return nil
+}
+
+func (r *PasswordResetRepository) ConsumeToken(rawToken string, now time.Time) (int, error) {
+	var userID int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var tm passwordResetTokenModel
+		// 同じトークンでの同時リクエストは、先に使用済みにした方だけを成功させる
+		result := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashToken(rawToken), now).Limit(1).Find(&tm)
+		if result.Error != nil {
+			return fmt.Errorf("failed to query password reset token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrInvalidPasswordResetToken
+		}
+		result = tx.Model(&passwordResetTokenModel{}).Where("id = ? AND used_at IS NULL", tm.ID).Update("used_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("failed to consume password reset token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrInvalidPasswordResetToken
+		}
+		userID = int(tm.UserID)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidPasswordResetToken) {
+			logging.L.Debug("invalid password reset token", "repository", "PasswordResetRepository", "method", "ConsumeToken")
+			return 0, err
+		}
+		logging.L.Error("failed to consume password reset token", "repository", "PasswordResetRepository", "method", "ConsumeToken", "error", err)
+		return 0, err
+	}
+	logging.L.Info("password reset token consumed", "repository", "PasswordResetRepository", "method", "ConsumeToken", "user_id", userID)
+	return userID, nil
+}
+
+func (r *PasswordResetRepository) DeleteExpiredTokens(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&passwordResetTokenModel{})
+	if result.Error != nil {
+		logging.L.Error("failed to delete expired password reset tokens", "repository", "PasswordResetRepository", "method", "DeleteExpiredTokens", "error", result.Error)
+		return 0, fmt.Errorf("failed to delete expired password reset tokens: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logging.L.Debug("expired password reset tokens deleted", "repository", "PasswordResetRepository", "method", "DeleteExpiredTokens", "count", result.RowsAffected)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestPasswordReset_ConsumeToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPasswordResetRepository(db)
+	for id := int64(1); id <= 2; id++ {
+		if err := db.Create(&userModel{ID: id, Email: "u" + string(rune('0'+id)) + "@example.com"}).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	now := time.Now()
+	tokens := []struct {
+		raw       string
+		userID    int
+		expiresAt time.Time
+	}{
+		{raw: "first", userID: 1, expiresAt: now.Add(30 * time.Minute)},
+		{raw: "second", userID: 1, expiresAt: now.Add(30 * time.Minute)},
+		{raw: "expired", userID: 2, expiresAt: now.Add(-time.Minute)},
+	}
+	for _, token := range tokens {
+		if err := repo.CreateToken(token.userID, token.raw, token.expiresAt); err != nil {
+			t.Fatalf("CreateToken failed: %v", err)
+		}
+	}
+
+	// トークンはハッシュだけを保存し、新しいトークンを発行するとそれまでの未使用のトークンは削除する
+	var stored []passwordResetTokenModel
+	if err := db.Where("user_id = ?", 1).Find(&stored).Error; err != nil {
+		t.Fatalf("failed to query tokens: %v", err)
+	}
+	if len(stored) != 1 || stored[0].TokenHash != hashToken("second") {
+		t.Fatalf("expected only the hash of the latest token to be stored, got %+v", stored)
+	}
+
+	userID, err := repo.ConsumeToken("second", now)
+	if err != nil || userID != 1 {
+		t.Fatalf("expected user 1, got %d (err=%v)", userID, err)
+	}
+	// 使用済み・置き換え済み・有効期限切れ・存在しないトークンは使えない
+	for _, raw := range []string{"second", "first", "expired", "unknown"} {
+		if _, err := repo.ConsumeToken(raw, now); !errors.Is(err, repositories.ErrInvalidPasswordResetToken) {
+			t.Errorf("expected ErrInvalidPasswordResetToken for %s, got %v", raw, err)
+		}
+	}
+
+	deleted, err := repo.DeleteExpiredTokens(now)
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected 1 expired token to be deleted, got %d (err=%v)", deleted, err)
+	}
+}
@@ -3,8 +3,10 @@ package postgres
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"go-shisha-backend/internal/models"
 	"go-shisha-backend/internal/repositories"
@@ -38,6 +40,9 @@ func (r *PostRepository) toDomain(pm *postModel) models.Post {
 				Name:  sm.Flavor.Name,
 				Color: sm.Flavor.Color,
 			}
+			if sm.Flavor.Category != nil {
+				slide.Flavor.Category = *sm.Flavor.Category
+			}
 		}
 		slides = append(slides, slide)
 	}
@@ -54,22 +59,154 @@ func (r *PostRepository) toDomain(pm *postModel) models.Post {
 		}
 	}
 
+	var remixedFrom *int
+	if pm.RemixedFrom != nil {
+		v := int(*pm.RemixedFrom)
+		remixedFrom = &v
+	}
+
+	var session *models.ShishaSession
+	if pm.Session != nil {
+		session = &models.ShishaSession{
+			BowlType:        pm.Session.BowlType,
+			HeatManagement:  pm.Session.HeatManagement,
+			CoalCount:       pm.Session.CoalCount,
+			DurationMinutes: pm.Session.DurationMinutes,
+			Rating:          pm.Session.Rating,
+		}
+	}
+
 	return models.Post{
-		ID:        int(pm.ID),
-		UserID:    int(pm.UserID),
-		Slides:    slides,
-		Likes:     pm.Likes,
-		User:      user,
-		CreatedAt: pm.CreatedAt,
+		ID:          int(pm.ID),
+		UserID:      int(pm.UserID),
+		Slides:      slides,
+		Likes:       pm.Likes,
+		User:        user,
+		CreatedAt:   pm.CreatedAt,
+		RemixedFrom: remixedFrom,
+		Session:     session,
+	}
+}
+
+// toSessionModel はセッション詳細をDBモデルに変換する
+func toSessionModel(postID int64, s *models.ShishaSession) *postSessionModel {
+	return &postSessionModel{
+		PostID:          postID,
+		BowlType:        s.BowlType,
+		HeatManagement:  s.HeatManagement,
+		CoalCount:       s.CoalCount,
+		DurationMinutes: s.DurationMinutes,
+		Rating:          s.Rating,
+	}
+}
+
+// applyPostFilter はセッション詳細による絞り込み条件をクエリに追加する
+// セッション詳細が未入力の投稿は、いずれかの条件を指定した時点で対象外になる
+func applyPostFilter(db *gorm.DB, filter models.PostFilter) *gorm.DB {
+	if filter.MinRating != nil {
+		db = db.Where("EXISTS (SELECT 1 FROM post_sessions ps WHERE ps.post_id = posts.id AND ps.rating >= ?)", *filter.MinRating)
+	}
+	if filter.BowlType != nil {
+		db = db.Where("EXISTS (SELECT 1 FROM post_sessions ps WHERE ps.post_id = posts.id AND ps.bowl_type = ?)", *filter.BowlType)
+	}
+	if filter.HeatManagement != nil {
+		db = db.Where("EXISTS (SELECT 1 FROM post_sessions ps WHERE ps.post_id = posts.id AND ps.heat_management = ?)", *filter.HeatManagement)
 	}
+	return db
 }
 
-func (r *PostRepository) GetAll(userID *int) ([]models.Post, error) {
+// visiblePosts は管理者によって非表示にされた投稿を除外する
+func visiblePosts(db *gorm.DB) *gorm.DB {
+	return db.Where("posts.hidden_at IS NULL")
+}
+
+// excludeAuthorsHiddenFrom は閲覧者がミュート・ブロックしたユーザーと、閲覧者をブロックしたユーザーの投稿を除外する
+func excludeAuthorsHiddenFrom(viewerID int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("posts.user_id NOT IN (SELECT muted_id FROM user_mutes WHERE muter_id = ?)", viewerID).
+			Where("posts.user_id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = ?)", viewerID).
+			Where("posts.user_id NOT IN (SELECT blocker_id FROM user_blocks WHERE blocked_id = ?)", viewerID)
+	}
+}
+
+// excludeMutedContent は閲覧者の有効なミュートルールに一致するスライドを含む投稿を除外する
+// キーワードは大文字・小文字を区別せずスライド本文と部分一致させる（LIKE の特殊文字はエスケープする）
+func excludeMutedContent(viewerID int, now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`NOT EXISTS (
+			SELECT 1 FROM slides ms JOIN mute_rules mr ON mr.user_id = ? AND (mr.expires_at IS NULL OR mr.expires_at > ?)
+			WHERE ms.post_id = posts.id AND (
+				(mr.kind = 'flavor' AND ms.flavor_id = mr.flavor_id) OR
+				(mr.kind = 'keyword' AND LOWER(ms.text) LIKE '%' || REPLACE(REPLACE(REPLACE(LOWER(mr.keyword), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\')
+			)
+		)`, viewerID, now)
+	}
+}
+
+// isBlockedByPostOwner は userID が postID の投稿者にブロックされているかを返す
+func isBlockedByPostOwner(tx *gorm.DB, userID, postID int) (bool, error) {
+	var count int64
+	if err := tx.Model(&userBlockModel{}).
+		Where("blocked_id = ? AND blocker_id IN (SELECT user_id FROM posts WHERE id = ?)", userID, postID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check block status: %w", err)
+	}
+	return count > 0, nil
+}
+
+// remixCountsByPostID は指定投稿ごとの（論理削除されていない）リミックス数を1クエリでまとめて取得する
+// リミックスが0件の投稿はマップに含まれない
+func (r *PostRepository) remixCountsByPostID(postIDs []int) (map[int]int, error) {
+	counts := map[int]int{}
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		RemixedFrom int64
+		Count       int
+	}
+	if err := r.db.Model(&postModel{}).
+		Select("remixed_from, COUNT(*) AS count").
+		Scopes(visiblePosts).
+		Where("remixed_from IN ?", postIDs).
+		Group("remixed_from").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count remixes: %w", err)
+	}
+	for _, row := range rows {
+		counts[int(row.RemixedFrom)] = row.Count
+	}
+	return counts, nil
+}
+
+// attachRemixCounts は投稿一覧にリミックス数を設定する
+// 集計に失敗しても一覧取得自体は失敗させず、ログのみ出力して remix_count=0 のまま返す
+func (r *PostRepository) attachRemixCounts(posts []models.Post, method string) {
+	postIDs := make([]int, 0, len(posts))
+	for i := range posts {
+		postIDs = append(postIDs, posts[i].ID)
+	}
+	counts, err := r.remixCountsByPostID(postIDs)
+	if err != nil {
+		logging.L.Error("failed to fetch remix counts", "repository", "PostRepository", "method", method, "error", err)
+		return
+	}
+	for i := range posts {
+		posts[i].RemixCount = counts[posts[i].ID]
+	}
+}
+
+func (r *PostRepository) GetAll(userID *int, filter models.PostFilter) ([]models.Post, error) {
 	logging.L.Debug("querying posts from DB", "repository", "PostRepository", "method", "GetAll")
 	var pms []postModel
-	if err := r.db.Preload("User").Preload("Slides", func(db *gorm.DB) *gorm.DB {
+	query := r.db.Preload("User").Preload("Session").Preload("Slides", func(db *gorm.DB) *gorm.DB {
 		return db.Order("slides.slide_order ASC")
-	}).Preload("Slides.Flavor").Order("created_at desc").Find(&pms).Error; err != nil {
+	}).Preload("Slides.Flavor").Scopes(visiblePosts)
+	if userID != nil {
+		query = query.Scopes(excludeAuthorsHiddenFrom(*userID), excludeMutedContent(*userID, r.db.NowFunc()))
+	}
+	if err := applyPostFilter(query, filter).Order("created_at desc").Find(&pms).Error; err != nil {
 		logging.L.Error("failed to query posts", "repository", "PostRepository", "method", "GetAll", "error", err)
 		return nil, fmt.Errorf("failed to query all posts: %w", err)
 	}
@@ -87,15 +224,16 @@ func (r *PostRepository) GetAll(userID *int) ([]models.Post, error) {
 		}
 		posts = append(posts, post)
 	}
+	r.attachRemixCounts(posts, "GetAll")
 	return posts, nil
 }
 
 func (r *PostRepository) GetByID(id int, userID *int) (*models.Post, error) {
 	logging.L.Debug("querying post by ID", "repository", "PostRepository", "method", "GetByID", "post_id", id)
 	var pm postModel
-	if err := r.db.Preload("User").Preload("Slides", func(db *gorm.DB) *gorm.DB {
+	if err := r.db.Preload("User").Preload("Session").Preload("Slides", func(db *gorm.DB) *gorm.DB {
 		return db.Order("slides.slide_order ASC")
-	}).Preload("Slides.Flavor").First(&pm, "id = ?", id).Error; err != nil {
+	}).Preload("Slides.Flavor").Scopes(visiblePosts).First(&pm, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logging.L.Debug("post not found", "repository", "PostRepository", "method", "GetByID", "post_id", id)
 			return nil, repositories.ErrPostNotFound
@@ -110,20 +248,94 @@ func (r *PostRepository) GetByID(id int, userID *int) (*models.Post, error) {
 			post.IsLiked = liked
 		}
 	}
+	if counts, err := r.remixCountsByPostID([]int{post.ID}); err != nil {
+		logging.L.Error("failed to fetch remix count", "repository", "PostRepository", "method", "GetByID", "post_id", id, "error", err)
+	} else {
+		post.RemixCount = counts[post.ID]
+	}
 	logging.L.Debug("post found", "repository", "PostRepository", "method", "GetByID", "post_id", id)
 	return &post, nil
 }
 
+func (r *PostRepository) GetByIDs(ids []int, userID *int) ([]models.Post, error) {
+	logging.L.Debug("querying posts by IDs", "repository", "PostRepository", "method", "GetByIDs", "count", len(ids))
+	posts := []models.Post{}
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	var pms []postModel
+	query := r.db.Preload("User").Preload("Session").Preload("Slides", func(db *gorm.DB) *gorm.DB {
+		return db.Order("slides.slide_order ASC")
+	}).Preload("Slides.Flavor").Scopes(visiblePosts)
+	if userID != nil {
+		query = query.Scopes(excludeAuthorsHiddenFrom(*userID), excludeMutedContent(*userID, r.db.NowFunc()))
+	}
+	if err := query.Where("id IN ?", ids).Find(&pms).Error; err != nil {
+		logging.L.Error("failed to query posts by IDs", "repository", "PostRepository", "method", "GetByIDs", "error", err)
+		return nil, fmt.Errorf("failed to query posts by ids: %w", err)
+	}
+
+	likedSet := map[int]bool{}
+	if userID != nil && len(pms) > 0 {
+		var likes []postLikeModel
+		if err := r.db.Where("user_id = ? AND post_id IN ?", *userID, ids).Find(&likes).Error; err != nil {
+			logging.L.Error("failed to fetch like statuses in GetByIDs", "repository", "PostRepository", "method", "GetByIDs", "user_id", *userID, "error", err)
+		} else {
+			for _, l := range likes {
+				likedSet[int(l.PostID)] = true
+			}
+		}
+	}
+
+	byID := make(map[int]models.Post, len(pms))
+	for i := range pms {
+		post := r.toDomain(&pms[i])
+		post.IsLiked = likedSet[post.ID]
+		byID[post.ID] = post
+	}
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	r.attachRemixCounts(posts, "GetByIDs")
+	return posts, nil
+}
+
+func (r *PostRepository) IsVisibleTo(postID, viewerID int) (bool, error) {
+	var count int64
+	if err := r.db.Model(&postModel{}).
+		Scopes(visiblePosts, excludeAuthorsHiddenFrom(viewerID), excludeMutedContent(viewerID, r.db.NowFunc())).
+		Where("posts.id = ?", postID).
+		Count(&count).Error; err != nil {
+		logging.L.Error("failed to check post visibility", "repository", "PostRepository", "method", "IsVisibleTo", "post_id", postID, "viewer_id", viewerID, "error", err)
+		return false, fmt.Errorf("failed to check visibility of post id=%d: %w", postID, err)
+	}
+	return count > 0, nil
+}
+
 func (r *PostRepository) Create(post *models.Post) error {
 	logging.L.Debug("creating post", "repository", "PostRepository", "method", "Create", "user_id", post.UserID)
 
-	// トランザクション内でpostとslidesを作成
+	// トランザクション内でpost・slides・セッション詳細を作成
 	err := r.db.Transaction(func(tx *gorm.DB) error {
 		// Postを作成
 		pm := postModel{
 			UserID: int64(post.UserID),
 			Likes:  post.Likes,
 		}
+		if post.RemixedFrom != nil {
+			// リミックス元の投稿者にブロックされている場合はリミックスできない
+			blocked, err := isBlockedByPostOwner(tx, post.UserID, *post.RemixedFrom)
+			if err != nil {
+				return err
+			}
+			if blocked {
+				return repositories.ErrBlocked
+			}
+			remixedFrom := int64(*post.RemixedFrom)
+			pm.RemixedFrom = &remixedFrom
+		}
 		if err := tx.Create(&pm).Error; err != nil {
 			return fmt.Errorf("failed to create post: %w", err)
 		}
@@ -146,12 +358,35 @@ func (r *PostRepository) Create(post *models.Post) error {
 			post.Slides[i].ID = int(sm.ID)
 		}
 
+		// セッション詳細は入力がある場合のみ作成する
+		if !post.Session.IsEmpty() {
+			if err := tx.Create(toSessionModel(pm.ID, post.Session)).Error; err != nil {
+				return fmt.Errorf("failed to create post session: %w", err)
+			}
+		}
+
 		post.ID = int(pm.ID)
 		post.CreatedAt = pm.CreatedAt
-		return nil
+
+		imageURLs := []string{}
+		for _, slide := range post.Slides {
+			if slide.ImageURL != "" {
+				imageURLs = append(imageURLs, slide.ImageURL)
+			}
+		}
+		return writeOutboxEvent(tx, models.DomainEventPostCreated, models.PostCreatedEvent{
+			PostID:      post.ID,
+			UserID:      post.UserID,
+			RemixedFrom: post.RemixedFrom,
+			ImageURLs:   imageURLs,
+		})
 	})
 
 	if err != nil {
+		if errors.Is(err, repositories.ErrBlocked) {
+			logging.L.Debug("remix rejected: blocked by source post owner", "repository", "PostRepository", "method", "Create", "user_id", post.UserID, "remixed_from", *post.RemixedFrom)
+			return repositories.ErrBlocked
+		}
 		logging.L.Error("failed to create post", "repository", "PostRepository", "method", "Create", "user_id", post.UserID, "error", err)
 		return err
 	}
@@ -193,6 +428,13 @@ func (r *PostRepository) HasLiked(userID, postID int) (bool, error) {
 func (r *PostRepository) AddLike(userID, postID int) error {
 	logging.L.Debug("adding like", "repository", "PostRepository", "method", "AddLike", "user_id", userID, "post_id", postID)
 	err := r.db.Transaction(func(tx *gorm.DB) error {
+		blocked, err := isBlockedByPostOwner(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return repositories.ErrBlocked
+		}
 		if err := tx.Create(&postLikeModel{UserID: int64(userID), PostID: int64(postID)}).Error; err != nil {
 			if errors.Is(err, gorm.ErrDuplicatedKey) {
 				return repositories.ErrAlreadyLiked
@@ -212,7 +454,8 @@ func (r *PostRepository) AddLike(userID, postID int) error {
 			}
 			return fmt.Errorf("failed to insert post_like: %w", err)
 		}
-		result := tx.Model(&postModel{}).Where("id = ?", postID).
+		// 非表示の投稿にはいいねできない（post_likes の挿入もロールバックされる）
+		result := tx.Model(&postModel{}).Where("id = ? AND hidden_at IS NULL", postID).
 			UpdateColumn("likes", gorm.Expr("likes + 1"))
 		if result.Error != nil {
 			return fmt.Errorf("failed to increment likes: %w", result.Error)
@@ -220,7 +463,16 @@ func (r *PostRepository) AddLike(userID, postID int) error {
 		if result.RowsAffected == 0 {
 			return repositories.ErrPostNotFound
 		}
-		return nil
+		var pm postModel
+		if err := tx.Select("id", "user_id", "likes").First(&pm, "id = ?", postID).Error; err != nil {
+			return fmt.Errorf("failed to read liked post: %w", err)
+		}
+		return writeOutboxEvent(tx, models.DomainEventLikeAdded, models.LikeAddedEvent{
+			PostID:      postID,
+			UserID:      userID,
+			PostOwnerID: int(pm.UserID),
+			Likes:       pm.Likes,
+		})
 	})
 	if err != nil {
 		if errors.Is(err, repositories.ErrAlreadyLiked) {
@@ -235,6 +487,10 @@ func (r *PostRepository) AddLike(userID, postID int) error {
 			logging.L.Debug("user not found for like (deleted?)", "repository", "PostRepository", "method", "AddLike", "user_id", userID)
 			return repositories.ErrUserNotFound
 		}
+		if errors.Is(err, repositories.ErrBlocked) {
+			logging.L.Debug("like rejected: blocked by post owner", "repository", "PostRepository", "method", "AddLike", "user_id", userID, "post_id", postID)
+			return repositories.ErrBlocked
+		}
 		logging.L.Error("failed to add like", "repository", "PostRepository", "method", "AddLike", "user_id", userID, "post_id", postID, "error", err)
 		return err
 	}
@@ -295,33 +551,48 @@ func (r *PostRepository) DeletePost(userID, postID int) error {
 		return repositories.ErrForbidden
 	}
 
-	// 論理削除
-	result := r.db.Delete(&pm)
-	if result.Error != nil {
-		logging.L.Error("failed to soft-delete post", "repository", "PostRepository", "method", "DeletePost", "post_id", postID, "error", result.Error)
-		return fmt.Errorf("failed to delete post id=%d: %w", postID, result.Error)
-	}
-	if result.RowsAffected == 0 {
-		logging.L.Debug("post already deleted or not found", "repository", "PostRepository", "method", "DeletePost", "post_id", postID)
-		return repositories.ErrPostNotFound
+	// 論理削除とリミックス参照の解除を同一トランザクションで行う
+	// 元投稿が削除されたリミックス投稿は remixed_from を NULL にして参照だけを外す
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&pm)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete post id=%d: %w", postID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrPostNotFound
+		}
+		if err := tx.Model(&postModel{}).Where("remixed_from = ?", postID).
+			UpdateColumn("remixed_from", nil).Error; err != nil {
+			return fmt.Errorf("failed to detach remixes of post id=%d: %w", postID, err)
+		}
+		return writeOutboxEvent(tx, models.DomainEventPostDeleted, models.PostDeletedEvent{PostID: postID, UserID: userID})
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrPostNotFound) {
+			logging.L.Debug("post already deleted or not found", "repository", "PostRepository", "method", "DeletePost", "post_id", postID)
+			return repositories.ErrPostNotFound
+		}
+		logging.L.Error("failed to soft-delete post", "repository", "PostRepository", "method", "DeletePost", "post_id", postID, "error", err)
+		return err
 	}
 
 	logging.L.Info("post soft-deleted", "repository", "PostRepository", "method", "DeletePost", "post_id", postID, "user_id", userID)
 	return nil
 }
 
-// UpdatePost は postID を指定して投稿のスライドの text/flavor_id を更新する
+// UpdatePost は postID を指定して投稿のスライドの text/flavor_id とセッション詳細を更新する
+// session が nil の場合はセッション詳細を変更せず、全項目が空の場合はセッション詳細を削除する
 // 投稿が存在しない場合は ErrPostNotFound を返す
 // 投稿が userID に紐づかない場合は ErrForbidden を返す
 // スライド枚数が既存と一致しない場合は ErrSlideCountMismatch を返す
 // 入力スライドIDが重複している場合は ErrDuplicateSlideID を返す
 // 入力スライドIDが対象投稿に属さない場合は ErrSlideNotBelongToPost を返す
-func (r *PostRepository) UpdatePost(userID, postID int, slides []models.UpdateSlideInput) (*models.Post, error) {
+func (r *PostRepository) UpdatePost(userID, postID int, slides []models.UpdateSlideInput, session *models.ShishaSession) (*models.Post, error) {
 	logging.L.Debug("updating post slides", "repository", "PostRepository", "method", "UpdatePost", "post_id", postID, "user_id", userID)
 
 	// まず投稿の存在を確認する
 	var pm postModel
-	if err := r.db.First(&pm, "id = ?", postID).Error; err != nil {
+	if err := r.db.Scopes(visiblePosts).First(&pm, "id = ?", postID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logging.L.Debug("post not found for update", "repository", "PostRepository", "method", "UpdatePost", "post_id", postID)
 			return nil, repositories.ErrPostNotFound
@@ -379,6 +650,23 @@ func (r *PostRepository) UpdatePost(userID, postID int, slides []models.UpdateSl
 				return fmt.Errorf("failed to update slide id=%d: %w", sm.ID, err)
 			}
 		}
+
+		if session == nil {
+			return nil
+		}
+		if session.IsEmpty() {
+			if err := tx.Where("post_id = ?", pm.ID).Delete(&postSessionModel{}).Error; err != nil {
+				return fmt.Errorf("failed to delete post session: %w", err)
+			}
+			return nil
+		}
+		// 既存のセッション詳細があれば全項目を上書きする
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "post_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"bowl_type", "heat_management", "coal_count", "duration_minutes", "rating", "updated_at"}),
+		}).Create(toSessionModel(pm.ID, session)).Error; err != nil {
+			return fmt.Errorf("failed to upsert post session: %w", err)
+		}
 		return nil
 	})
 	if err != nil {
@@ -400,9 +688,13 @@ func (r *PostRepository) UpdatePost(userID, postID int, slides []models.UpdateSl
 func (r *PostRepository) GetByUserID(userID int, currentUserID *int) ([]models.Post, error) {
 	logging.L.Debug("querying posts by user ID", "repository", "PostRepository", "method", "GetByUserID", "user_id", userID)
 	var pms []postModel
-	if err := r.db.Preload("User").Preload("Slides", func(db *gorm.DB) *gorm.DB {
+	query := r.db.Preload("User").Preload("Session").Preload("Slides", func(db *gorm.DB) *gorm.DB {
 		return db.Order("slides.slide_order ASC")
-	}).Preload("Slides.Flavor").Where("user_id = ?", userID).Order("created_at desc").Find(&pms).Error; err != nil {
+	}).Preload("Slides.Flavor").Scopes(visiblePosts)
+	if currentUserID != nil {
+		query = query.Scopes(excludeAuthorsHiddenFrom(*currentUserID), excludeMutedContent(*currentUserID, r.db.NowFunc()))
+	}
+	if err := query.Where("user_id = ?", userID).Order("created_at desc").Find(&pms).Error; err != nil {
 		logging.L.Error("failed to query posts by user", "repository", "PostRepository", "method", "GetByUserID", "user_id", userID, "error", err)
 		return nil, fmt.Errorf("failed to query posts by user_id=%d: %w", userID, err)
 	}
@@ -431,5 +723,6 @@ func (r *PostRepository) GetByUserID(userID int, currentUserID *int) ([]models.P
 		post.IsLiked = likedSet[post.ID]
 		posts = append(posts, post)
 	}
+	r.attachRemixCounts(posts, "GetByUserID")
 	return posts, nil
 }
@@ -38,9 +38,25 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// AutoMigrate schema for tests
-	if err := db.AutoMigrate(&userModel{}, &postModel{}, &slideModel{}, &flavorModel{}, &postLikeModel{}); err != nil {
+	if err := db.AutoMigrate(&userModel{}, &postModel{}, &slideModel{}, &flavorModel{}, &postLikeModel{}, &postSessionModel{}, &userBadgeModel{}, &collectionModel{}, &collectionItemModel{}, &notificationModel{}, &notificationActorModel{}, &webhookEndpointModel{}, &webhookDeliveryModel{}, &outboxEventModel{}, &reportModel{}, &moderationActionModel{}, &userBlockModel{}, &userMuteModel{}, &muteRuleModel{}, &revokedAccessTokenModel{}, &passwordResetTokenModel{}, &userTOTPModel{}, &mfaRecoveryCodeModel{}, &passkeyCredentialModel{}, &userIdentityModel{}, &loginFailureModel{}); err != nil {
 		t.Fatalf("failed to migrate schema: %v", err)
 	}
+	// models.RefreshToken は DEFAULT now() を含み SQLite で AutoMigrate できないため、テーブルを直接作成する
+	if err := db.Exec(`CREATE TABLE refresh_tokens (
+		id integer PRIMARY KEY AUTOINCREMENT,
+		user_id integer NOT NULL,
+		token_hash text NOT NULL UNIQUE,
+		expires_at datetime NOT NULL,
+		created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		last_used_at datetime,
+		family_id text NOT NULL DEFAULT '',
+		rotated_at datetime,
+		user_agent text NOT NULL DEFAULT '',
+		ip_address text NOT NULL DEFAULT '',
+		session_started_at datetime
+	)`).Error; err != nil {
+		t.Fatalf("failed to create refresh_tokens: %v", err)
+	}
 	return db
 }
 
@@ -109,7 +125,7 @@ func TestGetAllAndGetByUserIDOrdering(t *testing.T) {
 		t.Fatalf("Create p2 failed: %v", err)
 	}
 
-	all, err := repo.GetAll(nil)
+	all, err := repo.GetAll(nil, models.PostFilter{})
 	if err != nil {
 		t.Fatalf("GetAll failed: %v", err)
 	}
@@ -309,7 +325,7 @@ func TestGetAll_IsLiked(t *testing.T) {
 	userID, postID := setupPostAndUser(t, db)
 
 	// いいね前は is_liked=false
-	postsBeforeLike, err := repo.GetAll(&userID)
+	postsBeforeLike, err := repo.GetAll(&userID, models.PostFilter{})
 	if err != nil {
 		t.Fatalf("GetAll failed: %v", err)
 	}
@@ -330,7 +346,7 @@ func TestGetAll_IsLiked(t *testing.T) {
 	if err := repo.AddLike(userID, postID); err != nil {
 		t.Fatalf("AddLike failed: %v", err)
 	}
-	postsAfterLike, err := repo.GetAll(&userID)
+	postsAfterLike, err := repo.GetAll(&userID, models.PostFilter{})
 	if err != nil {
 		t.Fatalf("GetAll after like failed: %v", err)
 	}
@@ -341,7 +357,7 @@ func TestGetAll_IsLiked(t *testing.T) {
 	}
 
 	// userID=nil のとき is_liked=false
-	postsNoUser, err := repo.GetAll(nil)
+	postsNoUser, err := repo.GetAll(nil, models.PostFilter{})
 	if err != nil {
 		t.Fatalf("GetAll(nil) failed: %v", err)
 	}
@@ -485,7 +501,7 @@ func TestDeletePost_NotInGetAll(t *testing.T) {
 	}
 
 	// 論理削除後はGetAllに含まれないこと
-	posts, err := repo.GetAll(nil)
+	posts, err := repo.GetAll(nil, models.PostFilter{})
 	if err != nil {
 		t.Fatalf("GetAll failed: %v", err)
 	}
@@ -545,7 +561,7 @@ func TestUpdatePost_UpdatesBySlideID(t *testing.T) {
 	updated, err := repo.UpdatePost(1, p.ID, []models.UpdateSlideInput{
 		{ID: p.Slides[1].ID, Text: "after-2", FlavorID: &flavorID},
 		{ID: p.Slides[0].ID, Text: "after-1", FlavorID: nil},
-	})
+	}, nil)
 	if err != nil {
 		t.Fatalf("UpdatePost failed: %v", err)
 	}
@@ -607,7 +623,7 @@ func TestUpdatePost_ReturnsErrorWhenSlideBelongsToAnotherPost(t *testing.T) {
 
 	_, err := repo.UpdatePost(1, post1.ID, []models.UpdateSlideInput{
 		{ID: post2.Slides[0].ID, Text: "tampered"},
-	})
+	}, nil)
 	if !errors.Is(err, repositories.ErrSlideNotBelongToPost) {
 		t.Fatalf("expected ErrSlideNotBelongToPost, got %v", err)
 	}
@@ -636,8 +652,197 @@ func TestUpdatePost_ReturnsDuplicateSlideIDOnDuplicateSlideID(t *testing.T) {
 	_, err := repo.UpdatePost(1, p.ID, []models.UpdateSlideInput{
 		{ID: p.Slides[0].ID, Text: "changed"},
 		{ID: p.Slides[0].ID, Text: "changed-again"},
-	})
+	}, nil)
 	if !errors.Is(err, repositories.ErrDuplicateSlideID) {
 		t.Fatalf("expected ErrDuplicateSlideID, got %v", err)
 	}
 }
+
+// TestRemix_CountAndDetachOnSourceDelete はリミックス数の集計と、元投稿削除時に参照が外れることを検証する。
+func TestRemix_CountAndDetachOnSourceDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostRepository(db)
+	userID, sourceID := setupPostAndUser(t, db)
+
+	remix := &models.Post{UserID: userID, RemixedFrom: &sourceID, Slides: []models.Slide{{Text: "remix"}}}
+	if err := repo.Create(remix); err != nil {
+		t.Fatalf("Create remix failed: %v", err)
+	}
+
+	source, err := repo.GetByID(sourceID, nil)
+	if err != nil {
+		t.Fatalf("GetByID source failed: %v", err)
+	}
+	if source.RemixCount != 1 {
+		t.Fatalf("expected remix_count=1, got %d", source.RemixCount)
+	}
+
+	got, err := repo.GetByID(remix.ID, nil)
+	if err != nil {
+		t.Fatalf("GetByID remix failed: %v", err)
+	}
+	if got.RemixedFrom == nil || *got.RemixedFrom != sourceID {
+		t.Fatalf("expected remixed_from=%d, got %v", sourceID, got.RemixedFrom)
+	}
+
+	all, err := repo.GetAll(nil, models.PostFilter{})
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	for _, p := range all {
+		if p.ID == sourceID && p.RemixCount != 1 {
+			t.Fatalf("expected remix_count=1 in GetAll, got %d", p.RemixCount)
+		}
+	}
+
+	// 元投稿を削除するとリミックス投稿は残り、参照だけが外れること
+	if err := repo.DeletePost(userID, sourceID); err != nil {
+		t.Fatalf("DeletePost failed: %v", err)
+	}
+	got, err = repo.GetByID(remix.ID, nil)
+	if err != nil {
+		t.Fatalf("GetByID remix after source delete failed: %v", err)
+	}
+	if got.RemixedFrom != nil {
+		t.Fatalf("expected remixed_from to be cleared, got %v", *got.RemixedFrom)
+	}
+}
+
+// TestRemix_DeletedRemixNotCounted は削除済みのリミックス投稿がリミックス数に含まれないことを検証する。
+func TestRemix_DeletedRemixNotCounted(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostRepository(db)
+	userID, sourceID := setupPostAndUser(t, db)
+
+	remix := &models.Post{UserID: userID, RemixedFrom: &sourceID, Slides: []models.Slide{{Text: "remix"}}}
+	if err := repo.Create(remix); err != nil {
+		t.Fatalf("Create remix failed: %v", err)
+	}
+	if err := repo.DeletePost(userID, remix.ID); err != nil {
+		t.Fatalf("DeletePost remix failed: %v", err)
+	}
+
+	posts, err := repo.GetByUserID(userID, nil)
+	if err != nil {
+		t.Fatalf("GetByUserID failed: %v", err)
+	}
+	for _, p := range posts {
+		if p.ID == sourceID && p.RemixCount != 0 {
+			t.Fatalf("expected remix_count=0 after remix deletion, got %d", p.RemixCount)
+		}
+	}
+}
+
+// TestSession_CreateUpdateAndClear はセッション詳細の作成・上書き・未変更・削除を検証する。
+func TestSession_CreateUpdateAndClear(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com", DisplayName: "u1"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	bowl := "ファンネル"
+	rating := 4
+	p := &models.Post{
+		UserID:  1,
+		Slides:  []models.Slide{{ImageURL: "/img1.jpg", Text: "s1"}},
+		Session: &models.ShishaSession{BowlType: &bowl, Rating: &rating},
+	}
+	if err := repo.Create(p); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	got, err := repo.GetByID(p.ID, nil)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.Session == nil || *got.Session.BowlType != bowl || *got.Session.Rating != 4 || got.Session.CoalCount != nil {
+		t.Fatalf("unexpected session after create: %+v", got.Session)
+	}
+
+	slides := []models.UpdateSlideInput{{ID: p.Slides[0].ID, Text: "s1"}}
+
+	// 上書き時は指定しなかった項目が消えること
+	coals := 3
+	updated, err := repo.UpdatePost(1, p.ID, slides, &models.ShishaSession{CoalCount: &coals})
+	if err != nil {
+		t.Fatalf("UpdatePost failed: %v", err)
+	}
+	if updated.Session == nil || updated.Session.BowlType != nil || updated.Session.Rating != nil || *updated.Session.CoalCount != 3 {
+		t.Fatalf("unexpected session after overwrite: %+v", updated.Session)
+	}
+
+	// nil の場合は変更されないこと
+	updated, err = repo.UpdatePost(1, p.ID, slides, nil)
+	if err != nil {
+		t.Fatalf("UpdatePost without session failed: %v", err)
+	}
+	if updated.Session == nil || *updated.Session.CoalCount != 3 {
+		t.Fatalf("expected session to be unchanged, got %+v", updated.Session)
+	}
+
+	// 空オブジェクトの場合は削除されること
+	updated, err = repo.UpdatePost(1, p.ID, slides, &models.ShishaSession{})
+	if err != nil {
+		t.Fatalf("UpdatePost with empty session failed: %v", err)
+	}
+	if updated.Session != nil {
+		t.Fatalf("expected session to be cleared, got %+v", updated.Session)
+	}
+}
+
+// TestGetAll_SessionFilter はセッション詳細による絞り込みを検証する。
+func TestGetAll_SessionFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com", DisplayName: "u1"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	funnel, clay := "ファンネル", "クレイ"
+	hmd := "ロータス"
+	r3, r5 := 3, 5
+	posts := []*models.Post{
+		{UserID: 1, Slides: []models.Slide{{Text: "low"}}, Session: &models.ShishaSession{BowlType: &funnel, Rating: &r3}},
+		{UserID: 1, Slides: []models.Slide{{Text: "high"}}, Session: &models.ShishaSession{BowlType: &clay, HeatManagement: &hmd, Rating: &r5}},
+		{UserID: 1, Slides: []models.Slide{{Text: "none"}}},
+	}
+	for _, p := range posts {
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	minRating := 4
+	cases := []struct {
+		name    string
+		filter  models.PostFilter
+		wantIDs []int
+	}{
+		{"no filter", models.PostFilter{}, []int{posts[2].ID, posts[1].ID, posts[0].ID}},
+		{"min rating", models.PostFilter{MinRating: &minRating}, []int{posts[1].ID}},
+		{"bowl type", models.PostFilter{BowlType: &funnel}, []int{posts[0].ID}},
+		{"heat management", models.PostFilter{HeatManagement: &hmd}, []int{posts[1].ID}},
+		{"combined no match", models.PostFilter{BowlType: &funnel, MinRating: &minRating}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := repo.GetAll(nil, tc.filter)
+			if err != nil {
+				t.Fatalf("GetAll failed: %v", err)
+			}
+			if len(got) != len(tc.wantIDs) {
+				t.Fatalf("expected %d posts, got %d", len(tc.wantIDs), len(got))
+			}
+			ids := map[int]bool{}
+			for _, p := range got {
+				ids[p.ID] = true
+			}
+			for _, id := range tc.wantIDs {
+				if !ids[id] {
+					t.Fatalf("expected post %d in result", id)
+				}
+			}
+		})
+	}
+}
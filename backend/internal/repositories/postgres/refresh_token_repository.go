@@ -3,6 +3,7 @@ package postgres
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"go-shisha-backend/internal/models"
 	"go-shisha-backend/pkg/logging"
 	"time"
@@ -10,12 +11,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrRefreshTokenRotated はローテーション済みのRefreshTokenを再度ローテーションしようとした場合のエラー
+var ErrRefreshTokenRotated = errors.New("refresh token already rotated")
+
 // RefreshTokenRepository はRefreshTokenのリポジトリインターフェース
 type RefreshTokenRepository interface {
 	Create(token *models.RefreshToken, rawToken string) error
+	// FindByTokenHash は有効期限内のRefreshTokenを返す（再利用の検知のため、ローテーション済みのものも返す）
 	FindByTokenHash(tokenHash string) (*models.RefreshToken, error)
+	// Rotate は current をローテーション済みにし、同じファミリーの next を作成する（SessionStartedAt も引き継ぐ）
+	// current が既にローテーション済みの場合は ErrRefreshTokenRotated を返す
+	Rotate(current *models.RefreshToken, next *models.RefreshToken, rawNext string) error
 	UpdateLastUsed(id int64) error
 	DeleteByUserID(userID int64) error
+	// DeleteByFamilyID はファミリーのRefreshTokenをすべて削除する
+	DeleteByFamilyID(familyID string) error
+	// ListActiveByUserID はユーザーの有効期限内でローテーションしていないRefreshToken（ログイン中のセッションごとに1件）を新しい順に返す
+	ListActiveByUserID(userID int64) ([]models.RefreshToken, error)
+	// DeleteByUserIDAndFamilyID はユーザーのファミリーのRefreshTokenをすべて削除し、削除した件数を返す
+	DeleteByUserIDAndFamilyID(userID int64, familyID string) (int64, error)
 	DeleteExpired() error
 }
 
@@ -28,11 +42,15 @@ func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
 	return &refreshTokenRepository{db: db}
 }
 
+// hashToken はトークンをSHA256でハッシュ化する（Refresh Token・パスワードリセットのトークン）
+func hashToken(rawToken string) string {
+	hash := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(hash[:])
+}
+
 // Create は新しいRefreshTokenを作成（トークンをSHA256でハッシュ化して保存）
 func (r *refreshTokenRepository) Create(token *models.RefreshToken, rawToken string) error {
-	// トークンをSHA256でハッシュ化
-	hash := sha256.Sum256([]byte(rawToken))
-	token.TokenHash = hex.EncodeToString(hash[:])
+	token.TokenHash = hashToken(rawToken)
 
 	if err := r.db.Create(token).Error; err != nil {
 		logging.L.Error("failed to create refresh token",
@@ -53,9 +71,7 @@ func (r *refreshTokenRepository) Create(token *models.RefreshToken, rawToken str
 
 // FindByTokenHash はトークンハッシュでRefreshTokenを検索
 func (r *refreshTokenRepository) FindByTokenHash(rawToken string) (*models.RefreshToken, error) {
-	// トークンをSHA256でハッシュ化
-	hash := sha256.Sum256([]byte(rawToken))
-	tokenHash := hex.EncodeToString(hash[:])
+	tokenHash := hashToken(rawToken)
 
 	var token models.RefreshToken
 
@@ -81,6 +97,49 @@ func (r *refreshTokenRepository) FindByTokenHash(rawToken string) (*models.Refre
 	return &token, nil
 }
 
+// Rotate は current をローテーション済みにし、同じファミリーの next を作成する
+func (r *refreshTokenRepository) Rotate(current *models.RefreshToken, next *models.RefreshToken, rawNext string) error {
+	now := time.Now()
+	next.FamilyID = current.FamilyID
+	next.SessionStartedAt = current.SessionStartedAt
+	next.TokenHash = hashToken(rawNext)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 同じトークンでの同時リフレッシュは、先にローテーションした方だけを成功させる
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL", current.ID).
+			Updates(map[string]interface{}{"rotated_at": now, "last_used_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenRotated
+		}
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenRotated) {
+			return err
+		}
+		logging.L.Error("failed to rotate refresh token",
+			"repository", "RefreshTokenRepository",
+			"method", "Rotate",
+			"token_id", current.ID,
+			"user_id", current.UserID,
+			"error", err)
+		return err
+	}
+	current.RotatedAt = &now
+
+	logging.L.Debug("refresh token rotated",
+		"repository", "RefreshTokenRepository",
+		"method", "Rotate",
+		"token_id", current.ID,
+		"new_token_id", next.ID,
+		"user_id", current.UserID)
+	return nil
+}
+
 // UpdateLastUsed はトークンの最終使用時刻を更新
 func (r *refreshTokenRepository) UpdateLastUsed(id int64) error {
 	now := time.Now()
@@ -118,6 +177,62 @@ func (r *refreshTokenRepository) DeleteByUserID(userID int64) error {
 	return nil
 }
 
+// DeleteByFamilyID はファミリーのRefreshTokenをすべて削除
+func (r *refreshTokenRepository) DeleteByFamilyID(familyID string) error {
+	if err := r.db.Where("family_id = ?", familyID).Delete(&models.RefreshToken{}).Error; err != nil {
+		logging.L.Error("failed to delete refresh token family",
+			"repository", "RefreshTokenRepository",
+			"method", "DeleteByFamilyID",
+			"family_id", familyID,
+			"error", err)
+		return err
+	}
+
+	logging.L.Info("refresh token family deleted",
+		"repository", "RefreshTokenRepository",
+		"method", "DeleteByFamilyID",
+		"family_id", familyID)
+	return nil
+}
+
+// ListActiveByUserID はユーザーのログイン中のセッションの現在のRefreshTokenを返す
+func (r *refreshTokenRepository) ListActiveByUserID(userID int64) ([]models.RefreshToken, error) {
+	var tokens []models.RefreshToken
+	if err := r.db.Where("user_id = ? AND rotated_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC, id DESC").
+		Find(&tokens).Error; err != nil {
+		logging.L.Error("failed to list active refresh tokens",
+			"repository", "RefreshTokenRepository",
+			"method", "ListActiveByUserID",
+			"user_id", userID,
+			"error", err)
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// DeleteByUserIDAndFamilyID はユーザーのファミリーのRefreshTokenをすべて削除
+func (r *refreshTokenRepository) DeleteByUserIDAndFamilyID(userID int64, familyID string) (int64, error) {
+	result := r.db.Where("user_id = ? AND family_id = ?", userID, familyID).Delete(&models.RefreshToken{})
+	if result.Error != nil {
+		logging.L.Error("failed to delete refresh token family",
+			"repository", "RefreshTokenRepository",
+			"method", "DeleteByUserIDAndFamilyID",
+			"user_id", userID,
+			"family_id", familyID,
+			"error", result.Error)
+		return 0, result.Error
+	}
+
+	logging.L.Info("refresh token family deleted",
+		"repository", "RefreshTokenRepository",
+		"method", "DeleteByUserIDAndFamilyID",
+		"user_id", userID,
+		"family_id", familyID,
+		"count", result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
 // DeleteExpired は有効期限切れのRefreshTokenを削除
 func (r *refreshTokenRepository) DeleteExpired() error {
 	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{}).Error; err != nil {
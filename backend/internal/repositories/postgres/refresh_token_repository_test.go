@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+func TestRefreshToken_RotateKeepsFamily(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRefreshTokenRepository(db)
+
+	current := &models.RefreshToken{UserID: 1, ExpiresAt: time.Now().Add(time.Hour), FamilyID: "family-1"}
+	if err := repo.Create(current, "raw-1"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	next := &models.RefreshToken{UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repo.Rotate(current, next, "raw-2"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if next.ID == 0 || next.FamilyID != "family-1" || current.RotatedAt == nil {
+		t.Fatalf("unexpected rotation: current=%+v next=%+v", current, next)
+	}
+
+	// ローテーション済みのトークンも再利用の検知のため検索できる
+	found, err := repo.FindByTokenHash("raw-1")
+	if err != nil || found.RotatedAt == nil {
+		t.Fatalf("expected rotated token to be found, got %+v (err=%v)", found, err)
+	}
+	// 同じトークンを再度ローテーションすることはできない
+	if err := repo.Rotate(found, &models.RefreshToken{UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, "raw-3"); !errors.Is(err, ErrRefreshTokenRotated) {
+		t.Fatalf("expected ErrRefreshTokenRotated, got %v", err)
+	}
+	if _, err := repo.FindByTokenHash("raw-3"); err == nil {
+		t.Fatal("expected failed rotation not to create a token")
+	}
+}
+
+func TestRefreshToken_DeleteByFamilyID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRefreshTokenRepository(db)
+
+	for raw, family := range map[string]string{"a1": "family-a", "a2": "family-a", "b1": "family-b"} {
+		if err := repo.Create(&models.RefreshToken{UserID: 1, ExpiresAt: time.Now().Add(time.Hour), FamilyID: family}, raw); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if err := repo.DeleteByFamilyID("family-a"); err != nil {
+		t.Fatalf("DeleteByFamilyID failed: %v", err)
+	}
+	for _, raw := range []string{"a1", "a2"} {
+		if _, err := repo.FindByTokenHash(raw); err == nil {
+			t.Fatalf("expected token %s to be deleted", raw)
+		}
+	}
+	if _, err := repo.FindByTokenHash("b1"); err != nil {
+		t.Fatalf("expected other family to remain, got %v", err)
+	}
+}
+
+func TestRefreshToken_ListActiveAndDeleteSession(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRefreshTokenRepository(db)
+
+	started := time.Now().Add(-time.Hour)
+	current := &models.RefreshToken{UserID: 1, ExpiresAt: time.Now().Add(time.Hour), FamilyID: "phone", UserAgent: "iPhone", IPAddress: "203.0.113.1", SessionStartedAt: started}
+	if err := repo.Create(current, "phone-1"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	rotated := &models.RefreshToken{UserID: 1, ExpiresAt: time.Now().Add(time.Hour), UserAgent: "iPhone", IPAddress: "203.0.113.9"}
+	if err := repo.Rotate(current, rotated, "phone-2"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	for raw, token := range map[string]*models.RefreshToken{
+		"laptop":  {UserID: 1, ExpiresAt: time.Now().Add(time.Hour), FamilyID: "laptop"},
+		"expired": {UserID: 1, ExpiresAt: time.Now().Add(-time.Minute), FamilyID: "expired"},
+		"other":   {UserID: 2, ExpiresAt: time.Now().Add(time.Hour), FamilyID: "other"},
+	} {
+		if err := repo.Create(token, raw); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// ローテーション済み・期限切れ・他のユーザーのトークンは含めない
+	tokens, err := repo.ListActiveByUserID(1)
+	if err != nil {
+		t.Fatalf("ListActiveByUserID failed: %v", err)
+	}
+	families := map[string]models.RefreshToken{}
+	for _, token := range tokens {
+		families[token.FamilyID] = token
+	}
+	if len(tokens) != 2 || families["phone"].IPAddress != "203.0.113.9" || !families["phone"].SessionStartedAt.Equal(started) {
+		t.Fatalf("unexpected active tokens: %+v", tokens)
+	}
+
+	if deleted, err := repo.DeleteByUserIDAndFamilyID(2, "phone"); err != nil || deleted != 0 {
+		t.Fatalf("expected other user's delete to affect nothing, got %d (err=%v)", deleted, err)
+	}
+	if deleted, err := repo.DeleteByUserIDAndFamilyID(1, "phone"); err != nil || deleted != 2 {
+		t.Fatalf("expected both tokens of the family to be deleted, got %d (err=%v)", deleted, err)
+	}
+}
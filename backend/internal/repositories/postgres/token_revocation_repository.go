@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type TokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRevocationRepository(db *gorm.DB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{db: db}
+}
+
+func (r *TokenRevocationRepository) RevokeAccessToken(jti string, userID int, expiresAt time.Time) error {
+	rm := revokedAccessTokenModel{
+		JTI:       jti,
+		UserID:    int64(userID),
+		ExpiresAt: expiresAt,
+		RevokedAt: r.db.NowFunc(),
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rm).Error; err != nil {
+		logging.L.Error("failed to revoke access token", "repository", "TokenRevocationRepository", "method", "RevokeAccessToken", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	logging.L.Info("access token revoked", "repository", "TokenRevocationRepository", "method", "RevokeAccessToken", "user_id", userID)
+	return nil
+}
+
+func (r *TokenRevocationRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&revokedAccessTokenModel{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		logging.L.Error("failed to query revoked access token", "repository", "TokenRevocationRepository", "method", "IsAccessTokenRevoked", "error", err)
+		return false, fmt.Errorf("failed to query revoked access token: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *TokenRevocationRepository) ConsumeToken(jti string, userID int, expiresAt time.Time) (bool, error) {
+	rm := revokedAccessTokenModel{
+		JTI:       jti,
+		UserID:    int64(userID),
+		ExpiresAt: expiresAt,
+		RevokedAt: r.db.NowFunc(),
+	}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rm)
+	if result.Error != nil {
+		logging.L.Error("failed to consume token", "repository", "TokenRevocationRepository", "method", "ConsumeToken", "user_id", userID, "error", result.Error)
+		return false, fmt.Errorf("failed to consume token: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *TokenRevocationRepository) IncrementTokenVersion(userID int) error {
+	result := r.db.Model(&userModel{}).Where("id = ?", userID).Update("token_version", gorm.Expr("token_version + 1"))
+	if result.Error != nil {
+		logging.L.Error("failed to increment token version", "repository", "TokenRevocationRepository", "method", "IncrementTokenVersion", "user_id", userID, "error", result.Error)
+		return fmt.Errorf("failed to increment token version of user id=%d: %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrUserNotFound
+	}
+	logging.L.Info("token version incremented", "repository", "TokenRevocationRepository", "method", "IncrementTokenVersion", "user_id", userID)
+	return nil
+}
+
+func (r *TokenRevocationRepository) DeleteExpiredRevocations(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&revokedAccessTokenModel{})
+	if result.Error != nil {
+		logging.L.Error("failed to delete expired revocations", "repository", "TokenRevocationRepository", "method", "DeleteExpiredRevocations", "error", result.Error)
+		return 0, fmt.Errorf("failed to delete expired revocations: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logging.L.Debug("expired revocations deleted", "repository", "TokenRevocationRepository", "method", "DeleteExpiredRevocations", "count", result.RowsAffected)
+	}
+	return result.RowsAffected, nil
+}
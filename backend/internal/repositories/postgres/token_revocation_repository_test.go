@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestTokenRevocation_RevokeAndExpire(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTokenRevocationRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	now := time.Now()
+	if err := repo.RevokeAccessToken("jti-old", 1, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("RevokeAccessToken failed: %v", err)
+	}
+	if err := repo.RevokeAccessToken("jti-new", 1, now.Add(15*time.Minute)); err != nil {
+		t.Fatalf("RevokeAccessToken failed: %v", err)
+	}
+	// 同じ jti の再度の無効化はエラーにしない
+	if err := repo.RevokeAccessToken("jti-new", 1, now.Add(15*time.Minute)); err != nil {
+		t.Fatalf("expected repeated revoke to succeed, got %v", err)
+	}
+
+	for jti, want := range map[string]bool{"jti-old": true, "jti-new": true, "jti-unknown": false} {
+		got, err := repo.IsAccessTokenRevoked(jti)
+		if err != nil || got != want {
+			t.Fatalf("IsAccessTokenRevoked(%q) = %v (err=%v), want %v", jti, got, err, want)
+		}
+	}
+
+	deleted, err := repo.DeleteExpiredRevocations(now)
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected 1 expired revocation to be deleted, got %d (err=%v)", deleted, err)
+	}
+	if revoked, _ := repo.IsAccessTokenRevoked("jti-new"); !revoked {
+		t.Fatal("expected unexpired revocation to remain")
+	}
+}
+
+func TestTokenRevocation_IncrementTokenVersion(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTokenRevocationRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com", TokenVersion: 2}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	if err := repo.IncrementTokenVersion(1); err != nil {
+		t.Fatalf("IncrementTokenVersion failed: %v", err)
+	}
+	var um userModel
+	if err := db.First(&um, 1).Error; err != nil || um.TokenVersion != 3 {
+		t.Fatalf("expected token version 3, got %d (err=%v)", um.TokenVersion, err)
+	}
+	if err := repo.IncrementTokenVersion(999); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestTokenRevocation_ConsumeToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTokenRevocationRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	expiresAt := time.Now().Add(5 * time.Minute)
+	if consumed, err := repo.ConsumeToken("jti-once", 1, expiresAt); err != nil || !consumed {
+		t.Fatalf("expected first consume to succeed, got %v (err=%v)", consumed, err)
+	}
+	// 記録済みの jti は使えない（無効化済みのトークンも同じ）
+	if consumed, err := repo.ConsumeToken("jti-once", 1, expiresAt); err != nil || consumed {
+		t.Fatalf("expected second consume to fail, got %v (err=%v)", consumed, err)
+	}
+	if err := repo.RevokeAccessToken("jti-revoked", 1, expiresAt); err != nil {
+		t.Fatalf("RevokeAccessToken failed: %v", err)
+	}
+	if consumed, err := repo.ConsumeToken("jti-revoked", 1, expiresAt); err != nil || consumed {
+		t.Fatalf("expected revoked token not to be consumed, got %v (err=%v)", consumed, err)
+	}
+	if revoked, _ := repo.IsAccessTokenRevoked("jti-once"); !revoked {
+		t.Fatal("expected consumed token to be revoked")
+	}
+}
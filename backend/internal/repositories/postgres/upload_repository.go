@@ -112,7 +112,9 @@ func (r *UploadRepository) DeleteUnusedOlderThan(duration time.Duration) (int64,
 // pg_advisory_xact_lock でユーザー単位の排他ロックを取得してから既存レコードを deleted 状態に更新し、
 // 新規レコードを作成する。advisory lock はトランザクション終了時に自動解放されるため
 // 同一ユーザーの並列リクエストが0件→複数件 insert するケースも確実に排除できる。
-// 削除された旧レコードのファイルパス一覧を返す（ディスク上のファイル削除は呼び出し元が行う）。
+// 削除された旧レコードのファイルパス一覧を返す。
+// 同一トランザクション内で ProfileImageReplaced イベントをアウトボックスに書き込み、
+// ディスク上の旧ファイルの削除はイベントの購読者が行う。
 func (r *UploadRepository) ReplaceProfileImage(newUpload *models.UploadDB) ([]string, error) {
 	var oldPaths []string
 	err := r.db.Transaction(func(tx *gorm.DB) error {
@@ -158,7 +160,16 @@ func (r *UploadRepository) ReplaceProfileImage(newUpload *models.UploadDB) ([]st
 			}
 		}
 
-		return nil
+		// 旧画像ファイルの削除等の後処理は ProfileImageReplaced イベントの購読者が行う
+		oldFilePaths := oldPaths
+		if oldFilePaths == nil {
+			oldFilePaths = []string{}
+		}
+		return writeOutboxEvent(tx, models.DomainEventProfileImageReplaced, models.ProfileImageReplacedEvent{
+			UserID:       newUpload.UserID,
+			FilePath:     newUpload.FilePath,
+			OldFilePaths: oldFilePaths,
+		})
 	})
 	if err != nil {
 		return nil, err
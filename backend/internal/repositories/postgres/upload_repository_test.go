@@ -18,7 +18,7 @@ func setupUploadTestDB(t *testing.T) *gorm.DB {
 	assert.NoError(t, err)
 
 	// マイグレーション
-	err = db.AutoMigrate(&models.UploadDB{})
+	err = db.AutoMigrate(&models.UploadDB{}, &outboxEventModel{})
 	assert.NoError(t, err)
 
 	return db
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) GetByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	var im userIdentityModel
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&im).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrIdentityNotFound
+		}
+		logging.L.Error("failed to query user identity", "repository", "UserIdentityRepository", "method", "GetByProviderSubject", "provider", provider, "error", err)
+		return nil, fmt.Errorf("failed to query user identity: %w", err)
+	}
+	return &models.UserIdentity{
+		ID:        im.ID,
+		UserID:    int(im.UserID),
+		Provider:  im.Provider,
+		Subject:   im.Subject,
+		Email:     im.Email,
+		CreatedAt: im.CreatedAt,
+	}, nil
+}
+
+func (r *UserIdentityRepository) Create(identity *models.UserIdentity) error {
+	im := userIdentityModel{
+		UserID:    int64(identity.UserID),
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: r.db.NowFunc(),
+	}
+	if err := r.db.Create(&im).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repositories.ErrIdentityAlreadyLinked
+		}
+		logging.L.Error("failed to create user identity", "repository", "UserIdentityRepository", "method", "Create", "user_id", identity.UserID, "provider", identity.Provider, "error", err)
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	identity.ID = im.ID
+	identity.CreatedAt = im.CreatedAt
+	logging.L.Info("user identity linked", "repository", "UserIdentityRepository", "method", "Create", "user_id", identity.UserID, "provider", identity.Provider)
+	return nil
+}
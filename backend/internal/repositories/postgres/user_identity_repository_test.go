@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestUserIdentityRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserIdentityRepository(db)
+	for _, u := range []userModel{{ID: 1, Email: "oidc1@example.com"}, {ID: 2, Email: "oidc2@example.com"}} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	if _, err := repo.GetByProviderSubject("google", "sub-1"); !errors.Is(err, repositories.ErrIdentityNotFound) {
+		t.Fatalf("expected ErrIdentityNotFound, got %v", err)
+	}
+	identity := &models.UserIdentity{UserID: 1, Provider: "google", Subject: "sub-1", Email: "oidc1@example.com"}
+	if err := repo.Create(identity); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if identity.ID == 0 || identity.CreatedAt.IsZero() {
+		t.Fatalf("expected ID and CreatedAt to be set: %+v", identity)
+	}
+
+	got, err := repo.GetByProviderSubject("google", "sub-1")
+	if err != nil || got.UserID != 1 || got.Email != "oidc1@example.com" {
+		t.Fatalf("unexpected identity: %+v (err=%v)", got, err)
+	}
+
+	t.Run("異常系: 同じプロバイダーのアカウントは別のユーザーに紐付けられない", func(t *testing.T) {
+		err := repo.Create(&models.UserIdentity{UserID: 2, Provider: "google", Subject: "sub-1"})
+		if !errors.Is(err, repositories.ErrIdentityAlreadyLinked) {
+			t.Errorf("expected ErrIdentityAlreadyLinked, got %v", err)
+		}
+	})
+
+	t.Run("正常系: プロバイダーが異なれば同じ sub を紐付けられる", func(t *testing.T) {
+		if err := repo.Create(&models.UserIdentity{UserID: 2, Provider: "line", Subject: "sub-1"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		got, err := repo.GetByProviderSubject("line", "sub-1")
+		if err != nil || got.UserID != 2 {
+			t.Errorf("unexpected identity: %+v (err=%v)", got, err)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type UserRelationRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRelationRepository(db *gorm.DB) *UserRelationRepository {
+	return &UserRelationRepository{db: db}
+}
+
+// relatedUserRow はブロック・ミュートの1件（相手のユーザーIDと日時）
+type relatedUserRow struct {
+	UserID    int64
+	CreatedAt time.Time
+}
+
+// toRelatedUsers は相手のユーザー情報を取得し、rows の順序で返す（退会済みのユーザーは除外される）
+func (r *UserRelationRepository) toRelatedUsers(rows []relatedUserRow) ([]models.RelatedUser, error) {
+	related := []models.RelatedUser{}
+	if len(rows) == 0 {
+		return related, nil
+	}
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.UserID)
+	}
+	var ums []userModel
+	if err := r.db.Where("id IN ?", ids).Find(&ums).Error; err != nil {
+		return nil, fmt.Errorf("failed to query related users: %w", err)
+	}
+	byID := make(map[int64]*userModel, len(ums))
+	for i := range ums {
+		byID[ums[i].ID] = &ums[i]
+	}
+	for _, row := range rows {
+		um, ok := byID[row.UserID]
+		if !ok {
+			continue
+		}
+		related = append(related, models.RelatedUser{
+			User: models.User{
+				ID:          int(um.ID),
+				Email:       um.Email,
+				DisplayName: um.DisplayName,
+				Description: um.Description,
+				IconURL:     um.IconURL,
+				ExternalURL: um.ExternalURL,
+			},
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return related, nil
+}
+
+// createRelation はブロック・ミュートの行を作成し、重複・相手の不在をそれぞれのエラーに変換する
+func (r *UserRelationRepository) createRelation(value interface{}, errAlready error) error {
+	if err := r.db.Create(value).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errAlready
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return repositories.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *UserRelationRepository) Block(blockerID, blockedID int) error {
+	err := r.createRelation(&userBlockModel{BlockerID: int64(blockerID), BlockedID: int64(blockedID)}, repositories.ErrAlreadyBlocked)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAlreadyBlocked) || errors.Is(err, repositories.ErrUserNotFound) {
+			return err
+		}
+		logging.L.Error("failed to block user", "repository", "UserRelationRepository", "method", "Block", "blocker_id", blockerID, "blocked_id", blockedID, "error", err)
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+	logging.L.Info("user blocked", "repository", "UserRelationRepository", "method", "Block", "blocker_id", blockerID, "blocked_id", blockedID)
+	return nil
+}
+
+func (r *UserRelationRepository) Unblock(blockerID, blockedID int) error {
+	result := r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&userBlockModel{})
+	if result.Error != nil {
+		logging.L.Error("failed to unblock user", "repository", "UserRelationRepository", "method", "Unblock", "blocker_id", blockerID, "blocked_id", blockedID, "error", result.Error)
+		return fmt.Errorf("failed to unblock user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotBlocked
+	}
+	logging.L.Info("user unblocked", "repository", "UserRelationRepository", "method", "Unblock", "blocker_id", blockerID, "blocked_id", blockedID)
+	return nil
+}
+
+func (r *UserRelationRepository) ListBlocked(userID int) ([]models.RelatedUser, error) {
+	var rows []relatedUserRow
+	if err := r.db.Model(&userBlockModel{}).
+		Select("blocked_id AS user_id, created_at").
+		Where("blocker_id = ?", userID).
+		Order("created_at DESC, blocked_id DESC").
+		Scan(&rows).Error; err != nil {
+		logging.L.Error("failed to query blocked users", "repository", "UserRelationRepository", "method", "ListBlocked", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query blocked users of user_id=%d: %w", userID, err)
+	}
+	related, err := r.toRelatedUsers(rows)
+	if err != nil {
+		logging.L.Error("failed to query blocked users", "repository", "UserRelationRepository", "method", "ListBlocked", "user_id", userID, "error", err)
+		return nil, err
+	}
+	return related, nil
+}
+
+func (r *UserRelationRepository) Mute(muterID, mutedID int) error {
+	err := r.createRelation(&userMuteModel{MuterID: int64(muterID), MutedID: int64(mutedID)}, repositories.ErrAlreadyMuted)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAlreadyMuted) || errors.Is(err, repositories.ErrUserNotFound) {
+			return err
+		}
+		logging.L.Error("failed to mute user", "repository", "UserRelationRepository", "method", "Mute", "muter_id", muterID, "muted_id", mutedID, "error", err)
+		return fmt.Errorf("failed to mute user: %w", err)
+	}
+	logging.L.Info("user muted", "repository", "UserRelationRepository", "method", "Mute", "muter_id", muterID, "muted_id", mutedID)
+	return nil
+}
+
+func (r *UserRelationRepository) Unmute(muterID, mutedID int) error {
+	result := r.db.Where("muter_id = ? AND muted_id = ?", muterID, mutedID).Delete(&userMuteModel{})
+	if result.Error != nil {
+		logging.L.Error("failed to unmute user", "repository", "UserRelationRepository", "method", "Unmute", "muter_id", muterID, "muted_id", mutedID, "error", result.Error)
+		return fmt.Errorf("failed to unmute user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotMuted
+	}
+	logging.L.Info("user unmuted", "repository", "UserRelationRepository", "method", "Unmute", "muter_id", muterID, "muted_id", mutedID)
+	return nil
+}
+
+func (r *UserRelationRepository) ListMuted(userID int) ([]models.RelatedUser, error) {
+	var rows []relatedUserRow
+	if err := r.db.Model(&userMuteModel{}).
+		Select("muted_id AS user_id, created_at").
+		Where("muter_id = ?", userID).
+		Order("created_at DESC, muted_id DESC").
+		Scan(&rows).Error; err != nil {
+		logging.L.Error("failed to query muted users", "repository", "UserRelationRepository", "method", "ListMuted", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query muted users of user_id=%d: %w", userID, err)
+	}
+	related, err := r.toRelatedUsers(rows)
+	if err != nil {
+		logging.L.Error("failed to query muted users", "repository", "UserRelationRepository", "method", "ListMuted", "user_id", userID, "error", err)
+		return nil, err
+	}
+	return related, nil
+}
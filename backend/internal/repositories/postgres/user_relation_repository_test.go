@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// setupRelationUsers はユーザー1〜3と、それぞれの投稿を1件ずつ作成し、ユーザーIDごとの投稿IDを返す
+func setupRelationUsers(t *testing.T, db *gorm.DB) map[int]int {
+	t.Helper()
+	postRepo := NewPostRepository(db)
+	postIDs := map[int]int{}
+	for id := 1; id <= 3; id++ {
+		if err := db.Create(&userModel{ID: int64(id), Email: "u" + string(rune('0'+id)) + "@example.com", DisplayName: "u"}).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		p := &models.Post{UserID: id, Slides: []models.Slide{{Text: "t"}}}
+		if err := postRepo.Create(p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		postIDs[id] = p.ID
+	}
+	return postIDs
+}
+
+// timelineAuthors は viewerID から見たタイムラインの投稿者IDの集合を返す
+func timelineAuthors(t *testing.T, repo *PostRepository, viewerID *int) map[int]bool {
+	t.Helper()
+	posts, err := repo.GetAll(viewerID, models.PostFilter{})
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	authors := map[int]bool{}
+	for _, p := range posts {
+		authors[p.UserID] = true
+	}
+	return authors
+}
+
+func TestUserRelation_BlockAndMuteLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRelationRepository(db)
+	setupRelationUsers(t, db)
+
+	if err := repo.Block(1, 2); err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+	if err := repo.Block(1, 2); !errors.Is(err, repositories.ErrAlreadyBlocked) {
+		t.Fatalf("expected ErrAlreadyBlocked, got %v", err)
+	}
+	if err := repo.Mute(1, 3); err != nil {
+		t.Fatalf("Mute failed: %v", err)
+	}
+	if err := repo.Mute(1, 3); !errors.Is(err, repositories.ErrAlreadyMuted) {
+		t.Fatalf("expected ErrAlreadyMuted, got %v", err)
+	}
+
+	blocked, err := repo.ListBlocked(1)
+	if err != nil || len(blocked) != 1 || blocked[0].User.ID != 2 {
+		t.Fatalf("unexpected blocked users: %+v err=%v", blocked, err)
+	}
+	muted, err := repo.ListMuted(1)
+	if err != nil || len(muted) != 1 || muted[0].User.ID != 3 {
+		t.Fatalf("unexpected muted users: %+v err=%v", muted, err)
+	}
+
+	if err := repo.Unblock(1, 2); err != nil {
+		t.Fatalf("Unblock failed: %v", err)
+	}
+	if err := repo.Unblock(1, 2); !errors.Is(err, repositories.ErrNotBlocked) {
+		t.Fatalf("expected ErrNotBlocked, got %v", err)
+	}
+	if err := repo.Unmute(1, 3); err != nil {
+		t.Fatalf("Unmute failed: %v", err)
+	}
+	if err := repo.Unmute(1, 3); !errors.Is(err, repositories.ErrNotMuted) {
+		t.Fatalf("expected ErrNotMuted, got %v", err)
+	}
+}
+
+func TestUserRelation_TimelineExcludesMutedAndBlocked(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRelationRepository(db)
+	postRepo := NewPostRepository(db)
+	setupRelationUsers(t, db)
+
+	if err := repo.Mute(1, 2); err != nil {
+		t.Fatalf("Mute failed: %v", err)
+	}
+	if err := repo.Block(3, 1); err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+
+	viewer := 1
+	if authors := timelineAuthors(t, postRepo, &viewer); len(authors) != 1 || !authors[1] {
+		t.Fatalf("expected only own posts for user 1, got %v", authors)
+	}
+	// ブロックした側からもブロックした相手の投稿は見えない
+	viewer = 3
+	if authors := timelineAuthors(t, postRepo, &viewer); len(authors) != 2 || authors[1] {
+		t.Fatalf("expected posts of users 2 and 3 for user 3, got %v", authors)
+	}
+	// ミュートは本人のタイムラインにのみ影響する
+	viewer = 2
+	if authors := timelineAuthors(t, postRepo, &viewer); len(authors) != 3 {
+		t.Fatalf("expected all posts for user 2, got %v", authors)
+	}
+	if authors := timelineAuthors(t, postRepo, nil); len(authors) != 3 {
+		t.Fatalf("expected all posts for anonymous viewer, got %v", authors)
+	}
+}
+
+func TestUserRelation_PostLookupsExcludeMutedAndBlocked(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRelationRepository(db)
+	postRepo := NewPostRepository(db)
+	postIDs := setupRelationUsers(t, db)
+
+	if err := repo.Mute(1, 2); err != nil {
+		t.Fatalf("Mute failed: %v", err)
+	}
+	if err := repo.Block(3, 1); err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+
+	viewer := 1
+	posts, err := postRepo.GetByIDs([]int{postIDs[1], postIDs[2], postIDs[3]}, &viewer)
+	if err != nil {
+		t.Fatalf("GetByIDs failed: %v", err)
+	}
+	if len(posts) != 1 || posts[0].UserID != 1 {
+		t.Fatalf("expected only own post from GetByIDs for user 1, got %+v", posts)
+	}
+	for _, authorID := range []int{2, 3} {
+		posts, err := postRepo.GetByUserID(authorID, &viewer)
+		if err != nil {
+			t.Fatalf("GetByUserID failed: %v", err)
+		}
+		if len(posts) != 0 {
+			t.Fatalf("expected no posts of user %d for user 1, got %+v", authorID, posts)
+		}
+	}
+
+	// 未ログインの場合は除外しない
+	posts, err = postRepo.GetByIDs([]int{postIDs[1], postIDs[2], postIDs[3]}, nil)
+	if err != nil {
+		t.Fatalf("GetByIDs failed: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("expected all posts for anonymous viewer, got %+v", posts)
+	}
+	posts, err = postRepo.GetByUserID(2, nil)
+	if err != nil {
+		t.Fatalf("GetByUserID failed: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected user 2's post for anonymous viewer, got %+v", posts)
+	}
+}
+
+func TestUserRelation_BlockedUserCannotLikeOrRemix(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRelationRepository(db)
+	postRepo := NewPostRepository(db)
+	postIDs := setupRelationUsers(t, db)
+
+	if err := repo.Block(1, 2); err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+
+	if err := postRepo.AddLike(2, postIDs[1]); !errors.Is(err, repositories.ErrBlocked) {
+		t.Fatalf("expected ErrBlocked, got %v", err)
+	}
+	var likes int64
+	db.Model(&postLikeModel{}).Where("post_id = ?", postIDs[1]).Count(&likes)
+	if likes != 0 {
+		t.Fatalf("expected no like to be recorded, got %d", likes)
+	}
+	// ブロックした側からのいいね・ブロックと無関係なユーザーのいいねはできる
+	if err := postRepo.AddLike(1, postIDs[2]); err != nil {
+		t.Fatalf("expected blocker to be able to like, got %v", err)
+	}
+	if err := postRepo.AddLike(3, postIDs[1]); err != nil {
+		t.Fatalf("expected unrelated user to be able to like, got %v", err)
+	}
+
+	remixedFrom := postIDs[1]
+	remix := &models.Post{UserID: 2, RemixedFrom: &remixedFrom, Slides: []models.Slide{{Text: "t"}}}
+	if err := postRepo.Create(remix); !errors.Is(err, repositories.ErrBlocked) {
+		t.Fatalf("expected ErrBlocked for remix, got %v", err)
+	}
+}
@@ -30,6 +30,13 @@ func (r *UserRepository) toDomain(um *userModel) models.User {
 		Description: um.Description,
 		IconURL:     um.IconURL,
 		ExternalURL: um.ExternalURL,
+		Role:        um.Role,
+
+		SuspendedAt:      um.SuspendedAt,
+		SuspendedUntil:   um.SuspendedUntil,
+		SuspensionReason: um.SuspensionReason,
+		TokenVersion:     um.TokenVersion,
+		EmailVerifiedAt:  um.EmailVerifiedAt,
 	}
 }
 
@@ -78,15 +85,8 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	}
 
 	// パスワードハッシュも含めて返す（認証用）
-	user := models.User{
-		ID:           int(um.ID),
-		Email:        um.Email,
-		PasswordHash: um.PasswordHash,
-		DisplayName:  um.DisplayName,
-		Description:  um.Description,
-		IconURL:      um.IconURL,
-		ExternalURL:  um.ExternalURL,
-	}
+	user := r.toDomain(&um)
+	user.PasswordHash = um.PasswordHash
 	logging.L.Debug("user found", "repository", "UserRepository", "method", "GetByEmail", "user_id", um.ID)
 	return &user, nil
 }
@@ -100,6 +100,10 @@ func (r *UserRepository) Create(user *models.User) error {
 		Description:  user.Description,
 		IconURL:      user.IconURL,
 		ExternalURL:  user.ExternalURL,
+		Role:         user.Role,
+	}
+	if um.Role == "" {
+		um.Role = models.RoleUser
 	}
 
 	if err := r.db.Create(um).Error; err != nil {
@@ -120,6 +124,7 @@ func (r *UserRepository) Create(user *models.User) error {
 	}
 
 	user.ID = int(um.ID)
+	user.Role = um.Role
 	logging.L.Info("user created",
 		"repository", "UserRepository",
 		"method", "Create",
@@ -166,3 +171,112 @@ func (r *UserRepository) Update(id int, input models.UpdateUserInput) (*models.U
 	}
 	return user, nil
 }
+
+func (r *UserRepository) UpdatePassword(userID int, passwordHash string) error {
+	result := r.db.Model(&userModel{}).Where("id = ?", userID).Update("password_hash", passwordHash)
+	if result.Error != nil {
+		logging.L.Error("failed to update password", "repository", "UserRepository", "method", "UpdatePassword", "user_id", userID, "error", result.Error)
+		return fmt.Errorf("failed to update password of user id=%d: %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrUserNotFound
+	}
+	logging.L.Info("password updated", "repository", "UserRepository", "method", "UpdatePassword", "user_id", userID)
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(userID int, email string) error {
+	// 確認済みの場合は最初に確認した日時を残す
+	result := r.db.Model(&userModel{}).
+		Where("id = ? AND email = ?", userID, email).
+		Update("email_verified_at", gorm.Expr("COALESCE(email_verified_at, ?)", r.db.NowFunc()))
+	if result.Error != nil {
+		logging.L.Error("failed to mark email verified", "repository", "UserRepository", "method", "MarkEmailVerified", "user_id", userID, "error", result.Error)
+		return fmt.Errorf("failed to mark email verified of user id=%d: %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrUserNotFound
+	}
+	logging.L.Info("email verified", "repository", "UserRepository", "method", "MarkEmailVerified", "user_id", userID)
+	return nil
+}
+
+func (r *UserRepository) GetRole(userID int) (string, error) {
+	var um userModel
+	if err := r.db.Select("id", "role").First(&um, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", repositories.ErrUserNotFound
+		}
+		logging.L.Error("failed to query user role", "repository", "UserRepository", "method", "GetRole", "user_id", userID, "error", err)
+		return "", fmt.Errorf("failed to query role of user id=%d: %w", userID, err)
+	}
+	return um.Role, nil
+}
+
+func (r *UserRepository) UpdateRole(actorID, userID int, role string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&userModel{}).Where("id = ?", userID).Update("role", role)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update role of user id=%d: %w", userID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrUserNotFound
+		}
+		actor := int64(actorID)
+		target := int64(userID)
+		if err := tx.Create(&moderationActionModel{
+			ModeratorID:  &actor,
+			Action:       models.ModerationActionChangeRole,
+			TargetUserID: &target,
+			Note:         role,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to record role change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return repositories.ErrUserNotFound
+		}
+		logging.L.Error("failed to update user role", "repository", "UserRepository", "method", "UpdateRole", "user_id", userID, "error", err)
+		return err
+	}
+	logging.L.Info("user role updated", "repository", "UserRepository", "method", "UpdateRole", "actor_id", actorID, "user_id", userID, "role", role)
+	return nil
+}
+
+func (r *UserRepository) BootstrapAdmin(email string) (bool, error) {
+	promoted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 複数インスタンスが同時に起動しても管理者が1人だけ作られるよう、管理者の確認と更新を1つの UPDATE で行う
+		result := tx.Model(&userModel{}).
+			Where("email = ? AND NOT EXISTS (SELECT 1 FROM users WHERE role = ?)", email, models.RoleAdmin).
+			Update("role", models.RoleAdmin)
+		if result.Error != nil {
+			return fmt.Errorf("failed to promote first admin: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			promoted = true
+			return nil
+		}
+		var admins int64
+		if err := tx.Model(&userModel{}).Where("role = ?", models.RoleAdmin).Count(&admins).Error; err != nil {
+			return fmt.Errorf("failed to count admins: %w", err)
+		}
+		if admins == 0 {
+			return repositories.ErrUserNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return false, repositories.ErrUserNotFound
+		}
+		logging.L.Error("failed to bootstrap admin", "repository", "UserRepository", "method", "BootstrapAdmin", "error", err)
+		return false, err
+	}
+	if promoted {
+		logging.L.Info("first admin created", "repository", "UserRepository", "method", "BootstrapAdmin", "email", email)
+	}
+	return promoted, nil
+}
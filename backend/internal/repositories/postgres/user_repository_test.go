@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+func TestUserRepository_CreateDefaultsToUserRole(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user := &models.User{Email: "new@example.com", PasswordHash: "x"}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if user.Role != models.RoleUser {
+		t.Fatalf("expected role %q, got %q", models.RoleUser, user.Role)
+	}
+	role, err := repo.GetRole(user.ID)
+	if err != nil || role != models.RoleUser {
+		t.Fatalf("expected stored role %q, got %q (err=%v)", models.RoleUser, role, err)
+	}
+	if _, err := repo.GetRole(999); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestUserRepository_UpdateRoleRecordsModerationAction(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	for id := 1; id <= 2; id++ {
+		if err := db.Create(&userModel{ID: int64(id), Email: "u" + string(rune('0'+id)) + "@example.com", Role: models.RoleUser}).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	if err := repo.UpdateRole(1, 2, models.RoleModerator); err != nil {
+		t.Fatalf("UpdateRole failed: %v", err)
+	}
+	user, err := repo.GetByID(2)
+	if err != nil || user.Role != models.RoleModerator {
+		t.Fatalf("expected moderator, got %+v (err=%v)", user, err)
+	}
+
+	actions, total, err := NewModerationRepository(db).ListActions(10, 0)
+	if err != nil {
+		t.Fatalf("ListActions failed: %v", err)
+	}
+	if total != 1 || actions[0].Action != models.ModerationActionChangeRole || actions[0].Note != models.RoleModerator ||
+		actions[0].ModeratorID == nil || *actions[0].ModeratorID != 1 || actions[0].TargetUserID == nil || *actions[0].TargetUserID != 2 {
+		t.Fatalf("unexpected moderation actions: %+v", actions)
+	}
+
+	if err := repo.UpdateRole(1, 999, models.RoleAdmin); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestUserRepository_BootstrapAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	for id := 1; id <= 2; id++ {
+		if err := db.Create(&userModel{ID: int64(id), Email: "u" + string(rune('0'+id)) + "@example.com", Role: models.RoleUser}).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	if _, err := repo.BootstrapAdmin("missing@example.com"); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound while no admin exists, got %v", err)
+	}
+
+	promoted, err := repo.BootstrapAdmin("u1@example.com")
+	if err != nil || !promoted {
+		t.Fatalf("expected first admin to be promoted, got promoted=%v err=%v", promoted, err)
+	}
+	if role, _ := repo.GetRole(1); role != models.RoleAdmin {
+		t.Fatalf("expected admin, got %q", role)
+	}
+
+	// 管理者がいる場合は何もしない（別のメールアドレスを指定しても昇格しない）
+	promoted, err = repo.BootstrapAdmin("u2@example.com")
+	if err != nil || promoted {
+		t.Fatalf("expected no-op once an admin exists, got promoted=%v err=%v", promoted, err)
+	}
+	if role, _ := repo.GetRole(2); role != models.RoleUser {
+		t.Fatalf("expected user 2 to remain user, got %q", role)
+	}
+	if _, err := repo.BootstrapAdmin("missing@example.com"); err != nil {
+		t.Fatalf("expected no error once an admin exists, got %v", err)
+	}
+}
+
+func TestUserRepository_UpdatePassword(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user := &models.User{Email: "password@example.com", PasswordHash: "old"}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := repo.UpdatePassword(user.ID, "new"); err != nil {
+		t.Fatalf("UpdatePassword failed: %v", err)
+	}
+	stored, err := repo.GetByEmail(user.Email)
+	if err != nil || stored.PasswordHash != "new" {
+		t.Fatalf("expected password hash to be updated, got %+v (err=%v)", stored, err)
+	}
+	if err := repo.UpdatePassword(999, "new"); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestUserRepository_MarkEmailVerified(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user := &models.User{Email: "verify@example.com", PasswordHash: "x"}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// メールアドレスが変わっている場合は確認しない
+	if err := repo.MarkEmailVerified(user.ID, "old@example.com"); !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := repo.MarkEmailVerified(user.ID, user.Email); err != nil {
+		t.Fatalf("MarkEmailVerified failed: %v", err)
+	}
+	verified, err := repo.GetByID(user.ID)
+	if err != nil || verified.EmailVerifiedAt == nil {
+		t.Fatalf("expected email to be verified, got %+v (err=%v)", verified, err)
+	}
+	// 再度確認しても最初に確認した日時を残す
+	if err := repo.MarkEmailVerified(user.ID, user.Email); err != nil {
+		t.Fatalf("MarkEmailVerified failed: %v", err)
+	}
+	again, _ := repo.GetByID(user.ID)
+	if again.EmailVerifiedAt == nil || !again.EmailVerifiedAt.Equal(*verified.EmailVerifiedAt) {
+		t.Fatalf("expected verified time to be kept, got %v and %v", verified.EmailVerifiedAt, again.EmailVerifiedAt)
+	}
+}
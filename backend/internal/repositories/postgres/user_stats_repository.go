@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/pkg/logging"
+)
+
+// topUsageLimit はよく使うフレーバー・カテゴリの最大件数
+const topUsageLimit = 5
+
+type UserStatsRepository struct {
+	db *gorm.DB
+	// loc は月・日の区切りに使うタイムゾーン（テストで差し替え可能）
+	loc *time.Location
+	// now は集計日時に使用する（テストで差し替え可能）
+	now func() time.Time
+}
+
+func NewUserStatsRepository(db *gorm.DB) *UserStatsRepository {
+	return &UserStatsRepository{db: db, loc: time.Local, now: time.Now}
+}
+
+// bucketSeconds は DB で事前に集計する時間幅（秒）
+// 実在するタイムゾーンのオフセットと夏時間の切り替えはいずれも15分単位のため、15分ごとの件数から月・日ごとの件数を正しく求められる
+const bucketSeconds = 15 * 60
+
+// quarterHourExpr は timestamp カラムを UNIX 時間の15分単位の通し番号に変換するSQL式を返す
+// テストでは SQLite を使用するため方言ごとに式を切り替える
+func (r *UserStatsRepository) quarterHourExpr(column string) string {
+	if r.db.Dialector.Name() == "sqlite" {
+		return fmt.Sprintf("(CAST(strftime('%%s', %s) AS INTEGER) / %d)", column, bucketSeconds)
+	}
+	return fmt.Sprintf("CAST(FLOOR(EXTRACT(EPOCH FROM %s) / %d) AS BIGINT)", column, bucketSeconds)
+}
+
+// countByLocalBucket は query の行を column の15分ごとに DB で数え、サーバーのタイムゾーンの layout（"2006-01" または "2006-01-02"）単位にまとめて昇順で返す
+// 夏時間の切り替えを正しく扱うため、タイムゾーンの変換は固定のオフセットではなく time.In で1件ずつ行う
+func (r *UserStatsRepository) countByLocalBucket(query *gorm.DB, column, layout string) ([]models.MonthlyCount, error) {
+	expr := r.quarterHourExpr(column)
+	var rows []struct {
+		Quarter int64
+		Count   int
+	}
+	if err := query.Select(expr + " AS quarter, COUNT(*) AS count").Group(expr).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, row := range rows {
+		counts[time.Unix(row.Quarter*bucketSeconds, 0).In(r.loc).Format(layout)] += row.Count
+	}
+	buckets := make([]models.MonthlyCount, 0, len(counts))
+	for bucket, count := range counts {
+		buckets = append(buckets, models.MonthlyCount{Month: bucket, Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Month < buckets[j].Month })
+	return buckets, nil
+}
+
+func (r *UserStatsRepository) GetUserStats(userID int) (*models.UserStats, error) {
+	logging.L.Debug("aggregating user stats", "repository", "UserStatsRepository", "method", "GetUserStats", "user_id", userID)
+
+	stats := &models.UserStats{
+		UserID:                userID,
+		PostsPerMonth:         []models.MonthlyCount{},
+		TopFlavors:            []models.FlavorUsage{},
+		TopFlavorCategories:   []models.CategoryUsage{},
+		LikesReceivedPerMonth: []models.MonthlyCount{},
+		GeneratedAt:           r.now(),
+	}
+
+	steps := []struct {
+		name string
+		fn   func(userID int, stats *models.UserStats) error
+	}{
+		{"posts per month", r.aggregatePostsPerMonth},
+		{"top flavors", r.aggregateTopFlavors},
+		{"top flavor categories", r.aggregateTopFlavorCategories},
+		{"distinct flavors", r.aggregateDistinctFlavors},
+		{"likes received", r.aggregateLikesReceived},
+		{"longest streak", r.aggregateLongestStreak},
+	}
+	for _, step := range steps {
+		if err := step.fn(userID, stats); err != nil {
+			logging.L.Error("failed to aggregate user stats", "repository", "UserStatsRepository", "method", "GetUserStats", "user_id", userID, "step", step.name, "error", err)
+			return nil, fmt.Errorf("failed to aggregate %s for user_id=%d: %w", step.name, userID, err)
+		}
+	}
+
+	logging.L.Debug("user stats aggregated", "repository", "UserStatsRepository", "method", "GetUserStats", "user_id", userID, "total_posts", stats.TotalPosts)
+	return stats, nil
+}
+
+// aggregatePostsPerMonth は月ごとの投稿数と投稿数の合計を集計する
+func (r *UserStatsRepository) aggregatePostsPerMonth(userID int, stats *models.UserStats) error {
+	rows, err := r.countByLocalBucket(r.db.Model(&postModel{}).Where("user_id = ?", userID), "posts.created_at", "2006-01")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		stats.TotalPosts += row.Count
+	}
+	stats.PostsPerMonth = rows
+	return nil
+}
+
+// aggregateTopFlavors はフレーバーごとに使用した投稿数を集計し、多い順に返す
+// 1投稿の複数スライドで同じフレーバーを使っても1回として数える
+func (r *UserStatsRepository) aggregateTopFlavors(userID int, stats *models.UserStats) error {
+	var rows []struct {
+		ID       int64
+		Name     string
+		Color    string
+		Category *string
+		Count    int
+	}
+	if err := r.db.Table("slides").
+		Select("flavors.id, flavors.name, flavors.color, flavors.category, COUNT(DISTINCT posts.id) AS count").
+		Joins("JOIN posts ON posts.id = slides.post_id AND posts.deleted_at IS NULL").
+		Joins("JOIN flavors ON flavors.id = slides.flavor_id").
+		Where("posts.user_id = ?", userID).
+		Group("flavors.id, flavors.name, flavors.color, flavors.category").
+		Order("count DESC, flavors.id ASC").
+		Limit(topUsageLimit).
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		flavor := models.Flavor{ID: int(row.ID), Name: row.Name, Color: row.Color}
+		if row.Category != nil {
+			flavor.Category = *row.Category
+		}
+		stats.TopFlavors = append(stats.TopFlavors, models.FlavorUsage{Flavor: flavor, Count: row.Count})
+	}
+	return nil
+}
+
+// aggregateTopFlavorCategories はフレーバーカテゴリごとに使用した投稿数を集計し、多い順に返す
+// カテゴリ未設定のフレーバーは集計対象外とする
+func (r *UserStatsRepository) aggregateTopFlavorCategories(userID int, stats *models.UserStats) error {
+	var rows []models.CategoryUsage
+	if err := r.db.Table("slides").
+		Select("flavors.category AS category, COUNT(DISTINCT posts.id) AS count").
+		Joins("JOIN posts ON posts.id = slides.post_id AND posts.deleted_at IS NULL").
+		Joins("JOIN flavors ON flavors.id = slides.flavor_id").
+		Where("posts.user_id = ? AND flavors.category IS NOT NULL AND flavors.category <> ''", userID).
+		Group("flavors.category").
+		Order("count DESC, category ASC").
+		Limit(topUsageLimit).
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	if rows != nil {
+		stats.TopFlavorCategories = rows
+	}
+	return nil
+}
+
+// aggregateDistinctFlavors はこれまでに使用したフレーバーの種類数を集計する
+func (r *UserStatsRepository) aggregateDistinctFlavors(userID int, stats *models.UserStats) error {
+	var count int64
+	if err := r.db.Table("slides").
+		Joins("JOIN posts ON posts.id = slides.post_id AND posts.deleted_at IS NULL").
+		Where("posts.user_id = ? AND slides.flavor_id IS NOT NULL", userID).
+		Distinct("slides.flavor_id").
+		Count(&count).Error; err != nil {
+		return err
+	}
+	stats.DistinctFlavors = int(count)
+	return nil
+}
+
+// aggregateLikesReceived はユーザーの投稿が受け取ったいいね数を、いいねされた月ごとに集計する
+func (r *UserStatsRepository) aggregateLikesReceived(userID int, stats *models.UserStats) error {
+	rows, err := r.countByLocalBucket(r.db.Table("post_likes").
+		Joins("JOIN posts ON posts.id = post_likes.post_id AND posts.deleted_at IS NULL").
+		Where("posts.user_id = ?", userID), "post_likes.created_at", "2006-01")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		stats.TotalLikesReceived += row.Count
+	}
+	stats.LikesReceivedPerMonth = rows
+	return nil
+}
+
+// aggregateLongestStreak は投稿のあった日付を重複なしで取得し、連続投稿日数の最長記録を求める
+func (r *UserStatsRepository) aggregateLongestStreak(userID int, stats *models.UserStats) error {
+	rows, err := r.countByLocalBucket(r.db.Model(&postModel{}).Where("user_id = ?", userID), "posts.created_at", "2006-01-02")
+	if err != nil {
+		return err
+	}
+	days := make([]string, 0, len(rows))
+	for _, row := range rows {
+		days = append(days, row.Month)
+	}
+	stats.LongestStreakDays = longestStreak(days)
+	return nil
+}
+
+// longestStreak は昇順の日付（YYYY-MM-DD）の一覧から、連続した日付の最長数を返す
+func longestStreak(days []string) int {
+	longest, current := 0, 0
+	var prev time.Time
+	for _, d := range days {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			logging.L.Warn("unexpected day format in streak aggregation", "repository", "UserStatsRepository", "method", "longestStreak", "day", d, "error", err)
+			continue
+		}
+		if current > 0 && t.Equal(prev.AddDate(0, 0, 1)) {
+			current++
+		} else {
+			current = 1
+		}
+		prev = t
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
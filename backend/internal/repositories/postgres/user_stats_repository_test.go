@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+	// 実行環境にタイムゾーンのデータベースがなくても夏時間のあるタイムゾーンを読み込めるようにする
+	_ "time/tzdata"
+
+	"gorm.io/gorm"
+)
+
+// createPostAt は指定日時に作成された投稿とスライドを直接作成する
+func createPostAt(t *testing.T, db *gorm.DB, userID int64, createdAt time.Time, flavorIDs ...int64) int64 {
+	t.Helper()
+	pm := postModel{UserID: userID, CreatedAt: createdAt}
+	if err := db.Create(&pm).Error; err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if len(flavorIDs) == 0 {
+		flavorIDs = []int64{0}
+	}
+	for i, fid := range flavorIDs {
+		sm := slideModel{PostID: pm.ID, Text: "s", SlideOrder: i}
+		if fid != 0 {
+			id := fid
+			sm.FlavorID = &id
+		}
+		if err := db.Create(&sm).Error; err != nil {
+			t.Fatalf("failed to create slide: %v", err)
+		}
+	}
+	return pm.ID
+}
+
+func TestGetUserStats_Aggregates(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserStatsRepository(db)
+	// UTC 基準で集計させる
+	repo.loc = time.UTC
+
+	for _, u := range []userModel{{ID: 1, Email: "u1@example.com"}, {ID: 2, Email: "u2@example.com"}} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	fruit, mint := "フルーツ", "ミント"
+	for _, f := range []flavorModel{
+		{ID: 1, Name: "ミント", Category: &mint},
+		{ID: 2, Name: "アップル", Category: &fruit},
+		{ID: 3, Name: "ベリー", Category: &fruit},
+		{ID: 4, Name: "謎", Category: nil},
+	} {
+		if err := db.Create(&f).Error; err != nil {
+			t.Fatalf("failed to create flavor: %v", err)
+		}
+	}
+
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 12, 0, 0, 0, time.UTC) }
+	// 1月: 3日連続（同日2投稿を含む）、2月: 2日連続、3月: 単発
+	p1 := createPostAt(t, db, 1, day(1, 10), 1, 1, 2)
+	createPostAt(t, db, 1, day(1, 11), 1)
+	createPostAt(t, db, 1, day(1, 11), 3)
+	createPostAt(t, db, 1, day(1, 12))
+	p5 := createPostAt(t, db, 1, day(2, 1), 2, 4)
+	createPostAt(t, db, 1, day(2, 2), 1)
+	deleted := createPostAt(t, db, 1, day(3, 5), 3)
+	createPostAt(t, db, 1, day(3, 20))
+	// 他ユーザーの投稿は集計に含まれない
+	createPostAt(t, db, 2, day(1, 13), 3)
+
+	// 削除済み投稿は集計に含まれない
+	if err := db.Delete(&postModel{ID: deleted}).Error; err != nil {
+		t.Fatalf("failed to soft-delete post: %v", err)
+	}
+
+	for _, l := range []postLikeModel{
+		{UserID: 2, PostID: p1, CreatedAt: day(1, 15)},
+		{UserID: 1, PostID: p1, CreatedAt: day(2, 3)},
+		{UserID: 2, PostID: p5, CreatedAt: day(2, 4)},
+	} {
+		if err := db.Create(&l).Error; err != nil {
+			t.Fatalf("failed to create like: %v", err)
+		}
+	}
+
+	stats, err := repo.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+
+	if stats.TotalPosts != 7 {
+		t.Fatalf("expected total_posts=7, got %d", stats.TotalPosts)
+	}
+	wantMonths := []struct {
+		month string
+		count int
+	}{{"2025-01", 4}, {"2025-02", 2}, {"2025-03", 1}}
+	if len(stats.PostsPerMonth) != len(wantMonths) {
+		t.Fatalf("unexpected posts_per_month: %+v", stats.PostsPerMonth)
+	}
+	for i, w := range wantMonths {
+		if stats.PostsPerMonth[i].Month != w.month || stats.PostsPerMonth[i].Count != w.count {
+			t.Fatalf("posts_per_month[%d]: want %s=%d, got %+v", i, w.month, w.count, stats.PostsPerMonth[i])
+		}
+	}
+
+	// ミント: 3投稿（同一投稿内の重複は1回）、アップル: 2投稿、ベリー: 1投稿、謎: 1投稿
+	if len(stats.TopFlavors) != 4 || stats.TopFlavors[0].Flavor.ID != 1 || stats.TopFlavors[0].Count != 3 ||
+		stats.TopFlavors[1].Flavor.ID != 2 || stats.TopFlavors[1].Count != 2 {
+		t.Fatalf("unexpected top_flavors: %+v", stats.TopFlavors)
+	}
+	if stats.TopFlavors[0].Flavor.Category != "ミント" {
+		t.Fatalf("expected flavor category to be set, got %+v", stats.TopFlavors[0].Flavor)
+	}
+	// フルーツ: p1(アップル)・ベリー投稿・p5(アップル) = 3投稿、ミント: 3投稿、未分類は含まない
+	if len(stats.TopFlavorCategories) != 2 || stats.TopFlavorCategories[0].Count != 3 || stats.TopFlavorCategories[1].Count != 3 {
+		t.Fatalf("unexpected top_flavor_categories: %+v", stats.TopFlavorCategories)
+	}
+	if stats.DistinctFlavors != 4 {
+		t.Fatalf("expected distinct_flavors=4, got %d", stats.DistinctFlavors)
+	}
+
+	if stats.TotalLikesReceived != 3 {
+		t.Fatalf("expected total_likes_received=3, got %d", stats.TotalLikesReceived)
+	}
+	if len(stats.LikesReceivedPerMonth) != 2 ||
+		stats.LikesReceivedPerMonth[0].Month != "2025-01" || stats.LikesReceivedPerMonth[0].Count != 1 ||
+		stats.LikesReceivedPerMonth[1].Month != "2025-02" || stats.LikesReceivedPerMonth[1].Count != 2 {
+		t.Fatalf("unexpected likes_received_per_month: %+v", stats.LikesReceivedPerMonth)
+	}
+
+	if stats.LongestStreakDays != 3 {
+		t.Fatalf("expected longest_streak_days=3, got %d", stats.LongestStreakDays)
+	}
+}
+
+func TestGetUserStats_NoPosts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserStatsRepository(db)
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	stats, err := repo.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if stats.TotalPosts != 0 || stats.LongestStreakDays != 0 || stats.DistinctFlavors != 0 {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+	// 空の一覧は null ではなく空配列として返す
+	if stats.PostsPerMonth == nil || stats.TopFlavors == nil || stats.TopFlavorCategories == nil || stats.LikesReceivedPerMonth == nil {
+		t.Fatalf("expected empty slices instead of nil, got %+v", stats)
+	}
+}
+
+func TestGetUserStats_BucketsInServerTimezone(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserStatsRepository(db)
+	repo.loc = time.FixedZone("JST", 9*60*60)
+
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	// UTC では 1/31 だが JST では 2/1 の投稿
+	createPostAt(t, db, 1, time.Date(2025, 1, 31, 20, 0, 0, 0, time.UTC))
+
+	stats, err := repo.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if len(stats.PostsPerMonth) != 1 || stats.PostsPerMonth[0].Month != "2025-02" {
+		t.Fatalf("expected post to be bucketed into 2025-02, got %+v", stats.PostsPerMonth)
+	}
+}
+
+func TestGetUserStats_BucketsAcrossDaylightSavingTime(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserStatsRepository(db)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	repo.loc = berlin
+	// 冬時間（UTC+1）の時期に集計しても、夏時間（UTC+2）の投稿は夏時間で区切る
+	repo.now = func() time.Time { return time.Date(2025, 1, 15, 12, 0, 0, 0, berlin) }
+
+	if err := db.Create(&userModel{ID: 1, Email: "u1@example.com"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	// 夏時間では 7/1 0:30、冬時間の 6/30 23:30 ではない
+	createPostAt(t, db, 1, time.Date(2025, 6, 30, 22, 30, 0, 0, time.UTC))
+	// 冬時間では 1/1 0:30
+	createPostAt(t, db, 1, time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC))
+	// 夏時間の 7/2 0:15（7/1 からの連続投稿）
+	createPostAt(t, db, 1, time.Date(2025, 7, 1, 22, 15, 0, 0, time.UTC))
+
+	stats, err := repo.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("GetUserStats failed: %v", err)
+	}
+	if len(stats.PostsPerMonth) != 2 ||
+		stats.PostsPerMonth[0].Month != "2025-01" || stats.PostsPerMonth[0].Count != 1 ||
+		stats.PostsPerMonth[1].Month != "2025-07" || stats.PostsPerMonth[1].Count != 2 {
+		t.Fatalf("expected posts to be bucketed into 2025-01 and 2025-07, got %+v", stats.PostsPerMonth)
+	}
+	if stats.LongestStreakDays != 2 {
+		t.Fatalf("expected longest_streak_days=2, got %d", stats.LongestStreakDays)
+	}
+}
+
+func TestLongestStreak(t *testing.T) {
+	cases := []struct {
+		name string
+		days []string
+		want int
+	}{
+		{"empty", nil, 0},
+		{"single", []string{"2025-01-01"}, 1},
+		{"month boundary", []string{"2025-01-31", "2025-02-01", "2025-02-02"}, 3},
+		{"gap resets", []string{"2025-01-01", "2025-01-02", "2025-01-04", "2025-01-05", "2025-01-06", "2025-01-07"}, 4},
+		{"leap day", []string{"2024-02-28", "2024-02-29", "2024-03-01"}, 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := longestStreak(tc.days); got != tc.want {
+				t.Fatalf("want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) toEndpoint(em *webhookEndpointModel, withSecret bool) models.WebhookEndpoint {
+	endpoint := models.WebhookEndpoint{
+		ID:        int(em.ID),
+		UserID:    int(em.UserID),
+		URL:       em.URL,
+		Events:    splitWebhookEvents(em.Events),
+		IsActive:  em.IsActive,
+		CreatedAt: em.CreatedAt,
+	}
+	if withSecret {
+		endpoint.Secret = em.Secret
+	}
+	return endpoint
+}
+
+func (r *WebhookRepository) toDelivery(dm *webhookDeliveryModel) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:             int(dm.ID),
+		EndpointID:     int(dm.EndpointID),
+		EventID:        dm.EventID,
+		EventType:      dm.EventType,
+		Payload:        []byte(dm.Payload),
+		Status:         dm.Status,
+		Attempts:       dm.Attempts,
+		NextAttemptAt:  dm.NextAttemptAt,
+		LastAttemptAt:  dm.LastAttemptAt,
+		ResponseStatus: dm.ResponseStatus,
+		LastError:      dm.LastError,
+		CreatedAt:      dm.CreatedAt,
+	}
+}
+
+// splitWebhookEvents はカンマ区切りのイベント種別を分割する
+func splitWebhookEvents(events string) []string {
+	if events == "" {
+		return []string{}
+	}
+	return strings.Split(events, ",")
+}
+
+func (r *WebhookRepository) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	logging.L.Debug("creating webhook endpoint", "repository", "WebhookRepository", "method", "CreateEndpoint", "user_id", endpoint.UserID)
+	em := webhookEndpointModel{
+		UserID:   int64(endpoint.UserID),
+		URL:      endpoint.URL,
+		Secret:   endpoint.Secret,
+		Events:   strings.Join(endpoint.Events, ","),
+		IsActive: endpoint.IsActive,
+	}
+	if err := r.db.Create(&em).Error; err != nil {
+		logging.L.Error("failed to create webhook endpoint", "repository", "WebhookRepository", "method", "CreateEndpoint", "user_id", endpoint.UserID, "error", err)
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	endpoint.ID = int(em.ID)
+	endpoint.CreatedAt = em.CreatedAt
+	logging.L.Info("webhook endpoint created", "repository", "WebhookRepository", "method", "CreateEndpoint", "endpoint_id", endpoint.ID, "user_id", endpoint.UserID)
+	return nil
+}
+
+func (r *WebhookRepository) GetEndpoint(id int) (*models.WebhookEndpoint, error) {
+	var em webhookEndpointModel
+	if err := r.db.First(&em, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrWebhookNotFound
+		}
+		logging.L.Error("failed to query webhook endpoint", "repository", "WebhookRepository", "method", "GetEndpoint", "endpoint_id", id, "error", err)
+		return nil, fmt.Errorf("failed to query webhook endpoint id=%d: %w", id, err)
+	}
+	endpoint := r.toEndpoint(&em, true)
+	return &endpoint, nil
+}
+
+func (r *WebhookRepository) ListEndpointsByUserID(userID int) ([]models.WebhookEndpoint, error) {
+	var ems []webhookEndpointModel
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&ems).Error; err != nil {
+		logging.L.Error("failed to query webhook endpoints", "repository", "WebhookRepository", "method", "ListEndpointsByUserID", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to query webhook endpoints by user_id=%d: %w", userID, err)
+	}
+	endpoints := make([]models.WebhookEndpoint, 0, len(ems))
+	for i := range ems {
+		endpoints = append(endpoints, r.toEndpoint(&ems[i], false))
+	}
+	return endpoints, nil
+}
+
+func (r *WebhookRepository) ListActiveEndpointsForEvent(userID int, eventType string) ([]models.WebhookEndpoint, error) {
+	// ユーザーごとの登録先の数は多くない想定のため、有効な登録先をすべて取得してアプリケーション側で絞り込む
+	var ems []webhookEndpointModel
+	if err := r.db.Where("user_id = ? AND is_active = ?", userID, true).Order("id ASC").Find(&ems).Error; err != nil {
+		logging.L.Error("failed to query active webhook endpoints", "repository", "WebhookRepository", "method", "ListActiveEndpointsForEvent", "user_id", userID, "event_type", eventType, "error", err)
+		return nil, fmt.Errorf("failed to query active webhook endpoints: %w", err)
+	}
+	endpoints := []models.WebhookEndpoint{}
+	for i := range ems {
+		for _, e := range splitWebhookEvents(ems[i].Events) {
+			if e == eventType {
+				endpoints = append(endpoints, r.toEndpoint(&ems[i], false))
+				break
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+func (r *WebhookRepository) DeleteEndpoint(id int) error {
+	logging.L.Debug("deleting webhook endpoint", "repository", "WebhookRepository", "method", "DeleteEndpoint", "endpoint_id", id)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// ON DELETE CASCADE に頼らず配信ログも明示的に削除する（外部キーが無効な環境でも孤立行を残さない）
+		if err := tx.Where("endpoint_id = ?", id).Delete(&webhookDeliveryModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		result := tx.Where("id = ?", id).Delete(&webhookEndpointModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete webhook endpoint id=%d: %w", id, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrWebhookNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrWebhookNotFound) {
+			return repositories.ErrWebhookNotFound
+		}
+		logging.L.Error("failed to delete webhook endpoint", "repository", "WebhookRepository", "method", "DeleteEndpoint", "endpoint_id", id, "error", err)
+		return err
+	}
+	logging.L.Info("webhook endpoint deleted", "repository", "WebhookRepository", "method", "DeleteEndpoint", "endpoint_id", id)
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	now := r.db.NowFunc()
+	dms := make([]webhookDeliveryModel, 0, len(deliveries))
+	for _, d := range deliveries {
+		next := now
+		if d.NextAttemptAt != nil {
+			next = *d.NextAttemptAt
+		}
+		dms = append(dms, webhookDeliveryModel{
+			EndpointID:    int64(d.EndpointID),
+			EventID:       d.EventID,
+			EventType:     d.EventType,
+			Payload:       string(d.Payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &next,
+		})
+	}
+	if err := r.db.Create(&dms).Error; err != nil {
+		logging.L.Error("failed to enqueue webhook deliveries", "repository", "WebhookRepository", "method", "EnqueueDeliveries", "count", len(dms), "error", err)
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	for i := range dms {
+		deliveries[i].ID = int(dms[i].ID)
+	}
+	logging.L.Debug("webhook deliveries enqueued", "repository", "WebhookRepository", "method", "EnqueueDeliveries", "count", len(dms))
+	return nil
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var dms []webhookDeliveryModel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+			Order("next_attempt_at ASC, id ASC").
+			Limit(limit)
+		// 複数インスタンスで同じ配信を取り合わないよう、ロック済みの行は読み飛ばす（SQLite では無視される）
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := query.Find(&dms).Error; err != nil {
+			return fmt.Errorf("failed to query due webhook deliveries: %w", err)
+		}
+		if len(dms) == 0 {
+			return nil
+		}
+		ids := make([]int64, 0, len(dms))
+		for i := range dms {
+			ids = append(ids, dms[i].ID)
+		}
+		leaseUntil := now.Add(lease)
+		if err := tx.Model(&webhookDeliveryModel{}).Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error; err != nil {
+			return fmt.Errorf("failed to lease webhook deliveries: %w", err)
+		}
+		for i := range dms {
+			dms[i].NextAttemptAt = &leaseUntil
+		}
+		return nil
+	})
+	if err != nil {
+		logging.L.Error("failed to claim webhook deliveries", "repository", "WebhookRepository", "method", "ClaimDueDeliveries", "error", err)
+		return nil, err
+	}
+	deliveries := make([]models.WebhookDelivery, 0, len(dms))
+	for i := range dms {
+		deliveries = append(deliveries, r.toDelivery(&dms[i]))
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) RecordAttempt(delivery *models.WebhookDelivery) error {
+	if err := r.db.Model(&webhookDeliveryModel{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"last_attempt_at": delivery.LastAttemptAt,
+		"response_status": delivery.ResponseStatus,
+		"last_error":      delivery.LastError,
+		"updated_at":      r.db.NowFunc(),
+	}).Error; err != nil {
+		logging.L.Error("failed to record webhook attempt", "repository", "WebhookRepository", "method", "RecordAttempt", "delivery_id", delivery.ID, "error", err)
+		return fmt.Errorf("failed to record attempt of webhook delivery id=%d: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(endpointID, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	var total int64
+	if err := r.db.Model(&webhookDeliveryModel{}).Where("endpoint_id = ?", endpointID).Count(&total).Error; err != nil {
+		logging.L.Error("failed to count webhook deliveries", "repository", "WebhookRepository", "method", "ListDeliveries", "endpoint_id", endpointID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries of endpoint_id=%d: %w", endpointID, err)
+	}
+	var dms []webhookDeliveryModel
+	if err := r.db.Where("endpoint_id = ?", endpointID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&dms).Error; err != nil {
+		logging.L.Error("failed to query webhook deliveries", "repository", "WebhookRepository", "method", "ListDeliveries", "endpoint_id", endpointID, "error", err)
+		return nil, 0, fmt.Errorf("failed to query webhook deliveries of endpoint_id=%d: %w", endpointID, err)
+	}
+	deliveries := make([]models.WebhookDelivery, 0, len(dms))
+	for i := range dms {
+		deliveries = append(deliveries, r.toDelivery(&dms[i]))
+	}
+	return deliveries, int(total), nil
+}
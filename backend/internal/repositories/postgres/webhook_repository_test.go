@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+func createTestWebhookEndpoint(t *testing.T, repo *WebhookRepository, userID int, events ...string) *models.WebhookEndpoint {
+	t.Helper()
+	endpoint := &models.WebhookEndpoint{UserID: userID, URL: "https://example.com/hook", Events: events, IsActive: true, Secret: "secret"}
+	if err := repo.CreateEndpoint(endpoint); err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+	return endpoint
+}
+
+func TestWebhook_EndpointsAndEventFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWebhookRepository(db)
+	userID, _ := setupPostAndUser(t, db)
+
+	created := createTestWebhookEndpoint(t, repo, userID, models.WebhookEventPostCreated, models.WebhookEventLikeAdded)
+	createTestWebhookEndpoint(t, repo, userID, models.WebhookEventPostDeleted)
+
+	got, err := repo.GetEndpoint(created.ID)
+	if err != nil {
+		t.Fatalf("GetEndpoint failed: %v", err)
+	}
+	if got.Secret != "secret" || len(got.Events) != 2 || got.Events[1] != models.WebhookEventLikeAdded {
+		t.Fatalf("unexpected endpoint: %+v", got)
+	}
+
+	listed, err := repo.ListEndpointsByUserID(userID)
+	if err != nil {
+		t.Fatalf("ListEndpointsByUserID failed: %v", err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(listed))
+	}
+	for _, e := range listed {
+		if e.Secret != "" {
+			t.Fatalf("secret must not be listed: %+v", e)
+		}
+	}
+
+	subscribed, err := repo.ListActiveEndpointsForEvent(userID, models.WebhookEventLikeAdded)
+	if err != nil {
+		t.Fatalf("ListActiveEndpointsForEvent failed: %v", err)
+	}
+	if len(subscribed) != 1 || subscribed[0].ID != created.ID {
+		t.Fatalf("expected only endpoint %d, got %+v", created.ID, subscribed)
+	}
+	// 他のユーザーの登録先は配信対象にしない
+	if others, err := repo.ListActiveEndpointsForEvent(userID+1, models.WebhookEventLikeAdded); err != nil || len(others) != 0 {
+		t.Fatalf("expected no endpoints of other users, got %+v (err=%v)", others, err)
+	}
+
+	// 無効化された登録先は配信対象にしない
+	if err := db.Model(&webhookEndpointModel{}).Where("id = ?", created.ID).Update("is_active", false).Error; err != nil {
+		t.Fatalf("failed to deactivate endpoint: %v", err)
+	}
+	subscribed, err = repo.ListActiveEndpointsForEvent(userID, models.WebhookEventLikeAdded)
+	if err != nil {
+		t.Fatalf("ListActiveEndpointsForEvent failed: %v", err)
+	}
+	if len(subscribed) != 0 {
+		t.Fatalf("expected no active endpoints, got %+v", subscribed)
+	}
+}
+
+func TestWebhook_ClaimLeasesDueDeliveries(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWebhookRepository(db)
+	userID, _ := setupPostAndUser(t, db)
+	endpoint := createTestWebhookEndpoint(t, repo, userID, models.WebhookEventPostCreated)
+
+	now := time.Now()
+	later := now.Add(time.Hour)
+	if err := repo.EnqueueDeliveries([]models.WebhookDelivery{
+		{EndpointID: endpoint.ID, EventID: "e1", EventType: models.WebhookEventPostCreated, Payload: []byte(`{"id":"e1"}`)},
+		{EndpointID: endpoint.ID, EventID: "e2", EventType: models.WebhookEventPostCreated, Payload: []byte(`{"id":"e2"}`), NextAttemptAt: &later},
+	}); err != nil {
+		t.Fatalf("EnqueueDeliveries failed: %v", err)
+	}
+
+	claimed, err := repo.ClaimDueDeliveries(now.Add(time.Second), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].EventID != "e1" || string(claimed[0].Payload) != `{"id":"e1"}` {
+		t.Fatalf("expected only due delivery e1, got %+v", claimed)
+	}
+
+	// リース期間中は再度取り出されない
+	again, err := repo.ClaimDueDeliveries(now.Add(2*time.Second), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries failed: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("expected leased delivery to be skipped, got %+v", again)
+	}
+
+	// リース期限を過ぎると再度取り出される（送信中に停止した場合の再送）
+	expired, err := repo.ClaimDueDeliveries(now.Add(2*time.Minute), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries failed: %v", err)
+	}
+	if len(expired) != 1 || expired[0].EventID != "e1" {
+		t.Fatalf("expected e1 after lease expiry, got %+v", expired)
+	}
+}
+
+func TestWebhook_RecordAttemptAndList(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWebhookRepository(db)
+	userID, _ := setupPostAndUser(t, db)
+	endpoint := createTestWebhookEndpoint(t, repo, userID, models.WebhookEventPostCreated)
+
+	deliveries := []models.WebhookDelivery{{EndpointID: endpoint.ID, EventID: "e1", EventType: models.WebhookEventPostCreated, Payload: []byte(`{}`)}}
+	if err := repo.EnqueueDeliveries(deliveries); err != nil {
+		t.Fatalf("EnqueueDeliveries failed: %v", err)
+	}
+
+	now := time.Now()
+	status := 200
+	delivery := deliveries[0]
+	delivery.Status = models.WebhookDeliverySucceeded
+	delivery.Attempts = 1
+	delivery.NextAttemptAt = nil
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = &status
+	if err := repo.RecordAttempt(&delivery); err != nil {
+		t.Fatalf("RecordAttempt failed: %v", err)
+	}
+
+	// 成功した配信は再度取り出されない
+	claimed, err := repo.ClaimDueDeliveries(now.Add(time.Hour), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries failed: %v", err)
+	}
+	if len(claimed) != 0 {
+		t.Fatalf("expected no due deliveries, got %+v", claimed)
+	}
+
+	listed, total, err := repo.ListDeliveries(endpoint.ID, 20, 0)
+	if err != nil {
+		t.Fatalf("ListDeliveries failed: %v", err)
+	}
+	if total != 1 || len(listed) != 1 {
+		t.Fatalf("expected 1 delivery, got total=%d %+v", total, listed)
+	}
+	got := listed[0]
+	if got.Status != models.WebhookDeliverySucceeded || got.Attempts != 1 || got.NextAttemptAt != nil ||
+		got.ResponseStatus == nil || *got.ResponseStatus != 200 || got.LastAttemptAt == nil {
+		t.Fatalf("unexpected delivery: %+v", got)
+	}
+}
+
+func TestWebhook_DeleteEndpointRemovesDeliveries(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWebhookRepository(db)
+	userID, _ := setupPostAndUser(t, db)
+	endpoint := createTestWebhookEndpoint(t, repo, userID, models.WebhookEventPostCreated)
+	if err := repo.EnqueueDeliveries([]models.WebhookDelivery{{EndpointID: endpoint.ID, EventID: "e1", EventType: models.WebhookEventPostCreated, Payload: []byte(`{}`)}}); err != nil {
+		t.Fatalf("EnqueueDeliveries failed: %v", err)
+	}
+
+	if err := repo.DeleteEndpoint(endpoint.ID); err != nil {
+		t.Fatalf("DeleteEndpoint failed: %v", err)
+	}
+	if _, err := repo.GetEndpoint(endpoint.ID); !errors.Is(err, repositories.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+	var count int64
+	if err := db.Model(&webhookDeliveryModel{}).Count(&count).Error; err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected deliveries to be deleted, got %d", count)
+	}
+	if err := repo.DeleteEndpoint(endpoint.ID); !errors.Is(err, repositories.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound on second delete, got %v", err)
+	}
+}
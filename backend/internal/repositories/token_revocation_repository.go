@@ -0,0 +1,24 @@
+package repositories
+
+import "time"
+
+// TokenRevocationRepository はアクセストークンの無効化のデータアクセスのインターフェースを定義する
+type TokenRevocationRepository interface {
+	// RevokeAccessToken は、jti のアクセストークンを有効期限まで無効として記録する
+	// 記録済みの jti を再度無効化してもエラーにしない
+	RevokeAccessToken(jti string, userID int, expiresAt time.Time) error
+
+	// IsAccessTokenRevoked は、jti のアクセストークンが無効化されているかを返す
+	IsAccessTokenRevoked(jti string) (bool, error)
+
+	// ConsumeToken は、一度しか使えないトークンの jti を有効期限まで無効として記録し、初めて記録した場合に true を返す
+	// 記録済みの場合は false を返す。確認と記録を1回の書き込みで行うため、並行するリクエストのうち1つだけが true になる
+	ConsumeToken(jti string, userID int, expiresAt time.Time) (bool, error)
+
+	// IncrementTokenVersion は、ユーザーのトークンバージョンを1つ進め、それまでに発行したアクセストークンをすべて無効にする
+	// ユーザーが存在しない場合は ErrUserNotFound を返す
+	IncrementTokenVersion(userID int) error
+
+	// DeleteExpiredRevocations は、有効期限が before より前の無効化の記録を削除し、削除した件数を返す
+	DeleteExpiredRevocations(before time.Time) (int64, error)
+}
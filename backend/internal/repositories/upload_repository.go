@@ -21,6 +21,7 @@ type UploadRepository interface {
 	// レコードを deleted 状態に更新し、新規レコードを作成する。
 	// advisory lock はトランザクション終了時に自動解放されるため、初回アップロード時（既存行0件）の
 	// 並列リクエストでも「1枚のみ」が保証される。削除した旧レコードのファイルパス一覧を返す。
+	// 同一トランザクション内で ProfileImageReplaced イベントをアウトボックスに書き込む。
 	ReplaceProfileImage(newUpload *models.UploadDB) ([]string, error)
 }
 
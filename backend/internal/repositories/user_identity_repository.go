@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+)
+
+// プロバイダーのアカウントとの紐付けのセンチネルエラー
+var (
+	// ErrIdentityNotFound はプロバイダーのアカウントがどのユーザーにも紐付いていない場合のエラー
+	ErrIdentityNotFound = errors.New("user identity not found")
+	// ErrIdentityAlreadyLinked はプロバイダーのアカウントが紐付け済みの場合のエラー
+	ErrIdentityAlreadyLinked = errors.New("user identity already linked")
+)
+
+// UserIdentityRepository は OpenID Connect のプロバイダーのアカウントとユーザーの紐付けのデータアクセスのインターフェースを定義する
+type UserIdentityRepository interface {
+	// GetByProviderSubject は、プロバイダーのアカウントの紐付けを返す（存在しない場合は ErrIdentityNotFound）
+	GetByProviderSubject(provider, subject string) (*models.UserIdentity, error)
+
+	// Create は、プロバイダーのアカウントをユーザーに紐付ける（紐付け済みの場合は ErrIdentityAlreadyLinked）
+	Create(identity *models.UserIdentity) error
+}
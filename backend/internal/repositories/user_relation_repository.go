@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+)
+
+var (
+	// ErrAlreadyBlocked は、すでにブロックしているユーザーをブロックしようとした場合に返されるエラー
+	ErrAlreadyBlocked = errors.New("already blocked")
+	// ErrNotBlocked は、ブロックしていないユーザーのブロックを解除しようとした場合に返されるエラー
+	ErrNotBlocked = errors.New("not blocked")
+	// ErrAlreadyMuted は、すでにミュートしているユーザーをミュートしようとした場合に返されるエラー
+	ErrAlreadyMuted = errors.New("already muted")
+	// ErrNotMuted は、ミュートしていないユーザーのミュートを解除しようとした場合に返されるエラー
+	ErrNotMuted = errors.New("not muted")
+	// ErrBlocked は、投稿者にブロックされているユーザーがその投稿にいいね・リミックスしようとした場合に返されるエラー
+	ErrBlocked = errors.New("blocked by post owner")
+)
+
+// UserRelationRepository はユーザーのブロック・ミュートのデータアクセスのインターフェースを定義する
+type UserRelationRepository interface {
+	// Block は、blockerID が blockedID をブロックする
+	// すでにブロックしている場合は ErrAlreadyBlocked、blockedID のユーザーが存在しない場合は ErrUserNotFound を返す
+	Block(blockerID, blockedID int) error
+
+	// Unblock は、blockerID による blockedID のブロックを解除する
+	// ブロックしていない場合は ErrNotBlocked を返す
+	Unblock(blockerID, blockedID int) error
+
+	// ListBlocked は、userID がブロックしたユーザーをブロックした日時の新しい順に返す
+	ListBlocked(userID int) ([]models.RelatedUser, error)
+
+	// Mute は、muterID が mutedID をミュートする
+	// すでにミュートしている場合は ErrAlreadyMuted、mutedID のユーザーが存在しない場合は ErrUserNotFound を返す
+	Mute(muterID, mutedID int) error
+
+	// Unmute は、muterID による mutedID のミュートを解除する
+	// ミュートしていない場合は ErrNotMuted を返す
+	Unmute(muterID, mutedID int) error
+
+	// ListMuted は、userID がミュートしたユーザーをミュートした日時の新しい順に返す
+	ListMuted(userID int) ([]models.RelatedUser, error)
+}
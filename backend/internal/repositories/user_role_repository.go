@@ -0,0 +1,17 @@
+package repositories
+
+// UserRoleRepository はユーザーのロールのデータアクセスのインターフェースを定義する
+type UserRoleRepository interface {
+	// GetRole は、ユーザーの現在のロールを返す
+	// 存在しない場合は ErrUserNotFound を返す
+	GetRole(userID int) (string, error)
+
+	// UpdateRole は、ユーザーのロールを変更し、操作した管理者とともにモデレーション操作の記録に残す
+	// 存在しない場合は ErrUserNotFound を返す
+	UpdateRole(actorID, userID int, role string) error
+
+	// BootstrapAdmin は、管理者が1人もいない場合に限り、指定されたメールアドレスのユーザーを管理者にする
+	// 管理者にした場合は true、既に管理者がいる場合は false を返す
+	// 管理者がおらず該当するユーザーも存在しない場合は ErrUserNotFound を返す
+	BootstrapAdmin(email string) (bool, error)
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import "go-shisha-backend/internal/models"
+
+// UserStatsRepository はユーザー統計の集計を行うインターフェース
+type UserStatsRepository interface {
+	// GetUserStats は、指定されたユーザーの投稿・フレーバー・いいねの統計を集計して返す
+	// 論理削除された投稿は集計に含めない
+	// ユーザーの存在確認は行わないため、投稿のないユーザーの場合は各項目が0件の統計を返す
+	GetUserStats(userID int) (*models.UserStats, error)
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// ErrWebhookNotFound は、対象の Webhook 登録先が存在しない場合に返されるエラー
+var ErrWebhookNotFound = errors.New("webhook endpoint not found")
+
+// WebhookRepository は Webhook の登録先と配信キューのデータアクセスのインターフェースを定義する
+type WebhookRepository interface {
+	// CreateEndpoint は、Webhook の登録先を作成する（endpoint.Secret を保存する）
+	CreateEndpoint(endpoint *models.WebhookEndpoint) error
+
+	// GetEndpoint は、指定された ID の登録先を Secret を含めて返す
+	// 存在しない場合は ErrWebhookNotFound を返す
+	GetEndpoint(id int) (*models.WebhookEndpoint, error)
+
+	// ListEndpointsByUserID は、ユーザーが登録した登録先を作成日時の新しい順に返す（Secret は含めない）
+	ListEndpointsByUserID(userID int) ([]models.WebhookEndpoint, error)
+
+	// ListActiveEndpointsForEvent は、userID が登録した登録先のうち、指定されたイベントを購読している有効なものを返す
+	ListActiveEndpointsForEvent(userID int, eventType string) ([]models.WebhookEndpoint, error)
+
+	// DeleteEndpoint は、登録先と配信ログを削除する
+	// 存在しない場合は ErrWebhookNotFound を返す
+	DeleteEndpoint(id int) error
+
+	// EnqueueDeliveries は、配信を送信待ちとしてキューに追加する（NextAttemptAt が nil の場合は即時送信対象とする）
+	EnqueueDeliveries(deliveries []models.WebhookDelivery) error
+
+	// ClaimDueDeliveries は、送信予定日時を過ぎた送信待ちの配信を最大 limit 件取り出す
+	// 取り出した配信は次回送信予定を now+lease に延ばし、他のワーカーが同時に送信しないようにする
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+
+	// RecordAttempt は、送信結果を記録する
+	// delivery の Status・Attempts・NextAttemptAt・LastAttemptAt・ResponseStatus・LastError を保存する
+	RecordAttempt(delivery *models.WebhookDelivery) error
+
+	// ListDeliveries は、登録先の配信ログを作成日時の新しい順に返し、あわせて総数を返す
+	ListDeliveries(endpointID, limit, offset int) ([]models.WebhookDelivery, int, error)
+}
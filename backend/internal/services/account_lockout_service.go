@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/logging"
+	"go-shisha-backend/pkg/mailer"
+)
+
+// アカウントのロックのセンチネルエラー
+var (
+	// ErrLoginThrottled はログインの失敗が続いたアカウントで、待ち時間が過ぎるまで・ロックが解除されるまでログインを受け付けない場合のエラー（*LoginThrottledError で返す）
+	// パスワードを検証する前に返すため、パスワードが正しいかどうかは推測できない
+	ErrLoginThrottled = errors.New("login temporarily throttled")
+	// ErrAccountNotLocked はロックされていないアカウントのロックを解除しようとした場合のエラー
+	ErrAccountNotLocked = errors.New("account is not locked")
+	// ErrInvalidUnlockToken はロックの解除用トークンが不正・有効期限切れの場合のエラー
+	ErrInvalidUnlockToken = errors.New("invalid or expired account unlock token")
+)
+
+// LoginThrottledError は待ち時間中・ロック中のためログインを受け付けなかったことを表すエラー（errors.Is で ErrLoginThrottled に一致する）
+// RetryAfter が過ぎると次の試行を受け付ける
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrLoginThrottled.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrLoginThrottled
+}
+
+// LockoutPolicy はアカウントごとのログインの失敗への対応
+// FreeAttempts 回までの試行は待たずにやり直せ、それ以降は試行のたびに待ち時間を BaseDelay から倍にし（MaxDelay まで）、
+// LockThreshold 回目の試行も失敗するとアカウントを LockDuration の間ロックする。ロックの期限が過ぎた後と、最後の試行から ResetAfter が過ぎた後は数え直す
+type LockoutPolicy struct {
+	FreeAttempts  int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	LockThreshold int
+	LockDuration  time.Duration
+	ResetAfter    time.Duration
+}
+
+// DefaultLockoutPolicy は既定の LockoutPolicy（3回まで待たずにやり直せ、10回失敗すると30分ロックする）
+var DefaultLockoutPolicy = LockoutPolicy{
+	FreeAttempts:  3,
+	BaseDelay:     time.Second,
+	MaxDelay:      time.Minute,
+	LockThreshold: 10,
+	LockDuration:  30 * time.Minute,
+	ResetAfter:    24 * time.Hour,
+}
+
+// AccountLockoutService はアカウントごとのログインの試行を数え、失敗が続いたアカウントのログインを遅らせ・ロックする
+// IP ごとの制限をすり抜ける、多数の IP からの1つのアカウントへの総当たりを遅らせる
+// 同時の試行で待ち時間をすり抜けられないよう、試行はパスワードを検証する前に Reserve で数え、ログインに成功したら RecordSuccess で記録を消す
+// ロックしたときは本人にロックの解除用のリンクをメールで送り、管理者・モデレーターも解除できる
+type AccountLockoutService struct {
+	userRepo    repositories.AuthUserRepository
+	failureRepo repositories.LoginFailureRepository
+	mailer      mailer.Mailer
+	// unlockURL はメールに記載するロックの解除画面の URL（?token= を付けて送る）
+	unlockURL string
+	policy    LockoutPolicy
+	now       func() time.Time
+}
+
+// NewAccountLockoutService は新しい AccountLockoutService を作成する
+func NewAccountLockoutService(userRepo repositories.AuthUserRepository, failureRepo repositories.LoginFailureRepository, m mailer.Mailer, unlockURL string, policy LockoutPolicy) *AccountLockoutService {
+	return &AccountLockoutService{
+		userRepo:    userRepo,
+		failureRepo: failureRepo,
+		mailer:      m,
+		unlockURL:   unlockURL,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// Reserve はパスワードを検証する前にユーザーのログインの試行を1回分確保する（確保した試行は、ログインに成功するまで失敗として数える）
+// ロック中、または前回の試行からの待ち時間が過ぎていない場合は数えずに、次の試行を受け付けるまでの時間の *LoginThrottledError を返す
+func (s *AccountLockoutService) Reserve(userID int) error {
+	now := s.now()
+	if _, err := s.failureRepo.Reserve(userID, now, now.Add(-s.policy.ResetAfter), s.delay); err != nil {
+		if errors.Is(err, repositories.ErrLoginAttemptThrottled) {
+			return &LoginThrottledError{RetryAfter: s.retryAfter(userID, now)}
+		}
+		return fmt.Errorf("failed to reserve login attempt: %w", err)
+	}
+	return nil
+}
+
+// retryAfter は待ち時間中・ロック中のユーザーが次の試行を受け付けられるまでの時間を返す（取得できない場合は0）
+func (s *AccountLockoutService) retryAfter(userID int, now time.Time) time.Duration {
+	failure, err := s.failureRepo.Get(userID)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrLoginFailureNotFound) {
+			logging.L.Error("failed to get login failure", "service", "AccountLockoutService", "method", "retryAfter", "user_id", userID, "error", err)
+		}
+		return 0
+	}
+	until := failure.NextAttemptAt
+	if failure.LockedUntil != nil && failure.LockedUntil.After(until) {
+		until = *failure.LockedUntil
+	}
+	return max(until.Sub(now), 0)
+}
+
+// RecordFailure は Reserve で確保した試行が失敗したことを記録し、LockThreshold 回目の試行も失敗したらアカウントをロックして本人にメールを送る
+func (s *AccountLockoutService) RecordFailure(user *models.User) error {
+	failure, err := s.failureRepo.Get(user.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrLoginFailureNotFound) {
+			// 同時のログインの成功で記録が消えた
+			return nil
+		}
+		return fmt.Errorf("failed to get login failure: %w", err)
+	}
+	if failure.FailedCount < s.policy.LockThreshold {
+		return nil
+	}
+
+	lockedUntil := s.now().Add(s.policy.LockDuration)
+	if err := s.failureRepo.Lock(user.ID, lockedUntil); err != nil {
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+	logging.L.Warn("account locked after repeated login failures", "service", "AccountLockoutService", "method", "RecordFailure", "user_id", user.ID, "failed_count", failure.FailedCount)
+	return s.sendUnlockMail(user, lockedUntil)
+}
+
+// RecordSuccess はログインに成功したユーザーの失敗の記録を消す
+func (s *AccountLockoutService) RecordSuccess(userID int) error {
+	if err := s.failureRepo.Delete(userID); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
+
+// Unlock はロックしたときにメールで送ったリンクのトークンでロックを解除する
+// ロックが期限切れ・解除済みの場合も成功とする
+func (s *AccountLockoutService) Unlock(token string) error {
+	claims, err := auth.ValidateAccountUnlockToken(token)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+	if err := s.failureRepo.Delete(int(claims.UserID)); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+	logging.L.Info("account unlocked by email", "service", "AccountLockoutService", "method", "Unlock", "user_id", claims.UserID)
+	return nil
+}
+
+// AdminUnlock は管理者・モデレーターがユーザーのロックを解除する（待ち時間もなくし、試行の回数も数え直す）
+// ユーザーが存在しない場合は repositories.ErrUserNotFound、ロックされていない場合は ErrAccountNotLocked を返す
+func (s *AccountLockoutService) AdminUnlock(moderatorID, userID int) error {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+	failure, err := s.failureRepo.Get(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrLoginFailureNotFound) {
+			return ErrAccountNotLocked
+		}
+		return fmt.Errorf("failed to get login failure: %w", err)
+	}
+	if failure.LockedUntil == nil || !s.now().Before(*failure.LockedUntil) {
+		return ErrAccountNotLocked
+	}
+	if err := s.failureRepo.Delete(userID); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+	logging.L.Info("account unlocked by moderator", "service", "AccountLockoutService", "method", "AdminUnlock", "moderator_id", moderatorID, "user_id", userID)
+	return nil
+}
+
+// delay は failedCount 回試行した後、次の試行を受け付けるまでの待ち時間を返す
+func (s *AccountLockoutService) delay(failedCount int) time.Duration {
+	if failedCount <= s.policy.FreeAttempts {
+		return 0
+	}
+	delay := s.policy.BaseDelay
+	for i := s.policy.FreeAttempts + 1; i < failedCount && delay < s.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, s.policy.MaxDelay)
+}
+
+// sendUnlockMail はロックしたアカウントの本人にロックの解除用のリンクを送る（メールはバックグラウンドで送信する）
+func (s *AccountLockoutService) sendUnlockMail(user *models.User, lockedUntil time.Time) error {
+	token, err := auth.GenerateAccountUnlockToken(int64(user.ID), lockedUntil)
+	if err != nil {
+		return fmt.Errorf("failed to generate account unlock token: %w", err)
+	}
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "アカウントのロック",
+		Body: fmt.Sprintf("%s さん\n\n"+
+			"ログインの失敗が続いたため、アカウントを%d分間ロックしました。ご本人の操作の場合は、以下のリンクからロックを解除できます。\n\n"+
+			"%s?token=%s\n\n"+
+			"ご本人の操作でない場合は、第三者がパスワードを試している可能性があります。ロックは時間が経つと解除されますが、パスワードの再設定をおすすめします。\n",
+			user.DisplayName, int(s.policy.LockDuration.Minutes()), s.unlockURL, url.QueryEscape(token)),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			logging.L.Error("failed to send account unlock mail", "service", "AccountLockoutService", "method", "sendUnlockMail", "user_id", user.ID, "error", err)
+		}
+	}()
+	return nil
+}
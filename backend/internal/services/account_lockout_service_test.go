@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/mailer"
+)
+
+// memoryLoginFailureRepo はテスト用のインメモリ LoginFailureRepository
+type memoryLoginFailureRepo struct {
+	mu       sync.Mutex
+	failures map[int]*models.LoginFailure
+}
+
+func newMemoryLoginFailureRepo() *memoryLoginFailureRepo {
+	return &memoryLoginFailureRepo{failures: make(map[int]*models.LoginFailure)}
+}
+
+func (r *memoryLoginFailureRepo) Get(userID int) (*models.LoginFailure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failure, ok := r.failures[userID]
+	if !ok {
+		return nil, repositories.ErrLoginFailureNotFound
+	}
+	copied := *failure
+	return &copied, nil
+}
+
+func (r *memoryLoginFailureRepo) Reserve(userID int, at, since time.Time, delay func(failedCount int) time.Duration) (*models.LoginFailure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failure, ok := r.failures[userID]
+	if !ok {
+		failure = &models.LoginFailure{UserID: userID}
+		r.failures[userID] = failure
+	} else if at.Before(failure.NextAttemptAt) || (failure.LockedUntil != nil && at.Before(*failure.LockedUntil)) {
+		return nil, repositories.ErrLoginAttemptThrottled
+	}
+	if failure.LastFailedAt.Before(since) || failure.LockedUntil != nil {
+		failure.FailedCount = 0
+		failure.LockedUntil = nil
+	}
+	failure.FailedCount++
+	failure.LastFailedAt = at
+	failure.NextAttemptAt = at.Add(delay(failure.FailedCount))
+	copied := *failure
+	return &copied, nil
+}
+
+func (r *memoryLoginFailureRepo) Lock(userID int, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if failure, ok := r.failures[userID]; ok {
+		failure.LockedUntil = &until
+	}
+	return nil
+}
+
+func (r *memoryLoginFailureRepo) Delete(userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failures, userID)
+	return nil
+}
+
+func TestAccountLockout(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	failureRepo := newMemoryLoginFailureRepo()
+	mail := &recordingMailer{sent: make(chan mailer.Message, 1)}
+	svc := NewAccountLockoutService(userRepo, failureRepo, mail, "http://localhost:3000/unlock-account", LockoutPolicy{
+		FreeAttempts:  2,
+		BaseDelay:     time.Second,
+		MaxDelay:      4 * time.Second,
+		LockThreshold: 5,
+		LockDuration:  30 * time.Minute,
+		ResetAfter:    time.Hour,
+	})
+	// ロックの解除用トークンの有効期限は実時刻で検証されるため、現在時刻を起点にする
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	authService := NewAuthService(userRepo, newMockRefreshTokenRepo(), NewTokenRevocationStore(newMemoryTokenRevocationRepo()), nopVerificationSender{}, disabledMFAVerifier{}, svc)
+
+	user := newMFATestUser(t, userRepo, "lockout@example.com")
+	login := func(password string) error {
+		_, _, _, err := authService.Login(&models.LoginInput{Email: user.Email, Password: password}, models.SessionDevice{})
+		return err
+	}
+	receiveToken := func(t *testing.T) string {
+		t.Helper()
+		select {
+		case msg := <-mail.sent:
+			if msg.To != user.Email {
+				t.Fatalf("unexpected recipient: %s", msg.To)
+			}
+			match := regexp.MustCompile(`http://localhost:3000/unlock-account\?token=(\S+)`).FindStringSubmatch(msg.Body)
+			if match == nil {
+				t.Fatalf("expected unlock link in mail body, got %q", msg.Body)
+			}
+			token, err := url.QueryUnescape(match[1])
+			if err != nil {
+				t.Fatalf("failed to unescape token: %v", err)
+			}
+			return token
+		case <-time.After(time.Second):
+			t.Fatal("expected unlock mail to be sent")
+			return ""
+		}
+	}
+
+	t.Run("正常系: ログインに成功すると失敗の回数を数え直す", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if err := login("wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+				t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+			}
+		}
+		if err := login("password12345"); err != nil {
+			t.Fatalf("expected login to succeed within free attempts, got %v", err)
+		}
+		if _, err := failureRepo.Get(user.ID); !errors.Is(err, repositories.ErrLoginFailureNotFound) {
+			t.Fatalf("expected failures to be reset, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 同時の試行でも待ち時間をすり抜けられない", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = login("wrong-password")
+			}()
+		}
+		wg.Wait()
+		// 待たずに試行できるのは FreeAttempts 回と、待ち時間が始まる1回だけ
+		failure, err := failureRepo.Get(user.ID)
+		if err != nil || failure.FailedCount != 3 {
+			t.Fatalf("expected 3 reserved attempts, got %+v (err=%v)", failure, err)
+		}
+		if err := svc.RecordSuccess(user.ID); err != nil {
+			t.Fatalf("RecordSuccess failed: %v", err)
+		}
+	})
+
+	t.Run("正常系: 失敗が続くと待ち時間が延び、しきい値に達するとロックし、メールのリンクで解除できる", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if err := login("wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+				t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+			}
+		}
+		// 待ち時間中はパスワードが正しくても、検証せずに次の試行までの時間を返す
+		var throttled *LoginThrottledError
+		if err := login("password12345"); !errors.As(err, &throttled) || throttled.RetryAfter != time.Second {
+			t.Fatalf("expected LoginThrottledError retrying after 1s, got %v", err)
+		}
+		now = now.Add(time.Second)
+		if err := login("wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+		// 4回目の試行の後は2秒待つ
+		now = now.Add(time.Second)
+		if err := svc.Reserve(user.ID); !errors.Is(err, ErrLoginThrottled) {
+			t.Fatalf("expected ErrLoginThrottled, got %v", err)
+		}
+		now = now.Add(time.Second)
+		if err := login("wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+		token := receiveToken(t)
+
+		now = now.Add(10 * time.Minute)
+		if err := login("password12345"); !errors.As(err, &throttled) || throttled.RetryAfter != 20*time.Minute {
+			t.Fatalf("expected LoginThrottledError retrying after the lock, got %v", err)
+		}
+		if err := svc.Unlock("not-a-token"); !errors.Is(err, ErrInvalidUnlockToken) {
+			t.Fatalf("expected ErrInvalidUnlockToken, got %v", err)
+		}
+		if err := svc.Unlock(token); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := login("password12345"); err != nil {
+			t.Fatalf("expected login to succeed after unlock, got %v", err)
+		}
+	})
+
+	// lockOut は待ち時間を待ちながらしきい値まで失敗し、ロックのメールを受け取る
+	lockOut := func(t *testing.T) {
+		t.Helper()
+		for i := 0; i < 5; i++ {
+			now = now.Add(4 * time.Second)
+			if err := login("wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+				t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+			}
+		}
+		receiveToken(t)
+	}
+
+	t.Run("正常系: 管理者がロックを解除し、ロックされていない場合は ErrAccountNotLocked", func(t *testing.T) {
+		if err := svc.AdminUnlock(99, user.ID); !errors.Is(err, ErrAccountNotLocked) {
+			t.Fatalf("expected ErrAccountNotLocked, got %v", err)
+		}
+		lockOut(t)
+		now = now.Add(10 * time.Minute)
+		if err := svc.Reserve(user.ID); !errors.Is(err, ErrLoginThrottled) {
+			t.Fatalf("expected locked account, got %v", err)
+		}
+		if err := svc.AdminUnlock(99, user.ID); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := login("password12345"); err != nil {
+			t.Fatalf("expected login to succeed after unlock, got %v", err)
+		}
+	})
+
+	t.Run("正常系: ロックは期限が過ぎると解除される", func(t *testing.T) {
+		lockOut(t)
+		now = now.Add(30 * time.Minute)
+		if err := svc.AdminUnlock(99, user.ID); !errors.Is(err, ErrAccountNotLocked) {
+			t.Fatalf("expected ErrAccountNotLocked for expired lock, got %v", err)
+		}
+		if err := login("password12345"); err != nil {
+			t.Fatalf("expected lock to expire, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 期限が過ぎたロックの後は1回失敗してもロックし直さない", func(t *testing.T) {
+		lockOut(t)
+		now = now.Add(30 * time.Minute)
+		if err := login("wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+		failure, err := failureRepo.Get(user.ID)
+		if err != nil || failure.FailedCount != 1 || failure.LockedUntil != nil {
+			t.Fatalf("expected count to restart after the lock expired, got %+v (err=%v)", failure, err)
+		}
+		select {
+		case <-mail.sent:
+			t.Fatal("expected account not to be locked again")
+		default:
+		}
+		if err := login("password12345"); err != nil {
+			t.Fatalf("expected login to succeed, got %v", err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"go-shisha-backend/internal/models"
@@ -11,6 +12,7 @@ import (
 	"go-shisha-backend/pkg/auth"
 	"go-shisha-backend/pkg/logging"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -20,19 +22,81 @@ var (
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	// ErrInvalidRefreshToken はRefresh Tokenが無効または期限切れの場合のエラー
 	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused はローテーション済みのRefresh Tokenが再度使われた場合のエラー（ファミリーごと無効化済み）
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	// ErrSessionNotFound は指定されたセッションが存在しないか、他のユーザーのものである場合のエラー
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrIncorrectPassword は確認のために入力された現在のパスワードが誤っている場合のエラー
+	ErrIncorrectPassword = errors.New("incorrect password")
+	// ErrMFARequired はパスワードは正しいが2段階認証のコードの入力が必要な場合のエラー（*MFAChallengeError で返す）
+	ErrMFARequired = errors.New("two-factor authentication required")
+	// ErrInvalidMFAChallenge は2段階認証のチャレンジトークンが不正・有効期限切れ・使用済みの場合のエラー
+	ErrInvalidMFAChallenge = errors.New("invalid or expired mfa challenge")
 )
 
+// MFAChallengeError は2段階認証が有効なユーザーのログインでパスワードを確認したことを表すエラー（errors.Is で ErrMFARequired に一致する）
+// ChallengeToken と2段階認証のコードを CompleteMFALogin に渡すとログインが完了する
+type MFAChallengeError struct {
+	ChallengeToken string
+	ExpiresAt      time.Time
+}
+
+func (e *MFAChallengeError) Error() string {
+	return ErrMFARequired.Error()
+}
+
+func (e *MFAChallengeError) Unwrap() error {
+	return ErrMFARequired
+}
+
+// maxUserAgentLength は記録する User-Agent の最大長
+const maxUserAgentLength = 512
+
+// refreshTokenTTL はRefresh Tokenの有効期間
+const refreshTokenTTL = 7 * 24 * time.Hour
+
+// verificationSender は登録したユーザーにメールアドレスの確認用のリンクを送る（EmailVerificationService が実装する）
+type verificationSender interface {
+	SendVerification(user *models.User) error
+}
+
+// mfaVerifier はログイン時の2段階認証を扱う（MFAService が実装する）
+type mfaVerifier interface {
+	IsEnabled(userID int) (bool, error)
+	VerifyLoginCode(userID int, code string) error
+}
+
+// loginLimiter はアカウントごとのログインの試行を数え、失敗が続いたアカウントのログインを止める（AccountLockoutService が実装する）
+// 試行はパスワード・コードを検証する前に Reserve で数え、ログインに成功したら RecordSuccess で記録を消す
+type loginLimiter interface {
+	Reserve(userID int) error
+	RecordFailure(user *models.User) error
+	RecordSuccess(userID int) error
+}
+
 // AuthService は認証サービスのインターフェース
 type AuthService struct {
 	userRepo         repositories.AuthUserRepository
 	refreshTokenRepo postgres.RefreshTokenRepository
+	// revocations はアクセストークンの無効化を記録する
+	revocations *TokenRevocationStore
+	// verifier は登録時にメールアドレスの確認用のリンクを送る
+	verifier verificationSender
+	// mfa はログイン時に2段階認証を求める
+	mfa mfaVerifier
+	// limiter はアカウントごとの総当たりを遅らせる
+	limiter loginLimiter
 }
 
 // NewAuthService はAuthServiceの新しいインスタンスを作成
-func NewAuthService(userRepo repositories.AuthUserRepository, refreshTokenRepo postgres.RefreshTokenRepository) *AuthService {
+func NewAuthService(userRepo repositories.AuthUserRepository, refreshTokenRepo postgres.RefreshTokenRepository, revocations *TokenRevocationStore, verifier verificationSender, mfa mfaVerifier, limiter loginLimiter) *AuthService {
 	return &AuthService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		revocations:      revocations,
+		verifier:         verifier,
+		mfa:              mfa,
+		limiter:          limiter,
 	}
 }
 
@@ -47,6 +111,7 @@ func (s *AuthService) Register(input *models.CreateUserInput) (*models.User, err
 	user := &models.User{
 		Email:       input.Email,
 		DisplayName: input.DisplayName,
+		Role:        models.RoleUser,
 	}
 
 	// パスワードをハッシュ化
@@ -82,11 +147,24 @@ func (s *AuthService) Register(input *models.CreateUserInput) (*models.User, err
 		"user_id", user.ID,
 		"email", user.Email)
 
+	// 確認メールの送信に失敗しても登録は成功とし、再送できるようにする
+	if err := s.verifier.SendVerification(user); err != nil {
+		logging.L.Error("failed to send verification mail",
+			"service", "AuthService",
+			"method", "Register",
+			"user_id", user.ID,
+			"error", err)
+	}
+
 	return user, nil
 }
 
 // Login はユーザーのログイン処理を行い、トークンを生成
-func (s *AuthService) Login(input *models.LoginInput) (*models.User, string, string, error) {
+// 2段階認証が有効なユーザーの場合はトークンを生成せず、チャレンジトークンを含む *MFAChallengeError を返す
+// ログインごとに新しいセッション（Refresh Token のファミリー）を作り、device を端末の情報として記録する
+// 失敗が続いてロック中・待ち時間中のアカウントは、パスワードを検証せずに *LoginThrottledError を返す
+// 2段階認証が有効なユーザーの試行は、2段階認証のログインが完了するまで失敗として数える
+func (s *AuthService) Login(input *models.LoginInput, device models.SessionDevice) (*models.User, string, string, error) {
 	logging.L.Info("user login attempt",
 		"service", "AuthService",
 		"method", "Login",
@@ -110,24 +188,61 @@ func (s *AuthService) Login(input *models.LoginInput) (*models.User, string, str
 		return nil, "", "", fmt.Errorf("failed to get user: %w", err)
 	}
 
+	// 失敗が続いたアカウントは、パスワードを検証せずに *LoginThrottledError を返す
+	if err := s.reserveLoginAttempt(user.ID, "Login"); err != nil {
+		return nil, "", "", err
+	}
+
 	// パスワードを検証
 	if err := user.CheckPassword(input.Password); err != nil {
 		logging.L.Warn("invalid password",
 			"service", "AuthService",
 			"method", "Login",
 			"user_id", user.ID)
+		s.recordLoginFailure(user, "Login")
 		return nil, "", "", ErrInvalidCredentials
 	}
 
-	// Access Tokenを生成
-	accessToken, err := auth.GenerateAccessToken(int64(user.ID))
+	// 利用停止中のユーザーはログインさせない（パスワードが正しい場合のみ理由を返す）
+	if err := checkSuspension(user); err != nil {
+		logging.L.Warn("suspended user login refused",
+			"service", "AuthService",
+			"method", "Login",
+			"user_id", user.ID)
+		return nil, "", "", err
+	}
+
+	// 2段階認証が有効なユーザーには、トークンの代わりにチャレンジトークンを返す
+	if err := s.requireMFA(user.ID, "Login"); err != nil {
+		return nil, "", "", err
+	}
+
+	s.recordLoginSuccess(user.ID, "Login")
+	accessToken, refreshToken, err := s.startSession(user, device)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	logging.L.Info("user logged in successfully",
+		"service", "AuthService",
+		"method", "Login",
+		"user_id", user.ID)
+
+	return user, accessToken, refreshToken, nil
+}
+
+// startSession は認証済みのユーザーの新しいセッション（Refresh Token のファミリー）を作り、Access Token と Refresh Token を返す
+func (s *AuthService) startSession(user *models.User, device models.SessionDevice) (string, string, error) {
+	// Access Tokenを生成（Refresh Tokenと同じセッションIDを含める）
+	sessionID := uuid.NewString()
+	accessToken, err := auth.GenerateAccessToken(int64(user.ID), user.Role, user.TokenVersion, sessionID)
 	if err != nil {
 		logging.L.Error("failed to generate access token",
 			"service", "AuthService",
-			"method", "Login",
+			"method", "startSession",
 			"user_id", user.ID,
 			"error", err)
-		return nil, "", "", fmt.Errorf("failed to generate access token: %w", err)
+		return "", "", fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Refresh Tokenを生成
@@ -135,48 +250,210 @@ func (s *AuthService) Login(input *models.LoginInput) (*models.User, string, str
 	if err != nil {
 		logging.L.Error("failed to generate refresh token",
 			"service", "AuthService",
-			"method", "Login",
+			"method", "startSession",
 			"user_id", user.ID,
 			"error", err)
-		return nil, "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// Refresh TokenをDBに保存
+	// Refresh TokenをDBに保存（ログインごとに新しいファミリーを作る）
+	now := time.Now()
 	tokenModel := &models.RefreshToken{
-		UserID:    int64(user.ID),
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+		UserID:           int64(user.ID),
+		ExpiresAt:        now.Add(refreshTokenTTL),
+		FamilyID:         sessionID,
+		UserAgent:        truncateUserAgent(device.UserAgent),
+		IPAddress:        device.IPAddress,
+		SessionStartedAt: now,
 	}
 	if err := s.refreshTokenRepo.Create(tokenModel, refreshToken); err != nil {
 		logging.L.Error("failed to save refresh token",
 			"service", "AuthService",
-			"method", "Login",
+			"method", "startSession",
 			"user_id", user.ID,
 			"error", err)
-		return nil, "", "", fmt.Errorf("failed to save refresh token: %w", err)
+		return "", "", fmt.Errorf("failed to save refresh token: %w", err)
 	}
 
+	return accessToken, refreshToken, nil
+}
+
+// LoginAuthenticatedUser はパスワード以外の方法（パスキーなど）で本人確認したユーザーのセッションを開始する
+// 利用停止中のユーザーの場合は *auth.SuspensionError を返す
+func (s *AuthService) LoginAuthenticatedUser(userID int, device models.SessionDevice) (*models.User, string, string, error) {
+	return s.loginVerifiedUser(userID, device, false, "LoginAuthenticatedUser")
+}
+
+// LoginFederatedUser は外部のプロバイダー（OpenID Connect）で本人確認したユーザーのセッションを開始する
+// プロバイダーでのログインはパスワードの代わりにすぎないため、2段階認証が有効なユーザーには *MFAChallengeError を返し、CompleteMFALogin でログインを完了させる
+// 利用停止中のユーザーの場合は *auth.SuspensionError を返す
+func (s *AuthService) LoginFederatedUser(userID int, device models.SessionDevice) (*models.User, string, string, error) {
+	return s.loginVerifiedUser(userID, device, true, "LoginFederatedUser")
+}
+
+// loginVerifiedUser は本人確認したユーザーのセッションを開始する（checkMFA が true の場合は2段階認証が有効なユーザーにチャレンジトークンを返す）
+func (s *AuthService) loginVerifiedUser(userID int, device models.SessionDevice, checkMFA bool, method string) (*models.User, string, string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := checkSuspension(user); err != nil {
+		logging.L.Warn("suspended user login refused",
+			"service", "AuthService",
+			"method", method,
+			"user_id", user.ID)
+		return nil, "", "", err
+	}
+	if checkMFA {
+		if err := s.requireMFA(user.ID, method); err != nil {
+			return nil, "", "", err
+		}
+	}
+	accessToken, refreshToken, err := s.startSession(user, device)
+	if err != nil {
+		return nil, "", "", err
+	}
 	logging.L.Info("user logged in successfully",
 		"service", "AuthService",
-		"method", "Login",
+		"method", method,
 		"user_id", user.ID)
+	return user, accessToken, refreshToken, nil
+}
 
+// requireMFA は2段階認証が有効なユーザーの場合、チャレンジトークンを発行して *MFAChallengeError を返す（無効な場合は nil を返す）
+func (s *AuthService) requireMFA(userID int, method string) error {
+	enabled, err := s.mfa.IsEnabled(userID)
+	if err != nil {
+		return fmt.Errorf("failed to check two-factor authentication: %w", err)
+	}
+	if !enabled {
+		return nil
+	}
+	challengeToken, expiresAt, err := auth.GenerateMFAChallengeToken(int64(userID))
+	if err != nil {
+		return fmt.Errorf("failed to generate mfa challenge token: %w", err)
+	}
+	logging.L.Info("two-factor authentication required",
+		"service", "AuthService",
+		"method", method,
+		"user_id", userID)
+	return &MFAChallengeError{ChallengeToken: challengeToken, ExpiresAt: expiresAt}
+}
+
+// CompleteMFALogin はログインで返したチャレンジトークンと2段階認証のコードを検証し、ログインを完了する
+// チャレンジトークンが不正・有効期限切れ・使用済みの場合は ErrInvalidMFAChallenge、コードが誤っている場合は ErrInvalidMFACode を返す
+// チャレンジトークンはコードの検証の前に使用済みにするため、コードが誤っている場合もログインからやり直す
+// 失敗が続いてロック中・待ち時間中のアカウントは、コードを検証せずに *LoginThrottledError を返す
+func (s *AuthService) CompleteMFALogin(challengeToken, code string, device models.SessionDevice) (*models.User, string, string, error) {
+	claims, err := auth.ValidateMFAChallengeToken(challengeToken)
+	if err != nil {
+		return nil, "", "", ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.GetByID(int(claims.UserID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, "", "", ErrInvalidMFAChallenge
+		}
+		return nil, "", "", fmt.Errorf("failed to get user: %w", err)
+	}
+	// 同じチャレンジトークンで並行してコードを送っても1つしか検証しないよう、確認と使用済みの記録を1回で行う
+	consumed, err := s.revocations.Consume(claims.ID, user.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to consume mfa challenge token: %w", err)
+	}
+	if !consumed {
+		return nil, "", "", ErrInvalidMFAChallenge
+	}
+	if err := checkSuspension(user); err != nil {
+		return nil, "", "", err
+	}
+	// コードの総当たりもパスワードと同じく数える
+	if err := s.reserveLoginAttempt(user.ID, "CompleteMFALogin"); err != nil {
+		return nil, "", "", err
+	}
+	if err := s.mfa.VerifyLoginCode(user.ID, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			logging.L.Warn("invalid two-factor authentication code",
+				"service", "AuthService",
+				"method", "CompleteMFALogin",
+				"user_id", user.ID)
+			s.recordLoginFailure(user, "CompleteMFALogin")
+			return nil, "", "", ErrInvalidMFACode
+		}
+		if errors.Is(err, ErrMFANotEnabled) {
+			// チャレンジトークンの発行後に2段階認証が無効にされた
+			return nil, "", "", ErrInvalidMFAChallenge
+		}
+		return nil, "", "", fmt.Errorf("failed to verify two-factor authentication code: %w", err)
+	}
+
+	s.recordLoginSuccess(user.ID, "CompleteMFALogin")
+	accessToken, refreshToken, err := s.startSession(user, device)
+	if err != nil {
+		return nil, "", "", err
+	}
+	logging.L.Info("user logged in with two-factor authentication",
+		"service", "AuthService",
+		"method", "CompleteMFALogin",
+		"user_id", user.ID)
 	return user, accessToken, refreshToken, nil
 }
 
-// Refresh はRefresh Tokenを使ってAccess Tokenを再発行
-func (s *AuthService) Refresh(refreshToken string) (string, error) {
+// reserveLoginAttempt はパスワード・コードを検証する前にアカウントのログインの試行を確保する（ロック中・待ち時間中は *LoginThrottledError を返す）
+func (s *AuthService) reserveLoginAttempt(userID int, method string) error {
+	if err := s.limiter.Reserve(userID); err != nil {
+		if errors.Is(err, ErrLoginThrottled) {
+			logging.L.Warn("login throttled",
+				"service", "AuthService",
+				"method", method,
+				"user_id", userID)
+			return err
+		}
+		return fmt.Errorf("failed to reserve login attempt: %w", err)
+	}
+	return nil
+}
+
+// recordLoginFailure は確保した試行が失敗したことを記録する（記録に失敗してもログインの結果は変えない）
+func (s *AuthService) recordLoginFailure(user *models.User, method string) {
+	if err := s.limiter.RecordFailure(user); err != nil {
+		logging.L.Error("failed to record login failure",
+			"service", "AuthService",
+			"method", method,
+			"user_id", user.ID,
+			"error", err)
+	}
+}
+
+// recordLoginSuccess はログインに成功したアカウントの失敗の記録を消す（消せなくてもログインは続ける）
+func (s *AuthService) recordLoginSuccess(userID int, method string) {
+	if err := s.limiter.RecordSuccess(userID); err != nil {
+		logging.L.Error("failed to reset login failures",
+			"service", "AuthService",
+			"method", method,
+			"user_id", userID,
+			"error", err)
+	}
+}
+
+// Refresh はRefresh Tokenを使ってAccess Tokenを再発行し、Refresh Tokenをローテーションする
+// 戻り値は新しいAccess Tokenと新しいRefresh Token（使ったRefresh Tokenはこれ以降使えない）
+// ローテーション済みのRefresh Tokenが使われた場合は漏洩とみなし、ファミリーごと無効化して ErrRefreshTokenReused を返す
+// device はセッションの端末の情報として記録する（空の項目はそれまでの値を引き継ぐ）
+func (s *AuthService) Refresh(refreshToken string, device models.SessionDevice) (string, string, error) {
 	logging.L.Info("refreshing access token",
 		"service", "AuthService",
 		"method", "Refresh")
 
 	// Refresh Tokenを検証
-	claims, err := auth.ValidateToken(refreshToken)
+	claims, err := auth.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		logging.L.Warn("invalid refresh token",
 			"service", "AuthService",
 			"method", "Refresh",
 			"error", err)
-		return "", ErrInvalidRefreshToken
+		return "", "", ErrInvalidRefreshToken
 	}
 
 	// DBからRefresh Tokenを検索
@@ -186,13 +463,13 @@ func (s *AuthService) Refresh(refreshToken string) (string, error) {
 			logging.L.Warn("refresh token not found in database",
 				"service", "AuthService",
 				"method", "Refresh")
-			return "", ErrInvalidRefreshToken
+			return "", "", ErrInvalidRefreshToken
 		}
 		logging.L.Error("failed to query refresh token",
 			"service", "AuthService",
 			"method", "Refresh",
 			"error", err)
-		return "", fmt.Errorf("failed to query refresh token: %w", err)
+		return "", "", fmt.Errorf("failed to query refresh token: %w", err)
 	}
 
 	// 有効期限をチェック
@@ -201,28 +478,83 @@ func (s *AuthService) Refresh(refreshToken string) (string, error) {
 			"service", "AuthService",
 			"method", "Refresh",
 			"token_id", tokenModel.ID)
-		return "", ErrInvalidRefreshToken
+		return "", "", ErrInvalidRefreshToken
 	}
 
-	// 新しいAccess Tokenを生成
-	newAccessToken, err := auth.GenerateAccessToken(claims.UserID)
+	// ローテーション済みのトークンの再利用は漏洩とみなす
+	if tokenModel.RotatedAt != nil {
+		return "", "", s.revokeReusedFamily(tokenModel)
+	}
+
+	// ロールの変更を反映するため、現在のロールを取得して新しいAccess Tokenに含める
+	user, err := s.userRepo.GetByID(int(claims.UserID))
 	if err != nil {
-		logging.L.Error("failed to generate new access token",
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			logging.L.Warn("refresh token owner not found",
+				"service", "AuthService",
+				"method", "Refresh",
+				"user_id", claims.UserID)
+			return "", "", ErrInvalidRefreshToken
+		}
+		logging.L.Error("failed to get user",
 			"service", "AuthService",
 			"method", "Refresh",
 			"user_id", claims.UserID,
 			"error", err)
-		return "", fmt.Errorf("failed to generate access token: %w", err)
+		return "", "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := checkSuspension(user); err != nil {
+		logging.L.Warn("suspended user refresh refused",
+			"service", "AuthService",
+			"method", "Refresh",
+			"user_id", claims.UserID)
+		return "", "", err
 	}
 
-	// 最終使用時刻を更新
-	if err := s.refreshTokenRepo.UpdateLastUsed(tokenModel.ID); err != nil {
-		logging.L.Error("failed to update last_used_at",
+	// 新しいRefresh Tokenを生成し、使ったトークンと置き換える
+	newRefreshToken, err := auth.GenerateRefreshToken(claims.UserID)
+	if err != nil {
+		logging.L.Error("failed to generate refresh token",
+			"service", "AuthService",
+			"method", "Refresh",
+			"user_id", claims.UserID,
+			"error", err)
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	next := &models.RefreshToken{
+		UserID:    claims.UserID,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+		UserAgent: tokenModel.UserAgent,
+		IPAddress: tokenModel.IPAddress,
+	}
+	if device.UserAgent != "" {
+		next.UserAgent = truncateUserAgent(device.UserAgent)
+	}
+	if device.IPAddress != "" {
+		next.IPAddress = device.IPAddress
+	}
+	if err := s.refreshTokenRepo.Rotate(tokenModel, next, newRefreshToken); err != nil {
+		if errors.Is(err, postgres.ErrRefreshTokenRotated) {
+			// 検索からローテーションまでの間に同じトークンが使われた
+			return "", "", s.revokeReusedFamily(tokenModel)
+		}
+		logging.L.Error("failed to rotate refresh token",
 			"service", "AuthService",
 			"method", "Refresh",
 			"token_id", tokenModel.ID,
 			"error", err)
-		// エラーでもAccess Tokenは返す（ログのみ）
+		return "", "", fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	// 新しいAccess Tokenを生成
+	newAccessToken, err := auth.GenerateAccessToken(claims.UserID, user.Role, user.TokenVersion, tokenModel.FamilyID)
+	if err != nil {
+		logging.L.Error("failed to generate new access token",
+			"service", "AuthService",
+			"method", "Refresh",
+			"user_id", claims.UserID,
+			"error", err)
+		return "", "", fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	logging.L.Info("access token refreshed",
@@ -230,17 +562,115 @@ func (s *AuthService) Refresh(refreshToken string) (string, error) {
 		"method", "Refresh",
 		"user_id", claims.UserID)
 
-	return newAccessToken, nil
+	return newAccessToken, newRefreshToken, nil
 }
 
-// Logout はログアウト処理を行う（Refresh Tokenを削除）
-func (s *AuthService) Logout(userID int64) error {
+// revokeReusedFamily はローテーション済みのRefresh Tokenの再利用をセキュリティイベントとして記録し、ファミリーごと無効化する
+// そのセッションで発行済みのAccess Tokenも有効期限を待たずに無効にする
+func (s *AuthService) revokeReusedFamily(token *models.RefreshToken) error {
+	logging.L.Warn("security event: refresh token reuse detected",
+		"service", "AuthService",
+		"method", "Refresh",
+		"event", "refresh_token_reuse",
+		"user_id", token.UserID,
+		"token_id", token.ID,
+		"family_id", token.FamilyID,
+		"rotated_at", token.RotatedAt)
+	if err := s.refreshTokenRepo.DeleteByFamilyID(token.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	if err := s.revocations.Revoke(sessionRevocationKey(token.FamilyID), int(token.UserID), time.Now().Add(auth.AccessTokenTTL)); err != nil {
+		return fmt.Errorf("failed to revoke session access tokens: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// VerifyAccessToken は署名・有効期限を検証済みのアクセストークンが現在も有効かを確認する
+// 持ち主が利用停止中の場合は *auth.SuspensionError を返す
+// 持ち主が存在しない・トークンバージョンが古い・ログアウトなどで jti が無効化されている場合は auth.ErrRevokedToken を返す
+func (s *AuthService) VerifyAccessToken(claims *auth.Claims) error {
+	user, err := s.tokenUser(int(claims.UserID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return auth.ErrRevokedToken
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := checkSuspension(user); err != nil {
+		return err
+	}
+	if claims.TokenVersion < user.TokenVersion {
+		return auth.ErrRevokedToken
+	}
+	if claims.ExpiresAt == nil {
+		return nil
+	}
+	// jti・セッションIDを含まないトークン（導入前に発行されたもの）は個別に無効化できないため確認しない
+	for _, key := range []string{claims.ID, sessionRevocationKey(claims.SessionID)} {
+		if key == "" {
+			continue
+		}
+		revoked, err := s.revocations.IsRevoked(key, claims.ExpiresAt.Time)
+		if err != nil {
+			return fmt.Errorf("failed to check access token revocation: %w", err)
+		}
+		if revoked {
+			return auth.ErrRevokedToken
+		}
+	}
+	return nil
+}
+
+// tokenUser はアクセストークンの確認に使うユーザーの状態を返す
+// リクエストごとの DB への問い合わせを減らすため、TokenRevocationStore に短い期間キャッシュする
+func (s *AuthService) tokenUser(userID int) (*models.User, error) {
+	user, generation, ok := s.revocations.cachedUser(userID)
+	if ok {
+		return user, nil
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	s.revocations.cacheUser(user, generation)
+	return user, nil
+}
+
+// sessionRevocationKey はセッションのAccess Tokenをまとめて無効化するときに記録するキーを返す（jti と区別するため接頭辞を付ける）
+func sessionRevocationKey(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	return "session:" + sessionID
+}
+
+// checkSuspension は利用停止中のユーザーの場合に *auth.SuspensionError を返す
+func checkSuspension(user *models.User) error {
+	if !user.IsSuspended(time.Now()) {
+		return nil
+	}
+	return &auth.SuspensionError{Reason: user.SuspensionReason, Until: user.SuspendedUntil}
+}
+
+// Logout はログアウト処理を行う（このセッションのRefresh Tokenを削除し、ログアウトに使ったAccess Tokenを無効化）
+// 他の端末のセッションはログアウトしない
+// セッションIDを含まないAccess Token（導入前に発行されたもの）や accessToken が nil の場合は、すべてのRefresh Tokenを削除する
+func (s *AuthService) Logout(userID int64, accessToken *auth.Claims) error {
 	logging.L.Info("user logout",
 		"service", "AuthService",
 		"method", "Logout",
 		"user_id", userID)
 
-	if err := s.refreshTokenRepo.DeleteByUserID(userID); err != nil {
+	if accessToken != nil && accessToken.SessionID != "" {
+		if _, err := s.refreshTokenRepo.DeleteByUserIDAndFamilyID(userID, accessToken.SessionID); err != nil {
+			logging.L.Error("failed to delete session refresh tokens",
+				"service", "AuthService",
+				"method", "Logout",
+				"user_id", userID,
+				"error", err)
+			return fmt.Errorf("failed to logout: %w", err)
+		}
+	} else if err := s.refreshTokenRepo.DeleteByUserID(userID); err != nil {
 		logging.L.Error("failed to delete refresh tokens",
 			"service", "AuthService",
 			"method", "Logout",
@@ -249,6 +679,15 @@ func (s *AuthService) Logout(userID int64) error {
 		return fmt.Errorf("failed to logout: %w", err)
 	}
 
+	if err := s.revokeAccessToken(accessToken); err != nil {
+		logging.L.Error("failed to revoke access token",
+			"service", "AuthService",
+			"method", "Logout",
+			"user_id", userID,
+			"error", err)
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
 	logging.L.Info("user logged out successfully",
 		"service", "AuthService",
 		"method", "Logout",
@@ -257,6 +696,165 @@ func (s *AuthService) Logout(userID int64) error {
 	return nil
 }
 
+// ListSessions はユーザーのログイン中のセッションを最後に使われた順に返す
+// currentSessionID のセッションには Current を設定する
+func (s *AuthService) ListSessions(userID int64, currentSessionID string) ([]models.Session, error) {
+	tokens, err := s.refreshTokenRepo.ListActiveByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	sessions := make([]models.Session, 0, len(tokens))
+	for _, token := range tokens {
+		startedAt := token.SessionStartedAt
+		if startedAt.IsZero() {
+			startedAt = token.CreatedAt
+		}
+		sessions = append(sessions, models.Session{
+			ID:         token.FamilyID,
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			CreatedAt:  startedAt,
+			LastUsedAt: token.CreatedAt,
+			Current:    token.FamilyID == currentSessionID,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession はユーザーのセッションをログアウトさせる
+// Refresh Tokenを削除し、そのセッションで発行済みのAccess Tokenも有効期限を待たずに無効にする
+// セッションが存在しないか他のユーザーのものである場合は ErrSessionNotFound を返す
+func (s *AuthService) RevokeSession(userID int64, sessionID string) error {
+	deleted, err := s.refreshTokenRepo.DeleteByUserIDAndFamilyID(userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if deleted == 0 {
+		return ErrSessionNotFound
+	}
+	// セッションのAccess Tokenは最長でも今から AccessTokenTTL 後に期限切れになる
+	if err := s.revocations.Revoke(sessionRevocationKey(sessionID), int(userID), time.Now().Add(auth.AccessTokenTTL)); err != nil {
+		return fmt.Errorf("failed to revoke session access tokens: %w", err)
+	}
+	logging.L.Info("session revoked",
+		"service", "AuthService",
+		"method", "RevokeSession",
+		"user_id", userID,
+		"session_id", sessionID)
+	return nil
+}
+
+// RevokeOtherSessions は currentSessionID 以外のユーザーのセッションをすべてログアウトさせ、ログアウトさせた件数を返す
+func (s *AuthService) RevokeOtherSessions(userID int64, currentSessionID string) (int, error) {
+	tokens, err := s.refreshTokenRepo.ListActiveByUserID(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	revoked := 0
+	for _, token := range tokens {
+		if token.FamilyID == currentSessionID {
+			continue
+		}
+		if err := s.RevokeSession(userID, token.FamilyID); err != nil {
+			if errors.Is(err, ErrSessionNotFound) {
+				// 一覧の取得後に他のリクエストでログアウト済み
+				continue
+			}
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// ChangePassword はユーザーのパスワードを変更し、この端末の新しいセッションの Access Token と Refresh Token を返す
+// 現在のパスワードが誤っている場合は ErrIncorrectPassword を返す
+// 変更後はトークンバージョンを進めてすべての端末をログアウトさせ、セッションIDを含まない古いAccess Tokenも無効にする
+// この端末のログイン状態は、新しいトークンで引き継ぐ
+func (s *AuthService) ChangePassword(userID int64, device models.SessionDevice, input *models.ChangePasswordInput) (string, string, error) {
+	user, err := verifyUserPassword(s.userRepo, int(userID), input.CurrentPassword)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := user.HashPassword(input.NewPassword); err != nil {
+		return "", "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, user.PasswordHash); err != nil {
+		return "", "", fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.InvalidateAllTokens(userID); err != nil {
+		return "", "", err
+	}
+	// 進めた後のトークンバージョンで発行し直す
+	user, err = s.userRepo.GetByID(user.ID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get user: %w", err)
+	}
+	accessToken, refreshToken, err := s.startSession(user, device)
+	if err != nil {
+		return "", "", err
+	}
+	logging.L.Info("password changed",
+		"service", "AuthService",
+		"method", "ChangePassword",
+		"user_id", userID)
+	return accessToken, refreshToken, nil
+}
+
+// verifyUserPassword は確認のために入力されたユーザーのパスワードを検証する（誤っている場合は ErrIncorrectPassword）
+// GetByID はパスワードハッシュを含まないため、認証用の GetByEmail で取得し直したユーザーを返す
+func verifyUserPassword(userRepo repositories.AuthUserRepository, userID int, password string) (*models.User, error) {
+	user, err := userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	user, err = userRepo.GetByEmail(user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := user.CheckPassword(password); err != nil {
+		logging.L.Warn("incorrect password confirmation",
+			"service", "AuthService",
+			"user_id", userID)
+		return nil, ErrIncorrectPassword
+	}
+	return user, nil
+}
+
+// truncateUserAgent は記録する User-Agent を maxUserAgentLength バイト以内に切り詰める
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) <= maxUserAgentLength {
+		return userAgent
+	}
+	return strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+}
+
+// InvalidateAllTokens はユーザーのすべての Refresh Token を削除し、発行済みのAccess Tokenもすべて無効にする
+// パスワード変更など、他の端末のログイン状態をすぐに終わらせたい場合に使う
+func (s *AuthService) InvalidateAllTokens(userID int64) error {
+	if err := s.refreshTokenRepo.DeleteByUserID(userID); err != nil {
+		return fmt.Errorf("failed to delete refresh tokens: %w", err)
+	}
+	if err := s.revocations.RevokeAll(int(userID)); err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+	logging.L.Info("all tokens invalidated",
+		"service", "AuthService",
+		"method", "InvalidateAllTokens",
+		"user_id", userID)
+	return nil
+}
+
+// revokeAccessToken はAccess Tokenを有効期限まで無効にする（jti を含まないトークンは何もしない）
+func (s *AuthService) revokeAccessToken(claims *auth.Claims) error {
+	if claims == nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	return s.revocations.Revoke(claims.ID, int(claims.UserID), claims.ExpiresAt.Time)
+}
+
 // GetCurrentUser は現在のユーザー情報を取得
 func (s *AuthService) GetCurrentUser(userID int64) (*models.User, error) {
 	logging.L.Debug("getting current user",
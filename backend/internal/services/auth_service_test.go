@@ -2,20 +2,24 @@ package services
 
 import (
 	"errors"
-	"os"
 	"testing"
 	"time"
 
 	"go-shisha-backend/internal/models"
 	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/internal/repositories/postgres"
 	"go-shisha-backend/pkg/auth"
 
 	"gorm.io/gorm"
 )
 
 func init() {
-	// テスト用のJWT_SECRETを設定
-	_ = os.Setenv("JWT_SECRET", "test-secret-key-for-testing-purpose-at-least-64-characters-long")
+	// テスト用の署名鍵を設定
+	keys, err := auth.GenerateKeySet()
+	if err != nil {
+		panic(err)
+	}
+	auth.SetKeySet(keys)
 }
 
 // mockAuthUserRepo はUserRepositoryのモック
@@ -54,6 +58,29 @@ func (m *mockAuthUserRepo) GetByID(id int) (*models.User, error) {
 	return nil, errors.New("user not found")
 }
 
+func (m *mockAuthUserRepo) UpdatePassword(userID int, passwordHash string) error {
+	for _, user := range m.users {
+		if user.ID == userID {
+			user.PasswordHash = passwordHash
+			return nil
+		}
+	}
+	return repositories.ErrUserNotFound
+}
+
+func (m *mockAuthUserRepo) MarkEmailVerified(userID int, email string) error {
+	for _, user := range m.users {
+		if user.ID == userID && user.Email == email {
+			if user.EmailVerifiedAt == nil {
+				now := time.Now()
+				user.EmailVerifiedAt = &now
+			}
+			return nil
+		}
+	}
+	return repositories.ErrUserNotFound
+}
+
 func (m *mockAuthUserRepo) GetAll() ([]models.User, error) {
 	return nil, nil
 }
@@ -85,6 +112,17 @@ func (m *mockRefreshTokenRepo) FindByTokenHash(rawToken string) (*models.Refresh
 	return nil, gorm.ErrRecordNotFound
 }
 
+func (m *mockRefreshTokenRepo) Rotate(current *models.RefreshToken, next *models.RefreshToken, rawNext string) error {
+	if current.RotatedAt != nil {
+		return postgres.ErrRefreshTokenRotated
+	}
+	now := time.Now()
+	current.RotatedAt = &now
+	next.FamilyID = current.FamilyID
+	next.SessionStartedAt = current.SessionStartedAt
+	return m.Create(next, rawNext)
+}
+
 func (m *mockRefreshTokenRepo) UpdateLastUsed(id int64) error {
 	for _, token := range m.tokens {
 		if token.ID == id {
@@ -105,6 +143,36 @@ func (m *mockRefreshTokenRepo) DeleteByUserID(userID int64) error {
 	return nil
 }
 
+func (m *mockRefreshTokenRepo) DeleteByFamilyID(familyID string) error {
+	for key, token := range m.tokens {
+		if token.FamilyID == familyID {
+			delete(m.tokens, key)
+		}
+	}
+	return nil
+}
+
+func (m *mockRefreshTokenRepo) ListActiveByUserID(userID int64) ([]models.RefreshToken, error) {
+	tokens := []models.RefreshToken{}
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.RotatedAt == nil {
+			tokens = append(tokens, *token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *mockRefreshTokenRepo) DeleteByUserIDAndFamilyID(userID int64, familyID string) (int64, error) {
+	var deleted int64
+	for key, token := range m.tokens {
+		if token.UserID == userID && token.FamilyID == familyID {
+			delete(m.tokens, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func (m *mockRefreshTokenRepo) DeleteExpired() error {
 	return nil
 }
@@ -112,7 +180,7 @@ func (m *mockRefreshTokenRepo) DeleteExpired() error {
 func TestRegister(t *testing.T) {
 	userRepo := newMockAuthUserRepo()
 	tokenRepo := newMockRefreshTokenRepo()
-	svc := NewAuthService(userRepo, tokenRepo)
+	svc := newTestAuthService(userRepo, tokenRepo)
 
 	t.Run("正常系: ユーザー登録成功", func(t *testing.T) {
 		input := &models.CreateUserInput{
@@ -156,7 +224,7 @@ func TestRegister(t *testing.T) {
 func TestLogin(t *testing.T) {
 	userRepo := newMockAuthUserRepo()
 	tokenRepo := newMockRefreshTokenRepo()
-	svc := NewAuthService(userRepo, tokenRepo)
+	svc := newTestAuthService(userRepo, tokenRepo)
 
 	// テスト用ユーザーを事前登録
 	registerInput := &models.CreateUserInput{
@@ -175,7 +243,7 @@ func TestLogin(t *testing.T) {
 			Password: "password12345",
 		}
 
-		user, accessToken, refreshToken, err := svc.Login(loginInput)
+		user, accessToken, refreshToken, err := svc.Login(loginInput, models.SessionDevice{})
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -205,7 +273,7 @@ func TestLogin(t *testing.T) {
 			Password: "wrongpassword123",
 		}
 
-		_, _, _, err := svc.Login(loginInput)
+		_, _, _, err := svc.Login(loginInput, models.SessionDevice{})
 		if err == nil {
 			t.Fatal("expected error for wrong password, got nil")
 		}
@@ -220,7 +288,7 @@ func TestLogin(t *testing.T) {
 			Password: "password12345",
 		}
 
-		_, _, _, err := svc.Login(loginInput)
+		_, _, _, err := svc.Login(loginInput, models.SessionDevice{})
 		if err == nil {
 			t.Fatal("expected error for non-existent user, got nil")
 		}
@@ -233,7 +301,7 @@ func TestLogin(t *testing.T) {
 func TestRefresh(t *testing.T) {
 	userRepo := newMockAuthUserRepo()
 	tokenRepo := newMockRefreshTokenRepo()
-	svc := NewAuthService(userRepo, tokenRepo)
+	svc := newTestAuthService(userRepo, tokenRepo)
 
 	// テスト用ユーザーを事前登録してログイン
 	registerInput := &models.CreateUserInput{
@@ -250,19 +318,23 @@ func TestRefresh(t *testing.T) {
 		Email:    "refresh@example.com",
 		Password: "password12345",
 	}
-	_, _, refreshToken, err := svc.Login(loginInput)
+	_, _, refreshToken, err := svc.Login(loginInput, models.SessionDevice{})
 	if err != nil {
 		t.Fatalf("failed to login test user: %v", err)
 	}
 
 	t.Run("正常系: トークンリフレッシュ成功", func(t *testing.T) {
-		newAccessToken, err := svc.Refresh(refreshToken)
+		newAccessToken, newRefreshToken, err := svc.Refresh(refreshToken, models.SessionDevice{})
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 		if newAccessToken == "" {
 			t.Fatal("expected new access token to be set")
 		}
+		if newRefreshToken == "" || newRefreshToken == refreshToken {
+			t.Fatal("expected refresh token to be rotated")
+		}
+		refreshToken = newRefreshToken
 
 		// 新しいトークンが検証可能か確認
 		_, err = auth.ValidateToken(newAccessToken)
@@ -271,18 +343,41 @@ func TestRefresh(t *testing.T) {
 		}
 	})
 
+	t.Run("正常系: 再発行したトークンに現在のロールが含まれる", func(t *testing.T) {
+		userRepo.users["refresh@example.com"].Role = models.RoleModerator
+
+		newAccessToken, newRefreshToken, err := svc.Refresh(refreshToken, models.SessionDevice{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		refreshToken = newRefreshToken
+		claims, err := auth.ValidateToken(newAccessToken)
+		if err != nil {
+			t.Fatalf("expected valid new access token, got error: %v", err)
+		}
+		if claims.Role != models.RoleModerator {
+			t.Errorf("expected role %q, got %q", models.RoleModerator, claims.Role)
+		}
+	})
+
 	t.Run("異常系: 無効なリフレッシュトークン", func(t *testing.T) {
-		_, err := svc.Refresh("invalid-token")
+		_, _, err := svc.Refresh("invalid-token", models.SessionDevice{})
 		if err == nil {
 			t.Fatal("expected error for invalid token, got nil")
 		}
 	})
+
+	t.Run("異常系: リフレッシュトークンは認証に使えない", func(t *testing.T) {
+		if _, err := auth.ValidateToken(refreshToken); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got %v", err)
+		}
+	})
 }
 
 func TestLogout(t *testing.T) {
 	userRepo := newMockAuthUserRepo()
 	tokenRepo := newMockRefreshTokenRepo()
-	svc := NewAuthService(userRepo, tokenRepo)
+	svc := newTestAuthService(userRepo, tokenRepo)
 
 	// テスト用ユーザーを事前登録してログイン
 	registerInput := &models.CreateUserInput{
@@ -299,13 +394,13 @@ func TestLogout(t *testing.T) {
 		Email:    "logout@example.com",
 		Password: "password12345",
 	}
-	_, _, _, err = svc.Login(loginInput)
+	_, _, _, err = svc.Login(loginInput, models.SessionDevice{})
 	if err != nil {
 		t.Fatalf("failed to login test user: %v", err)
 	}
 
 	t.Run("正常系: ログアウト成功", func(t *testing.T) {
-		err := svc.Logout(int64(user.ID))
+		err := svc.Logout(int64(user.ID), nil)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -320,7 +415,7 @@ func TestLogout(t *testing.T) {
 func TestGetCurrentUser(t *testing.T) {
 	userRepo := newMockAuthUserRepo()
 	tokenRepo := newMockRefreshTokenRepo()
-	svc := NewAuthService(userRepo, tokenRepo)
+	svc := newTestAuthService(userRepo, tokenRepo)
 
 	// テスト用ユーザーを事前登録
 	registerInput := &models.CreateUserInput{
@@ -353,3 +448,528 @@ func TestGetCurrentUser(t *testing.T) {
 		}
 	})
 }
+
+func TestSuspendedUser(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	tokenRepo := newMockRefreshTokenRepo()
+	store := NewTokenRevocationStore(newMemoryTokenRevocationRepo())
+	svc := NewAuthService(userRepo, tokenRepo, store, nopVerificationSender{}, disabledMFAVerifier{}, nopLoginLimiter{})
+
+	if _, err := svc.Register(&models.CreateUserInput{
+		Email:       "suspended@example.com",
+		Password:    "password12345",
+		DisplayName: "Suspended User",
+	}); err != nil {
+		t.Fatalf("failed to register test user: %v", err)
+	}
+	loginInput := &models.LoginInput{Email: "suspended@example.com", Password: "password12345"}
+	_, accessToken, refreshToken, err := svc.Login(loginInput, models.SessionDevice{})
+	if err != nil {
+		t.Fatalf("failed to login test user: %v", err)
+	}
+	claims, err := auth.ValidateToken(accessToken)
+	if err != nil {
+		t.Fatalf("expected valid access token, got error: %v", err)
+	}
+	if err := svc.VerifyAccessToken(claims); err != nil {
+		t.Fatalf("expected token to be valid before suspension, got %v", err)
+	}
+
+	// モデレーターによる利用停止（トークンバージョンも上がる）
+	user := userRepo.users["suspended@example.com"]
+	suspendedAt := time.Now()
+	until := suspendedAt.Add(24 * time.Hour)
+	user.SuspendedAt = &suspendedAt
+	user.SuspendedUntil = &until
+	user.SuspensionReason = "スパム行為のため"
+	user.TokenVersion++
+	// ModerationService と同じく、キャッシュしたトークンの確認用の状態を捨てる
+	store.ForgetUser(user.ID)
+
+	t.Run("異常系: 利用停止中はログインできない", func(t *testing.T) {
+		_, _, _, err := svc.Login(loginInput, models.SessionDevice{})
+		var suspension *auth.SuspensionError
+		if !errors.As(err, &suspension) {
+			t.Fatalf("expected SuspensionError, got %v", err)
+		}
+		if suspension.Reason != "スパム行為のため" || suspension.Until == nil || !suspension.Until.Equal(until) {
+			t.Fatalf("unexpected suspension: %+v", suspension)
+		}
+	})
+
+	t.Run("異常系: 利用停止中はパスワードが誤っていれば理由を返さない", func(t *testing.T) {
+		_, _, _, err := svc.Login(&models.LoginInput{Email: "suspended@example.com", Password: "wrongpassword123"}, models.SessionDevice{})
+		if !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 利用停止中はトークンを更新できない", func(t *testing.T) {
+		if _, _, err := svc.Refresh(refreshToken, models.SessionDevice{}); !errors.Is(err, auth.ErrAccountSuspended) {
+			t.Fatalf("expected ErrAccountSuspended, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 発行済みのアクセストークンを拒否する", func(t *testing.T) {
+		if err := svc.VerifyAccessToken(claims); !errors.Is(err, auth.ErrAccountSuspended) {
+			t.Fatalf("expected ErrAccountSuspended, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 終了日時を過ぎればログインでき、利用停止前のトークンは無効のまま", func(t *testing.T) {
+		past := time.Now().Add(-time.Minute)
+		user.SuspendedUntil = &past
+		store.ForgetUser(user.ID)
+
+		_, newAccessToken, _, err := svc.Login(loginInput, models.SessionDevice{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := svc.VerifyAccessToken(claims); !errors.Is(err, auth.ErrRevokedToken) {
+			t.Fatalf("expected ErrRevokedToken for old token, got %v", err)
+		}
+		newClaims, err := auth.ValidateToken(newAccessToken)
+		if err != nil {
+			t.Fatalf("expected valid access token, got error: %v", err)
+		}
+		if err := svc.VerifyAccessToken(newClaims); err != nil {
+			t.Fatalf("expected new token to be valid, got %v", err)
+		}
+	})
+}
+
+func TestAccessTokenRevocation(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	tokenRepo := newMockRefreshTokenRepo()
+	revocationRepo := newMemoryTokenRevocationRepo()
+	svc := NewAuthService(userRepo, tokenRepo, NewTokenRevocationStore(revocationRepo), nopVerificationSender{}, disabledMFAVerifier{}, nopLoginLimiter{})
+
+	user, err := svc.Register(&models.CreateUserInput{
+		Email:       "revoke@example.com",
+		Password:    "password12345",
+		DisplayName: "Revoke User",
+	})
+	if err != nil {
+		t.Fatalf("failed to register test user: %v", err)
+	}
+	login := func() *auth.Claims {
+		t.Helper()
+		_, accessToken, _, err := svc.Login(&models.LoginInput{Email: "revoke@example.com", Password: "password12345"}, models.SessionDevice{})
+		if err != nil {
+			t.Fatalf("failed to login test user: %v", err)
+		}
+		claims, err := auth.ValidateToken(accessToken)
+		if err != nil {
+			t.Fatalf("expected valid access token, got error: %v", err)
+		}
+		if claims.ID == "" {
+			t.Fatal("expected access token to have jti")
+		}
+		return claims
+	}
+
+	t.Run("正常系: ログアウトに使ったトークンだけが無効になる", func(t *testing.T) {
+		current := login()
+		other := login()
+		if current.ID == other.ID {
+			t.Fatal("expected unique jti per access token")
+		}
+
+		if err := svc.Logout(int64(user.ID), current); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := svc.VerifyAccessToken(current); !errors.Is(err, auth.ErrRevokedToken) {
+			t.Fatalf("expected ErrRevokedToken, got %v", err)
+		}
+		if err := svc.VerifyAccessToken(other); err != nil {
+			t.Fatalf("expected other token to remain valid, got %v", err)
+		}
+	})
+
+	t.Run("正常系: すべてのトークンを無効にする", func(t *testing.T) {
+		before := login()
+		// IncrementTokenVersion の結果をユーザーに反映する
+		revocationRepo.versions[user.ID] = user.TokenVersion
+		if err := svc.InvalidateAllTokens(int64(user.ID)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		user.TokenVersion = revocationRepo.versions[user.ID]
+
+		if err := svc.VerifyAccessToken(before); !errors.Is(err, auth.ErrRevokedToken) {
+			t.Fatalf("expected ErrRevokedToken, got %v", err)
+		}
+		if len(tokenRepo.tokens) != 0 {
+			t.Fatal("expected all refresh tokens to be deleted")
+		}
+		if err := svc.VerifyAccessToken(login()); err != nil {
+			t.Fatalf("expected new token to be valid, got %v", err)
+		}
+	})
+}
+
+// countingAuthUserRepo は GetByID の呼び出し回数を記録する mockAuthUserRepo
+type countingAuthUserRepo struct {
+	*mockAuthUserRepo
+	getByIDCalls int
+}
+
+func (m *countingAuthUserRepo) GetByID(id int) (*models.User, error) {
+	m.getByIDCalls++
+	return m.mockAuthUserRepo.GetByID(id)
+}
+
+func TestVerifyAccessTokenCachesUserState(t *testing.T) {
+	userRepo := &countingAuthUserRepo{mockAuthUserRepo: newMockAuthUserRepo()}
+	store := NewTokenRevocationStore(newMemoryTokenRevocationRepo())
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	svc := NewAuthService(userRepo, newMockRefreshTokenRepo(), store, nopVerificationSender{}, disabledMFAVerifier{}, nopLoginLimiter{})
+
+	user, err := svc.Register(&models.CreateUserInput{
+		Email:       "cache@example.com",
+		Password:    "password12345",
+		DisplayName: "Cache User",
+	})
+	if err != nil {
+		t.Fatalf("failed to register test user: %v", err)
+	}
+	_, accessToken, _, err := svc.Login(&models.LoginInput{Email: "cache@example.com", Password: "password12345"}, models.SessionDevice{})
+	if err != nil {
+		t.Fatalf("failed to login test user: %v", err)
+	}
+	claims, err := auth.ValidateToken(accessToken)
+	if err != nil {
+		t.Fatalf("expected valid access token, got error: %v", err)
+	}
+
+	t.Run("正常系: ユーザーの状態はキャッシュの有効期間内は問い合わせない", func(t *testing.T) {
+		userRepo.getByIDCalls = 0
+		for i := 0; i < 3; i++ {
+			if err := svc.VerifyAccessToken(claims); err != nil {
+				t.Fatalf("expected token to be valid, got %v", err)
+			}
+		}
+		if userRepo.getByIDCalls != 1 {
+			t.Fatalf("expected 1 user lookup thanks to cache, got %d", userRepo.getByIDCalls)
+		}
+	})
+
+	t.Run("正常系: 他のインスタンスでの利用停止はキャッシュの有効期間を過ぎると反映される", func(t *testing.T) {
+		suspendedAt := now
+		user.SuspendedAt = &suspendedAt
+		if err := svc.VerifyAccessToken(claims); err != nil {
+			t.Fatalf("expected cached state before TTL, got %v", err)
+		}
+		now = now.Add(store.ttl)
+		if err := svc.VerifyAccessToken(claims); !errors.Is(err, auth.ErrAccountSuspended) {
+			t.Fatalf("expected ErrAccountSuspended after TTL, got %v", err)
+		}
+		user.SuspendedAt = nil
+		now = now.Add(store.ttl)
+	})
+
+	t.Run("正常系: このインスタンスでの利用停止はすぐに反映される", func(t *testing.T) {
+		if err := svc.VerifyAccessToken(claims); err != nil {
+			t.Fatalf("expected token to be valid, got %v", err)
+		}
+		suspendedAt := now
+		user.SuspendedAt = &suspendedAt
+		store.ForgetUser(user.ID)
+		if err := svc.VerifyAccessToken(claims); !errors.Is(err, auth.ErrAccountSuspended) {
+			t.Fatalf("expected ErrAccountSuspended immediately, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 読み込み中に破棄されたユーザーの状態はキャッシュしない", func(t *testing.T) {
+		_, generation, _ := store.cachedUser(user.ID)
+		store.ForgetUser(user.ID)
+		store.cacheUser(user, generation)
+		if _, _, ok := store.cachedUser(user.ID); ok {
+			t.Fatal("expected stale user state not to be cached")
+		}
+	})
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	tokenRepo := newMockRefreshTokenRepo()
+	svc := newTestAuthService(userRepo, tokenRepo)
+
+	if _, err := svc.Register(&models.CreateUserInput{
+		Email:       "reuse@example.com",
+		Password:    "password12345",
+		DisplayName: "Reuse User",
+	}); err != nil {
+		t.Fatalf("failed to register test user: %v", err)
+	}
+	loginInput := &models.LoginInput{Email: "reuse@example.com", Password: "password12345"}
+	_, _, first, err := svc.Login(loginInput, models.SessionDevice{})
+	if err != nil {
+		t.Fatalf("failed to login test user: %v", err)
+	}
+	// 別の端末のログインは別のファミリーになる
+	_, _, otherDevice, err := svc.Login(loginInput, models.SessionDevice{})
+	if err != nil {
+		t.Fatalf("failed to login test user: %v", err)
+	}
+	if tokenRepo.tokens[first].FamilyID == "" || tokenRepo.tokens[first].FamilyID == tokenRepo.tokens[otherDevice].FamilyID {
+		t.Fatal("expected each login to start a new token family")
+	}
+
+	secondAccess, second, err := svc.Refresh(first, models.SessionDevice{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tokenRepo.tokens[second].FamilyID != tokenRepo.tokens[first].FamilyID {
+		t.Fatal("expected rotated token to stay in the same family")
+	}
+
+	// ローテーション済みのトークンの再利用でファミリーごと無効になる
+	if _, _, err := svc.Refresh(first, models.SessionDevice{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, _, err := svc.Refresh(second, models.SessionDevice{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken for revoked family, got %v", err)
+	}
+	// ファミリーのセッションで発行済みのAccess Tokenもすぐに無効になる
+	claims, err := auth.ValidateToken(secondAccess)
+	if err != nil {
+		t.Fatalf("expected valid access token, got error: %v", err)
+	}
+	if err := svc.VerifyAccessToken(claims); !errors.Is(err, auth.ErrRevokedToken) {
+		t.Fatalf("expected ErrRevokedToken for revoked family, got %v", err)
+	}
+	if _, _, err := svc.Refresh(otherDevice, models.SessionDevice{}); err != nil {
+		t.Fatalf("expected other device to be unaffected, got %v", err)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	tokenRepo := newMockRefreshTokenRepo()
+	svc := newTestAuthService(userRepo, tokenRepo)
+
+	user, err := svc.Register(&models.CreateUserInput{
+		Email:       "sessions@example.com",
+		Password:    "password12345",
+		DisplayName: "Sessions User",
+	})
+	if err != nil {
+		t.Fatalf("failed to register test user: %v", err)
+	}
+	loginInput := &models.LoginInput{Email: "sessions@example.com", Password: "password12345"}
+	login := func(device models.SessionDevice) (*auth.Claims, string) {
+		t.Helper()
+		_, accessToken, refreshToken, err := svc.Login(loginInput, device)
+		if err != nil {
+			t.Fatalf("failed to login test user: %v", err)
+		}
+		claims, err := auth.ValidateToken(accessToken)
+		if err != nil {
+			t.Fatalf("expected valid access token, got error: %v", err)
+		}
+		if claims.SessionID == "" {
+			t.Fatal("expected access token to have session id")
+		}
+		return claims, refreshToken
+	}
+	userID := int64(user.ID)
+
+	phone, phoneRefresh := login(models.SessionDevice{UserAgent: "iPhone", IPAddress: "203.0.113.1"})
+	laptop, _ := login(models.SessionDevice{UserAgent: "Firefox", IPAddress: "203.0.113.2"})
+	tablet, _ := login(models.SessionDevice{UserAgent: "iPad", IPAddress: "203.0.113.3"})
+
+	t.Run("正常系: 端末の一覧と現在の端末", func(t *testing.T) {
+		sessions, err := svc.ListSessions(userID, laptop.SessionID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(sessions) != 3 {
+			t.Fatalf("expected 3 sessions, got %d", len(sessions))
+		}
+		for _, session := range sessions {
+			if session.Current != (session.ID == laptop.SessionID) {
+				t.Errorf("unexpected current flag: %+v", session)
+			}
+			if session.ID == laptop.SessionID && (session.UserAgent != "Firefox" || session.IPAddress != "203.0.113.2") {
+				t.Errorf("unexpected device info: %+v", session)
+			}
+		}
+	})
+
+	t.Run("正常系: トークン更新で端末の情報を更新し、セッションIDを引き継ぐ", func(t *testing.T) {
+		accessToken, _, err := svc.Refresh(phoneRefresh, models.SessionDevice{UserAgent: "iPhone", IPAddress: "198.51.100.9"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		claims, err := auth.ValidateToken(accessToken)
+		if err != nil || claims.SessionID != phone.SessionID {
+			t.Fatalf("expected session id %q, got %+v (err=%v)", phone.SessionID, claims, err)
+		}
+		sessions, _ := svc.ListSessions(userID, "")
+		for _, session := range sessions {
+			if session.ID == phone.SessionID && session.IPAddress != "198.51.100.9" {
+				t.Errorf("expected IP address to be updated, got %+v", session)
+			}
+		}
+	})
+
+	t.Run("正常系: ログアウトはこの端末のセッションだけを終わらせる", func(t *testing.T) {
+		if err := svc.Logout(userID, tablet); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		sessions, _ := svc.ListSessions(userID, "")
+		if len(sessions) != 2 {
+			t.Fatalf("expected 2 sessions after logout, got %d", len(sessions))
+		}
+		if err := svc.VerifyAccessToken(laptop); err != nil {
+			t.Fatalf("expected other session to remain valid, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 他のユーザーのセッションはログアウトさせられない", func(t *testing.T) {
+		if err := svc.RevokeSession(userID+1, phone.SessionID); !errors.Is(err, ErrSessionNotFound) {
+			t.Fatalf("expected ErrSessionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 他の端末からすべてログアウト", func(t *testing.T) {
+		revoked, err := svc.RevokeOtherSessions(userID, laptop.SessionID)
+		if err != nil || revoked != 1 {
+			t.Fatalf("expected 1 session to be revoked, got %d (err=%v)", revoked, err)
+		}
+		sessions, _ := svc.ListSessions(userID, laptop.SessionID)
+		if len(sessions) != 1 || !sessions[0].Current {
+			t.Fatalf("expected only current session to remain, got %+v", sessions)
+		}
+		// ログアウトさせた端末のAccess Tokenも有効期限を待たずに無効になる
+		if err := svc.VerifyAccessToken(phone); !errors.Is(err, auth.ErrRevokedToken) {
+			t.Fatalf("expected ErrRevokedToken, got %v", err)
+		}
+		if err := svc.VerifyAccessToken(laptop); err != nil {
+			t.Fatalf("expected current session to remain valid, got %v", err)
+		}
+	})
+}
+
+// userTokenVersionRevocationRepo は IncrementTokenVersion の結果を mockAuthUserRepo のユーザーに反映する memoryTokenRevocationRepo
+type userTokenVersionRevocationRepo struct {
+	*memoryTokenRevocationRepo
+	users *mockAuthUserRepo
+}
+
+func (r *userTokenVersionRevocationRepo) IncrementTokenVersion(userID int) error {
+	user, err := r.users.GetByID(userID)
+	if err != nil {
+		return repositories.ErrUserNotFound
+	}
+	user.TokenVersion++
+	return nil
+}
+
+func TestChangePassword(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	tokenRepo := newMockRefreshTokenRepo()
+	revocations := NewTokenRevocationStore(&userTokenVersionRevocationRepo{memoryTokenRevocationRepo: newMemoryTokenRevocationRepo(), users: userRepo})
+	svc := NewAuthService(userRepo, tokenRepo, revocations, nopVerificationSender{}, disabledMFAVerifier{}, nopLoginLimiter{})
+
+	user, err := svc.Register(&models.CreateUserInput{
+		Email:       "change@example.com",
+		Password:    "password12345",
+		DisplayName: "Change User",
+	})
+	if err != nil {
+		t.Fatalf("failed to register test user: %v", err)
+	}
+	login := func(password string) (*auth.Claims, error) {
+		_, accessToken, _, err := svc.Login(&models.LoginInput{Email: "change@example.com", Password: password}, models.SessionDevice{})
+		if err != nil {
+			return nil, err
+		}
+		return auth.ValidateToken(accessToken)
+	}
+	current, err := login("password12345")
+	if err != nil {
+		t.Fatalf("failed to login: %v", err)
+	}
+	other, err := login("password12345")
+	if err != nil {
+		t.Fatalf("failed to login: %v", err)
+	}
+	userID := int64(user.ID)
+	// セッションIDを含まないAccess Token（セッションの導入前に発行されたもの）
+	legacyToken, err := auth.GenerateAccessToken(userID, user.Role, user.TokenVersion, "")
+	if err != nil {
+		t.Fatalf("failed to generate legacy token: %v", err)
+	}
+	legacy, err := auth.ValidateToken(legacyToken)
+	if err != nil {
+		t.Fatalf("failed to validate legacy token: %v", err)
+	}
+
+	t.Run("異常系: 現在のパスワードが誤っている", func(t *testing.T) {
+		_, _, err := svc.ChangePassword(userID, models.SessionDevice{}, &models.ChangePasswordInput{CurrentPassword: "wrong-password", NewPassword: "new-password-123"})
+		if !errors.Is(err, ErrIncorrectPassword) {
+			t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+		}
+		if _, err := login("password12345"); err != nil {
+			t.Fatalf("expected password to be unchanged, got %v", err)
+		}
+	})
+
+	t.Run("正常系: パスワードを変更し、すべてのトークンを無効にしてこの端末のトークンを発行し直す", func(t *testing.T) {
+		accessToken, refreshToken, err := svc.ChangePassword(userID, models.SessionDevice{UserAgent: "TestAgent"}, &models.ChangePasswordInput{CurrentPassword: "password12345", NewPassword: "new-password-123"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := login("password12345"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected old password to be rejected, got %v", err)
+		}
+		for name, claims := range map[string]*auth.Claims{"current": current, "other": other, "legacy": legacy} {
+			if err := svc.VerifyAccessToken(claims); !errors.Is(err, auth.ErrRevokedToken) {
+				t.Fatalf("expected %s token to be revoked, got %v", name, err)
+			}
+		}
+		reissued, err := auth.ValidateToken(accessToken)
+		if err != nil {
+			t.Fatalf("failed to validate reissued token: %v", err)
+		}
+		if err := svc.VerifyAccessToken(reissued); err != nil {
+			t.Fatalf("expected reissued token to be valid, got %v", err)
+		}
+		if refreshToken == "" {
+			t.Fatal("expected reissued refresh token")
+		}
+		sessions, _ := svc.ListSessions(userID, reissued.SessionID)
+		if len(sessions) != 1 || !sessions[0].Current || sessions[0].UserAgent != "TestAgent" {
+			t.Fatalf("expected only the reissued session to remain, got %+v", sessions)
+		}
+		if _, err := login("new-password-123"); err != nil {
+			t.Fatalf("expected new password to be accepted, got %v", err)
+		}
+	})
+}
+
+// nopVerificationSender・disabledMFAVerifier・nopLoginLimiter は、テストで確認しない AuthService の依存に渡す実装
+type nopVerificationSender struct{}
+
+func (nopVerificationSender) SendVerification(user *models.User) error { return nil }
+
+type disabledMFAVerifier struct{}
+
+func (disabledMFAVerifier) IsEnabled(userID int) (bool, error) { return false, nil }
+
+func (disabledMFAVerifier) VerifyLoginCode(userID int, code string) error { return ErrMFANotEnabled }
+
+type nopLoginLimiter struct{}
+
+func (nopLoginLimiter) Reserve(userID int) error { return nil }
+
+func (nopLoginLimiter) RecordFailure(user *models.User) error { return nil }
+
+func (nopLoginLimiter) RecordSuccess(userID int) error { return nil }
+
+// newTestAuthService はインメモリの TokenRevocationStore と、確認メール・2段階認証・ログインの制限に何もしない実装を渡して AuthService を作成する
+func newTestAuthService(userRepo repositories.AuthUserRepository, tokenRepo postgres.RefreshTokenRepository) *AuthService {
+	return NewAuthService(userRepo, tokenRepo, NewTokenRevocationStore(newMemoryTokenRevocationRepo()), nopVerificationSender{}, disabledMFAVerifier{}, nopLoginLimiter{})
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+// badgeRule はバッジの獲得条件
+type badgeRule struct {
+	code        string
+	name        string
+	description string
+	earned      func(stats *models.UserStats) bool
+}
+
+// badgeRules はバッジの一覧と獲得条件（判定はユーザー統計をもとに行う）
+var badgeRules = []badgeRule{
+	{
+		code:        models.BadgeFirstPost,
+		name:        "はじめての一服",
+		description: "はじめて投稿した",
+		earned:      func(s *models.UserStats) bool { return s.TotalPosts >= 1 },
+	},
+	{
+		code:        models.BadgeFlavorExplorer,
+		name:        "フレーバー探検家",
+		description: "10種類のフレーバーを試した",
+		earned:      func(s *models.UserStats) bool { return s.DistinctFlavors >= 10 },
+	},
+	{
+		code:        models.BadgePopular,
+		name:        "人気者",
+		description: "いいねを合計100件受け取った",
+		earned:      func(s *models.UserStats) bool { return s.TotalLikesReceived >= 100 },
+	},
+	{
+		code:        models.BadgeWeeklyStreak,
+		name:        "1週間連続",
+		description: "7日連続で投稿した",
+		earned:      func(s *models.UserStats) bool { return s.LongestStreakDays >= 7 },
+	},
+}
+
+// BadgeService はバッジの獲得判定と取得を扱う
+type BadgeService struct {
+	badgeRepo repositories.BadgeRepository
+	statsRepo repositories.UserStatsRepository
+	userRepo  repositories.UserRepository
+}
+
+// NewBadgeService は新しい BadgeService を作成する
+func NewBadgeService(badgeRepo repositories.BadgeRepository, statsRepo repositories.UserStatsRepository, userRepo repositories.UserRepository) *BadgeService {
+	return &BadgeService{
+		badgeRepo: badgeRepo,
+		statsRepo: statsRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// EvaluateBadges は指定ユーザーの最新の統計をもとに獲得条件を判定し、新たに獲得したバッジを返す
+// 統計はキャッシュを使わずに集計する（イベント直後の状態で判定するため）
+// 集計が重いため、リクエストの処理中ではなく RegisterEventHandlers で登録したドメインイベントの購読者から呼び出す
+func (s *BadgeService) EvaluateBadges(userID int) ([]models.UserBadge, error) {
+	stats, err := s.statsRepo.GetUserStats(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	for _, rule := range badgeRules {
+		if rule.earned(stats) {
+			codes = append(codes, rule.code)
+		}
+	}
+	awarded, err := s.badgeRepo.Award(userID, codes)
+	if err != nil {
+		return nil, err
+	}
+	return withBadgeDetails(awarded), nil
+}
+
+// badgeEvaluateHandlerName はドメインイベントの購読者名
+const badgeEvaluateHandlerName = "badge.evaluate"
+
+// RegisterEventHandlers は投稿・いいねのドメインイベントでバッジの獲得判定を行う購読者を登録する
+// 現状のイベントは投稿作成（リミックス含む）といいね受け取りのみ。フォロー機能は未実装のため対象外
+// 獲得済みのバッジは付与し直さないため、同じイベントを複数回受け取っても結果は変わらない
+func (s *BadgeService) RegisterEventHandlers(subscriber DomainEventSubscriber) {
+	subscriber.Subscribe(models.DomainEventPostCreated, badgeEvaluateHandlerName, s.handlePostCreated)
+	subscriber.Subscribe(models.DomainEventLikeAdded, badgeEvaluateHandlerName, s.handleLikeAdded)
+}
+
+func (s *BadgeService) handlePostCreated(event *models.DomainEvent) error {
+	var e models.PostCreatedEvent
+	if err := event.Decode(&e); err != nil {
+		return err
+	}
+	_, err := s.EvaluateBadges(e.UserID)
+	return err
+}
+
+func (s *BadgeService) handleLikeAdded(event *models.DomainEvent) error {
+	var e models.LikeAddedEvent
+	if err := event.Decode(&e); err != nil {
+		return err
+	}
+	// 受け取ったいいね数は投稿者のバッジに反映する
+	_, err := s.EvaluateBadges(e.PostOwnerID)
+	return err
+}
+
+// GetUserBadges は指定ユーザーが獲得したバッジを返す
+// ユーザーが存在しない場合は repositories.ErrUserNotFound を返す
+func (s *BadgeService) GetUserBadges(userID int) ([]models.UserBadge, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+	badges, err := s.badgeRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return withBadgeDetails(badges), nil
+}
+
+// GetUnseenBadges は指定ユーザーの未確認バッジを返す（確認済みにはしない）
+func (s *BadgeService) GetUnseenBadges(userID int) ([]models.UserBadge, error) {
+	badges, err := s.badgeRepo.ListUnseen(userID)
+	if err != nil {
+		return nil, err
+	}
+	return withBadgeDetails(badges), nil
+}
+
+// MarkBadgesSeen は指定ユーザーのバッジを確認済みにする
+// 取得後に新たに獲得したバッジを取りこぼさないよう、クライアントが表示したバッジのみを指定させる
+func (s *BadgeService) MarkBadgesSeen(userID int, codes []string) error {
+	return s.badgeRepo.MarkSeen(userID, codes)
+}
+
+// withBadgeDetails はバッジにルール定義の名称と説明を設定する
+// ルールから削除されたバッジは識別子をそのまま名称として扱う
+func withBadgeDetails(badges []models.UserBadge) []models.UserBadge {
+	for i := range badges {
+		badges[i].Name = badges[i].Code
+		for _, rule := range badgeRules {
+			if rule.code == badges[i].Code {
+				badges[i].Name = rule.name
+				badges[i].Description = rule.description
+				break
+			}
+		}
+		if badges[i].Description == "" {
+			logging.L.Warn("unknown badge code", "service", "BadgeService", "badge", badges[i].Code)
+		}
+	}
+	return badges
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// fixedStatsRepo は固定の統計を返すモック
+type fixedStatsRepo struct {
+	stats *models.UserStats
+	err   error
+}
+
+func (m *fixedStatsRepo) GetUserStats(userID int) (*models.UserStats, error) {
+	return m.stats, m.err
+}
+
+// memoryBadgeRepo はメモリ上でバッジを管理するモック
+type memoryBadgeRepo struct {
+	badges map[string]bool
+	unseen []models.UserBadge
+}
+
+func (m *memoryBadgeRepo) Award(userID int, codes []string) ([]models.UserBadge, error) {
+	if m.badges == nil {
+		m.badges = map[string]bool{}
+	}
+	var awarded []models.UserBadge
+	for _, code := range codes {
+		if m.badges[code] {
+			continue
+		}
+		m.badges[code] = true
+		b := models.UserBadge{Code: code, AwardedAt: time.Now()}
+		awarded = append(awarded, b)
+		m.unseen = append(m.unseen, b)
+	}
+	return awarded, nil
+}
+
+func (m *memoryBadgeRepo) ListByUserID(userID int) ([]models.UserBadge, error) {
+	var badges []models.UserBadge
+	for code := range m.badges {
+		badges = append(badges, models.UserBadge{Code: code})
+	}
+	return badges, nil
+}
+
+func (m *memoryBadgeRepo) ListUnseen(userID int) ([]models.UserBadge, error) {
+	return m.unseen, nil
+}
+
+func (m *memoryBadgeRepo) MarkSeen(userID int, codes []string) error {
+	var unseen []models.UserBadge
+	for _, b := range m.unseen {
+		if !slices.Contains(codes, b.Code) {
+			unseen = append(unseen, b)
+		}
+	}
+	m.unseen = unseen
+	return nil
+}
+
+func TestEvaluateBadges_Rules(t *testing.T) {
+	cases := []struct {
+		name  string
+		stats models.UserStats
+		want  []string
+	}{
+		{"no posts", models.UserStats{}, nil},
+		{"first post", models.UserStats{TotalPosts: 1}, []string{models.BadgeFirstPost}},
+		{"9 flavors", models.UserStats{TotalPosts: 9, DistinctFlavors: 9}, []string{models.BadgeFirstPost}},
+		{"10 flavors", models.UserStats{TotalPosts: 10, DistinctFlavors: 10}, []string{models.BadgeFirstPost, models.BadgeFlavorExplorer}},
+		{"100 likes", models.UserStats{TotalPosts: 1, TotalLikesReceived: 100}, []string{models.BadgeFirstPost, models.BadgePopular}},
+		{"6 day streak", models.UserStats{TotalPosts: 6, LongestStreakDays: 6}, []string{models.BadgeFirstPost}},
+		{"7 day streak", models.UserStats{TotalPosts: 7, LongestStreakDays: 7}, []string{models.BadgeFirstPost, models.BadgeWeeklyStreak}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stats := tc.stats
+			svc := NewBadgeService(&memoryBadgeRepo{}, &fixedStatsRepo{stats: &stats}, &mockUserRepoForPost{})
+			awarded, err := svc.EvaluateBadges(1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(awarded) != len(tc.want) {
+				t.Fatalf("want %v, got %+v", tc.want, awarded)
+			}
+			for i, code := range tc.want {
+				if awarded[i].Code != code {
+					t.Fatalf("want %v, got %+v", tc.want, awarded)
+				}
+				if awarded[i].Name == "" || awarded[i].Description == "" {
+					t.Fatalf("expected badge details to be set, got %+v", awarded[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateBadges_OnlyNewlyAwarded(t *testing.T) {
+	repo := &memoryBadgeRepo{}
+	stats := &models.UserStats{TotalPosts: 1}
+	svc := NewBadgeService(repo, &fixedStatsRepo{stats: stats}, &mockUserRepoForPost{})
+
+	if _, err := svc.EvaluateBadges(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	awarded, err := svc.EvaluateBadges(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(awarded) != 0 {
+		t.Fatalf("expected no newly awarded badges, got %+v", awarded)
+	}
+
+	unseen, err := svc.GetUnseenBadges(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(unseen) != 1 || unseen[0].Name != "はじめての一服" {
+		t.Fatalf("unexpected unseen badges: %+v", unseen)
+	}
+
+	if err := svc.MarkBadgesSeen(1, []string{unseen[0].Code}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unseen, err = svc.GetUnseenBadges(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(unseen) != 0 {
+		t.Fatalf("expected no unseen badges after marking, got %+v", unseen)
+	}
+}
+
+func TestEvaluateBadges_StatsError(t *testing.T) {
+	repo := &memoryBadgeRepo{}
+	svc := NewBadgeService(repo, &fixedStatsRepo{err: errors.New("db error")}, &mockUserRepoForPost{})
+
+	if _, err := svc.EvaluateBadges(1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(repo.badges) != 0 {
+		t.Fatalf("expected no badges to be awarded, got %v", repo.badges)
+	}
+}
+
+func TestGetUserBadges_UserMissing(t *testing.T) {
+	svc := NewBadgeService(&memoryBadgeRepo{}, &fixedStatsRepo{}, &mockUserRepoMissing{})
+	if _, err := svc.GetUserBadges(999); err == nil {
+		t.Fatal("expected error when user is missing, got nil")
+	}
+}
+
+func TestWithBadgeDetails_UnknownCode(t *testing.T) {
+	badges := withBadgeDetails([]models.UserBadge{{Code: "retired_badge"}})
+	if badges[0].Name != "retired_badge" {
+		t.Fatalf("expected unknown badge code to be used as name, got %+v", badges[0])
+	}
+}
+
+func TestBadgeService_HandlesDomainEvents(t *testing.T) {
+	repo := &memoryBadgeRepo{}
+	svc := NewBadgeService(repo, &fixedStatsRepo{stats: &models.UserStats{TotalPosts: 1, TotalLikesReceived: 100}}, &mockUserRepoForPost{})
+	bus := NewDomainEventBus()
+	svc.RegisterEventHandlers(bus)
+
+	events := []*models.DomainEvent{
+		{ID: 1, Type: models.DomainEventPostCreated, Payload: json.RawMessage(`{"post_id":10,"user_id":1,"image_urls":[]}`)},
+		{ID: 2, Type: models.DomainEventLikeAdded, Payload: json.RawMessage(`{"post_id":10,"user_id":2,"post_owner_id":1,"likes":100}`)},
+	}
+	for _, event := range events {
+		if err := bus.Deliver(event); err != nil {
+			t.Fatalf("Deliver failed: %v", err)
+		}
+		if len(event.CompletedHandlers) != 1 || event.CompletedHandlers[0] != badgeEvaluateHandlerName {
+			t.Fatalf("expected badge handler to complete, got %v", event.CompletedHandlers)
+		}
+	}
+	if !repo.badges[models.BadgeFirstPost] || !repo.badges[models.BadgePopular] {
+		t.Fatalf("expected badges to be awarded, got %v", repo.badges)
+	}
+}
+
+func TestBadgeService_DomainEventRetriedOnStatsError(t *testing.T) {
+	svc := NewBadgeService(&memoryBadgeRepo{}, &fixedStatsRepo{err: errors.New("db error")}, &mockUserRepoForPost{})
+	bus := NewDomainEventBus()
+	svc.RegisterEventHandlers(bus)
+
+	event := &models.DomainEvent{ID: 1, Type: models.DomainEventPostCreated, Payload: json.RawMessage(`{"post_id":10,"user_id":1,"image_urls":[]}`)}
+	if err := bus.Deliver(event); err == nil {
+		t.Fatal("expected error so that the event is redelivered, got nil")
+	}
+	if len(event.CompletedHandlers) != 0 {
+		t.Fatalf("expected no completed handlers, got %v", event.CompletedHandlers)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+// CollectionService はコレクション関連のビジネスロジックを処理する
+type CollectionService struct {
+	collectionRepo repositories.CollectionRepository
+	postRepo       repositories.PostRepository
+	userRepo       repositories.UserRepository
+}
+
+// NewCollectionService は新しい CollectionService を作成する
+func NewCollectionService(collectionRepo repositories.CollectionRepository, postRepo repositories.PostRepository, userRepo repositories.UserRepository) *CollectionService {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		postRepo:       postRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// CreateCollection は認証ユーザーのコレクションを作成する
+// 公開設定を省略した場合は公開コレクションとして作成する
+func (s *CollectionService) CreateCollection(userID int, input *models.CreateCollectionInput) (*models.Collection, error) {
+	isPublic := true
+	if input.IsPublic != nil {
+		isPublic = *input.IsPublic
+	}
+	collection := &models.Collection{
+		UserID:      userID,
+		Name:        input.Name,
+		Description: input.Description,
+		IsPublic:    isPublic,
+	}
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// GetCollection はコレクションと収録投稿を並び順で返す
+// 非公開コレクションを作成者以外が取得しようとした場合は、存在を明かさないため repositories.ErrCollectionNotFound を返す
+// viewerID が指定されている場合、各投稿のいいね状態（is_liked）を含めて返し、閲覧者がブロック・ミュートした投稿は収録投稿・件数のいずれにも含めない
+func (s *CollectionService) GetCollection(collectionID int, viewerID *int) (*models.Collection, error) {
+	collection, err := s.collectionRepo.GetByID(collectionID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewCollection(collection, viewerID) {
+		return nil, repositories.ErrCollectionNotFound
+	}
+
+	postIDs, err := s.collectionRepo.ListPostIDs(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := s.postRepo.GetByIDs(postIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	collection.Posts = posts
+	return collection, nil
+}
+
+// GetUserCollections は指定ユーザーのコレクション一覧を返す
+// 本人が取得する場合のみ非公開コレクションを含める。収録投稿の数は viewerID の閲覧者に表示される投稿の数
+// ユーザーが存在しない場合は repositories.ErrUserNotFound を返す
+func (s *CollectionService) GetUserCollections(userID int, viewerID *int) ([]models.Collection, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+	includePrivate := viewerID != nil && *viewerID == userID
+	return s.collectionRepo.ListByUserID(userID, includePrivate, viewerID)
+}
+
+// UpdateCollection はコレクションの名前・説明・公開設定を更新する
+// 作成者以外の場合は repositories.ErrForbidden を返す
+func (s *CollectionService) UpdateCollection(userID, collectionID int, input *models.UpdateCollectionInput) (*models.Collection, error) {
+	if _, err := s.getOwnedCollection(userID, collectionID, "UpdateCollection"); err != nil {
+		return nil, err
+	}
+	return s.collectionRepo.Update(collectionID, *input, &userID)
+}
+
+// DeleteCollection はコレクションを削除する（収録されていた投稿自体は削除しない）
+// 作成者以外の場合は repositories.ErrForbidden を返す
+func (s *CollectionService) DeleteCollection(userID, collectionID int) error {
+	if _, err := s.getOwnedCollection(userID, collectionID, "DeleteCollection"); err != nil {
+		return err
+	}
+	return s.collectionRepo.Delete(collectionID)
+}
+
+// AddItem はコレクションの末尾に投稿を追加する
+// 他ユーザーの投稿も追加できる。投稿が存在しない（削除済みを含む）場合は repositories.ErrPostNotFound を返す
+func (s *CollectionService) AddItem(userID, collectionID, postID int) error {
+	if _, err := s.getOwnedCollection(userID, collectionID, "AddItem"); err != nil {
+		return err
+	}
+	if _, err := s.postRepo.GetByID(postID, nil); err != nil {
+		return err
+	}
+	return s.collectionRepo.AddItem(collectionID, postID)
+}
+
+// RemoveItem はコレクションから投稿を取り除く
+func (s *CollectionService) RemoveItem(userID, collectionID, postID int) error {
+	if _, err := s.getOwnedCollection(userID, collectionID, "RemoveItem"); err != nil {
+		return err
+	}
+	return s.collectionRepo.RemoveItem(collectionID, postID)
+}
+
+// ReorderItems はコレクション内の投稿の並び順を更新する
+// postIDs が収録されている投稿と過不足なく一致しない場合は repositories.ErrCollectionItemsMismatch を返す
+func (s *CollectionService) ReorderItems(userID, collectionID int, postIDs []int) error {
+	if _, err := s.getOwnedCollection(userID, collectionID, "ReorderItems"); err != nil {
+		return err
+	}
+	return s.collectionRepo.ReorderItems(collectionID, postIDs)
+}
+
+// getOwnedCollection はコレクションを取得し、userID が作成者であることを確認する
+// 非公開コレクションの場合は存在を明かさないため repositories.ErrCollectionNotFound を返す
+func (s *CollectionService) getOwnedCollection(userID, collectionID int, method string) (*models.Collection, error) {
+	collection, err := s.collectionRepo.GetByID(collectionID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.UserID != userID {
+		logging.L.Debug("user does not own collection",
+			"service", "CollectionService",
+			"method", method,
+			"collection_id", collectionID,
+			"user_id", userID,
+			"owner_id", collection.UserID)
+		if !collection.IsPublic {
+			return nil, repositories.ErrCollectionNotFound
+		}
+		return nil, repositories.ErrForbidden
+	}
+	return collection, nil
+}
+
+// canViewCollection は viewerID のユーザーがコレクションを閲覧できるかを返す
+func canViewCollection(collection *models.Collection, viewerID *int) bool {
+	return collection.IsPublic || (viewerID != nil && *viewerID == collection.UserID)
+}
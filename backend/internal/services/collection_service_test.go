@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// memoryCollectionRepo はメモリ上でコレクションを管理するモック
+type memoryCollectionRepo struct {
+	collections map[int]*models.Collection
+	items       map[int][]int
+	nextID      int
+}
+
+func newMemoryCollectionRepo() *memoryCollectionRepo {
+	return &memoryCollectionRepo{collections: map[int]*models.Collection{}, items: map[int][]int{}}
+}
+
+func (m *memoryCollectionRepo) Create(collection *models.Collection) error {
+	m.nextID++
+	collection.ID = m.nextID
+	c := *collection
+	m.collections[c.ID] = &c
+	return nil
+}
+
+func (m *memoryCollectionRepo) GetByID(id int, viewerID *int) (*models.Collection, error) {
+	c, ok := m.collections[id]
+	if !ok {
+		return nil, repositories.ErrCollectionNotFound
+	}
+	copied := *c
+	copied.ItemCount = len(m.items[id])
+	return &copied, nil
+}
+
+func (m *memoryCollectionRepo) ListByUserID(userID int, includePrivate bool, viewerID *int) ([]models.Collection, error) {
+	var result []models.Collection
+	for _, c := range m.collections {
+		if c.UserID == userID && (includePrivate || c.IsPublic) {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryCollectionRepo) Update(id int, input models.UpdateCollectionInput, viewerID *int) (*models.Collection, error) {
+	c, ok := m.collections[id]
+	if !ok {
+		return nil, repositories.ErrCollectionNotFound
+	}
+	if input.Name != nil {
+		c.Name = *input.Name
+	}
+	if input.IsPublic != nil {
+		c.IsPublic = *input.IsPublic
+	}
+	return m.GetByID(id, viewerID)
+}
+
+func (m *memoryCollectionRepo) Delete(id int) error {
+	if _, ok := m.collections[id]; !ok {
+		return repositories.ErrCollectionNotFound
+	}
+	delete(m.collections, id)
+	delete(m.items, id)
+	return nil
+}
+
+func (m *memoryCollectionRepo) ListPostIDs(collectionID int) ([]int, error) {
+	return m.items[collectionID], nil
+}
+
+func (m *memoryCollectionRepo) AddItem(collectionID, postID int) error {
+	for _, id := range m.items[collectionID] {
+		if id == postID {
+			return repositories.ErrAlreadyInCollection
+		}
+	}
+	m.items[collectionID] = append(m.items[collectionID], postID)
+	return nil
+}
+
+func (m *memoryCollectionRepo) RemoveItem(collectionID, postID int) error {
+	return nil
+}
+
+func (m *memoryCollectionRepo) ReorderItems(collectionID int, postIDs []int) error {
+	m.items[collectionID] = postIDs
+	return nil
+}
+
+// byIDsPostRepo は GetByIDs に渡された ID と閲覧者を記録するモック
+type byIDsPostRepo struct {
+	mockPostRepo
+	gotIDs    []int
+	gotViewer *int
+}
+
+func (m *byIDsPostRepo) GetByIDs(ids []int, userID *int) ([]models.Post, error) {
+	m.gotIDs = ids
+	m.gotViewer = userID
+	posts := make([]models.Post, 0, len(ids))
+	for _, id := range ids {
+		posts = append(posts, models.Post{ID: id})
+	}
+	return posts, nil
+}
+
+func TestCreateCollection_DefaultsToPublic(t *testing.T) {
+	svc := NewCollectionService(newMemoryCollectionRepo(), &mockPostRepo{}, &mockUserRepoForPost{})
+
+	collection, err := svc.CreateCollection(1, &models.CreateCollectionInput{Name: "お気に入り"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !collection.IsPublic || collection.UserID != 1 {
+		t.Fatalf("unexpected collection: %+v", collection)
+	}
+
+	isPublic := false
+	private, err := svc.CreateCollection(1, &models.CreateCollectionInput{Name: "秘密", IsPublic: &isPublic})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if private.IsPublic {
+		t.Fatalf("expected private collection, got %+v", private)
+	}
+}
+
+func TestGetCollection_PrivateHiddenFromOthers(t *testing.T) {
+	repo := newMemoryCollectionRepo()
+	svc := NewCollectionService(repo, &byIDsPostRepo{}, &mockUserRepoForPost{})
+	isPublic := false
+	collection, _ := svc.CreateCollection(1, &models.CreateCollectionInput{Name: "秘密", IsPublic: &isPublic})
+
+	if _, err := svc.GetCollection(collection.ID, nil); !errors.Is(err, repositories.ErrCollectionNotFound) {
+		t.Fatalf("expected ErrCollectionNotFound for anonymous, got %v", err)
+	}
+	other := 2
+	if _, err := svc.GetCollection(collection.ID, &other); !errors.Is(err, repositories.ErrCollectionNotFound) {
+		t.Fatalf("expected ErrCollectionNotFound for other user, got %v", err)
+	}
+	owner := 1
+	if _, err := svc.GetCollection(collection.ID, &owner); err != nil {
+		t.Fatalf("expected owner to see private collection, got %v", err)
+	}
+}
+
+func TestGetCollection_ReturnsPostsInOrder(t *testing.T) {
+	repo := newMemoryCollectionRepo()
+	postRepo := &byIDsPostRepo{}
+	svc := NewCollectionService(repo, postRepo, &mockUserRepoForPost{})
+	collection, _ := svc.CreateCollection(1, &models.CreateCollectionInput{Name: "お気に入り"})
+	repo.items[collection.ID] = []int{3, 1, 2}
+
+	viewer := 5
+	got, err := svc.GetCollection(collection.ID, &viewer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Posts) != 3 || got.Posts[0].ID != 3 || got.Posts[1].ID != 1 || got.Posts[2].ID != 2 {
+		t.Fatalf("unexpected posts: %+v", got.Posts)
+	}
+	if postRepo.gotViewer == nil || *postRepo.gotViewer != viewer {
+		t.Fatalf("expected viewer to be passed to GetByIDs")
+	}
+}
+
+func TestCollectionMutations_RequireOwner(t *testing.T) {
+	repo := newMemoryCollectionRepo()
+	svc := NewCollectionService(repo, &mockPostRepo{}, &mockUserRepoForPost{})
+	public, _ := svc.CreateCollection(1, &models.CreateCollectionInput{Name: "公開"})
+	isPublic := false
+	private, _ := svc.CreateCollection(1, &models.CreateCollectionInput{Name: "秘密", IsPublic: &isPublic})
+
+	if err := svc.AddItem(2, public.ID, 10); !errors.Is(err, repositories.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	// 非公開コレクションは存在自体を明かさない
+	if err := svc.AddItem(2, private.ID, 10); !errors.Is(err, repositories.ErrCollectionNotFound) {
+		t.Fatalf("expected ErrCollectionNotFound, got %v", err)
+	}
+	if err := svc.DeleteCollection(2, public.ID); !errors.Is(err, repositories.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if err := svc.AddItem(1, public.ID, 10); err != nil {
+		t.Fatalf("expected owner to add item, got %v", err)
+	}
+	if err := svc.AddItem(1, public.ID, 10); !errors.Is(err, repositories.ErrAlreadyInCollection) {
+		t.Fatalf("expected ErrAlreadyInCollection, got %v", err)
+	}
+}
+
+func TestAddItem_PostNotFound(t *testing.T) {
+	repo := newMemoryCollectionRepo()
+	svc := NewCollectionService(repo, &mockPostRepoError{}, &mockUserRepoForPost{})
+	collection, _ := svc.CreateCollection(1, &models.CreateCollectionInput{Name: "お気に入り"})
+
+	if err := svc.AddItem(1, collection.ID, 10); err == nil {
+		t.Fatalf("expected error when post lookup fails")
+	}
+	if len(repo.items[collection.ID]) != 0 {
+		t.Fatalf("expected no item to be added")
+	}
+}
+
+func TestGetUserCollections_IncludesPrivateOnlyForOwner(t *testing.T) {
+	repo := newMemoryCollectionRepo()
+	svc := NewCollectionService(repo, &mockPostRepo{}, &mockUserRepoForPost{})
+	_, _ = svc.CreateCollection(1, &models.CreateCollectionInput{Name: "公開"})
+	isPublic := false
+	_, _ = svc.CreateCollection(1, &models.CreateCollectionInput{Name: "秘密", IsPublic: &isPublic})
+
+	other := 2
+	collections, err := svc.GetUserCollections(1, &other)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collections) != 1 {
+		t.Fatalf("expected 1 public collection, got %d", len(collections))
+	}
+	owner := 1
+	collections, err = svc.GetUserCollections(1, &owner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collections) != 2 {
+		t.Fatalf("expected 2 collections for owner, got %d", len(collections))
+	}
+}
+
+func TestGetUserCollections_UserMissing(t *testing.T) {
+	svc := NewCollectionService(newMemoryCollectionRepo(), &mockPostRepo{}, &mockUserRepoMissing{})
+	if _, err := svc.GetUserCollections(1, nil); err == nil {
+		t.Fatalf("expected error for missing user")
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/pkg/logging"
+)
+
+// DomainEventHandler はドメインイベントを処理する購読者の関数
+// 配送は少なくとも1回（at-least-once）のため、同じイベントを複数回受け取っても結果が変わらないように実装する
+// エラーを返した場合、そのイベントは失敗した購読者にのみ後で再配送される
+type DomainEventHandler func(event *models.DomainEvent) error
+
+// DomainEventSubscriber はドメインイベントの購読を登録するインターフェース
+type DomainEventSubscriber interface {
+	Subscribe(eventType, name string, handler DomainEventHandler)
+}
+
+type domainEventSubscription struct {
+	name    string
+	handler DomainEventHandler
+}
+
+// DomainEventBus はアウトボックスから取り出したドメインイベントをプロセス内の購読者に配送する
+type DomainEventBus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]domainEventSubscription
+}
+
+// NewDomainEventBus は新しい DomainEventBus を作成する
+func NewDomainEventBus() *DomainEventBus {
+	return &DomainEventBus{subscriptions: map[string][]domainEventSubscription{}}
+}
+
+// Subscribe は eventType のイベントを処理する購読者を登録する
+// name は処理済みの記録に使うため、同じイベント種別の中で一意かつカンマを含まない名前にする
+func (b *DomainEventBus) Subscribe(eventType, name string, handler DomainEventHandler) {
+	if name == "" || strings.Contains(name, ",") {
+		panic(fmt.Sprintf("invalid domain event handler name: %q", name))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.subscriptions[eventType] {
+		if s.name == name {
+			panic(fmt.Sprintf("domain event handler %q is already subscribed to %s", name, eventType))
+		}
+	}
+	b.subscriptions[eventType] = append(b.subscriptions[eventType], domainEventSubscription{name: name, handler: handler})
+}
+
+// Deliver はイベントを未処理の購読者に配送し、成功した購読者を event.CompletedHandlers に追加する
+// 失敗した購読者のエラーをまとめて返す
+func (b *DomainEventBus) Deliver(event *models.DomainEvent) error {
+	b.mu.RLock()
+	subscriptions := b.subscriptions[event.Type]
+	b.mu.RUnlock()
+
+	completed := map[string]bool{}
+	for _, name := range event.CompletedHandlers {
+		completed[name] = true
+	}
+	var errs []error
+	for _, s := range subscriptions {
+		if completed[s.name] {
+			continue
+		}
+		if err := b.invoke(s, event); err != nil {
+			logging.L.Warn("domain event handler failed",
+				"service", "DomainEventBus",
+				"event_id", event.ID,
+				"event_type", event.Type,
+				"handler", s.name,
+				"error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		event.CompletedHandlers = append(event.CompletedHandlers, s.name)
+	}
+	return errors.Join(errs...)
+}
+
+// invoke は購読者を呼び出す。購読者の panic はエラーとして扱い、他の購読者の配送を続ける
+func (b *DomainEventBus) invoke(s domainEventSubscription, event *models.DomainEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handler(event)
+}
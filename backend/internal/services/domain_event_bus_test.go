@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+)
+
+func TestDomainEventBus_DeliversToSubscribersOfType(t *testing.T) {
+	bus := NewDomainEventBus()
+	var calls []string
+	bus.Subscribe(models.DomainEventPostCreated, "a", func(event *models.DomainEvent) error {
+		calls = append(calls, "a")
+		return nil
+	})
+	bus.Subscribe(models.DomainEventPostCreated, "b", func(event *models.DomainEvent) error {
+		calls = append(calls, "b")
+		return nil
+	})
+	bus.Subscribe(models.DomainEventPostDeleted, "c", func(event *models.DomainEvent) error {
+		calls = append(calls, "c")
+		return nil
+	})
+
+	event := &models.DomainEvent{ID: 1, Type: models.DomainEventPostCreated}
+	if err := bus.Deliver(event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "a" || calls[1] != "b" {
+		t.Fatalf("expected a and b to be called, got %v", calls)
+	}
+	if len(event.CompletedHandlers) != 2 {
+		t.Fatalf("expected 2 completed handlers, got %v", event.CompletedHandlers)
+	}
+}
+
+func TestDomainEventBus_SkipsCompletedAndRecoversPanic(t *testing.T) {
+	bus := NewDomainEventBus()
+	var calls []string
+	bus.Subscribe(models.DomainEventLikeAdded, "done", func(event *models.DomainEvent) error {
+		calls = append(calls, "done")
+		return nil
+	})
+	bus.Subscribe(models.DomainEventLikeAdded, "failing", func(event *models.DomainEvent) error {
+		calls = append(calls, "failing")
+		return errors.New("temporary error")
+	})
+	bus.Subscribe(models.DomainEventLikeAdded, "panicking", func(event *models.DomainEvent) error {
+		calls = append(calls, "panicking")
+		panic("boom")
+	})
+	bus.Subscribe(models.DomainEventLikeAdded, "ok", func(event *models.DomainEvent) error {
+		calls = append(calls, "ok")
+		return nil
+	})
+
+	// 前回の配送で成功した購読者には再配送しない
+	event := &models.DomainEvent{ID: 1, Type: models.DomainEventLikeAdded, CompletedHandlers: []string{"done"}}
+	err := bus.Deliver(event)
+	if err == nil {
+		t.Fatal("expected error from failing handlers")
+	}
+	if len(calls) != 3 || calls[0] != "failing" || calls[2] != "ok" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+	if len(event.CompletedHandlers) != 2 || event.CompletedHandlers[1] != "ok" {
+		t.Fatalf("expected done and ok to be completed, got %v", event.CompletedHandlers)
+	}
+}
+
+func TestDomainEventBus_RejectsDuplicateName(t *testing.T) {
+	bus := NewDomainEventBus()
+	handler := func(event *models.DomainEvent) error { return nil }
+	bus.Subscribe(models.DomainEventPostCreated, "a", handler)
+	// 種別が異なれば同じ名前で購読できる
+	bus.Subscribe(models.DomainEventPostDeleted, "a", handler)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on duplicate handler name")
+		}
+	}()
+	bus.Subscribe(models.DomainEventPostCreated, "a", handler)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/logging"
+	"go-shisha-backend/pkg/mailer"
+)
+
+// メールアドレスの確認のセンチネルエラー
+var (
+	// ErrEmailAlreadyVerified は確認済みのメールアドレスに確認メールを再送しようとした場合のエラー
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrInvalidVerificationToken は確認用トークンが不正・有効期限切れ、またはメールアドレスが変わっている場合のエラー
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+)
+
+// EmailVerificationService は署名付きのリンクによるメールアドレスの確認を扱う
+// 確認用トークンは DB に保存せず、ユーザーIDとメールアドレスに署名して有効期限を付ける
+type EmailVerificationService struct {
+	userRepo repositories.AuthUserRepository
+	mailer   mailer.Mailer
+	// verifyURL はメールに記載するメールアドレスの確認画面の URL（?token= を付けて送る）
+	verifyURL string
+}
+
+// NewEmailVerificationService は新しい EmailVerificationService を作成する
+func NewEmailVerificationService(userRepo repositories.AuthUserRepository, m mailer.Mailer, verifyURL string) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:  userRepo,
+		mailer:    m,
+		verifyURL: verifyURL,
+	}
+}
+
+// SendVerification はユーザーのメールアドレスに確認用のリンクを送る（メールはバックグラウンドで送信する）
+// 確認済みの場合は ErrEmailAlreadyVerified を返す
+func (s *EmailVerificationService) SendVerification(user *models.User) error {
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	token, err := auth.GenerateEmailVerificationToken(int64(user.ID), user.Email)
+	if err != nil {
+		return fmt.Errorf("failed to generate email verification token: %w", err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "メールアドレスの確認",
+		Body: fmt.Sprintf("%s さん\n\n"+
+			"ご登録ありがとうございます。以下のリンクから%d時間以内にメールアドレスを確認してください。\n\n"+
+			"%s?token=%s\n\n"+
+			"このメールに心当たりがない場合は、このメールを破棄してください。\n",
+			user.DisplayName, int(auth.EmailVerificationTokenTTL.Hours()), s.verifyURL, url.QueryEscape(token)),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			logging.L.Error("failed to send verification mail", "service", "EmailVerificationService", "method", "SendVerification", "user_id", user.ID, "error", err)
+		}
+	}()
+	logging.L.Info("verification mail requested", "service", "EmailVerificationService", "method", "SendVerification", "user_id", user.ID)
+	return nil
+}
+
+// ResendVerification はユーザーに確認用のリンクを送り直す
+func (s *EmailVerificationService) ResendVerification(userID int) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return s.SendVerification(user)
+}
+
+// Verify は確認用トークンを検証し、ユーザーのメールアドレスを確認済みにする
+// 確認済みのユーザーのトークンで再度確認してもエラーにしない
+func (s *EmailVerificationService) Verify(token string) error {
+	claims, err := auth.ValidateEmailVerificationToken(token)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	if err := s.userRepo.MarkEmailVerified(int(claims.UserID), claims.Email); err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			// 発行後にユーザーが削除されたか、メールアドレスが変わっている
+			return ErrInvalidVerificationToken
+		}
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	return nil
+}
+
+// IsEmailVerified はユーザーのメールアドレスが確認済みかどうかを返す
+func (s *EmailVerificationService) IsEmailVerified(userID int) (bool, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false, err
+	}
+	return user.EmailVerifiedAt != nil, nil
+}
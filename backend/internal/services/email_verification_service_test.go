@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/mailer"
+)
+
+func TestEmailVerification(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	mail := &recordingMailer{sent: make(chan mailer.Message, 1)}
+	svc := NewEmailVerificationService(userRepo, mail, "http://localhost:3000/verify-email")
+	authService := NewAuthService(userRepo, newMockRefreshTokenRepo(), NewTokenRevocationStore(newMemoryTokenRevocationRepo()), svc, disabledMFAVerifier{}, nopLoginLimiter{})
+
+	receiveToken := func(t *testing.T) string {
+		t.Helper()
+		select {
+		case msg := <-mail.sent:
+			match := regexp.MustCompile(`http://localhost:3000/verify-email\?token=(\S+)`).FindStringSubmatch(msg.Body)
+			if match == nil {
+				t.Fatalf("expected verification link in mail body, got %q", msg.Body)
+			}
+			token, err := url.QueryUnescape(match[1])
+			if err != nil {
+				t.Fatalf("failed to unescape token: %v", err)
+			}
+			return token
+		case <-time.After(time.Second):
+			t.Fatal("expected verification mail to be sent")
+			return ""
+		}
+	}
+
+	user, err := authService.Register(&models.CreateUserInput{
+		Email:       "verify@example.com",
+		Password:    "password12345",
+		DisplayName: "Verify User",
+	})
+	if err != nil {
+		t.Fatalf("failed to register test user: %v", err)
+	}
+	// 登録時に確認メールを送る
+	token := receiveToken(t)
+
+	t.Run("異常系: 確認用トークンは認証に使えず、Access Tokenは確認に使えない", func(t *testing.T) {
+		if _, err := auth.ValidateToken(token); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got %v", err)
+		}
+		accessToken, err := auth.GenerateAccessToken(int64(user.ID), models.RoleUser, 0, "")
+		if err != nil {
+			t.Fatalf("failed to generate access token: %v", err)
+		}
+		if err := svc.Verify(accessToken); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Fatalf("expected ErrInvalidVerificationToken, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 発行後にメールアドレスが変わったトークン", func(t *testing.T) {
+		stale, err := auth.GenerateEmailVerificationToken(int64(user.ID), "old@example.com")
+		if err != nil {
+			t.Fatalf("failed to generate token: %v", err)
+		}
+		if err := svc.Verify(stale); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Fatalf("expected ErrInvalidVerificationToken, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 再送したリンクでも確認できる", func(t *testing.T) {
+		verified, err := svc.IsEmailVerified(user.ID)
+		if err != nil || verified {
+			t.Fatalf("expected unverified user, got %v (err=%v)", verified, err)
+		}
+		if err := svc.ResendVerification(user.ID); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		resent := receiveToken(t)
+		if err := svc.Verify(resent); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		verified, err = svc.IsEmailVerified(user.ID)
+		if err != nil || !verified {
+			t.Fatalf("expected verified user, got %v (err=%v)", verified, err)
+		}
+		// 確認済みのユーザーの古いリンクもエラーにしない
+		if err := svc.Verify(token); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 確認済みのユーザーには再送しない", func(t *testing.T) {
+		if err := svc.ResendVerification(user.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+			t.Fatalf("expected ErrEmailAlreadyVerified, got %v", err)
+		}
+	})
+}
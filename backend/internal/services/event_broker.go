@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"sync"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/pkg/logging"
+)
+
+// ErrBrokerClosed はシャットダウン済みの EventBroker に購読しようとした場合のエラー
+var ErrBrokerClosed = errors.New("event broker is closed")
+
+// defaultStreamBufferSize は接続ごとに保持する未送信イベント数の既定値
+const defaultStreamBufferSize = 32
+
+// StreamPublisher はストリームにイベントを配信するインターフェース
+type StreamPublisher interface {
+	Publish(event models.StreamEvent)
+}
+
+// Subscription はストリーム接続1本分の購読
+// 配信が追いつかずバッファがあふれた場合やブローカーの終了時には Done が閉じられる
+type Subscription struct {
+	userID int
+	events chan models.StreamEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Events は配信されたイベントを受け取るチャネルを返す
+func (s *Subscription) Events() <-chan models.StreamEvent {
+	return s.events
+}
+
+// Done は購読が終了したときに閉じられるチャネルを返す
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// EventBroker はプロセス内でストリームイベントを各接続に配信する
+// Publish は接続ごとのバッファに積むだけでブロックしない。バッファがあふれた接続は切断し、クライアントの再接続に任せる
+type EventBroker struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	bufferSize  int
+	closed      bool
+}
+
+// NewEventBroker は新しい EventBroker を作成する（bufferSize が0以下の場合は既定値を使う）
+func NewEventBroker(bufferSize int) *EventBroker {
+	if bufferSize <= 0 {
+		bufferSize = defaultStreamBufferSize
+	}
+	return &EventBroker{
+		subscribers: map[*Subscription]struct{}{},
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe は指定ユーザーの接続として購読を開始する
+// ブローカーが終了済みの場合は ErrBrokerClosed を返す
+func (b *EventBroker) Subscribe(userID int) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+	sub := &Subscription{
+		userID: userID,
+		events: make(chan models.StreamEvent, b.bufferSize),
+		done:   make(chan struct{}),
+	}
+	b.subscribers[sub] = struct{}{}
+	logging.L.Debug("stream subscribed", "service", "EventBroker", "user_id", userID, "subscribers", len(b.subscribers))
+	return sub, nil
+}
+
+// Unsubscribe は購読を終了する（複数回呼び出しても安全）
+func (b *EventBroker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		logging.L.Debug("stream unsubscribed", "service", "EventBroker", "user_id", sub.userID, "subscribers", len(b.subscribers))
+	}
+	sub.close()
+}
+
+// Publish はイベントを配信対象の接続に配信する
+func (b *EventBroker) Publish(event models.StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		if event.UserID != nil && *event.UserID != sub.userID {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// 読み出しが追いつかない接続は切断する（クライアントは再接続して最新状態を取得する）
+			logging.L.Warn("stream buffer overflow, disconnecting subscriber",
+				"service", "EventBroker",
+				"user_id", sub.userID,
+				"event", event.Type)
+			delete(b.subscribers, sub)
+			sub.close()
+		}
+	}
+}
+
+// Close はすべての購読を終了し、以降の購読を拒否する
+// http.Server.RegisterOnShutdown に登録し、Shutdown がストリーム接続の終了を待ち続けないようにする
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subscribers {
+		sub.close()
+	}
+	logging.L.Info("event broker closed", "service", "EventBroker", "subscribers", len(b.subscribers))
+	b.subscribers = map[*Subscription]struct{}{}
+}
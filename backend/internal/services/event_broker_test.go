@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// receiveEvent はタイムアウト付きでイベントを1件受け取る
+func receiveEvent(t *testing.T, sub *Subscription) (models.StreamEvent, bool) {
+	t.Helper()
+	select {
+	case ev := <-sub.Events():
+		return ev, true
+	case <-time.After(100 * time.Millisecond):
+		return models.StreamEvent{}, false
+	}
+}
+
+func TestEventBroker_BroadcastAndTargeted(t *testing.T) {
+	broker := NewEventBroker(4)
+	sub1, err := broker.Subscribe(1)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	sub2, err := broker.Subscribe(2)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	broker.Publish(models.StreamEvent{Type: models.StreamEventPostCreated})
+	for _, sub := range []*Subscription{sub1, sub2} {
+		if ev, ok := receiveEvent(t, sub); !ok || ev.Type != models.StreamEventPostCreated {
+			t.Fatalf("expected broadcast event, got %+v ok=%v", ev, ok)
+		}
+	}
+
+	target := 2
+	broker.Publish(models.StreamEvent{Type: models.StreamEventNotification, UserID: &target})
+	if ev, ok := receiveEvent(t, sub2); !ok || ev.Type != models.StreamEventNotification {
+		t.Fatalf("expected targeted event for user 2, got %+v ok=%v", ev, ok)
+	}
+	if _, ok := receiveEvent(t, sub1); ok {
+		t.Fatalf("expected user 1 not to receive user 2's notification")
+	}
+}
+
+func TestEventBroker_OverflowDisconnectsSlowSubscriber(t *testing.T) {
+	broker := NewEventBroker(2)
+	slow, _ := broker.Subscribe(1)
+
+	for i := 0; i < 3; i++ {
+		broker.Publish(models.StreamEvent{Type: models.StreamEventPostCreated})
+	}
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatalf("expected slow subscriber to be disconnected on overflow")
+	}
+	// 切断後の Unsubscribe も安全に呼び出せる
+	broker.Unsubscribe(slow)
+}
+
+func TestEventBroker_CloseEndsSubscriptions(t *testing.T) {
+	broker := NewEventBroker(0)
+	sub, _ := broker.Subscribe(1)
+
+	broker.Close()
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatalf("expected subscription to be closed")
+	}
+	if _, err := broker.Subscribe(1); !errors.Is(err, ErrBrokerClosed) {
+		t.Fatalf("expected ErrBrokerClosed, got %v", err)
+	}
+	// 二重の Close・終了後の Publish は何もしない
+	broker.Close()
+	broker.Publish(models.StreamEvent{Type: models.StreamEventPostCreated})
+}
+
+func TestPostService_PublishesStreamEvents(t *testing.T) {
+	broker := NewEventBroker(0)
+	sub, _ := broker.Subscribe(9)
+	postSvc := NewPostService(&ownedPostRepo{ownerID: 5}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{}, nopStatsInvalidator{}, nopNotifier{}, broker)
+
+	if _, err := postSvc.LikePost(2, 10); err != nil {
+		t.Fatalf("LikePost failed: %v", err)
+	}
+	ev, ok := receiveEvent(t, sub)
+	if !ok || ev.Type != models.StreamEventLikeCountChanged {
+		t.Fatalf("expected like_count_changed, got %+v ok=%v", ev, ok)
+	}
+	if data, _ := ev.Data.(models.LikeCountStreamData); data.PostID != 10 {
+		t.Fatalf("unexpected payload: %+v", ev.Data)
+	}
+}
+
+func TestNotificationService_PublishesToRecipient(t *testing.T) {
+	broker := NewEventBroker(0)
+	recipient, _ := broker.Subscribe(1)
+	other, _ := broker.Subscribe(3)
+	svc := NewNotificationService(&recordingNotificationRepo{}, broker)
+	postID := 10
+
+	if err := svc.Notify(models.NotificationEvent{UserID: 1, ActorID: 2, Type: models.NotificationTypePostLiked, PostID: &postID}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if ev, ok := receiveEvent(t, recipient); !ok || ev.Type != models.StreamEventNotification {
+		t.Fatalf("expected notification event for recipient, got %+v ok=%v", ev, ok)
+	}
+	if _, ok := receiveEvent(t, other); ok {
+		t.Fatalf("expected other users not to receive the notification")
+	}
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+	"go-shisha-backend/pkg/totp"
+)
+
+// 2段階認証のセンチネルエラー
+var (
+	// ErrMFAAlreadyEnabled は2段階認証が有効なユーザーが登録をやり直そうとした場合のエラー
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	// ErrMFANotEnabled は2段階認証が有効でない（登録を開始していない場合を含む）ユーザーの操作のエラー
+	ErrMFANotEnabled = errors.New("two-factor authentication not enabled")
+	// ErrInvalidMFACode は2段階認証のコードまたはリカバリーコードが誤っている・使用済みの場合のエラー
+	ErrInvalidMFACode = errors.New("invalid two-factor authentication code")
+)
+
+const (
+	// totpSkew はコードの検証で前後に許容する時間ステップ数（端末の時計のずれを吸収する）
+	totpSkew = 1
+	// recoveryCodeCount は一度に発行するリカバリーコードの数
+	recoveryCodeCount = 10
+	// recoveryCodeAlphabet はリカバリーコードに使う文字（読み間違えやすい 0/O・1/I/L を除く）
+	recoveryCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+	// recoveryCodeLength はリカバリーコードの文字数（表示時は4文字ごとにハイフンで区切る）
+	recoveryCodeLength = 12
+)
+
+// MFAService は TOTP による2段階認証の登録・無効化とログイン時のコードの検証を扱う
+type MFAService struct {
+	userRepo repositories.AuthUserRepository
+	mfaRepo  repositories.MFARepository
+	// issuer は認証アプリに表示するサービス名
+	issuer string
+	now    func() time.Time
+}
+
+// NewMFAService は新しい MFAService を作成する
+func NewMFAService(userRepo repositories.AuthUserRepository, mfaRepo repositories.MFARepository, issuer string) *MFAService {
+	return &MFAService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		issuer:   issuer,
+		now:      time.Now,
+	}
+}
+
+// Status はユーザーの2段階認証の状態を返す
+func (s *MFAService) Status(userID int) (*models.MFAStatus, error) {
+	t, err := s.getEnabledTOTP(userID)
+	if err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			return &models.MFAStatus{}, nil
+		}
+		return nil, err
+	}
+	remaining, err := s.mfaRepo.CountRecoveryCodes(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return &models.MFAStatus{Enabled: true, EnabledAt: t.EnabledAt, RemainingRecoveryCodes: remaining}, nil
+}
+
+// IsEnabled はユーザーの2段階認証が有効かどうかを返す
+func (s *MFAService) IsEnabled(userID int) (bool, error) {
+	if _, err := s.getEnabledTOTP(userID); err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// BeginEnrollment は新しいシークレットを発行して TOTP の登録を開始する
+// ConfirmEnrollment で最初のコードを確認するまで2段階認証は有効にならない
+// 2段階認証が有効な場合は ErrMFAAlreadyEnabled を返す
+func (s *MFAService) BeginEnrollment(userID int) (*models.TOTPEnrollment, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if _, err := s.getEnabledTOTP(userID); err == nil {
+		return nil, ErrMFAAlreadyEnabled
+	} else if !errors.Is(err, ErrMFANotEnabled) {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	if err := s.mfaRepo.SaveTOTPSecret(userID, secret); err != nil {
+		return nil, fmt.Errorf("failed to save totp secret: %w", err)
+	}
+	logging.L.Info("totp enrollment started", "service", "MFAService", "method", "BeginEnrollment", "user_id", userID)
+	return &models.TOTPEnrollment{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment は認証アプリに表示された最初のコードを確認して2段階認証を有効にし、リカバリーコードを返す
+// 登録を開始していない場合は ErrMFANotEnabled、有効な場合は ErrMFAAlreadyEnabled、コードが誤っている場合は ErrInvalidMFACode を返す
+func (s *MFAService) ConfirmEnrollment(userID int, code string) ([]string, error) {
+	t, err := s.mfaRepo.GetTOTP(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTOTPNotFound) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+	if t.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	counter, ok, err := totp.Validate(t.Secret, normalizeTOTPCode(code), s.now(), totpSkew)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate totp code: %w", err)
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := s.mfaRepo.EnableTOTP(userID, counter, normalizeRecoveryCodes(codes)); err != nil {
+		if errors.Is(err, repositories.ErrTOTPNotFound) {
+			// 確認中に他のリクエストで有効化・無効化された
+			return nil, ErrMFANotEnabled
+		}
+		return nil, fmt.Errorf("failed to enable totp: %w", err)
+	}
+	logging.L.Info("two-factor authentication enabled", "service", "MFAService", "method", "ConfirmEnrollment", "user_id", userID)
+	return codes, nil
+}
+
+// Disable はパスワードを確認して2段階認証を無効にし、リカバリーコードを削除する
+// パスワードが誤っている場合は ErrIncorrectPassword、有効でない場合は ErrMFANotEnabled を返す
+func (s *MFAService) Disable(userID int, password string) error {
+	if _, err := verifyUserPassword(s.userRepo, userID, password); err != nil {
+		return err
+	}
+	if _, err := s.getEnabledTOTP(userID); err != nil {
+		return err
+	}
+	if err := s.mfaRepo.DeleteTOTP(userID); err != nil {
+		return fmt.Errorf("failed to delete totp: %w", err)
+	}
+	logging.L.Info("two-factor authentication disabled", "service", "MFAService", "method", "Disable", "user_id", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes はパスワードを確認してリカバリーコードを発行し直す（それまでのコードは使えなくなる）
+// パスワードが誤っている場合は ErrIncorrectPassword、有効でない場合は ErrMFANotEnabled を返す
+func (s *MFAService) RegenerateRecoveryCodes(userID int, password string) ([]string, error) {
+	if _, err := verifyUserPassword(s.userRepo, userID, password); err != nil {
+		return nil, err
+	}
+	if _, err := s.getEnabledTOTP(userID); err != nil {
+		return nil, err
+	}
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, normalizeRecoveryCodes(codes)); err != nil {
+		return nil, fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// VerifyLoginCode はログイン時に入力された TOTP のコードまたはリカバリーコードを検証する
+// TOTP のコードは受け付け済みの時間ステップ以前のものを拒否し、リカバリーコードは使用済みにする
+// コードが誤っている・使用済みの場合は ErrInvalidMFACode を返す
+func (s *MFAService) VerifyLoginCode(userID int, code string) error {
+	t, err := s.getEnabledTOTP(userID)
+	if err != nil {
+		return err
+	}
+
+	if totpCode := normalizeTOTPCode(code); len(totpCode) == totp.Digits {
+		counter, ok, err := totp.Validate(t.Secret, totpCode, s.now(), totpSkew)
+		if err != nil {
+			return fmt.Errorf("failed to validate totp code: %w", err)
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		if err := s.mfaRepo.UseTOTPCounter(userID, counter); err != nil {
+			if errors.Is(err, repositories.ErrTOTPCodeReused) {
+				logging.L.Warn("totp code reused", "service", "MFAService", "method", "VerifyLoginCode", "user_id", userID)
+				return ErrInvalidMFACode
+			}
+			return fmt.Errorf("failed to use totp code: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.mfaRepo.ConsumeRecoveryCode(userID, normalizeRecoveryCode(code)); err != nil {
+		if errors.Is(err, repositories.ErrRecoveryCodeNotFound) {
+			return ErrInvalidMFACode
+		}
+		return fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	logging.L.Info("recovery code used for login", "service", "MFAService", "method", "VerifyLoginCode", "user_id", userID)
+	return nil
+}
+
+// getEnabledTOTP は有効な TOTP の設定を返す（登録中または未登録の場合は ErrMFANotEnabled）
+func (s *MFAService) getEnabledTOTP(userID int) (*models.TOTP, error) {
+	t, err := s.mfaRepo.GetTOTP(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTOTPNotFound) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+	if t.EnabledAt == nil {
+		return nil, ErrMFANotEnabled
+	}
+	return t, nil
+}
+
+// generateRecoveryCodes は recoveryCodeCount 個のリカバリーコードを XXXX-XXXX-XXXX の形式で生成する
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		b := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		var sb strings.Builder
+		for i, v := range b {
+			if i > 0 && i%4 == 0 {
+				sb.WriteByte('-')
+			}
+			// 256 は文字数で割り切れないが、偏りは 12 文字の推測の難しさに影響しない程度
+			sb.WriteByte(recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, sb.String())
+	}
+	return codes, nil
+}
+
+// normalizeTOTPCode は入力された TOTP のコードから空白を取り除く
+func normalizeTOTPCode(code string) string {
+	return strings.Join(strings.Fields(code), "")
+}
+
+// normalizeRecoveryCode はリカバリーコードからハイフン・空白を取り除いて大文字にする（保存・照合はこの形式で行う）
+func normalizeRecoveryCode(code string) string {
+	code = strings.Join(strings.Fields(code), "")
+	return strings.ToUpper(strings.ReplaceAll(code, "-", ""))
+}
+
+func normalizeRecoveryCodes(codes []string) []string {
+	normalized := make([]string, len(codes))
+	for i, code := range codes {
+		normalized[i] = normalizeRecoveryCode(code)
+	}
+	return normalized
+}
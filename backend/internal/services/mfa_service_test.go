@@ -0,0 +1,360 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/totp"
+)
+
+// memoryMFARepo はテスト用の MFARepository（リカバリーコードは生のまま保持する）
+type memoryMFARepo struct {
+	totps         map[int]*models.TOTP
+	recoveryCodes map[int]map[string]bool
+}
+
+func newMemoryMFARepo() *memoryMFARepo {
+	return &memoryMFARepo{
+		totps:         make(map[int]*models.TOTP),
+		recoveryCodes: make(map[int]map[string]bool),
+	}
+}
+
+func (r *memoryMFARepo) GetTOTP(userID int) (*models.TOTP, error) {
+	t, ok := r.totps[userID]
+	if !ok {
+		return nil, repositories.ErrTOTPNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *memoryMFARepo) SaveTOTPSecret(userID int, secret string) error {
+	if t, ok := r.totps[userID]; ok && t.EnabledAt != nil {
+		return errors.New("totp already enabled")
+	}
+	r.totps[userID] = &models.TOTP{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (r *memoryMFARepo) EnableTOTP(userID int, counter int64, recoveryCodes []string) error {
+	t, ok := r.totps[userID]
+	if !ok || t.EnabledAt != nil {
+		return repositories.ErrTOTPNotFound
+	}
+	now := time.Now()
+	t.EnabledAt = &now
+	t.LastUsedCounter = counter
+	return r.ReplaceRecoveryCodes(userID, recoveryCodes)
+}
+
+func (r *memoryMFARepo) UseTOTPCounter(userID int, counter int64) error {
+	t, ok := r.totps[userID]
+	if !ok || t.EnabledAt == nil || counter <= t.LastUsedCounter {
+		return repositories.ErrTOTPCodeReused
+	}
+	t.LastUsedCounter = counter
+	return nil
+}
+
+func (r *memoryMFARepo) ReplaceRecoveryCodes(userID int, recoveryCodes []string) error {
+	codes := make(map[string]bool, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		codes[code] = false
+	}
+	r.recoveryCodes[userID] = codes
+	return nil
+}
+
+func (r *memoryMFARepo) ConsumeRecoveryCode(userID int, recoveryCode string) error {
+	used, ok := r.recoveryCodes[userID][recoveryCode]
+	if !ok || used {
+		return repositories.ErrRecoveryCodeNotFound
+	}
+	r.recoveryCodes[userID][recoveryCode] = true
+	return nil
+}
+
+func (r *memoryMFARepo) CountRecoveryCodes(userID int) (int, error) {
+	count := 0
+	for _, used := range r.recoveryCodes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryMFARepo) DeleteTOTP(userID int) error {
+	delete(r.totps, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
+
+// newMFATestUser はパスワード "password12345" のユーザーを登録する
+func newMFATestUser(t *testing.T, userRepo *mockAuthUserRepo, email string) *models.User {
+	t.Helper()
+	user := &models.User{Email: email, DisplayName: "MFA User"}
+	if err := user.HashPassword("password12345"); err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	return user
+}
+
+// enableTestTOTP は時刻 now で TOTP を有効にし、シークレットとリカバリーコードを返す
+func enableTestTOTP(t *testing.T, svc *MFAService, userID int, now time.Time) (string, []string) {
+	t.Helper()
+	svc.now = func() time.Time { return now }
+	enrollment, err := svc.BeginEnrollment(userID)
+	if err != nil {
+		t.Fatalf("failed to begin enrollment: %v", err)
+	}
+	code, err := totp.Code(enrollment.Secret, now)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	recoveryCodes, err := svc.ConfirmEnrollment(userID, code)
+	if err != nil {
+		t.Fatalf("failed to confirm enrollment: %v", err)
+	}
+	return enrollment.Secret, recoveryCodes
+}
+
+func TestMFAEnrollment(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	userRepo := newMockAuthUserRepo()
+	mfaRepo := newMemoryMFARepo()
+	svc := NewMFAService(userRepo, mfaRepo, "Go Shisha")
+	svc.now = func() time.Time { return now }
+	user := newMFATestUser(t, userRepo, "mfa@example.com")
+
+	t.Run("異常系: 登録を開始していない場合は確認できない", func(t *testing.T) {
+		if _, err := svc.ConfirmEnrollment(user.ID, "123456"); !errors.Is(err, ErrMFANotEnabled) {
+			t.Fatalf("expected ErrMFANotEnabled, got %v", err)
+		}
+	})
+
+	enrollment, err := svc.BeginEnrollment(user.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if enrollment.Secret == "" || enrollment.OTPAuthURI != totp.URI("Go Shisha", "mfa@example.com", enrollment.Secret) {
+		t.Fatalf("unexpected enrollment: %+v", enrollment)
+	}
+	if enabled, _ := svc.IsEnabled(user.ID); enabled {
+		t.Fatal("expected mfa to stay disabled until confirmed")
+	}
+
+	t.Run("異常系: 誤ったコードでは有効にならない", func(t *testing.T) {
+		if _, err := svc.ConfirmEnrollment(user.ID, "000000"); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected ErrInvalidMFACode, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 最初のコードで有効になりリカバリーコードを返す", func(t *testing.T) {
+		code, _ := totp.Code(enrollment.Secret, now)
+		codes, err := svc.ConfirmEnrollment(user.ID, code)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(codes) != recoveryCodeCount {
+			t.Fatalf("expected %d recovery codes, got %d", recoveryCodeCount, len(codes))
+		}
+		status, err := svc.Status(user.ID)
+		if err != nil || !status.Enabled || status.RemainingRecoveryCodes != recoveryCodeCount {
+			t.Fatalf("unexpected status: %+v (err=%v)", status, err)
+		}
+		// 確認に使ったコードはログインに使えない
+		if err := svc.VerifyLoginCode(user.ID, code); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected confirmation code to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 有効な場合は登録をやり直せない", func(t *testing.T) {
+		if _, err := svc.BeginEnrollment(user.ID); !errors.Is(err, ErrMFAAlreadyEnabled) {
+			t.Fatalf("expected ErrMFAAlreadyEnabled, got %v", err)
+		}
+	})
+}
+
+func TestMFAVerifyLoginCode(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	userRepo := newMockAuthUserRepo()
+	svc := NewMFAService(userRepo, newMemoryMFARepo(), "Go Shisha")
+	user := newMFATestUser(t, userRepo, "login-code@example.com")
+	secret, recoveryCodes := enableTestTOTP(t, svc, user.ID, now)
+
+	t.Run("正常系: 次の時間ステップのコードは一度だけ使える", func(t *testing.T) {
+		svc.now = func() time.Time { return now.Add(totp.Period) }
+		code, _ := totp.Code(secret, now.Add(totp.Period))
+		if err := svc.VerifyLoginCode(user.ID, code); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := svc.VerifyLoginCode(user.ID, code); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected reused code to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 時計のずれは1ステップまで許容する", func(t *testing.T) {
+		svc.now = func() time.Time { return now.Add(3 * totp.Period) }
+		code, _ := totp.Code(secret, now.Add(4*totp.Period))
+		if err := svc.VerifyLoginCode(user.ID, code); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		code, _ = totp.Code(secret, now.Add(6*totp.Period))
+		if err := svc.VerifyLoginCode(user.ID, code); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected ErrInvalidMFACode, got %v", err)
+		}
+	})
+
+	t.Run("正常系: リカバリーコードは表記ゆれを許容し一度だけ使える", func(t *testing.T) {
+		input := " " + recoveryCodes[0][:4] + recoveryCodes[0][5:] + " "
+		if err := svc.VerifyLoginCode(user.ID, input); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := svc.VerifyLoginCode(user.ID, recoveryCodes[0]); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected used recovery code to be rejected, got %v", err)
+		}
+		status, _ := svc.Status(user.ID)
+		if status.RemainingRecoveryCodes != recoveryCodeCount-1 {
+			t.Fatalf("expected %d remaining recovery codes, got %d", recoveryCodeCount-1, status.RemainingRecoveryCodes)
+		}
+	})
+}
+
+func TestMFADisableAndRegenerate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	userRepo := newMockAuthUserRepo()
+	svc := NewMFAService(userRepo, newMemoryMFARepo(), "Go Shisha")
+	user := newMFATestUser(t, userRepo, "disable@example.com")
+
+	if err := svc.Disable(user.ID, "password12345"); !errors.Is(err, ErrMFANotEnabled) {
+		t.Fatalf("expected ErrMFANotEnabled, got %v", err)
+	}
+	_, oldCodes := enableTestTOTP(t, svc, user.ID, now)
+
+	t.Run("異常系: パスワードが誤っている場合は再発行しない", func(t *testing.T) {
+		if _, err := svc.RegenerateRecoveryCodes(user.ID, "wrong-password"); !errors.Is(err, ErrIncorrectPassword) {
+			t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 再発行すると以前のリカバリーコードは使えない", func(t *testing.T) {
+		newCodes, err := svc.RegenerateRecoveryCodes(user.ID, "password12345")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := svc.VerifyLoginCode(user.ID, oldCodes[0]); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected old recovery code to be rejected, got %v", err)
+		}
+		if err := svc.VerifyLoginCode(user.ID, newCodes[0]); err != nil {
+			t.Fatalf("expected new recovery code to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("異常系: パスワードが誤っている場合は無効にしない", func(t *testing.T) {
+		if err := svc.Disable(user.ID, "wrong-password"); !errors.Is(err, ErrIncorrectPassword) {
+			t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+		}
+		if enabled, _ := svc.IsEnabled(user.ID); !enabled {
+			t.Fatal("expected mfa to remain enabled")
+		}
+	})
+
+	t.Run("正常系: パスワードを確認して無効にする", func(t *testing.T) {
+		if err := svc.Disable(user.ID, "password12345"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		status, _ := svc.Status(user.ID)
+		if status.Enabled || status.RemainingRecoveryCodes != 0 {
+			t.Fatalf("unexpected status after disable: %+v", status)
+		}
+	})
+}
+
+func TestMFALogin(t *testing.T) {
+	now := time.Now()
+	userRepo := newMockAuthUserRepo()
+	mfaService := NewMFAService(userRepo, newMemoryMFARepo(), "Go Shisha")
+	svc := NewAuthService(userRepo, newMockRefreshTokenRepo(), NewTokenRevocationStore(newMemoryTokenRevocationRepo()), nopVerificationSender{}, mfaService, nopLoginLimiter{})
+	user := newMFATestUser(t, userRepo, "mfa-login@example.com")
+	loginInput := &models.LoginInput{Email: "mfa-login@example.com", Password: "password12345"}
+
+	t.Run("正常系: 2段階認証が無効な場合はパスワードだけでログインできる", func(t *testing.T) {
+		if _, accessToken, _, err := svc.Login(loginInput, models.SessionDevice{}); err != nil || accessToken == "" {
+			t.Fatalf("expected tokens, got err=%v", err)
+		}
+	})
+
+	secret, recoveryCodes := enableTestTOTP(t, mfaService, user.ID, now.Add(-totp.Period))
+	mfaService.now = func() time.Time { return now }
+	challenge := func() string {
+		t.Helper()
+		_, accessToken, _, err := svc.Login(loginInput, models.SessionDevice{})
+		var challengeErr *MFAChallengeError
+		if !errors.As(err, &challengeErr) || !errors.Is(err, ErrMFARequired) {
+			t.Fatalf("expected MFAChallengeError, got %v", err)
+		}
+		if accessToken != "" || challengeErr.ChallengeToken == "" {
+			t.Fatal("expected only a challenge token")
+		}
+		return challengeErr.ChallengeToken
+	}
+
+	t.Run("異常系: パスワードが誤っている場合はチャレンジトークンを返さない", func(t *testing.T) {
+		_, _, _, err := svc.Login(&models.LoginInput{Email: loginInput.Email, Password: "wrong-password"}, models.SessionDevice{})
+		if !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+	})
+
+	t.Run("異常系: コードが誤っている場合はログインできない", func(t *testing.T) {
+		if _, _, _, err := svc.CompleteMFALogin(challenge(), "000000", models.SessionDevice{}); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected ErrInvalidMFACode, got %v", err)
+		}
+	})
+
+	t.Run("異常系: コードを誤ったチャレンジトークンは正しいコードでも使えない", func(t *testing.T) {
+		token := challenge()
+		if _, _, _, err := svc.CompleteMFALogin(token, "000000", models.SessionDevice{}); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("expected ErrInvalidMFACode, got %v", err)
+		}
+		code, _ := totp.Code(secret, now)
+		if _, _, _, err := svc.CompleteMFALogin(token, code, models.SessionDevice{}); !errors.Is(err, ErrInvalidMFAChallenge) {
+			t.Fatalf("expected consumed challenge to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("異常系: チャレンジトークンを認証に使えない", func(t *testing.T) {
+		if _, _, _, err := svc.CompleteMFALogin("invalid", "000000", models.SessionDevice{}); !errors.Is(err, ErrInvalidMFAChallenge) {
+			t.Fatalf("expected ErrInvalidMFAChallenge, got %v", err)
+		}
+	})
+
+	t.Run("正常系: コードでログインが完了しチャレンジトークンは使えなくなる", func(t *testing.T) {
+		token := challenge()
+		code, _ := totp.Code(secret, now)
+		loggedIn, accessToken, refreshToken, err := svc.CompleteMFALogin(token, code, models.SessionDevice{UserAgent: "TestAgent"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if loggedIn.ID != user.ID || accessToken == "" || refreshToken == "" {
+			t.Fatal("expected tokens for the user")
+		}
+		if _, _, _, err := svc.CompleteMFALogin(token, recoveryCodes[0], models.SessionDevice{}); !errors.Is(err, ErrInvalidMFAChallenge) {
+			t.Fatalf("expected used challenge to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("正常系: リカバリーコードでログインできる", func(t *testing.T) {
+		if _, _, _, err := svc.CompleteMFALogin(challenge(), recoveryCodes[1], models.SessionDevice{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
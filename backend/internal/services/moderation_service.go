@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+var (
+	// ErrCannotReportSelf は自分自身または自分の投稿を通報しようとした場合のエラー
+	ErrCannotReportSelf = errors.New("自分自身や自分の投稿は通報できません")
+	// ErrCannotSuspendSelf は自分自身を利用停止にしようとした場合のエラー
+	ErrCannotSuspendSelf = errors.New("自分自身は利用停止にできません")
+	// ErrInvalidSuspensionEnd は利用停止の終了日時が過去の場合のエラー
+	ErrInvalidSuspensionEnd = errors.New("利用停止の終了日時が過去です")
+)
+
+// defaultModerationListLimit はモデレーションキュー・操作の記録の取得件数の既定値
+const defaultModerationListLimit = 20
+
+// ModerationService は通報の受付と管理者による対応を扱う
+type ModerationService struct {
+	moderationRepo repositories.ModerationRepository
+	postRepo       repositories.PostRepository
+	userRepo       repositories.UserRepository
+	// revocations は利用停止・解除をアクセストークンの確認にすぐ反映させるため、キャッシュしたユーザーの状態を破棄する
+	revocations *TokenRevocationStore
+	now         func() time.Time
+}
+
+// NewModerationService は新しい ModerationService を作成する
+func NewModerationService(moderationRepo repositories.ModerationRepository, postRepo repositories.PostRepository, userRepo repositories.UserRepository, revocations *TokenRevocationStore) *ModerationService {
+	return &ModerationService{
+		moderationRepo: moderationRepo,
+		postRepo:       postRepo,
+		userRepo:       userRepo,
+		revocations:    revocations,
+		now:            time.Now,
+	}
+}
+
+// ReportPost は投稿を通報する（通報対象のユーザーは投稿者とする）
+// 投稿が存在しないか非表示の場合は repositories.ErrPostNotFound を返す
+func (s *ModerationService) ReportPost(reporterID, postID int, input *models.CreateReportInput) (*models.Report, error) {
+	post, err := s.postRepo.GetByID(postID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID == reporterID {
+		return nil, ErrCannotReportSelf
+	}
+	report := &models.Report{
+		ReporterID:   reporterID,
+		TargetType:   models.ReportTargetPost,
+		PostID:       &post.ID,
+		TargetUserID: post.UserID,
+		Reason:       input.Reason,
+		Comment:      input.Comment,
+	}
+	if err := s.moderationRepo.CreateReport(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ReportUser はユーザーを通報する
+// ユーザーが存在しない場合は repositories.ErrUserNotFound を返す
+func (s *ModerationService) ReportUser(reporterID, userID int, input *models.CreateReportInput) (*models.Report, error) {
+	if reporterID == userID {
+		return nil, ErrCannotReportSelf
+	}
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+	report := &models.Report{
+		ReporterID:   reporterID,
+		TargetType:   models.ReportTargetUser,
+		TargetUserID: userID,
+		Reason:       input.Reason,
+		Comment:      input.Comment,
+	}
+	if err := s.moderationRepo.CreateReport(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListReports はモデレーションキューを古い順に返す（状態を省略した場合は未対応の通報）
+func (s *ModerationService) ListReports(query models.ReportListQuery) (*models.ReportsResponse, error) {
+	status := query.Status
+	if status == "" {
+		status = models.ReportStatusOpen
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultModerationListLimit
+	}
+	reports, total, err := s.moderationRepo.ListReports(status, limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ReportsResponse{
+		Reports: reports,
+		Total:   total,
+		Limit:   limit,
+		Offset:  query.Offset,
+	}, nil
+}
+
+// ResolveReport は通報を対応し、操作を記録する
+// suspend_user 以外では利用停止の終了日時を無視する
+func (s *ModerationService) ResolveReport(moderatorID, reportID int, input *models.ResolveReportInput) (*models.Report, error) {
+	var suspendUntil *time.Time
+	if input.Action == models.ModerationActionSuspendUser {
+		if err := s.validateSuspensionEnd(input.SuspendUntil); err != nil {
+			return nil, err
+		}
+		suspendUntil = input.SuspendUntil
+	}
+	report, err := s.moderationRepo.ResolveReport(reportID, moderatorID, input.Action, input.Note, suspendUntil)
+	if err != nil {
+		return nil, err
+	}
+	if input.Action == models.ModerationActionSuspendUser {
+		s.revocations.ForgetUser(report.TargetUserID)
+	}
+	logging.L.Info("report resolved by moderator",
+		"service", "ModerationService",
+		"method", "ResolveReport",
+		"report_id", reportID,
+		"moderator_id", moderatorID,
+		"action", input.Action)
+	return report, nil
+}
+
+// SuspendUser は通報によらずユーザーを利用停止にする
+// 利用停止中のユーザーはログイン・トークン更新ができず、発行済みのアクセストークンもすぐに拒否される
+func (s *ModerationService) SuspendUser(moderatorID, userID int, input *models.SuspendUserInput) error {
+	if moderatorID == userID {
+		return ErrCannotSuspendSelf
+	}
+	if err := s.validateSuspensionEnd(input.Until); err != nil {
+		return err
+	}
+	if err := s.moderationRepo.SuspendUser(moderatorID, userID, strings.TrimSpace(input.Reason), input.Until); err != nil {
+		return err
+	}
+	s.revocations.ForgetUser(userID)
+	return nil
+}
+
+// UnsuspendUser はユーザーの利用停止を解除する（利用停止前に発行したトークンは無効のまま）
+func (s *ModerationService) UnsuspendUser(moderatorID, userID int) error {
+	if err := s.moderationRepo.UnsuspendUser(moderatorID, userID); err != nil {
+		return err
+	}
+	s.revocations.ForgetUser(userID)
+	return nil
+}
+
+// validateSuspensionEnd は利用停止の終了日時が未指定または未来であることを確認する
+func (s *ModerationService) validateSuspensionEnd(until *time.Time) error {
+	if until != nil && !until.After(s.now()) {
+		return ErrInvalidSuspensionEnd
+	}
+	return nil
+}
+
+// ListActions はモデレーション操作の記録を新しい順に返す
+func (s *ModerationService) ListActions(query models.ModerationActionListQuery) (*models.ModerationActionsResponse, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultModerationListLimit
+	}
+	actions, total, err := s.moderationRepo.ListActions(limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ModerationActionsResponse{
+		Actions: actions,
+		Total:   total,
+		Limit:   limit,
+		Offset:  query.Offset,
+	}, nil
+}
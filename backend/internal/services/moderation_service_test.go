@@ -0,0 +1,176 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// recordingModerationRepo は作成された通報と一覧取得・利用停止の引数を記録するモック
+type recordingModerationRepo struct {
+	created         []models.Report
+	gotStatus       string
+	gotLimit        int
+	gotSuspendUntil *time.Time
+	gotReason       string
+}
+
+func (m *recordingModerationRepo) CreateReport(report *models.Report) error {
+	report.ID = len(m.created) + 1
+	report.Status = models.ReportStatusOpen
+	m.created = append(m.created, *report)
+	return nil
+}
+
+func (m *recordingModerationRepo) GetReport(id int) (*models.Report, error) {
+	return nil, repositories.ErrReportNotFound
+}
+
+func (m *recordingModerationRepo) ListReports(status string, limit, offset int) ([]models.Report, int, error) {
+	m.gotStatus = status
+	m.gotLimit = limit
+	return []models.Report{}, 0, nil
+}
+
+func (m *recordingModerationRepo) ResolveReport(reportID, moderatorID int, action, note string, suspendUntil *time.Time) (*models.Report, error) {
+	m.gotSuspendUntil = suspendUntil
+	return &models.Report{ID: reportID, Status: models.ReportStatusDismissed, Resolution: action, ResolvedBy: &moderatorID}, nil
+}
+
+func (m *recordingModerationRepo) SuspendUser(moderatorID, userID int, reason string, until *time.Time) error {
+	m.gotReason = reason
+	m.gotSuspendUntil = until
+	return nil
+}
+
+func (m *recordingModerationRepo) UnsuspendUser(moderatorID, userID int) error {
+	return nil
+}
+
+func (m *recordingModerationRepo) ListActions(limit, offset int) ([]models.ModerationAction, int, error) {
+	m.gotLimit = limit
+	return []models.ModerationAction{}, 0, nil
+}
+
+func TestReportPost_TargetsAuthor(t *testing.T) {
+	repo := &recordingModerationRepo{}
+	svc := NewModerationService(repo, &ownedPostRepo{ownerID: 1}, &mockUserRepoForPost{}, NewTokenRevocationStore(newMemoryTokenRevocationRepo()))
+
+	report, err := svc.ReportPost(2, 10, &models.CreateReportInput{Reason: models.ReportReasonSpam})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.TargetType != models.ReportTargetPost || report.PostID == nil || *report.PostID != 10 || report.TargetUserID != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if _, err := svc.ReportPost(1, 10, &models.CreateReportInput{Reason: models.ReportReasonSpam}); !errors.Is(err, ErrCannotReportSelf) {
+		t.Fatalf("expected ErrCannotReportSelf for own post, got %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected 1 report to be created, got %d", len(repo.created))
+	}
+}
+
+func TestReportUser_Validation(t *testing.T) {
+	repo := &recordingModerationRepo{}
+
+	svc := NewModerationService(repo, &mockPostRepo{}, &mockUserRepoForPost{}, NewTokenRevocationStore(newMemoryTokenRevocationRepo()))
+	if _, err := svc.ReportUser(2, 2, &models.CreateReportInput{Reason: models.ReportReasonOther}); !errors.Is(err, ErrCannotReportSelf) {
+		t.Fatalf("expected ErrCannotReportSelf, got %v", err)
+	}
+	report, err := svc.ReportUser(2, 1, &models.CreateReportInput{Reason: models.ReportReasonImpersonation})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.TargetType != models.ReportTargetUser || report.PostID != nil || report.TargetUserID != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	missing := NewModerationService(repo, &mockPostRepo{}, &mockUserRepoMissing{}, NewTokenRevocationStore(newMemoryTokenRevocationRepo()))
+	if _, err := missing.ReportUser(2, 99, &models.CreateReportInput{Reason: models.ReportReasonSpam}); err == nil {
+		t.Fatalf("expected error for missing user")
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected 1 report to be created, got %d", len(repo.created))
+	}
+}
+
+func TestListReports_DefaultsToOpenQueue(t *testing.T) {
+	repo := &recordingModerationRepo{}
+	svc := NewModerationService(repo, &mockPostRepo{}, &mockUserRepoForPost{}, NewTokenRevocationStore(newMemoryTokenRevocationRepo()))
+
+	res, err := svc.ListReports(models.ReportListQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.gotStatus != models.ReportStatusOpen || repo.gotLimit != defaultModerationListLimit || res.Limit != defaultModerationListLimit {
+		t.Fatalf("unexpected defaults: status=%q limit=%d", repo.gotStatus, repo.gotLimit)
+	}
+}
+
+func TestResolveReport_SuspendUntil(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &recordingModerationRepo{}
+	svc := NewModerationService(repo, &mockPostRepo{}, &mockUserRepoForPost{}, NewTokenRevocationStore(newMemoryTokenRevocationRepo()))
+	svc.now = func() time.Time { return now }
+
+	past := now.Add(-time.Hour)
+	if _, err := svc.ResolveReport(1, 3, &models.ResolveReportInput{Action: models.ModerationActionSuspendUser, SuspendUntil: &past}); !errors.Is(err, ErrInvalidSuspensionEnd) {
+		t.Fatalf("expected ErrInvalidSuspensionEnd, got %v", err)
+	}
+
+	future := now.Add(24 * time.Hour)
+	if _, err := svc.ResolveReport(1, 3, &models.ResolveReportInput{Action: models.ModerationActionSuspendUser, SuspendUntil: &future}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.gotSuspendUntil == nil || !repo.gotSuspendUntil.Equal(future) {
+		t.Fatalf("expected suspend until %v, got %v", future, repo.gotSuspendUntil)
+	}
+
+	// suspend_user 以外では終了日時を無視する
+	if _, err := svc.ResolveReport(1, 3, &models.ResolveReportInput{Action: models.ModerationActionDismiss, SuspendUntil: &past}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.gotSuspendUntil != nil {
+		t.Fatalf("expected suspend until to be ignored, got %v", repo.gotSuspendUntil)
+	}
+}
+
+func TestSuspendUser_Validation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &recordingModerationRepo{}
+	svc := NewModerationService(repo, &mockPostRepo{}, &mockUserRepoForPost{}, NewTokenRevocationStore(newMemoryTokenRevocationRepo()))
+	svc.now = func() time.Time { return now }
+
+	if err := svc.SuspendUser(1, 1, &models.SuspendUserInput{Reason: "spam"}); !errors.Is(err, ErrCannotSuspendSelf) {
+		t.Fatalf("expected ErrCannotSuspendSelf, got %v", err)
+	}
+	past := now.Add(-time.Minute)
+	if err := svc.SuspendUser(1, 2, &models.SuspendUserInput{Reason: "spam", Until: &past}); !errors.Is(err, ErrInvalidSuspensionEnd) {
+		t.Fatalf("expected ErrInvalidSuspensionEnd, got %v", err)
+	}
+	if err := svc.SuspendUser(1, 2, &models.SuspendUserInput{Reason: "  スパム行為のため  "}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.gotReason != "スパム行為のため" || repo.gotSuspendUntil != nil {
+		t.Fatalf("unexpected suspension: reason=%q until=%v", repo.gotReason, repo.gotSuspendUntil)
+	}
+}
+
+func TestSuspendUser_ForgetsCachedTokenUser(t *testing.T) {
+	store := NewTokenRevocationStore(newMemoryTokenRevocationRepo())
+	svc := NewModerationService(&recordingModerationRepo{}, &mockPostRepo{}, &mockUserRepoForPost{}, store)
+
+	_, generation, _ := store.cachedUser(2)
+	store.cacheUser(&models.User{ID: 2}, generation)
+	if err := svc.SuspendUser(1, 2, &models.SuspendUserInput{Reason: "spam"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, ok := store.cachedUser(2); ok {
+		t.Fatal("expected cached user state to be forgotten after suspension")
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+var (
+	// ErrInvalidMuteRule はミュートルールの内容が種類と一致しない、または有効期限が過去の場合のエラー
+	ErrInvalidMuteRule = errors.New("ミュートルールの内容が不正です")
+	// ErrTooManyMuteRules はミュートルールの登録数が上限に達している場合のエラー
+	ErrTooManyMuteRules = errors.New("ミュートルールの登録数が上限に達しています")
+)
+
+// maxMuteRulesPerUser はユーザーごとのミュートルールの登録数の上限
+const maxMuteRulesPerUser = 100
+
+// MuteRuleService はキーワード・フレーバーのミュートルールを扱う
+// ルールはタイムライン・検索の取得時に PostRepository が適用する
+type MuteRuleService struct {
+	muteRuleRepo repositories.MuteRuleRepository
+	flavorRepo   repositories.FlavorRepository
+	now          func() time.Time
+}
+
+// NewMuteRuleService は新しい MuteRuleService を作成する
+func NewMuteRuleService(muteRuleRepo repositories.MuteRuleRepository, flavorRepo repositories.FlavorRepository) *MuteRuleService {
+	return &MuteRuleService{
+		muteRuleRepo: muteRuleRepo,
+		flavorRepo:   flavorRepo,
+		now:          time.Now,
+	}
+}
+
+// ListRules は認証ユーザーのミュートルールを新しい順に返す（期限切れのルールを含む）
+func (s *MuteRuleService) ListRules(userID int) ([]models.MuteRule, error) {
+	return s.muteRuleRepo.ListByUserID(userID)
+}
+
+// CreateRule はミュートルールを作成する
+// 内容が不正な場合は ErrInvalidMuteRule、フレーバーが存在しない場合は repositories.ErrFlavorNotFound、
+// 登録数が上限に達している場合は ErrTooManyMuteRules を返す
+func (s *MuteRuleService) CreateRule(userID int, input *models.MuteRuleInput) (*models.MuteRule, error) {
+	rule, err := s.buildRule(input)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.muteRuleRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxMuteRulesPerUser {
+		return nil, ErrTooManyMuteRules
+	}
+	rule.UserID = userID
+	if err := s.muteRuleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule はミュートルールの内容を入力で置き換える
+// 他ユーザーのルールの場合は、存在を明かさないため repositories.ErrMuteRuleNotFound を返す
+func (s *MuteRuleService) UpdateRule(userID, ruleID int, input *models.MuteRuleInput) (*models.MuteRule, error) {
+	current, err := s.getOwnedRule(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := s.buildRule(input)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = current.ID
+	rule.UserID = current.UserID
+	rule.CreatedAt = current.CreatedAt
+	if err := s.muteRuleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule はミュートルールを削除する
+// 他ユーザーのルールの場合は repositories.ErrMuteRuleNotFound を返す
+func (s *MuteRuleService) DeleteRule(userID, ruleID int) error {
+	if _, err := s.getOwnedRule(userID, ruleID); err != nil {
+		return err
+	}
+	return s.muteRuleRepo.Delete(ruleID)
+}
+
+// buildRule は入力を検証・正規化してミュートルールを組み立てる
+// キーワードは前後の空白を除去し、種類と関係のない項目は無視する
+func (s *MuteRuleService) buildRule(input *models.MuteRuleInput) (*models.MuteRule, error) {
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(s.now()) {
+		return nil, ErrInvalidMuteRule
+	}
+	rule := &models.MuteRule{Kind: input.Kind, ExpiresAt: input.ExpiresAt}
+	switch input.Kind {
+	case models.MuteRuleKeyword:
+		rule.Keyword = strings.TrimSpace(input.Keyword)
+		if rule.Keyword == "" {
+			return nil, ErrInvalidMuteRule
+		}
+	case models.MuteRuleFlavor:
+		if input.FlavorID == nil {
+			return nil, ErrInvalidMuteRule
+		}
+		if _, err := s.flavorRepo.GetByID(*input.FlavorID); err != nil {
+			return nil, err
+		}
+		flavorID := *input.FlavorID
+		rule.FlavorID = &flavorID
+	default:
+		return nil, ErrInvalidMuteRule
+	}
+	return rule, nil
+}
+
+// getOwnedRule はミュートルールを取得し、userID が作成したものであることを確認する
+func (s *MuteRuleService) getOwnedRule(userID, ruleID int) (*models.MuteRule, error) {
+	rule, err := s.muteRuleRepo.GetByID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.UserID != userID {
+		return nil, repositories.ErrMuteRuleNotFound
+	}
+	return rule, nil
+}
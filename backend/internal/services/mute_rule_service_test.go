@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// memoryMuteRuleRepo はミュートルールをメモリ上に保持するモック
+type memoryMuteRuleRepo struct {
+	rules  map[int]*models.MuteRule
+	nextID int
+}
+
+func newMemoryMuteRuleRepo() *memoryMuteRuleRepo {
+	return &memoryMuteRuleRepo{rules: map[int]*models.MuteRule{}}
+}
+
+func (m *memoryMuteRuleRepo) Create(rule *models.MuteRule) error {
+	m.nextID++
+	rule.ID = m.nextID
+	r := *rule
+	m.rules[r.ID] = &r
+	return nil
+}
+
+func (m *memoryMuteRuleRepo) GetByID(id int) (*models.MuteRule, error) {
+	r, ok := m.rules[id]
+	if !ok {
+		return nil, repositories.ErrMuteRuleNotFound
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (m *memoryMuteRuleRepo) ListByUserID(userID int) ([]models.MuteRule, error) {
+	rules := []models.MuteRule{}
+	for _, r := range m.rules {
+		if r.UserID == userID {
+			rules = append(rules, *r)
+		}
+	}
+	return rules, nil
+}
+
+func (m *memoryMuteRuleRepo) Update(rule *models.MuteRule) error {
+	if _, ok := m.rules[rule.ID]; !ok {
+		return repositories.ErrMuteRuleNotFound
+	}
+	r := *rule
+	m.rules[r.ID] = &r
+	return nil
+}
+
+func (m *memoryMuteRuleRepo) Delete(id int) error {
+	if _, ok := m.rules[id]; !ok {
+		return repositories.ErrMuteRuleNotFound
+	}
+	delete(m.rules, id)
+	return nil
+}
+
+func TestMuteRuleService_CreateRule_Validation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	flavorID := 1
+	unknownFlavorID := 999
+
+	tests := []struct {
+		name    string
+		input   models.MuteRuleInput
+		wantErr error
+	}{
+		{"keyword", models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: " spoiler ", ExpiresAt: &future}, nil},
+		{"blank keyword", models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: "   "}, ErrInvalidMuteRule},
+		{"flavor", models.MuteRuleInput{Kind: models.MuteRuleFlavor, FlavorID: &flavorID}, nil},
+		{"flavor without id", models.MuteRuleInput{Kind: models.MuteRuleFlavor}, ErrInvalidMuteRule},
+		{"unknown flavor", models.MuteRuleInput{Kind: models.MuteRuleFlavor, FlavorID: &unknownFlavorID}, repositories.ErrFlavorNotFound},
+		{"past expiry", models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: "x", ExpiresAt: &past}, ErrInvalidMuteRule},
+		{"unknown kind", models.MuteRuleInput{Kind: "user", Keyword: "x"}, ErrInvalidMuteRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewMuteRuleService(newMemoryMuteRuleRepo(), &mockFlavorRepo{})
+			svc.now = func() time.Time { return now }
+			rule, err := svc.CreateRule(1, &tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && rule.UserID != 1 {
+				t.Fatalf("expected rule owned by user 1, got %+v", rule)
+			}
+		})
+	}
+}
+
+func TestMuteRuleService_CreateRule_NormalizesInput(t *testing.T) {
+	svc := NewMuteRuleService(newMemoryMuteRuleRepo(), &mockFlavorRepo{})
+	flavorID := 2
+
+	rule, err := svc.CreateRule(1, &models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: "  spoiler  ", FlavorID: &flavorID})
+	if err != nil {
+		t.Fatalf("CreateRule failed: %v", err)
+	}
+	// 種類と関係のない項目は保存しない
+	if rule.Keyword != "spoiler" || rule.FlavorID != nil {
+		t.Fatalf("expected trimmed keyword without flavor, got %+v", rule)
+	}
+
+	rule, err = svc.CreateRule(1, &models.MuteRuleInput{Kind: models.MuteRuleFlavor, Keyword: "ignored", FlavorID: &flavorID})
+	if err != nil {
+		t.Fatalf("CreateRule failed: %v", err)
+	}
+	if rule.Keyword != "" || rule.FlavorID == nil || *rule.FlavorID != flavorID {
+		t.Fatalf("expected flavor rule without keyword, got %+v", rule)
+	}
+}
+
+func TestMuteRuleService_CreateRule_Limit(t *testing.T) {
+	repo := newMemoryMuteRuleRepo()
+	svc := NewMuteRuleService(repo, &mockFlavorRepo{})
+	for i := 0; i < maxMuteRulesPerUser; i++ {
+		if err := repo.Create(&models.MuteRule{UserID: 1, Kind: models.MuteRuleKeyword, Keyword: "x"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	_, err := svc.CreateRule(1, &models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: "y"})
+	if !errors.Is(err, ErrTooManyMuteRules) {
+		t.Fatalf("expected ErrTooManyMuteRules, got %v", err)
+	}
+	// 上限はユーザーごとに数える
+	if _, err := svc.CreateRule(2, &models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: "y"}); err != nil {
+		t.Fatalf("expected other user to create rule, got %v", err)
+	}
+}
+
+func TestMuteRuleService_UpdateAndDelete_Ownership(t *testing.T) {
+	repo := newMemoryMuteRuleRepo()
+	svc := NewMuteRuleService(repo, &mockFlavorRepo{})
+	expiresAt := time.Now().Add(time.Hour)
+	rule, err := svc.CreateRule(1, &models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: "spoiler", ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("CreateRule failed: %v", err)
+	}
+
+	input := &models.MuteRuleInput{Kind: models.MuteRuleKeyword, Keyword: "apple"}
+	if _, err := svc.UpdateRule(2, rule.ID, input); !errors.Is(err, repositories.ErrMuteRuleNotFound) {
+		t.Fatalf("expected ErrMuteRuleNotFound for other user, got %v", err)
+	}
+	if err := svc.DeleteRule(2, rule.ID); !errors.Is(err, repositories.ErrMuteRuleNotFound) {
+		t.Fatalf("expected ErrMuteRuleNotFound for other user, got %v", err)
+	}
+
+	updated, err := svc.UpdateRule(1, rule.ID, input)
+	if err != nil {
+		t.Fatalf("UpdateRule failed: %v", err)
+	}
+	// 置き換えのため、省略した有効期限は無期限になる
+	if updated.ID != rule.ID || updated.Keyword != "apple" || updated.ExpiresAt != nil {
+		t.Fatalf("unexpected updated rule: %+v", updated)
+	}
+
+	if err := svc.DeleteRule(1, rule.ID); err != nil {
+		t.Fatalf("DeleteRule failed: %v", err)
+	}
+	if _, err := repo.GetByID(rule.ID); !errors.Is(err, repositories.ErrMuteRuleNotFound) {
+		t.Fatalf("expected rule to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+const (
+	// defaultNotificationLimit は通知一覧の取得件数の既定値
+	defaultNotificationLimit = 20
+	// deletedActorName は操作ユーザーが退会済みの場合に表示する名前
+	deletedActorName = "退会したユーザー"
+)
+
+// notificationType は通知の種類ごとの振る舞いを定義する
+type notificationType struct {
+	// grouped が true の場合、同じ投稿への未読通知を1件にまとめる
+	// まとめる種類の通知は作成時に grouped として記録し、未読のまとめ先通知の一意インデックス（idx_notifications_unread_group）の対象になる
+	grouped bool
+	// message は表示用メッセージを組み立てる（others はまとめられた他の操作ユーザーの人数）
+	message func(actorName string, others int) string
+}
+
+// notificationTypes は通知の種類ごとの定義
+// 新しい通知の種類を追加する場合は、models に定数を追加しここに定義を加える
+var notificationTypes = map[string]notificationType{
+	models.NotificationTypePostLiked: {
+		grouped: true,
+		message: func(actorName string, others int) string {
+			if others > 0 {
+				return fmt.Sprintf("%sさんと他%d人があなたの投稿にいいねしました", actorName, others)
+			}
+			return fmt.Sprintf("%sさんがあなたの投稿にいいねしました", actorName)
+		},
+	},
+}
+
+// Notifier はイベント発生時に通知を作成するインターフェース
+type Notifier interface {
+	Notify(event models.NotificationEvent) error
+}
+
+// NotificationService はアプリ内通知の作成と取得を扱う
+type NotificationService struct {
+	notificationRepo repositories.NotificationRepository
+	// streamPublisher は作成した通知を受信者のストリームに配信する
+	streamPublisher StreamPublisher
+}
+
+// NewNotificationService は新しい NotificationService を作成する
+func NewNotificationService(notificationRepo repositories.NotificationRepository, streamPublisher StreamPublisher) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		streamPublisher:  streamPublisher,
+	}
+}
+
+// Notify はイベントから通知を作成する
+// 自分自身の操作では通知しない。まとめる種類の通知は未読の同じ通知に加算する
+func (s *NotificationService) Notify(event models.NotificationEvent) error {
+	def, ok := notificationTypes[event.Type]
+	if !ok {
+		return fmt.Errorf("unknown notification type: %s", event.Type)
+	}
+	if event.UserID == event.ActorID {
+		return nil
+	}
+	var err error
+	if def.grouped {
+		err = s.notificationRepo.CreateOrGroup(event)
+	} else {
+		err = s.notificationRepo.Create(event)
+	}
+	if err != nil {
+		return err
+	}
+	s.publish(event)
+	return nil
+}
+
+// publish は作成した通知を受信者のストリームに配信する
+func (s *NotificationService) publish(event models.NotificationEvent) {
+	recipientID := event.UserID
+	s.streamPublisher.Publish(models.StreamEvent{
+		Type:   models.StreamEventNotification,
+		UserID: &recipientID,
+		Data: models.NotificationStreamData{
+			Type:    event.Type,
+			PostID:  event.PostID,
+			ActorID: event.ActorID,
+		},
+	})
+}
+
+// GetNotifications は指定ユーザーの通知を更新日時の新しい順に返す
+// limit が0の場合は既定の件数を返す
+func (s *NotificationService) GetNotifications(userID int, query models.NotificationListQuery) (*models.NotificationsResponse, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	notifications, total, err := s.notificationRepo.ListByUserID(userID, limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notifications {
+		notifications[i].Message = notificationMessage(&notifications[i])
+	}
+	return &models.NotificationsResponse{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+		Limit:         limit,
+		Offset:        query.Offset,
+	}, nil
+}
+
+// GetUnreadCount は指定ユーザーの未読通知数を返す
+func (s *NotificationService) GetUnreadCount(userID int) (int, error) {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+// MarkRead は指定ユーザーの通知を既読にする
+// 存在しない、または他ユーザーの通知の場合は repositories.ErrNotificationNotFound を返す
+func (s *NotificationService) MarkRead(userID, notificationID int) error {
+	return s.notificationRepo.MarkRead(userID, notificationID)
+}
+
+// MarkAllRead は指定ユーザーの未読通知をすべて既読にする
+func (s *NotificationService) MarkAllRead(userID int) error {
+	count, err := s.notificationRepo.MarkAllRead(userID)
+	if err != nil {
+		return err
+	}
+	logging.L.Debug("notifications marked as read", "service", "NotificationService", "method", "MarkAllRead", "user_id", userID, "count", count)
+	return nil
+}
+
+// notificationMessage は通知の表示用メッセージを組み立てる
+func notificationMessage(n *models.Notification) string {
+	def, ok := notificationTypes[n.Type]
+	if !ok {
+		return ""
+	}
+	actorName := deletedActorName
+	if n.Actor != nil {
+		actorName = n.Actor.DisplayName
+	}
+	return def.message(actorName, n.OthersCount)
+}
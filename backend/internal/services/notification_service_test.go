@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+)
+
+// recordingNotificationRepo は作成・まとめの呼び出しを記録するモック
+type recordingNotificationRepo struct {
+	created       []models.NotificationEvent
+	grouped       []models.NotificationEvent
+	notifications []models.Notification
+	gotLimit      int
+	gotOffset     int
+}
+
+func (m *recordingNotificationRepo) Create(event models.NotificationEvent) error {
+	m.created = append(m.created, event)
+	return nil
+}
+
+func (m *recordingNotificationRepo) CreateOrGroup(event models.NotificationEvent) error {
+	m.grouped = append(m.grouped, event)
+	return nil
+}
+
+func (m *recordingNotificationRepo) ListByUserID(userID, limit, offset int) ([]models.Notification, int, error) {
+	m.gotLimit = limit
+	m.gotOffset = offset
+	return m.notifications, len(m.notifications), nil
+}
+
+func (m *recordingNotificationRepo) CountUnread(userID int) (int, error) {
+	return 1, nil
+}
+
+func (m *recordingNotificationRepo) MarkRead(userID, notificationID int) error {
+	return nil
+}
+
+func (m *recordingNotificationRepo) MarkAllRead(userID int) (int, error) {
+	return 0, nil
+}
+
+// recordingNotifier は Notify の呼び出しを記録するモック
+type recordingNotifier struct {
+	events []models.NotificationEvent
+	err    error
+}
+
+func (m *recordingNotifier) Notify(event models.NotificationEvent) error {
+	m.events = append(m.events, event)
+	return m.err
+}
+
+// ownedPostRepo は指定した投稿者の投稿を返すモック
+type ownedPostRepo struct {
+	mockPostRepo
+	ownerID int
+}
+
+func (m *ownedPostRepo) GetByID(id int, userID *int) (*models.Post, error) {
+	return &models.Post{ID: id, UserID: m.ownerID}, nil
+}
+
+func TestNotify_GroupsLikes(t *testing.T) {
+	repo := &recordingNotificationRepo{}
+	svc := NewNotificationService(repo, nopStreamPublisher{})
+	postID := 10
+
+	if err := svc.Notify(models.NotificationEvent{UserID: 1, ActorID: 2, Type: models.NotificationTypePostLiked, PostID: &postID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.grouped) != 1 || len(repo.created) != 0 {
+		t.Fatalf("expected like to be grouped, got grouped=%d created=%d", len(repo.grouped), len(repo.created))
+	}
+}
+
+func TestNotify_SkipsSelfAndRejectsUnknownType(t *testing.T) {
+	repo := &recordingNotificationRepo{}
+	svc := NewNotificationService(repo, nopStreamPublisher{})
+	postID := 10
+
+	if err := svc.Notify(models.NotificationEvent{UserID: 1, ActorID: 1, Type: models.NotificationTypePostLiked, PostID: &postID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.grouped) != 0 {
+		t.Fatalf("expected no notification for own action")
+	}
+	if err := svc.Notify(models.NotificationEvent{UserID: 1, ActorID: 2, Type: "unknown"}); err == nil {
+		t.Fatalf("expected error for unknown notification type")
+	}
+}
+
+func TestGetNotifications_BuildsMessages(t *testing.T) {
+	repo := &recordingNotificationRepo{notifications: []models.Notification{
+		{ID: 1, Type: models.NotificationTypePostLiked, Actor: &models.NotificationActor{ID: 2, DisplayName: "太郎"}, OthersCount: 4},
+		{ID: 2, Type: models.NotificationTypePostLiked, Actor: &models.NotificationActor{ID: 3, DisplayName: "花子"}},
+		{ID: 3, Type: models.NotificationTypePostLiked},
+	}}
+	svc := NewNotificationService(repo, nopStreamPublisher{})
+
+	response, err := svc.GetNotifications(1, models.NotificationListQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.gotLimit != defaultNotificationLimit || response.Limit != defaultNotificationLimit {
+		t.Fatalf("expected default limit, got repo=%d response=%d", repo.gotLimit, response.Limit)
+	}
+	want := []string{
+		"太郎さんと他4人があなたの投稿にいいねしました",
+		"花子さんがあなたの投稿にいいねしました",
+		"退会したユーザーさんがあなたの投稿にいいねしました",
+	}
+	for i, msg := range want {
+		if response.Notifications[i].Message != msg {
+			t.Fatalf("notification %d: expected %q, got %q", i, msg, response.Notifications[i].Message)
+		}
+	}
+	if response.Total != 3 || response.UnreadCount != 1 {
+		t.Fatalf("unexpected counts: %+v", response)
+	}
+}
+
+func TestLikePost_NotifiesPostOwner(t *testing.T) {
+	notifier := &recordingNotifier{}
+	postSvc := NewPostService(&ownedPostRepo{ownerID: 5}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{}, nopStatsInvalidator{}, notifier, nopStreamPublisher{})
+
+	if _, err := postSvc.LikePost(2, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.events))
+	}
+	event := notifier.events[0]
+	if event.UserID != 5 || event.ActorID != 2 || event.Type != models.NotificationTypePostLiked || event.PostID == nil || *event.PostID != 10 {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+}
+
+func TestLikePost_NotifyFailureDoesNotFailLike(t *testing.T) {
+	notifier := &recordingNotifier{err: errors.New("db error")}
+	postSvc := NewPostService(&ownedPostRepo{ownerID: 5}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{}, nopStatsInvalidator{}, notifier, nopStreamPublisher{})
+
+	if _, err := postSvc.LikePost(2, 10); err != nil {
+		t.Fatalf("expected like to succeed even if notification fails, got %v", err)
+	}
+}
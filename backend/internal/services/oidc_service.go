@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/logging"
+	"go-shisha-backend/pkg/oidc"
+)
+
+// OpenID Connect のログインのセンチネルエラー
+var (
+	// ErrOIDCProviderNotFound は設定されていないプロバイダーが指定された場合のエラー
+	ErrOIDCProviderNotFound = errors.New("oidc provider not found")
+	// ErrInvalidOIDCState は state トークンが不正・有効期限切れ・使用済みか、プロバイダーが返した state と一致しない場合のエラー
+	ErrInvalidOIDCState = errors.New("invalid or expired oidc state")
+	// ErrOIDCLoginFailed は認可コードを交換できない、または ID トークンを検証できない場合のエラー
+	ErrOIDCLoginFailed = errors.New("oidc login failed")
+	// ErrOIDCEmailUnverified はプロバイダーから確認済みのメールアドレスを取得できず、ユーザーを作成・紐付けできない場合のエラー
+	ErrOIDCEmailUnverified = errors.New("oidc email is not verified")
+	// ErrOIDCAccountNotVerified は同じメールアドレスの既存のユーザーがメールアドレスを確認しておらず、紐付けできない場合のエラー
+	// 他人のメールアドレスで先に登録しておき、本人がプロバイダーでログインしたアカウントを乗っ取る攻撃を防ぐ
+	ErrOIDCAccountNotVerified = errors.New("existing account email is not verified")
+)
+
+// federatedSessionStarter はプロバイダーで本人確認したユーザーのログインのセッションを開始する（AuthService が実装する）
+type federatedSessionStarter interface {
+	LoginFederatedUser(userID int, device models.SessionDevice) (*models.User, string, string, error)
+}
+
+// OIDCService は OpenID Connect のプロバイダー（Google・LINE など）によるログインを扱う
+// プロバイダーのアカウントは確認済みのメールアドレスで既存のユーザーに紐付け、該当するユーザーがいない場合は作成する
+type OIDCService struct {
+	userRepo     repositories.AuthUserRepository
+	identityRepo repositories.UserIdentityRepository
+	sessions     federatedSessionStarter
+	providers    map[string]*oidc.Provider
+	names        []string
+	// revocations は使用済みの state トークンを記録する
+	revocations *TokenRevocationStore
+}
+
+// NewOIDCService は新しい OIDCService を作成する（プロバイダーは providers の順に一覧に並ぶ）
+func NewOIDCService(userRepo repositories.AuthUserRepository, identityRepo repositories.UserIdentityRepository, sessions federatedSessionStarter, providers []*oidc.Provider, revocations *TokenRevocationStore) *OIDCService {
+	s := &OIDCService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		sessions:     sessions,
+		providers:    make(map[string]*oidc.Provider, len(providers)),
+		names:        []string{},
+		revocations:  revocations,
+	}
+	for _, provider := range providers {
+		s.providers[provider.Name()] = provider
+		s.names = append(s.names, provider.Name())
+	}
+	return s
+}
+
+// Providers はログインに使えるプロバイダーの識別子を返す
+func (s *OIDCService) Providers() []string {
+	return s.names
+}
+
+// BeginLogin はプロバイダーによるログインを開始し、認可 URL と state トークンを返す
+// state トークンには state・nonce・PKCE の code_verifier を含め、ログインを開始したブラウザーの Cookie に預ける
+func (s *OIDCService) BeginLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrOIDCProviderNotFound
+	}
+	var values [3]string
+	for i := range values {
+		value, err := oidc.RandomString()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate random value: %w", err)
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build authorization url: %w", err)
+	}
+	stateToken, err := auth.GenerateOIDCStateToken(providerName, state, nonce, codeVerifier)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate oidc state token: %w", err)
+	}
+	return authURL, stateToken, nil
+}
+
+// FinishLogin はプロバイダーが返した認可コードを交換し、ID トークンのアカウントのユーザーとしてログインする
+// state トークンが無効な場合は ErrInvalidOIDCState、交換・検証に失敗した場合は ErrOIDCLoginFailed、
+// ユーザーを特定できない場合は ErrOIDCEmailUnverified・ErrOIDCAccountNotVerified を返す
+// 2段階認証が有効なユーザーの場合はセッションを開始せず *MFAChallengeError を返す（CompleteMFALogin でログインを完了する）
+func (s *OIDCService) FinishLogin(ctx context.Context, providerName, stateToken string, input *models.OIDCCallbackInput, device models.SessionDevice) (*models.User, string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, "", "", ErrOIDCProviderNotFound
+	}
+	claims, err := auth.ValidateOIDCStateToken(stateToken)
+	if err != nil || claims.Provider != providerName || subtle.ConstantTimeCompare([]byte(claims.State), []byte(input.State)) != 1 {
+		logging.L.Warn("oidc state mismatch", "service", "OIDCService", "method", "FinishLogin", "provider", providerName)
+		return nil, "", "", ErrInvalidOIDCState
+	}
+	revoked, err := s.revocations.IsRevoked(claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to check oidc state revocation: %w", err)
+	}
+	if revoked {
+		return nil, "", "", ErrInvalidOIDCState
+	}
+
+	rawIDToken, err := provider.Exchange(ctx, input.Code, claims.CodeVerifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrTokenExchange) {
+			logging.L.Warn("oidc token exchange failed", "service", "OIDCService", "method", "FinishLogin", "provider", providerName, "error", err)
+			return nil, "", "", ErrOIDCLoginFailed
+		}
+		return nil, "", "", fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	idToken, err := provider.VerifyIDToken(ctx, rawIDToken, claims.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidIDToken) {
+			logging.L.Warn("oidc id token verification failed", "service", "OIDCService", "method", "FinishLogin", "provider", providerName, "error", err)
+			return nil, "", "", ErrOIDCLoginFailed
+		}
+		return nil, "", "", fmt.Errorf("failed to verify id token: %w", err)
+	}
+
+	userID, err := s.resolveUser(providerName, idToken)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if err := s.revocations.Revoke(claims.ID, userID, claims.ExpiresAt.Time); err != nil {
+		return nil, "", "", fmt.Errorf("failed to revoke oidc state: %w", err)
+	}
+	return s.sessions.LoginFederatedUser(userID, device)
+}
+
+// resolveUser はプロバイダーのアカウントに紐付いたユーザーを返す
+// 紐付いていない場合は確認済みのメールアドレスで既存のユーザーに紐付け、該当するユーザーがいない場合は作成する
+func (s *OIDCService) resolveUser(providerName string, idToken *oidc.IDToken) (int, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(providerName, idToken.Subject)
+	if err == nil {
+		return identity.UserID, nil
+	}
+	if !errors.Is(err, repositories.ErrIdentityNotFound) {
+		return 0, fmt.Errorf("failed to get user identity: %w", err)
+	}
+	if idToken.Email == "" || !idToken.EmailVerified {
+		logging.L.Warn("oidc email is not verified", "service", "OIDCService", "method", "resolveUser", "provider", providerName)
+		return 0, ErrOIDCEmailUnverified
+	}
+
+	user, err := s.userRepo.GetByEmail(idToken.Email)
+	switch {
+	case err == nil:
+		if user.EmailVerifiedAt == nil {
+			logging.L.Warn("refused to link oidc identity to unverified account", "service", "OIDCService", "method", "resolveUser", "provider", providerName, "user_id", user.ID)
+			return 0, ErrOIDCAccountNotVerified
+		}
+	case errors.Is(err, repositories.ErrUserNotFound):
+		if user, err = s.createUser(idToken); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := s.identityRepo.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  idToken.Subject,
+		Email:    idToken.Email,
+	}); err != nil {
+		// 同じアカウントの同時のログインで先に紐付いた場合は、そのユーザーとしてログインする
+		if errors.Is(err, repositories.ErrIdentityAlreadyLinked) {
+			identity, getErr := s.identityRepo.GetByProviderSubject(providerName, idToken.Subject)
+			if getErr != nil {
+				return 0, fmt.Errorf("failed to get user identity: %w", getErr)
+			}
+			return identity.UserID, nil
+		}
+		return 0, fmt.Errorf("failed to link user identity: %w", err)
+	}
+	logging.L.Info("oidc identity linked", "service", "OIDCService", "method", "resolveUser", "provider", providerName, "user_id", user.ID)
+	return user.ID, nil
+}
+
+// createUser はプロバイダーのアカウントのユーザーを作成する
+// パスワードは推測できない値にし、パスワードでログインする場合はパスワードの再設定で設定してもらう
+func (s *OIDCService) createUser(idToken *oidc.IDToken) (*models.User, error) {
+	user := &models.User{
+		Email:       idToken.Email,
+		DisplayName: oidcDisplayName(idToken),
+		Role:        models.RoleUser,
+	}
+	password, err := oidc.RandomString()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	if err := user.HashPassword(password); err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	// プロバイダーが確認したメールアドレスのため、確認メールは送らない
+	if err := s.userRepo.MarkEmailVerified(user.ID, user.Email); err != nil {
+		return nil, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	logging.L.Info("user registered with oidc", "service", "OIDCService", "method", "createUser", "user_id", user.ID)
+	return user, nil
+}
+
+// oidcDisplayName は ID トークンの名前（ない場合はメールアドレスの @ より前）から表示名を作る
+func oidcDisplayName(idToken *oidc.IDToken) string {
+	name := strings.TrimSpace(idToken.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(idToken.Email, "@")
+	}
+	return name
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/oidc"
+	"go-shisha-backend/pkg/oidc/oidctest"
+	"go-shisha-backend/pkg/totp"
+)
+
+// memoryIdentityRepo は UserIdentityRepository のメモリー実装
+type memoryIdentityRepo struct {
+	identities []models.UserIdentity
+}
+
+func (r *memoryIdentityRepo) GetByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	for i := range r.identities {
+		if r.identities[i].Provider == provider && r.identities[i].Subject == subject {
+			identity := r.identities[i]
+			return &identity, nil
+		}
+	}
+	return nil, repositories.ErrIdentityNotFound
+}
+
+func (r *memoryIdentityRepo) Create(identity *models.UserIdentity) error {
+	if _, err := r.GetByProviderSubject(identity.Provider, identity.Subject); err == nil {
+		return repositories.ErrIdentityAlreadyLinked
+	}
+	identity.ID = int64(len(r.identities) + 1)
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+const testOIDCRedirectURL = "https://shisha.example/auth/oidc/mock"
+
+func newOIDCTestService(t *testing.T) (*OIDCService, *mockAuthUserRepo, *memoryIdentityRepo, *oidctest.Provider) {
+	t.Helper()
+	server := oidctest.NewProvider("client-id", "client-secret")
+	t.Cleanup(server.Close)
+	userRepo := newMockAuthUserRepo()
+	identityRepo := &memoryIdentityRepo{}
+	revocations := NewTokenRevocationStore(newMemoryTokenRevocationRepo())
+	authService := NewAuthService(userRepo, newMockRefreshTokenRepo(), revocations, nopVerificationSender{}, disabledMFAVerifier{}, nopLoginLimiter{})
+	provider := oidc.NewProvider(server.Config("mock", testOIDCRedirectURL), nil)
+	svc := NewOIDCService(userRepo, identityRepo, authService, []*oidc.Provider{provider}, revocations)
+	return svc, userRepo, identityRepo, server
+}
+
+// beginTestOIDCLogin はログインを開始してプロバイダーで同意し、state トークンとコールバックの入力を返す
+func beginTestOIDCLogin(t *testing.T, svc *OIDCService, server *oidctest.Provider, user oidctest.User) (string, *models.OIDCCallbackInput) {
+	t.Helper()
+	authURL, stateToken, err := svc.BeginLogin(context.Background(), "mock")
+	if err != nil {
+		t.Fatalf("BeginLogin failed: %v", err)
+	}
+	code, state, err := server.Authorize(authURL, user)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	return stateToken, &models.OIDCCallbackInput{Code: code, State: state}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: 初めてのログインでメールアドレス確認済みのユーザーを作成し、2回目は同じユーザーになる", func(t *testing.T) {
+		svc, userRepo, identityRepo, server := newOIDCTestService(t)
+		providerUser := oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true, Name: "New User"}
+
+		stateToken, input := beginTestOIDCLogin(t, svc, server, providerUser)
+		user, accessToken, refreshToken, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if accessToken == "" || refreshToken == "" {
+			t.Error("expected tokens")
+		}
+		created := userRepo.users["new@example.com"]
+		if created == nil || created.ID != user.ID || created.DisplayName != "New User" || created.EmailVerifiedAt == nil {
+			t.Fatalf("unexpected created user: %+v", created)
+		}
+		if len(identityRepo.identities) != 1 || identityRepo.identities[0].UserID != user.ID {
+			t.Fatalf("expected linked identity, got %+v", identityRepo.identities)
+		}
+
+		// プロバイダー側でメールアドレスが変わっても、紐付いたユーザーとしてログインする
+		providerUser.Email = "changed@example.com"
+		stateToken, input = beginTestOIDCLogin(t, svc, server, providerUser)
+		again, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if again.ID != user.ID || len(userRepo.users) != 1 {
+			t.Errorf("expected the same user, got %d (users=%d)", again.ID, len(userRepo.users))
+		}
+	})
+
+	t.Run("正常系: 名前がない場合はメールアドレスの @ より前を表示名にする", func(t *testing.T) {
+		svc, userRepo, _, server := newOIDCTestService(t)
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "noname@example.com", EmailVerified: true})
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := userRepo.users["noname@example.com"].DisplayName; got != "noname" {
+			t.Errorf("expected display name noname, got %q", got)
+		}
+	})
+
+	t.Run("正常系: メールアドレス確認済みの既存のユーザーに紐付ける", func(t *testing.T) {
+		svc, userRepo, identityRepo, server := newOIDCTestService(t)
+		existing := newMFATestUser(t, userRepo, "existing@example.com")
+		if err := userRepo.MarkEmailVerified(existing.ID, existing.Email); err != nil {
+			t.Fatalf("MarkEmailVerified failed: %v", err)
+		}
+
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "existing@example.com", EmailVerified: true})
+		user, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if user.ID != existing.ID || len(userRepo.users) != 1 || len(identityRepo.identities) != 1 {
+			t.Errorf("expected link to existing user %d, got %d", existing.ID, user.ID)
+		}
+	})
+
+	t.Run("異常系: 既存のユーザーがメールアドレスを確認していない場合は紐付けない", func(t *testing.T) {
+		svc, userRepo, identityRepo, server := newOIDCTestService(t)
+		newMFATestUser(t, userRepo, "unverified@example.com")
+
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "unverified@example.com", EmailVerified: true})
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); !errors.Is(err, ErrOIDCAccountNotVerified) {
+			t.Fatalf("expected ErrOIDCAccountNotVerified, got %v", err)
+		}
+		if len(identityRepo.identities) != 0 {
+			t.Errorf("expected no identity, got %+v", identityRepo.identities)
+		}
+	})
+
+	t.Run("異常系: プロバイダーのメールアドレスが未確認", func(t *testing.T) {
+		svc, userRepo, _, server := newOIDCTestService(t)
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: false})
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); !errors.Is(err, ErrOIDCEmailUnverified) {
+			t.Fatalf("expected ErrOIDCEmailUnverified, got %v", err)
+		}
+		if len(userRepo.users) != 0 {
+			t.Errorf("expected no user, got %d", len(userRepo.users))
+		}
+	})
+
+	t.Run("異常系: プロバイダーが返した state が一致しない", func(t *testing.T) {
+		svc, _, _, server := newOIDCTestService(t)
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+		input.State = "forged"
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); !errors.Is(err, ErrInvalidOIDCState) {
+			t.Errorf("expected ErrInvalidOIDCState, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 別のログインの state トークン", func(t *testing.T) {
+		svc, _, _, server := newOIDCTestService(t)
+		otherStateToken, _ := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+		_, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", otherStateToken, input, models.SessionDevice{}); !errors.Is(err, ErrInvalidOIDCState) {
+			t.Errorf("expected ErrInvalidOIDCState, got %v", err)
+		}
+	})
+
+	t.Run("異常系: state トークンは一度しか使えない", func(t *testing.T) {
+		svc, _, _, server := newOIDCTestService(t)
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); !errors.Is(err, ErrInvalidOIDCState) {
+			t.Errorf("expected ErrInvalidOIDCState, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 認可コードを交換できない", func(t *testing.T) {
+		svc, _, _, server := newOIDCTestService(t)
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+		input.Code = "unknown-code"
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); !errors.Is(err, ErrOIDCLoginFailed) {
+			t.Errorf("expected ErrOIDCLoginFailed, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 設定されていないプロバイダー", func(t *testing.T) {
+		svc, _, _, _ := newOIDCTestService(t)
+		if _, _, err := svc.BeginLogin(ctx, "unknown"); !errors.Is(err, ErrOIDCProviderNotFound) {
+			t.Errorf("expected ErrOIDCProviderNotFound, got %v", err)
+		}
+		if _, _, _, err := svc.FinishLogin(ctx, "unknown", "token", &models.OIDCCallbackInput{Code: "code", State: "state"}, models.SessionDevice{}); !errors.Is(err, ErrOIDCProviderNotFound) {
+			t.Errorf("expected ErrOIDCProviderNotFound, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 利用停止中のユーザー", func(t *testing.T) {
+		svc, userRepo, _, server := newOIDCTestService(t)
+		providerUser := oidctest.User{Subject: "sub-1", Email: "suspended@example.com", EmailVerified: true}
+		stateToken, input := beginTestOIDCLogin(t, svc, server, providerUser)
+		if _, _, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		userRepo.users["suspended@example.com"].SuspendedAt = &time.Time{}
+
+		stateToken, input = beginTestOIDCLogin(t, svc, server, providerUser)
+		_, accessToken, _, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{})
+		var suspension *auth.SuspensionError
+		if !errors.As(err, &suspension) || accessToken != "" {
+			t.Errorf("expected SuspensionError, got %v", err)
+		}
+	})
+}
+
+func TestOIDCLoginMFA(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	server := oidctest.NewProvider("client-id", "client-secret")
+	t.Cleanup(server.Close)
+	userRepo := newMockAuthUserRepo()
+	revocations := NewTokenRevocationStore(newMemoryTokenRevocationRepo())
+	mfaService := NewMFAService(userRepo, newMemoryMFARepo(), "Go Shisha")
+	authService := NewAuthService(userRepo, newMockRefreshTokenRepo(), revocations, nopVerificationSender{}, mfaService, nopLoginLimiter{})
+	provider := oidc.NewProvider(server.Config("mock", testOIDCRedirectURL), nil)
+	svc := NewOIDCService(userRepo, &memoryIdentityRepo{}, authService, []*oidc.Provider{provider}, revocations)
+
+	// 確認済みのメールアドレスで紐付く既存のユーザーが2段階認証を有効にしている
+	user := newMFATestUser(t, userRepo, "mfa-oidc@example.com")
+	if err := userRepo.MarkEmailVerified(user.ID, user.Email); err != nil {
+		t.Fatalf("MarkEmailVerified failed: %v", err)
+	}
+	secret, _ := enableTestTOTP(t, mfaService, user.ID, now.Add(-totp.Period))
+	mfaService.now = func() time.Time { return now }
+
+	t.Run("正常系: プロバイダーでのログインではトークンを発行せず、コードの入力でログインが完了する", func(t *testing.T) {
+		stateToken, input := beginTestOIDCLogin(t, svc, server, oidctest.User{Subject: "sub-1", Email: "mfa-oidc@example.com", EmailVerified: true})
+		_, accessToken, refreshToken, err := svc.FinishLogin(ctx, "mock", stateToken, input, models.SessionDevice{})
+		var challenge *MFAChallengeError
+		if !errors.As(err, &challenge) || challenge.ChallengeToken == "" {
+			t.Fatalf("expected MFAChallengeError, got %v", err)
+		}
+		if accessToken != "" || refreshToken != "" {
+			t.Fatal("expected no tokens before two-factor authentication")
+		}
+
+		code, _ := totp.Code(secret, now)
+		loggedIn, accessToken, _, err := authService.CompleteMFALogin(challenge.ChallengeToken, code, models.SessionDevice{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if loggedIn.ID != user.ID || accessToken == "" {
+			t.Fatal("expected tokens for the linked user")
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+const (
+	// アウトボックスは変更の直後に処理したいため、Webhook より短い間隔でポーリングする
+	defaultOutboxPollInterval = time.Second
+	// defaultOutboxLease は取り出したイベントを他のディスパッチャーが再配送しないよう確保する時間
+	defaultOutboxLease       = time.Minute
+	defaultOutboxBatchSize   = 50
+	defaultOutboxMaxAttempts = 10
+	// 再試行間隔は 5秒, 10秒, 20秒, ... と倍々に延ばし、最大1時間とする
+	defaultOutboxBaseBackoff = 5 * time.Second
+	defaultOutboxMaxBackoff  = time.Hour
+	// 処理済みのイベントは調査用に一定期間残し、定期的に削除する
+	defaultOutboxRetention       = 7 * 24 * time.Hour
+	defaultOutboxCleanupInterval = time.Hour
+)
+
+// OutboxDispatcher はアウトボックスから配送待ちのドメインイベントを取り出し、DomainEventBus の購読者に配送する
+// 購読者が失敗した場合は指数バックオフで再試行し、上限回数に達したら failed とする
+// 処理済みのイベントは retention を過ぎたら削除する
+type OutboxDispatcher struct {
+	outboxRepo repositories.OutboxRepository
+	bus        *DomainEventBus
+	now        func() time.Time
+
+	pollInterval time.Duration
+	lease        time.Duration
+	batchSize    int
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+
+	retention       time.Duration
+	cleanupInterval time.Duration
+}
+
+// NewOutboxDispatcher は新しい OutboxDispatcher を作成する
+func NewOutboxDispatcher(outboxRepo repositories.OutboxRepository, bus *DomainEventBus) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo:   outboxRepo,
+		bus:          bus,
+		now:          time.Now,
+		pollInterval: defaultOutboxPollInterval,
+		lease:        defaultOutboxLease,
+		batchSize:    defaultOutboxBatchSize,
+		maxAttempts:  defaultOutboxMaxAttempts,
+		baseBackoff:  defaultOutboxBaseBackoff,
+		maxBackoff:   defaultOutboxMaxBackoff,
+
+		retention:       defaultOutboxRetention,
+		cleanupInterval: defaultOutboxCleanupInterval,
+	}
+}
+
+// Run は ctx がキャンセルされるまでアウトボックスを定期的に処理し、処理済みのイベントを定期的に削除する
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(d.cleanupInterval)
+	defer cleanup.Stop()
+	logging.L.Info("outbox dispatcher started", "service", "OutboxDispatcher", "poll_interval", d.pollInterval.String())
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil {
+			logging.L.Error("failed to dispatch outbox events", "service", "OutboxDispatcher", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			logging.L.Info("outbox dispatcher stopped", "service", "OutboxDispatcher")
+			return
+		case <-ticker.C:
+		case <-cleanup.C:
+			if _, err := d.Cleanup(); err != nil {
+				logging.L.Error("failed to clean up outbox events", "service", "OutboxDispatcher", "error", err)
+			}
+		}
+	}
+}
+
+// Cleanup は retention より前に処理済みになったイベントを削除し、削除した件数を返す
+func (d *OutboxDispatcher) Cleanup() (int64, error) {
+	return d.outboxRepo.DeleteProcessedBefore(d.now().Add(-d.retention))
+}
+
+// DispatchDue は配送予定日時を過ぎたイベントを取り出して配送し、処理した件数を返す
+func (d *OutboxDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		events, err := d.outboxRepo.ClaimDueEvents(d.now(), d.lease, d.batchSize)
+		if err != nil {
+			return processed, err
+		}
+		if len(events) == 0 {
+			return processed, nil
+		}
+		for i := range events {
+			if ctx.Err() != nil {
+				// 取り出し済みのイベントはリース期限後に再度取り出される
+				return processed, nil
+			}
+			event := &events[i]
+			d.deliver(event)
+			if err := d.outboxRepo.RecordAttempt(event); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+		if len(events) < d.batchSize {
+			return processed, nil
+		}
+	}
+}
+
+// deliver はイベントを1回配送し、結果を event に反映する
+func (d *OutboxDispatcher) deliver(event *models.DomainEvent) {
+	now := d.now()
+	event.Attempts++
+
+	err := d.bus.Deliver(event)
+	if err == nil {
+		event.Status = models.OutboxEventProcessed
+		event.NextAttemptAt = nil
+		event.LastError = ""
+		event.ProcessedAt = &now
+		return
+	}
+
+	event.LastError = err.Error()
+	if event.Attempts >= d.maxAttempts {
+		event.Status = models.OutboxEventFailed
+		event.NextAttemptAt = nil
+		logging.L.Error("outbox event gave up",
+			"service", "OutboxDispatcher",
+			"event_id", event.ID,
+			"event_type", event.Type,
+			"attempts", event.Attempts,
+			"error", err)
+		return
+	}
+	next := now.Add(exponentialBackoff(d.baseBackoff, d.maxBackoff, event.Attempts))
+	event.Status = models.OutboxEventPending
+	event.NextAttemptAt = &next
+	logging.L.Warn("outbox event failed, will retry",
+		"service", "OutboxDispatcher",
+		"event_id", event.ID,
+		"event_type", event.Type,
+		"attempts", event.Attempts,
+		"next_attempt_at", next,
+		"error", err)
+}
+
+// exponentialBackoff は attempts 回目の失敗後の再試行までの待ち時間を返す（base から倍々に延ばし、limit で頭打ちにする）
+func exponentialBackoff(base, limit time.Duration, attempts int) time.Duration {
+	wait := base
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= limit {
+			return limit
+		}
+	}
+	return wait
+}
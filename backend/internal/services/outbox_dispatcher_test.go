@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// memoryOutboxRepo はアウトボックスをメモリ上に保持するモック
+type memoryOutboxRepo struct {
+	events []*models.DomainEvent
+}
+
+func (m *memoryOutboxRepo) add(eventType string) *models.DomainEvent {
+	zero := time.Time{}
+	event := &models.DomainEvent{ID: len(m.events) + 1, Type: eventType, Status: models.OutboxEventPending, NextAttemptAt: &zero}
+	m.events = append(m.events, event)
+	return event
+}
+
+func (m *memoryOutboxRepo) ClaimDueEvents(now time.Time, lease time.Duration, limit int) ([]models.DomainEvent, error) {
+	claimed := []models.DomainEvent{}
+	for _, e := range m.events {
+		if len(claimed) >= limit {
+			break
+		}
+		if e.Status != models.OutboxEventPending || e.NextAttemptAt == nil || e.NextAttemptAt.After(now) {
+			continue
+		}
+		leaseUntil := now.Add(lease)
+		e.NextAttemptAt = &leaseUntil
+		copied := *e
+		copied.CompletedHandlers = append([]string{}, e.CompletedHandlers...)
+		claimed = append(claimed, copied)
+	}
+	return claimed, nil
+}
+
+func (m *memoryOutboxRepo) RecordAttempt(event *models.DomainEvent) error {
+	for i, e := range m.events {
+		if e.ID == event.ID {
+			copied := *event
+			m.events[i] = &copied
+			return nil
+		}
+	}
+	return errors.New("event not found")
+}
+
+func (m *memoryOutboxRepo) DeleteProcessedBefore(before time.Time) (int64, error) {
+	kept := m.events[:0]
+	var deleted int64
+	for _, e := range m.events {
+		if e.Status == models.OutboxEventProcessed && e.ProcessedAt != nil && e.ProcessedAt.Before(before) {
+			deleted++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	m.events = kept
+	return deleted, nil
+}
+
+func TestOutboxDispatcher_ProcessesEvents(t *testing.T) {
+	repo := &memoryOutboxRepo{}
+	repo.add(models.DomainEventPostCreated)
+	repo.add(models.DomainEventPostDeleted)
+	bus := NewDomainEventBus()
+	received := 0
+	bus.Subscribe(models.DomainEventPostCreated, "counter", func(event *models.DomainEvent) error {
+		received++
+		return nil
+	})
+	dispatcher := NewOutboxDispatcher(repo, bus)
+
+	processed, err := dispatcher.DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 購読者のいないイベントも処理済みになる
+	if processed != 2 || received != 1 {
+		t.Fatalf("expected 2 processed and 1 received, got %d and %d", processed, received)
+	}
+	for _, e := range repo.events {
+		if e.Status != models.OutboxEventProcessed || e.ProcessedAt == nil || e.NextAttemptAt != nil {
+			t.Fatalf("unexpected event state: %+v", e)
+		}
+	}
+	if processed, _ := dispatcher.DispatchDue(context.Background()); processed != 0 {
+		t.Fatalf("expected nothing to dispatch, got %d", processed)
+	}
+}
+
+func TestOutboxDispatcher_RetriesOnlyFailedHandlers(t *testing.T) {
+	repo := &memoryOutboxRepo{}
+	repo.add(models.DomainEventLikeAdded)
+	bus := NewDomainEventBus()
+	okCalls, flakyCalls := 0, 0
+	bus.Subscribe(models.DomainEventLikeAdded, "ok", func(event *models.DomainEvent) error {
+		okCalls++
+		return nil
+	})
+	bus.Subscribe(models.DomainEventLikeAdded, "flaky", func(event *models.DomainEvent) error {
+		flakyCalls++
+		if flakyCalls == 1 {
+			return errors.New("temporary error")
+		}
+		return nil
+	})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dispatcher := NewOutboxDispatcher(repo, bus)
+	dispatcher.now = func() time.Time { return now }
+
+	dispatcher.DispatchDue(context.Background())
+	e := repo.events[0]
+	if e.Status != models.OutboxEventPending || e.Attempts != 1 || e.LastError == "" {
+		t.Fatalf("expected pending retry, got %+v", e)
+	}
+	if !e.NextAttemptAt.Equal(now.Add(defaultOutboxBaseBackoff)) {
+		t.Fatalf("expected retry after %s, got %v", defaultOutboxBaseBackoff, e.NextAttemptAt)
+	}
+
+	now = now.Add(defaultOutboxBaseBackoff)
+	dispatcher.DispatchDue(context.Background())
+	e = repo.events[0]
+	if e.Status != models.OutboxEventProcessed || e.Attempts != 2 {
+		t.Fatalf("expected processed after retry, got %+v", e)
+	}
+	// 成功済みの購読者は再試行で呼ばれない
+	if okCalls != 1 || flakyCalls != 2 {
+		t.Fatalf("expected ok=1 flaky=2, got ok=%d flaky=%d", okCalls, flakyCalls)
+	}
+}
+
+func TestOutboxDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := &memoryOutboxRepo{}
+	repo.add(models.DomainEventPostCreated)
+	bus := NewDomainEventBus()
+	bus.Subscribe(models.DomainEventPostCreated, "broken", func(event *models.DomainEvent) error {
+		return errors.New("permanent error")
+	})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dispatcher := NewOutboxDispatcher(repo, bus)
+	dispatcher.now = func() time.Time { return now }
+	dispatcher.maxAttempts = 2
+
+	dispatcher.DispatchDue(context.Background())
+	now = now.Add(time.Hour)
+	dispatcher.DispatchDue(context.Background())
+
+	e := repo.events[0]
+	if e.Status != models.OutboxEventFailed || e.Attempts != 2 || e.NextAttemptAt != nil {
+		t.Fatalf("expected failed event, got %+v", e)
+	}
+}
+
+func TestOutboxDispatcher_CleanupDeletesProcessedEventsAfterRetention(t *testing.T) {
+	repo := &memoryOutboxRepo{}
+	repo.add(models.DomainEventPostCreated)
+	repo.add(models.DomainEventPostDeleted)
+	bus := NewDomainEventBus()
+	bus.Subscribe(models.DomainEventPostDeleted, "broken", func(event *models.DomainEvent) error {
+		return errors.New("permanent error")
+	})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dispatcher := NewOutboxDispatcher(repo, bus)
+	dispatcher.now = func() time.Time { return now }
+	dispatcher.maxAttempts = 1
+	dispatcher.DispatchDue(context.Background())
+
+	now = now.Add(dispatcher.retention)
+	if deleted, err := dispatcher.Cleanup(); err != nil || deleted != 0 {
+		t.Fatalf("expected nothing to be deleted within retention, got %d (err=%v)", deleted, err)
+	}
+	// 保持期間を過ぎた処理済みのイベントだけを削除し、失敗したイベントは残す
+	now = now.Add(time.Second)
+	if deleted, err := dispatcher.Cleanup(); err != nil || deleted != 1 {
+		t.Fatalf("expected 1 processed event to be deleted, got %d (err=%v)", deleted, err)
+	}
+	if len(repo.events) != 1 || repo.events[0].Status != models.OutboxEventFailed {
+		t.Fatalf("expected only the failed event to remain, got %+v", repo.events)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	if got := exponentialBackoff(time.Second, time.Minute, 1); got != time.Second {
+		t.Fatalf("expected 1s, got %s", got)
+	}
+	if got := exponentialBackoff(time.Second, time.Minute, 4); got != 8*time.Second {
+		t.Fatalf("expected 8s, got %s", got)
+	}
+	if got := exponentialBackoff(time.Second, time.Minute, 50); got != time.Minute {
+		t.Fatalf("expected 1m, got %s", got)
+	}
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/logging"
+	"go-shisha-backend/pkg/webauthn"
+)
+
+// パスキーのセンチネルエラー
+var (
+	// ErrInvalidPasskeySession は登録・ログインの開始で返したトークンが不正・有効期限切れ・使用済みの場合のエラー
+	ErrInvalidPasskeySession = errors.New("invalid or expired passkey session")
+	// ErrPasskeyVerificationFailed は認証器のレスポンスを検証できない、または登録されていないパスキーの場合のエラー
+	ErrPasskeyVerificationFailed = errors.New("passkey verification failed")
+)
+
+// defaultPasskeyName は名前を指定せずに登録したパスキーの名前
+const defaultPasskeyName = "パスキー"
+
+// sessionStarter は本人確認したユーザーのログインのセッションを開始する（AuthService が実装する）
+type sessionStarter interface {
+	LoginAuthenticatedUser(userID int, device models.SessionDevice) (*models.User, string, string, error)
+}
+
+// PasskeyService はパスキー（WebAuthn）の登録・管理とパスワードを使わないログインを扱う
+// チャレンジはサーバーに保存せず、署名したトークンに含めてブラウザーに預ける
+type PasskeyService struct {
+	userRepo    repositories.AuthUserRepository
+	passkeyRepo repositories.PasskeyRepository
+	rp          *webauthn.RelyingParty
+	sessions    sessionStarter
+	// revocations は使用済みのトークンを記録する
+	revocations *TokenRevocationStore
+	now         func() time.Time
+}
+
+// NewPasskeyService は新しい PasskeyService を作成する
+func NewPasskeyService(userRepo repositories.AuthUserRepository, passkeyRepo repositories.PasskeyRepository, rp *webauthn.RelyingParty, sessions sessionStarter, revocations *TokenRevocationStore) *PasskeyService {
+	return &PasskeyService{
+		userRepo:    userRepo,
+		passkeyRepo: passkeyRepo,
+		rp:          rp,
+		sessions:    sessions,
+		revocations: revocations,
+		now:         time.Now,
+	}
+}
+
+// BeginRegistration はユーザーのパスキーの登録を開始し、navigator.credentials.create() のオプションを返す
+func (s *PasskeyService) BeginRegistration(userID int) (*models.PasskeyRegistrationOptionsResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	passkeys, err := s.passkeyRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		exclude = append(exclude, webauthn.CredentialDescriptor{Type: "public-key", ID: passkey.CredentialID, Transports: passkey.Transports})
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	token, err := auth.GeneratePasskeySessionToken(auth.PasskeyRegistrationAudience, int64(userID), challenge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate passkey session token: %w", err)
+	}
+	return &models.PasskeyRegistrationOptionsResponse{
+		SessionToken: token,
+		PublicKey: s.rp.CreationOptions(challenge, webauthn.UserEntity{
+			ID:          userHandle(userID),
+			Name:        user.Email,
+			DisplayName: user.DisplayName,
+		}, exclude),
+	}, nil
+}
+
+// FinishRegistration は認証器のレスポンスを検証してパスキーを登録する
+// トークンが無効な場合は ErrInvalidPasskeySession、レスポンスを検証できない場合は ErrPasskeyVerificationFailed、
+// 登録済みのパスキーの場合は repositories.ErrPasskeyAlreadyExists を返す
+func (s *PasskeyService) FinishRegistration(userID int, input *models.RegisterPasskeyInput) (*models.Passkey, error) {
+	claims, challenge, err := s.validateSession(auth.PasskeyRegistrationAudience, input.SessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.UserID != int64(userID) {
+		return nil, ErrInvalidPasskeySession
+	}
+	if err := s.consumeSession(claims, userID); err != nil {
+		return nil, err
+	}
+	credential, err := s.rp.VerifyRegistration(challenge, &input.Credential)
+	if err != nil {
+		logging.L.Warn("passkey registration verification failed", "service", "PasskeyService", "method", "FinishRegistration", "user_id", userID, "error", err)
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	passkey := &models.Passkey{
+		UserID:       userID,
+		CredentialID: credential.ID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    credential.SignCount,
+		Transports:   credential.Transports,
+		Name:         passkeyName(input.Name),
+	}
+	if passkey.Transports == nil {
+		passkey.Transports = []string{}
+	}
+	if err := s.passkeyRepo.Create(passkey); err != nil {
+		if errors.Is(err, repositories.ErrPasskeyAlreadyExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create passkey: %w", err)
+	}
+	logging.L.Info("passkey registered", "service", "PasskeyService", "method", "FinishRegistration", "user_id", userID, "passkey_id", passkey.ID)
+	return passkey, nil
+}
+
+// BeginLogin はパスキーによるログインを開始し、navigator.credentials.get() のオプションを返す
+func (s *PasskeyService) BeginLogin() (*models.PasskeyLoginOptionsResponse, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	token, err := auth.GeneratePasskeySessionToken(auth.PasskeyLoginAudience, 0, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate passkey session token: %w", err)
+	}
+	return &models.PasskeyLoginOptionsResponse{
+		SessionToken: token,
+		PublicKey:    s.rp.RequestOptions(challenge),
+	}, nil
+}
+
+// FinishLogin は認証器のレスポンスを検証し、パスキーの持ち主としてログインする
+// パスキーは所持と本人確認（生体認証・PIN）を兼ねるため、2段階認証のコードは求めない
+// トークンが無効な場合は ErrInvalidPasskeySession、登録されていないパスキー・検証できないレスポンスの場合は ErrPasskeyVerificationFailed を返す
+func (s *PasskeyService) FinishLogin(input *models.PasskeyLoginInput, device models.SessionDevice) (*models.User, string, string, error) {
+	claims, challenge, err := s.validateSession(auth.PasskeyLoginAudience, input.SessionToken)
+	if err != nil {
+		return nil, "", "", err
+	}
+	passkey, err := s.passkeyRepo.GetByCredentialID(input.Credential.RawID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPasskeyNotFound) {
+			logging.L.Warn("unknown passkey used for login", "service", "PasskeyService", "method", "FinishLogin")
+			return nil, "", "", ErrPasskeyVerificationFailed
+		}
+		return nil, "", "", fmt.Errorf("failed to get passkey: %w", err)
+	}
+	// ディスカバラブルクレデンシャルはユーザーハンドルを返すため、パスキーの持ち主と一致するか確認する
+	if handle := input.Credential.Response.UserHandle; len(handle) > 0 && !bytes.Equal(handle, userHandle(passkey.UserID)) {
+		logging.L.Warn("passkey user handle mismatch", "service", "PasskeyService", "method", "FinishLogin", "passkey_id", passkey.ID)
+		return nil, "", "", ErrPasskeyVerificationFailed
+	}
+	if err := s.consumeSession(claims, passkey.UserID); err != nil {
+		return nil, "", "", err
+	}
+
+	signCount, err := s.rp.VerifyAssertion(challenge, &webauthn.Credential{
+		ID:        passkey.CredentialID,
+		PublicKey: passkey.PublicKey,
+		SignCount: passkey.SignCount,
+	}, &input.Credential)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountRegression) {
+			logging.L.Warn("security event: passkey sign count regression",
+				"service", "PasskeyService", "method", "FinishLogin", "event", "passkey_sign_count_regression",
+				"user_id", passkey.UserID, "passkey_id", passkey.ID)
+		} else {
+			logging.L.Warn("passkey assertion verification failed", "service", "PasskeyService", "method", "FinishLogin", "passkey_id", passkey.ID, "error", err)
+		}
+		return nil, "", "", ErrPasskeyVerificationFailed
+	}
+	// 同じカウンターのレスポンスが並行して検証された場合は、先に更新した1つだけを受け付ける
+	if err := s.passkeyRepo.UpdateSignCount(passkey.ID, signCount, s.now()); err != nil {
+		if errors.Is(err, repositories.ErrPasskeySignCountStale) {
+			logging.L.Warn("security event: passkey sign count regression",
+				"service", "PasskeyService", "method", "FinishLogin", "event", "passkey_sign_count_regression",
+				"user_id", passkey.UserID, "passkey_id", passkey.ID)
+			return nil, "", "", ErrPasskeyVerificationFailed
+		}
+		return nil, "", "", fmt.Errorf("failed to update passkey: %w", err)
+	}
+	return s.sessions.LoginAuthenticatedUser(passkey.UserID, device)
+}
+
+// List はユーザーのパスキーを登録順に返す
+func (s *PasskeyService) List(userID int) ([]models.Passkey, error) {
+	passkeys, err := s.passkeyRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	return passkeys, nil
+}
+
+// Rename はユーザーのパスキーの名前を変更する（存在しない場合は repositories.ErrPasskeyNotFound）
+func (s *PasskeyService) Rename(userID int, id int64, name string) error {
+	return s.passkeyRepo.Rename(userID, id, passkeyName(name))
+}
+
+// Delete はユーザーのパスキーを削除する（存在しない場合は repositories.ErrPasskeyNotFound）
+func (s *PasskeyService) Delete(userID int, id int64) error {
+	return s.passkeyRepo.Delete(userID, id)
+}
+
+// validateSession は登録・ログインの開始で返したトークンの署名・有効期限を検証し、クレームとチャレンジを返す
+func (s *PasskeyService) validateSession(audience, token string) (*auth.PasskeySessionClaims, []byte, error) {
+	claims, challenge, err := auth.ValidatePasskeySessionToken(audience, token)
+	if err != nil {
+		return nil, nil, ErrInvalidPasskeySession
+	}
+	return claims, challenge, nil
+}
+
+// consumeSession は同じチャレンジのレスポンスを再び使えないよう、レスポンスの検証前にトークンを使用済みにする
+// 使用済みの場合は ErrInvalidPasskeySession を返す（並行する同じトークンのリクエストは1つだけが通る）
+func (s *PasskeyService) consumeSession(claims *auth.PasskeySessionClaims, userID int) error {
+	consumed, err := s.revocations.Consume(claims.ID, userID, claims.ExpiresAt.Time)
+	if err != nil {
+		return fmt.Errorf("failed to consume passkey session: %w", err)
+	}
+	if !consumed {
+		return ErrInvalidPasskeySession
+	}
+	return nil
+}
+
+// passkeyName は前後の空白を除いたパスキーの名前を返す（空の場合は defaultPasskeyName）
+func passkeyName(name string) string {
+	if name = strings.TrimSpace(name); name == "" {
+		return defaultPasskeyName
+	}
+	return name
+}
+
+// userHandle はパスキーに保存するユーザーハンドル（ユーザーIDの8バイトのビッグエンディアン）を返す
+func userHandle(userID int) []byte {
+	handle := make([]byte, 8)
+	binary.BigEndian.PutUint64(handle, uint64(userID))
+	return handle
+}
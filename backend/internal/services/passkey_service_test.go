@@ -0,0 +1,312 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/auth"
+	"go-shisha-backend/pkg/webauthn"
+	"go-shisha-backend/pkg/webauthn/webauthntest"
+)
+
+// memoryPasskeyRepo はテスト用のインメモリ PasskeyRepository
+type memoryPasskeyRepo struct {
+	passkeys []*models.Passkey
+	nextID   int64
+	// beforeUpdate は UpdateSignCount の直前に呼ばれる（並行するログインの再現に使う）
+	beforeUpdate func()
+}
+
+func (r *memoryPasskeyRepo) Create(passkey *models.Passkey) error {
+	for _, p := range r.passkeys {
+		if bytes.Equal(p.CredentialID, passkey.CredentialID) {
+			return repositories.ErrPasskeyAlreadyExists
+		}
+	}
+	r.nextID++
+	passkey.ID = r.nextID
+	copied := *passkey
+	r.passkeys = append(r.passkeys, &copied)
+	return nil
+}
+
+func (r *memoryPasskeyRepo) GetByCredentialID(credentialID []byte) (*models.Passkey, error) {
+	for _, p := range r.passkeys {
+		if bytes.Equal(p.CredentialID, credentialID) {
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrPasskeyNotFound
+}
+
+func (r *memoryPasskeyRepo) ListByUserID(userID int) ([]models.Passkey, error) {
+	passkeys := []models.Passkey{}
+	for _, p := range r.passkeys {
+		if p.UserID == userID {
+			passkeys = append(passkeys, *p)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *memoryPasskeyRepo) UpdateSignCount(id int64, signCount uint32, usedAt time.Time) error {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate()
+	}
+	for _, p := range r.passkeys {
+		if p.ID == id && (p.SignCount < signCount || p.SignCount == 0 && signCount == 0) {
+			p.SignCount = signCount
+			p.LastUsedAt = &usedAt
+			return nil
+		}
+	}
+	return repositories.ErrPasskeySignCountStale
+}
+
+func (r *memoryPasskeyRepo) Rename(userID int, id int64, name string) error {
+	for _, p := range r.passkeys {
+		if p.ID == id && p.UserID == userID {
+			p.Name = name
+			return nil
+		}
+	}
+	return repositories.ErrPasskeyNotFound
+}
+
+func (r *memoryPasskeyRepo) Delete(userID int, id int64) error {
+	for i, p := range r.passkeys {
+		if p.ID == id && p.UserID == userID {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrPasskeyNotFound
+}
+
+const testPasskeyOrigin = "https://shisha.example"
+
+// newPasskeyTestService は AuthService でセッションを開始する PasskeyService を作成する
+func newPasskeyTestService() (*PasskeyService, *mockAuthUserRepo, *memoryPasskeyRepo) {
+	userRepo := newMockAuthUserRepo()
+	passkeyRepo := &memoryPasskeyRepo{}
+	revocations := NewTokenRevocationStore(newMemoryTokenRevocationRepo())
+	authService := NewAuthService(userRepo, newMockRefreshTokenRepo(), revocations, nopVerificationSender{}, disabledMFAVerifier{}, nopLoginLimiter{})
+	rp := &webauthn.RelyingParty{ID: "shisha.example", Name: "Go Shisha", Origins: []string{testPasskeyOrigin}}
+	svc := NewPasskeyService(userRepo, passkeyRepo, rp, authService, revocations)
+	return svc, userRepo, passkeyRepo
+}
+
+// registerTestPasskey はソフトウェア認証器でユーザーのパスキーを登録する
+func registerTestPasskey(t *testing.T, svc *PasskeyService, authenticator *webauthntest.Authenticator, userID int, name string) *models.Passkey {
+	t.Helper()
+	options, err := svc.BeginRegistration(userID)
+	if err != nil {
+		t.Fatalf("BeginRegistration failed: %v", err)
+	}
+	credential, err := authenticator.Register(options.PublicKey)
+	if err != nil {
+		t.Fatalf("authenticator register failed: %v", err)
+	}
+	passkey, err := svc.FinishRegistration(userID, &models.RegisterPasskeyInput{SessionToken: options.SessionToken, Name: name, Credential: *credential})
+	if err != nil {
+		t.Fatalf("FinishRegistration failed: %v", err)
+	}
+	return passkey
+}
+
+// beginTestPasskeyLogin はログインを開始し、認証器のレスポンスを含むリクエストを返す
+func beginTestPasskeyLogin(t *testing.T, svc *PasskeyService, authenticator *webauthntest.Authenticator) *models.PasskeyLoginInput {
+	t.Helper()
+	options, err := svc.BeginLogin()
+	if err != nil {
+		t.Fatalf("BeginLogin failed: %v", err)
+	}
+	assertion, err := authenticator.Login(options.PublicKey)
+	if err != nil {
+		t.Fatalf("authenticator login failed: %v", err)
+	}
+	return &models.PasskeyLoginInput{SessionToken: options.SessionToken, Credential: *assertion}
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	svc, userRepo, passkeyRepo := newPasskeyTestService()
+	user := newMFATestUser(t, userRepo, "passkey@example.com")
+	other := newMFATestUser(t, userRepo, "other-passkey@example.com")
+	authenticator := webauthntest.New(testPasskeyOrigin)
+
+	t.Run("正常系: 登録したパスキーの名前と接続方法を保存する", func(t *testing.T) {
+		passkey := registerTestPasskey(t, svc, authenticator, user.ID, "  iPhone  ")
+		if passkey.Name != "iPhone" || passkey.UserID != user.ID || len(passkey.Transports) != 2 {
+			t.Fatalf("unexpected passkey: %+v", passkey)
+		}
+		if unnamed := registerTestPasskey(t, svc, webauthntest.New(testPasskeyOrigin), user.ID, ""); unnamed.Name != defaultPasskeyName {
+			t.Errorf("expected default name, got %q", unnamed.Name)
+		}
+	})
+
+	t.Run("正常系: 登録済みのパスキーを除外し、ユーザーハンドルはユーザーIDから作る", func(t *testing.T) {
+		options, err := svc.BeginRegistration(user.ID)
+		if err != nil {
+			t.Fatalf("BeginRegistration failed: %v", err)
+		}
+		if len(options.PublicKey.ExcludeCredentials) != 2 {
+			t.Errorf("expected 2 excluded credentials, got %d", len(options.PublicKey.ExcludeCredentials))
+		}
+		if !bytes.Equal(options.PublicKey.User.ID, userHandle(user.ID)) || options.PublicKey.User.Name != user.Email {
+			t.Errorf("unexpected user entity: %+v", options.PublicKey.User)
+		}
+	})
+
+	t.Run("異常系: セッショントークンは一度しか使えない", func(t *testing.T) {
+		options, _ := svc.BeginRegistration(user.ID)
+		credential, _ := webauthntest.New(testPasskeyOrigin).Register(options.PublicKey)
+		input := &models.RegisterPasskeyInput{SessionToken: options.SessionToken, Credential: *credential}
+		if _, err := svc.FinishRegistration(user.ID, input); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		another, _ := webauthntest.New(testPasskeyOrigin).Register(options.PublicKey)
+		input.Credential = *another
+		if _, err := svc.FinishRegistration(user.ID, input); !errors.Is(err, ErrInvalidPasskeySession) {
+			t.Errorf("expected ErrInvalidPasskeySession, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 別のユーザーのセッショントークンは使えない", func(t *testing.T) {
+		options, _ := svc.BeginRegistration(user.ID)
+		credential, _ := webauthntest.New(testPasskeyOrigin).Register(options.PublicKey)
+		_, err := svc.FinishRegistration(other.ID, &models.RegisterPasskeyInput{SessionToken: options.SessionToken, Credential: *credential})
+		if !errors.Is(err, ErrInvalidPasskeySession) {
+			t.Errorf("expected ErrInvalidPasskeySession, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 別のオリジンで作成したパスキーは登録できない", func(t *testing.T) {
+		options, _ := svc.BeginRegistration(user.ID)
+		credential, _ := webauthntest.New("https://evil.example").Register(options.PublicKey)
+		_, err := svc.FinishRegistration(user.ID, &models.RegisterPasskeyInput{SessionToken: options.SessionToken, Credential: *credential})
+		if !errors.Is(err, ErrPasskeyVerificationFailed) {
+			t.Errorf("expected ErrPasskeyVerificationFailed, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 登録済みのパスキーは登録できない", func(t *testing.T) {
+		options, _ := svc.BeginRegistration(other.ID)
+		credential, _ := webauthntest.New(testPasskeyOrigin).Register(options.PublicKey)
+		if err := passkeyRepo.Create(&models.Passkey{UserID: user.ID, CredentialID: credential.RawID}); err != nil {
+			t.Fatalf("failed to create passkey: %v", err)
+		}
+		_, err := svc.FinishRegistration(other.ID, &models.RegisterPasskeyInput{SessionToken: options.SessionToken, Credential: *credential})
+		if !errors.Is(err, repositories.ErrPasskeyAlreadyExists) {
+			t.Errorf("expected ErrPasskeyAlreadyExists, got %v", err)
+		}
+	})
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	svc, userRepo, passkeyRepo := newPasskeyTestService()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	user := newMFATestUser(t, userRepo, "passkey-login@example.com")
+	authenticator := webauthntest.New(testPasskeyOrigin)
+	authenticator.CountSignatures = true
+	passkey := registerTestPasskey(t, svc, authenticator, user.ID, "iPhone")
+
+	t.Run("正常系: パスキーでログインし、署名カウンターと最終利用日時を更新する", func(t *testing.T) {
+		loggedIn, accessToken, refreshToken, err := svc.FinishLogin(beginTestPasskeyLogin(t, svc, authenticator), models.SessionDevice{UserAgent: "TestAgent"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if loggedIn.ID != user.ID || accessToken == "" || refreshToken == "" {
+			t.Fatal("expected tokens for the passkey owner")
+		}
+		stored, _ := passkeyRepo.GetByCredentialID(passkey.CredentialID)
+		if stored.SignCount != 1 || stored.LastUsedAt == nil || !stored.LastUsedAt.Equal(now) {
+			t.Errorf("unexpected passkey after login: %+v", stored)
+		}
+	})
+
+	t.Run("異常系: 同じセッショントークンで再びログインできない", func(t *testing.T) {
+		input := beginTestPasskeyLogin(t, svc, authenticator)
+		if _, _, _, err := svc.FinishLogin(input, models.SessionDevice{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, _, _, err := svc.FinishLogin(input, models.SessionDevice{}); !errors.Is(err, ErrInvalidPasskeySession) {
+			t.Errorf("expected ErrInvalidPasskeySession, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 登録のセッショントークンではログインできない", func(t *testing.T) {
+		options, _ := svc.BeginRegistration(user.ID)
+		input := beginTestPasskeyLogin(t, svc, authenticator)
+		input.SessionToken = options.SessionToken
+		if _, _, _, err := svc.FinishLogin(input, models.SessionDevice{}); !errors.Is(err, ErrInvalidPasskeySession) {
+			t.Errorf("expected ErrInvalidPasskeySession, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 登録されていないパスキー", func(t *testing.T) {
+		unknown := webauthntest.New(testPasskeyOrigin)
+		options, _ := svc.BeginRegistration(user.ID)
+		if _, err := unknown.Register(options.PublicKey); err != nil {
+			t.Fatalf("authenticator register failed: %v", err)
+		}
+		if _, _, _, err := svc.FinishLogin(beginTestPasskeyLogin(t, svc, unknown), models.SessionDevice{}); !errors.Is(err, ErrPasskeyVerificationFailed) {
+			t.Errorf("expected ErrPasskeyVerificationFailed, got %v", err)
+		}
+	})
+
+	t.Run("異常系: ユーザーハンドルがパスキーの持ち主と異なる", func(t *testing.T) {
+		input := beginTestPasskeyLogin(t, svc, authenticator)
+		input.Credential.Response.UserHandle = userHandle(user.ID + 1)
+		if _, _, _, err := svc.FinishLogin(input, models.SessionDevice{}); !errors.Is(err, ErrPasskeyVerificationFailed) {
+			t.Errorf("expected ErrPasskeyVerificationFailed, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 検証中に他のログインが署名カウンターを進めた場合は受け付けない", func(t *testing.T) {
+		passkeyRepo.beforeUpdate = func() { passkeyRepo.passkeys[0].SignCount = 1000 }
+		defer func() { passkeyRepo.beforeUpdate = nil }()
+		if _, _, _, err := svc.FinishLogin(beginTestPasskeyLogin(t, svc, authenticator), models.SessionDevice{}); !errors.Is(err, ErrPasskeyVerificationFailed) {
+			t.Errorf("expected ErrPasskeyVerificationFailed, got %v", err)
+		}
+		if stored := passkeyRepo.passkeys[0]; stored.SignCount != 1000 {
+			t.Errorf("expected sign count not to be overwritten, got %d", stored.SignCount)
+		}
+	})
+
+	t.Run("異常系: 署名カウンターが戻った認証器は複製とみなす", func(t *testing.T) {
+		passkeyRepo.passkeys[0].SignCount = 100
+		if _, _, _, err := svc.FinishLogin(beginTestPasskeyLogin(t, svc, authenticator), models.SessionDevice{}); !errors.Is(err, ErrPasskeyVerificationFailed) {
+			t.Errorf("expected ErrPasskeyVerificationFailed, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 削除したパスキーではログインできない", func(t *testing.T) {
+		passkeyRepo.passkeys[0].SignCount = 0
+		if err := svc.Delete(user.ID, passkey.ID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, _, _, err := svc.FinishLogin(beginTestPasskeyLogin(t, svc, authenticator), models.SessionDevice{}); !errors.Is(err, ErrPasskeyVerificationFailed) {
+			t.Errorf("expected ErrPasskeyVerificationFailed, got %v", err)
+		}
+	})
+}
+
+func TestPasskeyLogin_SuspendedUser(t *testing.T) {
+	svc, userRepo, _ := newPasskeyTestService()
+	user := newMFATestUser(t, userRepo, "passkey-suspended@example.com")
+	authenticator := webauthntest.New(testPasskeyOrigin)
+	registerTestPasskey(t, svc, authenticator, user.ID, "")
+	userRepo.users[user.Email].SuspendedAt = &time.Time{}
+
+	_, accessToken, _, err := svc.FinishLogin(beginTestPasskeyLogin(t, svc, authenticator), models.SessionDevice{})
+	var suspension *auth.SuspensionError
+	if !errors.As(err, &suspension) || accessToken != "" {
+		t.Fatalf("expected SuspensionError, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+	"go-shisha-backend/pkg/mailer"
+)
+
+const (
+	// passwordResetTokenTTL はパスワードリセットのトークンの有効期間
+	passwordResetTokenTTL = 30 * time.Minute
+	// mailSendTimeout はバックグラウンドでのメールの送信のタイムアウト
+	mailSendTimeout = time.Minute
+	// passwordResetCleanupInterval は有効期限切れのトークンを削除する間隔
+	passwordResetCleanupInterval = time.Hour
+)
+
+// tokenInvalidator はユーザーのすべてのログイン状態を終わらせる（AuthService が実装する）
+type tokenInvalidator interface {
+	InvalidateAllTokens(userID int64) error
+}
+
+// PasswordResetService はメールで送ったリンクによるパスワードのリセットを扱う
+type PasswordResetService struct {
+	userRepo  repositories.AuthUserRepository
+	resetRepo repositories.PasswordResetRepository
+	// failureRepo はパスワードを再設定したユーザーのログインの失敗の記録・ロックを消す
+	failureRepo repositories.LoginFailureRepository
+	mailer      mailer.Mailer
+	tokens      tokenInvalidator
+	// resetURL はメールに記載するパスワードリセットの画面の URL（?token= を付けて送る）
+	resetURL string
+	now      func() time.Time
+}
+
+// NewPasswordResetService は新しい PasswordResetService を作成する
+func NewPasswordResetService(userRepo repositories.AuthUserRepository, resetRepo repositories.PasswordResetRepository, failureRepo repositories.LoginFailureRepository, m mailer.Mailer, tokens tokenInvalidator, resetURL string) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		failureRepo: failureRepo,
+		mailer:      m,
+		tokens:      tokens,
+		resetURL:    resetURL,
+		now:         time.Now,
+	}
+}
+
+// RequestReset はメールアドレスのユーザーにパスワードリセットのリンクを送る
+// 登録されているメールアドレスかどうかを推測されないよう、ユーザーが存在しない場合もエラーにせず、
+// メールはバックグラウンドで送信して応答時間にも差が出ないようにする
+func (s *PasswordResetService) RequestReset(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			logging.L.Info("password reset requested for unknown email", "service", "PasswordResetService", "method", "RequestReset")
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	rawToken, err := generatePasswordResetToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+	if err := s.resetRepo.CreateToken(user.ID, rawToken, s.now().Add(passwordResetTokenTTL)); err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "パスワードの再設定",
+		Body: fmt.Sprintf("%s さん\n\n"+
+			"パスワードの再設定を受け付けました。以下のリンクから%d分以内に新しいパスワードを設定してください。\n\n"+
+			"%s?token=%s\n\n"+
+			"このメールに心当たりがない場合は、このメールを破棄してください。パスワードは変更されません。\n",
+			user.DisplayName, int(passwordResetTokenTTL.Minutes()), s.resetURL, rawToken),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			logging.L.Error("failed to send password reset mail", "service", "PasswordResetService", "method", "RequestReset", "user_id", user.ID, "error", err)
+		}
+	}()
+	logging.L.Info("password reset requested", "service", "PasswordResetService", "method", "RequestReset", "user_id", user.ID)
+	return nil
+}
+
+// ConfirmReset はトークンを使ってパスワードを newPassword に変更し、すべての端末をログアウトさせる
+// ログインの失敗によるロックも解除し、新しいパスワードですぐにログインできるようにする
+// トークンが存在しない・使用済み・有効期限切れの場合は repositories.ErrInvalidPasswordResetToken を返す
+func (s *PasswordResetService) ConfirmReset(rawToken, newPassword string) error {
+	userID, err := s.resetRepo.ConsumeToken(rawToken, s.now())
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidPasswordResetToken) {
+			return err
+		}
+		return fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := user.HashPassword(newPassword); err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(userID, user.PasswordHash); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	// パスワードを知られた可能性があるため、この端末を含むすべての端末をログアウトさせる
+	if err := s.tokens.InvalidateAllTokens(int64(userID)); err != nil {
+		return fmt.Errorf("failed to invalidate tokens: %w", err)
+	}
+	if err := s.failureRepo.Delete(userID); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	logging.L.Info("password reset", "service", "PasswordResetService", "method", "ConfirmReset", "user_id", userID)
+	return nil
+}
+
+// Run は ctx がキャンセルされるまで定期的に有効期限切れのトークンを削除する
+func (s *PasswordResetService) Run(ctx context.Context) {
+	ticker := time.NewTicker(passwordResetCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.resetRepo.DeleteExpiredTokens(s.now()); err != nil {
+				logging.L.Error("failed to clean up password reset tokens", "service", "PasswordResetService", "error", err)
+			}
+		}
+	}
+}
+
+// generatePasswordResetToken は推測できないランダムなトークンを生成する
+func generatePasswordResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
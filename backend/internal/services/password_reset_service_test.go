@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/mailer"
+)
+
+// memoryPasswordResetRepo はテスト用の PasswordResetRepository（トークンは生のまま保持する）
+type memoryPasswordResetRepo struct {
+	tokens map[string]memoryPasswordResetToken
+}
+
+type memoryPasswordResetToken struct {
+	userID    int
+	expiresAt time.Time
+	used      bool
+}
+
+func newMemoryPasswordResetRepo() *memoryPasswordResetRepo {
+	return &memoryPasswordResetRepo{tokens: make(map[string]memoryPasswordResetToken)}
+}
+
+func (r *memoryPasswordResetRepo) CreateToken(userID int, rawToken string, expiresAt time.Time) error {
+	for raw, token := range r.tokens {
+		if token.userID == userID && !token.used {
+			delete(r.tokens, raw)
+		}
+	}
+	r.tokens[rawToken] = memoryPasswordResetToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (r *memoryPasswordResetRepo) ConsumeToken(rawToken string, now time.Time) (int, error) {
+	token, ok := r.tokens[rawToken]
+	if !ok || token.used || !now.Before(token.expiresAt) {
+		return 0, repositories.ErrInvalidPasswordResetToken
+	}
+	token.used = true
+	r.tokens[rawToken] = token
+	return token.userID, nil
+}
+
+func (r *memoryPasswordResetRepo) DeleteExpiredTokens(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// recordingMailer は送信したメールをチャネルに送るテスト用の Mailer
+type recordingMailer struct {
+	sent chan mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+type recordingTokenInvalidator struct {
+	invalidated []int64
+}
+
+func (i *recordingTokenInvalidator) InvalidateAllTokens(userID int64) error {
+	i.invalidated = append(i.invalidated, userID)
+	return nil
+}
+
+func TestPasswordReset(t *testing.T) {
+	userRepo := newMockAuthUserRepo()
+	user := &models.User{Email: "reset@example.com", DisplayName: "Reset User"}
+	_ = user.HashPassword("old-password-123")
+	_ = userRepo.Create(user)
+
+	resetRepo := newMemoryPasswordResetRepo()
+	mail := &recordingMailer{sent: make(chan mailer.Message, 1)}
+	tokens := &recordingTokenInvalidator{}
+	failureRepo := newMemoryLoginFailureRepo()
+	svc := NewPasswordResetService(userRepo, resetRepo, failureRepo, mail, tokens, "http://localhost:3000/password-reset")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	receiveToken := func(t *testing.T) string {
+		t.Helper()
+		select {
+		case msg := <-mail.sent:
+			if msg.To != "reset@example.com" {
+				t.Fatalf("unexpected recipient: %s", msg.To)
+			}
+			match := regexp.MustCompile(`http://localhost:3000/password-reset\?token=([A-Za-z0-9_-]+)`).FindStringSubmatch(msg.Body)
+			if match == nil {
+				t.Fatalf("expected reset link in mail body, got %q", msg.Body)
+			}
+			return match[1]
+		case <-time.After(time.Second):
+			t.Fatal("expected password reset mail to be sent")
+			return ""
+		}
+	}
+
+	t.Run("正常系: 登録されていないメールアドレスでもエラーにせず、メールは送らない", func(t *testing.T) {
+		if err := svc.RequestReset("unknown@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		select {
+		case msg := <-mail.sent:
+			t.Fatalf("expected no mail to be sent, got %+v", msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("異常系: 有効期限切れのトークン", func(t *testing.T) {
+		if err := svc.RequestReset("reset@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		token := receiveToken(t)
+		svc.now = func() time.Time { return now.Add(passwordResetTokenTTL) }
+		defer func() { svc.now = func() time.Time { return now } }()
+		if err := svc.ConfirmReset(token, "new-password-123"); !errors.Is(err, repositories.ErrInvalidPasswordResetToken) {
+			t.Fatalf("expected ErrInvalidPasswordResetToken, got %v", err)
+		}
+	})
+
+	t.Run("正常系: パスワードを再設定し、すべての端末をログアウトさせ、ログインのロックを解除する", func(t *testing.T) {
+		if _, err := failureRepo.Reserve(user.ID, now, now.Add(-time.Hour), func(int) time.Duration { return 0 }); err != nil {
+			t.Fatalf("Reserve failed: %v", err)
+		}
+		if err := failureRepo.Lock(user.ID, now.Add(time.Hour)); err != nil {
+			t.Fatalf("Lock failed: %v", err)
+		}
+		if err := svc.RequestReset("reset@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		token := receiveToken(t)
+		if err := svc.ConfirmReset(token, "new-password-123"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := failureRepo.Get(user.ID); !errors.Is(err, repositories.ErrLoginFailureNotFound) {
+			t.Fatalf("expected login lock to be cleared, got %v", err)
+		}
+		if err := user.CheckPassword("new-password-123"); err != nil {
+			t.Fatalf("expected password to be changed, got %v", err)
+		}
+		if len(tokens.invalidated) != 1 || tokens.invalidated[0] != int64(user.ID) {
+			t.Fatalf("expected all tokens to be invalidated, got %v", tokens.invalidated)
+		}
+		// トークンは1回だけ使える
+		if err := svc.ConfirmReset(token, "another-password-123"); !errors.Is(err, repositories.ErrInvalidPasswordResetToken) {
+			t.Fatalf("expected ErrInvalidPasswordResetToken, got %v", err)
+		}
+	})
+}
@@ -24,22 +24,90 @@ type PostService struct {
 	userRepo   repositories.UserRepository
 	flavorRepo repositories.FlavorRepository
 	uploadRepo repositories.UploadRepository
+	// statsInvalidator は投稿・いいねの変化をユーザー統計のキャッシュに反映する
+	statsInvalidator UserStatsInvalidator
+	// notifier はいいね等の発生時に投稿者へ通知する
+	notifier Notifier
+	// streamPublisher は新規投稿・いいね数の変化をストリームに配信する
+	streamPublisher StreamPublisher
 }
 
 // NewPostService は新しいPostServiceを作成する
-func NewPostService(postRepo repositories.PostRepository, userRepo repositories.UserRepository, flavorRepo repositories.FlavorRepository, uploadRepo repositories.UploadRepository) *PostService {
+func NewPostService(postRepo repositories.PostRepository, userRepo repositories.UserRepository, flavorRepo repositories.FlavorRepository, uploadRepo repositories.UploadRepository, statsInvalidator UserStatsInvalidator, notifier Notifier, streamPublisher StreamPublisher) *PostService {
 	return &PostService{
-		postRepo:   postRepo,
-		userRepo:   userRepo,
-		flavorRepo: flavorRepo,
-		uploadRepo: uploadRepo,
+		postRepo:         postRepo,
+		userRepo:         userRepo,
+		flavorRepo:       flavorRepo,
+		uploadRepo:       uploadRepo,
+		statsInvalidator: statsInvalidator,
+		notifier:         notifier,
+		streamPublisher:  streamPublisher,
 	}
 }
 
+// notify は通知を作成する
+// 通知の失敗は投稿・いいね自体の失敗とはせず、ログのみ出力する
+func (s *PostService) notify(event models.NotificationEvent, method string) {
+	if err := s.notifier.Notify(event); err != nil {
+		logging.L.Warn("通知の作成失敗",
+			"service", "PostService",
+			"method", method,
+			"user_id", event.UserID,
+			"type", event.Type,
+			"error", err)
+	}
+}
+
+// publishPostCreated は新規投稿をストリームに配信する
+func (s *PostService) publishPostCreated(post *models.Post) {
+	s.streamPublisher.Publish(models.StreamEvent{
+		Type: models.StreamEventPostCreated,
+		Data: models.PostCreatedStreamData{PostID: post.ID, UserID: post.UserID},
+	})
+}
+
+// publishLikeCount は投稿のいいね数をストリームに配信する
+func (s *PostService) publishLikeCount(post *models.Post) {
+	s.streamPublisher.Publish(models.StreamEvent{
+		Type: models.StreamEventLikeCountChanged,
+		Data: models.LikeCountStreamData{PostID: post.ID, Likes: post.Likes},
+	})
+}
+
 // GetAllPosts はすべての投稿を取得する
 // userIDが指定されている場合、各投稿のいいね状態（is_liked）を含めて返す
-func (s *PostService) GetAllPosts(userID *int) ([]models.Post, error) {
-	return s.postRepo.GetAll(userID)
+// filter のセッション詳細条件は前後の空白を除去し、空文字は未指定として扱う
+func (s *PostService) GetAllPosts(userID *int, filter models.PostFilter) ([]models.Post, error) {
+	filter.BowlType = normalizeOptionalString(filter.BowlType)
+	filter.HeatManagement = normalizeOptionalString(filter.HeatManagement)
+	return s.postRepo.GetAll(userID, filter)
+}
+
+// normalizeOptionalString は前後の空白を除去し、空文字の場合は nil を返す
+func normalizeOptionalString(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*v)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// toShishaSession はセッション詳細の入力を正規化して返す
+// input が nil の場合は nil を返す。文字列項目は前後の空白を除去し、空文字は未入力として扱う
+func toShishaSession(input *models.ShishaSessionInput) *models.ShishaSession {
+	if input == nil {
+		return nil
+	}
+	return &models.ShishaSession{
+		BowlType:        normalizeOptionalString(input.BowlType),
+		HeatManagement:  normalizeOptionalString(input.HeatManagement),
+		CoalCount:       input.CoalCount,
+		DurationMinutes: input.DurationMinutes,
+		Rating:          input.Rating,
+	}
 }
 
 // GetPostByID は指定IDの投稿を取得する
@@ -48,6 +116,12 @@ func (s *PostService) GetPostByID(id int, userID *int) (*models.Post, error) {
 	return s.postRepo.GetByID(id, userID)
 }
 
+// IsPostVisibleTo は投稿を viewerID のユーザーのタイムラインに表示できるかを返す
+// ストリームで配信する投稿のイベントを、タイムラインと同じ条件で閲覧者ごとに絞り込むために使う
+func (s *PostService) IsPostVisibleTo(viewerID, postID int) (bool, error) {
+	return s.postRepo.IsVisibleTo(postID, viewerID)
+}
+
 // CreatePost は新しい投稿を作成する
 func (s *PostService) CreatePost(userID int, input *models.CreatePostInput) (*models.Post, error) {
 	// Verify user exists and get user information
@@ -96,26 +170,68 @@ func (s *PostService) CreatePost(userID int, input *models.CreatePostInput) (*mo
 		Slides: slides,
 		User:   *user,
 	}
+	if session := toShishaSession(input.Session); !session.IsEmpty() {
+		post.Session = session
+	}
 
 	err = s.postRepo.Create(post)
 	if err != nil {
 		return nil, err
 	}
+	s.statsInvalidator.Invalidate(userID)
+	s.publishPostCreated(post)
+	// 使用した画像のステータス更新・バッジの獲得判定は、投稿と同じトランザクションで書き込まれた
+	// PostCreated イベントを UploadService・BadgeService が購読して行う（失敗時は再試行される）
 
-	// 使用した画像のステータスを"used"に更新
-	// 設計方針: 投稿作成が優先。ステータス更新失敗してもログのみで処理継続
-	// （画像の使い回しは許可、クリーンアップは別途バッチ処理で対応）
-	for _, slide := range slides {
-		if err := s.uploadRepo.MarkAsUsed(slide.ImageURL); err != nil {
-			logging.L.Warn("画像ステータス更新失敗（投稿作成は成功）",
-				"post_id", post.ID,
-				"image_url", slide.ImageURL,
-				"error", err)
-			// 投稿作成は成功として扱うため、処理を継続
-			continue
+	return post, nil
+}
+
+// RemixPost は指定された投稿のレシピ（各スライドのテキストとフレーバー）をコピーした新しい投稿を作成する
+// 画像はコピーしないため、作成される投稿のスライドは画像なし（image_url が空）となる
+// 作成された投稿は remixed_from に元投稿のIDを保持する
+// 元投稿が存在しない（削除済みを含む）場合は repositories.ErrPostNotFound を返す
+// 元投稿の投稿者にブロックされている場合は repositories.ErrBlocked を返す
+func (s *PostService) RemixPost(userID, sourcePostID int) (*models.Post, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, repositories.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	source, err := s.postRepo.GetByID(sourcePostID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	slides := make([]models.Slide, len(source.Slides))
+	for i, sourceSlide := range source.Slides {
+		slides[i] = models.Slide{
+			Text:   sourceSlide.Text,
+			Flavor: sourceSlide.Flavor,
 		}
 	}
 
+	remixedFrom := source.ID
+	post := &models.Post{
+		UserID:      userID,
+		Slides:      slides,
+		User:        *user,
+		RemixedFrom: &remixedFrom,
+	}
+	if err := s.postRepo.Create(post); err != nil {
+		return nil, err
+	}
+	s.statsInvalidator.Invalidate(userID)
+	s.publishPostCreated(post)
+
+	logging.L.Info("post remixed",
+		"service", "PostService",
+		"method", "RemixPost",
+		"post_id", post.ID,
+		"source_post_id", source.ID,
+		"user_id", userID)
 	return post, nil
 }
 
@@ -159,11 +275,25 @@ func (s *PostService) validateImageURL(userID int, imageURL string) error {
 
 // LikePost は指定された投稿にいいねを追加する
 // ユーザーIDと投稿IDを受け取り、AddLikeでDB登録後に最新データを返す
+// 投稿者にブロックされている場合は repositories.ErrBlocked を返す
 func (s *PostService) LikePost(userID, postID int) (*models.Post, error) {
 	if err := s.postRepo.AddLike(userID, postID); err != nil {
 		return nil, err
 	}
-	return s.postRepo.GetByID(postID, &userID)
+	post, err := s.postRepo.GetByID(postID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	// 受け取ったいいね数は投稿者の統計に反映する（バッジの獲得判定は LikeAdded イベントを BadgeService が購読して行う）
+	s.statsInvalidator.Invalidate(post.UserID)
+	s.notify(models.NotificationEvent{
+		UserID:  post.UserID,
+		ActorID: userID,
+		Type:    models.NotificationTypePostLiked,
+		PostID:  &postID,
+	}, "LikePost")
+	s.publishLikeCount(post)
+	return post, nil
 }
 
 // UnlikePost は指定された投稿のいいねを取り消す
@@ -172,17 +302,27 @@ func (s *PostService) UnlikePost(userID, postID int) (*models.Post, error) {
 	if err := s.postRepo.RemoveLike(userID, postID); err != nil {
 		return nil, err
 	}
-	return s.postRepo.GetByID(postID, &userID)
+	post, err := s.postRepo.GetByID(postID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	s.statsInvalidator.Invalidate(post.UserID)
+	s.publishLikeCount(post)
+	return post, nil
 }
 
 // DeletePost は指定された投稿を論理削除する
 // 投稿が存在しない場合は repositories.ErrPostNotFound を返す
 // 投稿の所有者でない場合は repositories.ErrForbidden を返す
 func (s *PostService) DeletePost(userID, postID int) error {
-	return s.postRepo.DeletePost(userID, postID)
+	if err := s.postRepo.DeletePost(userID, postID); err != nil {
+		return err
+	}
+	s.statsInvalidator.Invalidate(userID)
+	return nil
 }
 
-// UpdatePost は指定された投稿のスライドの text/flavor_id を更新する
+// UpdatePost は指定された投稿のスライドの text/flavor_id とセッション詳細を更新する
 // 投稿が存在しない場合は repositories.ErrPostNotFound を返す
 // 投稿の所有者でない場合は repositories.ErrForbidden を返す
 // スライド枚数が既存と一致しない場合は repositories.ErrSlideCountMismatch を返す
@@ -222,5 +362,11 @@ func (s *PostService) UpdatePost(userID, postID int, input *models.UpdatePostInp
 			return nil, err
 		}
 	}
-	return s.postRepo.UpdatePost(userID, postID, input.Slides)
+	post, err := s.postRepo.UpdatePost(userID, postID, input.Slides, toShishaSession(input.Session))
+	if err != nil {
+		return nil, err
+	}
+	// スライドのフレーバー変更は統計に影響する
+	s.statsInvalidator.Invalidate(userID)
+	return post, nil
 }
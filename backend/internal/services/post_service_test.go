@@ -12,13 +12,16 @@ import (
 
 type mockPostRepo struct{}
 
-func (m *mockPostRepo) GetAll(userID *int) ([]models.Post, error) {
+func (m *mockPostRepo) GetAll(userID *int, filter models.PostFilter) ([]models.Post, error) {
 	return []models.Post{{ID: 1}}, nil
 }
 func (m *mockPostRepo) GetByID(id int, userID *int) (*models.Post, error) {
 	p := &models.Post{ID: id, Likes: 0}
 	return p, nil
 }
+func (m *mockPostRepo) GetByIDs(ids []int, userID *int) ([]models.Post, error) {
+	return nil, nil
+}
 func (m *mockPostRepo) GetByUserID(userID int, currentUserID *int) ([]models.Post, error) {
 	return []models.Post{{ID: 1, UserID: userID}}, nil
 }
@@ -38,8 +41,11 @@ func (m *mockPostRepo) RemoveLike(userID, postID int) error { return nil }
 func (m *mockPostRepo) HasLiked(userID, postID int) (bool, error) {
 	return false, nil
 }
+func (m *mockPostRepo) IsVisibleTo(postID, viewerID int) (bool, error) {
+	return true, nil
+}
 func (m *mockPostRepo) DeletePost(userID, postID int) error { return nil }
-func (m *mockPostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput) (*models.Post, error) {
+func (m *mockPostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput, session *models.ShishaSession) (*models.Post, error) {
 	return &models.Post{ID: postID}, nil
 }
 
@@ -79,7 +85,7 @@ func (s *spyPostRepo) GetByID(id int, userID *int) (*models.Post, error) {
 	return &models.Post{ID: id, Likes: s.currentLikes, IsLiked: s.currentIsLiked}, nil
 }
 func (s *spyPostRepo) DeletePost(userID, postID int) error { return nil }
-func (s *spyPostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput) (*models.Post, error) {
+func (s *spyPostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput, session *models.ShishaSession) (*models.Post, error) {
 	return &models.Post{ID: postID}, nil
 }
 
@@ -138,7 +144,7 @@ func (m *mockUploadRepo) ReplaceProfileImage(newUpload *models.UploadDB) ([]stri
 }
 
 func TestCreatePost(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	input := &models.CreatePostInput{Slides: []models.SlideInput{{ImageURL: "/images/test.jpg", Text: "hello"}}}
 	p, err := postSvc.CreatePost(1, input)
 	if err != nil {
@@ -153,7 +159,7 @@ func TestCreatePost(t *testing.T) {
 }
 
 func TestCreatePost_WithFlavor(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	flavorID := 1
 	input := &models.CreatePostInput{
 		Slides: []models.SlideInput{
@@ -183,7 +189,7 @@ func TestCreatePost_WithFlavor(t *testing.T) {
 }
 
 func TestCreatePost_WithInvalidFlavorID(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	invalidFlavorID := 999
 	input := &models.CreatePostInput{
 		Slides: []models.SlideInput{
@@ -216,7 +222,7 @@ func TestCreatePost_WithInvalidFlavorID(t *testing.T) {
 
 func TestLikeUnlikePost(t *testing.T) {
 	spy := &spyPostRepo{}
-	postSvc := NewPostService(spy, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(spy, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	liked, err := postSvc.LikePost(1, 2)
 	if err != nil {
 		t.Fatalf("unexpected error like: %v", err)
@@ -263,8 +269,8 @@ func TestLikeUnlikePost(t *testing.T) {
 }
 
 func TestGetAllPosts(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
-	posts, err := postSvc.GetAllPosts(nil)
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	posts, err := postSvc.GetAllPosts(nil, models.PostFilter{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -276,12 +282,15 @@ func TestGetAllPosts(t *testing.T) {
 // Error cases for PostService
 type mockPostRepoError struct{}
 
-func (m *mockPostRepoError) GetAll(userID *int) ([]models.Post, error) {
+func (m *mockPostRepoError) GetAll(userID *int, filter models.PostFilter) ([]models.Post, error) {
 	return nil, errors.New("db error")
 }
 func (m *mockPostRepoError) GetByID(id int, userID *int) (*models.Post, error) {
 	return nil, errors.New("db error")
 }
+func (m *mockPostRepoError) GetByIDs(ids []int, userID *int) ([]models.Post, error) {
+	return nil, errors.New("db error")
+}
 func (m *mockPostRepoError) GetByUserID(userID int, currentUserID *int) ([]models.Post, error) {
 	return nil, errors.New("db error")
 }
@@ -297,8 +306,11 @@ func (m *mockPostRepoError) RemoveLike(userID, postID int) error { return errors
 func (m *mockPostRepoError) HasLiked(userID, postID int) (bool, error) {
 	return false, errors.New("db error")
 }
+func (m *mockPostRepoError) IsVisibleTo(postID, viewerID int) (bool, error) {
+	return false, errors.New("db error")
+}
 func (m *mockPostRepoError) DeletePost(userID, postID int) error { return errors.New("db error") }
-func (m *mockPostRepoError) UpdatePost(userID, postID int, slides []models.UpdateSlideInput) (*models.Post, error) {
+func (m *mockPostRepoError) UpdatePost(userID, postID int, slides []models.UpdateSlideInput, session *models.ShishaSession) (*models.Post, error) {
 	return nil, errors.New("db error")
 }
 
@@ -313,7 +325,7 @@ func (m *mockUserRepoMissing) Update(id int, input models.UpdateUserInput) (*mod
 }
 
 func TestCreatePost_UserMissing(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoMissing{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoMissing{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	input := &models.CreatePostInput{Slides: []models.SlideInput{{ImageURL: "/images/test.jpg", Text: "hello"}}}
 	_, err := postSvc.CreatePost(999, input)
 	if err == nil {
@@ -322,7 +334,7 @@ func TestCreatePost_UserMissing(t *testing.T) {
 }
 
 func TestCreatePost_PostCreateError(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepoError{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepoError{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	input := &models.CreatePostInput{Slides: []models.SlideInput{{ImageURL: "/images/test.jpg", Text: "hello"}}}
 	_, err := postSvc.CreatePost(1, input)
 	if err == nil {
@@ -331,7 +343,7 @@ func TestCreatePost_PostCreateError(t *testing.T) {
 }
 
 func TestLikePost_Error(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepoError{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepoError{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	_, err := postSvc.LikePost(1, 1)
 	if err == nil {
 		t.Fatalf("expected error when AddLike fails, got nil")
@@ -339,7 +351,7 @@ func TestLikePost_Error(t *testing.T) {
 }
 
 func TestUnlikePost_Error(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepoError{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepoError{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	_, err := postSvc.UnlikePost(1, 1)
 	if err == nil {
 		t.Fatalf("expected error when RemoveLike fails, got nil")
@@ -359,7 +371,7 @@ func (m *mockUploadRepoInvalidPath) GetByFilePath(filePath string) (*models.Uplo
 }
 
 func TestCreatePost_ImageValidation_InvalidPath(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoInvalidPath{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoInvalidPath{})
 	input := &models.CreatePostInput{
 		Slides: []models.SlideInput{{ImageURL: "/images/../etc/passwd", Text: "hack"}},
 	}
@@ -378,7 +390,7 @@ type mockUploadRepoNotAllowed struct {
 }
 
 func TestCreatePost_ImageValidation_NotAllowed(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoNotAllowed{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoNotAllowed{})
 	input := &models.CreatePostInput{
 		Slides: []models.SlideInput{{ImageURL: "/uploads/test.jpg", Text: "wrong prefix"}},
 	}
@@ -401,7 +413,7 @@ func (m *mockUploadRepoNotFound) GetByFilePath(filePath string) (*models.UploadD
 }
 
 func TestCreatePost_ImageValidation_NotFound(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoNotFound{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoNotFound{})
 	input := &models.CreatePostInput{
 		Slides: []models.SlideInput{{ImageURL: "/images/notfound.jpg", Text: "missing"}},
 	}
@@ -429,7 +441,7 @@ func (m *mockUploadRepoWrongUser) GetByFilePath(filePath string) (*models.Upload
 }
 
 func TestCreatePost_ImageValidation_PermissionDenied(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoWrongUser{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoWrongUser{})
 	input := &models.CreatePostInput{
 		Slides: []models.SlideInput{{ImageURL: "/images/others.jpg", Text: "not mine"}},
 	}
@@ -457,7 +469,7 @@ func (m *mockUploadRepoDeleted) GetByFilePath(filePath string) (*models.UploadDB
 }
 
 func TestCreatePost_ImageValidation_Deleted(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoDeleted{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepoDeleted{})
 	input := &models.CreatePostInput{
 		Slides: []models.SlideInput{{ImageURL: "/images/deleted.jpg", Text: "gone"}},
 	}
@@ -480,7 +492,7 @@ func (m *mockPostRepoAlreadyLiked) AddLike(userID, postID int) error {
 }
 
 func TestLikePost_AlreadyLiked(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepoAlreadyLiked{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepoAlreadyLiked{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	_, err := postSvc.LikePost(1, 2)
 	if err == nil {
 		t.Fatalf("expected error for already liked, got nil")
@@ -500,7 +512,7 @@ func (m *mockPostRepoNotLiked) RemoveLike(userID, postID int) error {
 }
 
 func TestUnlikePost_NotLiked(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepoNotLiked{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepoNotLiked{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	_, err := postSvc.UnlikePost(1, 2)
 	if err == nil {
 		t.Fatalf("expected error for not liked, got nil")
@@ -511,9 +523,9 @@ func TestUnlikePost_NotLiked(t *testing.T) {
 }
 
 func TestGetAllPosts_WithUserID(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	userID := 1
-	posts, err := postSvc.GetAllPosts(&userID)
+	posts, err := postSvc.GetAllPosts(&userID, models.PostFilter{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -523,7 +535,7 @@ func TestGetAllPosts_WithUserID(t *testing.T) {
 }
 
 func TestGetPostByID_WithUserID(t *testing.T) {
-	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 	userID := 1
 	post, err := postSvc.GetPostByID(1, &userID)
 	if err != nil {
@@ -546,7 +558,7 @@ func (d *deletePostRepo) DeletePost(userID, postID int) error {
 
 func TestDeletePost_Success(t *testing.T) {
 	repo := &deletePostRepo{deleteErr: nil}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	if err := postSvc.DeletePost(1, 10); err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -555,7 +567,7 @@ func TestDeletePost_Success(t *testing.T) {
 
 func TestDeletePost_NotFound(t *testing.T) {
 	repo := &deletePostRepo{deleteErr: repositories.ErrPostNotFound}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	err := postSvc.DeletePost(1, 999)
 	if !errors.Is(err, repositories.ErrPostNotFound) {
@@ -565,7 +577,7 @@ func TestDeletePost_NotFound(t *testing.T) {
 
 func TestDeletePost_Forbidden(t *testing.T) {
 	repo := &deletePostRepo{deleteErr: repositories.ErrForbidden}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	err := postSvc.DeletePost(1, 2)
 	if !errors.Is(err, repositories.ErrForbidden) {
@@ -576,20 +588,22 @@ func TestDeletePost_Forbidden(t *testing.T) {
 // updatePostRepo はUpdatePost用のモックリポジトリ
 type updatePostRepo struct {
 	mockPostRepo
-	updateResult  *models.Post
-	updateErr     error
-	capturedSlides []models.UpdateSlideInput
+	updateResult    *models.Post
+	updateErr       error
+	capturedSlides  []models.UpdateSlideInput
+	capturedSession *models.ShishaSession
 }
 
-func (u *updatePostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput) (*models.Post, error) {
+func (u *updatePostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput, session *models.ShishaSession) (*models.Post, error) {
 	u.capturedSlides = slides
+	u.capturedSession = session
 	return u.updateResult, u.updateErr
 }
 
 func TestUpdatePost_Success(t *testing.T) {
 	expected := &models.Post{ID: 10, UserID: 1}
 	repo := &updatePostRepo{updateResult: expected}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{{ID: 1, Text: "updated"}},
@@ -605,7 +619,7 @@ func TestUpdatePost_Success(t *testing.T) {
 
 func TestUpdatePost_NotFound(t *testing.T) {
 	repo := &updatePostRepo{updateErr: repositories.ErrPostNotFound}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{{ID: 1, Text: "updated"}},
@@ -618,7 +632,7 @@ func TestUpdatePost_NotFound(t *testing.T) {
 
 func TestUpdatePost_Forbidden(t *testing.T) {
 	repo := &updatePostRepo{updateErr: repositories.ErrForbidden}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{{ID: 1, Text: "updated"}},
@@ -631,7 +645,7 @@ func TestUpdatePost_Forbidden(t *testing.T) {
 
 func TestUpdatePost_SlideCountMismatch(t *testing.T) {
 	repo := &updatePostRepo{updateErr: repositories.ErrSlideCountMismatch}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{{ID: 1, Text: "a"}, {ID: 2, Text: "b"}},
@@ -644,7 +658,7 @@ func TestUpdatePost_SlideCountMismatch(t *testing.T) {
 
 func TestUpdatePost_DuplicateSlideID(t *testing.T) {
 	repo := &updatePostRepo{updateErr: repositories.ErrDuplicateSlideID}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{{ID: 1, Text: "a"}, {ID: 1, Text: "b"}},
@@ -657,7 +671,7 @@ func TestUpdatePost_DuplicateSlideID(t *testing.T) {
 
 func TestUpdatePost_SlideNotBelongToPost(t *testing.T) {
 	repo := &updatePostRepo{updateErr: repositories.ErrSlideNotBelongToPost}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{{ID: 999, Text: "a"}},
@@ -686,7 +700,7 @@ func TestUpdatePost_WithInvalidFlavorID(t *testing.T) {
 	// 該当スライドのFlavorIDがnilに落とされてrepoに渡ることを確認する
 	invalidFlavorID := 999
 	repo := &updatePostRepo{updateResult: &models.Post{ID: 10, UserID: 1}}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{
@@ -713,7 +727,7 @@ func TestUpdatePost_FlavorRepoDBError(t *testing.T) {
 	// flavorRepoがDB障害等の予期しないエラーを返した場合、UpdatePost自体も失敗することを確認する
 	flavorID := 1
 	repo := &updatePostRepo{updateResult: &models.Post{ID: 10}}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepoDBError{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepoDBError{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{
@@ -729,7 +743,7 @@ func TestUpdatePost_FlavorRepoDBError(t *testing.T) {
 func TestUpdatePost_TextOmittedBecomesEmpty(t *testing.T) {
 	// 全上書き仕様の確認: text を省略（ゼロ値 ""）した場合、"" のまま repo に渡ることを確認する
 	repo := &updatePostRepo{updateResult: &models.Post{ID: 10, UserID: 1}}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{
@@ -754,7 +768,7 @@ func TestUpdatePost_TextOmittedBecomesEmpty(t *testing.T) {
 func TestUpdatePost_FlavorIDNilPassThrough(t *testing.T) {
 	// 全上書き仕様の確認: flavor_id を明示的に nil で渡すと nil のまま repo に渡ること（フレーバー解除）を確認する
 	repo := &updatePostRepo{updateResult: &models.Post{ID: 10, UserID: 1}}
-	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
 
 	input := &models.UpdatePostInput{
 		Slides: []models.UpdateSlideInput{
@@ -772,3 +786,207 @@ func TestUpdatePost_FlavorIDNilPassThrough(t *testing.T) {
 		t.Fatalf("expected nil FlavorID (flavor removal), got %v", repo.capturedSlides[0].FlavorID)
 	}
 }
+
+// remixSourcePostRepo はリミックス元の投稿を返し、Create に渡された投稿を記録するモック
+type remixSourcePostRepo struct {
+	mockPostRepo
+	source  *models.Post
+	created *models.Post
+}
+
+func (m *remixSourcePostRepo) GetByID(id int, userID *int) (*models.Post, error) {
+	if m.source == nil || m.source.ID != id {
+		return nil, repositories.ErrPostNotFound
+	}
+	return m.source, nil
+}
+
+func (m *remixSourcePostRepo) Create(post *models.Post) error {
+	post.ID = 30
+	m.created = post
+	return nil
+}
+
+func TestRemixPost_CopiesTextAndFlavorsWithoutImages(t *testing.T) {
+	mint := &models.Flavor{ID: 1, Name: "ミント", Color: "bg-green-500"}
+	repo := &remixSourcePostRepo{source: &models.Post{
+		ID:     5,
+		UserID: 2,
+		Slides: []models.Slide{
+			{ID: 1, ImageURL: "/images/a.jpg", Text: "ミント多め", Flavor: mint},
+			{ID: 2, ImageURL: "/images/b.jpg", Text: "仕上げ"},
+		},
+	}}
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+
+	p, err := postSvc.RemixPost(1, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.created == nil {
+		t.Fatal("expected Create to be called")
+	}
+	if p.UserID != 1 || p.User.ID != 1 {
+		t.Fatalf("expected remix to belong to caller, got user_id=%d user=%+v", p.UserID, p.User)
+	}
+	if p.RemixedFrom == nil || *p.RemixedFrom != 5 {
+		t.Fatalf("expected remixed_from=5, got %v", p.RemixedFrom)
+	}
+	if len(p.Slides) != 2 {
+		t.Fatalf("expected 2 slides, got %d", len(p.Slides))
+	}
+	for i, slide := range p.Slides {
+		if slide.ImageURL != "" {
+			t.Fatalf("slide %d: expected image to be dropped, got %q", i, slide.ImageURL)
+		}
+		if slide.Text != repo.source.Slides[i].Text {
+			t.Fatalf("slide %d: expected text %q, got %q", i, repo.source.Slides[i].Text, slide.Text)
+		}
+	}
+	if p.Slides[0].Flavor == nil || p.Slides[0].Flavor.ID != 1 {
+		t.Fatalf("expected flavor to be copied, got %+v", p.Slides[0].Flavor)
+	}
+	if p.Slides[1].Flavor != nil {
+		t.Fatalf("expected no flavor on second slide, got %+v", p.Slides[1].Flavor)
+	}
+}
+
+func TestRemixPost_SourceNotFound(t *testing.T) {
+	repo := &remixSourcePostRepo{}
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+
+	_, err := postSvc.RemixPost(1, 99)
+	if !errors.Is(err, repositories.ErrPostNotFound) {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	if repo.created != nil {
+		t.Fatal("expected Create not to be called")
+	}
+}
+
+func TestRemixPost_UserMissing(t *testing.T) {
+	postSvc := newTestPostService(&mockPostRepo{}, &mockUserRepoMissing{}, &mockFlavorRepo{}, &mockUploadRepo{})
+	_, err := postSvc.RemixPost(999, 1)
+	if err == nil {
+		t.Fatalf("expected error when user is missing, got nil")
+	}
+}
+
+// sessionPostRepo はセッション詳細の受け渡しを検証するため、Create の投稿と GetAll の絞り込み条件を記録するモック
+type sessionPostRepo struct {
+	mockPostRepo
+	created        *models.Post
+	capturedFilter models.PostFilter
+}
+
+func (m *sessionPostRepo) Create(post *models.Post) error {
+	post.ID = 40
+	m.created = post
+	return nil
+}
+
+func (m *sessionPostRepo) GetAll(userID *int, filter models.PostFilter) ([]models.Post, error) {
+	m.capturedFilter = filter
+	return nil, nil
+}
+
+func TestCreatePost_WithSession_NormalizesStrings(t *testing.T) {
+	repo := &sessionPostRepo{}
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+
+	bowl, hmd, rating := "  ファンネル ", "   ", 5
+	input := &models.CreatePostInput{
+		Slides:  []models.SlideInput{{ImageURL: "/images/test.jpg", Text: "hello"}},
+		Session: &models.ShishaSessionInput{BowlType: &bowl, HeatManagement: &hmd, Rating: &rating},
+	}
+	if _, err := postSvc.CreatePost(1, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := repo.created.Session
+	if s == nil {
+		t.Fatal("expected session to be passed to repository")
+	}
+	if s.BowlType == nil || *s.BowlType != "ファンネル" {
+		t.Fatalf("expected trimmed bowl_type, got %v", s.BowlType)
+	}
+	if s.HeatManagement != nil {
+		t.Fatalf("expected blank heat_management to be nil, got %q", *s.HeatManagement)
+	}
+	if s.Rating == nil || *s.Rating != 5 {
+		t.Fatalf("expected rating=5, got %v", s.Rating)
+	}
+}
+
+func TestCreatePost_EmptySessionIsDropped(t *testing.T) {
+	repo := &sessionPostRepo{}
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+
+	blank := ""
+	input := &models.CreatePostInput{
+		Slides:  []models.SlideInput{{ImageURL: "/images/test.jpg", Text: "hello"}},
+		Session: &models.ShishaSessionInput{BowlType: &blank},
+	}
+	if _, err := postSvc.CreatePost(1, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.created.Session != nil {
+		t.Fatalf("expected empty session to be dropped, got %+v", repo.created.Session)
+	}
+}
+
+func TestUpdatePost_SessionPassThrough(t *testing.T) {
+	repo := &updatePostRepo{updateResult: &models.Post{ID: 10}}
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+
+	// session 省略時は nil（変更なし）として渡ること
+	input := &models.UpdatePostInput{Slides: []models.UpdateSlideInput{{ID: 1, Text: "updated"}}}
+	if _, err := postSvc.UpdatePost(1, 10, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.capturedSession != nil {
+		t.Fatalf("expected nil session, got %+v", repo.capturedSession)
+	}
+
+	// 空オブジェクトは削除指示として空のセッションが渡ること
+	input.Session = &models.ShishaSessionInput{}
+	if _, err := postSvc.UpdatePost(1, 10, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.capturedSession == nil || !repo.capturedSession.IsEmpty() {
+		t.Fatalf("expected empty session, got %+v", repo.capturedSession)
+	}
+}
+
+func TestGetAllPosts_NormalizesFilter(t *testing.T) {
+	repo := &sessionPostRepo{}
+	postSvc := newTestPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{})
+
+	bowl, hmd := " ファンネル ", ""
+	if _, err := postSvc.GetAllPosts(nil, models.PostFilter{BowlType: &bowl, HeatManagement: &hmd}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.capturedFilter.BowlType == nil || *repo.capturedFilter.BowlType != "ファンネル" {
+		t.Fatalf("expected trimmed bowl_type, got %v", repo.capturedFilter.BowlType)
+	}
+	if repo.capturedFilter.HeatManagement != nil {
+		t.Fatalf("expected empty heat_management to be nil, got %q", *repo.capturedFilter.HeatManagement)
+	}
+}
+
+// nopStatsInvalidator・nopNotifier・nopStreamPublisher は、テストで確認しない PostService の依存に渡す何もしない実装
+type nopStatsInvalidator struct{}
+
+func (nopStatsInvalidator) Invalidate(userID int) {}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(event models.NotificationEvent) error { return nil }
+
+type nopStreamPublisher struct{}
+
+func (nopStreamPublisher) Publish(event models.StreamEvent) {}
+
+// newTestPostService は統計・通知・ストリームの依存に何もしない実装を渡して PostService を作成する
+func newTestPostService(postRepo repositories.PostRepository, userRepo repositories.UserRepository, flavorRepo repositories.FlavorRepository, uploadRepo repositories.UploadRepository) *PostService {
+	return NewPostService(postRepo, userRepo, flavorRepo, uploadRepo, nopStatsInvalidator{}, nopNotifier{}, nopStreamPublisher{})
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+var (
+	// ErrInvalidRole は存在しないロールを指定した場合のエラー
+	ErrInvalidRole = errors.New("ロールが不正です")
+	// ErrCannotChangeOwnRole は自分自身のロールを変更しようとした場合のエラー（最後の管理者が権限を失うのを防ぐ）
+	ErrCannotChangeOwnRole = errors.New("自分自身のロールは変更できません")
+)
+
+// RoleService はユーザーのロール（user / moderator / admin）を扱う
+type RoleService struct {
+	roleRepo repositories.UserRoleRepository
+}
+
+// NewRoleService は新しい RoleService を作成する
+func NewRoleService(roleRepo repositories.UserRoleRepository) *RoleService {
+	return &RoleService{
+		roleRepo: roleRepo,
+	}
+}
+
+// GetRole はユーザーの現在のロールを返す（RequireCurrentRole ミドルウェアで使う）
+func (s *RoleService) GetRole(userID int) (string, error) {
+	return s.roleRepo.GetRole(userID)
+}
+
+// ChangeRole は管理者 actorID がユーザー userID のロールを変更する
+// 変更後のロールは、対象ユーザーの次回のトークン再発行から反映される
+func (s *RoleService) ChangeRole(actorID, userID int, role string) error {
+	if role != models.RoleUser && role != models.RoleModerator && role != models.RoleAdmin {
+		return ErrInvalidRole
+	}
+	if actorID == userID {
+		return ErrCannotChangeOwnRole
+	}
+	return s.roleRepo.UpdateRole(actorID, userID, role)
+}
+
+// BootstrapAdmin は管理者が1人もいない場合に、指定されたメールアドレスのユーザーを最初の管理者にする
+// 起動時に呼び出し、既に管理者がいる場合は何もしない。該当するユーザーがまだ登録されていない場合は警告のみ出力する
+func (s *RoleService) BootstrapAdmin(email string) error {
+	promoted, err := s.roleRepo.BootstrapAdmin(email)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			logging.L.Warn("bootstrap admin user is not registered yet", "service", "RoleService", "method", "BootstrapAdmin", "email", email)
+			return nil
+		}
+		return err
+	}
+	if promoted {
+		logging.L.Info("bootstrap admin promoted", "service", "RoleService", "method", "BootstrapAdmin", "email", email)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// memoryRoleRepo はユーザーのロールをメモリ上に保持するモック
+type memoryRoleRepo struct {
+	roles     map[int]string
+	emails    map[string]int
+	changedBy map[int]int
+}
+
+func newMemoryRoleRepo() *memoryRoleRepo {
+	return &memoryRoleRepo{
+		roles:     map[int]string{1: models.RoleAdmin, 2: models.RoleUser},
+		emails:    map[string]int{"admin@example.com": 1, "user@example.com": 2},
+		changedBy: map[int]int{},
+	}
+}
+
+func (m *memoryRoleRepo) GetRole(userID int) (string, error) {
+	role, ok := m.roles[userID]
+	if !ok {
+		return "", repositories.ErrUserNotFound
+	}
+	return role, nil
+}
+
+func (m *memoryRoleRepo) UpdateRole(actorID, userID int, role string) error {
+	if _, ok := m.roles[userID]; !ok {
+		return repositories.ErrUserNotFound
+	}
+	m.roles[userID] = role
+	m.changedBy[userID] = actorID
+	return nil
+}
+
+func (m *memoryRoleRepo) BootstrapAdmin(email string) (bool, error) {
+	for _, role := range m.roles {
+		if role == models.RoleAdmin {
+			return false, nil
+		}
+	}
+	id, ok := m.emails[email]
+	if !ok {
+		return false, repositories.ErrUserNotFound
+	}
+	m.roles[id] = models.RoleAdmin
+	return true, nil
+}
+
+func TestRoleService_ChangeRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int
+		userID  int
+		role    string
+		wantErr error
+	}{
+		{"promote to moderator", 1, 2, models.RoleModerator, nil},
+		{"unknown role", 1, 2, "owner", ErrInvalidRole},
+		{"own role", 1, 1, models.RoleUser, ErrCannotChangeOwnRole},
+		{"missing user", 1, 99, models.RoleModerator, repositories.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRoleRepo()
+			svc := NewRoleService(repo)
+			err := svc.ChangeRole(tt.actorID, tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && (repo.roles[tt.userID] != tt.role || repo.changedBy[tt.userID] != tt.actorID) {
+				t.Fatalf("expected role %q set by %d, got %q by %d", tt.role, tt.actorID, repo.roles[tt.userID], repo.changedBy[tt.userID])
+			}
+		})
+	}
+}
+
+func TestRoleService_BootstrapAdmin(t *testing.T) {
+	repo := newMemoryRoleRepo()
+	repo.roles[1] = models.RoleUser
+	svc := NewRoleService(repo)
+
+	// 未登録のメールアドレスはエラーにせず、登録後の次回起動で昇格させる
+	if err := svc.BootstrapAdmin("later@example.com"); err != nil {
+		t.Fatalf("expected missing user to be ignored, got %v", err)
+	}
+	if err := svc.BootstrapAdmin("user@example.com"); err != nil {
+		t.Fatalf("BootstrapAdmin failed: %v", err)
+	}
+	if repo.roles[2] != models.RoleAdmin {
+		t.Fatalf("expected user 2 to be admin, got %q", repo.roles[2])
+	}
+}
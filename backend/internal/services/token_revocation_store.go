@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+const (
+	// tokenRevocationCacheTTL は「無効化されていない」という確認結果・ユーザーの状態をキャッシュする期間
+	// このインスタンスでの無効化はすぐに反映され、他のインスタンスでの無効化はこの期間内に反映される
+	tokenRevocationCacheTTL = 30 * time.Second
+	// tokenRevocationCleanupInterval は有効期限切れの無効化の記録とキャッシュを削除する間隔
+	tokenRevocationCleanupInterval = 15 * time.Minute
+)
+
+type cachedRevocation struct {
+	revoked bool
+	// expiresAt はキャッシュの有効期限（無効化済みの場合はトークンの有効期限）
+	expiresAt time.Time
+}
+
+// cachedTokenUser はアクセストークンの確認に使うユーザーの状態（トークンバージョン・利用停止）のキャッシュ
+type cachedTokenUser struct {
+	user      *models.User
+	expiresAt time.Time
+}
+
+// TokenRevocationStore はアクセストークンの無効化を DB に記録し、確認結果とユーザーの状態をメモリにキャッシュする
+// アクセストークンは認証が必要なリクエストごとに確認するため、DB への問い合わせをキャッシュで減らす
+type TokenRevocationStore struct {
+	repo repositories.TokenRevocationRepository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedRevocation
+	users map[int]cachedTokenUser
+	// userGeneration は ForgetUser のたびに進み、読み込み中に破棄されたユーザーの古い状態をキャッシュしないために使う
+	userGeneration uint64
+}
+
+// NewTokenRevocationStore は新しい TokenRevocationStore を作成する
+func NewTokenRevocationStore(repo repositories.TokenRevocationRepository) *TokenRevocationStore {
+	return &TokenRevocationStore{
+		repo:  repo,
+		ttl:   tokenRevocationCacheTTL,
+		now:   time.Now,
+		cache: make(map[string]cachedRevocation),
+		users: make(map[int]cachedTokenUser),
+	}
+}
+
+// Revoke は jti のアクセストークンを有効期限 expiresAt まで無効にする
+func (s *TokenRevocationStore) Revoke(jti string, userID int, expiresAt time.Time) error {
+	if err := s.repo.RevokeAccessToken(jti, userID, expiresAt); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.cache[jti] = cachedRevocation{revoked: true, expiresAt: expiresAt}
+	s.mu.Unlock()
+	return nil
+}
+
+// IsRevoked は有効期限 expiresAt の jti のアクセストークンが無効化されているかを返す
+func (s *TokenRevocationStore) IsRevoked(jti string, expiresAt time.Time) (bool, error) {
+	now := s.now()
+	s.mu.Lock()
+	entry, ok := s.cache[jti]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := s.repo.IsAccessTokenRevoked(jti)
+	if err != nil {
+		return false, err
+	}
+	cacheUntil := expiresAt
+	if !revoked && now.Add(s.ttl).Before(cacheUntil) {
+		cacheUntil = now.Add(s.ttl)
+	}
+	s.mu.Lock()
+	s.cache[jti] = cachedRevocation{revoked: revoked, expiresAt: cacheUntil}
+	s.mu.Unlock()
+	return revoked, nil
+}
+
+// Consume は一度しか使えないトークン jti を有効期限 expiresAt まで無効にし、初めて使われた場合に true を返す
+// 使用済み・無効化済みの場合は false を返す。IsRevoked で確認してから Revoke する場合と異なり、並行するリクエストのうち1つだけが true になる
+func (s *TokenRevocationStore) Consume(jti string, userID int, expiresAt time.Time) (bool, error) {
+	now := s.now()
+	s.mu.Lock()
+	entry, ok := s.cache[jti]
+	s.mu.Unlock()
+	if ok && entry.revoked && now.Before(entry.expiresAt) {
+		return false, nil
+	}
+
+	consumed, err := s.repo.ConsumeToken(jti, userID, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	s.cache[jti] = cachedRevocation{revoked: true, expiresAt: expiresAt}
+	s.mu.Unlock()
+	return consumed, nil
+}
+
+// RevokeAll はユーザーのトークンバージョンを進め、それまでに発行したアクセストークンをすべて無効にする
+func (s *TokenRevocationStore) RevokeAll(userID int) error {
+	if err := s.repo.IncrementTokenVersion(userID); err != nil {
+		return err
+	}
+	s.ForgetUser(userID)
+	return nil
+}
+
+// cachedUser はキャッシュしたユーザーの状態と、cacheUser に渡す世代を返す
+func (s *TokenRevocationStore) cachedUser(userID int) (*models.User, uint64, bool) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.users[userID]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, s.userGeneration, false
+	}
+	return entry.user, s.userGeneration, true
+}
+
+// cacheUser はユーザーの状態を ttl の間キャッシュする
+// cachedUser で世代を取得した後に ForgetUser された場合は、読み込んだ状態が古いおそれがあるためキャッシュしない
+func (s *TokenRevocationStore) cacheUser(user *models.User, generation uint64) {
+	state := &models.User{
+		ID:               user.ID,
+		TokenVersion:     user.TokenVersion,
+		SuspendedAt:      user.SuspendedAt,
+		SuspendedUntil:   user.SuspendedUntil,
+		SuspensionReason: user.SuspensionReason,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if generation != s.userGeneration {
+		return
+	}
+	s.users[user.ID] = cachedTokenUser{user: state, expiresAt: s.now().Add(s.ttl)}
+}
+
+// ForgetUser はキャッシュしたユーザーの状態を破棄する
+// 利用停止・トークンバージョンの変更をこのインスタンスのアクセストークンの確認にすぐ反映させるために呼び出す
+// 他のインスタンスでの変更は、キャッシュの有効期間内に反映される
+func (s *TokenRevocationStore) ForgetUser(userID int) {
+	s.mu.Lock()
+	delete(s.users, userID)
+	s.userGeneration++
+	s.mu.Unlock()
+}
+
+// Cleanup は有効期限切れの無効化の記録とキャッシュを削除する
+func (s *TokenRevocationStore) Cleanup() error {
+	now := s.now()
+	s.mu.Lock()
+	for jti, entry := range s.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(s.cache, jti)
+		}
+	}
+	for userID, entry := range s.users {
+		if !now.Before(entry.expiresAt) {
+			delete(s.users, userID)
+		}
+	}
+	s.mu.Unlock()
+	_, err := s.repo.DeleteExpiredRevocations(now)
+	return err
+}
+
+// Run は ctx がキャンセルされるまで定期的に Cleanup を実行する
+func (s *TokenRevocationStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(tokenRevocationCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Cleanup(); err != nil {
+				logging.L.Error("failed to clean up token revocations", "service", "TokenRevocationStore", "error", err)
+			}
+		}
+	}
+}
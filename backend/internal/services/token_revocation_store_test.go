@@ -0,0 +1,152 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/repositories"
+)
+
+// memoryTokenRevocationRepo はテスト用のインメモリ TokenRevocationRepository
+type memoryTokenRevocationRepo struct {
+	revoked  map[string]time.Time
+	queries  int
+	versions map[int]int
+}
+
+func newMemoryTokenRevocationRepo() *memoryTokenRevocationRepo {
+	return &memoryTokenRevocationRepo{
+		revoked:  make(map[string]time.Time),
+		versions: make(map[int]int),
+	}
+}
+
+func (m *memoryTokenRevocationRepo) RevokeAccessToken(jti string, userID int, expiresAt time.Time) error {
+	m.revoked[jti] = expiresAt
+	return nil
+}
+
+func (m *memoryTokenRevocationRepo) IsAccessTokenRevoked(jti string) (bool, error) {
+	m.queries++
+	_, ok := m.revoked[jti]
+	return ok, nil
+}
+
+func (m *memoryTokenRevocationRepo) ConsumeToken(jti string, userID int, expiresAt time.Time) (bool, error) {
+	if _, ok := m.revoked[jti]; ok {
+		return false, nil
+	}
+	m.revoked[jti] = expiresAt
+	return true, nil
+}
+
+func (m *memoryTokenRevocationRepo) IncrementTokenVersion(userID int) error {
+	if _, ok := m.versions[userID]; !ok {
+		return repositories.ErrUserNotFound
+	}
+	m.versions[userID]++
+	return nil
+}
+
+func (m *memoryTokenRevocationRepo) DeleteExpiredRevocations(before time.Time) (int64, error) {
+	var deleted int64
+	for jti, expiresAt := range m.revoked {
+		if expiresAt.Before(before) {
+			delete(m.revoked, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestTokenRevocationStore_CachesLookups(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newMemoryTokenRevocationRepo()
+	store := NewTokenRevocationStore(repo)
+	store.now = func() time.Time { return now }
+	expiresAt := now.Add(15 * time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if revoked, err := store.IsRevoked("jti-1", expiresAt); err != nil || revoked {
+			t.Fatalf("expected not revoked, got %v (err=%v)", revoked, err)
+		}
+	}
+	if repo.queries != 1 {
+		t.Fatalf("expected 1 query thanks to cache, got %d", repo.queries)
+	}
+
+	// このインスタンスでの無効化はキャッシュにもすぐ反映される
+	if err := store.Revoke("jti-1", 1, expiresAt); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if revoked, _ := store.IsRevoked("jti-1", expiresAt); !revoked {
+		t.Fatal("expected revoked immediately after Revoke")
+	}
+
+	// 他のインスタンスでの無効化はキャッシュの有効期間を過ぎると反映される
+	repo.revoked["jti-2"] = expiresAt
+	store.cache["jti-2"] = cachedRevocation{revoked: false, expiresAt: now.Add(store.ttl)}
+	if revoked, _ := store.IsRevoked("jti-2", expiresAt); revoked {
+		t.Fatal("expected cached result before TTL")
+	}
+	now = now.Add(store.ttl)
+	if revoked, _ := store.IsRevoked("jti-2", expiresAt); !revoked {
+		t.Fatal("expected revocation to be visible after TTL")
+	}
+}
+
+func TestTokenRevocationStore_Consume(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newMemoryTokenRevocationRepo()
+	store := NewTokenRevocationStore(repo)
+	store.now = func() time.Time { return now }
+	expiresAt := now.Add(5 * time.Minute)
+
+	// 確認の結果がキャッシュされていても、使用済みかは DB への記録で判定する
+	if revoked, _ := store.IsRevoked("jti-1", expiresAt); revoked {
+		t.Fatal("expected not revoked before consume")
+	}
+	if consumed, err := store.Consume("jti-1", 1, expiresAt); err != nil || !consumed {
+		t.Fatalf("expected first consume to succeed, got %v (err=%v)", consumed, err)
+	}
+	if consumed, err := store.Consume("jti-1", 1, expiresAt); err != nil || consumed {
+		t.Fatalf("expected second consume to fail, got %v (err=%v)", consumed, err)
+	}
+	if revoked, _ := store.IsRevoked("jti-1", expiresAt); !revoked {
+		t.Fatal("expected consumed token to be revoked")
+	}
+
+	// 他のインスタンスで使われたトークンは、このインスタンスのキャッシュによらず使えない
+	repo.revoked["jti-2"] = expiresAt
+	store.cache["jti-2"] = cachedRevocation{revoked: false, expiresAt: now.Add(store.ttl)}
+	if consumed, err := store.Consume("jti-2", 1, expiresAt); err != nil || consumed {
+		t.Fatalf("expected token used on another instance to be rejected, got %v (err=%v)", consumed, err)
+	}
+}
+
+func TestTokenRevocationStore_Cleanup(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newMemoryTokenRevocationRepo()
+	store := NewTokenRevocationStore(repo)
+	store.now = func() time.Time { return now }
+
+	if err := store.Revoke("expired", 1, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if err := store.Revoke("active", 1, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	now = now.Add(30 * time.Minute)
+	if err := store.Cleanup(); err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if _, ok := store.cache["expired"]; ok {
+		t.Fatal("expected expired cache entry to be removed")
+	}
+	if _, ok := repo.revoked["expired"]; ok {
+		t.Fatal("expected expired revocation to be deleted")
+	}
+	if _, ok := repo.revoked["active"]; !ok {
+		t.Fatal("expected active revocation to remain")
+	}
+}
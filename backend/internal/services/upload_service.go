@@ -286,30 +286,72 @@ func (s *UploadService) UploadProfileImage(userID int, file *multipart.FileHeade
 		return "", fmt.Errorf("プロフィール画像の保存に失敗しました")
 	}
 
-	// DB置き換え成功後、旧プロフィール画像のディスクファイルを削除（ベストエフォート）
-	// filepath.Base でファイル名のみを抽出することで、DB値に不正なパスが含まれていても
-	// profileUploadDir 配下にしか削除が及ばない（パストラバーサル防止）
-	for _, oldPath := range oldPaths {
+	// 旧プロフィール画像のディスクファイルは、同じトランザクションで書き込まれた
+	// ProfileImageReplaced イベントの購読者（handleProfileImageReplaced）が削除する
+	s.logger.Info("プロフィール画像を置き換え",
+		"user_id", userID,
+		"replaced_count", len(oldPaths))
+
+	return pending.uploadRecord.FilePath, nil
+}
+
+// ドメインイベントの購読者名
+const (
+	uploadMarkUsedHandlerName           = "upload.mark_used"
+	uploadRemoveProfileImageHandlerName = "upload.remove_replaced_profile_images"
+)
+
+// RegisterEventHandlers は画像の状態を更新するドメインイベントの購読者を登録する
+func (s *UploadService) RegisterEventHandlers(subscriber DomainEventSubscriber) {
+	subscriber.Subscribe(models.DomainEventPostCreated, uploadMarkUsedHandlerName, s.handlePostCreated)
+	subscriber.Subscribe(models.DomainEventProfileImageReplaced, uploadRemoveProfileImageHandlerName, s.handleProfileImageReplaced)
+}
+
+// handlePostCreated は投稿で使用した画像のステータスを "used" に更新する
+// アップロード記録が存在しない画像は再試行しても解決しないため、ログのみ出力して読み飛ばす
+func (s *UploadService) handlePostCreated(event *models.DomainEvent) error {
+	var e models.PostCreatedEvent
+	if err := event.Decode(&e); err != nil {
+		return err
+	}
+	for _, imageURL := range e.ImageURLs {
+		if err := s.uploadRepo.MarkAsUsed(imageURL); err != nil {
+			if errors.Is(err, repositories.ErrUploadNotFound) {
+				s.logger.Warn("画像ステータス更新対象のアップロードが存在しない",
+					"post_id", e.PostID,
+					"image_url", imageURL)
+				continue
+			}
+			return fmt.Errorf("画像ステータスの更新に失敗しました: %w", err)
+		}
+	}
+	return nil
+}
+
+// handleProfileImageReplaced は置き換えられた旧プロフィール画像のディスクファイルを削除する
+// filepath.Base でファイル名のみを抽出することで、DB値に不正なパスが含まれていても
+// profileUploadDir 配下にしか削除が及ばない（パストラバーサル防止）
+func (s *UploadService) handleProfileImageReplaced(event *models.DomainEvent) error {
+	var e models.ProfileImageReplacedEvent
+	if err := event.Decode(&e); err != nil {
+		return err
+	}
+	for _, oldPath := range e.OldFilePaths {
 		baseName := filepath.Base(oldPath)
-		if baseName == "." || baseName == ".." {
+		if baseName == "." || baseName == ".." || baseName == string(filepath.Separator) {
 			s.logger.Warn("不正なベースネームをスキップ（パストラバーサル防止）",
-				"user_id", userID,
+				"user_id", e.UserID,
 				"file_path", oldPath)
 			continue
 		}
 		diskPath := filepath.Join(profileUploadDir, baseName)
-		if removeErr := os.Remove(diskPath); removeErr != nil {
-			s.logger.Warn("既存プロフィール画像削除失敗",
-				"user_id", userID,
-				"path", diskPath,
-				"error", removeErr)
-		} else {
-			s.logger.Info("既存プロフィール画像を削除（置き換え）",
-				"user_id", userID,
-				"old_path", oldPath)
+		// 再試行時に削除済みのファイルは成功として扱う
+		if err := os.Remove(diskPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("既存プロフィール画像の削除に失敗しました: %w", err)
 		}
+		s.logger.Info("既存プロフィール画像を削除（置き換え）",
+			"user_id", e.UserID,
+			"old_path", oldPath)
 	}
-
-	return pending.uploadRecord.FilePath, nil
+	return nil
 }
-
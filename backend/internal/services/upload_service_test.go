@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -380,3 +381,68 @@ func TestUploadService_UploadProfileImage(t *testing.T) {
 		assert.Equal(t, beforeCount, len(afterFiles), "DB失敗時にディスクファイルがロールバックされていること")
 	})
 }
+
+func TestUploadService_HandlePostCreated(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	event := &models.DomainEvent{
+		ID:      1,
+		Type:    models.DomainEventPostCreated,
+		Payload: []byte(`{"post_id":1,"user_id":1,"image_urls":["/images/a.jpg","/images/missing.jpg","/images/b.jpg"]}`),
+	}
+
+	t.Run("正常系_使用した画像を used に更新（記録のない画像は読み飛ばす）", func(t *testing.T) {
+		mockRepo := new(MockUploadRepository)
+		service := NewUploadService(mockRepo, logger)
+		bus := NewDomainEventBus()
+		service.RegisterEventHandlers(bus)
+		mockRepo.On("MarkAsUsed", "/images/a.jpg").Return(nil).Once()
+		mockRepo.On("MarkAsUsed", "/images/missing.jpg").Return(repositories.ErrUploadNotFound).Once()
+		mockRepo.On("MarkAsUsed", "/images/b.jpg").Return(nil).Once()
+
+		err := bus.Deliver(event)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系_DBエラーは再試行のためエラーを返す", func(t *testing.T) {
+		mockRepo := new(MockUploadRepository)
+		service := NewUploadService(mockRepo, logger)
+		mockRepo.On("MarkAsUsed", "/images/a.jpg").Return(errors.New("DB error")).Once()
+
+		err := service.handlePostCreated(event)
+
+		assert.Error(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUploadService_HandleProfileImageReplaced(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	defer func() {
+		_ = os.RemoveAll("public/images")
+	}()
+	assert.NoError(t, os.MkdirAll(profileUploadDir, 0755))
+	oldFile := filepath.Join(profileUploadDir, "old.jpg")
+	assert.NoError(t, os.WriteFile(oldFile, []byte("old"), 0644))
+	outside := filepath.Join("public", "images", "outside.jpg")
+	assert.NoError(t, os.WriteFile(outside, []byte("outside"), 0644))
+
+	service := NewUploadService(new(MockUploadRepository), logger)
+	event := &models.DomainEvent{
+		ID:      1,
+		Type:    models.DomainEventProfileImageReplaced,
+		Payload: []byte(`{"user_id":1,"file_path":"/images/profiles/new.jpg","old_file_paths":["/images/profiles/old.jpg","/images/profiles/gone.jpg","/images/profiles/../outside.jpg"]}`),
+	}
+
+	// 存在しないファイルは削除済みとして成功扱いにする
+	assert.NoError(t, service.handleProfileImageReplaced(event))
+	_, err := os.Stat(oldFile)
+	assert.True(t, os.IsNotExist(err), "旧プロフィール画像が削除されていること")
+	// パスに .. を含んでもファイル名のみを使うため profileUploadDir の外は削除しない
+	_, err = os.Stat(outside)
+	assert.NoError(t, err)
+
+	// 再試行されても成功する
+	assert.NoError(t, service.handleProfileImageReplaced(event))
+}
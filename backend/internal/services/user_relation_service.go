@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// ErrCannotRelateToSelf は自分自身をブロック・ミュートしようとした場合のエラー
+var ErrCannotRelateToSelf = errors.New("自分自身はブロック・ミュートできません")
+
+// UserRelationService はユーザーのブロック・ミュートを扱う
+// ミュートしたユーザーの投稿はタイムラインから除外され、ブロックしたユーザーは自分の投稿にいいね・リミックスできなくなる
+type UserRelationService struct {
+	relationRepo repositories.UserRelationRepository
+	userRepo     repositories.UserRepository
+}
+
+// NewUserRelationService は新しい UserRelationService を作成する
+func NewUserRelationService(relationRepo repositories.UserRelationRepository, userRepo repositories.UserRepository) *UserRelationService {
+	return &UserRelationService{
+		relationRepo: relationRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// requireTarget はブロック・ミュートの相手が自分以外の存在するユーザーであることを確認する
+func (s *UserRelationService) requireTarget(userID, targetID int) error {
+	if userID == targetID {
+		return ErrCannotRelateToSelf
+	}
+	_, err := s.userRepo.GetByID(targetID)
+	return err
+}
+
+// Block は userID が targetID をブロックする
+// すでにブロックしている場合は repositories.ErrAlreadyBlocked、相手が存在しない場合は repositories.ErrUserNotFound を返す
+func (s *UserRelationService) Block(userID, targetID int) error {
+	if err := s.requireTarget(userID, targetID); err != nil {
+		return err
+	}
+	return s.relationRepo.Block(userID, targetID)
+}
+
+// Unblock は userID による targetID のブロックを解除する
+func (s *UserRelationService) Unblock(userID, targetID int) error {
+	return s.relationRepo.Unblock(userID, targetID)
+}
+
+// ListBlocked は userID がブロックしたユーザーを新しい順に返す
+func (s *UserRelationService) ListBlocked(userID int) ([]models.RelatedUser, error) {
+	return s.relationRepo.ListBlocked(userID)
+}
+
+// Mute は userID が targetID をミュートする
+// すでにミュートしている場合は repositories.ErrAlreadyMuted、相手が存在しない場合は repositories.ErrUserNotFound を返す
+func (s *UserRelationService) Mute(userID, targetID int) error {
+	if err := s.requireTarget(userID, targetID); err != nil {
+		return err
+	}
+	return s.relationRepo.Mute(userID, targetID)
+}
+
+// Unmute は userID による targetID のミュートを解除する
+func (s *UserRelationService) Unmute(userID, targetID int) error {
+	return s.relationRepo.Unmute(userID, targetID)
+}
+
+// ListMuted は userID がミュートしたユーザーを新しい順に返す
+func (s *UserRelationService) ListMuted(userID int) ([]models.RelatedUser, error) {
+	return s.relationRepo.ListMuted(userID)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"go-shisha-backend/internal/models"
+)
+
+// recordingRelationRepo はブロック・ミュートの呼び出しを記録するモック
+type recordingRelationRepo struct {
+	blocked [][2]int
+	muted   [][2]int
+}
+
+func (m *recordingRelationRepo) Block(blockerID, blockedID int) error {
+	m.blocked = append(m.blocked, [2]int{blockerID, blockedID})
+	return nil
+}
+func (m *recordingRelationRepo) Unblock(blockerID, blockedID int) error { return nil }
+func (m *recordingRelationRepo) ListBlocked(userID int) ([]models.RelatedUser, error) {
+	return []models.RelatedUser{}, nil
+}
+func (m *recordingRelationRepo) Mute(muterID, mutedID int) error {
+	m.muted = append(m.muted, [2]int{muterID, mutedID})
+	return nil
+}
+func (m *recordingRelationRepo) Unmute(muterID, mutedID int) error { return nil }
+func (m *recordingRelationRepo) ListMuted(userID int) ([]models.RelatedUser, error) {
+	return []models.RelatedUser{}, nil
+}
+
+func TestUserRelation_RejectsSelfAndMissingUser(t *testing.T) {
+	repo := &recordingRelationRepo{}
+
+	svc := NewUserRelationService(repo, &mockUserRepoForPost{})
+	if err := svc.Block(1, 1); !errors.Is(err, ErrCannotRelateToSelf) {
+		t.Fatalf("expected ErrCannotRelateToSelf for block, got %v", err)
+	}
+	if err := svc.Mute(1, 1); !errors.Is(err, ErrCannotRelateToSelf) {
+		t.Fatalf("expected ErrCannotRelateToSelf for mute, got %v", err)
+	}
+	if err := svc.Block(1, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Mute(1, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	missing := NewUserRelationService(repo, &mockUserRepoMissing{})
+	if err := missing.Block(1, 99); err == nil {
+		t.Fatalf("expected error for missing user")
+	}
+	if len(repo.blocked) != 1 || repo.blocked[0] != [2]int{1, 2} || len(repo.muted) != 1 || repo.muted[0] != [2]int{1, 3} {
+		t.Fatalf("unexpected calls: blocked=%v muted=%v", repo.blocked, repo.muted)
+	}
+}
@@ -30,19 +30,25 @@ func (m *mockUserRepo) Update(id int, input models.UpdateUserInput) (*models.Use
 
 type noopPostRepo struct{}
 
-func (n *noopPostRepo) GetAll(userID *int) ([]models.Post, error)         { return nil, nil }
+func (n *noopPostRepo) GetAll(userID *int, filter models.PostFilter) ([]models.Post, error) {
+	return nil, nil
+}
 func (n *noopPostRepo) GetByID(id int, userID *int) (*models.Post, error) { return nil, nil }
+func (n *noopPostRepo) GetByIDs(ids []int, userID *int) ([]models.Post, error) {
+	return nil, nil
+}
 func (n *noopPostRepo) GetByUserID(userID int, currentUserID *int) ([]models.Post, error) {
 	return nil, nil
 }
-func (n *noopPostRepo) Create(post *models.Post) error              { return nil }
-func (n *noopPostRepo) IncrementLikes(id int) (*models.Post, error) { return nil, nil }
-func (n *noopPostRepo) DecrementLikes(id int) (*models.Post, error) { return nil, nil }
-func (n *noopPostRepo) AddLike(userID, postID int) error            { return nil }
-func (n *noopPostRepo) RemoveLike(userID, postID int) error         { return nil }
-func (n *noopPostRepo) HasLiked(userID, postID int) (bool, error)   { return false, nil }
-func (n *noopPostRepo) DeletePost(userID, postID int) error         { return nil }
-func (n *noopPostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput) (*models.Post, error) {
+func (n *noopPostRepo) Create(post *models.Post) error                 { return nil }
+func (n *noopPostRepo) IncrementLikes(id int) (*models.Post, error)    { return nil, nil }
+func (n *noopPostRepo) DecrementLikes(id int) (*models.Post, error)    { return nil, nil }
+func (n *noopPostRepo) AddLike(userID, postID int) error               { return nil }
+func (n *noopPostRepo) RemoveLike(userID, postID int) error            { return nil }
+func (n *noopPostRepo) HasLiked(userID, postID int) (bool, error)      { return false, nil }
+func (n *noopPostRepo) IsVisibleTo(postID, viewerID int) (bool, error) { return true, nil }
+func (n *noopPostRepo) DeletePost(userID, postID int) error            { return nil }
+func (n *noopPostRepo) UpdatePost(userID, postID int, slides []models.UpdateSlideInput, session *models.ShishaSession) (*models.Post, error) {
 	return nil, nil
 }
 
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+// userStatsCacheTTL はユーザー統計のキャッシュ有効期間
+// 投稿・いいね時には Invalidate で即時破棄されるため、TTL は取りこぼしに対する保険として扱う
+const userStatsCacheTTL = 10 * time.Minute
+
+// userStatsCacheMaxEntries はキャッシュするユーザー数の上限
+const userStatsCacheMaxEntries = 10000
+
+// UserStatsInvalidator はユーザー統計のキャッシュを破棄するインターフェース
+// 投稿やいいねによって統計が変化したときに PostService から呼び出される
+type UserStatsInvalidator interface {
+	Invalidate(userID int)
+}
+
+type cachedUserStats struct {
+	stats     *models.UserStats
+	expiresAt time.Time
+}
+
+// userStatsFill は集計中のユーザーの状態
+// 集計中に Invalidate されると generation が進み、集計前の状態にもとづく結果はキャッシュしない
+type userStatsFill struct {
+	generation uint64
+	// pending は同じユーザーの集計中のリクエスト数（0 になったら破棄する）
+	pending int
+}
+
+// UserStatsService はユーザー統計の取得とキャッシュを扱う
+type UserStatsService struct {
+	statsRepo  repositories.UserStatsRepository
+	userRepo   repositories.UserRepository
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[int]cachedUserStats
+	fills map[int]*userStatsFill
+}
+
+// NewUserStatsService は新しい UserStatsService を作成する
+func NewUserStatsService(statsRepo repositories.UserStatsRepository, userRepo repositories.UserRepository) *UserStatsService {
+	return &UserStatsService{
+		statsRepo:  statsRepo,
+		userRepo:   userRepo,
+		ttl:        userStatsCacheTTL,
+		maxEntries: userStatsCacheMaxEntries,
+		now:        time.Now,
+		cache:      make(map[int]cachedUserStats),
+		fills:      make(map[int]*userStatsFill),
+	}
+}
+
+// GetUserStats は指定ユーザーの統計を返す
+// キャッシュが有効な場合は集計を行わずキャッシュを返す
+// ユーザーが存在しない場合は repositories.ErrUserNotFound を返す
+func (s *UserStatsService) GetUserStats(userID int) (*models.UserStats, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	entry, ok := s.cache[userID]
+	if ok && now.Before(entry.expiresAt) {
+		s.mu.Unlock()
+		logging.L.Debug("user stats cache hit", "service", "UserStatsService", "method", "GetUserStats", "user_id", userID)
+		return entry.stats, nil
+	}
+	fill, ok := s.fills[userID]
+	if !ok {
+		fill = &userStatsFill{}
+		s.fills[userID] = fill
+	}
+	fill.pending++
+	generation := fill.generation
+	s.mu.Unlock()
+
+	stats, err := s.statsRepo.GetUserStats(userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fill.pending--
+	if fill.pending == 0 {
+		delete(s.fills, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fill.generation != generation {
+		// 集計中に投稿・いいねで統計が変化したため、古いおそれのある結果はキャッシュしない
+		logging.L.Debug("user stats invalidated during aggregation", "service", "UserStatsService", "method", "GetUserStats", "user_id", userID)
+		return stats, nil
+	}
+	s.store(userID, cachedUserStats{stats: stats, expiresAt: now.Add(s.ttl)}, now)
+	return stats, nil
+}
+
+// store はキャッシュに統計を保存する（s.mu を保持した状態で呼び出す）
+// 上限に達している場合は期限切れのエントリを削除し、それでも空きがなければ任意の1件を削除する
+func (s *UserStatsService) store(userID int, entry cachedUserStats, now time.Time) {
+	if _, ok := s.cache[userID]; !ok && len(s.cache) >= s.maxEntries {
+		for id, e := range s.cache {
+			if !now.Before(e.expiresAt) {
+				delete(s.cache, id)
+			}
+		}
+		if len(s.cache) >= s.maxEntries {
+			for id := range s.cache {
+				delete(s.cache, id)
+				break
+			}
+		}
+	}
+	s.cache[userID] = entry
+}
+
+// Invalidate は指定ユーザーの統計キャッシュを破棄し、次回取得時に再集計させる
+// 集計中のリクエストがある場合は、その結果もキャッシュさせない
+func (s *UserStatsService) Invalidate(userID int) {
+	s.mu.Lock()
+	delete(s.cache, userID)
+	if fill, ok := s.fills[userID]; ok {
+		fill.generation++
+	}
+	s.mu.Unlock()
+	logging.L.Debug("user stats cache invalidated", "service", "UserStatsService", "method", "Invalidate", "user_id", userID)
+}
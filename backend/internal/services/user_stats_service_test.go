@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// countingStatsRepo は集計の呼び出し回数を記録するモック
+type countingStatsRepo struct {
+	calls int
+	err   error
+	// onQuery は集計中に呼び出される（集計と並行した操作の再現用）
+	onQuery func()
+}
+
+func (m *countingStatsRepo) GetUserStats(userID int) (*models.UserStats, error) {
+	m.calls++
+	if m.onQuery != nil {
+		m.onQuery()
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.UserStats{UserID: userID, TotalPosts: m.calls}, nil
+}
+
+func TestGetUserStats_CachesUntilInvalidated(t *testing.T) {
+	repo := &countingStatsRepo{}
+	svc := NewUserStatsService(repo, &mockUserRepoForPost{})
+
+	first, err := svc.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := svc.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 1 || first != second {
+		t.Fatalf("expected cached stats to be reused, calls=%d", repo.calls)
+	}
+
+	// 他ユーザーのキャッシュ破棄は影響しない
+	svc.Invalidate(2)
+	if _, err := svc.GetUserStats(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 1 {
+		t.Fatalf("expected cache to be kept, calls=%d", repo.calls)
+	}
+
+	svc.Invalidate(1)
+	third, err := svc.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 2 || third.TotalPosts != 2 {
+		t.Fatalf("expected stats to be re-aggregated after invalidation, calls=%d", repo.calls)
+	}
+}
+
+func TestGetUserStats_CacheExpires(t *testing.T) {
+	repo := &countingStatsRepo{}
+	svc := NewUserStatsService(repo, &mockUserRepoForPost{})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	if _, err := svc.GetUserStats(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = now.Add(userStatsCacheTTL + time.Second)
+	if _, err := svc.GetUserStats(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 2 {
+		t.Fatalf("expected stats to be re-aggregated after TTL, calls=%d", repo.calls)
+	}
+}
+
+func TestGetUserStats_UserMissing(t *testing.T) {
+	repo := &countingStatsRepo{}
+	svc := NewUserStatsService(repo, &mockUserRepoMissing{})
+
+	if _, err := svc.GetUserStats(999); err == nil {
+		t.Fatal("expected error when user is missing, got nil")
+	}
+	if repo.calls != 0 {
+		t.Fatalf("expected no aggregation for missing user, calls=%d", repo.calls)
+	}
+}
+
+func TestGetUserStats_ErrorNotCached(t *testing.T) {
+	repo := &countingStatsRepo{err: errors.New("db error")}
+	svc := NewUserStatsService(repo, &mockUserRepoForPost{})
+
+	if _, err := svc.GetUserStats(1); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	repo.err = nil
+	if _, err := svc.GetUserStats(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 2 {
+		t.Fatalf("expected failed aggregation not to be cached, calls=%d", repo.calls)
+	}
+}
+
+func TestGetUserStats_InvalidatedDuringAggregationNotCached(t *testing.T) {
+	repo := &countingStatsRepo{}
+	svc := NewUserStatsService(repo, &mockUserRepoForPost{})
+	repo.onQuery = func() {
+		// 集計中に投稿・いいねでキャッシュが破棄される
+		repo.onQuery = nil
+		svc.Invalidate(1)
+	}
+
+	if _, err := svc.GetUserStats(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := svc.GetUserStats(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 2 || second.TotalPosts != 2 {
+		t.Fatalf("expected stats aggregated before invalidation not to be cached, calls=%d", repo.calls)
+	}
+	if _, err := svc.GetUserStats(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 2 {
+		t.Fatalf("expected stats to be cached after a clean aggregation, calls=%d", repo.calls)
+	}
+	if len(svc.fills) != 0 {
+		t.Fatalf("expected no pending aggregations, got %d", len(svc.fills))
+	}
+}
+
+func TestGetUserStats_CacheSizeIsBounded(t *testing.T) {
+	repo := &countingStatsRepo{}
+	svc := NewUserStatsService(repo, &mockUserRepoForPost{})
+	svc.maxEntries = 2
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	for _, userID := range []int{1, 2} {
+		if _, err := svc.GetUserStats(userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// 期限切れのエントリから削除する
+	now = now.Add(userStatsCacheTTL + time.Second)
+	if _, err := svc.GetUserStats(3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(svc.cache) != 1 {
+		t.Fatalf("expected expired entries to be evicted, got %d entries", len(svc.cache))
+	}
+	// 期限切れのエントリがなくても上限を超えない
+	for _, userID := range []int{4, 5} {
+		if _, err := svc.GetUserStats(userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(svc.cache) != 2 {
+		t.Fatalf("expected cache to be capped at 2 entries, got %d", len(svc.cache))
+	}
+}
+
+// recordingInvalidator は統計キャッシュ破棄の対象ユーザーを記録するモック
+type recordingInvalidator struct {
+	userIDs []int
+}
+
+func (r *recordingInvalidator) Invalidate(userID int) {
+	r.userIDs = append(r.userIDs, userID)
+}
+
+func TestPostService_InvalidatesStats(t *testing.T) {
+	inv := &recordingInvalidator{}
+	postSvc := NewPostService(&mockPostRepo{}, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{}, inv, nopNotifier{}, nopStreamPublisher{})
+
+	input := &models.CreatePostInput{Slides: []models.SlideInput{{ImageURL: "/images/test.jpg", Text: "hello"}}}
+	if _, err := postSvc.CreatePost(1, input); err != nil {
+		t.Fatalf("CreatePost failed: %v", err)
+	}
+	if err := postSvc.DeletePost(1, 10); err != nil {
+		t.Fatalf("DeletePost failed: %v", err)
+	}
+	if len(inv.userIDs) != 2 || inv.userIDs[0] != 1 || inv.userIDs[1] != 1 {
+		t.Fatalf("expected stats of user 1 to be invalidated twice, got %v", inv.userIDs)
+	}
+}
+
+func TestPostService_LikeInvalidatesOwnerStats(t *testing.T) {
+	inv := &recordingInvalidator{}
+	repo := &remixSourcePostRepo{source: &models.Post{ID: 5, UserID: 2}}
+	postSvc := NewPostService(repo, &mockUserRepoForPost{}, &mockFlavorRepo{}, &mockUploadRepo{}, inv, nopNotifier{}, nopStreamPublisher{})
+
+	if _, err := postSvc.LikePost(1, 5); err != nil {
+		t.Fatalf("LikePost failed: %v", err)
+	}
+	// いいねした本人ではなく投稿者の統計が破棄される
+	if len(inv.userIDs) != 1 || inv.userIDs[0] != 2 {
+		t.Fatalf("expected stats of post owner to be invalidated, got %v", inv.userIDs)
+	}
+}
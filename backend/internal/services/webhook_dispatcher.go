@@ -0,0 +1,324 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+// Webhook の送信時に付与するヘッダー
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	defaultWebhookPollInterval = 10 * time.Second
+	// defaultWebhookLease は取り出した配信を他のワーカーが再送しないよう確保する時間（送信タイムアウトより長くする）
+	defaultWebhookLease       = time.Minute
+	defaultWebhookBatchSize   = 20
+	defaultWebhookMaxAttempts = 8
+	// 再送間隔は 30秒, 1分, 2分, ... と倍々に延ばし、最大6時間とする
+	defaultWebhookBaseBackoff = 30 * time.Second
+	defaultWebhookMaxBackoff  = 6 * time.Hour
+	webhookRequestTimeout     = 10 * time.Second
+	// webhookMaxDrainBytes は接続を再利用するために読み捨てるレスポンス本文の最大長
+	webhookMaxDrainBytes = 4 << 10
+)
+
+// errWebhookDestinationNotAllowed は配信先の IP アドレスが内部ネットワークを指している場合のエラー
+var errWebhookDestinationNotAllowed = errors.New("webhook destination address is not allowed")
+
+// SignWebhookPayload は Webhook の署名（"sha256=" + 16進数の HMAC-SHA256）を返す
+// 署名対象は "<タイムスタンプ>.<本文>" で、受信側は X-Webhook-Timestamp と本文から同じ値を計算して検証する
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDispatcher は配信キューから送信予定の配信を取り出して送信する
+// 送信に失敗した配信は指数バックオフで再送し、上限回数に達したら failed とする
+type WebhookDispatcher struct {
+	webhookRepo repositories.WebhookRepository
+	client      *http.Client
+	now         func() time.Time
+	trigger     chan struct{}
+
+	pollInterval time.Duration
+	lease        time.Duration
+	batchSize    int
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+}
+
+// NewWebhookDispatcher は新しい WebhookDispatcher を作成する（client が nil の場合は newWebhookHTTPClient のクライアントを使う）
+func NewWebhookDispatcher(webhookRepo repositories.WebhookRepository, client *http.Client) *WebhookDispatcher {
+	if client == nil {
+		client = newWebhookHTTPClient()
+	}
+	return &WebhookDispatcher{
+		webhookRepo:  webhookRepo,
+		client:       client,
+		now:          time.Now,
+		trigger:      make(chan struct{}, 1),
+		pollInterval: defaultWebhookPollInterval,
+		lease:        defaultWebhookLease,
+		batchSize:    defaultWebhookBatchSize,
+		maxAttempts:  defaultWebhookMaxAttempts,
+		baseBackoff:  defaultWebhookBaseBackoff,
+		maxBackoff:   defaultWebhookMaxBackoff,
+	}
+}
+
+// newWebhookHTTPClient は配信用の HTTP クライアントを作成する
+// 配信先は任意のユーザーが登録できるため、接続時に解決済みの IP アドレスを確認して内部ネットワーク・クラウドのメタデータサービスへの接続を拒否し、
+// リダイレクトにも従わない（DNS の再バインド・リダイレクトで確認をすり抜けられないよう、登録時ではなく接続ごとに確認する）
+func newWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookRequestTimeout,
+		Control: webhookDialControl,
+	}
+	return &http.Client{
+		Timeout: webhookRequestTimeout,
+		Transport: &http.Transport{
+			// プロキシを経由すると接続先の確認が意味をなさないため使わない
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookRequestTimeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// blockedWebhookPrefixes は Webhook の配信先として許可しないアドレスの範囲（IANA の特別な用途のアドレスの一覧による）
+// 内部のネットワークに届くおそれのある範囲に加え、IPv4 のアドレスを埋め込んで変換する IPv6 の範囲（NAT64・6to4・Teredo）も拒否する
+var blockedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // このネットワーク
+	netip.MustParsePrefix("10.0.0.0/8"),      // プライベート
+	netip.MustParsePrefix("100.64.0.0/10"),   // キャリアグレード NAT の共有アドレス
+	netip.MustParsePrefix("127.0.0.0/8"),     // ループバック
+	netip.MustParsePrefix("169.254.0.0/16"),  // リンクローカル（クラウドのメタデータ）
+	netip.MustParsePrefix("172.16.0.0/12"),   // プライベート
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF のプロトコル用
+	netip.MustParsePrefix("192.0.2.0/24"),    // ドキュメント用
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 のリレー
+	netip.MustParsePrefix("192.168.0.0/16"),  // プライベート
+	netip.MustParsePrefix("198.18.0.0/15"),   // ベンチマーク用
+	netip.MustParsePrefix("198.51.100.0/24"), // ドキュメント用
+	netip.MustParsePrefix("203.0.113.0/24"),  // ドキュメント用
+	netip.MustParsePrefix("224.0.0.0/4"),     // マルチキャスト
+	netip.MustParsePrefix("240.0.0.0/4"),     // 予約済み・ブロードキャスト
+	netip.MustParsePrefix("::/96"),           // 未指定・ループバック・IPv4 互換
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // ローカルの NAT64
+	netip.MustParsePrefix("100::/64"),        // 破棄用
+	netip.MustParsePrefix("2001::/23"),       // IETF のプロトコル用（Teredo を含む）
+	netip.MustParsePrefix("2001:db8::/32"),   // ドキュメント用
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("fc00::/7"),        // ユニークローカル
+	netip.MustParsePrefix("fe80::/10"),       // リンクローカル
+	netip.MustParsePrefix("fec0::/10"),       // サイトローカル（廃止）
+	netip.MustParsePrefix("ff00::/8"),        // マルチキャスト
+}
+
+// webhookDialControl は接続先の IP アドレスが blockedWebhookPrefixes の範囲であれば接続を拒否する
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errWebhookDestinationNotAllowed
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !isPublicWebhookIP(ip) {
+		return errWebhookDestinationNotAllowed
+	}
+	return nil
+}
+
+// isPublicWebhookIP は ip が配信先として許可するアドレスか判定する（IPv4 射影アドレスは IPv4 のアドレスとして判定する）
+func isPublicWebhookIP(ip netip.Addr) bool {
+	ip = ip.WithZone("").Unmap()
+	for _, prefix := range blockedWebhookPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Trigger は次のポーリングを待たずに配信キューを処理するよう促す（ブロックしない）
+func (d *WebhookDispatcher) Trigger() {
+	select {
+	case d.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run は ctx がキャンセルされるまで配信キューを定期的に処理する
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	logging.L.Info("webhook dispatcher started", "service", "WebhookDispatcher", "poll_interval", d.pollInterval.String())
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil {
+			logging.L.Error("failed to dispatch webhooks", "service", "WebhookDispatcher", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			logging.L.Info("webhook dispatcher stopped", "service", "WebhookDispatcher")
+			return
+		case <-ticker.C:
+		case <-d.trigger:
+		}
+	}
+}
+
+// DispatchDue は送信予定日時を過ぎた配信を取り出して送信し、処理した件数を返す
+func (d *WebhookDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		deliveries, err := d.webhookRepo.ClaimDueDeliveries(d.now(), d.lease, d.batchSize)
+		if err != nil {
+			return processed, err
+		}
+		if len(deliveries) == 0 {
+			return processed, nil
+		}
+		endpoints := map[int]*models.WebhookEndpoint{}
+		for i := range deliveries {
+			if ctx.Err() != nil {
+				// 取り出し済みの配信はリース期限後に再度取り出される
+				return processed, nil
+			}
+			delivery := &deliveries[i]
+			endpoint, ok := endpoints[delivery.EndpointID]
+			if !ok {
+				endpoint, err = d.webhookRepo.GetEndpoint(delivery.EndpointID)
+				if err != nil && !errors.Is(err, repositories.ErrWebhookNotFound) {
+					return processed, err
+				}
+				endpoints[delivery.EndpointID] = endpoint
+			}
+			d.deliver(ctx, delivery, endpoint)
+			if ctx.Err() != nil {
+				// シャットダウンで中断された送信は失敗として数えず、リース期限後に再送する
+				return processed, nil
+			}
+			if err := d.webhookRepo.RecordAttempt(delivery); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+		if len(deliveries) < d.batchSize {
+			return processed, nil
+		}
+	}
+}
+
+// deliver は配信を1回送信し、結果を delivery に反映する
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery, endpoint *models.WebhookEndpoint) {
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+
+	if endpoint == nil || !endpoint.IsActive {
+		// 登録先が削除・無効化された配信は再送しない
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "endpoint is not active"
+		return
+	}
+
+	status, err := d.send(ctx, delivery, endpoint)
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		logging.L.Debug("webhook delivered", "service", "WebhookDispatcher", "delivery_id", delivery.ID, "endpoint_id", endpoint.ID, "status", status)
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		logging.L.Warn("webhook delivery gave up",
+			"service", "WebhookDispatcher",
+			"delivery_id", delivery.ID,
+			"endpoint_id", endpoint.ID,
+			"attempts", delivery.Attempts,
+			"error", err)
+		return
+	}
+	next := now.Add(d.backoff(delivery.Attempts))
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = &next
+	logging.L.Warn("webhook delivery failed, will retry",
+		"service", "WebhookDispatcher",
+		"delivery_id", delivery.ID,
+		"endpoint_id", endpoint.ID,
+		"attempts", delivery.Attempts,
+		"next_attempt_at", next,
+		"error", err)
+}
+
+// send は署名付きで配信を送信し、受け取ったHTTPステータスを返す（2xx 以外はエラーとする）
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *models.WebhookDelivery, endpoint *models.WebhookEndpoint) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-shisha-webhook/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.EventID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// 接続を再利用できるよう本文を読み捨てる（本文は配信の記録に残さない）
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookMaxDrainBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff は attempts 回目の失敗後の再送までの待ち時間を返す
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	return exponentialBackoff(d.baseBackoff, d.maxBackoff, attempts)
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+)
+
+// webhookReceiver は署名を検証して指定したステータスを返すテスト用の受信サーバー
+type webhookReceiver struct {
+	server   *httptest.Server
+	status   int
+	received atomic.Int32
+	badSig   atomic.Int32
+}
+
+func newWebhookReceiver(t *testing.T, secret string, status int) *webhookReceiver {
+	t.Helper()
+	r := &webhookReceiver{status: status}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		ts, _ := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+		if req.Header.Get(WebhookSignatureHeader) != SignWebhookPayload(secret, ts, body) ||
+			req.Header.Get(WebhookEventHeader) == "" || req.Header.Get(WebhookDeliveryHeader) == "" {
+			r.badSig.Add(1)
+		}
+		r.received.Add(1)
+		w.WriteHeader(r.status)
+		_, _ = w.Write([]byte("receiver says hi"))
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// setupWebhookDispatch は受信サーバーを登録し、イベントを1件キューに追加する
+func setupWebhookDispatch(t *testing.T, status int) (*memoryWebhookRepo, *WebhookDispatcher, *webhookReceiver, *time.Time) {
+	t.Helper()
+	repo := newMemoryWebhookRepo()
+	svc := NewWebhookService(repo, &countingTrigger{})
+	endpoint, err := svc.CreateEndpoint(1, &models.CreateWebhookInput{Events: []string{models.WebhookEventPostCreated}, URL: "http://placeholder"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	receiver := newWebhookReceiver(t, endpoint.Secret, status)
+	repo.endpoints[endpoint.ID].URL = receiver.server.URL
+
+	if err := svc.Enqueue(models.WebhookEventPostCreated, 1, models.PostWebhookData{PostID: 1, UserID: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dispatcher := NewWebhookDispatcher(repo, receiver.server.Client())
+	dispatcher.now = func() time.Time { return now }
+	return repo, dispatcher, receiver, &now
+}
+
+func TestWebhookDispatcher_DeliversSignedPayload(t *testing.T) {
+	repo, dispatcher, receiver, _ := setupWebhookDispatch(t, http.StatusNoContent)
+
+	processed, err := dispatcher.DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if processed != 1 || receiver.received.Load() != 1 || receiver.badSig.Load() != 0 {
+		t.Fatalf("expected 1 valid signed delivery, got processed=%d received=%d badSig=%d", processed, receiver.received.Load(), receiver.badSig.Load())
+	}
+	d := repo.deliveries[0]
+	if d.Status != models.WebhookDeliverySucceeded || d.Attempts != 1 || d.NextAttemptAt != nil ||
+		d.ResponseStatus == nil || *d.ResponseStatus != http.StatusNoContent {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+
+	// 成功した配信は再送しない
+	if processed, _ := dispatcher.DispatchDue(context.Background()); processed != 0 {
+		t.Fatalf("expected nothing to dispatch, got %d", processed)
+	}
+}
+
+func TestWebhookDispatcher_RetriesWithBackoffAndGivesUp(t *testing.T) {
+	repo, dispatcher, receiver, now := setupWebhookDispatch(t, http.StatusInternalServerError)
+	dispatcher.maxAttempts = 3
+
+	if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := repo.deliveries[0]
+	if d.Status != models.WebhookDeliveryPending || d.Attempts != 1 || d.LastError == "" {
+		t.Fatalf("expected pending retry, got %+v", d)
+	}
+	if d.NextAttemptAt == nil || !d.NextAttemptAt.Equal(now.Add(defaultWebhookBaseBackoff)) {
+		t.Fatalf("expected retry after %s, got %v", defaultWebhookBaseBackoff, d.NextAttemptAt)
+	}
+
+	// 再送予定日時より前は送信しない
+	if processed, _ := dispatcher.DispatchDue(context.Background()); processed != 0 {
+		t.Fatalf("expected no dispatch before backoff, got %d", processed)
+	}
+
+	*now = now.Add(defaultWebhookBaseBackoff)
+	dispatcher.DispatchDue(context.Background())
+	d = repo.deliveries[0]
+	if d.Attempts != 2 || !d.NextAttemptAt.Equal(now.Add(2*defaultWebhookBaseBackoff)) {
+		t.Fatalf("expected doubled backoff, got %+v", d)
+	}
+
+	*now = now.Add(2 * defaultWebhookBaseBackoff)
+	dispatcher.DispatchDue(context.Background())
+	d = repo.deliveries[0]
+	if d.Status != models.WebhookDeliveryFailed || d.Attempts != 3 || d.NextAttemptAt != nil {
+		t.Fatalf("expected delivery to give up after max attempts, got %+v", d)
+	}
+	if receiver.received.Load() != 3 {
+		t.Fatalf("expected 3 requests, got %d", receiver.received.Load())
+	}
+}
+
+func TestWebhookDispatcher_DeletedEndpointFails(t *testing.T) {
+	repo, dispatcher, receiver, _ := setupWebhookDispatch(t, http.StatusOK)
+	repo.endpoints = map[int]*models.WebhookEndpoint{}
+
+	if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.deliveries[0].Status != models.WebhookDeliveryFailed || receiver.received.Load() != 0 {
+		t.Fatalf("expected failed delivery without request, got %+v (received=%d)", repo.deliveries[0], receiver.received.Load())
+	}
+}
+
+func TestWebhookDispatcher_Backoff(t *testing.T) {
+	d := NewWebhookDispatcher(newMemoryWebhookRepo(), nil)
+	if got := d.backoff(1); got != defaultWebhookBaseBackoff {
+		t.Fatalf("expected %s, got %s", defaultWebhookBaseBackoff, got)
+	}
+	if got := d.backoff(3); got != 4*defaultWebhookBaseBackoff {
+		t.Fatalf("expected %s, got %s", 4*defaultWebhookBaseBackoff, got)
+	}
+	if got := d.backoff(100); got != defaultWebhookMaxBackoff {
+		t.Fatalf("expected max backoff %s, got %s", defaultWebhookMaxBackoff, got)
+	}
+}
+
+func TestWebhookDispatcher_DoesNotRecordResponseBody(t *testing.T) {
+	repo, dispatcher, _, _ := setupWebhookDispatch(t, http.StatusBadRequest)
+
+	if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.deliveries[0].LastError; got != "unexpected status 400" {
+		t.Fatalf("expected only the status to be recorded, got %q", got)
+	}
+}
+
+func TestWebhookDispatcher_RefusesInternalDestinations(t *testing.T) {
+	repo, dispatcher, receiver, _ := setupWebhookDispatch(t, http.StatusOK)
+	// 既定のクライアントは受信サーバー（127.0.0.1）への接続を拒否する
+	dispatcher.client = newWebhookHTTPClient()
+
+	if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := repo.deliveries[0]; d.Status != models.WebhookDeliveryPending || receiver.received.Load() != 0 {
+		t.Fatalf("expected refused delivery without request, got %+v (received=%d)", d, receiver.received.Load())
+	}
+}
+
+func TestWebhookDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	repo, dispatcher, receiver, _ := setupWebhookDispatch(t, http.StatusOK)
+	redirector := httptest.NewServer(http.RedirectHandler(receiver.server.URL, http.StatusFound))
+	t.Cleanup(redirector.Close)
+	for _, endpoint := range repo.endpoints {
+		endpoint.URL = redirector.URL
+	}
+	client := newWebhookHTTPClient()
+	client.Transport = http.DefaultTransport
+	dispatcher.client = client
+
+	if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := repo.deliveries[0]; d.ResponseStatus == nil || *d.ResponseStatus != http.StatusFound || receiver.received.Load() != 0 {
+		t.Fatalf("expected redirect not to be followed, got %+v (received=%d)", d, receiver.received.Load())
+	}
+}
+
+func TestIsPublicWebhookIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"100.63.255.255", true},
+		{"100.128.0.1", true},
+		{"198.20.0.1", true},
+		{"2606:4700::1111", true},
+		{"::ffff:93.184.216.34", true},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"10.0.0.5", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"127.0.0.1", false},
+		{"169.254.169.254", false},
+		{"172.16.0.1", false},
+		{"192.0.0.170", false},
+		{"192.0.2.1", false},
+		{"192.88.99.1", false},
+		{"192.168.1.1", false},
+		{"198.18.0.1", false},
+		{"198.19.255.254", false},
+		{"198.51.100.1", false},
+		{"203.0.113.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::", false},
+		{"::1", false},
+		{"::127.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"64:ff9b:1::1", false},
+		{"100::1", false},
+		{"2001::1", false},
+		{"2001:db8::1", false},
+		{"2002:a9fe:a9fe::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"fe80::1%eth0", false},
+		{"fec0::1", false},
+		{"ff02::1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicWebhookIP(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("isPublicWebhookIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+	"go-shisha-backend/pkg/logging"
+)
+
+// ErrInvalidWebhookURL は Webhook の配信先URLが http/https の絶対URLでない場合のエラー
+var ErrInvalidWebhookURL = errors.New("Webhook の配信先URLが不正です")
+
+const (
+	// webhookSecretBytes は署名鍵の長さ（バイト）
+	webhookSecretBytes = 32
+	// defaultWebhookDeliveryLimit は配信ログの取得件数の既定値
+	defaultWebhookDeliveryLimit = 20
+)
+
+// WebhookDeliveryTrigger は配信キューに追加された配信の即時送信を促すインターフェース
+type WebhookDeliveryTrigger interface {
+	Trigger()
+}
+
+// WebhookService は Webhook の登録先の管理と配信のキュー追加を扱う
+// 実際の送信は WebhookDispatcher が配信キューから取り出して行う
+type WebhookService struct {
+	webhookRepo repositories.WebhookRepository
+	// trigger はキューに追加した配信の即時送信を促す
+	trigger WebhookDeliveryTrigger
+	now     func() time.Time
+}
+
+// NewWebhookService は新しい WebhookService を作成する
+func NewWebhookService(webhookRepo repositories.WebhookRepository, trigger WebhookDeliveryTrigger) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		trigger:     trigger,
+		now:         time.Now,
+	}
+}
+
+// CreateEndpoint は Webhook の登録先を作成し、署名鍵を含めて返す
+// 署名鍵を返すのはこのときだけのため、呼び出し側で保管してもらう
+func (s *WebhookService) CreateEndpoint(userID int, input *models.CreateWebhookInput) (*models.WebhookEndpoint, error) {
+	if err := validateWebhookURL(input.URL); err != nil {
+		return nil, err
+	}
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// イベント種別の重複を除き、定義順に並べる
+	requested := map[string]bool{}
+	for _, e := range input.Events {
+		requested[e] = true
+	}
+	events := make([]string, 0, len(requested))
+	for _, e := range models.WebhookEventTypes {
+		if requested[e] {
+			events = append(events, e)
+		}
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		UserID:   userID,
+		URL:      input.URL,
+		Events:   events,
+		IsActive: true,
+		Secret:   secret,
+	}
+	if err := s.webhookRepo.CreateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// ListEndpoints は指定ユーザーが登録した登録先を返す（署名鍵は含めない）
+func (s *WebhookService) ListEndpoints(userID int) ([]models.WebhookEndpoint, error) {
+	return s.webhookRepo.ListEndpointsByUserID(userID)
+}
+
+// DeleteEndpoint は登録先を削除する
+// 他ユーザーの登録先の場合は、存在を明かさないため repositories.ErrWebhookNotFound を返す
+func (s *WebhookService) DeleteEndpoint(userID, endpointID int) error {
+	if _, err := s.getOwnedEndpoint(userID, endpointID); err != nil {
+		return err
+	}
+	return s.webhookRepo.DeleteEndpoint(endpointID)
+}
+
+// GetDeliveries は登録先の配信ログを新しい順に返す
+// 他ユーザーの登録先の場合は repositories.ErrWebhookNotFound を返す
+func (s *WebhookService) GetDeliveries(userID, endpointID int, query models.WebhookDeliveryListQuery) (*models.WebhookDeliveriesResponse, error) {
+	if _, err := s.getOwnedEndpoint(userID, endpointID); err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryLimit
+	}
+	deliveries, total, err := s.webhookRepo.ListDeliveries(endpointID, limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Limit:      limit,
+		Offset:     query.Offset,
+	}, nil
+}
+
+// webhookEventHandlerName はドメインイベントの購読者名
+const webhookEventHandlerName = "webhook.enqueue"
+
+// RegisterEventHandlers は投稿・いいねのドメインイベントを Webhook の配信キューに追加する購読者を登録する
+func (s *WebhookService) RegisterEventHandlers(subscriber DomainEventSubscriber) {
+	subscriber.Subscribe(models.DomainEventPostCreated, webhookEventHandlerName, s.handlePostCreated)
+	subscriber.Subscribe(models.DomainEventPostDeleted, webhookEventHandlerName, s.handlePostDeleted)
+	subscriber.Subscribe(models.DomainEventLikeAdded, webhookEventHandlerName, s.handleLikeAdded)
+}
+
+func (s *WebhookService) handlePostCreated(event *models.DomainEvent) error {
+	var e models.PostCreatedEvent
+	if err := event.Decode(&e); err != nil {
+		return err
+	}
+	return s.Enqueue(models.WebhookEventPostCreated, e.UserID, models.PostWebhookData{PostID: e.PostID, UserID: e.UserID, RemixedFrom: e.RemixedFrom})
+}
+
+func (s *WebhookService) handlePostDeleted(event *models.DomainEvent) error {
+	var e models.PostDeletedEvent
+	if err := event.Decode(&e); err != nil {
+		return err
+	}
+	return s.Enqueue(models.WebhookEventPostDeleted, e.UserID, models.PostWebhookData{PostID: e.PostID, UserID: e.UserID})
+}
+
+func (s *WebhookService) handleLikeAdded(event *models.DomainEvent) error {
+	var e models.LikeAddedEvent
+	if err := event.Decode(&e); err != nil {
+		return err
+	}
+	// いいねは投稿者の登録先に配信する
+	return s.Enqueue(models.WebhookEventLikeAdded, e.PostOwnerID, models.LikeWebhookData{
+		PostID:      e.PostID,
+		UserID:      e.UserID,
+		PostOwnerID: e.PostOwnerID,
+		Likes:       e.Likes,
+	})
+}
+
+// Enqueue はイベントを購読している ownerID の有効な登録先ごとに配信をキューに追加する
+// 他のユーザーの投稿・いいねを受け取れないよう、イベントの対象の投稿の投稿者（ownerID）の登録先にだけ配信する
+// 同じイベントの配信には共通のイベントIDを付与する
+func (s *WebhookService) Enqueue(eventType string, ownerID int, data interface{}) error {
+	endpoints, err := s.webhookRepo.ListActiveEndpointsForEvent(ownerID, eventType)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	eventID := uuid.NewString()
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: s.now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			EventID:    eventID,
+			EventType:  eventType,
+			Payload:    payload,
+		})
+	}
+	if err := s.webhookRepo.EnqueueDeliveries(deliveries); err != nil {
+		return err
+	}
+	logging.L.Debug("webhook event enqueued",
+		"service", "WebhookService",
+		"method", "Enqueue",
+		"event_type", eventType,
+		"event_id", eventID,
+		"endpoints", len(endpoints))
+	s.trigger.Trigger()
+	return nil
+}
+
+// getOwnedEndpoint は登録先を取得し、userID が登録したものであることを確認する
+func (s *WebhookService) getOwnedEndpoint(userID, endpointID int) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.GetEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.UserID != userID {
+		logging.L.Debug("user does not own webhook endpoint",
+			"service", "WebhookService",
+			"endpoint_id", endpointID,
+			"user_id", userID,
+			"owner_id", endpoint.UserID)
+		return nil, repositories.ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+
+// validateWebhookURL は配信先URLが http/https の絶対URLであることを確認する
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// generateWebhookSecret はランダムな署名鍵を生成する
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"go-shisha-backend/internal/models"
+	"go-shisha-backend/internal/repositories"
+)
+
+// memoryWebhookRepo は登録先と配信キューをメモリ上に保持するモック
+type memoryWebhookRepo struct {
+	endpoints  map[int]*models.WebhookEndpoint
+	deliveries []*models.WebhookDelivery
+	nextID     int
+}
+
+func newMemoryWebhookRepo() *memoryWebhookRepo {
+	return &memoryWebhookRepo{endpoints: map[int]*models.WebhookEndpoint{}}
+}
+
+func (m *memoryWebhookRepo) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	m.nextID++
+	endpoint.ID = m.nextID
+	e := *endpoint
+	m.endpoints[e.ID] = &e
+	return nil
+}
+
+func (m *memoryWebhookRepo) GetEndpoint(id int) (*models.WebhookEndpoint, error) {
+	e, ok := m.endpoints[id]
+	if !ok {
+		return nil, repositories.ErrWebhookNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (m *memoryWebhookRepo) ListEndpointsByUserID(userID int) ([]models.WebhookEndpoint, error) {
+	endpoints := []models.WebhookEndpoint{}
+	for _, e := range m.endpoints {
+		if e.UserID == userID {
+			copied := *e
+			copied.Secret = ""
+			endpoints = append(endpoints, copied)
+		}
+	}
+	return endpoints, nil
+}
+
+func (m *memoryWebhookRepo) ListActiveEndpointsForEvent(userID int, eventType string) ([]models.WebhookEndpoint, error) {
+	endpoints := []models.WebhookEndpoint{}
+	for _, e := range m.endpoints {
+		if e.UserID != userID || !e.IsActive {
+			continue
+		}
+		for _, ev := range e.Events {
+			if ev == eventType {
+				endpoints = append(endpoints, *e)
+				break
+			}
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ID < endpoints[j].ID })
+	return endpoints, nil
+}
+
+func (m *memoryWebhookRepo) DeleteEndpoint(id int) error {
+	if _, ok := m.endpoints[id]; !ok {
+		return repositories.ErrWebhookNotFound
+	}
+	delete(m.endpoints, id)
+	return nil
+}
+
+func (m *memoryWebhookRepo) EnqueueDeliveries(deliveries []models.WebhookDelivery) error {
+	for i := range deliveries {
+		d := deliveries[i]
+		m.nextID++
+		d.ID = m.nextID
+		d.Status = models.WebhookDeliveryPending
+		if d.NextAttemptAt == nil {
+			zero := time.Time{}
+			d.NextAttemptAt = &zero
+		}
+		deliveries[i].ID = d.ID
+		m.deliveries = append(m.deliveries, &d)
+	}
+	return nil
+}
+
+func (m *memoryWebhookRepo) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	claimed := []models.WebhookDelivery{}
+	for _, d := range m.deliveries {
+		if len(claimed) >= limit {
+			break
+		}
+		if d.Status != models.WebhookDeliveryPending || d.NextAttemptAt == nil || d.NextAttemptAt.After(now) {
+			continue
+		}
+		leaseUntil := now.Add(lease)
+		d.NextAttemptAt = &leaseUntil
+		claimed = append(claimed, *d)
+	}
+	return claimed, nil
+}
+
+func (m *memoryWebhookRepo) RecordAttempt(delivery *models.WebhookDelivery) error {
+	for i, d := range m.deliveries {
+		if d.ID == delivery.ID {
+			copied := *delivery
+			m.deliveries[i] = &copied
+			return nil
+		}
+	}
+	return errors.New("delivery not found")
+}
+
+func (m *memoryWebhookRepo) ListDeliveries(endpointID, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	deliveries := []models.WebhookDelivery{}
+	for _, d := range m.deliveries {
+		if d.EndpointID == endpointID {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	return deliveries, len(deliveries), nil
+}
+
+// countingTrigger は Trigger の呼び出し回数を記録するモック
+type countingTrigger struct {
+	count int
+}
+
+func (m *countingTrigger) Trigger() {
+	m.count++
+}
+
+func TestWebhookService_CreateEndpoint(t *testing.T) {
+	repo := newMemoryWebhookRepo()
+	svc := NewWebhookService(repo, &countingTrigger{})
+
+	endpoint, err := svc.CreateEndpoint(1, &models.CreateWebhookInput{
+		URL:    "https://example.com/hook",
+		Events: []string{models.WebhookEventLikeAdded, models.WebhookEventPostCreated, models.WebhookEventLikeAdded},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(endpoint.Secret) != webhookSecretBytes*2 || !endpoint.IsActive {
+		t.Fatalf("expected active endpoint with generated secret, got %+v", endpoint)
+	}
+	// 重複を除き、定義順に並べる
+	if len(endpoint.Events) != 2 || endpoint.Events[0] != models.WebhookEventPostCreated || endpoint.Events[1] != models.WebhookEventLikeAdded {
+		t.Fatalf("unexpected events: %v", endpoint.Events)
+	}
+
+	for _, raw := range []string{"ftp://example.com/hook", "/relative", "https://"} {
+		if _, err := svc.CreateEndpoint(1, &models.CreateWebhookInput{URL: raw, Events: []string{models.WebhookEventPostCreated}}); !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Fatalf("expected ErrInvalidWebhookURL for %q, got %v", raw, err)
+		}
+	}
+}
+
+func TestWebhookService_OwnershipChecks(t *testing.T) {
+	repo := newMemoryWebhookRepo()
+	svc := NewWebhookService(repo, &countingTrigger{})
+	endpoint, err := svc.CreateEndpoint(1, &models.CreateWebhookInput{URL: "https://example.com/hook", Events: []string{models.WebhookEventPostCreated}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := svc.DeleteEndpoint(2, endpoint.ID); !errors.Is(err, repositories.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound for other user, got %v", err)
+	}
+	if _, err := svc.GetDeliveries(2, endpoint.ID, models.WebhookDeliveryListQuery{}); !errors.Is(err, repositories.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound for other user, got %v", err)
+	}
+
+	res, err := svc.GetDeliveries(1, endpoint.ID, models.WebhookDeliveryListQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Limit != defaultWebhookDeliveryLimit {
+		t.Fatalf("expected default limit %d, got %d", defaultWebhookDeliveryLimit, res.Limit)
+	}
+	if err := svc.DeleteEndpoint(1, endpoint.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWebhookService_EnqueueToSubscribedEndpoints(t *testing.T) {
+	repo := newMemoryWebhookRepo()
+	trigger := &countingTrigger{}
+	svc := NewWebhookService(repo, trigger)
+
+	likes, _ := svc.CreateEndpoint(1, &models.CreateWebhookInput{URL: "https://a.example.com/hook", Events: []string{models.WebhookEventLikeAdded}})
+	both, _ := svc.CreateEndpoint(1, &models.CreateWebhookInput{URL: "https://b.example.com/hook", Events: []string{models.WebhookEventPostCreated, models.WebhookEventLikeAdded}})
+	svc.CreateEndpoint(1, &models.CreateWebhookInput{URL: "https://c.example.com/hook", Events: []string{models.WebhookEventPostDeleted}})
+	// 他のユーザーの登録先には配信しない
+	svc.CreateEndpoint(2, &models.CreateWebhookInput{URL: "https://d.example.com/hook", Events: []string{models.WebhookEventLikeAdded}})
+
+	if err := svc.Enqueue(models.WebhookEventLikeAdded, 1, models.LikeWebhookData{PostID: 10, UserID: 2, PostOwnerID: 1, Likes: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(repo.deliveries))
+	}
+	if repo.deliveries[0].EndpointID != likes.ID || repo.deliveries[1].EndpointID != both.ID {
+		t.Fatalf("unexpected endpoints: %d, %d", repo.deliveries[0].EndpointID, repo.deliveries[1].EndpointID)
+	}
+	// 同じイベントの配信は共通のイベントIDを持つ
+	if repo.deliveries[0].EventID == "" || repo.deliveries[0].EventID != repo.deliveries[1].EventID {
+		t.Fatalf("expected shared event id, got %q and %q", repo.deliveries[0].EventID, repo.deliveries[1].EventID)
+	}
+
+	var payload struct {
+		ID   string                 `json:"id"`
+		Type string                 `json:"type"`
+		Data models.LikeWebhookData `json:"data"`
+	}
+	if err := json.Unmarshal(repo.deliveries[0].Payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.ID != repo.deliveries[0].EventID || payload.Type != models.WebhookEventLikeAdded || payload.Data.Likes != 3 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if trigger.count != 1 {
+		t.Fatalf("expected trigger to be called once, got %d", trigger.count)
+	}
+
+	// 購読者がいないイベントはキューに追加せず、送信も促さない
+	repo.endpoints = map[int]*models.WebhookEndpoint{}
+	if err := svc.Enqueue(models.WebhookEventPostDeleted, 1, models.PostWebhookData{PostID: 10, UserID: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.deliveries) != 2 || trigger.count != 1 {
+		t.Fatalf("expected no new deliveries, got %d (trigger=%d)", len(repo.deliveries), trigger.count)
+	}
+}
+
+func TestWebhookService_HandlesDomainEvents(t *testing.T) {
+	repo := newMemoryWebhookRepo()
+	svc := NewWebhookService(repo, &countingTrigger{})
+	bus := NewDomainEventBus()
+	svc.RegisterEventHandlers(bus)
+	svc.CreateEndpoint(1, &models.CreateWebhookInput{URL: "https://example.com/hook", Events: models.WebhookEventTypes})
+
+	events := []*models.DomainEvent{
+		{ID: 1, Type: models.DomainEventPostCreated, Payload: json.RawMessage(`{"post_id":10,"user_id":1,"remixed_from":3,"image_urls":[]}`)},
+		{ID: 2, Type: models.DomainEventLikeAdded, Payload: json.RawMessage(`{"post_id":10,"user_id":2,"post_owner_id":1,"likes":4}`)},
+		{ID: 3, Type: models.DomainEventPostDeleted, Payload: json.RawMessage(`{"post_id":10,"user_id":1}`)},
+	}
+	for _, event := range events {
+		if err := bus.Deliver(event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(repo.deliveries) != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", len(repo.deliveries))
+	}
+	wantTypes := []string{models.WebhookEventPostCreated, models.WebhookEventLikeAdded, models.WebhookEventPostDeleted}
+	for i, want := range wantTypes {
+		if repo.deliveries[i].EventType != want {
+			t.Fatalf("delivery %d: expected %s, got %s", i, want, repo.deliveries[i].EventType)
+		}
+	}
+	var payload struct {
+		Data models.PostWebhookData `json:"data"`
+	}
+	if err := json.Unmarshal(repo.deliveries[0].Payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Data.PostID != 10 || payload.Data.RemixedFrom == nil || *payload.Data.RemixedFrom != 3 {
+		t.Fatalf("unexpected post.created data: %+v", payload.Data)
+	}
+}
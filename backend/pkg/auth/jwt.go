@@ -1,11 +1,12 @@
 package auth
 
 import (
+	"encoding/base64"
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -13,58 +14,147 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 	// ErrExpiredToken はトークンが期限切れの場合のエラー
 	ErrExpiredToken = errors.New("token has expired")
+	// ErrRevokedToken は署名・有効期限は正しいが、発行後に無効化されたトークンの場合のエラー
+	ErrRevokedToken = errors.New("token has been revoked")
+	// ErrAccountSuspended はトークンの持ち主が利用停止中の場合のエラー
+	ErrAccountSuspended = errors.New("account is suspended")
 )
 
+// SuspensionError は利用停止中のユーザーの認証を拒否したことを表すエラー（errors.Is で ErrAccountSuspended に一致する）
+type SuspensionError struct {
+	Reason string
+	// Until は利用停止の終了日時（nil の場合は無期限）
+	Until *time.Time
+}
+
+func (e *SuspensionError) Error() string {
+	return ErrAccountSuspended.Error()
+}
+
+func (e *SuspensionError) Unwrap() error {
+	return ErrAccountSuspended
+}
+
+// AccessTokenTTL はAccess Tokenの有効期間
+const AccessTokenTTL = 15 * time.Minute
+
 // Claims はJWTのクレーム情報を保持する構造体
+// アクセストークンでは RegisteredClaims.ID（jti）にトークンごとの一意なIDを設定し、ログアウト時の無効化に使う
 type Claims struct {
 	UserID int64 `json:"uid"`
+	// Role はトークン発行時点のロール（Refresh Token では空）
+	// 発行後にロールが変更されても有効期限まで残るため、重要な操作では DB のロールを再確認する
+	Role string `json:"role,omitempty"`
+	// TokenVersion はトークン発行時点のユーザーのトークンバージョン
+	// ユーザーのトークンバージョンが進むと、それより前に発行したアクセストークンは有効期限内でも拒否される
+	TokenVersion int `json:"token_version,omitempty"`
+	// SessionID はトークンを発行したログインのセッションID（Refresh Token のファミリーID、Refresh Token では空）
+	SessionID string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// refreshTokenAudience は Refresh Token の aud クレーム
+// Access Token にだけ aud を付けず、Refresh Token・確認用トークンなどを認証に使えないようにする
+const refreshTokenAudience = "refresh"
+
+// emailVerificationAudience はメールアドレスの確認用トークンの aud クレーム
+const emailVerificationAudience = "email_verification"
+
+// EmailVerificationTokenTTL はメールアドレスの確認用トークンの有効期間
+const EmailVerificationTokenTTL = 24 * time.Hour
+
+// EmailVerificationClaims はメールアドレスの確認用トークンのクレーム情報を保持する構造体
+type EmailVerificationClaims struct {
+	UserID int64 `json:"uid"`
+	// Email は確認するメールアドレス（発行後にメールアドレスが変わった場合はトークンを使えない）
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// mfaChallengeAudience は2段階認証のチャレンジトークンの aud クレーム
+const mfaChallengeAudience = "mfa_challenge"
+
+// MFAChallengeTokenTTL は2段階認証のチャレンジトークンの有効期間
+const MFAChallengeTokenTTL = 5 * time.Minute
+
+// accountUnlockAudience はログインの失敗でロックしたアカウントの解除用トークンの aud クレーム
+const accountUnlockAudience = "account_unlock"
+
+// パスキーの登録・認証の操作のトークンの aud クレーム
+const (
+	PasskeyRegistrationAudience = "passkey_registration"
+	PasskeyLoginAudience        = "passkey_login"
+)
+
+// PasskeySessionTokenTTL はパスキーの登録・認証の操作のトークンの有効期間
+const PasskeySessionTokenTTL = 5 * time.Minute
+
+// PasskeySessionClaims はパスキーの登録・認証の操作のトークンのクレーム情報を保持する構造体
+type PasskeySessionClaims struct {
+	// UserID は登録するユーザー（認証では 0）
+	UserID int64 `json:"uid,omitempty"`
+	// Challenge はブラウザーに渡したチャレンジ（Base64URL）
+	Challenge string `json:"challenge"`
+	jwt.RegisteredClaims
+}
+
+// oidcLoginAudience は OpenID Connect のログインの state トークンの aud クレーム
+const oidcLoginAudience = "oidc_login"
+
+// OIDCStateTokenTTL は OpenID Connect のログインの state トークンの有効期間（プロバイダーでのログイン・同意を含む）
+const OIDCStateTokenTTL = 10 * time.Minute
+
+// OIDCStateClaims は OpenID Connect のログインの state トークンのクレーム情報を保持する構造体
+// Cookie でブラウザーに預け、プロバイダーから戻ったときに同じブラウザーで開始したログインか確認する
+type OIDCStateClaims struct {
+	// Provider はログインを開始したプロバイダーの識別子
+	Provider string `json:"provider"`
+	// State・Nonce は認可リクエストに含めた値
+	State string `json:"state"`
+	Nonce string `json:"nonce"`
+	// CodeVerifier は PKCE の code_verifier（プロバイダーには S256 のチャレンジだけを送る）
+	CodeVerifier string `json:"code_verifier"`
 	jwt.RegisteredClaims
 }
 
 // GenerateAccessToken はAccess Tokenを生成する（15分有効）
-func GenerateAccessToken(userID int64) (string, error) {
+func GenerateAccessToken(userID int64, role string, tokenVersion int, sessionID string) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserID: userID,
+		UserID:       userID,
+		Role:         role,
+		TokenVersion: tokenVersion,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(15 * time.Minute)),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secret := getJWTSecret()
-	return token.SignedString([]byte(secret))
+	return signToken(claims)
 }
 
-// GenerateRefreshToken はRefresh Tokenを生成する（7日有効）
+// GenerateRefreshToken はRefresh Tokenを生成する（7日有効、リフレッシュのたびにローテーションする）
 func GenerateRefreshToken(userID int64) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// ローテーションで同じ秒に発行しても別のトークンになるよう jti を付与する
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{refreshTokenAudience},
 			ExpiresAt: jwt.NewNumericDate(now.Add(7 * 24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secret := getJWTSecret()
-	return token.SignedString([]byte(secret))
+	return signToken(claims)
 }
 
-// ValidateToken はトークンを検証し、クレームを返す
+// ValidateToken は Access Token を検証し、クレームを返す
 func ValidateToken(tokenString string) (*Claims, error) {
-	secret := getJWTSecret()
-
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// 署名方法の検証
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(secret), nil
-	})
-
+	token, err := parseToken(tokenString, &Claims{})
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
@@ -76,15 +166,180 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	if !ok || !token.Valid {
 		return nil, ErrInvalidToken
 	}
+	// aud 付きのトークン（Refresh Token・メールアドレスの確認用など）は認証に使えない
+	if len(claims.Audience) > 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// ValidateRefreshToken は Refresh Token を検証し、クレームを返す（Access Token は ErrInvalidToken）
+func ValidateRefreshToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := parseAudienceToken(tokenString, refreshTokenAudience, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// GenerateEmailVerificationToken はメールアドレスの確認用トークンを生成する（24時間有効）
+func GenerateEmailVerificationToken(userID int64, email string) (string, error) {
+	now := time.Now()
+	claims := EmailVerificationClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{emailVerificationAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(EmailVerificationTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return signToken(claims)
+}
 
+// ValidateEmailVerificationToken はメールアドレスの確認用トークンを検証し、クレームを返す
+func ValidateEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error) {
+	claims := &EmailVerificationClaims{}
+	if err := parseAudienceToken(tokenString, emailVerificationAudience, claims); err != nil {
+		return nil, err
+	}
 	return claims, nil
 }
 
-// getJWTSecret は環境変数からJWT_SECRETを取得する
-func getJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		panic("JWT_SECRET environment variable is required but not set")
+// GenerateMFAChallengeToken はパスワードを確認したユーザーの2段階認証のチャレンジトークンを生成する（5分有効）
+// jti を付与し、ログインの完了時に無効化して使い回せないようにする
+func GenerateMFAChallengeToken(userID int64) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(MFAChallengeTokenTTL)
+	claims := Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{mfaChallengeAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	signed, err := signToken(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ValidateMFAChallengeToken は2段階認証のチャレンジトークンを検証し、クレームを返す
+func ValidateMFAChallengeToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := parseAudienceToken(tokenString, mfaChallengeAudience, claims); err != nil {
+		return nil, err
+	}
+	if claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// GenerateAccountUnlockToken はロックしたアカウントの解除用トークンを生成する（ロックの終了日時 expiresAt まで有効）
+func GenerateAccountUnlockToken(userID int64, expiresAt time.Time) (string, error) {
+	claims := Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{accountUnlockAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signToken(claims)
+}
+
+// ValidateAccountUnlockToken はロックしたアカウントの解除用トークンを検証し、クレームを返す
+func ValidateAccountUnlockToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := parseAudienceToken(tokenString, accountUnlockAudience, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// GeneratePasskeySessionToken はパスキーの登録・認証の操作のトークンを生成する（5分有効）
+// サーバーにチャレンジを保存せず、audience（PasskeyRegistrationAudience・PasskeyLoginAudience）とチャレンジに署名してブラウザーに預ける
+// 登録では userID に操作しているユーザーを含め、認証では 0 にする
+func GeneratePasskeySessionToken(audience string, userID int64, challenge []byte) (string, error) {
+	now := time.Now()
+	claims := PasskeySessionClaims{
+		UserID:    userID,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(PasskeySessionTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return signToken(claims)
+}
+
+// ValidatePasskeySessionToken はパスキーの登録・認証の操作のトークンを検証し、クレームとチャレンジを返す
+func ValidatePasskeySessionToken(audience, tokenString string) (*PasskeySessionClaims, []byte, error) {
+	claims := &PasskeySessionClaims{}
+	if err := parseAudienceToken(tokenString, audience, claims); err != nil {
+		return nil, nil, err
+	}
+	challenge, err := base64.RawURLEncoding.DecodeString(claims.Challenge)
+	if err != nil || len(challenge) == 0 || claims.ID == "" {
+		return nil, nil, ErrInvalidToken
+	}
+	return claims, challenge, nil
+}
+
+// GenerateOIDCStateToken は OpenID Connect のログインの state トークンを生成する（10分有効）
+func GenerateOIDCStateToken(provider, state, nonce, codeVerifier string) (string, error) {
+	now := time.Now()
+	claims := OIDCStateClaims{
+		Provider:     provider,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{oidcLoginAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(OIDCStateTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return signToken(claims)
+}
+
+// ValidateOIDCStateToken は OpenID Connect のログインの state トークンを検証し、クレームを返す
+func ValidateOIDCStateToken(tokenString string) (*OIDCStateClaims, error) {
+	claims := &OIDCStateClaims{}
+	if err := parseAudienceToken(tokenString, oidcLoginAudience, claims); err != nil {
+		return nil, err
+	}
+	if claims.ID == "" || claims.State == "" || claims.Nonce == "" || claims.CodeVerifier == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// parseAudienceToken は aud クレームが audience のトークンを検証し、claims に読み込む
+func parseAudienceToken(tokenString, audience string, claims jwt.Claims) error {
+	token, err := parseToken(tokenString, claims, jwt.WithAudience(audience))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return ErrExpiredToken
+		}
+		return ErrInvalidToken
+	}
+	if !token.Valid {
+		return ErrInvalidToken
 	}
-	return secret
+	return nil
 }
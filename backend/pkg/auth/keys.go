@@ -0,0 +1,331 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"go-shisha-backend/pkg/jwk"
+	"go-shisha-backend/pkg/logging"
+)
+
+var (
+	// ErrSigningKeyNotConfigured はトークンの署名鍵が設定されていない場合のエラー
+	ErrSigningKeyNotConfigured = errors.New("jwt signing key is not configured")
+	// ErrUnsupportedSigningKey は Ed25519・RSA（2048 ビット以上）以外の鍵が指定された場合のエラー
+	ErrUnsupportedSigningKey = errors.New("unsupported jwt signing key")
+)
+
+// minRSAKeyBits は署名・検証に使う RSA 鍵の最小のビット数
+const minRSAKeyBits = 2048
+
+// legacyTokenGracePeriod は署名鍵に切り替えた後も HS256 のトークンを受け付ける期間
+// 切り替え前に発行した Refresh Token の有効期限（7日）が切れるまでに限る
+const legacyTokenGracePeriod = 7 * 24 * time.Hour
+
+// verificationKey はトークンの検証に使う公開鍵と署名方式
+type verificationKey struct {
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// KeySet はトークンの署名鍵と検証鍵の組
+// 署名には1つの鍵だけを使い、トークンのヘッダーの kid（鍵の JWK Thumbprint）で検証鍵を選ぶ
+// 鍵をローテーションする場合は、新しい鍵で署名し、以前の鍵を有効期限が切れるまで検証鍵に残す
+type KeySet struct {
+	kid     string
+	method  jwt.SigningMethod
+	signer  crypto.Signer
+	keys    map[string]verificationKey
+	ordered []string
+	// legacySecret は HS256 の共有シークレット（移行前に発行した kid のないトークンの検証だけに使う）
+	legacySecret []byte
+	// legacyRetiredAt は共有シークレットから署名鍵に切り替えた日時
+	// この日時より前に発行したトークンだけを、legacyTokenGracePeriod が過ぎるまで受け付ける
+	legacyRetiredAt time.Time
+}
+
+// keySet はトークンの署名・検証に使う KeySet（SetKeySet で設定する）
+var keySet atomic.Pointer[KeySet]
+
+// SetKeySet はトークンの署名・検証に使う KeySet を設定する
+func SetKeySet(ks *KeySet) {
+	keySet.Store(ks)
+}
+
+// currentKeySet は設定されている KeySet を返す
+func currentKeySet() (*KeySet, error) {
+	ks := keySet.Load()
+	if ks == nil {
+		return nil, ErrSigningKeyNotConfigured
+	}
+	return ks, nil
+}
+
+// NewKeySet は signer で署名し、signer と verificationKeys の公開鍵で検証する KeySet を作成する
+// 鍵は Ed25519（EdDSA）と 2048 ビット以上の RSA（RS256）に対応する
+func NewKeySet(signer crypto.Signer, verificationKeys ...crypto.PublicKey) (*KeySet, error) {
+	ks := &KeySet{signer: signer, keys: make(map[string]verificationKey)}
+	kid, method, err := ks.addVerificationKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	ks.kid, ks.method = kid, method
+	for _, key := range verificationKeys {
+		if _, _, err := ks.addVerificationKey(key); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// GenerateKeySet は新しい Ed25519 の鍵で署名する KeySet を作成する
+// 鍵はどこにも保存しないため、プロセスを再起動すると発行済みのトークンはすべて無効になる（開発・テスト用）
+func GenerateKeySet() (*KeySet, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return NewKeySet(private)
+}
+
+// LoadKeySetFromEnv は環境変数から KeySet を読み込む
+//   - JWT_SIGNING_KEY・JWT_SIGNING_KEY_FILE: 署名鍵（PEM の秘密鍵、どちらか一方）
+//   - JWT_VERIFICATION_KEYS・JWT_VERIFICATION_KEY_FILES: ローテーション中の以前の鍵（PEM の公開鍵を連結したもの・カンマ区切りのファイル）
+//   - JWT_SECRET: 移行前に HS256 で発行したトークンを検証する共有シークレット（署名には使わない）
+//   - JWT_SECRET_RETIRED_AT: 署名鍵に切り替えた日時（RFC 3339、JWT_SECRET を指定する場合は必須）
+//
+// JWT_SECRET は切り替えから legacyTokenGracePeriod が過ぎると使わない
+// 環境変数の PEM は改行を \n と書いてもよい。署名鍵がない場合は ErrSigningKeyNotConfigured を返す
+func LoadKeySetFromEnv() (*KeySet, error) {
+	signingPEM, err := pemFromEnv("JWT_SIGNING_KEY", "JWT_SIGNING_KEY_FILE")
+	if err != nil {
+		return nil, err
+	}
+	if len(signingPEM) == 0 {
+		return nil, ErrSigningKeyNotConfigured
+	}
+	signer, err := parsePrivateKeyPEM(signingPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT signing key: %w", err)
+	}
+
+	verificationPEM := []byte(unescapePEM(os.Getenv("JWT_VERIFICATION_KEYS")))
+	if files := os.Getenv("JWT_VERIFICATION_KEY_FILES"); files != "" {
+		for _, path := range strings.Split(files, ",") {
+			data, err := os.ReadFile(strings.TrimSpace(path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read JWT verification key: %w", err)
+			}
+			verificationPEM = append(append(verificationPEM, '\n'), data...)
+		}
+	}
+	verificationKeys, err := parsePublicKeysPEM(verificationPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT verification key: %w", err)
+	}
+
+	ks, err := NewKeySet(signer, verificationKeys...)
+	if err != nil {
+		return nil, err
+	}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		value := os.Getenv("JWT_SECRET_RETIRED_AT")
+		if value == "" {
+			return nil, errors.New("JWT_SECRET_RETIRED_AT is required when JWT_SECRET is set")
+		}
+		retiredAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_SECRET_RETIRED_AT: %w", err)
+		}
+		if time.Now().Before(retiredAt.Add(legacyTokenGracePeriod)) {
+			ks.legacySecret, ks.legacyRetiredAt = []byte(secret), retiredAt
+		} else {
+			logging.L.Warn("JWT_SECRET is ignored because the grace period after JWT_SECRET_RETIRED_AT has ended", "retired_at", retiredAt)
+		}
+	}
+	return ks, nil
+}
+
+// JWKS は検証鍵の公開鍵を JSON Web Key Set で返す（署名鍵が先頭、共有シークレットは含めない）
+func (ks *KeySet) JWKS() jwk.Set {
+	set := jwk.Set{Keys: make([]jwk.Key, 0, len(ks.ordered))}
+	for _, kid := range ks.ordered {
+		key := ks.keys[kid]
+		// addVerificationKey で変換できることを確認済み
+		k, _ := jwk.FromPublicKey(key.public, kid, key.method.Alg())
+		set.Keys = append(set.Keys, k)
+	}
+	return set
+}
+
+// KeyID は署名鍵の kid を返す
+func (ks *KeySet) KeyID() string {
+	return ks.kid
+}
+
+// addVerificationKey は公開鍵を検証鍵に加え、kid と署名方式を返す
+func (ks *KeySet) addVerificationKey(public crypto.PublicKey) (string, jwt.SigningMethod, error) {
+	var method jwt.SigningMethod
+	switch key := public.(type) {
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSAKeyBits {
+			return "", nil, ErrUnsupportedSigningKey
+		}
+		method = jwt.SigningMethodRS256
+	default:
+		return "", nil, ErrUnsupportedSigningKey
+	}
+	k, err := jwk.FromPublicKey(public, "", method.Alg())
+	if err != nil {
+		return "", nil, ErrUnsupportedSigningKey
+	}
+	kid, err := k.Thumbprint()
+	if err != nil {
+		return "", nil, ErrUnsupportedSigningKey
+	}
+	if _, exists := ks.keys[kid]; !exists {
+		ks.keys[kid] = verificationKey{method: method, public: public}
+		ks.ordered = append(ks.ordered, kid)
+	}
+	return kid, method, nil
+}
+
+// sign はクレームに署名鍵で署名し、ヘッダーに kid を付ける
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	token.Header["kid"] = ks.kid
+	return token.SignedString(ks.signer)
+}
+
+// keyFunc はトークンのヘッダーの kid と alg に一致する検証鍵を返す
+// kid のないトークンは共有シークレットが設定されている場合だけ、切り替え前に発行されたものを猶予期間の間 HS256 で検証する
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if len(ks.legacySecret) == 0 || token.Method != jwt.SigningMethodHS256 {
+			return nil, ErrInvalidToken
+		}
+		issuedAt, err := token.Claims.GetIssuedAt()
+		if err != nil || issuedAt == nil || !issuedAt.Before(ks.legacyRetiredAt) || !time.Now().Before(ks.legacyRetiredAt.Add(legacyTokenGracePeriod)) {
+			return nil, ErrInvalidToken
+		}
+		return ks.legacySecret, nil
+	}
+	key, ok := ks.keys[kid]
+	if !ok || token.Method.Alg() != key.method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	return key.public, nil
+}
+
+// signToken は設定されている KeySet でクレームに署名する
+func signToken(claims jwt.Claims) (string, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return "", err
+	}
+	return ks.sign(claims)
+}
+
+// parseToken は設定されている KeySet でトークンを検証し、claims に読み込む
+func parseToken(tokenString string, claims jwt.Claims, options ...jwt.ParserOption) (*jwt.Token, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodHS256.Alg()}))
+	token, err := jwt.ParseWithClaims(tokenString, claims, ks.keyFunc, options...)
+	if err == nil && token.Method == jwt.SigningMethodHS256 {
+		// 共有シークレットの廃止の目安にするため、移行前のトークンを受け付けるたびに記録する
+		issuedAt, _ := claims.GetIssuedAt()
+		logging.L.Warn("accepted legacy HS256 token", "issued_at", issuedAt, "retired_at", ks.legacyRetiredAt)
+	}
+	return token, err
+}
+
+// pemFromEnv は環境変数 name の PEM、または環境変数 fileName のファイルの内容を返す（両方の指定はエラー）
+func pemFromEnv(name, fileName string) ([]byte, error) {
+	value, path := os.Getenv(name), os.Getenv(fileName)
+	switch {
+	case value != "" && path != "":
+		return nil, fmt.Errorf("%s and %s cannot be set at the same time", name, fileName)
+	case value != "":
+		return []byte(unescapePEM(value)), nil
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", fileName, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// unescapePEM は .env などで1行に書いた PEM の \n を改行に戻す
+func unescapePEM(value string) string {
+	return strings.ReplaceAll(value, `\n`, "\n")
+}
+
+// parsePrivateKeyPEM は PEM の秘密鍵（PKCS #8・PKCS #1 の RSA）を読み込む
+func parsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedSigningKey
+	}
+	return signer, nil
+}
+
+// parsePublicKeysPEM は連結した PEM の公開鍵（PKIX）をすべて読み込む
+func parsePublicKeysPEM(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		return nil, errors.New("unexpected data after PEM blocks")
+	}
+	return keys, nil
+}
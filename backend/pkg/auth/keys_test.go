@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// useKeySet はテストの間だけ ks で署名・検証する
+func useKeySet(t *testing.T, ks *KeySet) {
+	t.Helper()
+	previous := keySet.Load()
+	SetKeySet(ks)
+	t.Cleanup(func() { keySet.Store(previous) })
+}
+
+func newTestKeySet(t *testing.T, signer crypto.Signer, verificationKeys ...crypto.PublicKey) *KeySet {
+	t.Helper()
+	ks, err := NewKeySet(signer, verificationKeys...)
+	if err != nil {
+		t.Fatalf("NewKeySet failed: %v", err)
+	}
+	return ks
+}
+
+func privateKeyPEM(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func publicKeyPEM(t *testing.T, key crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestKeySet_SignAndVerify(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	tests := []struct {
+		name   string
+		signer crypto.Signer
+		alg    string
+	}{
+		{name: "正常系: Ed25519 の鍵で EdDSA の署名", signer: edKey, alg: "EdDSA"},
+		{name: "正常系: RSA の鍵で RS256 の署名", signer: rsaKey, alg: "RS256"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := newTestKeySet(t, tt.signer)
+			useKeySet(t, ks)
+
+			token, err := GenerateAccessToken(1, "user", 0, "")
+			if err != nil {
+				t.Fatalf("GenerateAccessToken failed: %v", err)
+			}
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("failed to parse token: %v", err)
+			}
+			if parsed.Header["alg"] != tt.alg || parsed.Header["kid"] != ks.KeyID() {
+				t.Errorf("unexpected header: %v", parsed.Header)
+			}
+			claims, err := ValidateToken(token)
+			if err != nil || claims.UserID != 1 {
+				t.Errorf("expected valid token, got %v (err=%v)", claims, err)
+			}
+
+			jwks := ks.JWKS()
+			if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != ks.KeyID() || jwks.Keys[0].Alg != tt.alg {
+				t.Errorf("unexpected jwks: %+v", jwks)
+			}
+		})
+	}
+
+	t.Run("異常系: 2048 ビット未満の RSA 鍵", func(t *testing.T) {
+		small, _ := rsa.GenerateKey(rand.Reader, 1024)
+		if _, err := NewKeySet(small); !errors.Is(err, ErrUnsupportedSigningKey) {
+			t.Errorf("expected ErrUnsupportedSigningKey, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 署名鍵が設定されていない", func(t *testing.T) {
+		useKeySet(t, nil)
+		if _, err := GenerateAccessToken(1, "user", 0, ""); !errors.Is(err, ErrSigningKeyNotConfigured) {
+			t.Errorf("expected ErrSigningKeyNotConfigured, got %v", err)
+		}
+	})
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	_, oldKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, newKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	useKeySet(t, newTestKeySet(t, oldKey))
+	oldToken, err := GenerateRefreshToken(1)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken failed: %v", err)
+	}
+
+	t.Run("正常系: 以前の鍵を検証鍵に残している間は以前のトークンを検証できる", func(t *testing.T) {
+		rotated := newTestKeySet(t, newKey, oldKey.Public())
+		useKeySet(t, rotated)
+		if _, err := ValidateRefreshToken(oldToken); err != nil {
+			t.Errorf("expected valid token, got %v", err)
+		}
+		if keys := rotated.JWKS().Keys; len(keys) != 2 || keys[0].Kid != rotated.KeyID() {
+			t.Errorf("expected signing key first in jwks, got %+v", keys)
+		}
+	})
+
+	t.Run("異常系: 以前の鍵を外したら以前のトークンは無効", func(t *testing.T) {
+		useKeySet(t, newTestKeySet(t, newKey))
+		if _, err := ValidateRefreshToken(oldToken); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+}
+
+func TestKeySet_LegacySecret(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ks := newTestKeySet(t, edKey)
+	useKeySet(t, ks)
+	secret := []byte("legacy-secret-key-for-testing-purpose-at-least-64-characters-long")
+	issuedAt := time.Now().Add(-time.Hour)
+	claims := Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)), IssuedAt: jwt.NewNumericDate(issuedAt)}}
+	legacyToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	useLegacySecret := func(t *testing.T, retiredAt time.Time) {
+		ks.legacySecret, ks.legacyRetiredAt = secret, retiredAt
+		t.Cleanup(func() { ks.legacySecret, ks.legacyRetiredAt = nil, time.Time{} })
+	}
+
+	t.Run("異常系: 共有シークレットが設定されていない場合は HS256 のトークンを受け付けない", func(t *testing.T) {
+		if _, err := ValidateToken(legacyToken); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 移行前に発行した kid のない HS256 のトークンを検証できる", func(t *testing.T) {
+		useLegacySecret(t, issuedAt.Add(time.Minute))
+		if _, err := ValidateToken(legacyToken); err != nil {
+			t.Errorf("expected valid token, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 切り替えの後に発行された HS256 のトークン", func(t *testing.T) {
+		useLegacySecret(t, issuedAt.Add(-time.Minute))
+		if _, err := ValidateToken(legacyToken); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+		noIssuedAt, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: claims.ExpiresAt}}).SignedString(secret)
+		if _, err := ValidateToken(noIssuedAt); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken for token without iat, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 切り替えから猶予期間が過ぎた後の HS256 のトークン", func(t *testing.T) {
+		useLegacySecret(t, time.Now().Add(-legacyTokenGracePeriod))
+		old, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: claims.ExpiresAt,
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-legacyTokenGracePeriod - time.Hour)),
+		}}).SignedString(secret)
+		if _, err := ValidateToken(old); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 署名鍵の kid を付けた HS256 のトークン（公開鍵をシークレットにする攻撃）", func(t *testing.T) {
+		useLegacySecret(t, issuedAt.Add(time.Minute))
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = ks.KeyID()
+		forged, _ := token.SignedString([]byte(edKey.Public().(ed25519.PublicKey)))
+		if _, err := ValidateToken(forged); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+}
+
+func TestLoadKeySetFromEnv(t *testing.T) {
+	_, signingKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, oldKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	dir := t.TempDir()
+	signingFile := filepath.Join(dir, "signing.pem")
+	if err := os.WriteFile(signingFile, []byte(privateKeyPEM(t, rsaKey)), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	oldKeyFile := filepath.Join(dir, "old.pem")
+	if err := os.WriteFile(oldKeyFile, []byte(publicKeyPEM(t, oldKey.Public())), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	setEnv := func(t *testing.T, env map[string]string) {
+		for _, name := range []string{"JWT_SIGNING_KEY", "JWT_SIGNING_KEY_FILE", "JWT_VERIFICATION_KEYS", "JWT_VERIFICATION_KEY_FILES", "JWT_SECRET", "JWT_SECRET_RETIRED_AT"} {
+			t.Setenv(name, env[name])
+		}
+	}
+
+	t.Run("正常系: 1行に書いた PEM の署名鍵と以前の鍵を読み込む", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"JWT_SIGNING_KEY":       strings.ReplaceAll(privateKeyPEM(t, signingKey), "\n", `\n`),
+			"JWT_VERIFICATION_KEYS": publicKeyPEM(t, oldKey.Public()) + publicKeyPEM(t, &rsaKey.PublicKey),
+			"JWT_SECRET":            "legacy",
+			"JWT_SECRET_RETIRED_AT": time.Now().Add(-time.Hour).Format(time.RFC3339),
+		})
+		ks, err := LoadKeySetFromEnv()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if keys := ks.JWKS().Keys; len(keys) != 3 || keys[0].Alg != "EdDSA" || keys[2].Alg != "RS256" {
+			t.Errorf("unexpected jwks: %+v", keys)
+		}
+		if string(ks.legacySecret) != "legacy" {
+			t.Error("expected legacy secret")
+		}
+	})
+
+	t.Run("正常系: ファイルから読み込む", func(t *testing.T) {
+		setEnv(t, map[string]string{"JWT_SIGNING_KEY_FILE": signingFile, "JWT_VERIFICATION_KEY_FILES": oldKeyFile})
+		ks, err := LoadKeySetFromEnv()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if keys := ks.JWKS().Keys; len(keys) != 2 || keys[0].Alg != "RS256" || keys[1].Alg != "EdDSA" {
+			t.Errorf("unexpected jwks: %+v", keys)
+		}
+	})
+
+	t.Run("正常系: 切り替えから猶予期間が過ぎた共有シークレットは使わない", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"JWT_SIGNING_KEY":       privateKeyPEM(t, signingKey),
+			"JWT_SECRET":            "legacy",
+			"JWT_SECRET_RETIRED_AT": time.Now().Add(-legacyTokenGracePeriod).Format(time.RFC3339),
+		})
+		ks, err := LoadKeySetFromEnv()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if ks.legacySecret != nil {
+			t.Error("expected legacy secret to be ignored")
+		}
+	})
+
+	t.Run("異常系: 共有シークレットに切り替えの日時がない・不正", func(t *testing.T) {
+		for _, retiredAt := range []string{"", "2026-01-01"} {
+			setEnv(t, map[string]string{"JWT_SIGNING_KEY": privateKeyPEM(t, signingKey), "JWT_SECRET": "legacy", "JWT_SECRET_RETIRED_AT": retiredAt})
+			if _, err := LoadKeySetFromEnv(); err == nil {
+				t.Errorf("expected error for JWT_SECRET_RETIRED_AT=%q", retiredAt)
+			}
+		}
+	})
+
+	t.Run("異常系: 署名鍵がない", func(t *testing.T) {
+		setEnv(t, map[string]string{"JWT_SECRET": "legacy"})
+		if _, err := LoadKeySetFromEnv(); !errors.Is(err, ErrSigningKeyNotConfigured) {
+			t.Errorf("expected ErrSigningKeyNotConfigured, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 署名鍵を環境変数とファイルの両方で指定", func(t *testing.T) {
+		setEnv(t, map[string]string{"JWT_SIGNING_KEY": privateKeyPEM(t, signingKey), "JWT_SIGNING_KEY_FILE": signingFile})
+		if _, err := LoadKeySetFromEnv(); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("異常系: 検証鍵に公開鍵以外の PEM", func(t *testing.T) {
+		setEnv(t, map[string]string{"JWT_SIGNING_KEY": privateKeyPEM(t, signingKey), "JWT_VERIFICATION_KEYS": privateKeyPEM(t, oldKey)})
+		if _, err := LoadKeySetFromEnv(); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,134 @@
+// Package jwk は JSON Web Key（RFC 7517）の公開鍵を扱う
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// ErrUnsupportedKey は鍵の種類・曲線に対応していない、または値が不正な場合のエラー
+var ErrUnsupportedKey = errors.New("unsupported json web key")
+
+// Key は公開鍵の JSON Web Key（RSA・EC の P-256・OKP の Ed25519 に対応する）
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA の公開鍵
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC・OKP の公開鍵
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// Set は JSON Web Key Set
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// PublicKey は鍵を *rsa.PublicKey・*ecdsa.PublicKey・ed25519.PublicKey に変換する
+func (k Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			// 2048 ビット未満の鍵は受け付けない
+			return nil, ErrUnsupportedKey
+		}
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+		if exponent < 3 {
+			return nil, ErrUnsupportedKey
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+	case "EC":
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if k.Crv != "P-256" || errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, ErrUnsupportedKey
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, ErrUnsupportedKey
+		}
+		return key, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if k.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// FromPublicKey は公開鍵を JSON Web Key に変換する
+func FromPublicKey(key crypto.PublicKey, kid, alg string) (Key, error) {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return Key{
+			Kty: "RSA", Kid: kid, Use: "sig", Alg: alg,
+			N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return Key{}, ErrUnsupportedKey
+		}
+		return Key{
+			Kty: "EC", Kid: kid, Use: "sig", Alg: alg, Crv: "P-256",
+			X: base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			Y: base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		}, nil
+	case ed25519.PublicKey:
+		return Key{Kty: "OKP", Kid: kid, Use: "sig", Alg: alg, Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(key)}, nil
+	}
+	return Key{}, ErrUnsupportedKey
+}
+
+// Thumbprint は鍵の JWK Thumbprint（RFC 7638、SHA-256 の Base64URL）を返す
+// 鍵の種類ごとの必須のメンバーだけから計算するため、kid・use・alg によらず同じ鍵は同じ値になる
+func (k Key) Thumbprint() (string, error) {
+	var members interface{}
+	switch k.Kty {
+	case "RSA":
+		// encoding/json は構造体のフィールドを宣言順に出力するため、メンバー名の辞書順に並べる
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	default:
+		return "", ErrUnsupportedKey
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
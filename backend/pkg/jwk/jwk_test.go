@@ -0,0 +1,85 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestKey_RoundTrip(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name string
+		key  crypto.PublicKey
+		alg  string
+	}{
+		{name: "正常系: RSA", key: &rsaKey.PublicKey, alg: "RS256"},
+		{name: "正常系: EC（P-256）", key: &ecKey.PublicKey, alg: "ES256"},
+		{name: "正常系: OKP（Ed25519）", key: edPublic, alg: "EdDSA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := FromPublicKey(tt.key, "kid-1", tt.alg)
+			if err != nil {
+				t.Fatalf("FromPublicKey failed: %v", err)
+			}
+			if k.Kid != "kid-1" || k.Alg != tt.alg || k.Use != "sig" {
+				t.Errorf("unexpected key: %+v", k)
+			}
+			got, err := k.PublicKey()
+			if err != nil {
+				t.Fatalf("PublicKey failed: %v", err)
+			}
+			if !got.(interface{ Equal(crypto.PublicKey) bool }).Equal(tt.key) {
+				t.Error("expected the same public key")
+			}
+		})
+	}
+
+	t.Run("異常系: 2048 ビット未満の RSA 鍵", func(t *testing.T) {
+		small, _ := rsa.GenerateKey(rand.Reader, 1024)
+		k, _ := FromPublicKey(&small.PublicKey, "", "RS256")
+		if _, err := k.PublicKey(); !errors.Is(err, ErrUnsupportedKey) {
+			t.Errorf("expected ErrUnsupportedKey, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 曲線上にない EC の座標", func(t *testing.T) {
+		k, _ := FromPublicKey(&ecKey.PublicKey, "", "ES256")
+		k.Y = k.X
+		if _, err := k.PublicKey(); !errors.Is(err, ErrUnsupportedKey) {
+			t.Errorf("expected ErrUnsupportedKey, got %v", err)
+		}
+	})
+}
+
+func TestKey_Thumbprint(t *testing.T) {
+	t.Run("正常系: RFC 7638 の例と同じ値になる", func(t *testing.T) {
+		var k Key
+		if err := json.Unmarshal([]byte(`{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`), &k); err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+		got, err := k.Thumbprint()
+		if err != nil {
+			t.Fatalf("Thumbprint failed: %v", err)
+		}
+		if got != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+			t.Errorf("unexpected thumbprint: %s", got)
+		}
+	})
+
+	t.Run("異常系: 対応していない鍵の種類", func(t *testing.T) {
+		if _, err := (Key{Kty: "oct"}).Thumbprint(); !errors.Is(err, ErrUnsupportedKey) {
+			t.Errorf("expected ErrUnsupportedKey, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go-shisha-backend/pkg/logging"
+)
+
+// LogMailer はメールを送信せずにログに出力する Mailer（開発・テスト用）
+// path を指定した場合は送信内容をファイルにも追記する
+type LogMailer struct {
+	path string
+	from string
+
+	mu sync.Mutex
+}
+
+// NewLogMailer は新しい LogMailer を作成する（path が空の場合はログにのみ出力する）
+func NewLogMailer(path, from string) *LogMailer {
+	return &LogMailer{path: path, from: from}
+}
+
+// Send はメールの内容をログに出力する
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	// 開発環境でリンクを確認できるよう本文も出力する
+	logging.L.Info("mail sent", "mailer", "LogMailer", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	if m.path == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mail log file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(buildMessage(m.from, msg, time.Now()), "\r\n\r\n"...)); err != nil {
+		return fmt.Errorf("failed to write mail log file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"go-shisha-backend/pkg/logging"
+)
+
+// Message は送信するメール（本文はプレーンテキスト）
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer はメールを送信するインターフェース
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewFromEnv は環境変数の設定に応じた Mailer を返す
+// MAILER=smtp の場合は SMTP_HOST・SMTP_PORT（既定値 587）・SMTP_USERNAME・SMTP_PASSWORD の SMTP サーバーから送信する
+// それ以外の場合は送信せずにログに出力する（開発・テスト用。MAIL_LOG_FILE を指定するとファイルにも追記する）
+// 送信元は MAIL_FROM（既定値 no-reply@localhost）
+func NewFromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM: %w", err)
+	}
+
+	switch driver := os.Getenv("MAILER"); driver {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is not set")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+	case "", "log":
+		if os.Getenv("APP_ENV") == "production" {
+			logging.L.Warn("mailer is not configured; emails will only be logged", "mailer", "LogMailer")
+		}
+		return NewLogMailer(os.Getenv("MAIL_LOG_FILE"), from), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER: %s", driver)
+	}
+}
+
+// buildMessage は RFC 5322 形式のメールを組み立てる（件名は日本語を含むため MIME エンコードする）
+func buildMessage(from string, msg Message, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes()
+}
@@ -0,0 +1,131 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogMailer_WritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m := NewLogMailer(path, "no-reply@example.com")
+
+	if err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "パスワードの再設定", Body: "line1\nline2"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read mail log: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{"From: no-reply@example.com\r\n", "To: user@example.com\r\n", "Subject: =?UTF-8?b?", "line1\r\nline2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected mail log to contain %q, got %q", want, got)
+		}
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "未設定の場合はログに出力する", env: map[string]string{}},
+		{name: "SMTP", env: map[string]string{"MAILER": "smtp", "SMTP_HOST": "smtp.example.com"}},
+		{name: "SMTP_HOST が未設定", env: map[string]string{"MAILER": "smtp"}, wantErr: true},
+		{name: "不明な MAILER", env: map[string]string{"MAILER": "carrier-pigeon"}, wantErr: true},
+		{name: "不正な MAIL_FROM", env: map[string]string{"MAIL_FROM": "not an address"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"MAILER", "MAIL_FROM", "MAIL_LOG_FILE", "SMTP_HOST", "SMTP_PORT"} {
+				t.Setenv(key, tt.env[key])
+			}
+			m, err := NewFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && m == nil {
+				t.Fatal("expected mailer")
+			}
+		})
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	// 認証・STARTTLS なしの最小限の SMTP サーバー
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var commands []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "DATA":
+				reply("354 go ahead")
+				var body []string
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					data = strings.TrimRight(data, "\r\n")
+					if data == "." {
+						break
+					}
+					body = append(body, data)
+				}
+				commands = append(commands, "DATA:"+strings.Join(body, "\n"))
+				reply("250 OK")
+			case "QUIT":
+				reply("221 bye")
+				received <- commands
+				return
+			default:
+				commands = append(commands, line)
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	m := NewSMTPMailer(host, port, "", "", "Go Shisha <no-reply@example.com>")
+	if err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello", Body: "hi"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	commands := <-received
+	if len(commands) != 3 {
+		t.Fatalf("unexpected SMTP commands: %q", commands)
+	}
+	if commands[0] != "MAIL FROM:<no-reply@example.com>" || commands[1] != "RCPT TO:<user@example.com>" {
+		t.Errorf("unexpected envelope: %q", commands[:2])
+	}
+	if !strings.Contains(commands[2], "Subject: Hello") || !strings.HasSuffix(commands[2], "\nhi") {
+		t.Errorf("unexpected message: %q", commands[2])
+	}
+}
@@ -0,0 +1,58 @@
+package oidc
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// providerNamePattern はプロバイダーの識別子に使える文字（URL のパスと環境変数名に使う）
+var providerNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// defaultScopes は OIDC_<NAME>_SCOPES が未設定の場合に要求するスコープ
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// ConfigsFromEnv は環境変数からプロバイダーの設定を読み込む
+// OIDC_PROVIDERS にカンマ区切りで識別子（google,line など）を並べ、識別子ごとに次の環境変数を設定する
+//   - OIDC_<NAME>_ISSUER: 発行者の URL（必須）
+//   - OIDC_<NAME>_CLIENT_ID・OIDC_<NAME>_CLIENT_SECRET: クライアントの認証情報（必須）
+//   - OIDC_<NAME>_SCOPES: スペース区切りのスコープ（既定値 "openid email profile"）
+//   - OIDC_<NAME>_REDIRECT_URL: 認可コードを受け取る URL（既定値 redirectBase + "/" + 識別子）
+//
+// OIDC_PROVIDERS が未設定の場合は空のスライスを返す
+func ConfigsFromEnv(redirectBase string) ([]Config, error) {
+	var configs []Config
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !providerNamePattern.MatchString(name) || seen[name] {
+			return nil, fmt.Errorf("invalid OIDC provider name: %q", name)
+		}
+		seen[name] = true
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if config.Issuer == "" || config.ClientID == "" || config.ClientSecret == "" {
+			return nil, fmt.Errorf("%sISSUER, %sCLIENT_ID and %sCLIENT_SECRET are required", prefix, prefix, prefix)
+		}
+		if config.RedirectURL == "" {
+			config.RedirectURL = strings.TrimSuffix(redirectBase, "/") + "/" + name
+		}
+		if len(config.Scopes) == 0 {
+			config.Scopes = defaultScopes
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
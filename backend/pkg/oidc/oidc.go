@@ -0,0 +1,350 @@
+// Package oidc は OpenID Connect の認可コードフロー（PKCE）でログインする汎用のクライアントを提供する
+// ディスカバリー（/.well-known/openid-configuration）でエンドポイントと署名鍵を取得するため、プロバイダーごとの実装は不要
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"go-shisha-backend/pkg/jwk"
+)
+
+// センチネルエラー
+var (
+	// ErrDiscovery はディスカバリー・署名鍵の取得に失敗した場合のエラー
+	ErrDiscovery = errors.New("oidc discovery failed")
+	// ErrTokenExchange は認可コードをトークンに交換できない場合のエラー
+	ErrTokenExchange = errors.New("oidc token exchange failed")
+	// ErrInvalidIDToken は ID トークンの署名・発行者・対象・有効期限・nonce を検証できない場合のエラー
+	ErrInvalidIDToken = errors.New("invalid oidc id token")
+)
+
+// clockSkew は ID トークンの有効期限・発行日時の検証で許容する時刻のずれ
+const clockSkew = time.Minute
+
+// keyRefreshInterval は未知の kid の ID トークンを受け取った場合に署名鍵を取得し直す最短の間隔
+const keyRefreshInterval = time.Minute
+
+// Config はプロバイダーの設定
+type Config struct {
+	// Name は URL・ユーザーとの紐付けに使う識別子（google など）
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL はプロバイダーが認可コードを返すフロントエンドの URL
+	RedirectURL string
+	// Scopes は要求するスコープ（openid は常に含める）
+	Scopes []string
+}
+
+// Metadata はディスカバリーで取得するプロバイダーのメタデータ
+type Metadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// IDToken は検証した ID トークンのクレーム
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider は1つの OpenID プロバイダーのクライアント
+// メタデータと署名鍵は最初に使うときに取得し、プロバイダーが停止していてもサーバーは起動できるようにする
+type Provider struct {
+	config     Config
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	metadata  *Metadata
+	keys      map[string]crypto.PublicKey
+	keysFetch time.Time
+}
+
+// NewProvider は新しい Provider を作成する（httpClient が nil の場合はタイムアウト10秒のクライアントを使う）
+func NewProvider(config Config, httpClient *http.Client) *Provider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{config: config, httpClient: httpClient}
+}
+
+// Name はプロバイダーの識別子を返す
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL はユーザーをリダイレクトする認可エンドポイントの URL を返す
+// codeVerifier は PKCE の code_verifier で、URL には S256 のチャレンジだけを含める
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	scopes := []string{"openid"}
+	for _, scope := range p.config.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange は認可コードをトークンエンドポイントで交換し、ID トークンを返す
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	// client_secret_basic に対応していないプロバイダーにはフォームでクライアントシークレットを送る
+	useBasic := len(metadata.TokenEndpointAuthMethodsSupported) == 0 || contains(metadata.TokenEndpointAuthMethodsSupported, "client_secret_basic")
+	if !useBasic {
+		form.Set("client_id", p.config.ClientID)
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if useBasic {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var body struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	status, err := p.doJSON(req, &body)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+	if status != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("%w: status %d %s", ErrTokenExchange, status, body.Error)
+	}
+	return body.IDToken, nil
+}
+
+// VerifyIDToken は ID トークンの署名・発行者・対象・有効期限と、認可リクエストの nonce を検証する
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	methods := []string{"RS256", "ES256", "EdDSA"}
+	if p.config.ClientSecret != "" {
+		// クライアントシークレットで署名するプロバイダー（LINE など）に対応する
+		methods = append(methods, "HS256")
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() == "HS256" {
+			return []byte(p.config.ClientSecret), nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	// 複数の対象に発行されたトークンは azp がこのクライアントの場合だけ受け付ける
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("%w: azp mismatch", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" || nonce == "" || claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	return &IDToken{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover はメタデータを取得する（取得できた場合は以降キャッシュを返す）
+func (p *Provider) discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	var metadata Metadata
+	status, err := p.doJSON(req, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrDiscovery, status)
+	}
+	// 発行者が設定と異なるメタデータは受け付けない（OpenID Connect Discovery 4.3）
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer || metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: invalid metadata for issuer %s", ErrDiscovery, p.config.Issuer)
+	}
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// publicKey は kid の署名鍵を返す。知らない kid の場合は鍵のローテーションに備えて取得し直す
+func (p *Provider) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetch) < keyRefreshInterval {
+		return nil, ErrInvalidIDToken
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadata.JWKSURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	var set jwk.Set
+	status, err := p.doJSON(req, &set)
+	if err != nil || status != http.StatusOK {
+		return nil, fmt.Errorf("%w: failed to fetch jwks (status %d): %v", ErrDiscovery, status, err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// 対応していない種類の鍵は無視する
+		if key, err := k.PublicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	p.keys = keys
+	p.keysFetch = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, ErrInvalidIDToken
+}
+
+// lookupKey は取得済みの鍵から kid の鍵を探す（kid がない場合は鍵が1つだけのときにそれを使う）
+func (p *Provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// doJSON はリクエストを送信し、レスポンスの JSON を v に読み込んでステータスコードを返す
+func (p *Provider) doJSON(req *http.Request, v interface{}) (int, error) {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
+
+// idTokenClaims は ID トークンのクレーム
+type idTokenClaims struct {
+	Nonce           string    `json:"nonce"`
+	AuthorizedParty string    `json:"azp"`
+	Email           string    `json:"email"`
+	EmailVerified   boolClaim `json:"email_verified"`
+	Name            string    `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// boolClaim は真偽値または文字列（"true"）の真偽値のクレーム
+type boolClaim bool
+
+func (b *boolClaim) UnmarshalJSON(data []byte) error {
+	*b = boolClaim(string(data) == "true" || string(data) == `"true"`)
+	return nil
+}
+
+// RandomString はstate・nonce・PKCE の code_verifier に使う推測できない文字列（256ビット）を返す
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge は PKCE の code_verifier から S256 の code_challenge を求める
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
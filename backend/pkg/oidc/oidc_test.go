@@ -0,0 +1,218 @@
+// oidctest が oidc を使うため、外部テストパッケージにする
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"go-shisha-backend/pkg/oidc"
+	"go-shisha-backend/pkg/oidc/oidctest"
+)
+
+const redirectURL = "https://shisha.example/auth/oidc/mock"
+
+var testUser = oidctest.User{Subject: "user-1", Email: "user@example.com", EmailVerified: true, Name: "Mock User"}
+
+// login は認可 URL の発行からトークンの交換・ID トークンの検証までを行う
+func login(t *testing.T, provider *oidc.Provider, server *oidctest.Provider) (*oidc.IDToken, error) {
+	t.Helper()
+	ctx := context.Background()
+	state, _ := oidc.RandomString()
+	nonce, _ := oidc.RandomString()
+	verifier, _ := oidc.RandomString()
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+	code, returnedState, err := server.Authorize(authURL, testUser)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if returnedState != state {
+		t.Fatalf("expected state %q, got %q", state, returnedState)
+	}
+	rawIDToken, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	return provider.VerifyIDToken(ctx, rawIDToken, nonce)
+}
+
+func TestProvider_Login(t *testing.T) {
+	server := oidctest.NewProvider("client-id", "client secret")
+	defer server.Close()
+
+	t.Run("正常系: 認可 URL に PKCE・nonce・スコープを含め、ID トークンを検証できる", func(t *testing.T) {
+		provider := oidc.NewProvider(server.Config("mock", redirectURL), nil)
+		authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+		if err != nil {
+			t.Fatalf("AuthCodeURL failed: %v", err)
+		}
+		q, _ := url.Parse(authURL)
+		query := q.Query()
+		if query.Get("scope") != "openid email profile" || query.Get("code_challenge") != oidc.CodeChallenge("verifier") ||
+			query.Get("nonce") != "nonce" || query.Get("redirect_uri") != redirectURL {
+			t.Errorf("unexpected authorization url: %s", authURL)
+		}
+
+		idToken, err := login(t, provider, server)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if idToken.Subject != "user-1" || idToken.Email != "user@example.com" || !idToken.EmailVerified || idToken.Name != "Mock User" {
+			t.Errorf("unexpected id token: %+v", idToken)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "異常系: 別のクライアント向けの ID トークン", modify: func(claims jwt.MapClaims) { claims["aud"] = "other-client" }},
+		{name: "異常系: 別の発行者の ID トークン", modify: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example" }},
+		{name: "異常系: 有効期限切れの ID トークン", modify: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "異常系: nonce が異なる", modify: func(claims jwt.MapClaims) { claims["nonce"] = "replayed" }},
+		{name: "異常系: 複数の対象に発行され azp が異なる", modify: func(claims jwt.MapClaims) {
+			claims["aud"] = []string{"client-id", "other-client"}
+			claims["azp"] = "other-client"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.ModifyClaims = tt.modify
+			defer func() { server.ModifyClaims = nil }()
+			provider := oidc.NewProvider(server.Config("mock", redirectURL), nil)
+			if _, err := login(t, provider, server); !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Errorf("expected ErrInvalidIDToken, got %v", err)
+			}
+		})
+	}
+
+	t.Run("異常系: code_verifier が異なる場合は交換できない", func(t *testing.T) {
+		provider := oidc.NewProvider(server.Config("mock", redirectURL), nil)
+		authURL, _ := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+		code, _, _ := server.Authorize(authURL, testUser)
+		if _, err := provider.Exchange(context.Background(), code, "other-verifier"); !errors.Is(err, oidc.ErrTokenExchange) {
+			t.Errorf("expected ErrTokenExchange, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 認可コードは一度しか交換できない", func(t *testing.T) {
+		provider := oidc.NewProvider(server.Config("mock", redirectURL), nil)
+		authURL, _ := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+		code, _, _ := server.Authorize(authURL, testUser)
+		if _, err := provider.Exchange(context.Background(), code, "verifier"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := provider.Exchange(context.Background(), code, "verifier"); !errors.Is(err, oidc.ErrTokenExchange) {
+			t.Errorf("expected ErrTokenExchange, got %v", err)
+		}
+	})
+
+	t.Run("異常系: クライアントシークレットが誤っている", func(t *testing.T) {
+		config := server.Config("mock", redirectURL)
+		config.ClientSecret = "wrong"
+		provider := oidc.NewProvider(config, nil)
+		authURL, _ := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+		code, _, _ := server.Authorize(authURL, testUser)
+		if _, err := provider.Exchange(context.Background(), code, "verifier"); !errors.Is(err, oidc.ErrTokenExchange) {
+			t.Errorf("expected ErrTokenExchange, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 取得済みの鍵で署名されていない ID トークン", func(t *testing.T) {
+		provider := oidc.NewProvider(server.Config("mock", redirectURL), nil)
+		if _, err := login(t, provider, server); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		// 直前に鍵を取得しているため、知らない kid の鍵はすぐには取得し直さない
+		if err := server.RotateKey(); err != nil {
+			t.Fatalf("RotateKey failed: %v", err)
+		}
+		if _, err := login(t, provider, server); !errors.Is(err, oidc.ErrInvalidIDToken) {
+			t.Errorf("expected ErrInvalidIDToken, got %v", err)
+		}
+	})
+}
+
+func TestProvider_Discovery(t *testing.T) {
+	t.Run("異常系: メタデータの発行者が設定と異なる", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"issuer":"https://evil.example","authorization_endpoint":"https://evil.example/authorize","token_endpoint":"https://evil.example/token","jwks_uri":"https://evil.example/jwks"}`))
+		}))
+		defer server.Close()
+		provider := oidc.NewProvider(oidc.Config{Name: "mock", Issuer: server.URL, ClientID: "client-id"}, nil)
+		if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); !errors.Is(err, oidc.ErrDiscovery) {
+			t.Errorf("expected ErrDiscovery, got %v", err)
+		}
+	})
+
+	t.Run("異常系: プロバイダーに接続できない", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		provider := oidc.NewProvider(oidc.Config{Name: "mock", Issuer: server.URL, ClientID: "client-id"}, nil)
+		if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); !errors.Is(err, oidc.ErrDiscovery) {
+			t.Errorf("expected ErrDiscovery, got %v", err)
+		}
+	})
+}
+
+func TestConfigsFromEnv(t *testing.T) {
+	t.Run("正常系: 識別子ごとの設定を読み込み、既定値を補う", func(t *testing.T) {
+		t.Setenv("OIDC_PROVIDERS", "google, LINE")
+		t.Setenv("OIDC_GOOGLE_ISSUER", "https://accounts.google.com")
+		t.Setenv("OIDC_GOOGLE_CLIENT_ID", "google-client")
+		t.Setenv("OIDC_GOOGLE_CLIENT_SECRET", "google-secret")
+		t.Setenv("OIDC_LINE_ISSUER", "https://access.line.me")
+		t.Setenv("OIDC_LINE_CLIENT_ID", "line-client")
+		t.Setenv("OIDC_LINE_CLIENT_SECRET", "line-secret")
+		t.Setenv("OIDC_LINE_SCOPES", "openid email")
+		t.Setenv("OIDC_LINE_REDIRECT_URL", "https://shisha.example/line")
+
+		configs, err := oidc.ConfigsFromEnv("https://shisha.example/auth/oidc/")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(configs) != 2 {
+			t.Fatalf("expected 2 configs, got %d", len(configs))
+		}
+		if configs[0].Name != "google" || configs[0].RedirectURL != "https://shisha.example/auth/oidc/google" || len(configs[0].Scopes) != 3 {
+			t.Errorf("unexpected google config: %+v", configs[0])
+		}
+		if configs[1].Name != "line" || configs[1].RedirectURL != "https://shisha.example/line" || len(configs[1].Scopes) != 2 {
+			t.Errorf("unexpected line config: %+v", configs[1])
+		}
+	})
+
+	t.Run("正常系: 未設定の場合はプロバイダーなし", func(t *testing.T) {
+		t.Setenv("OIDC_PROVIDERS", "")
+		configs, err := oidc.ConfigsFromEnv("https://shisha.example")
+		if err != nil || len(configs) != 0 {
+			t.Errorf("expected no configs, got %v (err=%v)", configs, err)
+		}
+	})
+
+	t.Run("異常系: クライアントの認証情報がない", func(t *testing.T) {
+		t.Setenv("OIDC_PROVIDERS", "google")
+		t.Setenv("OIDC_GOOGLE_ISSUER", "https://accounts.google.com")
+		t.Setenv("OIDC_GOOGLE_CLIENT_ID", "")
+		if _, err := oidc.ConfigsFromEnv("https://shisha.example"); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("異常系: 識別子に使えない文字", func(t *testing.T) {
+		t.Setenv("OIDC_PROVIDERS", "my-idp")
+		if _, err := oidc.ConfigsFromEnv("https://shisha.example"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
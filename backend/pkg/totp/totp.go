@@ -0,0 +1,118 @@
+// Package totp は RFC 6238（TOTP）のワンタイムパスワードを扱う
+// Google Authenticator などの認証アプリと互換性のある既定値（HMAC-SHA1・30秒・6桁）を使う
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Period はコードが切り替わる間隔
+	Period = 30 * time.Second
+	// Digits はコードの桁数
+	Digits = 6
+	// secretSize はシークレットのバイト数（RFC 4226 の推奨値 160 ビット）
+	secretSize = 20
+)
+
+// ErrInvalidSecret はシークレットが Base32 として不正な場合のエラー
+var ErrInvalidSecret = errors.New("invalid totp secret")
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret はランダムなシークレットを Base32（パディングなし）で生成する
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(b), nil
+}
+
+// Counter は時刻 t の時間ステップ（RFC 6238 の T）を返す
+func Counter(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code は時刻 t のコードを返す
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(Counter(t)), Digits), nil
+}
+
+// Validate は code が時刻 t の前後 skew ステップ以内のコードと一致するかを検証し、一致した時間ステップを返す
+// 同じコードの再利用を防ぐため、呼び出し側は返された時間ステップ以前のコードを受け付けないようにする
+func Validate(secret, code string, t time.Time, skew int) (int64, bool, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(code) != Digits {
+		return 0, false, nil
+	}
+	current := Counter(t)
+	for i := -skew; i <= skew; i++ {
+		counter := current + int64(i)
+		if counter < 0 {
+			continue
+		}
+		if hmac.Equal([]byte(hotp(key, uint64(counter), Digits)), []byte(code)) {
+			return counter, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// URI は認証アプリに QR コードで読み込ませる otpauth URI を返す
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period/time.Second)))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// decodeSecret は Base32 のシークレットをデコードする（小文字・空白・パディングを許容する）
+func decodeSecret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+	key, err := secretEncoding.DecodeString(normalized)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+// hotp は RFC 4226 の HOTP 値を digits 桁の文字列で返す
+func hotp(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// 動的切り捨て（RFC 4226 5.3）
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
@@ -0,0 +1,99 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B のテストベクター（SHA1、シークレットは ASCII の "12345678901234567890"）
+func TestHOTP_RFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		counter := Counter(time.Unix(tt.unix, 0))
+		if got := hotp(key, uint64(counter), 8); got != tt.want {
+			t.Errorf("time %d: expected %s, got %s", tt.unix, tt.want, got)
+		}
+	}
+}
+
+func TestCodeAndValidate(t *testing.T) {
+	secret := secretEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	code, err := Code(secret, now)
+	if err != nil {
+		t.Fatalf("Code failed: %v", err)
+	}
+	// 8桁のテストベクターの下6桁と一致する
+	if code != "050471" {
+		t.Fatalf("expected 050471, got %s", code)
+	}
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		code    string
+		wantOK  bool
+		wantCtr int64
+	}{
+		{"同じ時間ステップ", now, code, true, Counter(now)},
+		{"1ステップ後まで許容", now.Add(Period), code, true, Counter(now)},
+		{"2ステップ後は拒否", now.Add(2 * Period), code, false, 0},
+		{"桁数が違う", now, "50471", false, 0},
+		{"誤ったコード", now, "000000", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, ok, err := Validate(secret, tt.code, tt.at, 1)
+			if err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+			if ok != tt.wantOK || counter != tt.wantCtr {
+				t.Errorf("expected (%d, %v), got (%d, %v)", tt.wantCtr, tt.wantOK, counter, ok)
+			}
+		})
+	}
+
+	// 小文字・空白を含むシークレットも受け付ける
+	if _, ok, err := Validate(strings.ToLower(secret[:4])+" "+secret[4:], code, now, 0); err != nil || !ok {
+		t.Errorf("expected normalized secret to be accepted, got ok=%v err=%v", ok, err)
+	}
+	if _, _, err := Validate("not base32!", code, now, 0); err != ErrInvalidSecret {
+		t.Errorf("expected ErrInvalidSecret, got %v", err)
+	}
+}
+
+func TestGenerateSecretAndURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %v", err)
+	}
+	if key, err := decodeSecret(secret); err != nil || len(key) != secretSize {
+		t.Fatalf("expected %d byte secret, got %d (err=%v)", secretSize, len(key), err)
+	}
+
+	uri, err := url.Parse(URI("Go Shisha", "user@example.com", secret))
+	if err != nil {
+		t.Fatalf("failed to parse URI: %v", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Go Shisha:user@example.com" {
+		t.Errorf("unexpected URI: %s", uri)
+	}
+	query := uri.Query()
+	if query.Get("secret") != secret || query.Get("issuer") != "Go Shisha" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("unexpected URI parameters: %v", query)
+	}
+}
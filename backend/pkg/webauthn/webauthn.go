@@ -0,0 +1,379 @@
+// Package webauthn は WebAuthn（パスキー）のリライングパーティーとして、登録・認証のレスポンスを検証する
+// https://www.w3.org/TR/webauthn-2/ の 7 章の手順のうち、アテステーションの信頼性の確認を除いたものを実装する
+// （登録時は attestation: "none" を要求し、認証器の製造元は確認しない）
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Timeout はブラウザーに渡す登録・認証の操作のタイムアウト
+const Timeout = 5 * time.Minute
+
+// challengeSize はチャレンジのバイト数
+const challengeSize = 32
+
+// maxCredentialIDLength は受け付けるクレデンシャルIDの最大長（WebAuthn Level 2 の上限）
+const maxCredentialIDLength = 1023
+
+// authenticatorData のフラグ
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+)
+
+// 検証のエラー
+var (
+	// ErrInvalidResponse はレスポンスの形式が不正な場合のエラー
+	ErrInvalidResponse = errors.New("invalid webauthn response")
+	// ErrChallengeMismatch はチャレンジ・操作の種類・オリジン・RP ID が一致しない場合のエラー
+	ErrChallengeMismatch = errors.New("webauthn client data does not match")
+	// ErrUserNotVerified は認証器でユーザーの存在・本人確認（生体認証・PIN）が行われていない場合のエラー
+	ErrUserNotVerified = errors.New("webauthn user not verified")
+	// ErrInvalidSignature は署名が公開鍵で検証できない場合のエラー
+	ErrInvalidSignature = errors.New("invalid webauthn signature")
+	// ErrSignCountRegression は署名カウンターが前回以下の場合のエラー（認証器が複製された可能性がある）
+	ErrSignCountRegression = errors.New("webauthn sign count did not increase")
+)
+
+// URLEncodedBytes は JSON で Base64URL（パディングなし）の文字列として扱うバイト列
+// PublicKeyCredential.toJSON() と同じ形式で、パディング付きの文字列も受け付ける
+type URLEncodedBytes []byte
+
+func (b URLEncodedBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *URLEncodedBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// RelyingParty はリライングパーティー（このサービス）の設定
+type RelyingParty struct {
+	// ID は RP ID（パスキーを紐付けるドメイン、例: example.com）
+	ID string
+	// Name は認証器に表示するサービス名
+	Name string
+	// Origins は登録・認証を受け付けるオリジン（例: https://example.com）
+	Origins []string
+}
+
+// RPEntity は PublicKeyCredentialRpEntity
+type RPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity は PublicKeyCredentialUserEntity
+type UserEntity struct {
+	// ID はユーザーハンドル（個人情報を含めない）
+	ID          URLEncodedBytes `json:"id" swaggertype:"string"`
+	Name        string          `json:"name"`
+	DisplayName string          `json:"displayName"`
+}
+
+// CredentialParameter は PublicKeyCredentialParameters
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// CredentialDescriptor は PublicKeyCredentialDescriptor
+type CredentialDescriptor struct {
+	Type       string          `json:"type"`
+	ID         URLEncodedBytes `json:"id" swaggertype:"string"`
+	Transports []string        `json:"transports,omitempty"`
+}
+
+// AuthenticatorSelection は AuthenticatorSelectionCriteria
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions は navigator.credentials.create() に渡す PublicKeyCredentialCreationOptions（JSON 形式）
+type CreationOptions struct {
+	Challenge              URLEncodedBytes        `json:"challenge" swaggertype:"string"`
+	RP                     RPEntity               `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int                    `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions は navigator.credentials.get() に渡す PublicKeyCredentialRequestOptions（JSON 形式）
+type RequestOptions struct {
+	Challenge        URLEncodedBytes        `json:"challenge" swaggertype:"string"`
+	Timeout          int                    `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// RegistrationResponse は navigator.credentials.create() の結果（PublicKeyCredential.toJSON() の形式）
+type RegistrationResponse struct {
+	ID       string                       `json:"id"`
+	RawID    URLEncodedBytes              `json:"rawId" swaggertype:"string"`
+	Type     string                       `json:"type"`
+	Response AuthenticatorAttestationData `json:"response"`
+}
+
+// AuthenticatorAttestationData は AuthenticatorAttestationResponse
+type AuthenticatorAttestationData struct {
+	ClientDataJSON    URLEncodedBytes `json:"clientDataJSON" swaggertype:"string"`
+	AttestationObject URLEncodedBytes `json:"attestationObject" swaggertype:"string"`
+	Transports        []string        `json:"transports,omitempty"`
+}
+
+// AssertionResponse は navigator.credentials.get() の結果（PublicKeyCredential.toJSON() の形式）
+type AssertionResponse struct {
+	ID       string                     `json:"id"`
+	RawID    URLEncodedBytes            `json:"rawId" swaggertype:"string"`
+	Type     string                     `json:"type"`
+	Response AuthenticatorAssertionData `json:"response"`
+}
+
+// AuthenticatorAssertionData は AuthenticatorAssertionResponse
+type AuthenticatorAssertionData struct {
+	ClientDataJSON    URLEncodedBytes `json:"clientDataJSON" swaggertype:"string"`
+	AuthenticatorData URLEncodedBytes `json:"authenticatorData" swaggertype:"string"`
+	Signature         URLEncodedBytes `json:"signature" swaggertype:"string"`
+	UserHandle        URLEncodedBytes `json:"userHandle,omitempty" swaggertype:"string"`
+}
+
+// Credential は登録を検証したクレデンシャル
+type Credential struct {
+	ID []byte
+	// PublicKey は COSE_Key 形式の公開鍵
+	PublicKey  []byte
+	SignCount  uint32
+	Transports []string
+}
+
+// collectedClientData は clientDataJSON の内容
+type collectedClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// authenticatorData は authenticatorData を読み取った内容
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+	// 以下は登録時（AT フラグが立っている場合）のみ
+	credentialID []byte
+	publicKey    []byte
+}
+
+// NewChallenge はランダムなチャレンジを生成する
+func NewChallenge() ([]byte, error) {
+	b := make([]byte, challengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// CreationOptions はパスキーの登録のオプションを返す
+// 本人確認（生体認証・PIN）とディスカバラブルクレデンシャルを必須にし、exclude のクレデンシャルは重複して登録させない
+func (rp *RelyingParty) CreationOptions(challenge []byte, user UserEntity, exclude []CredentialDescriptor) CreationOptions {
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+	return CreationOptions{
+		Challenge: challenge,
+		RP:        RPEntity{ID: rp.ID, Name: rp.Name},
+		User:      user,
+		PubKeyCredParams: []CredentialParameter{
+			{Type: "public-key", Alg: AlgES256},
+			{Type: "public-key", Alg: AlgEdDSA},
+			{Type: "public-key", Alg: AlgRS256},
+		},
+		Timeout:            int(Timeout / time.Millisecond),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: "required",
+		},
+		Attestation: "none",
+	}
+}
+
+// RequestOptions はパスキーによる認証のオプションを返す
+// allowCredentials を空にし、認証器に保存されたパスキーからユーザーに選んでもらう
+func (rp *RelyingParty) RequestOptions(challenge []byte) RequestOptions {
+	return RequestOptions{
+		Challenge:        challenge,
+		Timeout:          int(Timeout / time.Millisecond),
+		RPID:             rp.ID,
+		AllowCredentials: []CredentialDescriptor{},
+		UserVerification: "required",
+	}
+}
+
+// VerifyRegistration は登録のレスポンスを challenge で検証し、登録するクレデンシャルを返す
+func (rp *RelyingParty) VerifyRegistration(challenge []byte, resp *RegistrationResponse) (*Credential, error) {
+	if resp.Type != "public-key" {
+		return nil, ErrInvalidResponse
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	value, rest, err := decodeCBOR(resp.Response.AttestationObject)
+	if err != nil || len(rest) != 0 {
+		return nil, ErrInvalidResponse
+	}
+	attestation, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrInvalidResponse
+	}
+	format, _ := attestation["fmt"].(string)
+	rawAuthData, _ := attestation["authData"].([]byte)
+	statement, _ := attestation["attStmt"].(map[interface{}]interface{})
+	if format == "" || statement == nil {
+		return nil, ErrInvalidResponse
+	}
+	// attestation: "none" を要求しているため、"none" 以外の形式のアテステーションは検証せずに無視する
+	if format == "none" && len(statement) != 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredData == 0 {
+		return nil, ErrInvalidResponse
+	}
+	if len(resp.RawID) > 0 && !bytes.Equal(resp.RawID, authData.credentialID) {
+		return nil, ErrInvalidResponse
+	}
+	if _, err := parsePublicKey(authData.publicKey); err != nil {
+		return nil, err
+	}
+	return &Credential{
+		ID:         authData.credentialID,
+		PublicKey:  authData.publicKey,
+		SignCount:  authData.signCount,
+		Transports: resp.Response.Transports,
+	}, nil
+}
+
+// VerifyAssertion は認証のレスポンスを challenge と登録済みのクレデンシャルで検証し、新しい署名カウンターを返す
+// ユーザーハンドルとクレデンシャルの持ち主が一致するかは呼び出し側で確認する
+func (rp *RelyingParty) VerifyAssertion(challenge []byte, credential *Credential, resp *AssertionResponse) (uint32, error) {
+	if resp.Type != "public-key" || !bytes.Equal(resp.RawID, credential.ID) {
+		return 0, ErrInvalidResponse
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+	authData, err := rp.parseAuthenticatorData(resp.Response.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := parsePublicKey(credential.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(resp.Response.ClientDataJSON)
+	signed := append(append([]byte{}, resp.Response.AuthenticatorData...), clientDataHash[:]...)
+	if !key.verify(signed, resp.Response.Signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	// 署名カウンターに対応していない認証器（同期されるパスキーなど）は常に 0 を返す
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return 0, ErrSignCountRegression
+	}
+	return authData.signCount, nil
+}
+
+// verifyClientData は clientDataJSON の操作の種類・チャレンジ・オリジンを検証する
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var clientData collectedClientData
+	if err := json.Unmarshal(raw, &clientData); err != nil {
+		return ErrInvalidResponse
+	}
+	if clientData.Type != ceremony {
+		return ErrChallengeMismatch
+	}
+	received, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(clientData.Challenge, "="))
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(received, challenge) != 1 {
+		return ErrChallengeMismatch
+	}
+	for _, origin := range rp.Origins {
+		if clientData.Origin == origin {
+			return nil
+		}
+	}
+	return ErrChallengeMismatch
+}
+
+// parseAuthenticatorData は authenticatorData を読み取り、RP ID とユーザーの本人確認を検証する
+func (rp *RelyingParty) parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, ErrInvalidResponse
+	}
+	data := &authenticatorData{
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(data.rpIDHash, rpIDHash[:]) {
+		return nil, ErrChallengeMismatch
+	}
+	// パスワードの代わりに使うため、ユーザーの存在だけでなく本人確認も必須にする
+	if data.flags&flagUserPresent == 0 || data.flags&flagUserVerified == 0 {
+		return nil, ErrUserNotVerified
+	}
+
+	if data.flags&flagAttestedCredData == 0 {
+		return data, nil
+	}
+	// attestedCredentialData: AAGUID（16）・クレデンシャルIDの長さ（2）・クレデンシャルID・公開鍵（COSE_Key）
+	rest := raw[37:]
+	if len(rest) < 18 {
+		return nil, ErrInvalidResponse
+	}
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLength == 0 || idLength > maxCredentialIDLength || len(rest) < idLength {
+		return nil, ErrInvalidResponse
+	}
+	data.credentialID = append([]byte(nil), rest[:idLength]...)
+	rest = rest[idLength:]
+	// 公開鍵の後ろに拡張（ED フラグ）が続く場合があるため、公開鍵の CBOR の範囲だけを取り出す
+	_, after, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+	data.publicKey = append([]byte(nil), rest[:len(rest)-len(after)]...)
+	return data, nil
+}
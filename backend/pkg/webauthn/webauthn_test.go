@@ -0,0 +1,197 @@
+// webauthntest が webauthn を使うため、外部テストパッケージにする
+package webauthn_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go-shisha-backend/pkg/webauthn"
+	"go-shisha-backend/pkg/webauthn/webauthntest"
+)
+
+func newRelyingParty() *webauthn.RelyingParty {
+	return &webauthn.RelyingParty{ID: "example.com", Name: "Go Shisha", Origins: []string{"https://example.com"}}
+}
+
+// register はソフトウェア認証器でパスキーを登録し、検証したクレデンシャルを返す
+func register(t *testing.T, rp *webauthn.RelyingParty, authenticator *webauthntest.Authenticator) *webauthn.Credential {
+	t.Helper()
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		t.Fatalf("NewChallenge failed: %v", err)
+	}
+	resp, err := authenticator.Register(rp.CreationOptions(challenge, webauthn.UserEntity{ID: []byte{1}, Name: "user@example.com"}, nil))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	credential, err := rp.VerifyRegistration(challenge, resp)
+	if err != nil {
+		t.Fatalf("VerifyRegistration failed: %v", err)
+	}
+	return credential
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	rp := newRelyingParty()
+	challenge, _ := webauthn.NewChallenge()
+	options := rp.CreationOptions(challenge, webauthn.UserEntity{ID: []byte{1}, Name: "user@example.com"}, nil)
+
+	t.Run("正常系: JSON を経由したレスポンスを検証できる", func(t *testing.T) {
+		resp, err := webauthntest.New("https://example.com").Register(options)
+		if err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		body, _ := json.Marshal(resp)
+		var decoded webauthn.RegistrationResponse
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		credential, err := rp.VerifyRegistration(challenge, &decoded)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(credential.ID) == 0 || len(credential.PublicKey) == 0 || len(credential.Transports) != 2 {
+			t.Errorf("unexpected credential: %+v", credential)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		modify  func(a *webauthntest.Authenticator, rp *webauthn.RelyingParty, challenge *[]byte)
+		wantErr error
+	}{
+		{
+			name: "異常系: 別のオリジン",
+			modify: func(a *webauthntest.Authenticator, rp *webauthn.RelyingParty, challenge *[]byte) {
+				a.Origin = "https://evil.example"
+			},
+			wantErr: webauthn.ErrChallengeMismatch,
+		},
+		{
+			name: "異常系: チャレンジが異なる",
+			modify: func(a *webauthntest.Authenticator, rp *webauthn.RelyingParty, challenge *[]byte) {
+				*challenge = []byte("other")
+			},
+			wantErr: webauthn.ErrChallengeMismatch,
+		},
+		{
+			name: "異常系: 本人確認をしていない",
+			modify: func(a *webauthntest.Authenticator, rp *webauthn.RelyingParty, challenge *[]byte) {
+				a.Flags = webauthntest.FlagUserPresent
+			},
+			wantErr: webauthn.ErrUserNotVerified,
+		},
+		{
+			name: "異常系: 別の RP ID",
+			modify: func(a *webauthntest.Authenticator, rp *webauthn.RelyingParty, challenge *[]byte) {
+				rp.ID = "other.example"
+			},
+			wantErr: webauthn.ErrChallengeMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := webauthntest.New("https://example.com")
+			registeredRP := newRelyingParty()
+			verifyChallenge := challenge
+			tt.modify(authenticator, registeredRP, &verifyChallenge)
+			resp, err := authenticator.Register(options)
+			if err != nil {
+				t.Fatalf("Register failed: %v", err)
+			}
+			if _, err := registeredRP.VerifyRegistration(verifyChallenge, resp); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("異常系: 壊れたアテステーション", func(t *testing.T) {
+		resp, _ := webauthntest.New("https://example.com").Register(options)
+		resp.Response.AttestationObject = resp.Response.AttestationObject[:len(resp.Response.AttestationObject)/2]
+		if _, err := rp.VerifyRegistration(challenge, resp); !errors.Is(err, webauthn.ErrInvalidResponse) {
+			t.Errorf("expected ErrInvalidResponse, got %v", err)
+		}
+	})
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	rp := newRelyingParty()
+
+	t.Run("正常系: 署名を検証し署名カウンターを返す", func(t *testing.T) {
+		authenticator := webauthntest.New("https://example.com")
+		authenticator.CountSignatures = true
+		credential := register(t, rp, authenticator)
+
+		for want := uint32(1); want <= 2; want++ {
+			challenge, _ := webauthn.NewChallenge()
+			resp, err := authenticator.Login(rp.RequestOptions(challenge))
+			if err != nil {
+				t.Fatalf("Login failed: %v", err)
+			}
+			signCount, err := rp.VerifyAssertion(challenge, credential, resp)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if signCount != want {
+				t.Fatalf("expected sign count %d, got %d", want, signCount)
+			}
+			credential.SignCount = signCount
+		}
+
+		// 複製された認証器は前回以下の署名カウンターを返す
+		credential.SignCount = 10
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authenticator.Login(rp.RequestOptions(challenge))
+		if _, err := rp.VerifyAssertion(challenge, credential, resp); !errors.Is(err, webauthn.ErrSignCountRegression) {
+			t.Errorf("expected ErrSignCountRegression, got %v", err)
+		}
+	})
+
+	t.Run("正常系: 署名カウンターに対応していない認証器", func(t *testing.T) {
+		authenticator := webauthntest.New("https://example.com")
+		credential := register(t, rp, authenticator)
+		for i := 0; i < 2; i++ {
+			challenge, _ := webauthn.NewChallenge()
+			resp, _ := authenticator.Login(rp.RequestOptions(challenge))
+			if signCount, err := rp.VerifyAssertion(challenge, credential, resp); err != nil || signCount != 0 {
+				t.Fatalf("expected (0, nil), got (%d, %v)", signCount, err)
+			}
+		}
+	})
+
+	t.Run("異常系: 署名が改ざんされている", func(t *testing.T) {
+		authenticator := webauthntest.New("https://example.com")
+		credential := register(t, rp, authenticator)
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authenticator.Login(rp.RequestOptions(challenge))
+		resp.Response.AuthenticatorData[len(resp.Response.AuthenticatorData)-1] ^= 0xff
+		if _, err := rp.VerifyAssertion(challenge, credential, resp); !errors.Is(err, webauthn.ErrInvalidSignature) {
+			t.Errorf("expected ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 別のパスキーの署名", func(t *testing.T) {
+		credential := register(t, rp, webauthntest.New("https://example.com"))
+		other := webauthntest.New("https://example.com")
+		register(t, rp, other)
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := other.Login(rp.RequestOptions(challenge))
+		resp.RawID = credential.ID
+		if _, err := rp.VerifyAssertion(challenge, credential, resp); !errors.Is(err, webauthn.ErrInvalidSignature) {
+			t.Errorf("expected ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 登録のレスポンスを認証に使えない", func(t *testing.T) {
+		authenticator := webauthntest.New("https://example.com")
+		credential := register(t, rp, authenticator)
+		challenge, _ := webauthn.NewChallenge()
+		reg, _ := webauthntest.New("https://example.com").Register(rp.CreationOptions(challenge, webauthn.UserEntity{ID: []byte{1}}, nil))
+		resp, _ := authenticator.Login(rp.RequestOptions(challenge))
+		resp.Response.ClientDataJSON = reg.Response.ClientDataJSON
+		if _, err := rp.VerifyAssertion(challenge, credential, resp); !errors.Is(err, webauthn.ErrChallengeMismatch) {
+			t.Errorf("expected ErrChallengeMismatch, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+// Package webauthntest はテスト用のソフトウェア認証器を提供する
+// ブラウザーと認証器の代わりに、webauthn パッケージのオプションから登録・認証のレスポンスを作る
+package webauthntest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"go-shisha-backend/pkg/webauthn"
+)
+
+// フラグ（authenticatorData）
+const (
+	FlagUserPresent  byte = 0x01
+	FlagUserVerified byte = 0x04
+	flagAttested     byte = 0x40
+)
+
+// ErrNoCredential は RP ID に登録されたパスキーがない場合のエラー
+var ErrNoCredential = errors.New("webauthntest: no credential for rp id")
+
+// Authenticator は ES256 の鍵を生成・保存するソフトウェア認証器
+type Authenticator struct {
+	// Origin は clientDataJSON に記録するオリジン
+	Origin string
+	// Flags は authenticatorData のフラグ（既定はユーザーの存在・本人確認済み）
+	Flags byte
+	// CountSignatures が true の場合、認証のたびに署名カウンターを増やす（false の場合は同期されるパスキーと同じく常に 0）
+	CountSignatures bool
+
+	credentials []*credential
+}
+
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// New は origin のページから操作するソフトウェア認証器を作成する
+func New(origin string) *Authenticator {
+	return &Authenticator{Origin: origin, Flags: FlagUserPresent | FlagUserVerified}
+}
+
+// Register は options でパスキーを作成し、navigator.credentials.create() の結果を返す
+func (a *Authenticator) Register(options webauthn.CreationOptions) (*webauthn.RegistrationResponse, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	cred := &credential{id: id, rpID: options.RP.ID, userHandle: options.User.ID, key: key}
+
+	coseKey := encode(map[interface{}]interface{}{
+		int64(1):  int64(2),  // kty: EC2
+		int64(3):  int64(-7), // alg: ES256
+		int64(-1): int64(1),  // crv: P-256
+		int64(-2): padded(key.X.Bytes()),
+		int64(-3): padded(key.Y.Bytes()),
+	})
+	var attested bytes.Buffer
+	attested.Write(make([]byte, 16)) // AAGUID
+	_ = binary.Write(&attested, binary.BigEndian, uint16(len(id)))
+	attested.Write(id)
+	attested.Write(coseKey)
+
+	authData := a.authenticatorData(cred.rpID, a.Flags|flagAttested, 0, attested.Bytes())
+	clientData, err := a.clientData("webauthn.create", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	a.credentials = append(a.credentials, cred)
+
+	return &webauthn.RegistrationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(id),
+		RawID: id,
+		Type:  "public-key",
+		Response: webauthn.AuthenticatorAttestationData{
+			ClientDataJSON: clientData,
+			AttestationObject: encode(map[interface{}]interface{}{
+				"fmt":      "none",
+				"attStmt":  map[interface{}]interface{}{},
+				"authData": authData,
+			}),
+			Transports: []string{"internal", "hybrid"},
+		},
+	}, nil
+}
+
+// Login は options で認証し、navigator.credentials.get() の結果を返す
+// allowCredentials が空の場合は RP ID に最後に登録したパスキーを使う
+func (a *Authenticator) Login(options webauthn.RequestOptions) (*webauthn.AssertionResponse, error) {
+	var cred *credential
+	for i := len(a.credentials) - 1; i >= 0 && cred == nil; i-- {
+		c := a.credentials[i]
+		if c.rpID != options.RPID {
+			continue
+		}
+		if len(options.AllowCredentials) == 0 {
+			cred = c
+		}
+		for _, allowed := range options.AllowCredentials {
+			if bytes.Equal(allowed.ID, c.id) {
+				cred = c
+			}
+		}
+	}
+	if cred == nil {
+		return nil, ErrNoCredential
+	}
+	if a.CountSignatures {
+		cred.signCount++
+	}
+
+	authData := a.authenticatorData(cred.rpID, a.Flags, cred.signCount, nil)
+	clientData, err := a.clientData("webauthn.get", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &webauthn.AssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(cred.id),
+		RawID: cred.id,
+		Type:  "public-key",
+		Response: webauthn.AuthenticatorAssertionData{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        cred.userHandle,
+		},
+	}, nil
+}
+
+func (a *Authenticator) authenticatorData(rpID string, flags byte, signCount uint32, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	var buf bytes.Buffer
+	buf.Write(rpIDHash[:])
+	buf.WriteByte(flags)
+	_ = binary.Write(&buf, binary.BigEndian, signCount)
+	buf.Write(attested)
+	return buf.Bytes()
+}
+
+func (a *Authenticator) clientData(ceremony string, challenge []byte) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+}
+
+// padded は座標を32バイトに揃える
+func padded(b []byte) []byte {
+	out := make([]byte, 32)
+	copy(out[32-len(b):], b)
+	return out
+}
+
+// encode は CTAP2 の正規形（マップのキーはエンコード後の長さ・バイト順）で CBOR にエンコードする
+func encode(value interface{}) []byte {
+	var buf bytes.Buffer
+	switch v := value.(type) {
+	case int64:
+		if v >= 0 {
+			writeHeader(&buf, 0, uint64(v))
+		} else {
+			writeHeader(&buf, 1, uint64(-1-v))
+		}
+	case []byte:
+		writeHeader(&buf, 2, uint64(len(v)))
+		buf.Write(v)
+	case string:
+		writeHeader(&buf, 3, uint64(len(v)))
+		buf.WriteString(v)
+	case map[interface{}]interface{}:
+		type entry struct{ key, value []byte }
+		entries := make([]entry, 0, len(v))
+		for key, item := range v {
+			entries = append(entries, entry{encode(key), encode(item)})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if len(entries[i].key) != len(entries[j].key) {
+				return len(entries[i].key) < len(entries[j].key)
+			}
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+		writeHeader(&buf, 5, uint64(len(entries)))
+		for _, e := range entries {
+			buf.Write(e.key)
+			buf.Write(e.value)
+		}
+	default:
+		panic("webauthntest: unsupported cbor value")
+	}
+	return buf.Bytes()
+}
+
+func writeHeader(buf *bytes.Buffer, major byte, arg uint64) {
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= 0xff:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= 0xffff:
+		buf.WriteByte(major<<5 | 25)
+		_ = binary.Write(buf, binary.BigEndian, uint16(arg))
+	case arg <= 0xffffffff:
+		buf.WriteByte(major<<5 | 26)
+		_ = binary.Write(buf, binary.BigEndian, uint32(arg))
+	default:
+		buf.WriteByte(major<<5 | 27)
+		_ = binary.Write(buf, binary.BigEndian, arg)
+	}
+}